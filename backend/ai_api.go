@@ -3,6 +3,8 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	appai "shotgun_code/application/ai"
+	"shotgun_code/application/semantic"
 	"shotgun_code/domain"
 	"shotgun_code/handlers"
 )
@@ -12,13 +14,12 @@ func (a *App) GenerateCode(systemPrompt, userPrompt string) (string, error) {
 	return a.aiHandler.GenerateCode(a.ctx, systemPrompt, userPrompt)
 }
 
-// GenerateCodeStream generates code with streaming response via Wails events
-func (a *App) GenerateCodeStream(systemPrompt, userPrompt string) {
-	go func() {
-		a.aiHandler.GenerateCodeStream(a.ctx, systemPrompt, userPrompt, func(chunk domain.StreamChunk) {
-			a.bridge.Emit("ai:stream:chunk", chunk)
-		})
-	}()
+// GenerateCodeStream starts generating code with the response streamed via
+// "ai:stream:chunk" events and returns the request ID to cancel it with
+func (a *App) GenerateCodeStream(systemPrompt, userPrompt string) (string, error) {
+	return a.aiHandler.GenerateCodeStream(a.ctx, systemPrompt, userPrompt, func(chunk domain.StreamChunk) {
+		a.bridge.Emit("ai:stream:chunk", chunk)
+	})
 }
 
 // GenerateIntelligentCode performs intelligent code generation
@@ -41,6 +42,21 @@ func (a *App) ListAvailableModels() ([]string, error) {
 	return a.aiHandler.ListAvailableModels(a.ctx)
 }
 
+// QueryAIAuditLog returns the AI request audit trail filtered by a JSON query
+func (a *App) QueryAIAuditLog(queryJson string) (string, error) {
+	return a.aiHandler.QueryAIAuditLog(queryJson)
+}
+
+// GetAICacheStats returns hit/miss statistics of the AI response cache
+func (a *App) GetAICacheStats() appai.CacheStats {
+	return a.aiHandler.GetCacheStats()
+}
+
+// GetAIRateLimits returns the adaptive rate limit state of the AI providers
+func (a *App) GetAIRateLimits() map[string]appai.RateLimitState {
+	return a.aiHandler.GetRateLimits()
+}
+
 // SuggestContextFiles suggests relevant files for a task
 func (a *App) SuggestContextFiles(task string, allFiles []*domain.FileNode) ([]string, error) {
 	return a.aiHandler.SuggestContextFiles(a.ctx, task, allFiles)
@@ -56,6 +72,16 @@ func (a *App) AgenticChat(requestJson string) (string, error) {
 	return a.aiHandler.AgenticChat(a.ctx, requestJson)
 }
 
+// CancelAIRequest cancels an in-flight AI request by the ID reported in "ai:request" events
+func (a *App) CancelAIRequest(requestId string) error {
+	return a.aiHandler.CancelRequest(requestId)
+}
+
+// ListActiveAIRequests returns the AI requests that are still running
+func (a *App) ListActiveAIRequests() []handlers.AIRequestInfo {
+	return a.aiHandler.ActiveRequests()
+}
+
 // ==================== Qwen Task Methods ====================
 
 // QwenExecuteTask executes a task using Qwen with smart context collection
@@ -65,7 +91,7 @@ func (a *App) QwenExecuteTask(requestJson string) (string, error) {
 		return "", fmt.Errorf("failed to parse request: %w", err)
 	}
 
-	result := a.qwenHandler.ExecuteTask(req)
+	result := a.qwenHandler.ExecuteTask(a.ctx, req)
 
 	resultJson, err := json.Marshal(result)
 	if err != nil {
@@ -82,7 +108,7 @@ func (a *App) QwenPreviewContext(requestJson string) (string, error) {
 		return "", fmt.Errorf("failed to parse request: %w", err)
 	}
 
-	result := a.qwenHandler.PreviewContext(req)
+	result := a.qwenHandler.PreviewContext(a.ctx, req)
 
 	resultJson, err := json.Marshal(result)
 	if err != nil {
@@ -122,6 +148,14 @@ func (a *App) SemanticFindSimilar(requestJson string) (string, error) {
 	return a.container.SemanticHandler.FindSimilar(a.ctx, requestJson)
 }
 
+// SemanticSearchRelated finds code related to the indexed chunk at a file location
+func (a *App) SemanticSearchRelated(requestJson string) (string, error) {
+	if a.container.SemanticHandler == nil {
+		return "", fmt.Errorf("semantic search not available: embedding provider not configured")
+	}
+	return a.container.SemanticHandler.SearchRelated(a.ctx, requestJson)
+}
+
 // SemanticIndexProject indexes a project for semantic search
 func (a *App) SemanticIndexProject(projectRoot string) error {
 	if a.container.SemanticHandler == nil {
@@ -170,6 +204,14 @@ func (a *App) SemanticHybridSearch(requestJson string) (string, error) {
 	return a.container.SemanticHandler.HybridSearch(a.ctx, requestJson)
 }
 
+// GetBackgroundIndexStatus returns the status of background re-indexing of changed files
+func (a *App) GetBackgroundIndexStatus() semantic.ReindexerStatus {
+	if a.container.Reindexer == nil {
+		return semantic.ReindexerStatus{}
+	}
+	return a.container.Reindexer.Status()
+}
+
 // IsSemanticSearchAvailable checks if semantic search is configured
 func (a *App) IsSemanticSearchAvailable() bool {
 	return a.container.SemanticHandler != nil
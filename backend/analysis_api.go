@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"shotgun_code/application/project"
 	"shotgun_code/domain"
+	"shotgun_code/handlers"
 	"shotgun_code/infrastructure/git"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
@@ -30,24 +35,47 @@ func (a *App) GetSymbolDependents(symbolID, language string, graph *domain.Symbo
 	return a.analysisHandler.GetSymbolDependents(a.ctx, symbolID, language, graph)
 }
 
-// Build executes project build
-func (a *App) Build(projectPath, language string) (*domain.BuildResult, error) {
-	return a.analysisHandler.Build(a.ctx, projectPath, language)
+// FindSymbolDefinition resolves a reference to symbolName in fromFile to the
+// node that defines it, preferring definitions in the same package
+func (a *App) FindSymbolDefinition(projectRoot, language, symbolName, fromFile string) (*domain.SymbolNode, error) {
+	return a.analysisHandler.FindSymbolDefinition(a.ctx, projectRoot, language, symbolName, fromFile)
 }
 
-// TypeCheck performs type checking
-func (a *App) TypeCheck(projectPath, language string) (*domain.TypeCheckResult, error) {
-	return a.analysisHandler.TypeCheck(a.ctx, projectPath, language)
+// Build executes project build; opts selects Go build tags and GOOS/GOARCH
+// and whether to ignore build caches
+func (a *App) Build(projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, error) {
+	return a.analysisHandler.Build(a.commandCtx("build"), projectPath, language, target)
+}
+
+// TypeCheck performs type checking; opts selects Go build tags and GOOS/GOARCH
+func (a *App) TypeCheck(projectPath, language string, target domain.BuildTarget) (*domain.TypeCheckResult, error) {
+	return a.analysisHandler.TypeCheck(a.commandCtx("typecheck"), projectPath, language, target)
 }
 
 // BuildAndTypeCheck performs build and type checking
-func (a *App) BuildAndTypeCheck(projectPath, language string) (*domain.BuildResult, *domain.TypeCheckResult, error) {
-	return a.analysisHandler.BuildAndTypeCheck(a.ctx, projectPath, language)
+func (a *App) BuildAndTypeCheck(projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, *domain.TypeCheckResult, error) {
+	return a.analysisHandler.BuildAndTypeCheck(a.commandCtx("build"), projectPath, language, target)
 }
 
 // ValidateProject performs full project validation
 func (a *App) ValidateProject(projectPath string, languages []string) (*domain.ProjectValidationResult, error) {
-	return a.analysisHandler.ValidateProject(a.ctx, projectPath, languages)
+	return a.analysisHandler.ValidateProject(a.commandCtx("validate"), projectPath, languages)
+}
+
+// ValidateProjectSummary checks project health across build, type check, tests
+// and static analysis and returns the overall status with per-category results
+func (a *App) ValidateProjectSummary(config domain.ValidationSummaryConfig) (*domain.ValidationSummary, error) {
+	return a.analysisHandler.ValidateProjectSummary(a.commandCtx("validate"), &config)
+}
+
+// ComputeProjectHealth scores project health from 0 to 100 with the default
+// weights, aggregating static analysis, coverage, vulnerabilities, dependency
+// cycles and the build into a letter grade with a per-category breakdown
+func (a *App) ComputeProjectHealth(projectPath string) (*domain.ProjectHealth, error) {
+	if a.container == nil || a.container.VerificationPipelineService == nil {
+		return nil, fmt.Errorf("verification service not initialized")
+	}
+	return a.container.VerificationPipelineService.ComputeProjectHealth(a.commandCtx("health"), projectPath, nil)
 }
 
 // DetectLanguages detects languages in a project
@@ -57,12 +85,12 @@ func (a *App) DetectLanguages(projectPath string) ([]string, error) {
 
 // RunTests executes tests according to configuration
 func (a *App) RunTests(config *domain.TestConfig) ([]*domain.TestResult, error) {
-	return a.analysisHandler.RunTests(a.ctx, config)
+	return a.analysisHandler.RunTests(a.commandCtx("test"), config)
 }
 
 // RunTargetedTests executes targeted tests for affected files
 func (a *App) RunTargetedTests(config *domain.TestConfig, changedFiles []string) ([]*domain.TestResult, error) {
-	return a.analysisHandler.RunTargetedTests(a.ctx, config, changedFiles)
+	return a.analysisHandler.RunTargetedTests(a.commandCtx("test"), config, changedFiles)
 }
 
 // DiscoverTests discovers tests in a project
@@ -77,17 +105,17 @@ func (a *App) BuildAffectedGraph(changedFiles []string, projectPath string) (*do
 
 // RunSmokeTests executes only smoke tests
 func (a *App) RunSmokeTests(projectPath, language string) ([]*domain.TestResult, error) {
-	return a.analysisHandler.RunSmokeTests(a.ctx, projectPath, language)
+	return a.analysisHandler.RunSmokeTests(a.commandCtx("test"), projectPath, language)
 }
 
 // RunUnitTests executes only unit tests
 func (a *App) RunUnitTests(projectPath, language string) ([]*domain.TestResult, error) {
-	return a.analysisHandler.RunUnitTests(a.ctx, projectPath, language)
+	return a.analysisHandler.RunUnitTests(a.commandCtx("test"), projectPath, language)
 }
 
 // RunIntegrationTests executes only integration tests
 func (a *App) RunIntegrationTests(projectPath, language string) ([]*domain.TestResult, error) {
-	return a.analysisHandler.RunIntegrationTests(a.ctx, projectPath, language)
+	return a.analysisHandler.RunIntegrationTests(a.commandCtx("test"), projectPath, language)
 }
 
 // ValidateTestResults validates test results
@@ -97,27 +125,37 @@ func (a *App) ValidateTestResults(results []*domain.TestResult) *domain.TestVali
 
 // AnalyzeProject performs static analysis on a project
 func (a *App) AnalyzeProject(projectPath string, languages []string) (*domain.StaticAnalysisReport, error) {
-	return a.analysisHandler.AnalyzeProject(a.ctx, projectPath, languages)
+	return a.analysisHandler.AnalyzeProject(a.commandCtx("static"), projectPath, languages)
+}
+
+// ExportAnalysisReport analyzes the project and renders the result as a "pdf"
+// or "html" report, returning the report path
+func (a *App) ExportAnalysisReport(projectPath string, languages []string, format string) (string, error) {
+	report, err := a.analysisHandler.AnalyzeProject(a.commandCtx("static"), projectPath, languages)
+	if err != nil {
+		return "", fmt.Errorf("failed to analyze project: %w", err)
+	}
+	return a.exportService.ExportAnalysisReport(report, format)
 }
 
 // AnalyzeFile performs static analysis on a single file
 func (a *App) AnalyzeFile(filePath, language string) (*domain.StaticAnalysisResult, error) {
-	return a.analysisHandler.AnalyzeFile(a.ctx, filePath, language)
+	return a.analysisHandler.AnalyzeFile(a.commandCtx("static"), filePath, language)
 }
 
 // AnalyzeGoProject performs static analysis on a Go project
 func (a *App) AnalyzeGoProject(projectPath string) (*domain.StaticAnalysisResult, error) {
-	return a.analysisHandler.AnalyzeGoProject(a.ctx, projectPath)
+	return a.analysisHandler.AnalyzeGoProject(a.commandCtx("static"), projectPath)
 }
 
 // AnalyzeTypeScriptProject performs static analysis on a TypeScript project
 func (a *App) AnalyzeTypeScriptProject(projectPath string) (*domain.StaticAnalysisResult, error) {
-	return a.analysisHandler.AnalyzeTypeScriptProject(a.ctx, projectPath)
+	return a.analysisHandler.AnalyzeTypeScriptProject(a.commandCtx("static"), projectPath)
 }
 
 // AnalyzeJavaScriptProject performs static analysis on a JavaScript project
 func (a *App) AnalyzeJavaScriptProject(projectPath string) (*domain.StaticAnalysisResult, error) {
-	return a.analysisHandler.AnalyzeJavaScriptProject(a.ctx, projectPath)
+	return a.analysisHandler.AnalyzeJavaScriptProject(a.commandCtx("static"), projectPath)
 }
 
 // GetSupportedAnalyzers returns supported analyzers
@@ -238,6 +276,10 @@ func (a *App) GetSmartSuggestions(projectPath string, currentFiles []string, tas
 	archSuggestions := a.getArchSuggestions(projectPath, currentFiles, seen)
 	suggestions = append(suggestions, archSuggestions...)
 
+	// 3. Semantic suggestions (files similar to the task or current files)
+	semanticSuggestions := a.getSemanticSuggestions(projectPath, currentFiles, task, seen)
+	suggestions = append(suggestions, semanticSuggestions...)
+
 	// Sort by confidence (highest first)
 	sortSuggestionsByConfidence(suggestions)
 
@@ -252,10 +294,22 @@ func (a *App) GetSmartSuggestions(projectPath string, currentFiles []string, tas
 	}, nil
 }
 
+// gitContextFor returns a git context builder for the project, reusing the
+// previous one so its co-change cache survives between calls
+func (a *App) gitContextFor(projectPath string) *git.ContextBuilder {
+	a.gitContextMu.Lock()
+	defer a.gitContextMu.Unlock()
+	if a.gitContext == nil || a.gitContextRoot != projectPath {
+		a.gitContext = git.NewContextBuilder(projectPath)
+		a.gitContextRoot = projectPath
+	}
+	return a.gitContext
+}
+
 // getGitSuggestions returns suggestions based on git co-change history
 func (a *App) getGitSuggestions(projectPath string, currentFiles []string, seen map[string]bool) []SmartSuggestion {
 	var suggestions []SmartSuggestion
-	gitContext := git.NewContextBuilder(projectPath)
+	gitContext := a.gitContextFor(projectPath)
 
 	for _, file := range currentFiles {
 		coChanged, err := gitContext.GetCoChangedFiles(file, 5)
@@ -304,6 +358,81 @@ func (a *App) getArchSuggestions(projectPath string, currentFiles []string, seen
 	return suggestions
 }
 
+// maxSemanticQueryChars limits the query built from current files' content
+const maxSemanticQueryChars = 2000
+
+// getSemanticSuggestions returns files semantically related to the task description,
+// or to the content of the current files when no task is given. Returns nil when
+// semantic search is not configured or the project is not indexed.
+func (a *App) getSemanticSuggestions(projectPath string, currentFiles []string, task string, seen map[string]bool) []SmartSuggestion {
+	if a.container == nil || a.container.Semantic == nil {
+		return nil
+	}
+	searchService := a.container.GetSemanticSearch(a.ctx)
+	if searchService == nil || !searchService.IsIndexed(a.ctx, projectPath) {
+		return nil
+	}
+
+	query := strings.TrimSpace(task)
+	reason := "Related to task"
+	if query == "" {
+		query = semanticQueryFromFiles(projectPath, currentFiles)
+		reason = "Similar to selected files"
+	}
+	if query == "" {
+		return nil
+	}
+
+	resp, err := searchService.Search(a.ctx, domain.SemanticSearchRequest{
+		Query:       query,
+		ProjectRoot: projectPath,
+		TopK:        15,
+		MinScore:    0.5,
+		SearchType:  domain.SearchTypeSemantic,
+	})
+	if err != nil {
+		a.log.Warning(fmt.Sprintf("Semantic suggestions failed: %v", err))
+		return nil
+	}
+
+	var suggestions []SmartSuggestion
+	for _, result := range resp.Results {
+		path := filepath.ToSlash(result.Chunk.FilePath)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		suggestions = append(suggestions, SmartSuggestion{
+			Path:       path,
+			Source:     "semantic",
+			Reason:     reason,
+			Confidence: float64(result.Score),
+		})
+	}
+	return suggestions
+}
+
+// semanticQueryFromFiles builds a search query from the beginning of the given files
+func semanticQueryFromFiles(projectPath string, files []string) string {
+	var sb strings.Builder
+	for _, file := range files {
+		remaining := maxSemanticQueryChars - sb.Len()
+		if remaining <= 0 {
+			break
+		}
+		content, err := os.ReadFile(filepath.Join(projectPath, file))
+		if err != nil {
+			continue
+		}
+		if len(content) > remaining {
+			content = content[:remaining]
+		}
+		sb.Write(content)
+		sb.WriteString("\n")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
 // sortSuggestionsByConfidence sorts suggestions by confidence descending
 func sortSuggestionsByConfidence(suggestions []SmartSuggestion) {
 	for i := 0; i < len(suggestions)-1; i++ {
@@ -317,154 +446,209 @@ func sortSuggestionsByConfidence(suggestions []SmartSuggestion) {
 
 // === File Quick Info (Phase 4) ===
 
-// FileQuickInfo contains quick statistics about a file
-type FileQuickInfo struct {
-	SymbolCount    int     `json:"symbolCount"`
-	ImportCount    int     `json:"importCount"`
-	DependentCount int     `json:"dependentCount"`
-	ChangeRisk     float64 `json:"changeRisk"`
-	RiskLevel      string  `json:"riskLevel"` // "low", "medium", "high"
-}
-
 // GetFileQuickInfo returns quick statistics for a file
-func (a *App) GetFileQuickInfo(projectPath, filePath string) (*FileQuickInfo, error) {
-	info := &FileQuickInfo{}
-
-	// Get symbol count using symbol index
-	service := project.NewStructureServiceLazy(a.log)
-	symbols, _ := service.GetFileSymbols(projectPath, filePath)
-	info.SymbolCount = len(symbols)
-
-	// Get import count
-	imports, _ := service.GetFileImports(projectPath, filePath)
-	info.ImportCount = len(imports)
-
-	// Get dependent files count
-	dependents, _ := service.GetDependentFiles(projectPath, filePath)
-	info.DependentCount = len(dependents)
-
-	// Calculate change risk based on dependents
-	info.ChangeRisk = calculateRisk(info.DependentCount, info.SymbolCount)
-	info.RiskLevel = getRiskLevel(info.ChangeRisk)
-
-	return info, nil
+func (a *App) GetFileQuickInfo(projectPath, filePath string) (*handlers.FileQuickInfo, error) {
+	return a.analysisHandler.GetFileQuickInfo(projectPath, filePath)
 }
 
-func calculateRisk(dependents, symbols int) float64 {
-	if dependents == 0 {
-		return 0.1
+// GetMostDepended ranks the files (Go packages) most depended on, up to
+// limit (<= 0 uses the default), to find the hubs that need careful review
+func (a *App) GetMostDepended(projectRoot string, limit int) ([]domain.FileDependencyRank, error) {
+	if a.analysisContainer == nil {
+		return nil, fmt.Errorf("analysis container not initialized")
 	}
-	risk := float64(dependents) / 20.0 // normalize to 0-1 range
-	if risk > 1.0 {
-		risk = 1.0
+	ranker, ok := a.analysisContainer.GetCallGraph().(interface {
+		GetMostDepended(projectRoot string, limit int) ([]domain.FileDependencyRank, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("dependency ranking not available")
 	}
-	// Adjust by symbol count (more symbols = more risk)
-	if symbols > 20 {
-		risk += 0.1
+	return ranker.GetMostDepended(projectRoot, limit)
+}
+
+// SuggestCycleBreak picks the edge of a dependency cycle (file paths or Go
+// package directories) whose symbols are cheapest to move behind an interface
+func (a *App) SuggestCycleBreak(projectRoot string, cycle []string) (*domain.CycleBreakSuggestion, error) {
+	if a.analysisContainer == nil {
+		return nil, fmt.Errorf("analysis container not initialized")
 	}
-	if risk > 1.0 {
-		risk = 1.0
+	breaker, ok := a.analysisContainer.GetCallGraph().(interface {
+		SuggestCycleBreak(projectRoot string, cycle []string) (*domain.CycleBreakSuggestion, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("cycle break suggestions not available")
 	}
-	return risk
+	return breaker.SuggestCycleBreak(projectRoot, cycle)
 }
 
-func getRiskLevel(risk float64) string {
-	if risk < 0.3 {
-		return "low"
+// GetDependencyMermaid renders the file dependency graph as a Mermaid diagram
+// of up to maxNodes nodes (<= 0 uses the configured default). includeExternal
+// adds the third-party modules files import as separately styled nodes.
+func (a *App) GetDependencyMermaid(projectRoot string, includeExternal bool, maxNodes int) (string, error) {
+	if a.analysisContainer == nil {
+		return "", fmt.Errorf("analysis container not initialized")
 	}
-	if risk < 0.7 {
-		return "medium"
+	exporter, ok := a.analysisContainer.GetCallGraph().(interface {
+		DependencyMermaid(projectRoot string, includeExternal bool, maxNodes int) (string, error)
+	})
+	if !ok {
+		return "", fmt.Errorf("dependency graph export not available")
 	}
-	return "high"
+	if maxNodes <= 0 {
+		maxNodes = domain.DefaultConfig().CallGraph.MaxMermaidNodes
+	}
+	return exporter.DependencyMermaid(projectRoot, includeExternal, maxNodes)
 }
 
 // === Impact Preview (Phase 5) ===
 
-// ImpactPreviewResult contains impact analysis for selected files
-type ImpactPreviewResult struct {
-	TotalDependents int            `json:"totalDependents"`
-	AggregateRisk   float64        `json:"aggregateRisk"`
-	RiskLevel       string         `json:"riskLevel"`
-	AffectedFiles   []AffectedFile `json:"affectedFiles"`
-	RelatedTests    []string       `json:"relatedTests"`
+// GetImpactPreview returns impact analysis for selected files.
+// Dependents are followed transitively up to maxDepth levels (<= 0 uses the default).
+func (a *App) GetImpactPreview(projectPath string, filePaths []string, maxDepth int) (*handlers.ImpactPreviewResult, error) {
+	return a.analysisHandler.GetImpactPreview(projectPath, filePaths, maxDepth)
 }
 
-// AffectedFile represents a file affected by changes
-type AffectedFile struct {
-	Path       string `json:"path"`
-	Type       string `json:"type"` // "direct", "transitive"
-	Dependents int    `json:"dependents"`
+func isTestFile(path string) bool {
+	return strings.Contains(path, "_test.") ||
+		strings.Contains(path, ".test.") ||
+		strings.Contains(path, ".spec.") ||
+		strings.Contains(path, "/tests/") ||
+		strings.Contains(path, "/__tests__/")
 }
 
-// GetImpactPreview returns impact analysis for selected files
-func (a *App) GetImpactPreview(projectPath string, filePaths []string) (*ImpactPreviewResult, error) {
-	result := &ImpactPreviewResult{
-		AffectedFiles: []AffectedFile{},
-		RelatedTests:  []string{},
-	}
+// === Change Blast Radius ===
 
-	service := project.NewStructureServiceLazy(a.log)
-	seen := make(map[string]bool)
-	var totalRisk float64
+// Caller depth limits of a change's blast radius
+const (
+	defaultBlastDepth = 3
+	maxBlastDepth     = 6
+)
 
-	for _, filePath := range filePaths {
-		seen[filePath] = true
-	}
+// Reasons a file is part of a change's blast radius
+const (
+	blastReasonChanged   = "changed"   // defines the changed function
+	blastReasonCaller    = "caller"    // calls the changed function, directly or transitively
+	blastReasonDependent = "dependent" // imports the changed file or its package
+	blastReasonTest      = "test"      // tests an affected file
+)
 
-	// Collect all dependents
-	for _, filePath := range filePaths {
-		dependents, err := service.GetDependentFiles(projectPath, filePath)
-		if err != nil {
-			continue
-		}
+// BlastRadius is what changing a function affects: its callers, the files
+// involved and the tests that should run
+type BlastRadius struct {
+	Function domain.CallGraphNode   `json:"function"`
+	Callers  []domain.CallGraphNode `json:"callers"`
+	Files    []BlastRadiusFile      `json:"files"`
+	Tests    []string               `json:"tests"`
+}
 
-		for _, dep := range dependents {
-			if seen[dep] {
-				continue
-			}
-			seen[dep] = true
+// BlastRadiusFile is an affected file with every reason it is included
+type BlastRadiusFile struct {
+	Path    string              `json:"path"`
+	Reasons []BlastRadiusReason `json:"reasons"`
+}
 
-			depType := "direct"
-			result.AffectedFiles = append(result.AffectedFiles, AffectedFile{
-				Path: dep,
-				Type: depType,
-			})
+// BlastRadiusReason explains why a file is affected
+type BlastRadiusReason struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"` // function or file responsible
+}
 
-			// Check if it's a test file
-			if isTestFile(dep) {
-				result.RelatedTests = append(result.RelatedTests, dep)
-			}
-		}
+// GetChangeBlastRadius returns the callers, files and tests affected by changing
+// a function, merging call graph impact, importers of its file and test selection.
+// Callers are followed up to maxDepth levels (<= 0 uses the default); a language
+// limits importers and tests to that language.
+func (a *App) GetChangeBlastRadius(projectRoot, language, functionID string, maxDepth int) (*BlastRadius, error) {
+	if a.analysisContainer == nil {
+		return nil, fmt.Errorf("analysis container not initialized")
+	}
+	callGraph := a.analysisContainer.GetCallGraph()
+	if callGraph == nil {
+		return nil, fmt.Errorf("call graph not available")
+	}
+	return collectBlastRadius(a.ctx, callGraph, a.testService, projectRoot, language, functionID, maxDepth)
+}
+
+// collectBlastRadius builds the blast radius of functionID; tests may be nil
+func collectBlastRadius(ctx context.Context, callGraph domain.CallGraphBuilder, tests domain.ITestService, projectRoot, language, functionID string, maxDepth int) (*BlastRadius, error) {
+	graph, err := callGraph.Build(projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build call graph: %w", err)
+	}
+	fn, ok := graph.Nodes[functionID]
+	if !ok {
+		return nil, domain.NewNotFoundError("function", functionID)
+	}
+	if maxDepth <= 0 {
+		maxDepth = defaultBlastDepth
+	}
+	maxDepth = min(maxDepth, maxBlastDepth)
+	language = domain.NormalizeLanguage(language)
+	inLanguage := func(path string) bool {
+		return language == "" || domain.LanguageForFile(path) == language
+	}
 
-		// Calculate risk for this file
-		info, _ := a.GetFileQuickInfo(projectPath, filePath)
-		if info != nil {
-			totalRisk += info.ChangeRisk
+	result := &BlastRadius{Function: *fn, Tests: []string{}}
+	files := make(map[string]*BlastRadiusFile)
+	var order []string
+	addFile := func(path, kind, detail string) {
+		file, ok := files[path]
+		if !ok {
+			file = &BlastRadiusFile{Path: path}
+			files[path] = file
+			order = append(order, path)
+		}
+		reason := BlastRadiusReason{Kind: kind, Detail: detail}
+		if !slices.Contains(file.Reasons, reason) {
+			file.Reasons = append(file.Reasons, reason)
 		}
 	}
 
-	result.TotalDependents = len(result.AffectedFiles)
-	if len(filePaths) > 0 {
-		result.AggregateRisk = totalRisk / float64(len(filePaths))
+	addFile(fn.FilePath, blastReasonChanged, fn.Name)
+	result.Callers = callGraph.GetImpact(functionID, maxDepth)
+	sort.Slice(result.Callers, func(i, j int) bool {
+		if result.Callers[i].FilePath != result.Callers[j].FilePath {
+			return result.Callers[i].FilePath < result.Callers[j].FilePath
+		}
+		return result.Callers[i].Line < result.Callers[j].Line
+	})
+	for _, caller := range result.Callers {
+		addFile(caller.FilePath, blastReasonCaller, caller.Name)
+	}
+	for _, target := range []string{fn.FilePath, filepath.Dir(fn.FilePath)} {
+		for _, dep := range callGraph.GetFileDependents(target) {
+			if inLanguage(dep) {
+				addFile(dep, blastReasonDependent, fn.FilePath)
+			}
+		}
 	}
-	result.RiskLevel = getRiskLevel(result.AggregateRisk)
 
-	// Limit affected files to 20
-	if len(result.AffectedFiles) > 20 {
-		result.AffectedFiles = result.AffectedFiles[:20]
+	affected := slices.Clone(order)
+	selected := make(map[string]bool)
+	if tests != nil {
+		selection, err := tests.BuildAffectedGraph(ctx, affected, projectRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select tests: %w", err)
+		}
+		for _, path := range affected {
+			for _, test := range selection.TestMapping[path] {
+				if test != path && inLanguage(test) {
+					addFile(test, blastReasonTest, path)
+					selected[test] = true
+				}
+			}
+		}
 	}
 
+	for _, path := range order {
+		result.Files = append(result.Files, *files[path])
+		// Callers and importers in test files are tests to run as well
+		if selected[path] || (isTestFile(path) && inLanguage(path)) {
+			result.Tests = append(result.Tests, path)
+		}
+	}
 	return result, nil
 }
 
-func isTestFile(path string) bool {
-	return strings.Contains(path, "_test.") ||
-		strings.Contains(path, ".test.") ||
-		strings.Contains(path, ".spec.") ||
-		strings.Contains(path, "/tests/") ||
-		strings.Contains(path, "/__tests__/")
-}
-
 // === Memory/Context API (Phase 6) ===
 
 // ContextMemoryEntry represents a saved context
@@ -492,17 +676,7 @@ func (a *App) GetRecentContexts(projectPath string, limit int) ([]ContextMemoryE
 		return nil, fmt.Errorf("failed to get recent contexts: %w", err)
 	}
 
-	result := make([]ContextMemoryEntry, 0, len(contexts))
-	for _, ctx := range contexts {
-		result = append(result, ContextMemoryEntry{
-			ID:        ctx.ID,
-			Topic:     ctx.Topic,
-			Summary:   ctx.Summary,
-			Files:     ctx.Files,
-			CreatedAt: ctx.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		})
-	}
-	return result, nil
+	return toContextMemoryEntries(contexts), nil
 }
 
 // FindContextByTopic searches contexts by topic
@@ -521,6 +695,29 @@ func (a *App) FindContextByTopic(projectPath, topic string) ([]ContextMemoryEntr
 		return nil, fmt.Errorf("failed to find contexts: %w", err)
 	}
 
+	return toContextMemoryEntries(contexts), nil
+}
+
+// FindSimilarContexts returns saved contexts most similar to the query.
+// Falls back to topic search when no embedding provider is configured.
+func (a *App) FindSimilarContexts(projectPath, query string, limit int) ([]ContextMemoryEntry, error) {
+	if a.analysisContainer == nil {
+		return []ContextMemoryEntry{}, nil
+	}
+
+	contextMemory := a.analysisContainer.GetContextMemory()
+	if contextMemory == nil {
+		return []ContextMemoryEntry{}, nil
+	}
+
+	contexts, err := contextMemory.FindSimilarContexts(projectPath, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar contexts: %w", err)
+	}
+	return toContextMemoryEntries(contexts), nil
+}
+
+func toContextMemoryEntries(contexts []*domain.ConversationContext) []ContextMemoryEntry {
 	result := make([]ContextMemoryEntry, 0, len(contexts))
 	for _, ctx := range contexts {
 		result = append(result, ContextMemoryEntry{
@@ -531,7 +728,26 @@ func (a *App) FindContextByTopic(projectPath, topic string) ([]ContextMemoryEntr
 			CreatedAt: ctx.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		})
 	}
-	return result, nil
+	return result
+}
+
+// PruneContexts removes saved contexts beyond the retention limits
+// and returns how many were removed
+func (a *App) PruneContexts(projectPath string) (int, error) {
+	if a.analysisContainer == nil {
+		return 0, nil
+	}
+
+	contextMemory := a.analysisContainer.GetContextMemory()
+	if contextMemory == nil {
+		return 0, nil
+	}
+
+	removed, err := contextMemory.PruneContexts(projectPath)
+	if err != nil {
+		return removed, fmt.Errorf("failed to prune contexts: %w", err)
+	}
+	return removed, nil
 }
 
 // SaveContextMemory saves current context to memory
@@ -570,6 +786,23 @@ func (a *App) ExecuteRepair(projectPath, errorOutput, language string, maxAttemp
 	return a.repairService.ExecuteRepair(a.ctx, req)
 }
 
+// PreviewRepair returns the corrections a repair would make as a diff, without changing files
+func (a *App) PreviewRepair(projectPath, errorOutput, language string) (*domain.RepairResult, error) {
+	req := domain.RepairRequest{
+		ProjectPath: projectPath,
+		ErrorOutput: errorOutput,
+		Language:    language,
+		MaxAttempts: 1,
+		DryRun:      true,
+	}
+	return a.repairService.ExecuteRepair(a.ctx, req)
+}
+
+// GetRepairHistory returns the reports of past repair runs for a project, newest first
+func (a *App) GetRepairHistory(projectPath string) ([]domain.RepairReport, error) {
+	return a.repairService.GetRepairHistory(a.ctx, projectPath)
+}
+
 // GetAvailableRepairRules returns available rules for a language
 func (a *App) GetAvailableRepairRules(language string) ([]domain.RepairRule, error) {
 	return a.repairService.GetAvailableRules(language)
@@ -590,6 +823,11 @@ func (a *App) ValidateRepairRule(rule domain.RepairRule) error {
 	return a.repairService.ValidateRule(rule)
 }
 
+// TestRepairRule shows what a rule would do to in-memory sample files, without touching the project
+func (a *App) TestRepairRule(rule domain.RepairRule, sampleError string, sampleFiles map[string]string) (*domain.RepairResult, error) {
+	return a.repairService.TestRepairRule(rule, sampleError, sampleFiles)
+}
+
 // === Guardrail Service ===
 
 // ValidatePath validates a path against policies
@@ -641,3 +879,8 @@ func (a *App) RemoveBudgetPolicy(policyID string) error {
 func (a *App) UpdateBudgetPolicy(policy domain.BudgetPolicy) error {
 	return a.guardrailService.UpdateBudgetPolicy(policy)
 }
+
+// commandCtx labels the streamed output of commands started by a UI call with step
+func (a *App) commandCtx(step string) context.Context {
+	return domain.WithCommandStep(a.ctx, "", step)
+}
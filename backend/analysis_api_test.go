@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"shotgun_code/cmd/app"
+	"shotgun_code/domain"
+	"shotgun_code/internal/initmanager"
+	"strings"
+	"testing"
+)
+
+// stubSemanticSearch returns fixed results for every query
+type stubSemanticSearch struct {
+	domain.SemanticSearchService
+	results   []domain.SemanticSearchResult
+	lastQuery string
+}
+
+func (s *stubSemanticSearch) IsIndexed(context.Context, string) bool { return true }
+
+func (s *stubSemanticSearch) Search(_ context.Context, req domain.SemanticSearchRequest) (*domain.SemanticSearchResponse, error) {
+	s.lastQuery = req.Query
+	return &domain.SemanticSearchResponse{Results: s.results}, nil
+}
+
+func TestGetSemanticSuggestions(t *testing.T) {
+	search := &stubSemanticSearch{results: []domain.SemanticSearchResult{
+		{Chunk: domain.CodeChunk{FilePath: "config/loader.go"}, Score: 0.9},
+		{Chunk: domain.CodeChunk{FilePath: "config/loader.go", StartLine: 40}, Score: 0.8},
+		{Chunk: domain.CodeChunk{FilePath: "main.go"}, Score: 0.7},
+	}}
+	semantic := initmanager.NewLazyService(func(context.Context) (*app.SemanticServices, error) {
+		return &app.SemanticServices{Search: search}, nil
+	})
+	a := &App{ctx: context.Background(), log: &domain.NoopLogger{}, container: &app.AppContainer{Semantic: semantic}}
+
+	seen := map[string]bool{"main.go": true}
+	suggestions := a.getSemanticSuggestions(t.TempDir(), []string{"main.go"}, "load config", seen)
+
+	if search.lastQuery != "load config" {
+		t.Errorf("expected task as query, got %q", search.lastQuery)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("expected 1 deduplicated suggestion, got %+v", suggestions)
+	}
+	if s := suggestions[0]; s.Path != "config/loader.go" || s.Source != "semantic" || s.Confidence < 0.89 {
+		t.Errorf("unexpected suggestion %+v", s)
+	}
+}
+
+func TestGetSemanticSuggestions_NotConfigured(t *testing.T) {
+	a := &App{ctx: context.Background(), log: &domain.NoopLogger{}, container: &app.AppContainer{}}
+	if suggestions := a.getSemanticSuggestions(t.TempDir(), nil, "task", map[string]bool{}); suggestions != nil {
+		t.Errorf("expected no suggestions, got %+v", suggestions)
+	}
+}
+
+// stubCallGraph serves a fixed call graph and file dependents
+type stubCallGraph struct {
+	domain.CallGraphBuilder
+	graph      *domain.CallGraph
+	callers    []domain.CallGraphNode
+	dependents map[string][]string
+}
+
+func (s *stubCallGraph) Build(string) (*domain.CallGraph, error) { return s.graph, nil }
+
+func (s *stubCallGraph) GetImpact(string, int) []domain.CallGraphNode { return s.callers }
+
+func (s *stubCallGraph) GetFileDependents(path string) []string { return s.dependents[path] }
+
+// stubTestSelector maps affected files to their tests
+type stubTestSelector struct {
+	domain.ITestService
+	mapping map[string][]string
+}
+
+func (s *stubTestSelector) BuildAffectedGraph(_ context.Context, changedFiles []string, _ string) (*domain.AffectedGraph, error) {
+	return &domain.AffectedGraph{ChangedFiles: changedFiles, TestMapping: s.mapping}, nil
+}
+
+func TestCollectBlastRadius(t *testing.T) {
+	fn := domain.CallGraphNode{ID: "store.Load", Name: "Load", FilePath: "store/load.go"}
+	callGraph := &stubCallGraph{
+		graph: &domain.CallGraph{Nodes: map[string]*domain.CallGraphNode{fn.ID: &fn}},
+		callers: []domain.CallGraphNode{
+			{ID: "api.Get", Name: "Get", FilePath: "api/get.go", Line: 20},
+			{ID: "api.List", Name: "List", FilePath: "api/get.go", Line: 5},
+			{ID: "store.TestLoad", Name: "TestLoad", FilePath: "store/load_test.go"},
+		},
+		dependents: map[string][]string{
+			"store":         {"api/get.go", "cmd/main.go", "web/client.ts"},
+			"store/load.go": nil,
+		},
+	}
+	tests := &stubTestSelector{mapping: map[string][]string{
+		"api/get.go":    {"api/get_test.go"},
+		"store/load.go": {"store/load_test.go"},
+	}}
+
+	radius, err := collectBlastRadius(context.Background(), callGraph, tests, "/project", "go", fn.ID, 0)
+	if err != nil {
+		t.Fatalf("collectBlastRadius failed: %v", err)
+	}
+
+	var paths []string
+	byPath := make(map[string]BlastRadiusFile)
+	for _, file := range radius.Files {
+		paths = append(paths, file.Path)
+		byPath[file.Path] = file
+	}
+	want := []string{"store/load.go", "api/get.go", "store/load_test.go", "cmd/main.go", "api/get_test.go"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected deduplicated files %v, got %v", want, paths)
+	}
+	if reasons := byPath["api/get.go"].Reasons; len(reasons) != 3 ||
+		reasons[0] != (BlastRadiusReason{Kind: blastReasonCaller, Detail: "List"}) ||
+		reasons[2] != (BlastRadiusReason{Kind: blastReasonDependent, Detail: "store/load.go"}) {
+		t.Errorf("unexpected reasons for api/get.go: %+v", reasons)
+	}
+	if reasons := byPath["store/load_test.go"].Reasons; len(reasons) != 2 || reasons[1].Kind != blastReasonTest {
+		t.Errorf("expected store/load_test.go as caller and test, got %+v", reasons)
+	}
+	if strings.Join(radius.Tests, ",") != "store/load_test.go,api/get_test.go" {
+		t.Errorf("unexpected tests %v", radius.Tests)
+	}
+
+	if _, err := collectBlastRadius(context.Background(), callGraph, nil, "/project", "", "missing", 0); err == nil {
+		t.Error("expected error for unknown function")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	appai "shotgun_code/application/ai"
 	"shotgun_code/application/analysis"
@@ -12,11 +13,11 @@ import (
 	"shotgun_code/application/protocol"
 	"shotgun_code/application/sbom"
 	"shotgun_code/application/settings"
-	"shotgun_code/application/symbol"
 	"shotgun_code/application/taskflow"
 	"shotgun_code/cmd/app"
 	"shotgun_code/domain"
 	"shotgun_code/handlers"
+	"shotgun_code/infrastructure/git"
 	"shotgun_code/infrastructure/wailsbridge"
 	contextservice "shotgun_code/internal/context"
 	projectservice "shotgun_code/internal/project"
@@ -59,7 +60,6 @@ type App struct {
 	aiService             *appai.Service
 	settingsService       *settings.Service
 	contextAnalysis       contextAnalysisService
-	symbolGraph           *symbol.Service
 	testService           domain.ITestService
 	staticAnalyzerService domain.IStaticAnalyzerService
 	sbomService           *sbom.Service
@@ -87,12 +87,18 @@ type App struct {
 	// Analysis Container (for smart analysis tools)
 	analysisContainer *analysis.Container
 
+	// Git context for smart suggestions, cached per project (keeps co-change data)
+	gitContextMu   sync.Mutex
+	gitContext     *git.ContextBuilder
+	gitContextRoot string
+
 	// Startup path from command line arguments
 	startupPath string
 }
 
 func (a *App) startup(ctx context.Context, container *app.AppContainer) {
-	a.ctx = ctx
+	// Commands started from the UI stream their output as command:output events
+	a.ctx = domain.WithCommandOutput(ctx, container.Bridge)
 	a.log = container.Log
 	a.bridge = container.Bridge
 	a.container = container
@@ -127,7 +133,6 @@ func (a *App) startup(ctx context.Context, container *app.AppContainer) {
 			a.log.Warning("context analysis service does not implement required AnalyzeTaskAndCollectContext method")
 		}
 	}
-	a.symbolGraph = container.SymbolGraph
 	a.testService = container.TestService
 	a.staticAnalyzerService = container.StaticAnalyzerService
 	a.sbomService = container.SBOMService
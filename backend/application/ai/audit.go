@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"shotgun_code/domain"
+	"time"
+)
+
+// defaultAuditQueryLimit caps audit queries that don't set a limit
+const defaultAuditQueryLimit = 500
+
+// AuditLogger records every AI request (provider, model, prompt hash, tokens,
+// cost, latency, outcome) to the audit trail. Prompt text is only stored when
+// AIAuditIncludePrompts is set. A nil AuditLogger records nothing.
+type AuditLogger struct {
+	repo     domain.AIAuditRepository
+	settings SettingsProvider
+	log      domain.Logger
+}
+
+// NewAuditLogger creates an audit logger writing to repo
+func NewAuditLogger(repo domain.AIAuditRepository, settings SettingsProvider, log domain.Logger) *AuditLogger {
+	return &AuditLogger{repo: repo, settings: settings, log: log}
+}
+
+// auditCall describes an AI request being audited
+type auditCall struct {
+	provider  domain.AIProvider
+	req       domain.AIRequest
+	start     time.Time
+	streaming bool
+}
+
+func newAuditCall(provider domain.AIProvider, req domain.AIRequest, streaming bool) auditCall {
+	return auditCall{provider: provider, req: req, start: time.Now(), streaming: streaming}
+}
+
+// Record writes an entry for a finished call. tokensUsed is the provider's
+// total; content is the response, used to estimate tokens when the provider
+// reports none. Failures to write are logged, not returned.
+func (a *AuditLogger) Record(call auditCall, outcome string, tokensUsed int, content string, callErr error) {
+	if a == nil || a.repo == nil {
+		return
+	}
+
+	req := call.req
+	entry := domain.AIAuditEntry{
+		ID:         fmt.Sprintf("audit_%d", time.Now().UnixNano()),
+		Timestamp:  call.start,
+		RequestID:  req.RequestID,
+		Model:      req.Model,
+		Streaming:  call.streaming,
+		PromptHash: hashPrompt(req.SystemPrompt, req.UserPrompt),
+		LatencyMs:  time.Since(call.start).Milliseconds(),
+		Outcome:    outcome,
+	}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	}
+	if a.includePrompts() {
+		entry.SystemPrompt = req.SystemPrompt
+		entry.UserPrompt = req.UserPrompt
+	}
+
+	entry.PromptTokens, entry.CompletionTokens = splitTokens(req, tokensUsed, content)
+	entry.TotalTokens = entry.PromptTokens + entry.CompletionTokens
+
+	if call.provider != nil {
+		entry.Provider = call.provider.GetProviderInfo().Name
+		// Failed requests and cached responses aren't billed
+		if outcome == domain.AIAuditOutcomeSuccess {
+			pricing := call.provider.GetPricing(req.Model)
+			entry.Cost = float64(entry.PromptTokens)/1000*pricing.InputTokensPer1K +
+				float64(entry.CompletionTokens)/1000*pricing.OutputTokensPer1K
+			entry.Currency = pricing.Currency
+		}
+	}
+
+	if err := a.repo.Append(entry); err != nil {
+		a.log.Warning(fmt.Sprintf("Failed to write AI audit entry: %v", err))
+	}
+}
+
+// Query returns audit entries matching query, newest first
+func (a *AuditLogger) Query(query domain.AIAuditQuery) ([]domain.AIAuditEntry, error) {
+	if a == nil || a.repo == nil {
+		return []domain.AIAuditEntry{}, nil
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultAuditQueryLimit
+	}
+	return a.repo.Query(query)
+}
+
+func (a *AuditLogger) includePrompts() bool {
+	if a.settings == nil {
+		return false
+	}
+	dto, err := a.settings.GetSettingsDTO()
+	return err == nil && dto.AIAuditIncludePrompts
+}
+
+func hashPrompt(systemPrompt, userPrompt string) string {
+	h := sha256.New()
+	h.Write([]byte(systemPrompt))
+	h.Write([]byte{0})
+	h.Write([]byte(userPrompt))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// splitTokens splits the provider's total into prompt and completion tokens.
+// Providers only report the total, so the prompt share is estimated at ~4
+// characters per token.
+func splitTokens(req domain.AIRequest, tokensUsed int, content string) (prompt, completion int) {
+	prompt = (len(req.SystemPrompt) + len(req.UserPrompt)) / 4
+	if tokensUsed <= 0 {
+		return prompt, len(content) / 4
+	}
+	if prompt > tokensUsed {
+		prompt = tokensUsed
+	}
+	return prompt, tokensUsed - prompt
+}
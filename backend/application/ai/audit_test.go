@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"shotgun_code/domain"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubSettings struct {
+	dto domain.SettingsDTO
+}
+
+func (s *stubSettings) GetSettingsDTO() (domain.SettingsDTO, error) { return s.dto, nil }
+
+type stubProvider struct {
+	resp domain.AIResponse
+	err  error
+}
+
+func (p *stubProvider) Generate(context.Context, domain.AIRequest) (domain.AIResponse, error) {
+	return p.resp, p.err
+}
+
+func (p *stubProvider) GenerateStream(_ context.Context, _ domain.AIRequest, onChunk func(domain.StreamChunk)) error {
+	onChunk(domain.StreamChunk{Content: p.resp.Content})
+	onChunk(domain.StreamChunk{Done: true, TokensUsed: p.resp.TokensUsed})
+	return p.err
+}
+
+func (p *stubProvider) ListModels(context.Context) ([]string, error) { return nil, nil }
+func (p *stubProvider) GetProviderInfo() domain.ProviderInfo {
+	return domain.ProviderInfo{Name: "stub"}
+}
+func (p *stubProvider) ValidateRequest(domain.AIRequest) error { return nil }
+func (p *stubProvider) EstimateTokens(domain.AIRequest) (int, error) {
+	return 0, nil
+}
+
+func (p *stubProvider) GetPricing(model string) domain.PricingInfo {
+	return domain.PricingInfo{Model: model, InputTokensPer1K: 1, OutputTokensPer1K: 2, Currency: "USD"}
+}
+
+type memoryAuditRepo struct {
+	entries []domain.AIAuditEntry
+}
+
+func (r *memoryAuditRepo) Append(entry domain.AIAuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *memoryAuditRepo) Query(query domain.AIAuditQuery) ([]domain.AIAuditEntry, error) {
+	var result []domain.AIAuditEntry
+	for _, e := range r.entries {
+		if query.Matches(e) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func newAuditedService(provider domain.AIProvider, settings *stubSettings) (*Service, *memoryAuditRepo) {
+	// localai needs no API key
+	settings.dto.SelectedProvider = "localai"
+	settings.dto.SelectedModels = map[string]string{"localai": "stub-model"}
+	registry := map[string]domain.AIProviderFactory{
+		"localai": func(string, string) (domain.AIProvider, error) { return provider, nil },
+	}
+	repo := &memoryAuditRepo{}
+	svc := NewService(settings, &domain.NoopLogger{}, registry, nil)
+	svc.SetAuditLogger(NewAuditLogger(repo, settings, &domain.NoopLogger{}))
+	return svc, repo
+}
+
+func TestAuditLogger_RecordsGeneration(t *testing.T) {
+	provider := &stubProvider{resp: domain.AIResponse{Content: "done", TokensUsed: 1500}}
+	svc, repo := newAuditedService(provider, &stubSettings{})
+	defer func() { _ = svc.Shutdown(context.Background()) }()
+
+	userPrompt := strings.Repeat("x", 2000) // ~500 prompt tokens
+	deterministic := GenerationOptions{Deterministic: true}
+	_, err := svc.GenerateCodeWithOptions(context.Background(), "", userPrompt, deterministic)
+	require.NoError(t, err)
+	// Second identical request is served from the cache
+	_, err = svc.GenerateCodeWithOptions(context.Background(), "", userPrompt, deterministic)
+	require.NoError(t, err)
+
+	require.Len(t, repo.entries, 2)
+	entry := repo.entries[0]
+	assert.Equal(t, "stub", entry.Provider)
+	assert.Equal(t, "stub-model", entry.Model)
+	assert.Equal(t, domain.AIAuditOutcomeSuccess, entry.Outcome)
+	assert.Equal(t, hashPrompt("", userPrompt), entry.PromptHash)
+	assert.Empty(t, entry.UserPrompt, "prompt content is excluded by default")
+	assert.Equal(t, 500, entry.PromptTokens)
+	assert.Equal(t, 1000, entry.CompletionTokens)
+	assert.Equal(t, 1500, entry.TotalTokens)
+	assert.InDelta(t, 0.5*1+1.0*2, entry.Cost, 1e-9)
+	assert.Equal(t, "USD", entry.Currency)
+
+	assert.Equal(t, domain.AIAuditOutcomeCacheHit, repo.entries[1].Outcome)
+	assert.Zero(t, repo.entries[1].Cost)
+}
+
+func TestAuditLogger_RecordsErrorsAndPrompts(t *testing.T) {
+	provider := &stubProvider{err: errors.New("quota exceeded")}
+	settings := &stubSettings{dto: domain.SettingsDTO{AIAuditIncludePrompts: true}}
+	svc, _ := newAuditedService(provider, settings)
+	defer func() { _ = svc.Shutdown(context.Background()) }()
+
+	_, err := svc.GenerateCode(context.Background(), "system", "user")
+	require.Error(t, err)
+
+	err = svc.GenerateCodeStream(context.Background(), "system", "user", func(domain.StreamChunk) {})
+	require.Error(t, err)
+
+	entries, err := svc.QueryAuditLog(domain.AIAuditQuery{Outcome: domain.AIAuditOutcomeError})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "quota exceeded", entries[0].Error)
+	assert.Equal(t, "system", entries[0].SystemPrompt)
+	assert.Equal(t, "user", entries[0].UserPrompt)
+	assert.Zero(t, entries[0].Cost)
+	assert.True(t, entries[1].Streaming)
+}
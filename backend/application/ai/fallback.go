@@ -0,0 +1,36 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net"
+	"shotgun_code/domain"
+)
+
+// isRetryableProviderError reports whether err means the provider is
+// unavailable (timeout, 5xx, rate limit, network failure) rather than the
+// request being wrong, so it's worth trying the next provider. A cancelled or
+// expired ctx is never retried.
+func isRetryableProviderError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var providerErr *domain.ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.Retryable
+	}
+	if errors.Is(err, domain.ErrRateLimitExceeded) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var domainErr *domain.DomainError
+	if errors.As(err, &domainErr) {
+		switch domainErr.Code {
+		case domain.ErrCodeRateLimitExceeded, domain.ErrCodeExternalService, domain.ErrCodeTimeout, domain.ErrCodeNetworkError:
+			return true
+		}
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
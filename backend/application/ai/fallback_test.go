@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"errors"
+	"shotgun_code/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFallbackService(primary, fallback domain.AIProvider) *Service {
+	// localai and qwen-cli need no API key
+	settings := &stubSettings{dto: domain.SettingsDTO{
+		SelectedProvider:   "localai",
+		AIProviderFallback: []string{"qwen-cli"},
+		SelectedModels:     map[string]string{"localai": "local-model", "qwen-cli": "qwen-model"},
+	}}
+	registry := map[string]domain.AIProviderFactory{
+		"localai":  func(string, string) (domain.AIProvider, error) { return primary, nil },
+		"qwen-cli": func(string, string) (domain.AIProvider, error) { return fallback, nil },
+	}
+	intelligent := NewIntelligentService(settings, &domain.NoopLogger{}, NewRateLimiter(), NewMetricsCollector())
+	svc := NewService(settings, &domain.NoopLogger{}, registry, intelligent)
+	intelligent.SetProviderGetter(svc)
+	return svc
+}
+
+func TestGenerateCode_FallsBackOnProviderOutage(t *testing.T) {
+	primary := &stubProvider{err: domain.NewExternalError("AI provider", errors.New("502 Bad Gateway"))}
+	fallback := &stubProvider{resp: domain.AIResponse{Content: "patched", TokensUsed: 7}}
+	svc := newFallbackService(primary, fallback)
+
+	result, err := svc.GenerateCodeDetailed(t.Context(), "system", "task", GenerationOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "patched", result.Content)
+	assert.Equal(t, "qwen-cli", result.Provider)
+	assert.Equal(t, "qwen-model", result.Model)
+}
+
+func TestGenerateCode_DoesNotFallBackOnRequestErrors(t *testing.T) {
+	primary := &stubProvider{err: domain.ErrInvalidAPIKey}
+	fallback := &stubProvider{resp: domain.AIResponse{Content: "patched"}}
+	svc := newFallbackService(primary, fallback)
+
+	_, err := svc.GenerateCode(t.Context(), "system", "task")
+
+	require.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+}
+
+func TestGenerateIntelligentCode_RateLimitIsPerProvider(t *testing.T) {
+	primary := &stubProvider{resp: domain.AIResponse{Content: "primary"}}
+	fallback := &stubProvider{resp: domain.AIResponse{Content: "fallback"}}
+	svc := newFallbackService(primary, fallback)
+	// The primary provider has no request budget left
+	svc.GetIntelligentService().rateLimiter.config["localai"] = rateLimitConfig{}
+
+	result, err := svc.GenerateIntelligentCode(t.Context(), "task", "", IntelligentGenerationOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", result.Content)
+	assert.Equal(t, "qwen-cli", result.Provider)
+}
+
+func TestGenerateCode_FallbackFollowsProviderErrors(t *testing.T) {
+	fallback := &stubProvider{resp: domain.AIResponse{Content: "patched"}}
+
+	overloaded := &stubProvider{err: domain.NewProviderError("LocalAI", 503, 0, errors.New("overloaded"))}
+	result, err := newFallbackService(overloaded, fallback).GenerateCodeDetailed(t.Context(), "system", "task", GenerationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "qwen-cli", result.Provider)
+
+	rejected := &stubProvider{err: domain.NewProviderError("LocalAI", 401, 0, errors.New("bad key"))}
+	_, err = newFallbackService(rejected, fallback).GenerateCode(t.Context(), "system", "task")
+	require.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+}
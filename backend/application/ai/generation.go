@@ -2,8 +2,10 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"shotgun_code/domain"
+	"strings"
 	"sync/atomic"
 	"time"
 )
@@ -16,6 +18,11 @@ type GenerationOptions struct {
 	TopP        float64
 	Priority    domain.RequestPriority
 	Timeout     time.Duration
+	// Deterministic sends temperature 0; identical deterministic requests are
+	// answered from the response cache
+	Deterministic bool
+	// NoCache skips the response cache for this request
+	NoCache bool
 }
 
 type generationParams struct {
@@ -25,7 +32,7 @@ type generationParams struct {
 	topP        float64
 	timeout     time.Duration
 	priority    domain.RequestPriority
-	useCache    bool
+	noCache     bool
 }
 
 func applyOptions(params *generationParams, options *GenerationOptions) {
@@ -37,9 +44,9 @@ func applyOptions(params *generationParams, options *GenerationOptions) {
 	}
 	if options.Temperature != 0 {
 		params.temperature = options.Temperature
-		if params.temperature > deterministicTempThreshold {
-			params.useCache = false
-		}
+	}
+	if options.Deterministic {
+		params.temperature = 0
 	}
 	if options.MaxTokens != 0 {
 		params.maxTokens = options.MaxTokens
@@ -53,76 +60,140 @@ func applyOptions(params *generationParams, options *GenerationOptions) {
 	if options.Priority != domain.PriorityLow {
 		params.priority = options.Priority
 	}
+	params.noCache = options.NoCache
 }
 
-func (s *Service) checkCache(cacheKey string, useCache bool) (string, bool) {
-	if !useCache {
-		return "", false
-	}
-	s.responseCacheMu.RLock()
-	defer s.responseCacheMu.RUnlock()
-	if cached, ok := s.responseCache[cacheKey]; ok && time.Since(cached.timestamp) < responseCacheTTL {
-		atomic.AddInt64(&s.cacheHits, 1)
-		return cached.content, true
-	}
-	return "", false
+// useCache reports whether the response may be cached: sampling at a higher
+// temperature is expected to give a different answer every time
+func (p *generationParams) useCache() bool {
+	return !p.noCache && p.temperature <= deterministicTempThreshold
+}
+
+// GenerationResult is generated content with the provider and model that served it
+type GenerationResult struct {
+	Content    string
+	Provider   string
+	Model      string
+	TokensUsed int
+	Cached     bool
 }
 
-func (s *Service) generateCodeInternal(ctx context.Context, systemPrompt, userPrompt string, options *GenerationOptions) (string, error) {
+func (s *Service) generateCodeInternal(ctx context.Context, systemPrompt, userPrompt string, options *GenerationOptions) (*GenerationResult, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	atomic.AddInt64(&s.totalRequests, 1)
 
-	provider, model, err := s.getProvider(ctx)
+	chain, err := s.GetProviderChain(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	params := &generationParams{
-		model: model, temperature: DefaultTemperature, maxTokens: DefaultMaxTokens,
-		topP: DefaultTopP, timeout: DefaultTimeout, priority: domain.PriorityNormal, useCache: true,
+		model: chain[0].Model, temperature: DefaultTemperature, maxTokens: DefaultMaxTokens,
+		topP: DefaultTopP, timeout: DefaultTimeout, priority: domain.PriorityNormal,
 	}
 	applyOptions(params, options)
 
-	cacheKey := s.getCacheKey(systemPrompt, userPrompt, params.model, params.temperature, params.maxTokens, params.topP)
-	if content, found := s.checkCache(cacheKey, params.useCache); found {
-		return content, nil
-	}
-	atomic.AddInt64(&s.cacheMisses, 1)
-
 	req := domain.AIRequest{
 		Model: params.model, SystemPrompt: systemPrompt, UserPrompt: userPrompt,
 		Temperature: params.temperature, MaxTokens: params.maxTokens, TopP: params.topP,
-		RequestID: fmt.Sprintf("req_%d", time.Now().UnixNano()),
+		RequestID: requestIDFromContext(ctx, fmt.Sprintf("req_%d", time.Now().UnixNano())),
 		Priority:  params.priority, Timeout: params.timeout,
 	}
 
-	tctx, cancel := context.WithTimeout(ctx, params.timeout)
-	defer cancel()
+	cacheKey := s.getCacheKey(chain[0].Name, params.model, systemPrompt, userPrompt, params.temperature, params.maxTokens, params.topP)
+	if params.useCache() {
+		if cached, found := s.responses.get(cacheKey); found {
+			s.auditLogger.Record(newAuditCall(chain[0].Provider, req, false), domain.AIAuditOutcomeCacheHit, 0, cached.Content, nil)
+			return &GenerationResult{Content: cached.Content, Provider: cached.Provider, Model: cached.Model, Cached: true}, nil
+		}
+	}
 
-	resp, err := provider.Generate(tctx, req)
+	var resp domain.AIResponse
+	var served domain.AIProviderChoice
+	fellBack := false
+	for i, choice := range chain {
+		if i > 0 {
+			s.log.Warning(fmt.Sprintf("AI provider %s failed (%v), falling back to %s", chain[i-1].Name, err, choice.Name))
+			// Модель из options относится к основному провайдеру
+			req.Model = choice.Model
+		}
+		if limiter := s.rateLimiter(); limiter != nil {
+			if err = limiter.CheckLimit(choice.Name); err != nil {
+				err = fmt.Errorf("%w: %v", domain.ErrRateLimitExceeded, err)
+				continue
+			}
+		}
+		resp, err = s.generateWith(ctx, choice.Provider, req)
+		if limiter := s.rateLimiter(); limiter != nil {
+			limiter.Observe(choice.Name, err)
+		}
+		if err == nil {
+			served = choice
+			fellBack = i > 0
+			break
+		}
+		if !isRetryableProviderError(ctx, err) {
+			break
+		}
+	}
 	if err != nil {
-		return "", fmt.Errorf("AI generation failed: %w", err)
+		return nil, fmt.Errorf("AI generation failed: %w", err)
 	}
 
-	if params.useCache && resp.Content != "" {
-		s.responseCacheMu.Lock()
-		s.responseCache[cacheKey] = &cachedAIResponse{content: resp.Content, timestamp: time.Now(), tokens: resp.TokensUsed}
-		s.responseCacheMu.Unlock()
+	model := resp.ModelUsed
+	if model == "" {
+		model = req.Model
+	}
+	// The key names the primary provider, so a fallback's answer isn't cached under it
+	if params.useCache() && !fellBack && resp.Content != "" {
+		s.responses.put(&domain.AICacheEntry{
+			Key: cacheKey, Content: resp.Content, Provider: served.Name, Model: model,
+			TokensUsed: resp.TokensUsed, CreatedAt: time.Now(),
+		})
 	}
 
 	atomic.AddInt64(&s.totalTokensUsed, int64(resp.TokensUsed))
-	return resp.Content, nil
+	return &GenerationResult{Content: resp.Content, Provider: served.Name, Model: model, TokensUsed: resp.TokensUsed}, nil
+}
+
+// generateWith runs req on one provider under the request timeout and audits the call
+func (s *Service) generateWith(ctx context.Context, provider domain.AIProvider, req domain.AIRequest) (domain.AIResponse, error) {
+	tctx, cancel := context.WithTimeout(ctx, req.Timeout)
+	defer cancel()
+
+	call := newAuditCall(provider, req, false)
+	resp, err := provider.Generate(tctx, req)
+	if err != nil {
+		s.auditLogger.Record(call, domain.AIAuditOutcomeError, 0, "", err)
+		return domain.AIResponse{}, err
+	}
+	s.auditLogger.Record(call, domain.AIAuditOutcomeSuccess, resp.TokensUsed, resp.Content, nil)
+	return resp, nil
 }
 
 // GenerateCode generates code using AI
 func (s *Service) GenerateCode(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
-	return s.generateCodeInternal(ctx, systemPrompt, userPrompt, nil)
+	result, err := s.generateCodeInternal(ctx, systemPrompt, userPrompt, nil)
+	if err != nil {
+		return "", err
+	}
+	return result.Content, nil
 }
 
 // GenerateCodeWithOptions generates code with additional options
 func (s *Service) GenerateCodeWithOptions(ctx context.Context, systemPrompt, userPrompt string, options GenerationOptions) (string, error) {
+	result, err := s.generateCodeInternal(ctx, systemPrompt, userPrompt, &options)
+	if err != nil {
+		return "", err
+	}
+	return result.Content, nil
+}
+
+// GenerateCodeDetailed generates code like GenerateCodeWithOptions and reports
+// which provider served the request after any fallback
+func (s *Service) GenerateCodeDetailed(ctx context.Context, systemPrompt, userPrompt string, options GenerationOptions) (*GenerationResult, error) {
 	return s.generateCodeInternal(ctx, systemPrompt, userPrompt, &options)
 }
 
@@ -142,14 +213,36 @@ func (s *Service) GenerateCodeStream(ctx context.Context, systemPrompt, userProm
 	req := domain.AIRequest{
 		Model: model, SystemPrompt: systemPrompt, UserPrompt: userPrompt,
 		Temperature: DefaultTemperature, MaxTokens: DefaultMaxTokens, TopP: DefaultTopP,
-		RequestID: fmt.Sprintf("stream_%d", time.Now().UnixNano()),
+		RequestID: requestIDFromContext(ctx, fmt.Sprintf("stream_%d", time.Now().UnixNano())),
 		Priority:  domain.PriorityNormal, Timeout: DefaultStreamTimeout,
 	}
 
 	tctx, cancel := context.WithTimeout(ctx, req.Timeout)
 	defer cancel()
 
-	return provider.GenerateStream(tctx, req, onChunk)
+	call := newAuditCall(provider, req, true)
+	var content strings.Builder
+	var tokensUsed int
+	var streamErr error
+	err = provider.GenerateStream(tctx, req, func(chunk domain.StreamChunk) {
+		content.WriteString(chunk.Content)
+		if chunk.TokensUsed > 0 {
+			tokensUsed = chunk.TokensUsed
+		}
+		if chunk.Error != "" {
+			streamErr = errors.New(chunk.Error)
+		}
+		onChunk(chunk)
+	})
+	if err == nil {
+		err = streamErr
+	}
+	if err != nil {
+		s.auditLogger.Record(call, domain.AIAuditOutcomeError, tokensUsed, content.String(), err)
+	} else {
+		s.auditLogger.Record(call, domain.AIAuditOutcomeSuccess, tokensUsed, content.String(), nil)
+	}
+	return err
 }
 
 // GenerateIntelligentCode uses intelligent system for code generation
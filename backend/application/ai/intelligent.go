@@ -2,6 +2,7 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"shotgun_code/domain"
@@ -16,6 +17,7 @@ type IntelligentService struct {
 	providerGetter  domain.AIProviderGetter
 	rateLimiter     *RateLimiter
 	metrics         *MetricsCollector
+	auditLogger     *AuditLogger
 }
 
 // NewIntelligentService creates a new intelligent AI service
@@ -38,6 +40,11 @@ func (s *IntelligentService) SetProviderGetter(getter domain.AIProviderGetter) {
 	s.providerGetter = getter
 }
 
+// SetAuditLogger sets the audit logger for AI requests
+func (s *IntelligentService) SetAuditLogger(auditLogger *AuditLogger) {
+	s.auditLogger = auditLogger
+}
+
 // IntelligentGenerationOptions options for intelligent generation
 type IntelligentGenerationOptions struct {
 	Temperature            float64
@@ -84,7 +91,7 @@ func (s *IntelligentService) GenerateIntelligentCode(
 	}
 
 	intelligentReq := s.buildIntelligentRequest(task, dto, options)
-	provider, model, err := s.selectOptimalProvider(ctx, intelligentReq)
+	chain, err := s.selectProviderChain(ctx, intelligentReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to select provider: %w", err)
 	}
@@ -92,31 +99,32 @@ func (s *IntelligentService) GenerateIntelligentCode(
 	optimizedPrompt := s.optimizePrompt(task, codeContext, options)
 
 	req := domain.AIRequest{
-		Model: model, SystemPrompt: s.buildSystemPrompt(options), UserPrompt: optimizedPrompt,
+		SystemPrompt: s.buildSystemPrompt(options), UserPrompt: optimizedPrompt,
 		Temperature: options.Temperature, MaxTokens: options.MaxTokens, TopP: options.TopP,
-		RequestID: generateRequestID(), Priority: options.Priority, Timeout: options.Timeout,
-	}
-
-	if err := s.rateLimiter.CheckLimit(provider.GetProviderInfo().Name); err != nil {
-		return nil, fmt.Errorf("rate limit exceeded: %w", err)
+		RequestID: requestIDFromContext(ctx, generateRequestID()), Priority: options.Priority, Timeout: options.Timeout,
 	}
 
 	var response domain.AIResponse
+	var served domain.AIProviderChoice
 	var lastErr error
-
-	for attempt := 0; attempt <= options.MaxRetries; attempt++ {
-		if attempt > 0 {
-			s.log.Info(fmt.Sprintf("Retry attempt %d for request %s", attempt, req.RequestID))
-			time.Sleep(time.Duration(attempt) * time.Second)
+	for i, choice := range chain {
+		if i > 0 {
+			s.log.Warning(fmt.Sprintf("AI provider %s failed (%v), falling back to %s", chain[i-1].Name, lastErr, choice.Name))
+		}
+		// Лимит считается отдельно для каждого провайдера, чтобы исчерпанный лимит не блокировал fallback
+		if err := s.rateLimiter.CheckLimit(choice.Name); err != nil {
+			lastErr = fmt.Errorf("rate limit exceeded: %w", err)
+			continue
 		}
 
-		response, lastErr = provider.Generate(ctx, req)
+		req.Model = choice.Model
+		response, lastErr = s.generateWithRetries(ctx, choice.Name, choice.Provider, req, options.MaxRetries)
 		if lastErr == nil {
+			served = choice
 			break
 		}
-
-		if attempt == options.MaxRetries && options.EnableFallback {
-			response, lastErr = s.tryFallback(ctx, req)
+		if !isRetryableProviderError(ctx, lastErr) {
+			break
 		}
 	}
 
@@ -125,16 +133,43 @@ func (s *IntelligentService) GenerateIntelligentCode(
 	}
 
 	analysis := s.analyzeResponse(response)
-	s.metrics.RecordGeneration(provider.GetProviderInfo().Name, model, time.Since(startTime), response.TokensUsed)
+	s.metrics.RecordGeneration(served.Name, served.Model, time.Since(startTime), response.TokensUsed)
 
 	return &IntelligentGenerationResult{
 		Content: response.Content, ModelUsed: response.ModelUsed, TokensUsed: response.TokensUsed,
 		ProcessingTime: response.ProcessingTime, QualityScore: analysis.QualityScore,
 		Suggestions: analysis.Suggestions, Warnings: response.Warnings,
-		RequestID: req.RequestID, Provider: provider.GetProviderInfo().Name,
+		RequestID: req.RequestID, Provider: served.Name,
 	}, nil
 }
 
+// generateWithRetries calls provider up to maxRetries+1 times, stopping early
+// on errors that retrying the same provider won't fix. A rate limited provider
+// is not retried until the rate limiter's pause ends.
+func (s *IntelligentService) generateWithRetries(ctx context.Context, name string, provider domain.AIProvider, req domain.AIRequest, maxRetries int) (domain.AIResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			s.log.Info(fmt.Sprintf("Retry attempt %d for request %s", attempt, req.RequestID))
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		call := newAuditCall(provider, req, false)
+		response, err := provider.Generate(ctx, req)
+		s.rateLimiter.Observe(name, err)
+		if err == nil {
+			s.auditLogger.Record(call, domain.AIAuditOutcomeSuccess, response.TokensUsed, response.Content, nil)
+			return response, nil
+		}
+		s.auditLogger.Record(call, domain.AIAuditOutcomeError, 0, "", err)
+		lastErr = err
+		if errors.Is(err, domain.ErrRateLimitExceeded) {
+			break
+		}
+	}
+	return domain.AIResponse{}, lastErr
+}
+
 func (s *IntelligentService) buildIntelligentRequest(task string, _ domain.SettingsDTO, options IntelligentGenerationOptions) domain.IntelligentRequest {
 	return domain.IntelligentRequest{
 		BaseRequest: domain.AIRequest{UserPrompt: task, Priority: options.Priority, Timeout: options.Timeout},
@@ -150,28 +185,56 @@ func (s *IntelligentService) buildIntelligentRequest(task string, _ domain.Setti
 	}
 }
 
-func (s *IntelligentService) selectOptimalProvider(ctx context.Context, req domain.IntelligentRequest) (domain.AIProvider, string, error) {
+// selectProviderChain returns the providers to try in order: the selected one
+// and, when the getter supports it, the fallbacks from settings
+func (s *IntelligentService) selectProviderChain(ctx context.Context, req domain.IntelligentRequest) ([]domain.AIProviderChoice, error) {
 	if s.providerGetter == nil {
-		return nil, "", fmt.Errorf("provider getter not initialized")
+		return nil, fmt.Errorf("provider getter not initialized")
 	}
 
-	provider, model, err := s.providerGetter.GetProvider(ctx)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to get provider: %w", err)
+	var chain []domain.AIProviderChoice
+	if chainGetter, ok := s.providerGetter.(domain.AIProviderChainGetter); ok {
+		providers, err := chainGetter.GetProviderChain(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get provider: %w", err)
+		}
+		chain = providers
+	} else {
+		provider, model, err := s.providerGetter.GetProvider(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get provider: %w", err)
+		}
+		chain = []domain.AIProviderChoice{{Name: provider.GetProviderInfo().Name, Provider: provider, Model: model}}
 	}
 
-	if model == "" {
-		if intelligentProvider, ok := provider.(domain.IntelligentAIProvider); ok {
-			model, err = intelligentProvider.SelectOptimalModel(ctx, req.BaseRequest, req.Optimization.ModelSelection)
+	selected := chain[:0]
+	for i, choice := range chain {
+		if choice.Model == "" {
+			model, err := s.selectOptimalModel(ctx, choice.Provider, req)
+			if err != nil && i == 0 {
+				return nil, err
+			}
 			if err != nil {
-				return nil, "", fmt.Errorf("failed to select optimal model: %w", err)
+				s.log.Warning(fmt.Sprintf("Skipping fallback AI provider %s: %v", choice.Name, err))
+				continue
 			}
-		} else {
-			return nil, "", fmt.Errorf("no model selected for provider")
+			choice.Model = model
 		}
+		selected = append(selected, choice)
 	}
+	return selected, nil
+}
 
-	return provider, model, nil
+func (s *IntelligentService) selectOptimalModel(ctx context.Context, provider domain.AIProvider, req domain.IntelligentRequest) (string, error) {
+	intelligentProvider, ok := provider.(domain.IntelligentAIProvider)
+	if !ok {
+		return "", fmt.Errorf("no model selected for provider")
+	}
+	model, err := intelligentProvider.SelectOptimalModel(ctx, req.BaseRequest, req.Optimization.ModelSelection)
+	if err != nil {
+		return "", fmt.Errorf("failed to select optimal model: %w", err)
+	}
+	return model, nil
 }
 
 func (s *IntelligentService) optimizePrompt(task, codeContext string, options IntelligentGenerationOptions) string {
@@ -200,11 +263,6 @@ func (s *IntelligentService) buildSystemPrompt(options IntelligentGenerationOpti
 	return basePrompt
 }
 
-func (s *IntelligentService) tryFallback(_ context.Context, _ domain.AIRequest) (domain.AIResponse, error) {
-	s.log.Warning("Fallback providers not supported in current implementation")
-	return domain.AIResponse{}, fmt.Errorf("fallback not supported")
-}
-
 func (s *IntelligentService) analyzeResponse(response domain.AIResponse) domain.ResponseAnalysis {
 	analysis := domain.ResponseAnalysis{QualityScore: 0.8, RelevanceScore: 0.8, CompletenessScore: 0.8, Confidence: 0.8}
 	if strings.Contains(response.Content, "diff --git") {
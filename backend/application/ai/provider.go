@@ -18,12 +18,47 @@ func (s *Service) getProvider(_ context.Context) (domain.AIProvider, string, err
 	if err != nil {
 		return nil, "", fmt.Errorf("could not get settings: %w", err)
 	}
-
-	providerType := dto.SelectedProvider
-	if providerType == "" {
+	if dto.SelectedProvider == "" {
 		return nil, "", fmt.Errorf("no AI provider selected")
 	}
+	return s.providerFor(dto, dto.SelectedProvider)
+}
+
+// GetProviderChain returns the selected provider followed by the fallback
+// providers from settings. Fallbacks that can't be created are skipped.
+func (s *Service) GetProviderChain(_ context.Context) ([]domain.AIProviderChoice, error) {
+	dto, err := s.settingsService.GetSettingsDTO()
+	if err != nil {
+		return nil, fmt.Errorf("could not get settings: %w", err)
+	}
+	if dto.SelectedProvider == "" {
+		return nil, fmt.Errorf("no AI provider selected")
+	}
+
+	provider, model, err := s.providerFor(dto, dto.SelectedProvider)
+	if err != nil {
+		return nil, err
+	}
+	chain := []domain.AIProviderChoice{{Name: dto.SelectedProvider, Provider: provider, Model: model}}
+
+	seen := map[string]bool{dto.SelectedProvider: true}
+	for _, providerType := range dto.AIProviderFallback {
+		if providerType == "" || seen[providerType] {
+			continue
+		}
+		seen[providerType] = true
+		provider, model, err := s.providerFor(dto, providerType)
+		if err != nil {
+			s.log.Warning(fmt.Sprintf("Skipping fallback AI provider %s: %v", providerType, err))
+			continue
+		}
+		chain = append(chain, domain.AIProviderChoice{Name: providerType, Provider: provider, Model: model})
+	}
+	return chain, nil
+}
 
+// providerFor returns the cached provider of providerType, creating it on first use
+func (s *Service) providerFor(dto domain.SettingsDTO, providerType string) (domain.AIProvider, string, error) {
 	apiKey := s.getAPIKey(dto, providerType)
 	if apiKey == "" && providerType != "localai" && providerType != "qwen-cli" {
 		return nil, "", fmt.Errorf("API key for %s is not set", providerType)
@@ -90,10 +125,12 @@ func (s *Service) getModelForProvider(dto domain.SettingsDTO, providerType strin
 	return model
 }
 
-// InvalidateProviderCache clears the provider cache
+// InvalidateProviderCache clears the provider cache and the cached responses,
+// which may have come from a provider that is no longer configured
 func (s *Service) InvalidateProviderCache() {
 	s.providerCacheMu.Lock()
 	s.providerCache = make(map[string]domain.AIProvider)
 	s.providerCacheMu.Unlock()
+	s.InvalidateResponseCache()
 	s.log.Info("Provider cache invalidated")
 }
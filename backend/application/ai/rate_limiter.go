@@ -1,12 +1,30 @@
 package ai
 
 import (
+	"errors"
 	"fmt"
+	"shotgun_code/domain"
 	"sync"
 	"time"
 )
 
-// RateLimiter implements token bucket rate limiting per provider
+const (
+	// defaultRateLimitPause is how long a provider is paused after a 429
+	// response that didn't say when to retry
+	defaultRateLimitPause = 5 * time.Second
+
+	// minRateFraction is how far a provider's token rate can be tightened
+	minRateFraction = 1.0 / 64
+
+	// relaxAfterSuccesses is how many consecutive successful requests double
+	// a tightened token rate, up to the configured one
+	relaxAfterSuccesses = 20
+)
+
+// RateLimiter implements token bucket rate limiting per provider. It adapts
+// to providers' rate limits: a 429 response pauses the provider for its
+// Retry-After delay and halves its token rate, and sustained success relaxes
+// the rate again.
 type RateLimiter struct {
 	buckets map[string]*tokenBucket
 	mu      sync.RWMutex
@@ -14,9 +32,12 @@ type RateLimiter struct {
 }
 
 type tokenBucket struct {
-	tokens     float64
-	lastRefill time.Time
-	mu         sync.Mutex
+	tokens      float64
+	lastRefill  time.Time
+	rate        float64 // current tokens per second, at most the configured rate
+	pausedUntil time.Time
+	successes   int // consecutive successful requests since the last 429
+	mu          sync.Mutex
 }
 
 type rateLimitConfig struct {
@@ -24,6 +45,14 @@ type rateLimitConfig struct {
 	maxTokens       float64
 }
 
+// RateLimitState is the current state of a provider's rate limit
+type RateLimitState struct {
+	TokensPerSecond    float64   `json:"tokensPerSecond"`
+	MaxTokensPerSecond float64   `json:"maxTokensPerSecond"`
+	AvailableTokens    float64   `json:"availableTokens"`
+	PausedUntil        time.Time `json:"pausedUntil,omitempty"`
+}
+
 // NewRateLimiter creates a new rate limiter
 func NewRateLimiter() *RateLimiter {
 	return &RateLimiter{
@@ -39,31 +68,16 @@ func NewRateLimiter() *RateLimiter {
 
 // CheckLimit checks if request is within rate limit
 func (r *RateLimiter) CheckLimit(provider string) error {
-	r.mu.RLock()
-	bucket, exists := r.buckets[provider]
-	r.mu.RUnlock()
-
-	if !exists {
-		r.mu.Lock()
-		if bucket, exists = r.buckets[provider]; !exists {
-			config := r.getConfig(provider)
-			bucket = &tokenBucket{tokens: config.maxTokens, lastRefill: time.Now()}
-			r.buckets[provider] = bucket
-		}
-		r.mu.Unlock()
-	}
-
+	bucket := r.bucket(provider)
 	bucket.mu.Lock()
 	defer bucket.mu.Unlock()
 
-	config := r.getConfig(provider)
-	elapsed := time.Since(bucket.lastRefill).Seconds()
-	bucket.tokens += elapsed * config.tokensPerSecond
-	if bucket.tokens > config.maxTokens {
-		bucket.tokens = config.maxTokens
+	now := time.Now()
+	if now.Before(bucket.pausedUntil) {
+		return fmt.Errorf("provider %s is rate limited for another %v", provider, bucket.pausedUntil.Sub(now).Round(time.Millisecond))
 	}
-	bucket.lastRefill = time.Now()
 
+	r.refill(provider, bucket, now)
 	if bucket.tokens < 1 {
 		return fmt.Errorf("rate limit exceeded for provider %s, please wait", provider)
 	}
@@ -72,6 +86,119 @@ func (r *RateLimiter) CheckLimit(provider string) error {
 	return nil
 }
 
+// Observe adapts the provider's limit to the outcome of a request: rate
+// limit errors pause and tighten it, successes relax it
+func (r *RateLimiter) Observe(provider string, err error) {
+	if err == nil {
+		r.RecordSuccess(provider)
+		return
+	}
+	if !errors.Is(err, domain.ErrRateLimitExceeded) {
+		return
+	}
+	var retryAfter time.Duration
+	var providerErr *domain.ProviderError
+	if errors.As(err, &providerErr) {
+		retryAfter = providerErr.RetryAfter
+	}
+	r.RecordRateLimit(provider, retryAfter)
+}
+
+// RecordRateLimit pauses a provider that responded with 429 for retryAfter
+// (defaultRateLimitPause if 0) and halves its token rate
+func (r *RateLimiter) RecordRateLimit(provider string, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = defaultRateLimitPause
+	}
+	config := r.getConfig(provider)
+	bucket := r.bucket(provider)
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	now := time.Now()
+	r.refill(provider, bucket, now)
+	if until := now.Add(retryAfter); until.After(bucket.pausedUntil) {
+		bucket.pausedUntil = until
+	}
+	bucket.rate = max(bucket.rate/2, config.tokensPerSecond*minRateFraction)
+	bucket.tokens = 0
+	bucket.successes = 0
+}
+
+// RecordSuccess counts a successful request, doubling a tightened token rate
+// after relaxAfterSuccesses of them in a row
+func (r *RateLimiter) RecordSuccess(provider string) {
+	config := r.getConfig(provider)
+	bucket := r.bucket(provider)
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	if bucket.rate >= config.tokensPerSecond {
+		return
+	}
+	bucket.successes++
+	if bucket.successes >= relaxAfterSuccesses {
+		bucket.rate = min(bucket.rate*2, config.tokensPerSecond)
+		bucket.successes = 0
+	}
+}
+
+// State returns the rate limit state of the providers used so far
+func (r *RateLimiter) State() map[string]RateLimitState {
+	r.mu.RLock()
+	buckets := make(map[string]*tokenBucket, len(r.buckets))
+	for provider, bucket := range r.buckets {
+		buckets[provider] = bucket
+	}
+	r.mu.RUnlock()
+
+	now := time.Now()
+	state := make(map[string]RateLimitState, len(buckets))
+	for provider, bucket := range buckets {
+		bucket.mu.Lock()
+		r.refill(provider, bucket, now)
+		providerState := RateLimitState{
+			TokensPerSecond:    bucket.rate,
+			MaxTokensPerSecond: r.getConfig(provider).tokensPerSecond,
+			AvailableTokens:    bucket.tokens,
+		}
+		if now.Before(bucket.pausedUntil) {
+			providerState.PausedUntil = bucket.pausedUntil
+		}
+		bucket.mu.Unlock()
+		state[provider] = providerState
+	}
+	return state
+}
+
+// bucket returns the provider's bucket, creating a full one on first use
+func (r *RateLimiter) bucket(provider string) *tokenBucket {
+	r.mu.RLock()
+	bucket, exists := r.buckets[provider]
+	r.mu.RUnlock()
+	if exists {
+		return bucket
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if bucket, exists = r.buckets[provider]; !exists {
+		config := r.getConfig(provider)
+		bucket = &tokenBucket{tokens: config.maxTokens, lastRefill: time.Now(), rate: config.tokensPerSecond}
+		r.buckets[provider] = bucket
+	}
+	return bucket
+}
+
+// refill adds the tokens accrued since the last refill; the bucket's lock must be held
+func (r *RateLimiter) refill(provider string, bucket *tokenBucket, now time.Time) {
+	config := r.getConfig(provider)
+	bucket.rate = min(bucket.rate, config.tokensPerSecond)
+	elapsed := now.Sub(bucket.lastRefill).Seconds()
+	bucket.tokens = min(bucket.tokens+elapsed*bucket.rate, config.maxTokens)
+	bucket.lastRefill = now
+}
+
 func (r *RateLimiter) getConfig(provider string) rateLimitConfig {
 	if config, ok := r.config[provider]; ok {
 		return config
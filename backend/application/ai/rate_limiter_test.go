@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"errors"
+	"shotgun_code/domain"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_PausesOnRetryAfter(t *testing.T) {
+	limiter := NewRateLimiter()
+	require.NoError(t, limiter.CheckLimit("openai"))
+
+	limiter.Observe("openai", domain.NewProviderError("OpenAI", 429, 50*time.Millisecond, errors.New("slow down")))
+
+	require.Error(t, limiter.CheckLimit("openai"))
+	require.NoError(t, limiter.CheckLimit("gemini"), "other providers are not paused")
+
+	state := limiter.State()["openai"]
+	assert.Equal(t, 5.0, state.TokensPerSecond)
+	assert.Equal(t, 10.0, state.MaxTokensPerSecond)
+	assert.False(t, state.PausedUntil.IsZero())
+
+	time.Sleep(200 * time.Millisecond)
+	require.NoError(t, limiter.CheckLimit("openai"))
+}
+
+func TestRateLimiter_TightensAndRelaxes(t *testing.T) {
+	limiter := NewRateLimiter()
+	for range 10 {
+		limiter.RecordRateLimit("openrouter", time.Nanosecond)
+	}
+	assert.Equal(t, 5.0/64, limiter.State()["openrouter"].TokensPerSecond, "the rate is tightened down to its floor")
+
+	// Other errors don't affect the limit
+	limiter.Observe("openrouter", errors.New("invalid request"))
+	for range relaxAfterSuccesses - 1 {
+		limiter.Observe("openrouter", nil)
+	}
+	assert.Equal(t, 5.0/64, limiter.State()["openrouter"].TokensPerSecond)
+	limiter.Observe("openrouter", nil)
+	assert.Equal(t, 5.0/32, limiter.State()["openrouter"].TokensPerSecond)
+}
+
+func TestRateLimiter_Concurrent(t *testing.T) {
+	limiter := NewRateLimiter()
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = limiter.CheckLimit("openai")
+			if i%5 == 0 {
+				limiter.Observe("openai", domain.ErrRateLimitExceeded)
+			} else {
+				limiter.Observe("openai", nil)
+			}
+			_ = limiter.State()
+		}()
+	}
+	wg.Wait()
+	assert.Less(t, limiter.State()["openai"].TokensPerSecond, 10.0)
+}
+
+func TestGenerateCode_RateLimitedProviderIsSkipped(t *testing.T) {
+	primary := &stubProvider{err: domain.NewProviderError("LocalAI", 429, time.Minute, errors.New("slow down"))}
+	fallback := &stubProvider{resp: domain.AIResponse{Content: "patched"}}
+	svc := newFallbackService(primary, fallback)
+
+	_, err := svc.GenerateCode(t.Context(), "system", "task")
+	require.NoError(t, err)
+	primary.err = nil
+
+	// The primary provider is paused for its Retry-After delay
+	result, err := svc.GenerateCodeDetailed(t.Context(), "system", "other task", GenerationOptions{NoCache: true})
+	require.NoError(t, err)
+	assert.Equal(t, "qwen-cli", result.Provider)
+
+	limits := svc.GetMetrics()["rate_limits"].(map[string]RateLimitState)
+	assert.False(t, limits["localai"].PausedUntil.IsZero())
+}
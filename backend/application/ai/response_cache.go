@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"container/list"
+	"shotgun_code/domain"
+	"sync"
+	"time"
+)
+
+// CacheStats describes the AI response cache
+type CacheStats struct {
+	Entries    int     `json:"entries"`
+	MaxEntries int     `json:"maxEntries"`
+	TTLSeconds int     `json:"ttlSeconds"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	Evictions  int64   `json:"evictions"`
+	HitRate    float64 `json:"hitRate"` // 0.0 - 1.0
+}
+
+// responseCache is an LRU cache of AI completions whose entries expire after ttl
+type responseCache struct {
+	mu        sync.Mutex
+	maxSize   int
+	ttl       time.Duration
+	order     *list.List // front is the most recently used entry
+	entries   map[string]*list.Element
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+func newResponseCache(maxSize int, ttl time.Duration) *responseCache {
+	return &responseCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the live entry for key and marks it as recently used
+func (c *responseCache) get(key string) (*domain.AICacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	entry := elem.Value.(*domain.AICacheEntry)
+	if time.Since(entry.CreatedAt) >= c.ttl {
+		c.remove(elem)
+		c.misses++
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	c.hits++
+	return entry, true
+}
+
+// put stores entry, evicting the least recently used entries over maxSize
+func (c *responseCache) put(entry *domain.AICacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.Key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.Key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxSize {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+// removeExpired drops the entries older than ttl
+func (c *responseCache) removeExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		if time.Since(elem.Value.(*domain.AICacheEntry).CreatedAt) >= c.ttl {
+			c.remove(elem)
+		}
+		elem = prev
+	}
+}
+
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+func (c *responseCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*domain.AICacheEntry).Key)
+}
+
+func (c *responseCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CacheStats{
+		Entries:    c.order.Len(),
+		MaxEntries: c.maxSize,
+		TTLSeconds: int(c.ttl.Seconds()),
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRate = float64(c.hits) / float64(total)
+	}
+	return stats
+}
@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"shotgun_code/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newResponseCache(2, time.Hour)
+	now := time.Now()
+	cache.put(&domain.AICacheEntry{Key: "a", Content: "A", CreatedAt: now})
+	cache.put(&domain.AICacheEntry{Key: "b", Content: "B", CreatedAt: now})
+	_, _ = cache.get("a") // "b" becomes the least recently used
+	cache.put(&domain.AICacheEntry{Key: "c", Content: "C", CreatedAt: now})
+
+	_, found := cache.get("b")
+	assert.False(t, found)
+	entry, found := cache.get("a")
+	require.True(t, found)
+	assert.Equal(t, "A", entry.Content)
+
+	stats := cache.stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, int64(1), stats.Evictions)
+}
+
+func TestResponseCache_ExpiresEntries(t *testing.T) {
+	cache := newResponseCache(10, time.Minute)
+	cache.put(&domain.AICacheEntry{Key: "old", CreatedAt: time.Now().Add(-2 * time.Minute)})
+
+	_, found := cache.get("old")
+	assert.False(t, found)
+	assert.Zero(t, cache.stats().Entries)
+}
+
+func TestGenerateCode_CachesOnlyDeterministicRequests(t *testing.T) {
+	provider := &stubProvider{resp: domain.AIResponse{Content: "done"}}
+	svc, _ := newAuditedService(provider, &stubSettings{})
+	defer func() { _ = svc.Shutdown(context.Background()) }()
+	ctx := context.Background()
+
+	// Default temperature samples, so nothing is cached
+	_, err := svc.GenerateCode(ctx, "system", "task")
+	require.NoError(t, err)
+	_, err = svc.GenerateCode(ctx, "system", "task")
+	require.NoError(t, err)
+	assert.Zero(t, svc.GetCacheStats().Entries)
+
+	deterministic := GenerationOptions{Deterministic: true}
+	first, err := svc.GenerateCodeDetailed(ctx, "system", "task", deterministic)
+	require.NoError(t, err)
+	assert.False(t, first.Cached)
+	second, err := svc.GenerateCodeDetailed(ctx, "system", "task", deterministic)
+	require.NoError(t, err)
+	assert.True(t, second.Cached)
+	assert.Equal(t, "done", second.Content)
+	assert.Equal(t, "stub-model", second.Model)
+
+	bypass, err := svc.GenerateCodeDetailed(ctx, "system", "task", GenerationOptions{Deterministic: true, NoCache: true})
+	require.NoError(t, err)
+	assert.False(t, bypass.Cached)
+
+	svc.InvalidateProviderCache()
+	assert.Zero(t, svc.GetCacheStats().Entries)
+}
+
+func TestGenerateCode_DoesNotCacheFallbackResponses(t *testing.T) {
+	primary := &stubProvider{err: domain.NewExternalError("AI provider", errors.New("502 Bad Gateway"))}
+	fallback := &stubProvider{resp: domain.AIResponse{Content: "patched"}}
+	svc := newFallbackService(primary, fallback)
+	deterministic := GenerationOptions{Deterministic: true}
+
+	result, err := svc.GenerateCodeDetailed(t.Context(), "system", "task", deterministic)
+	require.NoError(t, err)
+	assert.Equal(t, "qwen-cli", result.Provider)
+	assert.Zero(t, svc.GetCacheStats().Entries)
+
+	// Once the primary provider is back it answers instead of the fallback's cached reply
+	primary.err, primary.resp = nil, domain.AIResponse{Content: "primary"}
+	result, err = svc.GenerateCodeDetailed(t.Context(), "system", "task", deterministic)
+	require.NoError(t, err)
+	assert.False(t, result.Cached)
+	assert.Equal(t, "primary", result.Content)
+}
@@ -18,17 +18,16 @@ type Service struct {
 	log                domain.Logger
 	providerRegistry   map[string]domain.AIProviderFactory
 	intelligentService *IntelligentService
+	auditLogger        *AuditLogger
 
 	providerCache   map[string]domain.AIProvider
 	providerCacheMu sync.RWMutex
 
-	responseCache   map[string]*cachedAIResponse
-	responseCacheMu sync.RWMutex
+	responses *responseCache
 
 	totalRequests   int64
-	cacheHits       int64
-	cacheMisses     int64
 	totalTokensUsed int64
+	requestSeq      int64
 
 	stopCh   chan struct{}
 	stopOnce sync.Once
@@ -40,12 +39,6 @@ type SettingsProvider interface {
 	GetSettingsDTO() (domain.SettingsDTO, error)
 }
 
-type cachedAIResponse struct {
-	content   string
-	timestamp time.Time
-	tokens    int
-}
-
 const (
 	maxResponseCacheSize       = 100
 	responseCacheTTL           = 30 * time.Minute
@@ -71,7 +64,7 @@ func NewService(
 		providerRegistry:   providerRegistry,
 		intelligentService: intelligentService,
 		providerCache:      make(map[string]domain.AIProvider),
-		responseCache:      make(map[string]*cachedAIResponse),
+		responses:          newResponseCache(maxResponseCacheSize, responseCacheTTL),
 		stopCh:             make(chan struct{}),
 	}
 	service.wg.Add(1)
@@ -94,9 +87,7 @@ func (s *Service) Shutdown(ctx context.Context) error {
 		return ctx.Err()
 	}
 
-	s.responseCacheMu.Lock()
-	s.responseCache = make(map[string]*cachedAIResponse)
-	s.responseCacheMu.Unlock()
+	s.responses.clear()
 
 	s.providerCacheMu.Lock()
 	s.providerCache = make(map[string]domain.AIProvider)
@@ -115,65 +106,101 @@ func (s *Service) cleanupResponseCache() {
 		case <-s.stopCh:
 			return
 		case <-ticker.C:
-			s.performCacheCleanup()
+			s.responses.removeExpired()
 		}
 	}
 }
 
-func (s *Service) performCacheCleanup() {
-	s.responseCacheMu.Lock()
-	defer s.responseCacheMu.Unlock()
-
-	now := time.Now()
-	for key, entry := range s.responseCache {
-		if now.Sub(entry.timestamp) > responseCacheTTL {
-			delete(s.responseCache, key)
-		}
+// getCacheKey hashes everything that determines a completion: provider,
+// model, prompts and sampling parameters
+func (s *Service) getCacheKey(provider, model, systemPrompt, userPrompt string, temperature float64, maxTokens int, topP float64) string {
+	h := sha256.New()
+	for _, part := range []string{provider, model, systemPrompt, userPrompt} {
+		fmt.Fprintf(h, "%d:%s", len(part), part)
 	}
+	fmt.Fprintf(h, "%.2f:%d:%.2f", temperature, maxTokens, topP)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
 
-	for len(s.responseCache) > maxResponseCacheSize {
-		oldest := time.Now()
-		oldestKey := ""
-		for key, entry := range s.responseCache {
-			if entry.timestamp.Before(oldest) {
-				oldest = entry.timestamp
-				oldestKey = key
-			}
-		}
-		if oldestKey == "" {
-			break
-		}
-		delete(s.responseCache, oldestKey)
-	}
+// GetCacheStats returns the size and hit counters of the AI response cache
+func (s *Service) GetCacheStats() CacheStats {
+	return s.responses.stats()
 }
 
-func (s *Service) getCacheKey(systemPrompt, userPrompt, model string, temperature float64, maxTokens int, topP float64) string {
-	h := sha256.New()
-	h.Write([]byte(systemPrompt))
-	h.Write([]byte(userPrompt))
-	h.Write([]byte(model))
-	fmt.Fprintf(h, "%.2f:%d:%.2f", temperature, maxTokens, topP)
-	return hex.EncodeToString(h.Sum(nil))[:16]
+// InvalidateResponseCache drops all cached AI responses
+func (s *Service) InvalidateResponseCache() {
+	s.responses.clear()
+}
+
+// NewRequestID issues a unique ID for an in-flight AI request
+func (s *Service) NewRequestID() string {
+	return fmt.Sprintf("ai_%d_%d", time.Now().Unix(), atomic.AddInt64(&s.requestSeq, 1))
+}
+
+type requestIDKey struct{}
+
+// WithRequestID attaches an AI request ID to ctx; generations record it in the audit log
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// requestIDFromContext returns the request ID attached to ctx, or fallback
+func requestIDFromContext(ctx context.Context, fallback string) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	return fallback
 }
 
 // GetMetrics returns AI service metrics
 func (s *Service) GetMetrics() map[string]any {
-	s.responseCacheMu.RLock()
-	cacheSize := len(s.responseCache)
-	s.responseCacheMu.RUnlock()
+	cache := s.responses.stats()
 
 	s.providerCacheMu.RLock()
 	providerCount := len(s.providerCache)
 	s.providerCacheMu.RUnlock()
 
-	return map[string]any{
+	metrics := map[string]any{
 		"total_requests":      atomic.LoadInt64(&s.totalRequests),
-		"cache_hits":          atomic.LoadInt64(&s.cacheHits),
-		"cache_misses":        atomic.LoadInt64(&s.cacheMisses),
+		"cache_hits":          cache.Hits,
+		"cache_misses":        cache.Misses,
 		"total_tokens_used":   atomic.LoadInt64(&s.totalTokensUsed),
-		"response_cache_size": cacheSize,
+		"response_cache_size": cache.Entries,
 		"cached_providers":    providerCount,
 	}
+	metrics["rate_limits"] = s.GetRateLimits()
+	return metrics
+}
+
+// GetRateLimits returns the adaptive rate limit state per provider
+func (s *Service) GetRateLimits() map[string]RateLimitState {
+	if limiter := s.rateLimiter(); limiter != nil {
+		return limiter.State()
+	}
+	return map[string]RateLimitState{}
+}
+
+// rateLimiter returns the per-provider rate limiter shared with the
+// intelligent service, or nil without one
+func (s *Service) rateLimiter() *RateLimiter {
+	if s.intelligentService == nil {
+		return nil
+	}
+	return s.intelligentService.rateLimiter
+}
+
+// SetAuditLogger sets the audit logger for AI requests, including those made
+// by the intelligent service
+func (s *Service) SetAuditLogger(auditLogger *AuditLogger) {
+	s.auditLogger = auditLogger
+	if s.intelligentService != nil {
+		s.intelligentService.SetAuditLogger(auditLogger)
+	}
+}
+
+// QueryAuditLog returns AI audit entries matching query, newest first
+func (s *Service) QueryAuditLog(query domain.AIAuditQuery) ([]domain.AIAuditEntry, error) {
+	return s.auditLogger.Query(query)
 }
 
 // GetIntelligentService returns the intelligent AI service
@@ -27,8 +27,8 @@ type ContainerConfig struct {
 	ContextMemoryFactory func(contextDir string) (domain.ContextMemory, error)
 	// ProjectStructureFactory creates project structure detector
 	ProjectStructureFactory func() domain.ProjectStructureDetector
-	// ReferenceFinderFactory creates reference finder
-	ReferenceFinderFactory func(registry analysis.AnalyzerRegistry) domain.ReferenceFinder
+	// ReferenceFinderFactory creates reference finder; symbolIndex is used to cross-check matches
+	ReferenceFinderFactory func(registry analysis.AnalyzerRegistry, symbolIndex analysis.SymbolIndex) domain.ReferenceFinder
 }
 
 // Container manages analysis services with lazy initialization and caching.
@@ -185,7 +185,10 @@ func (c *Container) GetReferenceFinder() domain.ReferenceFinder {
 	defer c.mu.Unlock()
 
 	if c.referenceFinder == nil && c.config.ReferenceFinderFactory != nil {
-		c.referenceFinder = c.config.ReferenceFinderFactory(c.registry)
+		if c.symbolIndex == nil && c.config.SymbolIndexFactory != nil {
+			c.symbolIndex = c.config.SymbolIndexFactory(c.registry)
+		}
+		c.referenceFinder = c.config.ReferenceFinderFactory(c.registry, c.symbolIndex)
 	}
 	return c.referenceFinder
 }
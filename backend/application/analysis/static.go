@@ -8,8 +8,9 @@ import (
 
 // StaticAnalyzerService provides high-level API for static analysis.
 type StaticAnalyzerService struct {
-	log    domain.Logger
-	engine domain.StaticAnalyzerEngine
+	log           domain.Logger
+	engine        domain.StaticAnalyzerEngine
+	languageScope *domain.LanguageScope
 }
 
 // NewStaticAnalyzerService creates a new static analyzer service.
@@ -20,8 +21,17 @@ func NewStaticAnalyzerService(log domain.Logger, engine domain.StaticAnalyzerEng
 	}
 }
 
-// AnalyzeProject performs project analysis.
+// SetLanguageScope limits analysis to the enabled languages.
+func (s *StaticAnalyzerService) SetLanguageScope(scope *domain.LanguageScope) {
+	s.languageScope = scope
+}
+
+// AnalyzeProject performs project analysis. Languages outside the enabled scope are skipped.
 func (s *StaticAnalyzerService) AnalyzeProject(ctx context.Context, projectPath string, languages []string) (*domain.StaticAnalysisReport, error) {
+	if enabled := s.languageScope.Filter(languages); len(enabled) != len(languages) {
+		s.log.Info(fmt.Sprintf("Analysis limited to enabled languages %v (requested %v)", enabled, languages))
+		languages = enabled
+	}
 	s.log.Info(fmt.Sprintf("Analyzing project: %s for languages: %v", projectPath, languages))
 
 	results, err := s.engine.AnalyzeProject(ctx, projectPath, languages)
@@ -3,6 +3,10 @@ package build
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"shotgun_code/domain"
 )
@@ -13,12 +17,14 @@ const (
 	langTypeScript = "typescript"
 	langTS         = "ts"
 	langJava       = "java"
+	langPython     = "python"
 )
 
 // Service предоставляет высокоуровневый API для работы с build pipeline
 type Service struct {
-	log      domain.Logger
-	pipeline domain.BuildPipeline
+	log       domain.Logger
+	pipeline  domain.BuildPipeline
+	languages domain.LanguageStatsDetector
 }
 
 // NewService создает новый сервис сборки
@@ -29,22 +35,28 @@ func NewService(log domain.Logger, pipeline domain.BuildPipeline) *Service {
 	}
 }
 
+// SetLanguageDetector задает общий кэширующий детектор языков;
+// по нему найденные языки упорядочиваются по доле файлов в проекте
+func (s *Service) SetLanguageDetector(languages domain.LanguageStatsDetector) {
+	s.languages = languages
+}
+
 // Build выполняет сборку проекта
-func (s *Service) Build(ctx context.Context, projectPath, language string) (*domain.BuildResult, error) {
+func (s *Service) Build(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, error) {
 	s.log.Info(fmt.Sprintf("Building %s project at %s", language, projectPath))
-	return s.pipeline.Build(ctx, projectPath, language)
+	return s.pipeline.Build(ctx, projectPath, language, target)
 }
 
 // TypeCheck выполняет проверку типов
-func (s *Service) TypeCheck(ctx context.Context, projectPath, language string) (*domain.TypeCheckResult, error) {
+func (s *Service) TypeCheck(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.TypeCheckResult, error) {
 	s.log.Info(fmt.Sprintf("Type checking %s project at %s", language, projectPath))
-	return s.pipeline.TypeCheck(ctx, projectPath, language)
+	return s.pipeline.TypeCheck(ctx, projectPath, language, target)
 }
 
 // BuildAndTypeCheck выполняет сборку и проверку типов
-func (s *Service) BuildAndTypeCheck(ctx context.Context, projectPath, language string) (*domain.BuildResult, *domain.TypeCheckResult, error) {
+func (s *Service) BuildAndTypeCheck(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, *domain.TypeCheckResult, error) {
 	s.log.Info(fmt.Sprintf("Building and type checking %s project at %s", language, projectPath))
-	return s.pipeline.BuildAndTypeCheck(ctx, projectPath, language)
+	return s.pipeline.BuildAndTypeCheck(ctx, projectPath, language, target)
 }
 
 // BuildMultiLanguage выполняет сборку для нескольких языков
@@ -53,7 +65,7 @@ func (s *Service) BuildMultiLanguage(ctx context.Context, projectPath string, la
 
 	results := make(map[string]*domain.BuildResult)
 	for _, language := range languages {
-		result, err := s.Build(ctx, projectPath, language)
+		result, err := s.Build(ctx, projectPath, language, domain.BuildTarget{})
 		if err != nil {
 			s.log.Warning(fmt.Sprintf("Failed to build %s: %v", language, err))
 			continue
@@ -69,7 +81,7 @@ func (s *Service) TypeCheckMultiLanguage(ctx context.Context, projectPath string
 
 	results := make(map[string]*domain.TypeCheckResult)
 	for _, language := range languages {
-		result, err := s.TypeCheck(ctx, projectPath, language)
+		result, err := s.TypeCheck(ctx, projectPath, language, domain.BuildTarget{})
 		if err != nil {
 			s.log.Warning(fmt.Sprintf("Failed to type check %s: %v", language, err))
 			continue
@@ -92,14 +104,14 @@ func (s *Service) ValidateProject(ctx context.Context, projectPath string, langu
 	for _, language := range languages {
 		langResult := &domain.LanguageValidationResult{Language: language}
 
-		typeCheckResult, err := s.TypeCheck(ctx, projectPath, language)
+		typeCheckResult, err := s.TypeCheck(ctx, projectPath, language, domain.BuildTarget{})
 		if err != nil {
 			langResult.TypeCheckError = err.Error()
 		} else {
 			langResult.TypeCheckResult = typeCheckResult
 		}
 
-		buildResult, err := s.Build(ctx, projectPath, language)
+		buildResult, err := s.Build(ctx, projectPath, language, domain.BuildTarget{})
 		if err != nil {
 			langResult.BuildError = err.Error()
 		} else {
@@ -136,7 +148,8 @@ func (s *Service) DetectLanguages(ctx context.Context, projectPath string) ([]st
 	for _, language := range supportedLanguages {
 		switch language {
 		case langGo:
-			if s.hasFile(projectPath, "go.mod") {
+			// go.work объединяет модули рабочего пространства; сборка проходит по каждому из них
+			if s.hasFile(projectPath, "go.mod") || s.hasFile(projectPath, "go.work") {
 				detectedLanguages = append(detectedLanguages, language)
 			}
 		case langTypeScript, langTS:
@@ -147,13 +160,42 @@ func (s *Service) DetectLanguages(ctx context.Context, projectPath string) ([]st
 			if s.hasFile(projectPath, "pom.xml") || s.hasFile(projectPath, "build.gradle") {
 				detectedLanguages = append(detectedLanguages, language)
 			}
+		case langPython:
+			if s.hasFile(projectPath, "pyproject.toml") || s.hasFile(projectPath, "requirements.txt") {
+				detectedLanguages = append(detectedLanguages, language)
+			}
 		}
 	}
 
+	detectedLanguages = s.sortByFileShare(projectPath, detectedLanguages)
 	s.log.Info(fmt.Sprintf("Detected languages: %v", detectedLanguages))
 	return detectedLanguages, nil
 }
 
+// sortByFileShare упорядочивает языки по убыванию доли файлов, сохраняя исходный порядок при равенстве
+func (s *Service) sortByFileShare(projectPath string, languages []string) []string {
+	if s.languages == nil || len(languages) < 2 {
+		return languages
+	}
+
+	counts := make(map[string]int)
+	for _, info := range s.languages.Detect(projectPath) {
+		counts[strings.ToLower(info.Name)] = info.FileCount
+	}
+	fileCount := func(language string) int {
+		if language == langTS {
+			language = langTypeScript
+		}
+		return counts[language]
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return fileCount(languages[i]) > fileCount(languages[j])
+	})
+	return languages
+}
+
 func (s *Service) hasFile(projectPath, filename string) bool {
-	return true // Placeholder - real implementation should use os.Stat
+	_, err := os.Stat(filepath.Join(projectPath, filename))
+	return err == nil
 }
@@ -9,8 +9,9 @@ import (
 
 // TestService предоставляет высокоуровневый API для работы с тестами
 type TestService struct {
-	log        domain.Logger
-	testEngine domain.TestEngine
+	log           domain.Logger
+	testEngine    domain.TestEngine
+	languageScope *domain.LanguageScope
 }
 
 // NewTestService создает новый сервис тестирования
@@ -21,8 +22,16 @@ func NewTestService(log domain.Logger, testEngine domain.TestEngine) *TestServic
 	}
 }
 
+// SetLanguageScope ограничивает запуск тестов включенными языками
+func (s *TestService) SetLanguageScope(scope *domain.LanguageScope) {
+	s.languageScope = scope
+}
+
 // RunTests выполняет тесты согласно конфигурации
 func (s *TestService) RunTests(ctx context.Context, config *domain.TestConfig) ([]*domain.TestResult, error) {
+	if s.skipLanguage(config.Language) {
+		return []*domain.TestResult{}, nil
+	}
 	s.log.Info(fmt.Sprintf("Running tests with scope: %s", config.Scope))
 
 	if config.Scope == domain.TestScopeAffected || config.Scope == domain.TestScopeAffectedSmoke {
@@ -39,6 +48,9 @@ func (s *TestService) RunTests(ctx context.Context, config *domain.TestConfig) (
 
 // RunTargetedTests выполняет целевые тесты для затронутых файлов
 func (s *TestService) RunTargetedTests(ctx context.Context, config *domain.TestConfig, changedFiles []string) ([]*domain.TestResult, error) {
+	if s.skipLanguage(config.Language) {
+		return []*domain.TestResult{}, nil
+	}
 	s.log.Info(fmt.Sprintf("Running targeted tests for %d changed files", len(changedFiles)))
 
 	affectedGraph, err := s.testEngine.BuildAffectedGraph(ctx, changedFiles, config.ProjectPath)
@@ -127,3 +139,12 @@ func (s *TestService) ValidateTestResults(results []*domain.TestResult) *domain.
 	}
 	return validation
 }
+
+// skipLanguage сообщает, что язык выключен в настройках и тесты для него не запускаются
+func (s *TestService) skipLanguage(language string) bool {
+	if language == "" || s.languageScope.Enabled(language) {
+		return false
+	}
+	s.log.Info(fmt.Sprintf("Skipping %s tests: language is not enabled", language))
+	return true
+}
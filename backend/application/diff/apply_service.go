@@ -3,15 +3,20 @@ package diff
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"shotgun_code/domain"
 )
 
 // ApplyService предоставляет высокоуровневый API для применения правок
 type ApplyService struct {
-	log    domain.Logger
-	engine domain.ApplyEngine
-	config *domain.ApplyEngineConfig
+	log        domain.Logger
+	engine     domain.ApplyEngine
+	config     *domain.ApplyEngineConfig
+	guardrails domain.GuardrailService
+	safeMode   *domain.SafeMode
 }
 
 // NewApplyService создает новый сервис применения
@@ -31,8 +36,27 @@ func NewApplyService(
 	return &ApplyService{log: log, engine: engine, config: config}
 }
 
+// SetGuardrails включает проверку путей правок политиками guardrails перед записью
+func (s *ApplyService) SetGuardrails(guardrails domain.GuardrailService) {
+	s.guardrails = guardrails
+}
+
+// SetSafeMode подключает переключатель safe mode, блокирующий запись файлов
+func (s *ApplyService) SetSafeMode(mode *domain.SafeMode) {
+	s.safeMode = mode
+}
+
 // ApplyEdits применяет правки из Edits JSON
 func (s *ApplyService) ApplyEdits(ctx context.Context, edits *domain.EditsJSON) ([]*domain.ApplyResult, error) {
+	return s.ApplyEditsInProject(ctx, edits, "")
+}
+
+// ApplyEditsInProject применяет правки проекта projectRoot: пути правок, в том
+// числе абсолютные, проверяются guardrails относительно корня проекта
+func (s *ApplyService) ApplyEditsInProject(ctx context.Context, edits *domain.EditsJSON, projectRoot string) ([]*domain.ApplyResult, error) {
+	if err := s.safeMode.Check("applying edits"); err != nil {
+		return nil, err
+	}
 	s.log.Info(fmt.Sprintf("Applying %d edits", len(edits.Edits)))
 
 	operations := make([]*domain.ApplyOperation, 0, len(edits.Edits))
@@ -40,6 +64,12 @@ func (s *ApplyService) ApplyEdits(ctx context.Context, edits *domain.EditsJSON)
 		op := s.editToOperation(edit)
 		operations = append(operations, op)
 	}
+	// Ни одна правка не записывается, если хотя бы один путь запрещен
+	for _, op := range operations {
+		if err := s.checkPath(projectRoot, op.Path); err != nil {
+			return nil, err
+		}
+	}
 
 	results, err := s.engine.ApplyOperations(ctx, operations)
 	if err != nil {
@@ -59,9 +89,86 @@ func (s *ApplyService) ApplyEdits(ctx context.Context, edits *domain.EditsJSON)
 	return results, nil
 }
 
+// PreviewEdits применяет правки в режиме dry-run: файлы проекта копируются в
+// previewDir и правки применяются к копиям, проект не изменяется. Пути правок
+// задаются относительно projectRoot; в результатах остаются исходные пути,
+// а путь копии лежит в Metadata["previewPath"]
+func (s *ApplyService) PreviewEdits(ctx context.Context, edits *domain.EditsJSON, projectRoot, previewDir string) ([]*domain.ApplyResult, error) {
+	s.log.Info(fmt.Sprintf("Previewing %d edits in %s", len(edits.Edits), previewDir))
+
+	operations := make([]*domain.ApplyOperation, 0, len(edits.Edits))
+	projectPaths := make(map[string]string, len(edits.Edits))
+	for _, edit := range edits.Edits {
+		op := s.editToOperation(edit)
+		if err := s.checkPath(projectRoot, op.Path); err != nil {
+			return nil, err
+		}
+		rel, err := ProjectRelPath(projectRoot, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		previewPath := filepath.Join(previewDir, rel)
+		if err := copyForPreview(filepath.Join(projectRoot, rel), previewPath); err != nil {
+			return nil, fmt.Errorf("failed to prepare preview of %s: %w", rel, err)
+		}
+		projectPaths[previewPath] = op.Path
+		op.Path = previewPath
+		operations = append(operations, op)
+	}
+
+	results, err := s.engine.ApplyOperations(ctx, operations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview operations: %w", err)
+	}
+	for _, result := range results {
+		if projectPath, ok := projectPaths[result.Path]; ok {
+			if result.Metadata == nil {
+				result.Metadata = make(map[string]interface{})
+			}
+			result.Metadata["previewPath"] = result.Path
+			result.Path = projectPath
+		}
+	}
+	return results, nil
+}
+
+// ProjectRelPath возвращает путь правки относительно projectRoot; пути вне проекта запрещены
+func ProjectRelPath(projectRoot, path string) (string, error) {
+	abs := path
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(projectRoot, path)
+	}
+	rel, err := filepath.Rel(projectRoot, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", domain.NewValidationError(fmt.Sprintf("edit path is outside the project: %s", path), nil)
+	}
+	return rel, nil
+}
+
+// copyForPreview копирует существующий файл проекта в каталог предпросмотра
+func copyForPreview(src, dst string) error {
+	content, err := os.ReadFile(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, content, 0o644)
+}
+
 // ApplySingleEdit применяет одну правку
 func (s *ApplyService) ApplySingleEdit(ctx context.Context, edit *domain.Edit) (*domain.ApplyResult, error) {
+	if err := s.safeMode.Check("applying edits"); err != nil {
+		return nil, err
+	}
 	op := s.editToOperation(edit)
+	if err := s.checkPath("", op.Path); err != nil {
+		return nil, err
+	}
 	return s.engine.ApplyOperation(ctx, op)
 }
 
@@ -69,6 +176,9 @@ func (s *ApplyService) ApplySingleEdit(ctx context.Context, edit *domain.Edit) (
 func (s *ApplyService) ValidateEdits(ctx context.Context, edits *domain.EditsJSON) error {
 	for _, edit := range edits.Edits {
 		op := s.editToOperation(edit)
+		if err := s.checkPath("", op.Path); err != nil {
+			return err
+		}
 		if err := s.engine.ValidateOperation(ctx, op); err != nil {
 			return fmt.Errorf("validation failed for edit %s: %w", edit.ID, err)
 		}
@@ -78,6 +188,9 @@ func (s *ApplyService) ValidateEdits(ctx context.Context, edits *domain.EditsJSO
 
 // RollbackEdits откатывает правки
 func (s *ApplyService) RollbackEdits(ctx context.Context, results []*domain.ApplyResult) error {
+	if err := s.safeMode.Check("rolling back edits"); err != nil {
+		return err
+	}
 	for i := len(results) - 1; i >= 0; i-- {
 		result := results[i]
 		if result.Success {
@@ -106,6 +219,38 @@ func (s *ApplyService) UpdateConfig(config *domain.ApplyEngineConfig) {
 	s.log.Info("Updated apply engine configuration")
 }
 
+// checkPath возвращает ошибку, если guardrails блокируют правку пути. Если
+// задан projectRoot, путь проверяется относительно него: правила политик -
+// относительные glob-паттерны и с абсолютными путями не совпадают
+func (s *ApplyService) checkPath(projectRoot, path string) error {
+	if s.guardrails == nil {
+		return nil
+	}
+	policyPath := path
+	if projectRoot != "" {
+		rel, err := ProjectRelPath(projectRoot, path)
+		if err != nil {
+			return err
+		}
+		policyPath = rel
+	}
+	violations, err := s.guardrails.ValidatePath(policyPath)
+	blocking := make([]domain.GuardrailViolation, 0, len(violations))
+	for _, v := range violations {
+		if v.Severity == domain.GuardrailSeverityBlock {
+			blocking = append(blocking, v)
+		}
+	}
+	if err != nil && len(blocking) == 0 {
+		blocking = violations
+	}
+	if err != nil || len(blocking) > 0 {
+		s.log.Warning(fmt.Sprintf("Edit of %s blocked by guardrails", path))
+		return domain.NewPathBlockedError(path, blocking)
+	}
+	return nil
+}
+
 func (s *ApplyService) editToOperation(edit *domain.Edit) *domain.ApplyOperation {
 	op := &domain.ApplyOperation{
 		ID:        edit.ID,
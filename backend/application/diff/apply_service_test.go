@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"shotgun_code/application/guardrails"
+	"shotgun_code/domain"
+	"shotgun_code/infrastructure/policy"
+	"shotgun_code/testutils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestApplyEdits_BlocksDeniedPath(t *testing.T) {
+	log := &domain.NoopLogger{}
+	engine := &testutils.MockApplyEngine{}
+	service := NewApplyService(log, &domain.ApplyEngineConfig{}, engine, nil, nil)
+	service.SetGuardrails(guardrails.NewService(log, policy.NewOPAService(log), nil))
+
+	edits := &domain.EditsJSON{Edits: []*domain.Edit{
+		{ID: "e1", Kind: "full", Op: "replace", Path: "main.go", Content: "package main\n"},
+		{ID: "e2", Kind: "full", Op: "replace", Path: "go.sum", Content: "example.com/x v1.0.0 h1:abc=\n"},
+	}}
+	_, err := service.ApplyEdits(context.Background(), edits)
+
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeGuardrailViolation {
+		t.Fatalf("expected guardrail violation, got %v", err)
+	}
+	if domainErr.Context["path"] != "go.sum" {
+		t.Errorf("expected go.sum to be blocked, got %v", domainErr.Context["path"])
+	}
+	violations, _ := domainErr.Context["violations"].([]domain.GuardrailViolation)
+	if len(violations) == 0 || violations[0].Context["matched_rule"] != "deny:go.sum" {
+		t.Errorf("expected matched rule deny:go.sum, got %+v", violations)
+	}
+	// Nothing is written, not even the allowed edit
+	engine.AssertNotCalled(t, "ApplyOperations", mock.Anything, mock.Anything)
+}
+
+func TestApplyEditsInProject_BlocksDeniedAbsolutePath(t *testing.T) {
+	log := &domain.NoopLogger{}
+	engine := &testutils.MockApplyEngine{}
+	service := NewApplyService(log, &domain.ApplyEngineConfig{}, engine, nil, nil)
+	service.SetGuardrails(guardrails.NewService(log, policy.NewOPAService(log), nil))
+
+	// The default "vendor/**" rule is relative to the project root
+	projectRoot := t.TempDir()
+	deniedPath := filepath.Join(projectRoot, "vendor", "example.com", "lib", "lib.go")
+	edits := &domain.EditsJSON{Edits: []*domain.Edit{
+		{ID: "e1", Kind: "full", Op: "replace", Path: filepath.Join(projectRoot, "main.go"), Content: "package main\n"},
+		{ID: "e2", Kind: "full", Op: "replace", Path: deniedPath, Content: "package lib\n"},
+	}}
+	_, err := service.ApplyEditsInProject(context.Background(), edits, projectRoot)
+
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeGuardrailViolation {
+		t.Fatalf("expected guardrail violation, got %v", err)
+	}
+	if domainErr.Context["path"] != deniedPath {
+		t.Errorf("expected %s to be blocked, got %v", deniedPath, domainErr.Context["path"])
+	}
+	engine.AssertNotCalled(t, "ApplyOperations", mock.Anything, mock.Anything)
+
+	// Preview checks absolute paths the same way
+	_, err = service.PreviewEdits(context.Background(), edits, projectRoot, t.TempDir())
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeGuardrailViolation {
+		t.Fatalf("expected preview to be blocked, got %v", err)
+	}
+}
+
+func TestApplyEdits_BlockedInSafeMode(t *testing.T) {
+	engine := &testutils.MockApplyEngine{}
+	service := NewApplyService(&domain.NoopLogger{}, &domain.ApplyEngineConfig{}, engine, nil, nil)
+	service.SetSafeMode(domain.NewSafeMode(true))
+
+	edits := &domain.EditsJSON{Edits: []*domain.Edit{
+		{ID: "e1", Kind: "full", Op: "replace", Path: "main.go", Content: "package main\n"},
+	}}
+	_, err := service.ApplyEdits(context.Background(), edits)
+
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeSafeModeEnabled {
+		t.Fatalf("expected safe mode error, got %v", err)
+	}
+	if _, err := service.ApplySingleEdit(context.Background(), edits.Edits[0]); err == nil {
+		t.Error("expected ApplySingleEdit to be blocked")
+	}
+	if err := service.RollbackEdits(context.Background(), []*domain.ApplyResult{{Success: true}}); err == nil {
+		t.Error("expected RollbackEdits to be blocked")
+	}
+	engine.AssertNotCalled(t, "ApplyOperations", mock.Anything, mock.Anything)
+	engine.AssertNotCalled(t, "ApplyOperation", mock.Anything, mock.Anything)
+	engine.AssertNotCalled(t, "RollbackOperation", mock.Anything, mock.Anything)
+}
+
+func TestPreviewEdits_RejectsPathOutsideProject(t *testing.T) {
+	engine := &testutils.MockApplyEngine{}
+	service := NewApplyService(&domain.NoopLogger{}, &domain.ApplyEngineConfig{}, engine, nil, nil)
+
+	edits := &domain.EditsJSON{Edits: []*domain.Edit{
+		{ID: "e1", Kind: "fullFile", Op: "modify", Path: "../outside.go", Content: "package x\n"},
+	}}
+	_, err := service.PreviewEdits(context.Background(), edits, t.TempDir(), t.TempDir())
+
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeValidationError {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	engine.AssertNotCalled(t, "ApplyOperations", mock.Anything, mock.Anything)
+}
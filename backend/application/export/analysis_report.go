@@ -0,0 +1,365 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"shotgun_code/domain"
+	"sort"
+	"strings"
+)
+
+const (
+	// analysisIssuesPerPage caps the issues listed on one report page
+	analysisIssuesPerPage = 200
+	// analysisTopOffenders is the number of files listed as top offenders
+	analysisTopOffenders = 10
+)
+
+// Analysis report formats
+const (
+	AnalysisReportPDF  = "pdf"
+	AnalysisReportHTML = "html"
+)
+
+// severityOrder lists severities from most to least important
+var severityOrder = []string{"error", "warning", "info", "hint"}
+
+// analysisIssue is an issue flattened out of the per-language results
+type analysisIssue struct {
+	domain.StaticIssue
+	Analyzer string
+}
+
+// severityCount is the number of issues of one severity
+type severityCount struct {
+	Severity string
+	Count    int
+}
+
+// fileCount is the number of issues in one file
+type fileCount struct {
+	File   string
+	Errors int
+	Total  int
+}
+
+// issueGroup is a run of issues with the same severity and file on a page
+type issueGroup struct {
+	Severity string
+	File     string
+	Issues   []analysisIssue
+}
+
+// analysisPage is one page of the issue listing
+type analysisPage struct {
+	Number int
+	Groups []issueGroup
+}
+
+// analysisSummary is the rendering model of a static analysis report
+type analysisSummary struct {
+	ProjectPath   string
+	Timestamp     string
+	Languages     []string
+	Total         int
+	FilesAffected int
+	BySeverity    []severityCount
+	TopOffenders  []fileCount
+	Pages         []analysisPage
+}
+
+// ExportAnalysisReport renders a static analysis report as PDF or HTML into a
+// new temporary directory and returns the path of the report. Issues are
+// grouped by severity and file and split into pages of analysisIssuesPerPage;
+// an HTML report is written as one file per page, linked to each other.
+func (s *Service) ExportAnalysisReport(report *domain.StaticAnalysisReport, format string) (string, error) {
+	format = strings.ToLower(format)
+	if format != AnalysisReportPDF && format != AnalysisReportHTML {
+		return "", fmt.Errorf("unsupported analysis report format: %s", format)
+	}
+
+	summary := summarizeAnalysis(report)
+
+	tempDir, err := s.tempFileProvider.MkdirTemp("", "shotgun-analysis-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	var outputPath string
+	if format == AnalysisReportPDF {
+		outputPath = s.pathProvider.Join(tempDir, "analysis-report.pdf")
+		err = s.pdf.WriteAtomic(renderAnalysisText(summary), domain.PDFOptions{PageNumbers: true}, outputPath)
+	} else {
+		outputPath, err = s.writeAnalysisHTML(summary, tempDir)
+	}
+	if err != nil {
+		_ = s.fileSystemWriter.RemoveAll(tempDir)
+		return "", fmt.Errorf("failed to write analysis report: %w", err)
+	}
+
+	s.log.Info(fmt.Sprintf("Exported %s analysis report with %d issues to %s", format, summary.Total, outputPath))
+	return outputPath, nil
+}
+
+// summarizeAnalysis sorts the report's issues and builds the counts and pages
+func summarizeAnalysis(report *domain.StaticAnalysisReport) analysisSummary {
+	summary := analysisSummary{ProjectPath: report.ProjectPath, Timestamp: report.Timestamp}
+
+	var issues []analysisIssue
+	for _, language := range sortedKeys(report.Results) {
+		result := report.Results[language]
+		if result == nil {
+			continue
+		}
+		summary.Languages = append(summary.Languages, language)
+		for _, issue := range result.Issues {
+			if issue == nil {
+				continue
+			}
+			flat := analysisIssue{StaticIssue: *issue, Analyzer: string(result.Analyzer)}
+			flat.Severity = strings.ToLower(flat.Severity)
+			flat.File = relativeTo(report.ProjectPath, flat.File)
+			issues = append(issues, flat)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if ra, rb := severityRank(a.Severity), severityRank(b.Severity); ra != rb {
+			return ra < rb
+		}
+		if a.Severity != b.Severity {
+			return a.Severity < b.Severity
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	summary.Total = len(issues)
+
+	severities := make(map[string]int)
+	files := make(map[string]*fileCount)
+	for _, issue := range issues {
+		severities[issue.Severity]++
+		fc, ok := files[issue.File]
+		if !ok {
+			fc = &fileCount{File: issue.File}
+			files[issue.File] = fc
+		}
+		fc.Total++
+		if issue.Severity == "error" {
+			fc.Errors++
+		}
+	}
+	for _, severity := range severityOrder {
+		summary.BySeverity = append(summary.BySeverity, severityCount{Severity: severity, Count: severities[severity]})
+		delete(severities, severity)
+	}
+	for _, severity := range sortedKeys(severities) {
+		summary.BySeverity = append(summary.BySeverity, severityCount{Severity: severity, Count: severities[severity]})
+	}
+
+	summary.FilesAffected = len(files)
+	for _, fc := range files {
+		summary.TopOffenders = append(summary.TopOffenders, *fc)
+	}
+	sort.Slice(summary.TopOffenders, func(i, j int) bool {
+		a, b := summary.TopOffenders[i], summary.TopOffenders[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		return a.File < b.File
+	})
+	if len(summary.TopOffenders) > analysisTopOffenders {
+		summary.TopOffenders = summary.TopOffenders[:analysisTopOffenders]
+	}
+
+	for start := 0; start < len(issues); start += analysisIssuesPerPage {
+		end := min(start+analysisIssuesPerPage, len(issues))
+		summary.Pages = append(summary.Pages, analysisPage{
+			Number: len(summary.Pages) + 1,
+			Groups: groupIssues(issues[start:end]),
+		})
+	}
+	return summary
+}
+
+// groupIssues splits sorted issues into runs with the same severity and file
+func groupIssues(issues []analysisIssue) []issueGroup {
+	var groups []issueGroup
+	for _, issue := range issues {
+		last := len(groups) - 1
+		if last < 0 || groups[last].Severity != issue.Severity || groups[last].File != issue.File {
+			groups = append(groups, issueGroup{Severity: issue.Severity, File: issue.File})
+			last++
+		}
+		groups[last].Issues = append(groups[last].Issues, issue)
+	}
+	return groups
+}
+
+func severityRank(severity string) int {
+	for i, s := range severityOrder {
+		if s == severity {
+			return i
+		}
+	}
+	return len(severityOrder)
+}
+
+// relativeTo shortens file paths inside the project to project-relative ones
+func relativeTo(projectPath, file string) string {
+	if projectPath == "" || !filepath.IsAbs(file) {
+		return file
+	}
+	if rel, err := filepath.Rel(projectPath, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// renderAnalysisText renders the summary as plain text for the PDF generator;
+// each page of issues starts on a new PDF page
+func renderAnalysisText(summary analysisSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "STATIC ANALYSIS REPORT\n\n")
+	fmt.Fprintf(&b, "Project:   %s\n", summary.ProjectPath)
+	if summary.Timestamp != "" {
+		fmt.Fprintf(&b, "Generated: %s\n", summary.Timestamp)
+	}
+	if len(summary.Languages) > 0 {
+		fmt.Fprintf(&b, "Languages: %s\n", strings.Join(summary.Languages, ", "))
+	}
+	fmt.Fprintf(&b, "\nIssues: %d in %d files\n\n", summary.Total, summary.FilesAffected)
+
+	b.WriteString("BY SEVERITY\n")
+	for _, sc := range summary.BySeverity {
+		fmt.Fprintf(&b, "  %-10s %6d\n", sc.Severity, sc.Count)
+	}
+
+	if len(summary.TopOffenders) > 0 {
+		b.WriteString("\nTOP OFFENDERS\n")
+		for i, fc := range summary.TopOffenders {
+			fmt.Fprintf(&b, "  %2d. %s (%d issues, %d errors)\n", i+1, fc.File, fc.Total, fc.Errors)
+		}
+	}
+
+	for _, page := range summary.Pages {
+		fmt.Fprintf(&b, "\fISSUES (page %d of %d)\n", page.Number, len(summary.Pages))
+		for _, group := range page.Groups {
+			fmt.Fprintf(&b, "\n[%s] %s\n", strings.ToUpper(group.Severity), group.File)
+			for _, issue := range group.Issues {
+				fmt.Fprintf(&b, "  %d:%d  %s", issue.Line, issue.Column, issue.Message)
+				if issue.Code != "" {
+					fmt.Fprintf(&b, " (%s)", issue.Code)
+				}
+				b.WriteByte('\n')
+			}
+		}
+	}
+	return b.String()
+}
+
+// analysisPageFile returns the file name of an HTML report page
+func analysisPageFile(number int) string {
+	if number <= 1 {
+		return "analysis-report.html"
+	}
+	return fmt.Sprintf("analysis-report-%d.html", number)
+}
+
+var analysisHTMLTemplate = template.Must(template.New("analysis").Funcs(template.FuncMap{
+	"pageFile": analysisPageFile,
+	"upper":    strings.ToUpper,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Static analysis report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #14161c; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d4dc; padding: .3rem .6rem; text-align: left; }
+h3 { margin-bottom: .3rem; font-size: 1rem; }
+.error { color: #c62828; } .warning { color: #b26a00; } .info, .hint { color: #1565c0; }
+code { font-size: .9em; }
+nav { margin: 1rem 0; }
+</style>
+</head>
+<body>
+<h1>Static analysis report</h1>
+<p>Project: <code>{{.Summary.ProjectPath}}</code>{{if .Summary.Timestamp}}<br>Generated: {{.Summary.Timestamp}}{{end}}{{if .Summary.Languages}}<br>Languages: {{range $i, $l := .Summary.Languages}}{{if $i}}, {{end}}{{$l}}{{end}}{{end}}</p>
+<p><strong>{{.Summary.Total}}</strong> issues in <strong>{{.Summary.FilesAffected}}</strong> files</p>
+{{if eq .Page.Number 1}}
+<h2>By severity</h2>
+<table>
+<tr><th>Severity</th><th>Issues</th></tr>
+{{range .Summary.BySeverity}}<tr><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+{{if .Summary.TopOffenders}}
+<h2>Top offenders</h2>
+<table>
+<tr><th>File</th><th>Issues</th><th>Errors</th></tr>
+{{range .Summary.TopOffenders}}<tr><td><code>{{.File}}</code></td><td>{{.Total}}</td><td>{{.Errors}}</td></tr>
+{{end}}</table>
+{{end}}
+{{end}}
+{{if .Page.Groups}}
+<h2>Issues (page {{.Page.Number}} of {{len .Summary.Pages}})</h2>
+{{range .Page.Groups}}
+<h3><span class="{{.Severity}}">{{upper .Severity}}</span> <code>{{.File}}</code></h3>
+<table>
+<tr><th>Line</th><th>Message</th><th>Code</th><th>Analyzer</th></tr>
+{{range .Issues}}<tr><td>{{.Line}}:{{.Column}}</td><td>{{.Message}}</td><td>{{.Code}}</td><td>{{.Analyzer}}</td></tr>
+{{end}}</table>
+{{end}}
+{{end}}
+{{if gt (len .Summary.Pages) 1}}
+<nav>{{if gt .Page.Number 1}}<a href="{{pageFile .Prev}}">&laquo; Previous</a> {{end}}Page {{.Page.Number}} of {{len .Summary.Pages}}{{if lt .Page.Number (len .Summary.Pages)}} <a href="{{pageFile .Next}}">Next &raquo;</a>{{end}}</nav>
+{{end}}
+</body>
+</html>
+`))
+
+// writeAnalysisHTML writes one HTML file per page into dir and returns the
+// path of the first page
+func (s *Service) writeAnalysisHTML(summary analysisSummary, dir string) (string, error) {
+	pages := summary.Pages
+	if len(pages) == 0 {
+		pages = []analysisPage{{Number: 1}}
+	}
+
+	for _, page := range pages {
+		var buf bytes.Buffer
+		err := analysisHTMLTemplate.Execute(&buf, map[string]interface{}{
+			"Summary": summary,
+			"Page":    page,
+			"Prev":    page.Number - 1,
+			"Next":    page.Number + 1,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to render page %d: %w", page.Number, err)
+		}
+		if err := s.fileSystemWriter.WriteFile(s.pathProvider.Join(dir, analysisPageFile(page.Number)), buf.Bytes(), 0o644); err != nil {
+			return "", err
+		}
+	}
+	return s.pathProvider.Join(dir, analysisPageFile(1)), nil
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"strings"
+	"testing"
+)
+
+type osTempFiles struct{ dir string }
+
+func (t osTempFiles) MkdirTemp(_, pattern string) (string, error) {
+	return os.MkdirTemp(t.dir, pattern)
+}
+
+// osPaths and osWriter implement what ExportAnalysisReport uses
+type osPaths struct{ domain.PathProvider }
+
+func (osPaths) Join(elem ...string) string { return filepath.Join(elem...) }
+
+type osWriter struct{ domain.FileSystemWriter }
+
+func (osWriter) WriteFile(name string, data []byte, perm int) error {
+	return os.WriteFile(name, data, os.FileMode(perm))
+}
+
+func (osWriter) RemoveAll(path string) error { return os.RemoveAll(path) }
+
+func analysisReportFixture(issueCount int) *domain.StaticAnalysisReport {
+	goIssues := []*domain.StaticIssue{
+		{File: "/proj/main.go", Line: 3, Column: 2, Severity: "warning", Message: "unused variable"},
+		{File: "/proj/main.go", Line: 1, Column: 1, Severity: "error", Message: "syntax error", Code: "E1"},
+	}
+	for i := 0; i < issueCount; i++ {
+		goIssues = append(goIssues, &domain.StaticIssue{
+			File: "/proj/big.go", Line: i + 1, Severity: "info", Message: fmt.Sprintf("note %d", i),
+		})
+	}
+	return &domain.StaticAnalysisReport{
+		ProjectPath: "/proj",
+		Results: map[string]*domain.StaticAnalysisResult{
+			"go": {Analyzer: domain.StaticAnalyzerType("staticcheck"), Issues: goIssues},
+			"ts": {Analyzer: domain.StaticAnalyzerType("eslint"), Issues: []*domain.StaticIssue{
+				{File: "/proj/web/app.ts", Line: 7, Column: 4, Severity: "error", Message: "<script> not allowed"},
+			}},
+		},
+	}
+}
+
+func TestSummarizeAnalysis(t *testing.T) {
+	summary := summarizeAnalysis(analysisReportFixture(analysisIssuesPerPage))
+
+	if summary.Total != analysisIssuesPerPage+3 || summary.FilesAffected != 3 {
+		t.Fatalf("unexpected totals: %d issues in %d files", summary.Total, summary.FilesAffected)
+	}
+	if summary.BySeverity[0] != (severityCount{Severity: "error", Count: 2}) {
+		t.Errorf("expected errors first, got %+v", summary.BySeverity)
+	}
+	if top := summary.TopOffenders[0]; top.File != "big.go" || top.Total != analysisIssuesPerPage {
+		t.Errorf("expected big.go to be the top offender, got %+v", top)
+	}
+	if len(summary.Pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(summary.Pages))
+	}
+
+	// Errors come first, grouped by file and sorted by line
+	first := summary.Pages[0].Groups
+	if first[0].Severity != "error" || first[0].File != "main.go" || first[1].File != "web/app.ts" {
+		t.Errorf("unexpected first groups: %+v", first[:2])
+	}
+	if summary.Pages[1].Groups[0].File != "big.go" {
+		t.Errorf("expected the second page to continue big.go, got %+v", summary.Pages[1].Groups[0])
+	}
+
+	text := renderAnalysisText(summary)
+	if strings.Count(text, "\f") != 2 || !strings.Contains(text, "ISSUES (page 2 of 2)") {
+		t.Error("expected each page of issues to start on a new PDF page")
+	}
+}
+
+func TestExportAnalysisReport_HTML(t *testing.T) {
+	service := NewService(&domain.NoopLogger{}, nil, nil, nil, nil, osTempFiles{dir: t.TempDir()}, osPaths{}, osWriter{}, nil)
+
+	path, err := service.ExportAnalysisReport(analysisReportFixture(analysisIssuesPerPage), "HTML")
+	if err != nil {
+		t.Fatalf("ExportAnalysisReport returned error: %v", err)
+	}
+	if filepath.Base(path) != "analysis-report.html" {
+		t.Errorf("expected the first page to be returned, got %s", path)
+	}
+
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(first)
+	for _, want := range []string{"Top offenders", "&lt;script&gt; not allowed", `href="analysis-report-2.html"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected first page to contain %q", want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(path), "analysis-report-2.html")); err != nil {
+		t.Errorf("expected a second page: %v", err)
+	}
+
+	if _, err := service.ExportAnalysisReport(analysisReportFixture(0), "docx"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"shotgun_code/domain"
+	"strings"
+	"time"
+)
+
+// contextBundleManifest describes the contents of a context bundle
+type contextBundleManifest struct {
+	ContextID    string    `json:"contextId"`
+	ProjectPath  string    `json:"projectPath"`
+	CreatedAt    time.Time `json:"createdAt"`
+	ExportedAt   time.Time `json:"exportedAt"`
+	TokenCount   int       `json:"tokenCount"`
+	LineCount    int       `json:"lineCount"`
+	Files        []string  `json:"files"`
+	SkippedFiles []string  `json:"skippedFiles,omitempty"`
+}
+
+// ExportContextBundle packs a stored context into a ZIP with the formatted
+// context (context.txt), a manifest (manifest.json) and the referenced files
+// under files/, mirroring their paths in the project. Files outside the
+// project are listed in the manifest as skipped. Returns the path of the ZIP.
+func (s *Service) ExportContextBundle(summary *domain.ContextSummary, content string, files map[string]string) (string, error) {
+	if summary == nil {
+		return "", fmt.Errorf("context summary is required")
+	}
+
+	manifest := contextBundleManifest{
+		ContextID:   summary.ID,
+		ProjectPath: summary.ProjectPath,
+		CreatedAt:   summary.CreatedAt,
+		ExportedAt:  time.Now(),
+		TokenCount:  summary.TokenCount,
+		LineCount:   summary.LineCount,
+		Files:       []string{},
+	}
+	entries := map[string][]byte{"context.txt": []byte(content)}
+
+	for _, file := range sortedKeys(files) {
+		rel, ok := bundlePath(summary.ProjectPath, file)
+		if !ok {
+			manifest.SkippedFiles = append(manifest.SkippedFiles, file)
+			continue
+		}
+		entries["files/"+rel] = []byte(files[file])
+		manifest.Files = append(manifest.Files, rel)
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal bundle manifest: %w", err)
+	}
+	entries["manifest.json"] = manifestJSON
+
+	tempDir, err := s.tempFileProvider.MkdirTemp("", "shotgun-bundle-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	outputPath := s.pathProvider.Join(tempDir, fmt.Sprintf("context-%s.zip", summary.ID))
+	if err := s.archiver.ZipFilesAtomic(entries, outputPath); err != nil {
+		_ = s.fileSystemWriter.RemoveAll(tempDir)
+		return "", fmt.Errorf("failed to write context bundle: %w", err)
+	}
+
+	s.log.Info(fmt.Sprintf("Exported context bundle %s with %d files to %s", summary.ID, len(manifest.Files), outputPath))
+	return outputPath, nil
+}
+
+// bundlePath returns the slash-separated path of a file inside the project,
+// or false if the file lies outside of it
+func bundlePath(projectPath, file string) (string, bool) {
+	rel := relativeTo(projectPath, file)
+	if filepath.IsAbs(rel) {
+		return "", false
+	}
+	rel = path.Clean(filepath.ToSlash(rel))
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") || strings.HasPrefix(rel, "/") {
+		return "", false
+	}
+	return rel, true
+}
@@ -0,0 +1,59 @@
+package export
+
+import (
+	"encoding/json"
+	"shotgun_code/domain"
+	"testing"
+)
+
+// recordingArchiver keeps the entries of the last archive
+type recordingArchiver struct {
+	files map[string][]byte
+	path  string
+}
+
+func (a *recordingArchiver) ZipFilesAtomic(files map[string][]byte, outputPath string) error {
+	a.files, a.path = files, outputPath
+	return nil
+}
+
+func TestExportContextBundle(t *testing.T) {
+	arch := &recordingArchiver{}
+	service := NewService(&domain.NoopLogger{}, nil, nil, nil, arch, osTempFiles{dir: t.TempDir()}, osPaths{}, osWriter{}, nil)
+
+	summary := &domain.ContextSummary{ID: "ctx1", ProjectPath: "/proj", TokenCount: 42}
+	files := map[string]string{
+		"main.go":        "package main",
+		"/proj/pkg/a.go": "package pkg",
+		"../secret.txt":  "nope",
+		"/etc/passwd":    "nope",
+	}
+
+	path, err := service.ExportContextBundle(summary, "--- File: main.go ---", files)
+	if err != nil {
+		t.Fatalf("ExportContextBundle returned error: %v", err)
+	}
+	if path != arch.path {
+		t.Errorf("expected %s, got %s", arch.path, path)
+	}
+
+	if string(arch.files["context.txt"]) != "--- File: main.go ---" {
+		t.Error("expected the formatted context in context.txt")
+	}
+	if string(arch.files["files/main.go"]) != "package main" || string(arch.files["files/pkg/a.go"]) != "package pkg" {
+		t.Errorf("expected project files under files/, got %v", keys(arch.files))
+	}
+	if len(arch.files) != 4 {
+		t.Errorf("expected files outside the project to be skipped, got %v", keys(arch.files))
+	}
+
+	var manifest contextBundleManifest
+	if err := json.Unmarshal(arch.files["manifest.json"], &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.ContextID != "ctx1" || len(manifest.Files) != 2 || len(manifest.SkippedFiles) != 2 {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+}
+
+func keys(m map[string][]byte) []string { return sortedKeys(m) }
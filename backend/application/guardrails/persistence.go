@@ -0,0 +1,111 @@
+package guardrails
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"shotgun_code/domain"
+	"strings"
+)
+
+// SetPolicyStore подключает хранилище политик и загружает сохраненные политики
+// и бюджеты вместо политик по умолчанию; некорректные записи пропускаются
+func (s *ServiceImpl) SetPolicyStore(store domain.GuardrailPolicyStore) {
+	set, err := store.Load()
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to load guardrail policies: %v", err))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	if set == nil {
+		return
+	}
+
+	s.policies = make([]domain.GuardrailPolicy, 0, len(set.Policies))
+	seen := make(map[string]bool)
+	for _, policy := range set.Policies {
+		if err := validatePolicy(policy); err != nil {
+			s.log.Warning(fmt.Sprintf("Skipping invalid guardrail policy %q: %v", policy.ID, err))
+			continue
+		}
+		if seen[policy.ID] {
+			s.log.Warning(fmt.Sprintf("Skipping duplicate guardrail policy %q", policy.ID))
+			continue
+		}
+		seen[policy.ID] = true
+		s.policies = append(s.policies, policy)
+	}
+
+	s.budgets = make([]domain.BudgetPolicy, 0, len(set.Budgets))
+	seen = make(map[string]bool)
+	for _, budget := range set.Budgets {
+		if err := validateBudgetPolicy(budget); err != nil {
+			s.log.Warning(fmt.Sprintf("Skipping invalid budget policy %q: %v", budget.ID, err))
+			continue
+		}
+		if seen[budget.ID] {
+			s.log.Warning(fmt.Sprintf("Skipping duplicate budget policy %q", budget.ID))
+			continue
+		}
+		seen[budget.ID] = true
+		s.budgets = append(s.budgets, budget)
+	}
+	s.log.Info(fmt.Sprintf("Loaded %d guardrail and %d budget policies", len(s.policies), len(s.budgets)))
+}
+
+// commitLocked сохраняет новый набор политик и применяет его только после
+// успешной записи; вызывается под s.mu
+func (s *ServiceImpl) commitLocked(policies []domain.GuardrailPolicy, budgets []domain.BudgetPolicy) error {
+	if s.store != nil {
+		if err := s.store.Save(domain.GuardrailPolicySet{Policies: policies, Budgets: budgets}); err != nil {
+			return fmt.Errorf("failed to save guardrail policies: %w", err)
+		}
+	}
+	s.policies, s.budgets = policies, budgets
+	return nil
+}
+
+// validatePolicy проверяет обязательные поля и шаблоны правил так же, как их
+// разбирает matchesPath
+func validatePolicy(policy domain.GuardrailPolicy) error {
+	if policy.ID == "" {
+		return fmt.Errorf("policy ID is required")
+	}
+	if policy.Name == "" {
+		return fmt.Errorf("policy name is required")
+	}
+	for _, rule := range policy.Rules {
+		if rule.Pattern == "" {
+			return fmt.Errorf("rule %s: pattern is required", rule.ID)
+		}
+		if strings.Contains(rule.Pattern, "*") || strings.Contains(rule.Pattern, "?") {
+			if _, err := filepath.Match(rule.Pattern, ""); err != nil {
+				return fmt.Errorf("rule %s: invalid glob pattern: %w", rule.ID, err)
+			}
+			continue
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("rule %s: invalid regex pattern: %w", rule.ID, err)
+		}
+	}
+	return nil
+}
+
+// validateBudgetPolicy проверяет обязательные поля и лимиты бюджета
+func validateBudgetPolicy(policy domain.BudgetPolicy) error {
+	if policy.ID == "" {
+		return fmt.Errorf("budget policy ID is required")
+	}
+	if policy.Name == "" {
+		return fmt.Errorf("budget policy name is required")
+	}
+	if policy.Limit < 0 {
+		return fmt.Errorf("budget policy limit must not be negative")
+	}
+	if policy.TimeWindow < 0 {
+		return fmt.Errorf("budget policy time window must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package guardrails
+
+import (
+	"shotgun_code/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryPolicyStore struct {
+	set *domain.GuardrailPolicySet
+}
+
+func (m *memoryPolicyStore) Load() (*domain.GuardrailPolicySet, error) { return m.set, nil }
+
+func (m *memoryPolicyStore) Save(set domain.GuardrailPolicySet) error {
+	m.set = &set
+	return nil
+}
+
+func TestPolicyStore_PersistsChanges(t *testing.T) {
+	store := &memoryPolicyStore{}
+	first := NewService(&domain.NoopLogger{}, nil, nil).(*ServiceImpl)
+	first.SetPolicyStore(store)
+	defaults, err := first.GetPolicies()
+	require.NoError(t, err)
+
+	custom := domain.GuardrailPolicy{
+		ID: "no-migrations", Name: "No migrations", Type: domain.GuardrailTypeForbiddenPath, Enabled: true,
+		Rules: []domain.GuardrailRule{{ID: "sql", Pattern: `^migrations/`, Action: domain.GuardrailActionBlock}},
+	}
+	require.NoError(t, first.AddPolicy(custom))
+	require.NoError(t, first.AddBudgetPolicy(domain.BudgetPolicy{ID: "files", Name: "Files", Type: domain.BudgetTypeFiles, Limit: 20}))
+
+	second := NewService(&domain.NoopLogger{}, nil, nil).(*ServiceImpl)
+	second.SetPolicyStore(store)
+	policies, err := second.GetPolicies()
+	require.NoError(t, err)
+	assert.Len(t, policies, len(defaults)+1, "default policies pass validation on reload")
+	assert.Contains(t, policies, custom)
+	budgets, err := second.GetBudgetPolicies()
+	require.NoError(t, err)
+	assert.Equal(t, "files", budgets[len(budgets)-1].ID)
+}
+
+func TestPolicyStore_SkipsInvalidPolicies(t *testing.T) {
+	store := &memoryPolicyStore{set: &domain.GuardrailPolicySet{
+		Policies: []domain.GuardrailPolicy{
+			{ID: "bad-regex", Name: "Bad", Rules: []domain.GuardrailRule{{ID: "r", Pattern: "(unclosed"}}},
+			{ID: "ok", Name: "OK"},
+		},
+		Budgets: []domain.BudgetPolicy{{ID: "negative", Name: "Negative", Limit: -1}},
+	}}
+
+	service := NewService(&domain.NoopLogger{}, nil, nil).(*ServiceImpl)
+	service.SetPolicyStore(store)
+
+	policies, err := service.GetPolicies()
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	assert.Equal(t, "ok", policies[0].ID)
+	budgets, err := service.GetBudgetPolicies()
+	require.NoError(t, err)
+	assert.Empty(t, budgets)
+	assert.Error(t, service.AddPolicy(domain.GuardrailPolicy{ID: "unnamed"}))
+}
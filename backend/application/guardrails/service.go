@@ -3,6 +3,7 @@ package guardrails
 import (
 	"fmt"
 	"shotgun_code/domain"
+	"slices"
 	"sync"
 	"time"
 )
@@ -19,6 +20,7 @@ type ServiceImpl struct {
 	opaService       domain.OPAService
 	fileStatProvider domain.FileStatProvider
 	taskTypeProvider domain.TaskTypeProvider
+	store            domain.GuardrailPolicyStore
 }
 
 // NewService создает новый сервис guardrails
@@ -34,10 +36,12 @@ func NewService(log domain.Logger, opaService domain.OPAService, fileStatProvide
 			EnableTaskValidation: true,
 			EnableBudgetTracking: true,
 			EnablePathValidation: true,
+			DeniedPaths:          domain.DefaultDeniedPaths,
 		},
 		opaService:       opaService,
 		fileStatProvider: fileStatProvider,
 	}
+	service.applyPathPolicy()
 
 	service.initializeDefaultPolicies()
 
@@ -106,6 +110,10 @@ func (s *ServiceImpl) GetBudgetPolicies() ([]domain.BudgetPolicy, error) {
 
 // AddPolicy добавляет новую политику
 func (s *ServiceImpl) AddPolicy(policy domain.GuardrailPolicy) error {
+	if err := validatePolicy(policy); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -115,7 +123,9 @@ func (s *ServiceImpl) AddPolicy(policy domain.GuardrailPolicy) error {
 		}
 	}
 
-	s.policies = append(s.policies, policy)
+	if err := s.commitLocked(append(slices.Clone(s.policies), policy), s.budgets); err != nil {
+		return err
+	}
 	s.log.Info(fmt.Sprintf("Added guardrail policy: %s", policy.Name))
 	return nil
 }
@@ -127,7 +137,9 @@ func (s *ServiceImpl) RemovePolicy(policyID string) error {
 
 	for i, policy := range s.policies {
 		if policy.ID == policyID {
-			s.policies = append(s.policies[:i], s.policies[i+1:]...)
+			if err := s.commitLocked(slices.Delete(slices.Clone(s.policies), i, i+1), s.budgets); err != nil {
+				return err
+			}
 			s.log.Info(fmt.Sprintf("Removed guardrail policy: %s", policy.Name))
 			return nil
 		}
@@ -138,12 +150,20 @@ func (s *ServiceImpl) RemovePolicy(policyID string) error {
 
 // UpdatePolicy обновляет политику
 func (s *ServiceImpl) UpdatePolicy(policy domain.GuardrailPolicy) error {
+	if err := validatePolicy(policy); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for i, existing := range s.policies {
 		if existing.ID == policy.ID {
-			s.policies[i] = policy
+			policies := slices.Clone(s.policies)
+			policies[i] = policy
+			if err := s.commitLocked(policies, s.budgets); err != nil {
+				return err
+			}
 			s.log.Info(fmt.Sprintf("Updated guardrail policy: %s", policy.Name))
 			return nil
 		}
@@ -154,6 +174,10 @@ func (s *ServiceImpl) UpdatePolicy(policy domain.GuardrailPolicy) error {
 
 // AddBudgetPolicy добавляет бюджетную политику
 func (s *ServiceImpl) AddBudgetPolicy(policy domain.BudgetPolicy) error {
+	if err := validateBudgetPolicy(policy); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -163,7 +187,9 @@ func (s *ServiceImpl) AddBudgetPolicy(policy domain.BudgetPolicy) error {
 		}
 	}
 
-	s.budgets = append(s.budgets, policy)
+	if err := s.commitLocked(s.policies, append(slices.Clone(s.budgets), policy)); err != nil {
+		return err
+	}
 	s.log.Info(fmt.Sprintf("Added budget policy: %s", policy.Name))
 	return nil
 }
@@ -175,7 +201,9 @@ func (s *ServiceImpl) RemoveBudgetPolicy(policyID string) error {
 
 	for i, policy := range s.budgets {
 		if policy.ID == policyID {
-			s.budgets = append(s.budgets[:i], s.budgets[i+1:]...)
+			if err := s.commitLocked(s.policies, slices.Delete(slices.Clone(s.budgets), i, i+1)); err != nil {
+				return err
+			}
 			s.log.Info(fmt.Sprintf("Removed budget policy: %s", policy.Name))
 			return nil
 		}
@@ -186,12 +214,20 @@ func (s *ServiceImpl) RemoveBudgetPolicy(policyID string) error {
 
 // UpdateBudgetPolicy обновляет бюджетную политику
 func (s *ServiceImpl) UpdateBudgetPolicy(policy domain.BudgetPolicy) error {
+	if err := validateBudgetPolicy(policy); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for i, existing := range s.budgets {
 		if existing.ID == policy.ID {
-			s.budgets[i] = policy
+			budgets := slices.Clone(s.budgets)
+			budgets[i] = policy
+			if err := s.commitLocked(s.policies, budgets); err != nil {
+				return err
+			}
 			s.log.Info(fmt.Sprintf("Updated budget policy: %s", policy.Name))
 			return nil
 		}
@@ -214,6 +250,14 @@ func (s *ServiceImpl) UpdateConfig(config domain.GuardrailConfig) error {
 	defer s.mu.Unlock()
 
 	s.config = config
+	s.applyPathPolicy()
 	s.log.Info("Updated guardrail configuration")
 	return nil
 }
+
+// applyPathPolicy передает allowlist/denylist из конфигурации в OPA
+func (s *ServiceImpl) applyPathPolicy() {
+	if s.opaService != nil {
+		s.opaService.SetPathPolicy(s.config.AllowedPaths, s.config.DeniedPaths)
+	}
+}
@@ -53,7 +53,7 @@ func (s *ServiceImpl) validateWithOPA(path string) []domain.GuardrailViolation {
 		violations = append(violations, domain.GuardrailViolation{
 			PolicyID: "opa-policy", RuleID: v.Type, Severity: domain.GuardrailSeverityBlock,
 			Message: v.Message, FilePath: path, Timestamp: time.Now(),
-			Context: map[string]any{"opa_violation": true, "violation_type": v.Type},
+			Context: map[string]any{"opa_violation": true, "violation_type": v.Type, "rule": v.Rule, "matched_rule": opaResult.MatchedRule},
 		})
 	}
 	return violations
@@ -56,12 +56,9 @@ func (d *lazyProjectStructureDetector) Detect(projectPath string) (*domain.Proje
 }
 
 func (d *lazyProjectStructureDetector) DetectLanguages(projectPath string) ([]string, error) {
-	result, err := d.getImpl().DetectStructure(projectPath)
-	if err != nil {
-		return nil, err
-	}
-	langs := make([]string, len(result.Languages))
-	for i, l := range result.Languages {
+	languages := d.getImpl().DetectLanguageInfo(projectPath)
+	langs := make([]string, len(languages))
+	for i, l := range languages {
 		langs[i] = l.Name
 	}
 	return langs, nil
@@ -244,7 +244,7 @@ func (s *Service) attemptSelfCorrection(ctx context.Context, err error, stage do
 		return false, nil
 	}
 
-	correctionResult, applyErr := s.correctionEngine.ApplyCorrections(ctx, corrections, config.ProjectPath)
+	correctionResult, applyErr := s.correctionEngine.ApplyCorrections(ctx, corrections, config.ProjectPath, false)
 	if applyErr != nil {
 		return false, fmt.Errorf("correction application failed: %w", applyErr)
 	}
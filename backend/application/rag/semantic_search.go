@@ -18,12 +18,15 @@ type IndexingState = semantic.IndexingState
 type SymbolInfoForChunking = semantic.SymbolInfoForChunking
 
 // NewSemanticSearchService creates a new semantic search service.
+// chunkerConfig selects the chunking strategy, max tokens and overlap;
+// the zero value keeps the chunker's defaults.
 func NewSemanticSearchService(
 	embeddingProvider domain.EmbeddingProvider,
 	vectorStore domain.VectorStore,
 	symbolIndex analysis.SymbolIndex,
 	log domain.Logger,
 	chunker domain.CodeChunker,
+	chunkerConfig domain.ChunkerConfig,
 ) *SemanticSearchService {
-	return semantic.NewService(embeddingProvider, vectorStore, symbolIndex, log, chunker)
+	return semantic.NewService(embeddingProvider, vectorStore, symbolIndex, log, chunker, chunkerConfig)
 }
@@ -157,14 +157,25 @@ func (r *Service) rerank(query string, results []domain.SemanticSearchResult) []
 
 	for i := range results {
 		chunk := &results[i].Chunk
-		score := results[i].Score
-		score += calcSymbolBoost(chunk.SymbolName, queryTerms)
-		score += calcContentBoost(chunk.Content, queryTerms)
-		score += calcChunkTypeBoost(chunk.ChunkType)
+		var largeChunk float32
 		if chunk.TokenCount > 400 {
-			score -= 0.02
+			largeChunk = -0.02
+		}
+		boosts := []struct {
+			name  string
+			value float32
+		}{
+			{"symbol", calcSymbolBoost(chunk.SymbolName, queryTerms)},
+			{"content", calcContentBoost(chunk.Content, queryTerms)},
+			{"chunkType", calcChunkTypeBoost(chunk.ChunkType)},
+			{"largeChunk", largeChunk},
+		}
+		for _, boost := range boosts {
+			results[i].Score += boost.value
+			if results[i].Explanation != nil {
+				results[i].Explanation.AddBoost(boost.name, boost.value)
+			}
 		}
-		results[i].Score = score
 	}
 
 	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
@@ -177,10 +188,16 @@ func (r *Service) HybridSearch(ctx context.Context, req domain.SemanticSearchReq
 
 	r.log.Info(fmt.Sprintf("RAG: Hybrid search for '%s'", domain.TruncateString(req.Query, 50)))
 
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	semanticWeight, keywordWeight := req.HybridWeights()
+
 	// Perform semantic search
 	semanticReq := req
 	semanticReq.SearchType = domain.SearchTypeSemantic
-	semanticReq.TopK = req.TopK * 2
+	semanticReq.TopK = req.Window() * 2
+	semanticReq.Offset, semanticReq.ScoreThreshold = 0, 0
 
 	semanticResults, err := r.semanticSearch.Search(ctx, semanticReq)
 	if err != nil {
@@ -190,7 +207,8 @@ func (r *Service) HybridSearch(ctx context.Context, req domain.SemanticSearchReq
 	// Perform keyword search
 	keywordReq := req
 	keywordReq.SearchType = domain.SearchTypeKeyword
-	keywordReq.TopK = req.TopK
+	keywordReq.TopK = req.Window()
+	keywordReq.Offset, keywordReq.ScoreThreshold = 0, 0
 
 	keywordResults, err := r.semanticSearch.Search(ctx, keywordReq)
 	if err != nil {
@@ -199,33 +217,52 @@ func (r *Service) HybridSearch(ctx context.Context, req domain.SemanticSearchReq
 		keywordResults = &domain.SemanticSearchResponse{Results: []domain.SemanticSearchResult{}}
 	}
 
-	// Reciprocal Rank Fusion (RRF) for merging results
+	// Weighted Reciprocal Rank Fusion (RRF) for merging results
 	k := 60 // RRF constant
 	scores := make(map[string]float32)
 	chunks := make(map[string]domain.CodeChunk)
+	var explanations map[string]*domain.ScoreBreakdown
+	if req.Explain {
+		explanations = make(map[string]*domain.ScoreBreakdown)
+	}
+	explain := func(key string) *domain.ScoreBreakdown {
+		if explanations[key] == nil {
+			explanations[key] = &domain.ScoreBreakdown{SemanticWeight: semanticWeight, KeywordWeight: keywordWeight}
+		}
+		return explanations[key]
+	}
 
 	// Add semantic results
 	for rank, result := range semanticResults.Results {
 		key := chunkKey(result.Chunk)
-		scores[key] += 1.0 / float32(k+rank+1)
+		reciprocalRank := 1 / float32(k+rank+1)
+		scores[key] += semanticWeight * reciprocalRank
 		chunks[key] = result.Chunk
+		if req.Explain {
+			explain(key).SemanticScore = reciprocalRank
+		}
 	}
 
 	// Add keyword results
 	for rank, result := range keywordResults.Results {
 		key := chunkKey(result.Chunk)
-		scores[key] += 1.0 / float32(k+rank+1)
+		reciprocalRank := 1 / float32(k+rank+1)
+		scores[key] += keywordWeight * reciprocalRank
 		if _, exists := chunks[key]; !exists {
 			chunks[key] = result.Chunk
 		}
+		if req.Explain {
+			explain(key).KeywordScore = reciprocalRank
+		}
 	}
 
 	// Convert to results
 	mergedResults := make([]domain.SemanticSearchResult, 0, len(scores))
 	for key, score := range scores {
 		mergedResults = append(mergedResults, domain.SemanticSearchResult{
-			Chunk: chunks[key],
-			Score: score,
+			Chunk:       chunks[key],
+			Score:       score,
+			Explanation: explanations[key],
 		})
 	}
 
@@ -237,16 +274,16 @@ func (r *Service) HybridSearch(ctx context.Context, req domain.SemanticSearchReq
 	// Apply re-ranking
 	mergedResults = r.rerank(req.Query, mergedResults)
 
-	// Limit to topK
-	if len(mergedResults) > req.TopK {
-		mergedResults = mergedResults[:req.TopK]
-	}
+	// Threshold the fused scores and cut the requested page
+	page, total := req.Page(mergedResults)
 
 	return &domain.SemanticSearchResponse{
-		Results:      mergedResults,
-		TotalResults: len(mergedResults),
-		QueryTime:    time.Since(startTime),
-		SearchType:   domain.SearchTypeHybrid,
+		Results:        page,
+		TotalResults:   len(page),
+		QueryTime:      time.Since(startTime),
+		SearchType:     domain.SearchTypeHybrid,
+		TotalAvailable: total,
+		Offset:         req.Offset,
 	}, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"shotgun_code/domain"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -14,10 +15,15 @@ type CorrectionEngine struct {
 	log             domain.Logger
 	fileSystem      domain.FileSystemProvider
 	correctionRules map[domain.ErrorType][]domain.CorrectionRule
+	safeMode        *domain.SafeMode
 }
 
 // NewCorrectionEngine creates a new CorrectionEngine instance
 func NewCorrectionEngine(log domain.Logger, fileSystem domain.FileSystemProvider) domain.CorrectionEngine {
+	return newCorrectionEngine(log, fileSystem)
+}
+
+func newCorrectionEngine(log domain.Logger, fileSystem domain.FileSystemProvider) *CorrectionEngine {
 	engine := &CorrectionEngine{
 		log:             log,
 		fileSystem:      fileSystem,
@@ -30,8 +36,36 @@ func NewCorrectionEngine(log domain.Logger, fileSystem domain.FileSystemProvider
 	return engine
 }
 
-// ApplyCorrection applies a single correction step
-func (c *CorrectionEngine) ApplyCorrection(ctx context.Context, step *domain.CorrectionStep, projectPath string) (*domain.CorrectionResult, error) {
+// SetSafeMode sets the switch that blocks corrections while safe mode is on
+func (c *CorrectionEngine) SetSafeMode(mode *domain.SafeMode) {
+	c.safeMode = mode
+}
+
+// ModuleRoot returns the directory of the module containing file: the nearest
+// go.mod or package.json above it within projectPath, or projectPath itself
+func (c *CorrectionEngine) ModuleRoot(projectPath, file, language string) string {
+	markers, ok := moduleMarkers[language]
+	if !ok {
+		return projectPath
+	}
+	return findModuleRoot(projectPath, file, markers)
+}
+
+// ApplyCorrection applies a single correction step; with dryRun the result
+// carries the proposed diff and no file is written
+func (c *CorrectionEngine) ApplyCorrection(ctx context.Context, step *domain.CorrectionStep, projectPath string, dryRun bool) (*domain.CorrectionResult, error) {
+	if dryRun {
+		return c.preview(projectPath, func(engine *CorrectionEngine) (*domain.CorrectionResult, error) {
+			return engine.applyCorrection(ctx, step, projectPath)
+		})
+	}
+	if err := c.safeMode.Check("applying corrections"); err != nil {
+		return nil, err
+	}
+	return c.applyCorrection(ctx, step, projectPath)
+}
+
+func (c *CorrectionEngine) applyCorrection(ctx context.Context, step *domain.CorrectionStep, projectPath string) (*domain.CorrectionResult, error) {
 	c.log.Info(fmt.Sprintf("Applying correction: %s for target: %s", step.Action, step.Target))
 
 	switch step.Action {
@@ -54,8 +88,21 @@ func (c *CorrectionEngine) ApplyCorrection(ctx context.Context, step *domain.Cor
 	}
 }
 
-// ApplyCorrections applies multiple correction steps
-func (c *CorrectionEngine) ApplyCorrections(ctx context.Context, steps []*domain.CorrectionStep, projectPath string) (*domain.CorrectionResult, error) {
+// ApplyCorrections applies multiple correction steps; with dryRun the result
+// carries the combined diff and no file is written
+func (c *CorrectionEngine) ApplyCorrections(ctx context.Context, steps []*domain.CorrectionStep, projectPath string, dryRun bool) (*domain.CorrectionResult, error) {
+	if dryRun {
+		return c.preview(projectPath, func(engine *CorrectionEngine) (*domain.CorrectionResult, error) {
+			return engine.applyCorrections(ctx, steps, projectPath)
+		})
+	}
+	if err := c.safeMode.Check("applying corrections"); err != nil {
+		return nil, err
+	}
+	return c.applyCorrections(ctx, steps, projectPath)
+}
+
+func (c *CorrectionEngine) applyCorrections(ctx context.Context, steps []*domain.CorrectionStep, projectPath string) (*domain.CorrectionResult, error) {
 	c.log.Info(fmt.Sprintf("Applying %d correction steps", len(steps)))
 
 	allFilesChanged := make([]string, 0)
@@ -68,7 +115,7 @@ func (c *CorrectionEngine) ApplyCorrections(ctx context.Context, steps []*domain
 	for i, step := range sortedSteps {
 		c.log.Debug(fmt.Sprintf("Applying correction step %d/%d: %s", i+1, len(sortedSteps), step.Description))
 
-		result, err := c.ApplyCorrection(ctx, step, projectPath)
+		result, err := c.applyCorrection(ctx, step, projectPath)
 		if err != nil {
 			c.log.Warning(fmt.Sprintf("Correction step failed: %v", err))
 			step.Applied = false
@@ -113,6 +160,72 @@ func (c *CorrectionEngine) CanHandle(errDetails *domain.ErrorDetails) bool {
 	return false
 }
 
+// ApplyRules applies the correction rules that can handle the error. Each rule
+// is tried against the files on disk and the edits of the kept rules are
+// written in one pass; a rule whose edits overlap those of a higher-priority
+// rule is skipped and reported in SkippedRules. With dryRun the result carries
+// the proposed diff and no file is written.
+func (c *CorrectionEngine) ApplyRules(ctx context.Context, errDetails *domain.ErrorDetails, projectPath string, dryRun bool) (*domain.CorrectionResult, error) {
+	if dryRun {
+		return c.preview(projectPath, func(engine *CorrectionEngine) (*domain.CorrectionResult, error) {
+			return engine.applyRules(ctx, errDetails, projectPath)
+		})
+	}
+	if err := c.safeMode.Check("applying corrections"); err != nil {
+		return nil, err
+	}
+	return c.applyRules(ctx, errDetails, projectPath)
+}
+
+func (c *CorrectionEngine) applyRules(ctx context.Context, errDetails *domain.ErrorDetails, projectPath string) (*domain.CorrectionResult, error) {
+	edits, err := c.tryRules(ctx, errDetails, projectPath)
+	if err != nil {
+		return nil, err
+	}
+	kept, skipped := selectRules(edits, projectPath)
+	for _, rule := range skipped {
+		c.log.Warning(fmt.Sprintf("Skipping conflicting correction rule %s", rule))
+	}
+
+	merged, err := c.mergeEdits(kept)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range sortedKeys(merged) {
+		if err := c.fileSystem.WriteFile(path, merged[path], 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	var changed, messages []string
+	for _, edit := range kept {
+		result := edit.result
+		if result == nil {
+			// A rule that could not be previewed stands in alone and runs directly
+			if result, err = edit.rule.ApplyCorrection(errDetails, projectPath); err != nil {
+				c.log.Warning(fmt.Sprintf("Correction rule %s failed: %v", ruleName(edit.rule), err))
+				continue
+			}
+			if !result.Success {
+				c.log.Debug(fmt.Sprintf("Correction rule %s did not apply: %s", ruleName(edit.rule), result.Message))
+				continue
+			}
+		}
+		changed = append(changed, result.FilesChanged...)
+		messages = append(messages, result.Message)
+	}
+
+	if len(messages) == 0 {
+		return &domain.CorrectionResult{Success: false, Message: "No correction rule applied", SkippedRules: skipped}, nil
+	}
+	return &domain.CorrectionResult{
+		Success:      true,
+		Message:      strings.Join(messages, "; "),
+		FilesChanged: removeDuplicates(changed),
+		SkippedRules: skipped,
+	}, nil
+}
+
 // Correction action implementations
 
 func (c *CorrectionEngine) applyImportFix(ctx context.Context, step *domain.CorrectionStep, projectPath string) (*domain.CorrectionResult, error) {
@@ -261,20 +374,59 @@ func (c *CorrectionEngine) getCorrectionPriority(action domain.CorrectionAction)
 }
 
 func (c *CorrectionEngine) registerCorrectionRules() {
-	c.correctionRules[domain.ErrorTypeImport] = []domain.CorrectionRule{
-		NewImportCorrectionRule(),
+	c.registerRule(NewGoUnusedImportRule(c.fileSystem))
+	c.registerRule(NewGoMissingImportRule(c.fileSystem))
+	c.registerRule(NewImportCorrectionRule())
+	c.registerRule(NewSyntaxCorrectionRule())
+	c.registerRule(NewTypeCorrectionRule())
+	c.registerRule(NewLintingCorrectionRule())
+	c.registerRule(NewCompilationCorrectionRule())
+}
+
+// correctionFixes maps the Fix of a "correction" repair rule to the
+// correction rules it runs; an empty Fix runs all of them
+var correctionFixes = map[string][]string{
+	"imports": {"GoUnusedImportRule", "GoMissingImportRule", "ImportCorrectionRule"},
+	"syntax":  {"SyntaxCorrectionRule"},
+	"types":   {"TypeCorrectionRule"},
+	"lint":    {"LintingCorrectionRule"},
+}
+
+// forFix returns a copy of the engine with only the correction rules of fix
+// (see correctionFixes)
+func (c *CorrectionEngine) forFix(fix string) (*CorrectionEngine, error) {
+	if fix == "" {
+		return c, nil
 	}
-	c.correctionRules[domain.ErrorTypeSyntax] = []domain.CorrectionRule{
-		NewSyntaxCorrectionRule(),
+	names, ok := correctionFixes[fix]
+	if !ok {
+		return nil, fmt.Errorf("unknown correction fix %q", fix)
 	}
-	c.correctionRules[domain.ErrorTypeTypeCheck] = []domain.CorrectionRule{
-		NewTypeCorrectionRule(),
+	engine := &CorrectionEngine{
+		log:             c.log,
+		fileSystem:      c.fileSystem,
+		correctionRules: make(map[domain.ErrorType][]domain.CorrectionRule),
+		safeMode:        c.safeMode,
 	}
-	c.correctionRules[domain.ErrorTypeLinting] = []domain.CorrectionRule{
-		NewLintingCorrectionRule(),
+	for errType, rules := range c.correctionRules {
+		for _, rule := range rules {
+			if slices.Contains(names, ruleName(rule)) {
+				engine.correctionRules[errType] = append(engine.correctionRules[errType], rule)
+			}
+		}
 	}
-	c.correctionRules[domain.ErrorTypeCompilation] = []domain.CorrectionRule{
-		NewCompilationCorrectionRule(),
+	return engine, nil
+}
+
+// registerRule adds a rule for each of its error types, keeping the rules of
+// each type ordered by priority
+func (c *CorrectionEngine) registerRule(rule domain.CorrectionRule) {
+	for _, errType := range rule.GetErrorTypes() {
+		rules := append(c.correctionRules[errType], rule)
+		sort.SliceStable(rules, func(i, j int) bool {
+			return rules[i].GetPriority() > rules[j].GetPriority()
+		})
+		c.correctionRules[errType] = rules
 	}
 }
 
@@ -287,6 +439,11 @@ func NewImportCorrectionRule() domain.CorrectionRule {
 	return &ImportCorrectionRule{}
 }
 
+// WithFileSystem returns the rule itself: it changes no file
+func (r *ImportCorrectionRule) WithFileSystem(domain.FileSystemProvider) domain.CorrectionRule {
+	return r
+}
+
 func (r *ImportCorrectionRule) CanHandle(errDetails *domain.ErrorDetails) bool {
 	return errDetails.ErrorType == domain.ErrorTypeImport
 }
@@ -313,6 +470,11 @@ func NewSyntaxCorrectionRule() domain.CorrectionRule {
 	return &SyntaxCorrectionRule{}
 }
 
+// WithFileSystem returns the rule itself: it changes no file
+func (r *SyntaxCorrectionRule) WithFileSystem(domain.FileSystemProvider) domain.CorrectionRule {
+	return r
+}
+
 func (r *SyntaxCorrectionRule) CanHandle(errDetails *domain.ErrorDetails) bool {
 	return errDetails.ErrorType == domain.ErrorTypeSyntax
 }
@@ -339,6 +501,11 @@ func NewTypeCorrectionRule() domain.CorrectionRule {
 	return &TypeCorrectionRule{}
 }
 
+// WithFileSystem returns the rule itself: it changes no file
+func (r *TypeCorrectionRule) WithFileSystem(domain.FileSystemProvider) domain.CorrectionRule {
+	return r
+}
+
 func (r *TypeCorrectionRule) CanHandle(errDetails *domain.ErrorDetails) bool {
 	return errDetails.ErrorType == domain.ErrorTypeTypeCheck
 }
@@ -365,6 +532,11 @@ func NewLintingCorrectionRule() domain.CorrectionRule {
 	return &LintingCorrectionRule{}
 }
 
+// WithFileSystem returns the rule itself: it changes no file
+func (r *LintingCorrectionRule) WithFileSystem(domain.FileSystemProvider) domain.CorrectionRule {
+	return r
+}
+
 func (r *LintingCorrectionRule) CanHandle(errDetails *domain.ErrorDetails) bool {
 	return errDetails.ErrorType == domain.ErrorTypeLinting
 }
@@ -391,6 +563,11 @@ func NewCompilationCorrectionRule() domain.CorrectionRule {
 	return &CompilationCorrectionRule{}
 }
 
+// WithFileSystem returns the rule itself: it changes no file
+func (r *CompilationCorrectionRule) WithFileSystem(domain.FileSystemProvider) domain.CorrectionRule {
+	return r
+}
+
 func (r *CompilationCorrectionRule) CanHandle(errDetails *domain.ErrorDetails) bool {
 	return errDetails.ErrorType == domain.ErrorTypeCompilation
 }
@@ -0,0 +1,68 @@
+package repair
+
+import (
+	"fmt"
+	"shotgun_code/domain"
+	"slices"
+	"strings"
+)
+
+// SetRuleStore подключает хранилище пользовательских правил и загружает из
+// него правила прошлых запусков; некорректные правила пропускаются
+func (s *Service) SetRuleStore(store domain.RepairRuleStore) {
+	rules, err := store.Load()
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to load custom repair rules: %v", err))
+	}
+
+	s.rulesMu.Lock()
+	defer s.rulesMu.Unlock()
+	s.ruleStore = store
+	s.customRules = nil
+	for _, rule := range rules {
+		if err := s.ValidateRule(rule); err != nil {
+			s.log.Warning(fmt.Sprintf("Skipping invalid repair rule %q: %v", rule.ID, err))
+			continue
+		}
+		if s.ruleExists(rule) {
+			s.log.Warning(fmt.Sprintf("Skipping duplicate repair rule %q", rule.ID))
+			continue
+		}
+		s.customRules = append(s.customRules, rule)
+	}
+}
+
+// customRulesFor возвращает пользовательские правила языка; правило без
+// языка применяется ко всем
+func (s *Service) customRulesFor(language string) []domain.RepairRule {
+	s.rulesMu.RLock()
+	defer s.rulesMu.RUnlock()
+
+	var rules []domain.RepairRule
+	for _, rule := range s.customRules {
+		if rule.Language == "" || strings.EqualFold(rule.Language, language) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// ruleExists проверяет, занят ли ID правила встроенным или пользовательским
+// правилом; вызывается под rulesMu
+func (s *Service) ruleExists(rule domain.RepairRule) bool {
+	sameID := func(r domain.RepairRule) bool { return r.ID == rule.ID }
+	return slices.ContainsFunc(s.getDefaultRules(rule.Language), sameID) ||
+		slices.ContainsFunc(s.customRules, sameID)
+}
+
+// commitRules сохраняет новый набор пользовательских правил и применяет его
+// только после успешной записи; вызывается под rulesMu
+func (s *Service) commitRules(rules []domain.RepairRule) error {
+	if s.ruleStore != nil {
+		if err := s.ruleStore.Save(rules); err != nil {
+			return fmt.Errorf("failed to save repair rules: %w", err)
+		}
+	}
+	s.customRules = rules
+	return nil
+}
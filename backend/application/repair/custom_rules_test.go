@@ -0,0 +1,61 @@
+package repair
+
+import (
+	"shotgun_code/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRuleStore struct {
+	rules []domain.RepairRule
+}
+
+func (m *memoryRuleStore) Load() ([]domain.RepairRule, error) { return m.rules, nil }
+
+func (m *memoryRuleStore) Save(rules []domain.RepairRule) error {
+	m.rules = rules
+	return nil
+}
+
+func TestCustomRules_PersistAcrossRestarts(t *testing.T) {
+	store := &memoryRuleStore{}
+	rule := domain.RepairRule{ID: "go-vet-shadow", Name: "Vet shadow", Pattern: `declaration of "\w+" shadows`, Language: "go", Category: "lint"}
+
+	first := NewService(&TestLogger{}, nil).(*Service)
+	first.SetRuleStore(store)
+	require.NoError(t, first.AddRule(rule))
+	require.Error(t, first.AddRule(rule), "duplicate IDs are rejected")
+
+	second := NewService(&TestLogger{}, nil).(*Service)
+	second.SetRuleStore(store)
+	rules, err := second.GetAvailableRules("go")
+	require.NoError(t, err)
+	assert.Contains(t, rules, rule)
+
+	require.NoError(t, second.RemoveRule(rule.ID))
+	assert.Empty(t, store.rules)
+	assert.Error(t, second.RemoveRule("go-format"), "built-in rules cannot be removed")
+}
+
+func TestCustomRules_SkipsInvalidRulesOnLoad(t *testing.T) {
+	store := &memoryRuleStore{rules: []domain.RepairRule{
+		{ID: "broken", Name: "Broken", Pattern: "(unclosed", Language: "go"},
+		{ID: "go-format", Name: "Shadows a built-in rule", Pattern: "gofmt", Language: "go"},
+		{ID: "any-language", Name: "Any language", Pattern: "TODO"},
+	}}
+
+	service := NewService(&TestLogger{}, nil).(*Service)
+	service.SetRuleStore(store)
+
+	rules, err := service.GetAvailableRules("typescript")
+	require.NoError(t, err)
+	var ids []string
+	for _, r := range rules {
+		ids = append(ids, r.ID)
+	}
+	assert.Contains(t, ids, "any-language", "rules without a language apply to every language")
+	assert.NotContains(t, ids, "broken")
+	assert.Len(t, service.customRulesFor("go"), 1)
+}
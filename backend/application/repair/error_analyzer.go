@@ -11,6 +11,7 @@ import (
 type ErrorAnalyzer struct {
 	log               domain.Logger
 	languageAnalyzers map[string]domain.LanguageErrorAnalyzer
+	analyzerOrder     []string
 }
 
 // NewErrorAnalyzer creates a new ErrorAnalyzer instance
@@ -20,14 +21,23 @@ func NewErrorAnalyzer(log domain.Logger) domain.ErrorAnalyzer {
 		languageAnalyzers: make(map[string]domain.LanguageErrorAnalyzer),
 	}
 
-	// Register language-specific analyzers
-	analyzer.languageAnalyzers[langGo] = NewGoErrorAnalyzer()
-	analyzer.languageAnalyzers[langTypeScript] = NewTypeScriptErrorAnalyzer()
-	analyzer.languageAnalyzers[langJavaScript] = NewJavaScriptErrorAnalyzer()
+	// Register language-specific analyzers. TypeScript goes first: it only
+	// accepts tsc output, while the others accept anything.
+	analyzer.register(NewTypeScriptErrorAnalyzer())
+	analyzer.register(NewGoErrorAnalyzer())
+	analyzer.register(NewJavaScriptErrorAnalyzer())
 
 	return analyzer
 }
 
+func (e *ErrorAnalyzer) register(analyzer domain.LanguageErrorAnalyzer) {
+	language := analyzer.GetLanguage()
+	if _, exists := e.languageAnalyzers[language]; !exists {
+		e.analyzerOrder = append(e.analyzerOrder, language)
+	}
+	e.languageAnalyzers[language] = analyzer
+}
+
 // AnalyzeError analyzes error output and provides detailed error information
 func (e *ErrorAnalyzer) AnalyzeError(errorOutput string, stage domain.ProtocolStage) (*domain.ErrorDetails, error) {
 	e.log.Debug(fmt.Sprintf("Analyzing error for stage %s: %s", stage, errorOutput))
@@ -44,7 +54,8 @@ func (e *ErrorAnalyzer) AnalyzeError(errorOutput string, stage domain.ProtocolSt
 	e.extractLocationInfo(errorOutput, errorDetails)
 
 	// Try language-specific analysis
-	for language, analyzer := range e.languageAnalyzers {
+	for _, language := range e.analyzerOrder {
+		analyzer := e.languageAnalyzers[language]
 		if langDetails, err := analyzer.AnalyzeError(errorOutput); err == nil {
 			e.log.Debug(fmt.Sprintf("Language-specific analysis successful for %s", language))
 			e.mergeErrorDetails(errorDetails, langDetails)
@@ -63,7 +74,8 @@ func (e *ErrorAnalyzer) SuggestCorrections(errDetails *domain.ErrorDetails) ([]*
 	corrections := make([]*domain.CorrectionStep, 0)
 
 	// Try language-specific corrections first
-	for _, analyzer := range e.languageAnalyzers {
+	for _, language := range e.analyzerOrder {
+		analyzer := e.languageAnalyzers[language]
 		if langCorrections, err := analyzer.SuggestCorrections(errDetails); err == nil && len(langCorrections) > 0 {
 			corrections = append(corrections, langCorrections...)
 		}
@@ -81,7 +93,10 @@ func (e *ErrorAnalyzer) ClassifyErrorType(errorOutput string) domain.ErrorType {
 	errorLower := strings.ToLower(errorOutput)
 
 	// TypeScript specific errors (check first for specificity)
-	if strings.Contains(errorOutput, "TS2304") || strings.Contains(errorLower, "cannot find name") {
+	if diags := parseTscOutput(errorOutput); len(diags) > 0 {
+		return classifyTscCode(diags[0].Code)
+	}
+	if strings.Contains(errorLower, "cannot find name") {
 		return domain.ErrorTypeImport
 	}
 
@@ -274,59 +289,6 @@ func (g *GoErrorAnalyzer) GetLanguage() string {
 	return langGo
 }
 
-// TypeScriptErrorAnalyzer analyzes TypeScript-specific errors
-type TypeScriptErrorAnalyzer struct{}
-
-func NewTypeScriptErrorAnalyzer() domain.LanguageErrorAnalyzer {
-	return &TypeScriptErrorAnalyzer{}
-}
-
-func (t *TypeScriptErrorAnalyzer) AnalyzeError(errorOutput string) (*domain.ErrorDetails, error) {
-	details := &domain.ErrorDetails{
-		Tool: "tsc",
-	}
-
-	if strings.Contains(errorOutput, "TS2304") {
-		details.ErrorType = domain.ErrorTypeImport
-		details.Suggestions = append(details.Suggestions, "Cannot find name - check imports or declarations")
-	}
-
-	if strings.Contains(errorOutput, "TS2322") {
-		details.ErrorType = domain.ErrorTypeTypeCheck
-		details.Suggestions = append(details.Suggestions, "Type assignment error - check type compatibility")
-	}
-
-	return details, nil
-}
-
-func (t *TypeScriptErrorAnalyzer) SuggestCorrections(error *domain.ErrorDetails) ([]*domain.CorrectionStep, error) {
-	corrections := make([]*domain.CorrectionStep, 0)
-
-	if strings.Contains(error.Message, "TS2304") {
-		corrections = append(corrections, &domain.CorrectionStep{
-			Action:      domain.ActionFixImport,
-			Target:      error.SourceFile,
-			Description: "Add missing import or type declaration",
-		})
-	}
-
-	return corrections, nil
-}
-
-func (t *TypeScriptErrorAnalyzer) ClassifyErrorType(errorOutput string) domain.ErrorType {
-	if strings.Contains(errorOutput, "TS2304") || strings.Contains(errorOutput, "Cannot find") {
-		return domain.ErrorTypeImport
-	}
-	if strings.Contains(errorOutput, "TS2322") || strings.Contains(errorOutput, "Type") {
-		return domain.ErrorTypeTypeCheck
-	}
-	return domain.ErrorTypeCompilation
-}
-
-func (t *TypeScriptErrorAnalyzer) GetLanguage() string {
-	return langTypeScript
-}
-
 // JavaScriptErrorAnalyzer analyzes JavaScript-specific errors
 type JavaScriptErrorAnalyzer struct{}
 
@@ -319,7 +319,7 @@ func TestCorrectionEngine_ApplyCorrection(t *testing.T) {
 
 			engine := NewCorrectionEngine(logger, fileSystemProvider)
 
-			result, err := engine.ApplyCorrection(context.Background(), tt.step, "/test/project")
+			result, err := engine.ApplyCorrection(context.Background(), tt.step, "/test/project", false)
 
 			assert.NoError(t, err)
 			assert.NotNil(t, result)
@@ -388,7 +388,7 @@ func TestCorrectionEngine_ApplyCorrections(t *testing.T) {
 
 			engine := NewCorrectionEngine(logger, fileSystemProvider)
 
-			result, err := engine.ApplyCorrections(context.Background(), tt.steps, "/test/project")
+			result, err := engine.ApplyCorrections(context.Background(), tt.steps, "/test/project", false)
 
 			if tt.expected(result) {
 				assert.NoError(t, err)
@@ -561,7 +561,7 @@ func TestErrorAnalyzerAndCorrectionEngine_Integration(t *testing.T) {
 	canHandle := engine.CanHandle(errorDetails)
 	assert.True(t, canHandle)
 
-	result, err := engine.ApplyCorrections(context.Background(), corrections, "/test/project")
+	result, err := engine.ApplyCorrections(context.Background(), corrections, "/test/project", false)
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 
@@ -0,0 +1,318 @@
+package repair
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"shotgun_code/domain"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+var (
+	// ./main.go:5:2: "os" imported and not used
+	// ./main.go:5:2: "github.com/a/b" imported as c and not used
+	goUnusedImportPattern = regexp.MustCompile(`(?m)^(.+?\.go):\d+:\d+: "([^"]+)" imported (?:as (\w+) )?and not used`)
+	// ./main.go:8:2: undefined: strings
+	goUndefinedPattern = regexp.MustCompile(`(?m)^(.+?\.go):\d+:\d+: undefined: (\w+)\s*$`)
+)
+
+// goStdlibPackages maps package names to standard library import paths for
+// names that aren't ambiguous (rand, template, scanner are left out)
+var goStdlibPackages = map[string]string{
+	"atomic": "sync/atomic", "base64": "encoding/base64", "bufio": "bufio",
+	"bytes": "bytes", "context": "context", "errors": "errors", "exec": "os/exec",
+	"filepath": "path/filepath", "fmt": "fmt", "fs": "io/fs", "hex": "encoding/hex",
+	"http": "net/http", "io": "io", "json": "encoding/json", "log": "log",
+	"maps": "maps", "math": "math", "os": "os", "reflect": "reflect",
+	"regexp": "regexp", "runtime": "runtime", "sha256": "crypto/sha256",
+	"slices": "slices", "sort": "sort", "strconv": "strconv", "strings": "strings",
+	"sync": "sync", "time": "time", "unicode": "unicode", "url": "net/url",
+	"utf8": "unicode/utf8",
+}
+
+// goFileEdit parses a Go file, lets edit change its AST and writes it back
+// if edit reports a change
+func goFileEdit(fileSystem domain.FileSystemProvider, path string, edit func(fset *token.FileSet, file *ast.File) bool) (bool, error) {
+	content, err := fileSystem.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.ParseComments)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if !edit(fset, file) {
+		return false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return false, fmt.Errorf("failed to format %s: %w", path, err)
+	}
+	if err := fileSystem.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// resolveErrorPath resolves a file path from compiler output against the project
+func resolveErrorPath(projectPath, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(projectPath, file)
+}
+
+// GoUnusedImportRule removes imports reported as "imported and not used"
+type GoUnusedImportRule struct {
+	fileSystem domain.FileSystemProvider
+}
+
+func NewGoUnusedImportRule(fileSystem domain.FileSystemProvider) domain.CorrectionRule {
+	return &GoUnusedImportRule{fileSystem: fileSystem}
+}
+
+// WithFileSystem returns the same rule working on fileSystem
+func (r *GoUnusedImportRule) WithFileSystem(fileSystem domain.FileSystemProvider) domain.CorrectionRule {
+	return &GoUnusedImportRule{fileSystem: fileSystem}
+}
+
+func (r *GoUnusedImportRule) CanHandle(errDetails *domain.ErrorDetails) bool {
+	return goUnusedImportPattern.MatchString(errDetails.Message)
+}
+
+func (r *GoUnusedImportRule) ApplyCorrection(errDetails *domain.ErrorDetails, projectPath string) (*domain.CorrectionResult, error) {
+	type unusedImport struct{ name, path string }
+	byFile := make(map[string][]unusedImport)
+	for _, m := range goUnusedImportPattern.FindAllStringSubmatch(errDetails.Message, -1) {
+		byFile[m[1]] = append(byFile[m[1]], unusedImport{name: m[3], path: m[2]})
+	}
+
+	var changed, messages []string
+	for _, file := range sortedKeys(byFile) {
+		imports := byFile[file]
+		ok, err := goFileEdit(r.fileSystem, resolveErrorPath(projectPath, file), func(fset *token.FileSet, f *ast.File) bool {
+			removed := false
+			for _, imp := range imports {
+				if astutil.DeleteNamedImport(fset, f, imp.name, imp.path) {
+					removed = true
+				}
+			}
+			return removed
+		})
+		if err != nil {
+			return &domain.CorrectionResult{Success: false, Message: err.Error(), FilesChanged: changed}, nil
+		}
+		if ok {
+			changed = append(changed, file)
+			for _, imp := range imports {
+				messages = append(messages, fmt.Sprintf("removed unused import %q from %s", imp.path, file))
+			}
+		}
+	}
+
+	if len(changed) == 0 {
+		return &domain.CorrectionResult{Success: false, Message: "No unused imports removed"}, nil
+	}
+	return &domain.CorrectionResult{Success: true, Message: strings.Join(messages, "; "), FilesChanged: changed}, nil
+}
+
+func (r *GoUnusedImportRule) GetPriority() int {
+	return 110
+}
+
+func (r *GoUnusedImportRule) GetErrorTypes() []domain.ErrorType {
+	return []domain.ErrorType{domain.ErrorTypeImport, domain.ErrorTypeCompilation}
+}
+
+// GoMissingImportRule adds an import for an identifier reported as "undefined"
+// when it is used as a package qualifier (strings.Split) and names exactly one
+// package of the module or a standard library package
+type GoMissingImportRule struct {
+	fileSystem domain.FileSystemProvider
+}
+
+func NewGoMissingImportRule(fileSystem domain.FileSystemProvider) domain.CorrectionRule {
+	return &GoMissingImportRule{fileSystem: fileSystem}
+}
+
+// WithFileSystem returns the same rule working on fileSystem
+func (r *GoMissingImportRule) WithFileSystem(fileSystem domain.FileSystemProvider) domain.CorrectionRule {
+	return &GoMissingImportRule{fileSystem: fileSystem}
+}
+
+func (r *GoMissingImportRule) CanHandle(errDetails *domain.ErrorDetails) bool {
+	return goUndefinedPattern.MatchString(errDetails.Message)
+}
+
+func (r *GoMissingImportRule) ApplyCorrection(errDetails *domain.ErrorDetails, projectPath string) (*domain.CorrectionResult, error) {
+	byFile := make(map[string][]string)
+	for _, m := range goUndefinedPattern.FindAllStringSubmatch(errDetails.Message, -1) {
+		byFile[m[1]] = append(byFile[m[1]], m[2])
+	}
+
+	var modulePackages map[string][]modulePackage
+	var changed, messages []string
+	for _, file := range sortedKeys(byFile) {
+		path := resolveErrorPath(projectPath, file)
+		ok, err := goFileEdit(r.fileSystem, path, func(fset *token.FileSet, f *ast.File) bool {
+			added := false
+			for _, name := range byFile[file] {
+				if !usedAsQualifier(f, name) {
+					continue
+				}
+				if modulePackages == nil {
+					modulePackages = scanModulePackages(findModuleRoot(projectPath, path, moduleMarkers[langGo]))
+				}
+				importPath := resolvePackage(name, modulePackages, filepath.Dir(path))
+				if importPath == "" {
+					continue
+				}
+				if astutil.AddImport(fset, f, importPath) {
+					added = true
+					messages = append(messages, fmt.Sprintf("added import %q to %s", importPath, file))
+				}
+			}
+			return added
+		})
+		if err != nil {
+			return &domain.CorrectionResult{Success: false, Message: err.Error(), FilesChanged: changed}, nil
+		}
+		if ok {
+			changed = append(changed, file)
+		}
+	}
+
+	if len(changed) == 0 {
+		return &domain.CorrectionResult{Success: false, Message: "No missing imports resolved"}, nil
+	}
+	return &domain.CorrectionResult{Success: true, Message: strings.Join(messages, "; "), FilesChanged: changed}, nil
+}
+
+func (r *GoMissingImportRule) GetPriority() int {
+	return 105
+}
+
+func (r *GoMissingImportRule) GetErrorTypes() []domain.ErrorType {
+	return []domain.ErrorType{domain.ErrorTypeCompilation, domain.ErrorTypeImport}
+}
+
+// usedAsQualifier reports whether name is used as name.X in the file
+func usedAsQualifier(f *ast.File, name string) bool {
+	found := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == name {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// modulePackage is a package of the project's module
+type modulePackage struct {
+	dir        string
+	importPath string
+}
+
+// resolvePackage picks the import path for a package name: a single module
+// package (other than the file's own) wins over the standard library
+func resolvePackage(name string, modulePackages map[string][]modulePackage, fileDir string) string {
+	var candidates []string
+	for _, pkg := range modulePackages[name] {
+		if pkg.dir != fileDir {
+			candidates = append(candidates, pkg.importPath)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return goStdlibPackages[name]
+	case 1:
+		return candidates[0]
+	default:
+		return "" // ambiguous; leave it to the user
+	}
+}
+
+// scanModulePackages indexes the packages of the module at projectPath by name
+func scanModulePackages(projectPath string) map[string][]modulePackage {
+	packages := make(map[string][]modulePackage)
+
+	data, err := os.ReadFile(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return packages
+	}
+	modulePath := modfile.ModulePath(data)
+	if modulePath == "" {
+		return packages
+	}
+
+	_ = filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != projectPath && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+			name == "vendor" || name == "testdata" || name == "node_modules") {
+			return filepath.SkipDir
+		}
+
+		pkgName := packageNameInDir(path)
+		if pkgName == "" || pkgName == "main" {
+			return nil
+		}
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return nil
+		}
+		importPath := modulePath
+		if rel != "." {
+			importPath += "/" + filepath.ToSlash(rel)
+		}
+		packages[pkgName] = append(packages[pkgName], modulePackage{dir: path, importPath: importPath})
+		return nil
+	})
+	return packages
+}
+
+// packageNameInDir returns the package name of the first non-test Go file in dir
+func packageNameInDir(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), extGo) || strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, e.Name()), nil, parser.PackageClauseOnly)
+		if err == nil {
+			return f.Name.Name
+		}
+	}
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,168 @@
+package repair
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"shotgun_code/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGoUnusedImportRule(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\tstr \"strings\"\n)\n\nfunc main() { fmt.Println() }\n",
+	})
+	rule := NewGoUnusedImportRule(osFileSystem{})
+
+	errDetails := &domain.ErrorDetails{Message: "# example.com/m\n" +
+		"./main.go:5:2: \"os\" imported and not used\n" +
+		"./main.go:6:2: \"strings\" imported as str and not used\n"}
+	require.True(t, rule.CanHandle(errDetails))
+
+	result, err := rule.ApplyCorrection(errDetails, dir)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Message)
+	assert.Equal(t, []string{"./main.go"}, result.FilesChanged)
+	assert.Equal(t, "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() { fmt.Println() }\n",
+		readTestFile(t, filepath.Join(dir, "main.go")))
+
+	assert.False(t, rule.CanHandle(&domain.ErrorDetails{Message: "./main.go:8:2: undefined: strings"}))
+}
+
+func TestGoMissingImportRule(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"go.mod":                "module example.com/m\n\ngo 1.21\n",
+		"internal/text/text.go": "package text\n\nfunc Upper(s string) string { return s }\n",
+		"main.go":               "package main\n\nfunc main() {\n\tprintln(text.Upper(strings.TrimSpace(\" a \")))\n\tprintln(widget.Name)\n}\n",
+	})
+	rule := NewGoMissingImportRule(osFileSystem{})
+
+	errDetails := &domain.ErrorDetails{Message: "./main.go:4:10: undefined: text\n" +
+		"./main.go:4:21: undefined: strings\n" +
+		"./main.go:5:10: undefined: widget\n"}
+	require.True(t, rule.CanHandle(errDetails))
+
+	result, err := rule.ApplyCorrection(errDetails, dir)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Message)
+
+	content := readTestFile(t, filepath.Join(dir, "main.go"))
+	assert.Contains(t, content, "\"example.com/m/internal/text\"")
+	assert.Contains(t, content, "\"strings\"")
+	assert.NotContains(t, content, "widget\"", "unknown packages are left alone")
+}
+
+func TestGoMissingImportRule_AmbiguousPackage(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"go.mod":        "module example.com/m\n\ngo 1.21\n",
+		"a/util/u.go":   "package util\n",
+		"b/util/u.go":   "package util\n",
+		"cmd/x/main.go": "package main\n\nfunc main() { util.Do() }\n",
+	})
+	rule := NewGoMissingImportRule(osFileSystem{})
+
+	result, err := rule.ApplyCorrection(&domain.ErrorDetails{Message: "cmd/x/main.go:3:15: undefined: util"}, dir)
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+}
+
+func TestCorrectionEngine_ApplyRulesByPriority(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"main.go": "package main\n\nimport \"os\"\n\nfunc main() {}\n",
+	})
+	engine := newCorrectionEngine(&TestLogger{}, osFileSystem{})
+
+	output := "./main.go:3:8: \"os\" imported and not used"
+	errDetails := &domain.ErrorDetails{Message: output, ErrorType: NewErrorAnalyzer(&TestLogger{}).ClassifyErrorType(output)}
+	require.True(t, engine.CanHandle(errDetails))
+
+	result, err := engine.ApplyRules(context.Background(), errDetails, dir, false)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"./main.go"}, result.FilesChanged)
+	assert.NotContains(t, readTestFile(t, filepath.Join(dir, "main.go")), "\"os\"")
+}
+
+func TestCorrectionEngine_DryRunReturnsDiff(t *testing.T) {
+	dir := t.TempDir()
+	original := "package main\n\nimport \"os\"\n\nfunc main() {}\n"
+	writeTestFiles(t, dir, map[string]string{"main.go": original})
+	engine := newCorrectionEngine(&TestLogger{}, osFileSystem{})
+	// Previewing writes nothing, so safe mode doesn't block it
+	engine.SetSafeMode(domain.NewSafeMode(true))
+
+	output := "./main.go:3:8: \"os\" imported and not used"
+	errDetails := &domain.ErrorDetails{Message: output, ErrorType: NewErrorAnalyzer(&TestLogger{}).ClassifyErrorType(output)}
+	result, err := engine.ApplyRules(context.Background(), errDetails, dir, true)
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Contains(t, result.Diff, "--- a/main.go\n+++ b/main.go\n")
+	assert.Contains(t, result.Diff, "-import \"os\"\n")
+	assert.Equal(t, original, readTestFile(t, filepath.Join(dir, "main.go")))
+}
+
+// execRunner runs commands for real
+type execRunner struct{}
+
+func (execRunner) RunCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return execRunner{}.RunCommandInDir(ctx, "", name, args...)
+}
+
+func (execRunner) RunCommandInDir(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+func TestExecuteRepair_GoUnusedImport(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"go.mod":  "module example.com/m\n\ngo 1.21\n",
+		"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() { fmt.Println(strings.ToUpper(\"ok\")) }\n",
+	})
+
+	buildOutput, err := execRunner{}.RunCommandInDir(context.Background(), dir, "go", "build", "./...")
+	require.Error(t, err, "the project must not build before the repair")
+
+	service := NewService(&TestLogger{}, execRunner{})
+	result, err := service.ExecuteRepair(context.Background(), domain.RepairRequest{
+		ProjectPath: dir,
+		ErrorOutput: string(buildOutput),
+		Language:    "go",
+		MaxAttempts: 3,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Error)
+
+	content := readTestFile(t, filepath.Join(dir, "main.go"))
+	assert.NotContains(t, content, "\"os\"")
+	assert.Contains(t, content, "\"strings\"")
+}
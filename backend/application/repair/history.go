@@ -0,0 +1,77 @@
+package repair
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"shotgun_code/domain"
+	"sort"
+	"time"
+)
+
+// SetReportRepository подключает хранилище, в которое пишутся отчеты repair
+func (s *Service) SetReportRepository(reports domain.ReportRepository) {
+	s.reports = reports
+}
+
+// saveReport сохраняет отчет о запуске repair; ошибки только логируются
+func (s *Service) saveReport(ctx context.Context, report *domain.RepairReport) {
+	if s.reports == nil {
+		return
+	}
+
+	content, err := json.Marshal(report)
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to marshal repair report: %v", err))
+		return
+	}
+
+	summary := fmt.Sprintf("Repair failed after %d attempts", len(report.Attempts))
+	if report.Success {
+		summary = fmt.Sprintf("Repair succeeded after %d attempts", len(report.Attempts))
+	}
+	now := time.Now()
+	generic := &domain.GenericReport{
+		Id:        report.ID,
+		Title:     fmt.Sprintf("Repair: %s", filepath.Base(report.ProjectPath)),
+		Type:      domain.RepairReportType,
+		Summary:   summary,
+		Content:   string(content),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.reports.SaveReport(ctx, generic); err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to save repair report: %v", err))
+	}
+}
+
+// GetRepairHistory возвращает отчеты repair для проекта, новые первыми
+func (s *Service) GetRepairHistory(ctx context.Context, projectPath string) ([]domain.RepairReport, error) {
+	history := []domain.RepairReport{}
+	if s.reports == nil {
+		return history, nil
+	}
+
+	reports, err := s.reports.ListReports(ctx, domain.RepairReportType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repair reports: %w", err)
+	}
+
+	projectPath = filepath.Clean(projectPath)
+	for _, r := range reports {
+		var report domain.RepairReport
+		if err := json.Unmarshal([]byte(r.Content), &report); err != nil {
+			s.log.Warning(fmt.Sprintf("Failed to parse repair report %s: %v", r.Id, err))
+			continue
+		}
+		if filepath.Clean(report.ProjectPath) == projectPath {
+			history = append(history, report)
+		}
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].StartedAt.After(history[j].StartedAt)
+	})
+	return history, nil
+}
@@ -0,0 +1,103 @@
+package repair
+
+import (
+	"context"
+	"errors"
+	"shotgun_code/domain"
+	"shotgun_code/testutils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryReportRepository keeps reports in memory
+type memoryReportRepository struct {
+	reports map[string]*domain.GenericReport
+}
+
+func newMemoryReportRepository() *memoryReportRepository {
+	return &memoryReportRepository{reports: make(map[string]*domain.GenericReport)}
+}
+
+func (r *memoryReportRepository) GetReport(_ context.Context, reportID string) (*domain.GenericReport, error) {
+	report, ok := r.reports[reportID]
+	if !ok {
+		return nil, errors.New("report not found")
+	}
+	return report, nil
+}
+
+func (r *memoryReportRepository) ListReports(_ context.Context, reportType string) ([]*domain.GenericReport, error) {
+	var reports []*domain.GenericReport
+	for _, report := range r.reports {
+		if reportType == "" || report.Type == reportType {
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+func (r *memoryReportRepository) SaveReport(_ context.Context, report *domain.GenericReport) error {
+	r.reports[report.Id] = report
+	return nil
+}
+
+func (r *memoryReportRepository) DeleteReport(_ context.Context, reportID string) error {
+	delete(r.reports, reportID)
+	return nil
+}
+
+func (r *memoryReportRepository) GetReportsByTask(_ context.Context, _ string) ([]*domain.GenericReport, error) {
+	return nil, nil
+}
+
+func TestExecuteRepair_WritesReport(t *testing.T) {
+	dir := t.TempDir()
+	runner := &testutils.MockCommandRunner{}
+	service := NewService(&TestLogger{}, runner).(*Service)
+	reports := newMemoryReportRepository()
+	service.SetReportRepository(reports)
+
+	tscOutput := "src/api.ts(12,5): error TS2307: Cannot find module 'lodash' or its corresponding type declarations.\n"
+	runner.On("RunCommandInDir", mock.Anything, dir, "npm", []string{"install"}).
+		Return([]byte{}, nil)
+	runner.On("RunCommandInDir", mock.Anything, dir, "npx", []string{"tsc", "--noEmit"}).
+		Return([]byte(tscOutput), &domain.CommandError{
+			Command: "npx", Stdout: []byte(tscOutput), ExitCode: 2, Err: errors.New("exit status 2"),
+		})
+
+	result, err := service.ExecuteRepair(t.Context(), domain.RepairRequest{
+		ProjectPath: dir,
+		ErrorOutput: tscOutput,
+		Language:    "typescript",
+		MaxAttempts: 1,
+	})
+	require.NoError(t, err)
+	require.False(t, result.Success)
+
+	history, err := service.GetRepairHistory(t.Context(), dir)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	report := history[0]
+	assert.Equal(t, dir, report.ProjectPath)
+	assert.False(t, report.Success)
+	assert.Equal(t, result.Error, report.Error)
+	require.Len(t, report.Attempts, 1)
+
+	attempt := report.Attempts[0]
+	assert.Equal(t, domain.ErrorTypeImport, attempt.ErrorType)
+	assert.Equal(t, domain.RepairBuildFailed, attempt.BuildBefore)
+	assert.Equal(t, domain.RepairBuildFailed, attempt.BuildAfter)
+	assert.Equal(t, tscOutput, attempt.ErrorsAfter)
+	require.Len(t, attempt.Corrections, 1)
+	assert.Equal(t, "ts-imports", attempt.Corrections[0].RuleID)
+	assert.Equal(t, []string{"package.json", "package-lock.json"}, attempt.Corrections[0].Files)
+
+	// Other projects have no history
+	other, err := service.GetRepairHistory(t.Context(), t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
@@ -0,0 +1,95 @@
+package repair
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// moduleMarkers are the files that mark the root of a module for a language
+var moduleMarkers = map[string][]string{
+	langGo:         {"go.mod"},
+	langTypeScript: {"package.json"},
+	langJavaScript: {"package.json"},
+}
+
+var (
+	// ./pkg/main.go:5:2: ... as printed by go build and go vet
+	goDiagnosticFilePattern = regexp.MustCompile(`(?m)^(.+?\.go):\d+(?::\d+)?:`)
+	// leading file path of a diagnostic line, used to rebase it onto the project
+	diagnosticPathPattern = regexp.MustCompile(`^(?:\./)?([^\s:()]+\.(?:go|tsx?|jsx?|vue))([:(])`)
+)
+
+// findModuleRoot walks up from file to the nearest directory holding one of
+// markers, never leaving projectPath; it falls back to projectPath
+func findModuleRoot(projectPath, file string, markers []string) string {
+	root := filepath.Clean(projectPath)
+	dir := filepath.Dir(resolveErrorPath(root, file))
+	if rel, err := filepath.Rel(root, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return root
+	}
+
+	for {
+		for _, marker := range markers {
+			if info, err := os.Stat(filepath.Join(dir, marker)); err == nil && !info.IsDir() {
+				return dir
+			}
+		}
+		if dir == root {
+			return root
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return root
+		}
+		dir = parent
+	}
+}
+
+// failingFiles returns the files reported in compiler output, in order of appearance
+func failingFiles(language, errorOutput string) []string {
+	var files []string
+	switch language {
+	case langGo:
+		for _, m := range goDiagnosticFilePattern.FindAllStringSubmatch(errorOutput, -1) {
+			files = append(files, strings.TrimSpace(m[1]))
+		}
+	case langTypeScript, langJavaScript:
+		for _, d := range parseTscOutput(errorOutput) {
+			if d.File != "" {
+				files = append(files, d.File)
+			}
+		}
+	}
+	return removeDuplicates(files)
+}
+
+// rebaseDiagnostics prefixes relative file paths in output produced in
+// moduleDir so they resolve against projectPath like the original errors
+func rebaseDiagnostics(output, projectPath, moduleDir string) string {
+	rel, err := filepath.Rel(projectPath, moduleDir)
+	if err != nil || rel == "." {
+		return output
+	}
+	prefix := filepath.ToSlash(rel) + "/"
+
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if m := diagnosticPathPattern.FindStringSubmatchIndex(line); m != nil && !filepath.IsAbs(line[m[2]:m[3]]) {
+			lines[i] = prefix + line[m[2]:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sortedModuleDirs returns the distinct module directories in a stable order
+func sortedModuleDirs(dirs map[string]bool) []string {
+	out := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		out = append(out, dir)
+	}
+	sort.Strings(out)
+	return out
+}
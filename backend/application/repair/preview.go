@@ -0,0 +1,111 @@
+package repair
+
+import (
+	"fmt"
+	"path/filepath"
+	"shotgun_code/domain"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// previewFileSystem keeps writes in memory on top of a real file system, so
+// corrections can run without touching the project
+type previewFileSystem struct {
+	base    domain.FileSystemProvider
+	pending map[string][]byte
+}
+
+func newPreviewFileSystem(base domain.FileSystemProvider) *previewFileSystem {
+	return &previewFileSystem{base: base, pending: make(map[string][]byte)}
+}
+
+func (p *previewFileSystem) ReadFile(filename string) ([]byte, error) {
+	if content, ok := p.pending[filepath.Clean(filename)]; ok {
+		return content, nil
+	}
+	return p.base.ReadFile(filename)
+}
+
+func (p *previewFileSystem) WriteFile(filename string, data []byte, _ int) error {
+	p.pending[filepath.Clean(filename)] = append([]byte(nil), data...)
+	return nil
+}
+
+func (p *previewFileSystem) MkdirAll(string, int) error {
+	return nil
+}
+
+// diff returns a unified diff of the pending writes against the files on
+// disk, with paths relative to projectPath
+func (p *previewFileSystem) diff(projectPath string) (string, error) {
+	paths := make([]string, 0, len(p.pending))
+	for path := range p.pending {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var out strings.Builder
+	for _, path := range paths {
+		var beforeLines []string
+		fromFile := "/dev/null"
+		name := path
+		if rel, err := filepath.Rel(projectPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+			name = filepath.ToSlash(rel)
+		}
+
+		if before, err := p.base.ReadFile(path); err == nil {
+			beforeLines = difflib.SplitLines(string(before))
+			fromFile = "a/" + name
+		}
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        beforeLines,
+			B:        difflib.SplitLines(string(p.pending[path])),
+			FromFile: fromFile,
+			ToFile:   "b/" + name,
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(text)
+	}
+	return out.String(), nil
+}
+
+// preview runs apply on a copy of the engine whose writes stay in memory and
+// attaches the resulting diff to the result
+func (c *CorrectionEngine) preview(projectPath string, apply func(engine *CorrectionEngine) (*domain.CorrectionResult, error)) (*domain.CorrectionResult, error) {
+	fs := newPreviewFileSystem(c.fileSystem)
+	result, err := apply(c.withFileSystem(fs))
+	if err != nil || result == nil {
+		return result, err
+	}
+	if result.Diff, err = fs.diff(projectPath); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// withFileSystem returns a copy of the engine that reads and writes through
+// fileSystem. Rules that cannot be bound to it (see FileSystemRule) are left
+// out, since they would write to the project
+func (c *CorrectionEngine) withFileSystem(fileSystem domain.FileSystemProvider) *CorrectionEngine {
+	engine := &CorrectionEngine{
+		log:             c.log,
+		fileSystem:      fileSystem,
+		correctionRules: make(map[domain.ErrorType][]domain.CorrectionRule, len(c.correctionRules)),
+	}
+	for errType, rules := range c.correctionRules {
+		for _, rule := range rules {
+			previewable, ok := rule.(FileSystemRule)
+			if !ok {
+				c.log.Debug(fmt.Sprintf("Correction rule %s cannot be previewed, leaving it out", ruleName(rule)))
+				continue
+			}
+			engine.correctionRules[errType] = append(engine.correctionRules[errType], previewable.WithFileSystem(fileSystem))
+		}
+	}
+	return engine
+}
@@ -0,0 +1,195 @@
+package repair
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"shotgun_code/domain"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FileSystemRule is implemented by correction rules that do all their file IO
+// through a domain.FileSystemProvider. Such a rule can run on a preview of the
+// project, so its edits can be checked for conflicts and merged with those of
+// other rules; other rules only run when no previewable rule edits the code
+type FileSystemRule interface {
+	WithFileSystem(fileSystem domain.FileSystemProvider) domain.CorrectionRule
+}
+
+// lineRange is a half-open range [start, end) of lines of a file as it is on
+// disk; an insertion has start == end
+type lineRange struct {
+	start, end int
+}
+
+// touches reports whether two edits overlap or sit on adjacent lines
+func (r lineRange) touches(other lineRange) bool {
+	return r.start <= other.end && other.start <= r.end
+}
+
+// lineHunk replaces a range of lines of a file on disk with new lines
+type lineHunk struct {
+	lineRange
+	lines []string
+}
+
+// ruleEdit is what a rule changes when tried on a preview of the project
+type ruleEdit struct {
+	rule   domain.CorrectionRule
+	result *domain.CorrectionResult // nil if the rule could not be previewed
+	hunks  map[string][]lineHunk    // by file path, against the files on disk
+}
+
+// changedHunks returns the lines of each pending file that differ from the file on disk
+func (p *previewFileSystem) changedHunks() map[string][]lineHunk {
+	hunks := make(map[string][]lineHunk, len(p.pending))
+	for path, content := range p.pending {
+		var before []string
+		if data, err := p.base.ReadFile(path); err == nil {
+			before = splitLines(string(data))
+		}
+		after := splitLines(string(content))
+		matcher := difflib.NewMatcher(before, after)
+		for _, op := range matcher.GetOpCodes() {
+			if op.Tag != 'e' {
+				hunks[path] = append(hunks[path], lineHunk{
+					lineRange: lineRange{start: op.I1, end: op.I2},
+					lines:     after[op.J1:op.J2],
+				})
+			}
+		}
+	}
+	return hunks
+}
+
+// tryRules runs each rule that can handle the error on its own preview of the
+// project, in priority order, and records the lines it would change. Every
+// preview starts from the files on disk, so the line numbers in the error
+// output hold for each rule.
+func (c *CorrectionEngine) tryRules(ctx context.Context, errDetails *domain.ErrorDetails, projectPath string) ([]ruleEdit, error) {
+	var edits []ruleEdit
+	for _, rule := range c.correctionRules[errDetails.ErrorType] {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !rule.CanHandle(errDetails) {
+			continue
+		}
+
+		previewable, ok := rule.(FileSystemRule)
+		if !ok {
+			edits = append(edits, ruleEdit{rule: rule})
+			continue
+		}
+		fs := newPreviewFileSystem(c.fileSystem)
+		result, err := previewable.WithFileSystem(fs).ApplyCorrection(errDetails, projectPath)
+		if err != nil {
+			c.log.Warning(fmt.Sprintf("Correction rule %s failed: %v", ruleName(rule), err))
+			continue
+		}
+		if !result.Success {
+			c.log.Debug(fmt.Sprintf("Correction rule %s did not apply: %s", ruleName(rule), result.Message))
+			continue
+		}
+		edits = append(edits, ruleEdit{rule: rule, result: result, hunks: fs.changedHunks()})
+	}
+	return edits, nil
+}
+
+// mergeEdits applies the hunks of all kept rules to the files on disk in one
+// pass and returns the new content of each changed file
+func (c *CorrectionEngine) mergeEdits(kept []ruleEdit) (map[string][]byte, error) {
+	byFile := make(map[string][]lineHunk)
+	for _, edit := range kept {
+		for path, hunks := range edit.hunks {
+			byFile[path] = append(byFile[path], hunks...)
+		}
+	}
+
+	merged := make(map[string][]byte, len(byFile))
+	for path, hunks := range byFile {
+		var lines []string
+		if data, err := c.fileSystem.ReadFile(path); err == nil {
+			lines = splitLines(string(data))
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		// Kept hunks never touch each other, so applying them from the end of
+		// the file keeps the line numbers of the remaining ones valid
+		sort.Slice(hunks, func(i, j int) bool { return hunks[i].start > hunks[j].start })
+		for _, hunk := range hunks {
+			lines = slices.Concat(lines[:hunk.start], hunk.lines, lines[hunk.end:])
+		}
+		merged[path] = []byte(strings.Join(lines, ""))
+	}
+	return merged, nil
+}
+
+// splitLines splits text into lines that keep their line endings, so joining
+// them gives back the text
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// selectRules keeps rules in priority order, skipping each rule whose edits
+// touch lines already changed by a kept rule. Rules that change no file, or
+// could not be previewed, only stand in when no rule edits the code.
+func selectRules(edits []ruleEdit, projectPath string) (kept []ruleEdit, skipped []string) {
+	for _, edit := range edits {
+		if owner, file := findConflict(edit, kept); owner != nil {
+			skipped = append(skipped, fmt.Sprintf("%s (overlaps %s in %s)", ruleName(edit.rule), ruleName(owner.rule), relativePath(projectPath, file)))
+			continue
+		}
+		kept = append(kept, edit)
+	}
+
+	var editing []ruleEdit
+	for _, edit := range kept {
+		if len(edit.hunks) > 0 {
+			editing = append(editing, edit)
+		}
+	}
+	if len(editing) == 0 && len(kept) > 0 {
+		return kept[:1], skipped
+	}
+	return editing, skipped
+}
+
+// findConflict returns the kept rule whose edits touch those of edit, and the file they share
+func findConflict(edit ruleEdit, kept []ruleEdit) (*ruleEdit, string) {
+	for i := range kept {
+		for file, hunks := range edit.hunks {
+			for _, hunk := range hunks {
+				for _, other := range kept[i].hunks[file] {
+					if hunk.touches(other.lineRange) {
+						return &kept[i], file
+					}
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+// ruleName returns the type name of a rule, e.g. GoUnusedImportRule
+func ruleName(rule domain.CorrectionRule) string {
+	name := fmt.Sprintf("%T", rule)
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func relativePath(projectPath, path string) string {
+	if rel, err := filepath.Rel(projectPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
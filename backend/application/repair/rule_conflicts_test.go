@@ -0,0 +1,144 @@
+package repair
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"shotgun_code/domain"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrectionEngine_SkipsOverlappingRules(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() { fmt.Println(strings.ToUpper(\"ok\")) }\n",
+	})
+	engine := newCorrectionEngine(&TestLogger{}, osFileSystem{})
+
+	// Both rules rewrite the import block on adjacent lines
+	output := "./main.go:5:2: \"os\" imported and not used\n./main.go:8:27: undefined: strings"
+	errDetails := &domain.ErrorDetails{Message: output, ErrorType: domain.ErrorTypeCompilation}
+	result, err := engine.ApplyRules(context.Background(), errDetails, dir, false)
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"GoMissingImportRule (overlaps GoUnusedImportRule in main.go)"}, result.SkippedRules)
+	content := readTestFile(t, filepath.Join(dir, "main.go"))
+	assert.NotContains(t, content, "\"os\"")
+	assert.NotContains(t, content, "\"strings\"")
+}
+
+func TestCorrectionEngine_AppliesNonOverlappingRules(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"a.go": "package main\n\nimport \"os\"\n\nfunc main() {}\n",
+		"b.go": "package main\n\nfunc upper() string { return strings.ToUpper(\"ok\") }\n",
+	})
+	engine := newCorrectionEngine(&TestLogger{}, osFileSystem{})
+
+	output := "./a.go:3:8: \"os\" imported and not used\n./b.go:3:30: undefined: strings"
+	errDetails := &domain.ErrorDetails{Message: output, ErrorType: domain.ErrorTypeCompilation}
+	result, err := engine.ApplyRules(context.Background(), errDetails, dir, false)
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Empty(t, result.SkippedRules)
+	assert.ElementsMatch(t, []string{"./a.go", "./b.go"}, result.FilesChanged)
+	assert.NotContains(t, readTestFile(t, filepath.Join(dir, "a.go")), "\"os\"")
+	assert.Contains(t, readTestFile(t, filepath.Join(dir, "b.go")), "\"strings\"")
+}
+
+// deleteLineRule deletes the line reported as "<file>:<line>: debug call"
+type deleteLineRule struct {
+	fileSystem domain.FileSystemProvider
+}
+
+var debugCallPattern = regexp.MustCompile(`(\S+\.go):(\d+):\d+: debug call`)
+
+func (r *deleteLineRule) WithFileSystem(fileSystem domain.FileSystemProvider) domain.CorrectionRule {
+	return &deleteLineRule{fileSystem: fileSystem}
+}
+
+func (r *deleteLineRule) CanHandle(errDetails *domain.ErrorDetails) bool {
+	return debugCallPattern.MatchString(errDetails.Message)
+}
+
+func (r *deleteLineRule) ApplyCorrection(errDetails *domain.ErrorDetails, projectPath string) (*domain.CorrectionResult, error) {
+	m := debugCallPattern.FindStringSubmatch(errDetails.Message)
+	path := resolveErrorPath(projectPath, m[1])
+	content, err := r.fileSystem.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	line, _ := strconv.Atoi(m[2])
+	lines := strings.SplitAfter(string(content), "\n")
+	lines = append(lines[:line-1], lines[line:]...)
+	if err := r.fileSystem.WriteFile(path, []byte(strings.Join(lines, "")), 0o644); err != nil {
+		return nil, err
+	}
+	return &domain.CorrectionResult{Success: true, Message: "removed debug call", FilesChanged: []string{m[1]}}, nil
+}
+
+func (r *deleteLineRule) GetPriority() int { return 10 }
+
+func (r *deleteLineRule) GetErrorTypes() []domain.ErrorType {
+	return []domain.ErrorType{domain.ErrorTypeCompilation}
+}
+
+// directRule changes no file through the engine and cannot be previewed
+type directRule struct{ applied bool }
+
+func (r *directRule) CanHandle(*domain.ErrorDetails) bool { return true }
+
+func (r *directRule) ApplyCorrection(*domain.ErrorDetails, string) (*domain.CorrectionResult, error) {
+	r.applied = true
+	return &domain.CorrectionResult{Success: true, Message: "direct fix"}, nil
+}
+
+func (r *directRule) GetPriority() int { return 200 }
+
+func (r *directRule) GetErrorTypes() []domain.ErrorType {
+	return []domain.ErrorType{domain.ErrorTypeLinting}
+}
+
+func TestCorrectionEngine_MergesRulesAgainstOriginalLines(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() {\n\tfmt.Println(\"ok\")\n\tprintln(\"debug\")\n}\n",
+	})
+	engine := newCorrectionEngine(&TestLogger{}, osFileSystem{})
+	engine.registerRule(&deleteLineRule{fileSystem: osFileSystem{}})
+
+	// Removing the import shifts the debug call up one line; the second rule
+	// must still delete the line reported by the compiler
+	output := "./main.go:5:2: \"os\" imported and not used\n./main.go:10:2: debug call"
+	errDetails := &domain.ErrorDetails{Message: output, ErrorType: domain.ErrorTypeCompilation}
+	result, err := engine.ApplyRules(context.Background(), errDetails, dir, false)
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Empty(t, result.SkippedRules)
+	assert.Equal(t, "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tfmt.Println(\"ok\")\n}\n", readTestFile(t, filepath.Join(dir, "main.go")))
+}
+
+func TestCorrectionEngine_RunsRuleThatCannotBePreviewed(t *testing.T) {
+	engine := newCorrectionEngine(&TestLogger{}, osFileSystem{})
+	rule := &directRule{}
+	engine.registerRule(rule)
+	errDetails := &domain.ErrorDetails{Message: "lint failed", ErrorType: domain.ErrorTypeLinting}
+
+	preview, err := engine.ApplyRules(context.Background(), errDetails, t.TempDir(), true)
+	require.NoError(t, err)
+	assert.True(t, preview.Success)
+	assert.False(t, rule.applied, "dry run must not run a rule that writes outside the preview")
+
+	result, err := engine.ApplyRules(context.Background(), errDetails, t.TempDir(), false)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.True(t, rule.applied)
+}
@@ -0,0 +1,74 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"shotgun_code/domain"
+)
+
+// sampleProjectRoot виртуальный корень, к которому привязаны образцы файлов
+var sampleProjectRoot = filepath.FromSlash("/shotgun-rule-sample")
+
+// sampleFileSystem файловая система в памяти с образцами файлов для проверки правил
+type sampleFileSystem map[string][]byte
+
+func newSampleFileSystem(files map[string]string) sampleFileSystem {
+	fsys := make(sampleFileSystem, len(files))
+	for name, content := range files {
+		fsys[filepath.Join(sampleProjectRoot, filepath.FromSlash(name))] = []byte(content)
+	}
+	return fsys
+}
+
+func (f sampleFileSystem) ReadFile(filename string) ([]byte, error) {
+	content, ok := f[filepath.Clean(filename)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: filename, Err: fs.ErrNotExist}
+	}
+	return content, nil
+}
+
+func (f sampleFileSystem) WriteFile(filename string, data []byte, _ int) error {
+	f[filepath.Clean(filename)] = append([]byte(nil), data...)
+	return nil
+}
+
+func (sampleFileSystem) MkdirAll(string, int) error {
+	return nil
+}
+
+// TestRepairRule применяет исправление правила (Fix) к образцам файлов в памяти
+// и возвращает, что оно сделало бы; проект и диск не затрагиваются. Пути в sampleFiles и
+// sampleError задаются относительно корня проекта
+func (s *Service) TestRepairRule(rule domain.RepairRule, sampleError string, sampleFiles map[string]string) (*domain.RepairResult, error) {
+	if err := s.ValidateRule(rule); err != nil {
+		return nil, err
+	}
+
+	result := &domain.RepairResult{RuleID: rule.ID, DryRun: true, Attempts: 1}
+	if !s.matchesError(sampleError, rule) {
+		result.Error = "rule pattern does not match the sample error"
+		return result, nil
+	}
+	if rule.Category != "correction" {
+		result.Error = fmt.Sprintf("rule matches, but %q rules run external tools and cannot be tested on sample files", rule.Category)
+		return result, nil
+	}
+
+	engine := newCorrectionEngine(s.log, newSampleFileSystem(sampleFiles))
+	correction, err := s.runCorrectionRule(context.Background(), engine, sampleProjectRoot, sampleError, rule, true)
+	if err != nil {
+		return nil, err
+	}
+	if !correction.Success {
+		result.Error = correction.Message
+		return result, nil
+	}
+
+	result.Success = true
+	result.FixedFiles = correction.FilesChanged
+	result.Diff = correction.Diff
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package repair
+
+import (
+	"shotgun_code/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestRepairRule_AppliesToSampleFiles(t *testing.T) {
+	service := NewService(&TestLogger{}, nil).(*Service)
+	rule := domain.RepairRule{ID: "unused", Name: "Unused imports", Pattern: `imported and not used`, Language: "go", Category: "correction"}
+	files := map[string]string{
+		"cmd/main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() { fmt.Println() }\n",
+	}
+
+	result, err := service.TestRepairRule(rule, "./cmd/main.go:5:2: \"os\" imported and not used\n", files)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Error)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, "unused", result.RuleID)
+	assert.Contains(t, result.Diff, "--- a/cmd/main.go")
+	assert.Contains(t, result.Diff, "-\t\"os\"")
+	assert.Contains(t, files["cmd/main.go"], "\"os\"", "sample files are not modified")
+}
+
+func TestTestRepairRule_ReportsWhyNothingApplies(t *testing.T) {
+	service := NewService(&TestLogger{}, nil).(*Service)
+	rule := domain.RepairRule{ID: "unused", Name: "Unused imports", Pattern: `imported and not used`, Category: "correction"}
+
+	result, err := service.TestRepairRule(rule, "undefined: foo", nil)
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Error, "does not match")
+
+	rule.Category = "format"
+	result, err = service.TestRepairRule(rule, "\"os\" imported and not used", nil)
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Error, "external tools")
+
+	_, err = service.TestRepairRule(domain.RepairRule{ID: "bad", Name: "Bad", Pattern: "("}, "", nil)
+	assert.Error(t, err)
+}
+
+func TestTestRepairRule_AppliesOnlyTheRuleFix(t *testing.T) {
+	service := NewService(&TestLogger{}, nil).(*Service)
+	files := map[string]string{
+		"main.go": "package main\n\nimport \"os\"\n\nfunc main() {}\n",
+	}
+	sampleError := "./main.go:3:8: \"os\" imported and not used\n"
+
+	rule := domain.RepairRule{ID: "lint", Name: "Lint", Pattern: `not used`, Fix: "lint", Category: "correction"}
+	result, err := service.TestRepairRule(rule, sampleError, files)
+	require.NoError(t, err)
+	assert.False(t, result.Success, "the lint fix does not remove imports")
+	assert.Empty(t, result.Diff)
+
+	rule.Fix = "imports"
+	result, err = service.TestRepairRule(rule, sampleError, files)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Error)
+	assert.Contains(t, result.Diff, "-import \"os\"")
+
+	rule.Fix = "rewrite everything"
+	_, err = service.TestRepairRule(rule, sampleError, files)
+	assert.ErrorContains(t, err, "unknown correction fix")
+}
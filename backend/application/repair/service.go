@@ -2,12 +2,15 @@ package repair
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
 	"shotgun_code/domain"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,6 +18,15 @@ import (
 type Service struct {
 	log           domain.Logger
 	commandRunner domain.CommandRunner
+	analyzers     map[string]domain.LanguageErrorAnalyzer
+	errorAnalyzer domain.ErrorAnalyzer
+	corrections   *CorrectionEngine
+	safeMode      *domain.SafeMode
+	reports       domain.ReportRepository
+
+	rulesMu     sync.RWMutex
+	customRules []domain.RepairRule
+	ruleStore   domain.RepairRuleStore
 }
 
 // NewService создает новый сервис repair
@@ -22,13 +34,33 @@ func NewService(log domain.Logger, commandRunner domain.CommandRunner) domain.Re
 	return &Service{
 		log:           log,
 		commandRunner: commandRunner,
+		analyzers: map[string]domain.LanguageErrorAnalyzer{
+			langGo:         NewGoErrorAnalyzer(),
+			langTypeScript: NewTypeScriptErrorAnalyzer(),
+			langJavaScript: NewJavaScriptErrorAnalyzer(),
+		},
+		errorAnalyzer: NewErrorAnalyzer(log),
+		corrections:   newCorrectionEngine(log, osFileSystem{}),
 	}
 }
 
+// SetSafeMode подключает переключатель safe mode к сервису и его correction engine
+func (s *Service) SetSafeMode(mode *domain.SafeMode) {
+	s.safeMode = mode
+	s.corrections.SetSafeMode(mode)
+}
+
 // ExecuteRepair выполняет repair цикл
 func (s *Service) ExecuteRepair(ctx context.Context, req domain.RepairRequest) (*domain.RepairResult, error) {
+	// Dry run ничего не записывает, поэтому доступен и в safe mode
+	if err := s.safeMode.Check("repairing projects"); err != nil && !req.DryRun {
+		return &domain.RepairResult{Success: false, Error: err.Error()}, err
+	}
 	startTime := time.Now()
 	s.log.Info(fmt.Sprintf("Starting repair cycle for project: %s", req.ProjectPath))
+	if req.Language == langTS {
+		req.Language = langTypeScript
+	}
 
 	// Проверяем существование проекта
 	if _, err := os.Stat(req.ProjectPath); os.IsNotExist(err) {
@@ -55,59 +87,128 @@ func (s *Service) ExecuteRepair(ctx context.Context, req domain.RepairRequest) (
 		return req.Rules[i].Priority > req.Rules[j].Priority
 	})
 
-	// Выполняем repair цикл
+	if req.DryRun {
+		result := s.previewRepair(ctx, req)
+		result.Duration = time.Since(startTime)
+		return result, nil
+	}
+
+	report := &domain.RepairReport{
+		ID:            fmt.Sprintf("repair_%d", startTime.UnixNano()),
+		ProjectPath:   req.ProjectPath,
+		Language:      req.Language,
+		StartedAt:     startTime,
+		InitialErrors: req.ErrorOutput,
+		Attempts:      []domain.RepairAttemptReport{},
+	}
+	result := s.runRepairCycle(ctx, req, report)
+	result.Duration = time.Since(startTime)
+
+	report.Success = result.Success
+	report.Error = result.Error
+	report.DurationMs = result.Duration.Milliseconds()
+	s.saveReport(ctx, report)
+
+	return result, nil
+}
+
+// runRepairCycle выполняет попытки repair, записывая каждую в report
+func (s *Service) runRepairCycle(ctx context.Context, req domain.RepairRequest, report *domain.RepairReport) *domain.RepairResult {
+	buildBefore := domain.RepairBuildFailed
+	// Модули, затронутые хотя бы одной попыткой; проверка собирает их все
+	affected := make(map[string]bool)
 	for attempt := 1; attempt <= req.MaxAttempts; attempt++ {
 		s.log.Info(fmt.Sprintf("Repair attempt %d/%d", attempt, req.MaxAttempts))
 
+		// Инструменты запускаются в модулях упавших файлов текущей попытки, а не в корне проекта
+		modules := s.moduleDirs(req.ProjectPath, req.Language, req.ErrorOutput)
+
 		// Анализируем ошибки и применяем правила
-		appliedRules := s.applyRepairRules(ctx, req.ProjectPath, req.ErrorOutput, req.Rules)
+		attemptReport := domain.RepairAttemptReport{
+			Attempt:     attempt,
+			ErrorType:   s.errorAnalyzer.ClassifyErrorType(req.ErrorOutput),
+			BuildBefore: buildBefore,
+			BuildAfter:  domain.RepairBuildSkipped,
+		}
+		attemptReport.Corrections = s.applyRepairRules(ctx, req.ProjectPath, modules, req.ErrorOutput, req.Rules)
 
-		if len(appliedRules) == 0 {
+		if len(attemptReport.Corrections) == 0 {
+			report.Attempts = append(report.Attempts, attemptReport)
 			s.log.Info("No applicable repair rules found")
 			break
 		}
 
-		// Проверяем, исправились ли ошибки
-		success, newErrors := s.verifyRepair(ctx, req.ProjectPath, req.Language)
+		// Проверяем, исправились ли ошибки и не сломались ли другие модули
+		s.markAffectedModules(affected, req.ProjectPath, req.Language, modules, attemptReport.Corrections)
+		success, newErrors := s.verifyRepair(ctx, req.ProjectPath, sortedModuleDirs(affected), req.Language)
 		if success {
-			duration := time.Since(startTime)
+			attemptReport.BuildAfter = domain.RepairBuildPassed
+			report.Attempts = append(report.Attempts, attemptReport)
 			return &domain.RepairResult{
 				Success:    true,
-				FixedFiles: appliedRules,
-				Duration:   duration,
+				FixedFiles: correctedFiles(attemptReport.Corrections),
 				Attempts:   attempt,
-			}, nil
+			}
 		}
+		attemptReport.BuildAfter = domain.RepairBuildFailed
+		attemptReport.ErrorsAfter = newErrors
+		report.Attempts = append(report.Attempts, attemptReport)
 
 		// Обновляем ошибки для следующей попытки
 		req.ErrorOutput = newErrors
+		buildBefore = attemptReport.BuildAfter
+	}
+
+	errMsg := "repair cycle completed but errors remain"
+	if remaining := s.describeErrors(req.Language, req.ErrorOutput); remaining != "" {
+		errMsg += ": " + remaining
 	}
 
-	duration := time.Since(startTime)
 	return &domain.RepairResult{
 		Success:  false,
-		Error:    "repair cycle completed but errors remain",
-		Duration: duration,
+		Error:    errMsg,
 		Attempts: req.MaxAttempts,
-	}, nil
+	}
 }
 
 // GetAvailableRules возвращает доступные правила для языка
 func (s *Service) GetAvailableRules(language string) ([]domain.RepairRule, error) {
 	rules := s.getDefaultRules(language)
-	return rules, nil
+	return append(rules, s.customRulesFor(language)...), nil
 }
 
-// AddRule добавляет новое правило
+// AddRule добавляет пользовательское правило; с подключенным хранилищем оно
+// сохраняется между запусками
 func (s *Service) AddRule(rule domain.RepairRule) error {
-	// В простой реализации просто логируем
-	s.log.Info(fmt.Sprintf("Adding repair rule: %s", rule.Name))
+	if err := s.ValidateRule(rule); err != nil {
+		return err
+	}
+
+	s.rulesMu.Lock()
+	defer s.rulesMu.Unlock()
+	if s.ruleExists(rule) {
+		return fmt.Errorf("repair rule with ID %s already exists", rule.ID)
+	}
+	if err := s.commitRules(append(slices.Clone(s.customRules), rule)); err != nil {
+		return err
+	}
+	s.log.Info(fmt.Sprintf("Added repair rule: %s", rule.Name))
 	return nil
 }
 
-// RemoveRule удаляет правило
+// RemoveRule удаляет пользовательское правило; встроенные правила не удаляются
 func (s *Service) RemoveRule(ruleID string) error {
-	s.log.Info(fmt.Sprintf("Removing repair rule: %s", ruleID))
+	s.rulesMu.Lock()
+	defer s.rulesMu.Unlock()
+
+	i := slices.IndexFunc(s.customRules, func(r domain.RepairRule) bool { return r.ID == ruleID })
+	if i < 0 {
+		return fmt.Errorf("custom repair rule with ID %s not found", ruleID)
+	}
+	if err := s.commitRules(slices.Delete(slices.Clone(s.customRules), i, i+1)); err != nil {
+		return err
+	}
+	s.log.Info(fmt.Sprintf("Removed repair rule: %s", ruleID))
 	return nil
 }
 
@@ -127,13 +228,18 @@ func (s *Service) ValidateRule(rule domain.RepairRule) error {
 	if _, err := regexp.Compile(rule.Pattern); err != nil {
 		return fmt.Errorf("invalid regex pattern: %w", err)
 	}
+	if rule.Category == "correction" {
+		if _, err := s.corrections.forFix(rule.Fix); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-// applyRepairRules применяет правила к проекту
-func (s *Service) applyRepairRules(ctx context.Context, projectPath, errorOutput string, rules []domain.RepairRule) []string {
-	var fixedFiles []string
+// applyRepairRules применяет правила к проекту и возвращает примененные правила
+func (s *Service) applyRepairRules(ctx context.Context, projectPath string, modules []string, errorOutput string, rules []domain.RepairRule) []domain.RepairCorrection {
+	var corrections []domain.RepairCorrection
 
 	for _, rule := range rules {
 		// Проверяем, подходит ли правило к ошибкам
@@ -142,17 +248,34 @@ func (s *Service) applyRepairRules(ctx context.Context, projectPath, errorOutput
 		}
 
 		// Применяем правило
-		files, err := s.applyRule(ctx, projectPath, rule)
+		files, err := s.applyRule(ctx, projectPath, modules, errorOutput, rule)
 		if err != nil {
 			s.log.Warning(fmt.Sprintf("Failed to apply rule %s: %v", rule.Name, err))
 			continue
 		}
+		if len(files) == 0 {
+			continue
+		}
 
-		fixedFiles = append(fixedFiles, files...)
+		corrections = append(corrections, domain.RepairCorrection{
+			RuleID:   rule.ID,
+			RuleName: rule.Name,
+			Category: rule.Category,
+			Files:    files,
+		})
 		s.log.Info(fmt.Sprintf("Applied rule %s to %d files", rule.Name, len(files)))
 	}
 
-	return fixedFiles
+	return corrections
+}
+
+// correctedFiles собирает файлы, измененные правилами
+func correctedFiles(corrections []domain.RepairCorrection) []string {
+	var files []string
+	for _, c := range corrections {
+		files = append(files, c.Files...)
+	}
+	return files
 }
 
 // matchesError проверяет, подходит ли правило к ошибкам
@@ -165,15 +288,51 @@ func (s *Service) matchesError(errorOutput string, rule domain.RepairRule) bool
 	return re.MatchString(errorOutput)
 }
 
+// moduleDirs возвращает корни модулей (go.mod, package.json) файлов из вывода
+// компилятора; корень проекта, если файлы не найдены
+func (s *Service) moduleDirs(projectPath, language, errorOutput string) []string {
+	dirs := make(map[string]bool)
+	for _, file := range failingFiles(language, errorOutput) {
+		dirs[s.corrections.ModuleRoot(projectPath, file, language)] = true
+	}
+	if len(dirs) == 0 {
+		return []string{projectPath}
+	}
+	return sortedModuleDirs(dirs)
+}
+
+// markAffectedModules добавляет в affected модули попытки и модули файлов,
+// измененных правилами correction; файлы инструментов (gofmt, npm install)
+// указаны относительно модулей попытки и уже учтены
+func (s *Service) markAffectedModules(affected map[string]bool, projectPath, language string, modules []string, corrections []domain.RepairCorrection) {
+	for _, dir := range modules {
+		affected[dir] = true
+	}
+	for _, c := range corrections {
+		if c.Category != "correction" {
+			continue
+		}
+		for _, file := range c.Files {
+			affected[s.corrections.ModuleRoot(projectPath, file, language)] = true
+		}
+	}
+}
+
 // applyRule применяет конкретное правило
-func (s *Service) applyRule(ctx context.Context, projectPath string, rule domain.RepairRule) ([]string, error) {
+func (s *Service) applyRule(ctx context.Context, projectPath string, modules []string, errorOutput string, rule domain.RepairRule) ([]string, error) {
 	var fixedFiles []string
 
 	switch rule.Category {
+	case "correction":
+		files, err := s.applyCorrectionRule(ctx, projectPath, errorOutput, rule)
+		if err != nil {
+			return nil, err
+		}
+		fixedFiles = files
 	case "format":
-		fixedFiles = s.applyFormatRule(ctx, projectPath, rule)
+		fixedFiles = s.applyFormatRule(ctx, modules, rule)
 	case "import":
-		fixedFiles = s.applyImportRule(ctx, projectPath, rule)
+		fixedFiles = s.applyImportRule(ctx, modules, rule)
 	case "syntax":
 		fixedFiles = s.applySyntaxRule(ctx, projectPath, rule)
 	default:
@@ -183,53 +342,112 @@ func (s *Service) applyRule(ctx context.Context, projectPath string, rule domain
 	return fixedFiles, nil
 }
 
-// applyFormatRule применяет правило форматирования
-func (s *Service) applyFormatRule(ctx context.Context, projectPath string, rule domain.RepairRule) []string {
+// applyFormatRule применяет правило форматирования в каждом модуле
+func (s *Service) applyFormatRule(ctx context.Context, modules []string, rule domain.RepairRule) []string {
 	var fixedFiles []string
 
 	// Определяем язык и применяем соответствующий форматтер
-	if strings.Contains(rule.Language, "go") {
-		// gofmt
-		_, err := s.commandRunner.RunCommandInDir(ctx, projectPath, "gofmt", "-w", ".")
-		if err == nil {
-			fixedFiles = append(fixedFiles, "*.go")
+	for _, dir := range modules {
+		if strings.Contains(rule.Language, "go") {
+			// gofmt
+			_, err := s.commandRunner.RunCommandInDir(ctx, dir, "gofmt", "-w", ".")
+			if err == nil {
+				fixedFiles = append(fixedFiles, "*.go")
+			}
+
+			// goimports
+			_, err = s.commandRunner.RunCommandInDir(ctx, dir, "goimports", "-w", ".")
+			if err == nil {
+				fixedFiles = append(fixedFiles, "*.go")
+			}
+		} else if strings.Contains(rule.Language, "typescript") || strings.Contains(rule.Language, "javascript") {
+			// prettier
+			_, err := s.commandRunner.RunCommandInDir(ctx, dir, "npx", "prettier", "--write", ".")
+			if err == nil {
+				fixedFiles = append(fixedFiles, "*.ts", "*.js", "*.vue")
+			}
 		}
+	}
 
-		// goimports
-		_, err = s.commandRunner.RunCommandInDir(ctx, projectPath, "goimports", "-w", ".")
-		if err == nil {
-			fixedFiles = append(fixedFiles, "*.go")
-		}
-	} else if strings.Contains(rule.Language, "typescript") || strings.Contains(rule.Language, "javascript") {
-		// prettier
-		_, err := s.commandRunner.RunCommandInDir(ctx, projectPath, "npx", "prettier", "--write", ".")
-		if err == nil {
-			fixedFiles = append(fixedFiles, "*.ts", "*.js", "*.vue")
+	return removeDuplicates(fixedFiles)
+}
+
+// applyImportRule применяет правило импортов в каждом модуле
+func (s *Service) applyImportRule(ctx context.Context, modules []string, rule domain.RepairRule) []string {
+	var fixedFiles []string
+
+	for _, dir := range modules {
+		if strings.Contains(rule.Language, "go") {
+			// go mod tidy
+			_, err := s.commandRunner.RunCommandInDir(ctx, dir, "go", "mod", "tidy")
+			if err == nil {
+				fixedFiles = append(fixedFiles, "go.mod", "go.sum")
+			}
+		} else if strings.Contains(rule.Language, "typescript") || strings.Contains(rule.Language, "javascript") {
+			// npm install
+			_, err := s.commandRunner.RunCommandInDir(ctx, dir, "npm", "install")
+			if err == nil {
+				fixedFiles = append(fixedFiles, "package.json", "package-lock.json")
+			}
 		}
 	}
 
-	return fixedFiles
+	return removeDuplicates(fixedFiles)
 }
 
-// applyImportRule применяет правило импортов
-func (s *Service) applyImportRule(ctx context.Context, projectPath string, rule domain.RepairRule) []string {
-	var fixedFiles []string
+// applyCorrectionRule исправляет ошибки из вывода компилятора правилами
+// CorrectionEngine, выбранными Fix правила
+func (s *Service) applyCorrectionRule(ctx context.Context, projectPath, errorOutput string, rule domain.RepairRule) ([]string, error) {
+	result, err := s.runCorrectionRule(ctx, s.corrections, projectPath, errorOutput, rule, false)
+	if err != nil || !result.Success {
+		return nil, err
+	}
+	s.log.Info(result.Message)
+	return result.FilesChanged, nil
+}
+
+// runCorrectionRule применяет к ошибкам правила engine, выбранные Fix правила
+func (s *Service) runCorrectionRule(ctx context.Context, engine *CorrectionEngine, projectPath, errorOutput string, rule domain.RepairRule, dryRun bool) (*domain.CorrectionResult, error) {
+	engine, err := engine.forFix(rule.Fix)
+	if err != nil {
+		return nil, err
+	}
+	errDetails := &domain.ErrorDetails{
+		Message:   errorOutput,
+		ErrorType: s.errorAnalyzer.ClassifyErrorType(errorOutput),
+	}
+	return engine.ApplyRules(ctx, errDetails, projectPath, dryRun)
+}
+
+// previewRepair показывает исправления correction-правил в виде diff, не изменяя
+// файлы; правила, запускающие инструменты (gofmt, go mod tidy, npm install), пропускаются
+func (s *Service) previewRepair(ctx context.Context, req domain.RepairRequest) *domain.RepairResult {
+	result := &domain.RepairResult{DryRun: true, Attempts: 1}
+	for _, rule := range req.Rules {
+		if !s.matchesError(req.ErrorOutput, rule) {
+			continue
+		}
+		if rule.Category != "correction" {
+			s.log.Info(fmt.Sprintf("Dry run: skipping rule %s, it runs external tools", rule.Name))
+			continue
+		}
 
-	if strings.Contains(rule.Language, "go") {
-		// go mod tidy
-		_, err := s.commandRunner.RunCommandInDir(ctx, projectPath, "go", "mod", "tidy")
-		if err == nil {
-			fixedFiles = append(fixedFiles, "go.mod", "go.sum")
+		correction, err := s.runCorrectionRule(ctx, s.corrections, req.ProjectPath, req.ErrorOutput, rule, true)
+		if err != nil {
+			result.Error = err.Error()
+			return result
 		}
-	} else if strings.Contains(rule.Language, "typescript") || strings.Contains(rule.Language, "javascript") {
-		// npm install
-		_, err := s.commandRunner.RunCommandInDir(ctx, projectPath, "npm", "install")
-		if err == nil {
-			fixedFiles = append(fixedFiles, "package.json", "package-lock.json")
+		if correction.Success {
+			result.Success = true
+			result.RuleID = rule.ID
+			result.FixedFiles = correction.FilesChanged
+			result.Diff = correction.Diff
+			return result
 		}
 	}
 
-	return fixedFiles
+	result.Error = "no corrections to preview"
+	return result
 }
 
 // applySyntaxRule применяет правило синтаксиса
@@ -239,16 +457,32 @@ func (s *Service) applySyntaxRule(ctx context.Context, projectPath string, rule
 	return []string{}
 }
 
-// verifyRepair проверяет, исправились ли ошибки
-func (s *Service) verifyRepair(ctx context.Context, projectPath, language string) (bool, string) {
+// verifyRepair проверяет, исправились ли ошибки, собирая каждый модуль;
+// пути в новых ошибках приводятся к корню проекта
+func (s *Service) verifyRepair(ctx context.Context, projectPath string, modules []string, language string) (bool, string) {
+	var failures []string
+	for _, dir := range modules {
+		ok, output := s.verifyModule(ctx, dir, language)
+		if !ok {
+			failures = append(failures, rebaseDiagnostics(output, projectPath, dir))
+		}
+	}
+	if len(failures) == 0 {
+		return true, ""
+	}
+	return false, strings.Join(failures, "\n")
+}
+
+// verifyModule собирает один модуль и возвращает его диагностики
+func (s *Service) verifyModule(ctx context.Context, dir, language string) (bool, string) {
 	var output []byte
 	var err error
 
 	switch language {
 	case langGo:
-		output, err = s.commandRunner.RunCommandInDir(ctx, projectPath, "go", "build", "./...")
+		output, err = s.commandRunner.RunCommandInDir(ctx, dir, "go", "build", "./...")
 	case langTypeScript, langJavaScript:
-		output, err = s.commandRunner.RunCommandInDir(ctx, projectPath, "npx", "tsc", "--noEmit")
+		output, err = s.commandRunner.RunCommandInDir(ctx, dir, "npx", "tsc", "--noEmit")
 	default:
 		return false, "unsupported language for verification"
 	}
@@ -257,14 +491,62 @@ func (s *Service) verifyRepair(ctx context.Context, projectPath, language string
 		return true, ""
 	}
 
+	// go build reports diagnostics to stderr, tsc to stdout
+	var cmdErr *domain.CommandError
+	if errors.As(err, &cmdErr) {
+		diagnostics := cmdErr.Stderr
+		if language != langGo {
+			diagnostics = cmdErr.Stdout
+		}
+		if len(diagnostics) > 0 {
+			return false, string(diagnostics)
+		}
+	}
 	return false, string(output)
 }
 
+// describeErrors сводит оставшиеся ошибки в предлагаемые исправления
+func (s *Service) describeErrors(language, errorOutput string) string {
+	analyzer, ok := s.analyzers[language]
+	if !ok || errorOutput == "" {
+		return ""
+	}
+	details, err := analyzer.AnalyzeError(errorOutput)
+	if err != nil {
+		return ""
+	}
+	details.Message = errorOutput
+	steps, err := analyzer.SuggestCorrections(details)
+	if err != nil {
+		return ""
+	}
+
+	descriptions := make([]string, 0, len(steps))
+	for _, step := range steps {
+		if step.Target != "" {
+			descriptions = append(descriptions, fmt.Sprintf("%s: %s", step.Target, step.Description))
+		} else {
+			descriptions = append(descriptions, step.Description)
+		}
+	}
+	return strings.Join(descriptions, "; ")
+}
+
 // getDefaultRules возвращает правила по умолчанию для языка
 func (s *Service) getDefaultRules(language string) []domain.RepairRule {
 	switch language {
 	case "go":
 		return []domain.RepairRule{
+			{
+				ID:          "go-fix-imports",
+				Name:        "Go Import Fixes",
+				Description: "Remove unused imports and add missing ones",
+				Pattern:     `imported (as \w+ )?and not used|undefined: `,
+				Fix:         "imports",
+				Priority:    95,
+				Language:    "go",
+				Category:    "correction",
+			},
 			{
 				ID:          "go-format",
 				Name:        "Go Format",
@@ -286,7 +568,7 @@ func (s *Service) getDefaultRules(language string) []domain.RepairRule {
 				Category:    "import",
 			},
 		}
-	case "typescript", "javascript":
+	case langTypeScript, langTS, langJavaScript:
 		return []domain.RepairRule{
 			{
 				ID:          "ts-format",
@@ -302,7 +584,7 @@ func (s *Service) getDefaultRules(language string) []domain.RepairRule {
 				ID:          "ts-imports",
 				Name:        "TypeScript Imports",
 				Description: "Fix TypeScript imports with npm install",
-				Pattern:     `Cannot find module|Module not found`,
+				Pattern:     `Cannot find module|Module not found|TS2307`,
 				Fix:         "imports",
 				Priority:    90,
 				Language:    "typescript",
@@ -313,3 +595,18 @@ func (s *Service) getDefaultRules(language string) []domain.RepairRule {
 		return []domain.RepairRule{}
 	}
 }
+
+// osFileSystem реализует FileSystemProvider поверх локальной файловой системы
+type osFileSystem struct{}
+
+func (osFileSystem) ReadFile(filename string) ([]byte, error) {
+	return os.ReadFile(filename)
+}
+
+func (osFileSystem) WriteFile(filename string, data []byte, perm int) error {
+	return os.WriteFile(filename, data, os.FileMode(perm))
+}
+
+func (osFileSystem) MkdirAll(path string, perm int) error {
+	return os.MkdirAll(path, os.FileMode(perm))
+}
@@ -0,0 +1,164 @@
+package repair
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"shotgun_code/testutils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteRepair_TypeScript(t *testing.T) {
+	dir := t.TempDir()
+	runner := &testutils.MockCommandRunner{}
+	service := NewService(&TestLogger{}, runner)
+
+	tscOutput := "src/api.ts(12,5): error TS2307: Cannot find module 'lodash' or its corresponding type declarations.\n"
+	runner.On("RunCommandInDir", mock.Anything, dir, "npm", []string{"install"}).
+		Return([]byte{}, nil)
+	runner.On("RunCommandInDir", mock.Anything, dir, "npx", []string{"tsc", "--noEmit"}).
+		Return([]byte(tscOutput), &domain.CommandError{
+			Command: "npx", Stdout: []byte(tscOutput), Stderr: []byte("npm warn exec\n"),
+			ExitCode: 2, Err: errors.New("exit status 2"),
+		})
+
+	result, err := service.ExecuteRepair(t.Context(), domain.RepairRequest{
+		ProjectPath: dir,
+		ErrorOutput: tscOutput,
+		Language:    "ts",
+		MaxAttempts: 1,
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Error, "src/api.ts: Install module 'lodash' or fix its import path")
+	runner.AssertCalled(t, "RunCommandInDir", mock.Anything, dir, "npm", []string{"install"})
+}
+
+func TestExecuteRepair_BlockedInSafeMode(t *testing.T) {
+	runner := &testutils.MockCommandRunner{}
+	service := NewService(&TestLogger{}, runner)
+	service.(domain.SafeModeSetter).SetSafeMode(domain.NewSafeMode(true))
+
+	result, err := service.ExecuteRepair(t.Context(), domain.RepairRequest{
+		ProjectPath: t.TempDir(),
+		ErrorOutput: "./main.go:3:8: \"os\" imported and not used",
+		Language:    "go",
+		MaxAttempts: 1,
+	})
+
+	var domainErr *domain.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, domain.ErrCodeSafeModeEnabled, domainErr.Code)
+	assert.False(t, result.Success)
+	runner.AssertNotCalled(t, "RunCommandInDir", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExecuteRepair_RunsToolsInFailingModule(t *testing.T) {
+	dir := t.TempDir()
+	webDir := filepath.Join(dir, "packages", "web")
+	require.NoError(t, os.MkdirAll(filepath.Join(webDir, "src"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(webDir, "package.json"), []byte("{}"), 0o644))
+	runner := &testutils.MockCommandRunner{}
+	service := NewService(&TestLogger{}, runner)
+
+	// tsc prints paths relative to the module it runs in
+	tscOutput := "src/api.ts(12,5): error TS2307: Cannot find module 'lodash' or its corresponding type declarations.\n"
+	runner.On("RunCommandInDir", mock.Anything, webDir, "npm", []string{"install"}).
+		Return([]byte{}, nil)
+	runner.On("RunCommandInDir", mock.Anything, webDir, "npx", []string{"tsc", "--noEmit"}).
+		Return([]byte(tscOutput), &domain.CommandError{
+			Command: "npx", Stdout: []byte(tscOutput), ExitCode: 2, Err: errors.New("exit status 2"),
+		})
+
+	result, err := service.ExecuteRepair(t.Context(), domain.RepairRequest{
+		ProjectPath: dir,
+		ErrorOutput: "packages/web/" + tscOutput,
+		Language:    "ts",
+		MaxAttempts: 1,
+	})
+
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Error, "packages/web/src/api.ts: Install module 'lodash'")
+	runner.AssertNotCalled(t, "RunCommandInDir", mock.Anything, dir, mock.Anything, mock.Anything)
+}
+
+func TestExecuteRepair_RecomputesModulesEachAttempt(t *testing.T) {
+	dir := t.TempDir()
+	webDir := filepath.Join(dir, "packages", "web")
+	apiDir := filepath.Join(dir, "packages", "api")
+	for _, module := range []string{webDir, apiDir} {
+		require.NoError(t, os.MkdirAll(filepath.Join(module, "src"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(module, "package.json"), []byte("{}"), 0o644))
+	}
+	runner := &testutils.MockCommandRunner{}
+	service := NewService(&TestLogger{}, runner)
+
+	// the first repair leaves web failing on an import from api
+	apiOutput := "../api/src/client.ts(3,1): error TS2307: Cannot find module 'zod' or its corresponding type declarations.\n"
+	runner.On("RunCommandInDir", mock.Anything, mock.Anything, "npm", []string{"install"}).
+		Return([]byte{}, nil)
+	runner.On("RunCommandInDir", mock.Anything, webDir, "npx", []string{"tsc", "--noEmit"}).
+		Return([]byte(apiOutput), &domain.CommandError{
+			Command: "npx", Stdout: []byte(apiOutput), ExitCode: 2, Err: errors.New("exit status 2"),
+		}).Once()
+	runner.On("RunCommandInDir", mock.Anything, mock.Anything, "npx", []string{"tsc", "--noEmit"}).
+		Return([]byte{}, nil)
+
+	result, err := service.ExecuteRepair(t.Context(), domain.RepairRequest{
+		ProjectPath: dir,
+		ErrorOutput: "packages/web/src/api.ts(12,5): error TS2307: Cannot find module 'lodash' or its corresponding type declarations.\n",
+		Language:    "ts",
+		MaxAttempts: 2,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 2, result.Attempts)
+	runner.AssertCalled(t, "RunCommandInDir", mock.Anything, apiDir, "npm", []string{"install"})
+	// the second attempt still verifies web, which the first attempt touched
+	runner.AssertNumberOfCalls(t, "RunCommandInDir", 5)
+}
+
+func TestModuleRoot(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "services", "api", "internal"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "services", "api", "go.mod"), []byte("module example.com/api\n"), 0o644))
+	engine := newCorrectionEngine(&TestLogger{}, osFileSystem{})
+
+	assert.Equal(t, filepath.Join(dir, "services", "api"), engine.ModuleRoot(dir, "services/api/internal/x.go", langGo))
+	assert.Equal(t, filepath.Clean(dir), engine.ModuleRoot(dir, "tools/gen.go", langGo))
+	assert.Equal(t, filepath.Clean(dir), engine.ModuleRoot(dir, "../outside/x.go", langGo))
+	assert.Equal(t, dir, engine.ModuleRoot(dir, "services/api/internal/x.go", "python"))
+}
+
+func TestExecuteRepair_DryRunPreviewsCorrections(t *testing.T) {
+	dir := t.TempDir()
+	original := "package main\n\nimport \"os\"\n\nfunc main() {}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(original), 0o644))
+	runner := &testutils.MockCommandRunner{}
+	service := NewService(&TestLogger{}, runner)
+
+	result, err := service.ExecuteRepair(t.Context(), domain.RepairRequest{
+		ProjectPath: dir,
+		ErrorOutput: "./main.go:3:8: \"os\" imported and not used",
+		Language:    "go",
+		MaxAttempts: 3,
+		DryRun:      true,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Error)
+	assert.True(t, result.DryRun)
+	assert.Contains(t, result.Diff, "-import \"os\"")
+	data, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, original, string(data))
+	runner.AssertNotCalled(t, "RunCommandInDir", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
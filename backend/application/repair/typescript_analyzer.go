@@ -0,0 +1,207 @@
+package repair
+
+import (
+	"fmt"
+	"regexp"
+	"shotgun_code/domain"
+	"strconv"
+	"strings"
+)
+
+// tscDiagnostic is a single diagnostic reported by tsc
+type tscDiagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Code    int
+	Message string
+}
+
+var (
+	// src/app.ts(12,5): error TS2304: Cannot find name 'foo'.
+	tscParenPattern = regexp.MustCompile(`^(.+?)\((\d+),(\d+)\): error TS(\d+): (.+)$`)
+	// src/app.ts:12:5 - error TS2304: Cannot find name 'foo'. (--pretty)
+	tscColonPattern = regexp.MustCompile(`^(.+?):(\d+):(\d+)(?::| -) error TS(\d+): (.+)$`)
+	// error TS5023: Unknown compiler option 'foo'.
+	tscBarePattern = regexp.MustCompile(`^error TS(\d+): (.+)$`)
+	// 'foo' in "Cannot find name 'foo'"
+	tscQuotedName = regexp.MustCompile(`'([^']+)'`)
+)
+
+// parseTscOutput extracts diagnostics from tsc output, ignoring other lines
+func parseTscOutput(output string) []tscDiagnostic {
+	var diags []tscDiagnostic
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := tscParenPattern.FindStringSubmatch(line); m != nil {
+			diags = append(diags, newTscDiagnostic(m[1], m[2], m[3], m[4], m[5]))
+		} else if m := tscColonPattern.FindStringSubmatch(line); m != nil {
+			diags = append(diags, newTscDiagnostic(m[1], m[2], m[3], m[4], m[5]))
+		} else if m := tscBarePattern.FindStringSubmatch(line); m != nil {
+			diags = append(diags, newTscDiagnostic("", "", "", m[1], m[2]))
+		}
+	}
+	return diags
+}
+
+func newTscDiagnostic(file, line, col, code, message string) tscDiagnostic {
+	d := tscDiagnostic{File: file, Message: strings.TrimSpace(message)}
+	d.Line, _ = strconv.Atoi(line)
+	d.Column, _ = strconv.Atoi(col)
+	d.Code, _ = strconv.Atoi(code)
+	return d
+}
+
+// classifyTscCode maps a tsc error code to an ErrorType
+func classifyTscCode(code int) domain.ErrorType {
+	switch code {
+	case 2304, 2305, 2307, 2503, 2552, 2580, 2582, 2583, 2584, 2614, 2724, 2792:
+		// Cannot find name/module/namespace, module has no exported member
+		return domain.ErrorTypeImport
+	case 2322, 2339, 2345, 2352, 2353, 2362, 2363, 2365, 2531, 2532, 2554, 2555,
+		2739, 2740, 2741, 2769, 18046, 18047, 18048:
+		// Not assignable, missing property, wrong arguments, possibly null
+		return domain.ErrorTypeTypeCheck
+	case 6133, 6138, 6192, 6196, 6198, 6199:
+		// Declared but never used
+		return domain.ErrorTypeLinting
+	}
+	if code >= 1000 && code < 2000 {
+		return domain.ErrorTypeSyntax
+	}
+	return domain.ErrorTypeCompilation
+}
+
+// TypeScriptErrorAnalyzer analyzes tsc output
+type TypeScriptErrorAnalyzer struct{}
+
+func NewTypeScriptErrorAnalyzer() domain.LanguageErrorAnalyzer {
+	return &TypeScriptErrorAnalyzer{}
+}
+
+// AnalyzeError describes the first tsc diagnostic in the output; it fails if
+// the output has no tsc diagnostics
+func (t *TypeScriptErrorAnalyzer) AnalyzeError(errorOutput string) (*domain.ErrorDetails, error) {
+	diags := parseTscOutput(errorOutput)
+	if len(diags) == 0 {
+		return nil, fmt.Errorf("no tsc diagnostics found")
+	}
+
+	first := diags[0]
+	details := &domain.ErrorDetails{
+		ErrorType:  classifyTscCode(first.Code),
+		Message:    errorOutput,
+		SourceFile: first.File,
+		LineNumber: first.Line,
+		Column:     first.Column,
+		Tool:       "tsc",
+		Severity:   "error",
+	}
+
+	seen := make(map[domain.ErrorType]bool)
+	for _, d := range diags {
+		errType := classifyTscCode(d.Code)
+		if seen[errType] {
+			continue
+		}
+		seen[errType] = true
+		details.Suggestions = append(details.Suggestions, tscSuggestion(errType))
+	}
+
+	return details, nil
+}
+
+func tscSuggestion(errType domain.ErrorType) string {
+	switch errType {
+	case domain.ErrorTypeImport:
+		return "Cannot find name or module - check imports, declarations and installed packages"
+	case domain.ErrorTypeTypeCheck:
+		return "Type error - check type compatibility of assignments and arguments"
+	case domain.ErrorTypeLinting:
+		return "Unused declaration - remove it or prefix it with an underscore"
+	case domain.ErrorTypeSyntax:
+		return "Syntax error - check brackets, commas and semicolons"
+	default:
+		return "Check the TypeScript compiler output"
+	}
+}
+
+// SuggestCorrections suggests a correction step for each tsc diagnostic
+func (t *TypeScriptErrorAnalyzer) SuggestCorrections(error *domain.ErrorDetails) ([]*domain.CorrectionStep, error) {
+	corrections := make([]*domain.CorrectionStep, 0)
+	seen := make(map[string]bool)
+
+	for _, d := range parseTscOutput(error.Message) {
+		target := d.File
+		if target == "" {
+			target = error.SourceFile
+		}
+		step := tscCorrection(d, target)
+		key := string(step.Action) + "|" + step.Target + "|" + step.Description
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		corrections = append(corrections, step)
+	}
+
+	return corrections, nil
+}
+
+func tscCorrection(d tscDiagnostic, target string) *domain.CorrectionStep {
+	name := ""
+	if m := tscQuotedName.FindStringSubmatch(d.Message); m != nil {
+		name = m[1]
+	}
+
+	step := &domain.CorrectionStep{Target: target}
+	switch classifyTscCode(d.Code) {
+	case domain.ErrorTypeImport:
+		step.Action = domain.ActionFixImport
+		switch {
+		case d.Code == 2307 && name != "":
+			step.Description = fmt.Sprintf("Install module '%s' or fix its import path", name)
+		case name != "":
+			step.Description = fmt.Sprintf("Add missing import or declaration for '%s'", name)
+		default:
+			step.Description = "Add missing import or declaration"
+		}
+	case domain.ErrorTypeTypeCheck:
+		step.Action = domain.ActionFixType
+		step.Description = fmt.Sprintf("Fix type error TS%d: %s", d.Code, d.Message)
+	case domain.ErrorTypeLinting:
+		step.Action = domain.ActionRemoveCode
+		if name != "" {
+			step.Description = fmt.Sprintf("Remove unused declaration '%s'", name)
+		} else {
+			step.Description = "Remove unused declaration"
+		}
+	case domain.ErrorTypeSyntax:
+		step.Action = domain.ActionFixSyntax
+		step.Description = fmt.Sprintf("Fix syntax error TS%d: %s", d.Code, d.Message)
+	default:
+		step.Action = domain.ActionAddMissingCode
+		step.Description = fmt.Sprintf("Fix TS%d: %s", d.Code, d.Message)
+	}
+	return step
+}
+
+func (t *TypeScriptErrorAnalyzer) ClassifyErrorType(errorOutput string) domain.ErrorType {
+	if diags := parseTscOutput(errorOutput); len(diags) > 0 {
+		return classifyTscCode(diags[0].Code)
+	}
+	if strings.Contains(errorOutput, "Cannot find") {
+		return domain.ErrorTypeImport
+	}
+	if strings.Contains(errorOutput, "Type") {
+		return domain.ErrorTypeTypeCheck
+	}
+	return domain.ErrorTypeCompilation
+}
+
+func (t *TypeScriptErrorAnalyzer) GetLanguage() string {
+	return langTypeScript
+}
@@ -0,0 +1,93 @@
+package repair
+
+import (
+	"shotgun_code/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTscOutput = `src/api.ts(3,10): error TS2307: Cannot find module './client' or its corresponding type declarations.
+src/api.ts(12,5): error TS2322: Type 'string' is not assignable to type 'number'.
+src/util.ts:7:9 - error TS6133: 'unused' is declared but its value is never read.
+src/util.ts(20,1): error TS1005: ';' expected.
+Found 4 errors in 2 files.`
+
+func TestParseTscOutput(t *testing.T) {
+	diags := parseTscOutput(testTscOutput)
+
+	require.Len(t, diags, 4)
+	assert.Equal(t, tscDiagnostic{
+		File: "src/api.ts", Line: 3, Column: 10, Code: 2307,
+		Message: "Cannot find module './client' or its corresponding type declarations.",
+	}, diags[0])
+	assert.Equal(t, "src/util.ts", diags[2].File)
+	assert.Equal(t, 7, diags[2].Line)
+	assert.Equal(t, 6133, diags[2].Code)
+}
+
+func TestClassifyTscCode(t *testing.T) {
+	tests := []struct {
+		code     int
+		expected domain.ErrorType
+	}{
+		{2304, domain.ErrorTypeImport},
+		{2307, domain.ErrorTypeImport},
+		{2322, domain.ErrorTypeTypeCheck},
+		{2345, domain.ErrorTypeTypeCheck},
+		{6133, domain.ErrorTypeLinting},
+		{1005, domain.ErrorTypeSyntax},
+		{5023, domain.ErrorTypeCompilation},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, classifyTscCode(tt.code), "TS%d", tt.code)
+	}
+}
+
+func TestTypeScriptErrorAnalyzer_TscOutput(t *testing.T) {
+	analyzer := NewTypeScriptErrorAnalyzer()
+
+	details, err := analyzer.AnalyzeError(testTscOutput)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ErrorTypeImport, details.ErrorType)
+	assert.Equal(t, "src/api.ts", details.SourceFile)
+	assert.Equal(t, 3, details.LineNumber)
+	assert.Equal(t, 10, details.Column)
+	assert.Len(t, details.Suggestions, 4)
+
+	steps, err := analyzer.SuggestCorrections(details)
+	require.NoError(t, err)
+	require.Len(t, steps, 4)
+	assert.Equal(t, domain.ActionFixImport, steps[0].Action)
+	assert.Equal(t, "Install module './client' or fix its import path", steps[0].Description)
+	assert.Equal(t, domain.ActionFixType, steps[1].Action)
+	assert.Equal(t, domain.ActionRemoveCode, steps[2].Action)
+	assert.Equal(t, "src/util.ts", steps[2].Target)
+	assert.Equal(t, "Remove unused declaration 'unused'", steps[2].Description)
+	assert.Equal(t, domain.ActionFixSyntax, steps[3].Action)
+}
+
+func TestTypeScriptErrorAnalyzer_RejectsOtherOutput(t *testing.T) {
+	analyzer := NewTypeScriptErrorAnalyzer()
+
+	_, err := analyzer.AnalyzeError("main.go:10:5: undefined: fmt")
+	assert.Error(t, err)
+}
+
+func TestErrorAnalyzer_ClassifiesTscOutput(t *testing.T) {
+	analyzer := NewErrorAnalyzer(&TestLogger{})
+
+	output := "src/util.ts(7,9): error TS6133: 'unused' is declared but its value is never read."
+	details, err := analyzer.AnalyzeError(output, domain.StageBuilding)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ErrorTypeLinting, details.ErrorType)
+	assert.Equal(t, "src/util.ts", details.SourceFile)
+	assert.Equal(t, 7, details.LineNumber)
+
+	steps, err := analyzer.SuggestCorrections(details)
+	require.NoError(t, err)
+	require.NotEmpty(t, steps)
+	assert.Equal(t, domain.ActionRemoveCode, steps[0].Action)
+}
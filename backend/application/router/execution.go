@@ -26,7 +26,7 @@ func (r *PlannerService) ExecutePipeline(ctx context.Context, pipeline *TaskPipe
 // executePipelineSequential выполняет пайплайн последовательно
 func (r *PlannerService) executePipelineSequential(ctx context.Context, pipeline *TaskPipeline) error {
 	for _, step := range pipeline.Steps {
-		if err := r.executeStep(ctx, step); err != nil {
+		if err := r.executeStep(domain.WithCommandStep(ctx, pipeline.TaskID, step.ID), step); err != nil {
 			if pipeline.Policy.FailFast {
 				pipeline.Status = PipelineStatusFailed
 				pipeline.Error = err.Error()
@@ -57,6 +57,7 @@ func (r *PlannerService) executePipelineParallel(ctx context.Context, pipeline *
 // executeStep выполняет один шаг пайплайна
 func (r *PlannerService) executeStep(ctx context.Context, step *TaskPipelineStep) error {
 	r.log.Info(fmt.Sprintf("Executing step: %s (%s)", step.Name, step.ID))
+	domain.LogTaskStep(ctx, domain.TaskLogInfo, step.ID, fmt.Sprintf("Step %s started", step.Name))
 
 	now := time.Now()
 	step.StartedAt = &now
@@ -93,6 +94,7 @@ func (r *PlannerService) executeStep(ctx context.Context, step *TaskPipelineStep
 		step.Status = StepStatusFailed
 		step.Error = err.Error()
 		r.log.Error(fmt.Sprintf("Step %s failed: %v", step.ID, err))
+		domain.LogTaskStep(ctx, domain.TaskLogError, step.ID, fmt.Sprintf("Step %s failed after %s: %v", step.Name, step.Duration, err))
 	} else {
 		step.Status = StepStatusCompleted
 		step.Result = &TaskPipelineStepResult{
@@ -100,6 +102,7 @@ func (r *PlannerService) executeStep(ctx context.Context, step *TaskPipelineStep
 			Message: fmt.Sprintf("Step %s completed successfully", step.Name),
 		}
 		r.log.Info(fmt.Sprintf("Step %s completed successfully", step.ID))
+		domain.LogTaskStep(ctx, domain.TaskLogInfo, step.ID, fmt.Sprintf("Step %s finished in %s", step.Name, step.Duration))
 	}
 
 	return err
@@ -131,7 +134,7 @@ func (r *PlannerService) executeCompileStep(ctx context.Context, step *TaskPipel
 		language = "go"
 	}
 
-	result, err := r.buildService.Build(ctx, projectPath, language)
+	result, err := r.buildService.Build(ctx, projectPath, language, buildTargetFromConfig(step.Config))
 	if err != nil {
 		return fmt.Errorf("build failed: %w", err)
 	}
@@ -140,7 +143,8 @@ func (r *PlannerService) executeCompileStep(ctx context.Context, step *TaskPipel
 		Success: result.Success,
 		Message: result.Output,
 		Data: map[string]any{
-			"duration": result.Duration,
+			"duration":  result.Duration,
+			"cache_hit": result.CacheHit,
 		},
 		Artifacts: result.Artifacts,
 	}
@@ -152,6 +156,32 @@ func (r *PlannerService) executeCompileStep(ctx context.Context, step *TaskPipel
 	return nil
 }
 
+// buildTargetFromConfig читает build-теги, GOOS/GOARCH и флаг clean из конфигурации шага.
+// Теги принимаются списком или строкой через запятую
+func buildTargetFromConfig(config map[string]any) domain.BuildTarget {
+	var target domain.BuildTarget
+	switch tags := config["build_tags"].(type) {
+	case []string:
+		target.Tags = tags
+	case []any:
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok && s != "" {
+				target.Tags = append(target.Tags, s)
+			}
+		}
+	case string:
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				target.Tags = append(target.Tags, tag)
+			}
+		}
+	}
+	target.GOOS, _ = config["goos"].(string)
+	target.GOARCH, _ = config["goarch"].(string)
+	target.Clean, _ = config["clean"].(bool)
+	return target
+}
+
 // executeTestStep выполняет шаг тестирования
 func (r *PlannerService) executeTestStep(ctx context.Context, step *TaskPipelineStep) error {
 	projectPath, ok := step.Config["project_path"].(string)
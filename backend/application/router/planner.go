@@ -304,6 +304,10 @@ func (r *PlannerService) createPipelineSteps(task domain.Task, policy *PipelineP
 			"project_path": task.Metadata["project_path"],
 			"language":     "go",
 			"build_mode":   "debug",
+			"build_tags":   task.Metadata["build_tags"],
+			"goos":         task.Metadata["goos"],
+			"goarch":       task.Metadata["goarch"],
+			"clean":        task.Metadata["clean_build"],
 		})
 	}
 
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"net"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -57,61 +59,42 @@ func createEmbeddingText(chunk domain.CodeChunk) string {
 
 // applyFilters applies search filters to results
 func (s *ServiceImpl) applyFilters(results []domain.SemanticSearchResult, filters *domain.SearchFilters) []domain.SemanticSearchResult {
-	if filters == nil {
+	if filters.IsEmpty() {
 		return results
 	}
 
 	filtered := make([]domain.SemanticSearchResult, 0, len(results))
 	for _, r := range results {
-		chunk := &r.Chunk
-		if !matchesLanguageFilter(chunk, filters.Languages) ||
-			!matchesChunkTypeFilter(chunk, filters.ChunkTypes) ||
-			!matchesFilePathFilter(chunk, filters.FilePaths) ||
-			isExcludedDir(chunk, filters.ExcludeDirs) {
-			continue
+		if filters.Matches(&r.Chunk) {
+			filtered = append(filtered, r)
 		}
-		filtered = append(filtered, r)
 	}
 	return filtered
 }
 
-func matchesLanguageFilter(chunk *domain.CodeChunk, languages []string) bool {
-	if len(languages) == 0 {
-		return true
-	}
-	return slices.Contains(languages, chunk.Language)
-}
-
-func matchesChunkTypeFilter(chunk *domain.CodeChunk, chunkTypes []domain.ChunkType) bool {
-	if len(chunkTypes) == 0 {
-		return true
-	}
-	return slices.Contains(chunkTypes, chunk.ChunkType)
-}
-
-func matchesFilePathFilter(chunk *domain.CodeChunk, filePaths []string) bool {
-	if len(filePaths) == 0 {
-		return true
-	}
-	for _, fp := range filePaths {
-		if strings.HasPrefix(chunk.FilePath, fp) {
-			return true
-		}
+// generateEmbeddingsWithRetry generates embeddings in batches within the
+// provider's limits, retrying each batch. Embeddings keep the order of texts.
+func (s *ServiceImpl) generateEmbeddingsWithRetry(ctx context.Context, texts []string) (*domain.EmbeddingResponse, error) {
+	batches := s.embeddingProvider.GetModelInfo().Batches(texts)
+	if len(batches) <= 1 {
+		return s.generateBatchWithRetry(ctx, texts)
 	}
-	return false
-}
 
-func isExcludedDir(chunk *domain.CodeChunk, excludeDirs []string) bool {
-	for _, dir := range excludeDirs {
-		if strings.Contains(chunk.FilePath, dir) {
-			return true
+	result := &domain.EmbeddingResponse{Embeddings: make([]domain.EmbeddingVector, 0, len(texts))}
+	for i, batch := range batches {
+		resp, err := s.generateBatchWithRetry(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("embedding batch %d of %d: %w", i+1, len(batches), err)
 		}
+		result.Embeddings = append(result.Embeddings, resp.Embeddings...)
+		result.TokensUsed += resp.TokensUsed
+		result.Model = resp.Model
 	}
-	return false
+	return result, nil
 }
 
-// generateEmbeddingsWithRetry generates embeddings with retry logic
-func (s *ServiceImpl) generateEmbeddingsWithRetry(ctx context.Context, texts []string) (*domain.EmbeddingResponse, error) {
+// generateBatchWithRetry generates embeddings for one batch with retry logic
+func (s *ServiceImpl) generateBatchWithRetry(ctx context.Context, texts []string) (*domain.EmbeddingResponse, error) {
 	maxRetries := 3
 	baseDelay := 1 * time.Second
 
@@ -125,8 +108,11 @@ func (s *ServiceImpl) generateEmbeddingsWithRetry(ctx context.Context, texts []s
 		}
 
 		lastErr = err
+		delay, retry := embeddingRetryDelay(err, attempt, baseDelay)
+		if !retry {
+			return nil, err
+		}
 		if attempt < maxRetries-1 {
-			delay := baseDelay * time.Duration(1<<attempt)
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
@@ -137,3 +123,42 @@ func (s *ServiceImpl) generateEmbeddingsWithRetry(ctx context.Context, texts []s
 
 	return nil, fmt.Errorf("failed after %d retries: %w", maxRetries, lastErr)
 }
+
+// maxEmbeddingRetryAfter is the longest Retry-After delay waited for before
+// giving up on a batch
+const maxEmbeddingRetryAfter = time.Minute
+
+// transientErrorTexts are error texts of transient failures, matched for
+// providers that don't return a domain.ProviderError
+var transientErrorTexts = []string{
+	"429", "500", "502", "503", "504",
+	"rate limit", "timeout", "timed out", "temporarily unavailable", "connection reset",
+}
+
+// embeddingRetryDelay decides whether a failed embedding request is retried
+// and how long to wait first. Provider errors are retried as the provider
+// reports, after the Retry-After delay it asked for or with exponential
+// backoff; other errors are retried only if their text looks transient.
+func embeddingRetryDelay(err error, attempt int, baseDelay time.Duration) (time.Duration, bool) {
+	backoff := baseDelay * time.Duration(1<<attempt)
+
+	var providerErr *domain.ProviderError
+	if errors.As(err, &providerErr) {
+		switch {
+		case !providerErr.Retryable || providerErr.RetryAfter > maxEmbeddingRetryAfter:
+			return 0, false
+		case providerErr.RetryAfter > 0:
+			return providerErr.RetryAfter, true
+		}
+		return backoff, true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return backoff, true
+	}
+	message := strings.ToLower(err.Error())
+	return backoff, slices.ContainsFunc(transientErrorTexts, func(text string) bool {
+		return strings.Contains(message, text)
+	})
+}
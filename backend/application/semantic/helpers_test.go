@@ -0,0 +1,118 @@
+package semantic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"shotgun_code/domain"
+	"shotgun_code/infrastructure/embeddings"
+	"testing"
+	"time"
+)
+
+// limitedProvider records the batches it receives and rejects oversized ones
+type limitedProvider struct {
+	*embeddings.FakeEmbeddingProvider
+	maxInputs int
+	maxTokens int
+	batches   []int
+}
+
+func (p *limitedProvider) GetModelInfo() domain.EmbeddingModelInfo {
+	info := p.FakeEmbeddingProvider.GetModelInfo()
+	info.MaxBatchInputs, info.MaxBatchTokens = p.maxInputs, p.maxTokens
+	return info
+}
+
+func (p *limitedProvider) GenerateEmbeddings(ctx context.Context, req domain.EmbeddingRequest) (*domain.EmbeddingResponse, error) {
+	if len(req.Texts) > p.maxInputs {
+		return nil, fmt.Errorf("batch of %d exceeds %d inputs", len(req.Texts), p.maxInputs)
+	}
+	p.batches = append(p.batches, len(req.Texts))
+	return p.FakeEmbeddingProvider.GenerateEmbeddings(ctx, req)
+}
+
+func TestService_GenerateEmbeddingsInBatches(t *testing.T) {
+	provider := &limitedProvider{FakeEmbeddingProvider: embeddings.NewFakeEmbeddingProvider(0), maxInputs: 3, maxTokens: 10}
+	service := NewService(provider, nil, nil, &domain.NoopLogger{}, fileChunker{}, domain.ChunkerConfig{})
+
+	// The 40-byte text fills a token-capped batch on its own
+	texts := []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta theta iota kappa lambda mu nu xi pi", "omega"}
+	resp, err := service.generateEmbeddingsWithRetry(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("generateEmbeddingsWithRetry failed: %v", err)
+	}
+
+	wantBatches := []int{3, 3, 1, 1}
+	if fmt.Sprint(provider.batches) != fmt.Sprint(wantBatches) {
+		t.Errorf("batches = %v, want %v", provider.batches, wantBatches)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		t.Fatalf("expected %d embeddings, got %d", len(texts), len(resp.Embeddings))
+	}
+
+	single, err := provider.FakeEmbeddingProvider.GenerateEmbeddings(context.Background(), domain.EmbeddingRequest{Texts: texts[3:4]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(resp.Embeddings[3]) != fmt.Sprint(single.Embeddings[0]) {
+		t.Error("embeddings should keep the order of the texts")
+	}
+	if resp.TokensUsed == 0 {
+		t.Error("expected the tokens of all batches to be summed")
+	}
+}
+
+// failingProvider fails a number of requests with err before succeeding
+type failingProvider struct {
+	*embeddings.FakeEmbeddingProvider
+	err      error
+	failures int
+	calls    int
+}
+
+func (p *failingProvider) GenerateEmbeddings(ctx context.Context, req domain.EmbeddingRequest) (*domain.EmbeddingResponse, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		return nil, p.err
+	}
+	return p.FakeEmbeddingProvider.GenerateEmbeddings(ctx, req)
+}
+
+func TestService_GenerateEmbeddingsRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"rate limit honors Retry-After", domain.NewProviderError("OpenAI", 429, 10*time.Millisecond, errors.New("slow down")), 2, false},
+		{"bad request is not retried", domain.NewProviderError("OpenAI", 400, 0, errors.New("invalid input")), 1, true},
+		{"excessive Retry-After gives up", domain.NewProviderError("OpenAI", 429, time.Hour, errors.New("quota")), 1, true},
+		{"unknown error is not retried", errors.New("invalid model"), 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &failingProvider{FakeEmbeddingProvider: embeddings.NewFakeEmbeddingProvider(0), err: tt.err, failures: 1}
+			service := NewService(provider, nil, nil, &domain.NoopLogger{}, fileChunker{}, domain.ChunkerConfig{})
+
+			start := time.Now()
+			_, err := service.generateEmbeddingsWithRetry(context.Background(), []string{"alpha"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if provider.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", provider.calls, tt.wantCalls)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("expected the Retry-After delay instead of the backoff, took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestEmbeddingRetryDelay_TextFallback(t *testing.T) {
+	if delay, retry := embeddingRetryDelay(errors.New("status 503: service temporarily unavailable"), 1, time.Second); !retry || delay != 2*time.Second {
+		t.Errorf("expected a transient error retried with backoff, got %v %v", delay, retry)
+	}
+}
@@ -2,6 +2,7 @@ package semantic
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,6 +27,19 @@ func (s *ServiceImpl) IndexProject(ctx context.Context, projectRoot string) erro
 
 	s.log.Info(fmt.Sprintf("Starting semantic indexing for project: %s", projectRoot))
 
+	if err := s.checkEmbeddingModel(ctx, projectID); err != nil {
+		if !isReindexRequired(err) {
+			state.Error = err
+			return err
+		}
+		// Vectors of another model can't be mixed with new ones; start over
+		s.log.Info(fmt.Sprintf("Rebuilding semantic index: %v", err))
+		if err := s.vectorStore.DeleteProject(ctx, projectID); err != nil {
+			state.Error = err
+			return fmt.Errorf("failed to clear semantic index: %w", err)
+		}
+	}
+
 	if s.symbolIndex != nil {
 		s.log.Info("Indexing symbols for project...")
 		if err := s.symbolIndex.IndexProject(ctx, projectRoot); err != nil {
@@ -130,7 +144,13 @@ func (s *ServiceImpl) indexFileBatch(ctx context.Context, projectRoot, projectID
 
 // IndexFile indexes a single file
 func (s *ServiceImpl) IndexFile(ctx context.Context, projectRoot string, filePath string) error {
+	if !s.languageScope.FileEnabled(filePath) {
+		return nil
+	}
 	projectID := generateProjectID(projectRoot)
+	if err := s.checkEmbeddingModel(ctx, projectID); err != nil {
+		return err
+	}
 	fullPath := filepath.Join(projectRoot, filePath)
 
 	content, err := os.ReadFile(fullPath)
@@ -189,6 +209,24 @@ func (s *ServiceImpl) IndexFile(ctx context.Context, projectRoot string, filePat
 	return s.vectorStore.StoreBatch(ctx, projectID, embeddedChunks)
 }
 
+// checkEmbeddingModel returns a domain.ErrCodeReindexRequired error if the
+// project was indexed with another embedding model, when the store records models
+func (s *ServiceImpl) checkEmbeddingModel(ctx context.Context, projectID string) error {
+	checker, ok := s.vectorStore.(interface {
+		CheckEmbeddingModel(ctx context.Context, projectID string) error
+	})
+	if !ok {
+		return nil
+	}
+	return checker.CheckEmbeddingModel(ctx, projectID)
+}
+
+// isReindexRequired reports whether err asks for the project to be re-indexed
+func isReindexRequired(err error) bool {
+	var domainErr *domain.DomainError
+	return errors.As(err, &domainErr) && domainErr.Code == domain.ErrCodeReindexRequired
+}
+
 // collectCodeFiles collects all code files from project
 func (s *ServiceImpl) collectCodeFiles(projectRoot string) ([]string, error) {
 	var files []string
@@ -202,7 +240,7 @@ func (s *ServiceImpl) collectCodeFiles(projectRoot string) ([]string, error) {
 			}
 			return nil
 		}
-		if isCodeFile(path) {
+		if isCodeFile(path) && s.languageScope.FileEnabled(path) {
 			relPath, _ := filepath.Rel(projectRoot, path)
 			files = append(files, relPath)
 		}
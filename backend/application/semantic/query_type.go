@@ -0,0 +1,97 @@
+package semantic
+
+import (
+	"strings"
+	"unicode"
+
+	"shotgun_code/domain"
+)
+
+// queryKind is the shape of a search query
+type queryKind string
+
+const (
+	queryKindIdentifier queryKind = "identifier" // ParseManifest, parse_manifest, http.Get
+	queryKindNatural    queryKind = "natural"    // where is the manifest parsed
+	queryKindMixed      queryKind = "mixed"      // ParseManifest errors
+)
+
+// Hybrid weights (semantic, keyword) chosen for each query kind
+var queryKindWeights = map[queryKind][2]float32{
+	queryKindIdentifier: {0.2, 0.8},
+	queryKindNatural:    {0.85, 0.15},
+	queryKindMixed:      {0.5, 0.5},
+}
+
+// detectQueryKind classifies a query: a single identifier-like token is a
+// lookup by name, several plain words are a description, and several words
+// with an identifier among them are both
+func detectQueryKind(query string) queryKind {
+	words := strings.Fields(query)
+	switch {
+	case len(words) == 0:
+		return queryKindNatural
+	case len(words) == 1:
+		if isIdentifier(words[0]) {
+			return queryKindIdentifier
+		}
+		return queryKindNatural
+	}
+	for _, w := range words {
+		if isIdentifier(w) && looksLikeCode(w) {
+			return queryKindMixed
+		}
+	}
+	return queryKindNatural
+}
+
+// isIdentifier reports whether s is a (possibly qualified) identifier such as
+// Foo, foo_bar, pkg.Func or Class::method
+func isIdentifier(s string) bool {
+	s = strings.TrimSuffix(s, "()")
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == ':' })
+	if len(parts) == 0 {
+		return false
+	}
+	for _, p := range parts {
+		for i, r := range p {
+			if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// looksLikeCode reports whether an identifier is unlikely to be a plain word:
+// camelCase, snake_case, qualified or with a call suffix
+func looksLikeCode(s string) bool {
+	if strings.ContainsAny(s, "_.:(") {
+		return true
+	}
+	return hasCamelCase(s)
+}
+
+// hasCamelCase reports an upper-case letter after a lower-case one
+func hasCamelCase(s string) bool {
+	prevLower := false
+	for _, r := range s {
+		if unicode.IsUpper(r) && prevLower {
+			return true
+		}
+		prevLower = unicode.IsLower(r)
+	}
+	return false
+}
+
+// resolveAutoSearch turns an auto search into a hybrid search weighted for the
+// query kind; weights set by the caller are kept
+func resolveAutoSearch(req domain.SemanticSearchRequest) (domain.SemanticSearchRequest, queryKind) {
+	kind := detectQueryKind(req.Query)
+	req.SearchType = domain.SearchTypeHybrid
+	if req.SemanticWeight == 0 && req.KeywordWeight == 0 {
+		weights := queryKindWeights[kind]
+		req.SemanticWeight, req.KeywordWeight = weights[0], weights[1]
+	}
+	return req, kind
+}
@@ -0,0 +1,49 @@
+package semantic
+
+import (
+	"testing"
+
+	"shotgun_code/domain"
+)
+
+func TestDetectQueryKind(t *testing.T) {
+	tests := []struct {
+		query string
+		want  queryKind
+	}{
+		{"ParseManifest", queryKindIdentifier},
+		{"parse_manifest", queryKindIdentifier},
+		{"http.Get", queryKindIdentifier},
+		{"Parser::parse()", queryKindIdentifier},
+		{"config", queryKindIdentifier},
+		{"where is the manifest parsed", queryKindNatural},
+		{"Load config file", queryKindNatural},
+		{"ParseManifest returns nil on error", queryKindMixed},
+		{"why does os.Open fail", queryKindMixed},
+		{"404 handler", queryKindNatural},
+		{"", queryKindNatural},
+	}
+	for _, tt := range tests {
+		if got := detectQueryKind(tt.query); got != tt.want {
+			t.Errorf("detectQueryKind(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestResolveAutoSearch(t *testing.T) {
+	req, kind := resolveAutoSearch(domain.SemanticSearchRequest{Query: "ParseManifest", SearchType: domain.SearchTypeAuto})
+	if kind != queryKindIdentifier || req.SearchType != domain.SearchTypeHybrid || req.KeywordWeight <= req.SemanticWeight {
+		t.Errorf("expected keyword-weighted hybrid search, got %s %+v", kind, req)
+	}
+
+	req, _ = resolveAutoSearch(domain.SemanticSearchRequest{Query: "how are manifests parsed", SearchType: domain.SearchTypeAuto})
+	if req.SemanticWeight <= req.KeywordWeight {
+		t.Errorf("expected semantic-weighted search, got %+v", req)
+	}
+
+	// Weights chosen by the caller win
+	req, _ = resolveAutoSearch(domain.SemanticSearchRequest{Query: "ParseManifest", SemanticWeight: 1})
+	if req.SemanticWeight != 1 || req.KeywordWeight != 0 {
+		t.Errorf("caller weights overridden: %+v", req)
+	}
+}
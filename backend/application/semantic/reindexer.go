@@ -0,0 +1,263 @@
+package semantic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"shotgun_code/domain"
+)
+
+// EventIndexUpdated is emitted after a batch of changed files was re-indexed
+const EventIndexUpdated = "index:updated"
+
+// IndexUpdatedEvent is the payload of EventIndexUpdated
+type IndexUpdatedEvent struct {
+	ProjectRoot string   `json:"projectRoot"`
+	Files       []string `json:"files"`
+	Errors      int      `json:"errors"`
+}
+
+// ReindexerStatus reports what background indexing has done
+type ReindexerStatus struct {
+	Enabled      bool      `json:"enabled"`
+	Running      bool      `json:"running"`
+	Pending      int       `json:"pending"`      // files queued for the next pass
+	FilesIndexed int       `json:"filesIndexed"` // since startup
+	Errors       int       `json:"errors"`
+	Skipped      int       `json:"skipped"` // changes ignored while disabled; a full re-index picks them up
+	LastRun      time.Time `json:"lastRun"`
+}
+
+// Reindexer keeps the semantic index and the call graph in sync with file
+// changes. Batches that arrive while a re-index is running are merged and
+// handled by a single follow-up pass, so a burst of changes (e.g. a branch
+// switch) re-indexes each affected file once.
+type Reindexer struct {
+	log       domain.Logger
+	bus       domain.EventBus
+	search    func(ctx context.Context) domain.SemanticSearchService // nil result: unavailable
+	callGraph func() domain.CallGraphBuilder
+
+	mu      sync.Mutex
+	enabled bool
+	pending map[string]map[string]struct{} // project root -> relative paths
+	running bool
+	idle    chan struct{} // closed when the current run finishes
+	stats   ReindexerStatus
+}
+
+// NewReindexer creates a reindexer; either getter may be nil
+func NewReindexer(
+	log domain.Logger,
+	bus domain.EventBus,
+	search func(ctx context.Context) domain.SemanticSearchService,
+	callGraph func() domain.CallGraphBuilder,
+) *Reindexer {
+	return &Reindexer{
+		log:       log,
+		bus:       bus,
+		search:    search,
+		callGraph: callGraph,
+		enabled:   true,
+		pending:   make(map[string]map[string]struct{}),
+	}
+}
+
+// SetEnabled turns background re-indexing on or off. Changes queued when it
+// is turned off are dropped and counted as skipped.
+func (r *Reindexer) SetEnabled(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.enabled == enabled {
+		return
+	}
+	r.enabled = enabled
+	if !enabled {
+		for _, set := range r.pending {
+			r.stats.Skipped += len(set)
+		}
+		r.pending = make(map[string]map[string]struct{})
+	}
+	r.log.Info(fmt.Sprintf("Background indexing enabled: %v", enabled))
+}
+
+// Status returns the current background indexing status
+func (r *Reindexer) Status() ReindexerStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := r.stats
+	status.Enabled = r.enabled
+	status.Running = r.running
+	for _, set := range r.pending {
+		status.Pending += len(set)
+	}
+	return status
+}
+
+// Schedule queues changed files (absolute or relative to rootDir) for
+// re-indexing. It matches the fswatcher OnFilesChanged callback.
+func (r *Reindexer) Schedule(rootDir string, files []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.enabled {
+		for _, f := range files {
+			if _, ok := relativePath(rootDir, f); ok {
+				r.stats.Skipped++
+			}
+		}
+		return
+	}
+
+	set := r.pending[rootDir]
+	if set == nil {
+		set = make(map[string]struct{})
+		r.pending[rootDir] = set
+	}
+	for _, f := range files {
+		if rel, ok := relativePath(rootDir, f); ok {
+			set[rel] = struct{}{}
+		}
+	}
+	if len(set) == 0 {
+		delete(r.pending, rootDir)
+		return
+	}
+
+	if !r.running {
+		r.running = true
+		r.idle = make(chan struct{})
+		go r.run()
+	}
+}
+
+// Wait blocks until queued changes are processed or ctx is done
+func (r *Reindexer) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	idle := r.idle
+	running := r.running
+	r.mu.Unlock()
+	if !running {
+		return nil
+	}
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run drains the pending set until no more changes arrive
+func (r *Reindexer) run() {
+	for {
+		r.mu.Lock()
+		if len(r.pending) == 0 {
+			r.running = false
+			close(r.idle)
+			r.mu.Unlock()
+			return
+		}
+		batch := r.pending
+		r.pending = make(map[string]map[string]struct{})
+		r.mu.Unlock()
+
+		for root, set := range batch {
+			files := make([]string, 0, len(set))
+			for f := range set {
+				files = append(files, f)
+			}
+			slices.Sort(files)
+			r.reindex(context.Background(), root, files)
+		}
+	}
+}
+
+// reindex updates the semantic index and the call graph for files of a project
+func (r *Reindexer) reindex(ctx context.Context, projectRoot string, files []string) {
+	var search domain.SemanticSearchService
+	if r.search != nil {
+		if s := r.search(ctx); s != nil && s.IsIndexed(ctx, projectRoot) {
+			search = s
+		}
+	}
+	var callGraph domain.CallGraphBuilder
+	if r.callGraph != nil {
+		callGraph = r.callGraph()
+	}
+
+	failed := 0
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(projectRoot, file))
+		deleted := errors.Is(err, os.ErrNotExist)
+		if err != nil && !deleted {
+			// Directories and unreadable files are skipped
+			continue
+		}
+
+		if search != nil && isCodeFile(file) {
+			if err := r.reindexSemantic(ctx, search, projectRoot, file, deleted); err != nil {
+				r.log.Warning(fmt.Sprintf("Re-index of %s failed: %v", file, err))
+				failed++
+			}
+		}
+		if callGraph != nil {
+			if deleted {
+				content = nil
+			}
+			if err := callGraph.BuildForFile(ctx, file, content); err != nil {
+				r.log.Warning(fmt.Sprintf("Call graph update for %s failed: %v", file, err))
+				failed++
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.stats.FilesIndexed += len(files)
+	r.stats.Errors += failed
+	r.stats.LastRun = time.Now()
+	r.mu.Unlock()
+
+	r.log.Debug(fmt.Sprintf("Re-indexed %d changed files in %s", len(files), projectRoot))
+	if r.bus != nil {
+		r.bus.Emit(EventIndexUpdated, IndexUpdatedEvent{ProjectRoot: projectRoot, Files: files, Errors: failed})
+	}
+}
+
+func (r *Reindexer) reindexSemantic(ctx context.Context, search domain.SemanticSearchService, projectRoot, file string, deleted bool) error {
+	if err := search.InvalidateFile(ctx, projectRoot, file); err != nil {
+		return err
+	}
+	if deleted {
+		return nil
+	}
+	return search.IndexFile(ctx, projectRoot, file)
+}
+
+// relativePath converts a changed path to a path relative to root, rejecting
+// paths outside root or inside skipped directories
+func relativePath(root, path string) (string, bool) {
+	rel := path
+	if filepath.IsAbs(path) {
+		var err error
+		if rel, err = filepath.Rel(root, path); err != nil {
+			return "", false
+		}
+	}
+	rel = filepath.Clean(rel)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	dirs := strings.Split(filepath.Dir(rel), string(filepath.Separator))
+	if slices.ContainsFunc(dirs, shouldSkipDir) {
+		return "", false
+	}
+	return rel, true
+}
@@ -0,0 +1,154 @@
+package semantic
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"shotgun_code/domain"
+)
+
+// recordingBus records emitted events
+type recordingBus struct {
+	mu     sync.Mutex
+	events []IndexUpdatedEvent
+}
+
+func (b *recordingBus) Emit(eventName string, data ...interface{}) {
+	if eventName != EventIndexUpdated {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, data[0].(IndexUpdatedEvent))
+}
+
+// recordingCallGraph records BuildForFile calls
+type recordingCallGraph struct {
+	domain.CallGraphBuilder
+	mu    sync.Mutex
+	built map[string]int
+}
+
+func (g *recordingCallGraph) BuildForFile(_ context.Context, filePath string, content []byte) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.built[filePath]++
+	return nil
+}
+
+func TestReindexer_CoalescesChanges(t *testing.T) {
+	projectRoot := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(projectRoot, path), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.go", "package a\n\nfunc Alpha() {}\n")
+	write("b.go", "package a\n\nfunc Beta() {}\n")
+
+	service := newOfflineService(t)
+	ctx := context.Background()
+	if err := service.IndexProject(ctx, projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+
+	bus := &recordingBus{}
+	graph := &recordingCallGraph{built: make(map[string]int)}
+	reindexer := NewReindexer(&domain.NoopLogger{}, bus,
+		func(context.Context) domain.SemanticSearchService { return service },
+		func() domain.CallGraphBuilder { return graph })
+
+	write("a.go", "package a\n\nfunc ParseManifest() {}\n")
+	if err := os.Remove(filepath.Join(projectRoot, "b.go")); err != nil {
+		t.Fatal(err)
+	}
+	// A burst of events for the same files, as after a branch switch
+	for i := 0; i < 5; i++ {
+		reindexer.Schedule(projectRoot, []string{
+			filepath.Join(projectRoot, "a.go"),
+			filepath.Join(projectRoot, "b.go"),
+			filepath.Join(projectRoot, "node_modules", "x.js"),
+		})
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := reindexer.Wait(waitCtx); err != nil {
+		t.Fatalf("re-index did not finish: %v", err)
+	}
+
+	// The first batch may run before the rest arrive; later ones are merged
+	if n := len(bus.events); n == 0 || n > 2 {
+		t.Fatalf("expected 1-2 index:updated events, got %d", n)
+	}
+	if graph.built["a.go"] > 2 || graph.built["b.go"] > 2 || graph.built["node_modules/x.js"] != 0 {
+		t.Errorf("unexpected call graph rebuilds %v", graph.built)
+	}
+
+	chunks, err := service.vectorStore.ListChunks(ctx, generateProjectID(projectRoot), "b.go")
+	if err != nil || len(chunks) != 0 {
+		t.Errorf("deleted file still indexed: %v %v", chunks, err)
+	}
+	chunks, err = service.vectorStore.ListChunks(ctx, generateProjectID(projectRoot), "a.go")
+	if err != nil || len(chunks) != 1 || chunks[0].Chunk.Content != "package a\n\nfunc ParseManifest() {}\n" {
+		t.Errorf("changed file not re-indexed: %+v %v", chunks, err)
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "project")
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{filepath.Join(root, "pkg", "a.go"), filepath.Join("pkg", "a.go"), true},
+		{filepath.Join("pkg", "a.go"), filepath.Join("pkg", "a.go"), true},
+		{filepath.Join(root, "vendor", "x", "a.go"), "", false},
+		{filepath.Join(string(filepath.Separator), "other", "a.go"), "", false},
+		{root, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := relativePath(root, tt.path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("relativePath(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReindexer_Disabled(t *testing.T) {
+	projectRoot := t.TempDir()
+	graph := &recordingCallGraph{built: make(map[string]int)}
+	reindexer := NewReindexer(&domain.NoopLogger{}, &recordingBus{}, nil,
+		func() domain.CallGraphBuilder { return graph })
+
+	reindexer.SetEnabled(false)
+	reindexer.Schedule(projectRoot, []string{filepath.Join(projectRoot, "a.go"), filepath.Join(projectRoot, "b.go")})
+	if err := reindexer.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	status := reindexer.Status()
+	if status.Enabled || status.Skipped != 2 || status.Pending != 0 || len(graph.built) != 0 {
+		t.Fatalf("disabled reindexer processed changes: %+v %v", status, graph.built)
+	}
+
+	reindexer.SetEnabled(true)
+	if err := os.WriteFile(filepath.Join(projectRoot, "a.go"), []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reindexer.Schedule(projectRoot, []string{filepath.Join(projectRoot, "a.go")})
+	waitCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := reindexer.Wait(waitCtx); err != nil {
+		t.Fatalf("re-index did not finish: %v", err)
+	}
+	status = reindexer.Status()
+	if !status.Enabled || status.FilesIndexed != 1 || status.LastRun.IsZero() || graph.built["a.go"] != 1 {
+		t.Errorf("unexpected status after re-enabling: %+v %v", status, graph.built)
+	}
+}
@@ -0,0 +1,133 @@
+package semantic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"shotgun_code/domain"
+)
+
+const (
+	// rerankCandidateFactor is how many candidates per requested result are re-scored
+	rerankCandidateFactor = 3
+
+	// rerankChunkChars limits how much of each chunk is shown to the LLM reranker
+	rerankChunkChars = 1200
+)
+
+// rerankedSearch collects rerankCandidateFactor times the requested window of
+// candidates, re-scores them with the reranker and cuts the page from the new
+// order. If re-ranking fails the candidates keep their original order.
+func (s *ServiceImpl) rerankedSearch(ctx context.Context, projectID string, req domain.SemanticSearchRequest, startTime time.Time) (*domain.SemanticSearchResponse, error) {
+	candidatesReq := req
+	candidatesReq.TopK = req.Window() * rerankCandidateFactor
+	candidatesReq.Offset, candidatesReq.ScoreThreshold = 0, 0
+
+	resp, err := s.search(ctx, projectID, candidatesReq, startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	results := resp.Results
+	if len(results) > 0 {
+		if reranked, err := s.rerank(ctx, req.Query, results); err != nil {
+			s.log.Warning(fmt.Sprintf("Re-ranking failed, keeping vector order: %v", err))
+		} else {
+			results = reranked
+		}
+	}
+
+	page, total := req.Page(results)
+	resp.Results = page
+	resp.TotalResults = len(page)
+	resp.TotalAvailable = total
+	resp.Offset = req.Offset
+	resp.QueryTime = time.Since(startTime)
+	return resp, nil
+}
+
+// rerank re-scores results with the reranker and sorts them by the new score
+func (s *ServiceImpl) rerank(ctx context.Context, query string, results []domain.SemanticSearchResult) ([]domain.SemanticSearchResult, error) {
+	chunks := make([]domain.CodeChunk, len(results))
+	for i, result := range results {
+		chunks[i] = result.Chunk
+	}
+
+	scores, err := s.reranker.Rerank(ctx, query, chunks)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) != len(results) {
+		return nil, fmt.Errorf("reranker returned %d scores for %d candidates", len(scores), len(results))
+	}
+
+	reranked := make([]domain.SemanticSearchResult, len(results))
+	for i, result := range results {
+		result.VectorScore = result.Score
+		result.RerankScore = scores[i]
+		result.Score = scores[i]
+		reranked[i] = result
+	}
+	sort.SliceStable(reranked, func(i, j int) bool { return reranked[i].Score > reranked[j].Score })
+	return reranked, nil
+}
+
+// TextGenerator generates a completion for a system and user prompt
+type TextGenerator func(ctx context.Context, systemPrompt, userPrompt string) (string, error)
+
+// LLMReranker scores candidates by asking an LLM to rate their relevance
+type LLMReranker struct {
+	generate TextGenerator
+}
+
+// NewLLMReranker creates a reranker that scores candidates with generate
+func NewLLMReranker(generate TextGenerator) *LLMReranker {
+	return &LLMReranker{generate: generate}
+}
+
+const llmRerankSystemPrompt = `You rate how relevant code snippets are to a code search query.
+Reply with a JSON array of integers from 0 (irrelevant) to 10 (exactly what is searched for),
+one per snippet, in the order of the snippets. Reply with the array only.`
+
+// Rerank implements domain.Reranker
+func (r *LLMReranker) Rerank(ctx context.Context, query string, chunks []domain.CodeChunk) ([]float32, error) {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Query: %s\n", query)
+	for i, chunk := range chunks {
+		fmt.Fprintf(&prompt, "\nSnippet %d (%s):\n%s\n", i+1, chunkLabel(chunk), domain.TruncateString(chunk.Content, rerankChunkChars))
+	}
+
+	response, err := r.generate(ctx, llmRerankSystemPrompt, prompt.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to score candidates: %w", err)
+	}
+	return parseRerankScores(response, len(chunks))
+}
+
+// parseRerankScores reads the JSON array of 0-10 ratings from an LLM response
+// and scales them to [0, 1]
+func parseRerankScores(response string, count int) ([]float32, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no score array in reranker response")
+	}
+
+	var ratings []float32
+	if err := json.Unmarshal([]byte(response[start:end+1]), &ratings); err != nil {
+		return nil, fmt.Errorf("invalid score array in reranker response: %w", err)
+	}
+	if len(ratings) != count {
+		return nil, fmt.Errorf("reranker rated %d of %d candidates", len(ratings), count)
+	}
+
+	scores := make([]float32, count)
+	for i, rating := range ratings {
+		scores[i] = min(max(rating, 0), 10) / 10
+	}
+	return scores, nil
+}
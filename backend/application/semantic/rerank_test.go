@@ -0,0 +1,137 @@
+package semantic
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"strings"
+	"testing"
+)
+
+// pathReranker scores chunks of files under prefix as relevant
+type pathReranker struct {
+	prefix string
+	err    error
+}
+
+func (r pathReranker) Rerank(_ context.Context, _ string, chunks []domain.CodeChunk) ([]float32, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	scores := make([]float32, len(chunks))
+	for i, chunk := range chunks {
+		if strings.HasPrefix(chunk.FilePath, r.prefix) {
+			scores[i] = 0.9
+		} else {
+			scores[i] = 0.1
+		}
+	}
+	return scores, nil
+}
+
+func TestService_SearchRerank(t *testing.T) {
+	projectRoot := t.TempDir()
+	files := map[string]string{
+		"config/loader.go": "package config\n\n// LoadConfig reads the config file\nfunc LoadConfig(path string) error { return nil }\n",
+		"ui/button.go":     "package ui\n\n// RenderButton draws a button for the config file dialog\nfunc RenderButton() {}\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(projectRoot, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	service := newOfflineService(t)
+	ctx := context.Background()
+	if err := service.IndexProject(ctx, projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+
+	search := func(rerank bool) *domain.SemanticSearchResponse {
+		t.Helper()
+		resp, err := service.Search(ctx, domain.SemanticSearchRequest{
+			Query:       "load config file",
+			ProjectRoot: projectRoot,
+			TopK:        1,
+			MinScore:    0.0001,
+			SearchType:  domain.SearchTypeSemantic,
+			Rerank:      rerank,
+		})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(resp.Results) != 1 {
+			t.Fatalf("expected 1 result, got %+v", resp.Results)
+		}
+		return resp
+	}
+
+	// Without a reranker the flag is ignored
+	plain := search(true).Results[0]
+	if plain.RerankScore != 0 || plain.Chunk.FilePath != filepath.Join("config", "loader.go") {
+		t.Fatalf("expected the vector order without a reranker, got %+v", plain)
+	}
+
+	service.SetReranker(pathReranker{prefix: "ui"})
+	if search(false).Results[0].Chunk.FilePath != plain.Chunk.FilePath {
+		t.Error("searches without Rerank should keep the vector order")
+	}
+
+	resp := search(true)
+	top := resp.Results[0]
+	if top.Chunk.FilePath != filepath.Join("ui", "button.go") {
+		t.Fatalf("expected the reranked file on top, got %s", top.Chunk.FilePath)
+	}
+	if top.RerankScore != 0.9 || top.Score != top.RerankScore || top.VectorScore <= 0 {
+		t.Errorf("expected vector and rerank scores on the result, got %+v", top)
+	}
+	if resp.TotalAvailable != 2 {
+		t.Errorf("expected both candidates to be counted, got %d", resp.TotalAvailable)
+	}
+
+	// A failing reranker falls back to the vector order
+	service.SetReranker(pathReranker{err: errors.New("unavailable")})
+	if fallback := search(true).Results[0]; fallback.Chunk.FilePath != plain.Chunk.FilePath || fallback.RerankScore != 0 {
+		t.Errorf("expected the vector order when re-ranking fails, got %+v", fallback)
+	}
+}
+
+func TestLLMReranker_Rerank(t *testing.T) {
+	var prompt string
+	reranker := NewLLMReranker(func(_ context.Context, _, userPrompt string) (string, error) {
+		prompt = userPrompt
+		return "Scores:\n[8, 2, 15]", nil
+	})
+
+	chunks := []domain.CodeChunk{
+		{FilePath: "a.go", SymbolName: "LoadConfig", Content: "func LoadConfig() {}"},
+		{FilePath: "b.go", StartLine: 3, Content: "func Render() {}"},
+		{FilePath: "c.go", Content: "func Parse() {}"},
+	}
+	scores, err := reranker.Rerank(context.Background(), "load config", chunks)
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+	want := []float32{0.8, 0.2, 1}
+	for i := range want {
+		if scores[i] != want[i] {
+			t.Errorf("score %d = %v, want %v", i, scores[i], want[i])
+		}
+	}
+	if !strings.Contains(prompt, "load config") || !strings.Contains(prompt, "LoadConfig") || !strings.Contains(prompt, "b.go:3") {
+		t.Errorf("prompt should contain the query and the snippets, got %q", prompt)
+	}
+
+	if _, err := parseRerankScores("[1, 2]", 3); err == nil {
+		t.Error("expected an error when candidates are missing scores")
+	}
+	if _, err := parseRerankScores("no scores", 1); err == nil {
+		t.Error("expected an error without a score array")
+	}
+}
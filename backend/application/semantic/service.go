@@ -3,6 +3,7 @@ package semantic
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"sync"
 	"time"
@@ -18,6 +19,8 @@ type ServiceImpl struct {
 	symbolIndex       analysis.SymbolIndex
 	log               domain.Logger
 	chunker           domain.CodeChunker
+	languageScope     *domain.LanguageScope
+	reranker          domain.Reranker
 
 	// Indexing state
 	indexingMu    sync.RWMutex
@@ -43,14 +46,22 @@ type SymbolInfoForChunking struct {
 	EndLine   int
 }
 
-// NewService creates a new semantic search service
+// NewService creates a new semantic search service. chunkerConfig tunes
+// chunkers implementing domain.ChunkerConfigurer; an invalid one is logged
+// and the chunker keeps its defaults.
 func NewService(
 	embeddingProvider domain.EmbeddingProvider,
 	vectorStore domain.VectorStore,
 	symbolIndex analysis.SymbolIndex,
 	log domain.Logger,
 	chunker domain.CodeChunker,
+	chunkerConfig domain.ChunkerConfig,
 ) *ServiceImpl {
+	if configurer, ok := chunker.(domain.ChunkerConfigurer); ok && chunkerConfig != (domain.ChunkerConfig{}) {
+		if err := configurer.Configure(chunkerConfig); err != nil {
+			log.Warning(fmt.Sprintf("Invalid chunker config, using the defaults: %v", err))
+		}
+	}
 	return &ServiceImpl{
 		embeddingProvider: embeddingProvider,
 		vectorStore:       vectorStore,
@@ -61,6 +72,16 @@ func NewService(
 	}
 }
 
+// SetLanguageScope limits indexing to the enabled languages
+func (s *ServiceImpl) SetLanguageScope(scope *domain.LanguageScope) {
+	s.languageScope = scope
+}
+
+// SetReranker sets the reranker used by searches that request re-ranking
+func (s *ServiceImpl) SetReranker(reranker domain.Reranker) {
+	s.reranker = reranker
+}
+
 // startIndexingState initializes indexing state
 func (s *ServiceImpl) startIndexingState(projectID string) (*IndexingState, error) {
 	s.indexingMu.Lock()
@@ -80,6 +101,9 @@ func (s *ServiceImpl) Search(ctx context.Context, req domain.SemanticSearchReque
 	startTime := time.Now()
 	projectID := generateProjectID(req.ProjectRoot)
 
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 	if req.TopK == 0 {
 		req.TopK = 10
 	}
@@ -89,6 +113,31 @@ func (s *ServiceImpl) Search(ctx context.Context, req domain.SemanticSearchReque
 
 	s.log.Info(fmt.Sprintf("Semantic search: query='%s', topK=%d", domain.TruncateString(req.Query, 50), req.TopK))
 
+	if req.SearchType == domain.SearchTypeAuto {
+		var kind queryKind
+		req, kind = resolveAutoSearch(req)
+		s.log.Debug(fmt.Sprintf("Auto search: %s query, weights semantic=%.2f keyword=%.2f", kind, req.SemanticWeight, req.KeywordWeight))
+	}
+
+	var resp *domain.SemanticSearchResponse
+	var err error
+	if req.Rerank && s.reranker != nil {
+		resp, err = s.rerankedSearch(ctx, projectID, req, startTime)
+	} else {
+		resp, err = s.search(ctx, projectID, req, startTime)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Snippets {
+		addSnippets(resp.Results, req.Query, req.Highlight)
+	}
+	return resp, nil
+}
+
+// search runs the search of the request's type
+func (s *ServiceImpl) search(ctx context.Context, projectID string, req domain.SemanticSearchRequest, startTime time.Time) (*domain.SemanticSearchResponse, error) {
 	switch req.SearchType {
 	case domain.SearchTypeKeyword:
 		return s.keywordSearch(ctx, req, startTime)
@@ -99,6 +148,15 @@ func (s *ServiceImpl) Search(ctx context.Context, req domain.SemanticSearchReque
 	}
 }
 
+// minSearchCandidates is the least number of candidates taken from the vector
+// store, so that TotalAvailable reflects more than the requested page
+const minSearchCandidates = 100
+
+// searchCandidates returns how many candidates to take from the vector store for a result window
+func searchCandidates(window int) int {
+	return max(window, minSearchCandidates)
+}
+
 // semanticSearch performs pure semantic search
 func (s *ServiceImpl) semanticSearch(ctx context.Context, projectID string, req domain.SemanticSearchRequest, startTime time.Time) (*domain.SemanticSearchResponse, error) {
 	resp, err := s.generateEmbeddingsWithRetry(ctx, []string{req.Query})
@@ -106,24 +164,32 @@ func (s *ServiceImpl) semanticSearch(ctx context.Context, projectID string, req
 		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 	}
 
-	results, err := s.vectorStore.Search(ctx, projectID, resp.Embeddings[0], req.TopK*2, req.MinScore)
+	// Filters are applied by the store so that they don't eat into TopK
+	results, err := s.vectorStore.Search(ctx, projectID, resp.Embeddings[0], searchCandidates(req.Window()), req.MinScore, req.Filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search vector store: %w", err)
 	}
 
-	results = s.applyFilters(results, req.Filters)
-	if len(results) > req.TopK {
-		results = results[:req.TopK]
+	if req.Explain {
+		for i := range results {
+			results[i].Explanation = &domain.ScoreBreakdown{SemanticScore: results[i].Score, SemanticWeight: 1}
+		}
 	}
 
+	page, total := req.Page(results)
 	return &domain.SemanticSearchResponse{
-		Results:      results,
-		TotalResults: len(results),
-		QueryTime:    time.Since(startTime),
-		SearchType:   domain.SearchTypeSemantic,
+		Results:        page,
+		TotalResults:   len(page),
+		QueryTime:      time.Since(startTime),
+		SearchType:     domain.SearchTypeSemantic,
+		TotalAvailable: total,
+		Offset:         req.Offset,
 	}, nil
 }
 
+// keywordMatchScore is the score of a symbol name match in keyword search
+const keywordMatchScore = 0.8
+
 // keywordSearch performs keyword-based search
 func (s *ServiceImpl) keywordSearch(_ context.Context, req domain.SemanticSearchRequest, startTime time.Time) (*domain.SemanticSearchResponse, error) {
 	if s.symbolIndex == nil {
@@ -138,7 +204,7 @@ func (s *ServiceImpl) keywordSearch(_ context.Context, req domain.SemanticSearch
 	results := make([]domain.SemanticSearchResult, 0, len(symbols))
 
 	for _, sym := range symbols {
-		results = append(results, domain.SemanticSearchResult{
+		result := domain.SemanticSearchResult{
 			Chunk: domain.CodeChunk{
 				FilePath:   sym.FilePath,
 				StartLine:  sym.StartLine,
@@ -146,20 +212,24 @@ func (s *ServiceImpl) keywordSearch(_ context.Context, req domain.SemanticSearch
 				SymbolName: sym.Name,
 				ChunkType:  domain.ChunkTypeFunction,
 			},
-			Score: 0.8,
-		})
+			Score: keywordMatchScore,
+		}
+		if req.Explain {
+			result.Explanation = &domain.ScoreBreakdown{KeywordScore: keywordMatchScore, KeywordWeight: 1}
+		}
+		results = append(results, result)
 	}
 
 	results = s.applyFilters(results, req.Filters)
-	if len(results) > req.TopK {
-		results = results[:req.TopK]
-	}
+	page, total := req.Page(results)
 
 	return &domain.SemanticSearchResponse{
-		Results:      results,
-		TotalResults: len(results),
-		QueryTime:    time.Since(startTime),
-		SearchType:   domain.SearchTypeKeyword,
+		Results:        page,
+		TotalResults:   len(page),
+		QueryTime:      time.Since(startTime),
+		SearchType:     domain.SearchTypeKeyword,
+		TotalAvailable: total,
+		Offset:         req.Offset,
 	}, nil
 }
 
@@ -173,7 +243,7 @@ func (s *ServiceImpl) hybridSearch(ctx context.Context, req domain.SemanticSearc
 		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 	}
 
-	semanticResults, err := s.vectorStore.Search(ctx, projectID, semanticResp.Embeddings[0], req.TopK*2, req.MinScore*0.8)
+	semanticResults, err := s.vectorStore.Search(ctx, projectID, semanticResp.Embeddings[0], searchCandidates(req.Window()*2), req.MinScore*0.8, req.Filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search vector store: %w", err)
 	}
@@ -191,23 +261,35 @@ func (s *ServiceImpl) hybridSearch(ctx context.Context, req domain.SemanticSearc
 					SymbolName: sym.Name,
 					ChunkType:  domain.ChunkTypeFunction,
 				},
-				Score: 0.7,
+				Score: 1,
 			})
 		}
 	}
 
-	// Merge and deduplicate results
+	// Merge and deduplicate results, blending scores by the request weights
+	semanticWeight, keywordWeight := req.HybridWeights()
 	resultMap := make(map[string]domain.SemanticSearchResult)
 	for _, r := range semanticResults {
 		key := fmt.Sprintf("%s:%d", r.Chunk.FilePath, r.Chunk.StartLine)
+		if req.Explain {
+			r.Explanation = &domain.ScoreBreakdown{SemanticScore: r.Score, SemanticWeight: semanticWeight, KeywordWeight: keywordWeight}
+		}
+		r.Score *= semanticWeight
 		resultMap[key] = r
 	}
 	for _, r := range keywordResults {
 		key := fmt.Sprintf("%s:%d", r.Chunk.FilePath, r.Chunk.StartLine)
 		if existing, ok := resultMap[key]; ok {
-			existing.Score = (existing.Score + r.Score) / 2
+			if existing.Explanation != nil {
+				existing.Explanation.KeywordScore = r.Score
+			}
+			existing.Score += r.Score * keywordWeight
 			resultMap[key] = existing
 		} else {
+			if req.Explain {
+				r.Explanation = &domain.ScoreBreakdown{KeywordScore: r.Score, SemanticWeight: semanticWeight, KeywordWeight: keywordWeight}
+			}
+			r.Score *= keywordWeight
 			resultMap[key] = r
 		}
 	}
@@ -222,15 +304,15 @@ func (s *ServiceImpl) hybridSearch(ctx context.Context, req domain.SemanticSearc
 	})
 
 	results = s.applyFilters(results, req.Filters)
-	if len(results) > req.TopK {
-		results = results[:req.TopK]
-	}
+	page, total := req.Page(results)
 
 	return &domain.SemanticSearchResponse{
-		Results:      results,
-		TotalResults: len(results),
-		QueryTime:    time.Since(startTime),
-		SearchType:   domain.SearchTypeHybrid,
+		Results:        page,
+		TotalResults:   len(page),
+		QueryTime:      time.Since(startTime),
+		SearchType:     domain.SearchTypeHybrid,
+		TotalAvailable: total,
+		Offset:         req.Offset,
 	}, nil
 }
 
@@ -252,6 +334,91 @@ func (s *ServiceImpl) FindSimilar(_ context.Context, req domain.SimilarCodeReque
 	}, nil
 }
 
+// SearchRelated returns the nearest neighbors of the chunk containing startLine.
+// The source chunk's stored embedding is reused, so no embedding request is made.
+func (s *ServiceImpl) SearchRelated(ctx context.Context, projectRoot, filePath string, startLine, topK int) (*domain.SemanticSearchResponse, error) {
+	startTime := time.Now()
+	projectID := generateProjectID(projectRoot)
+
+	if topK <= 0 {
+		topK = 10
+	}
+	if filepath.IsAbs(filePath) {
+		if rel, err := filepath.Rel(projectRoot, filePath); err == nil {
+			filePath = rel
+		}
+	}
+
+	chunks, err := s.vectorStore.ListChunks(ctx, projectID, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chunks: %w", err)
+	}
+	source := chunkAtLine(chunks, startLine)
+	if source == nil {
+		return nil, fmt.Errorf("no indexed chunk at %s:%d", filePath, startLine)
+	}
+
+	// One extra result makes room for the source chunk itself
+	results, err := s.vectorStore.Search(ctx, projectID, source.Embedding, topK+1, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search vector store: %w", err)
+	}
+
+	related := make([]domain.SemanticSearchResult, 0, len(results))
+	for _, r := range results {
+		if r.Chunk.ID == source.Chunk.ID {
+			continue
+		}
+		r.Reason = "Related to " + chunkLabel(source.Chunk)
+		related = append(related, r)
+	}
+	if len(related) > topK {
+		related = related[:topK]
+	}
+
+	return &domain.SemanticSearchResponse{
+		Results:      related,
+		TotalResults: len(related),
+		QueryTime:    time.Since(startTime),
+		SearchType:   domain.SearchTypeSemantic,
+	}, nil
+}
+
+// chunkAtLine returns the narrowest chunk containing line, or the chunk
+// starting closest to it when none contains it
+func chunkAtLine(chunks []domain.EmbeddedChunk, line int) *domain.EmbeddedChunk {
+	var containing, nearest *domain.EmbeddedChunk
+	for i := range chunks {
+		c := &chunks[i]
+		if line >= c.Chunk.StartLine && line <= c.Chunk.EndLine {
+			if containing == nil || c.Chunk.EndLine-c.Chunk.StartLine < containing.Chunk.EndLine-containing.Chunk.StartLine {
+				containing = c
+			}
+		} else if nearest == nil || absInt(c.Chunk.StartLine-line) < absInt(nearest.Chunk.StartLine-line) {
+			nearest = c
+		}
+	}
+	if containing != nil {
+		return containing
+	}
+	return nearest
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// chunkLabel names a chunk by its symbol, or by its location
+func chunkLabel(chunk domain.CodeChunk) string {
+	if chunk.SymbolName != "" {
+		return chunk.SymbolName
+	}
+	return fmt.Sprintf("%s:%d", chunk.FilePath, chunk.StartLine)
+}
+
 // GetClusters returns code clusters
 func (s *ServiceImpl) GetClusters(_ context.Context, _ string, _ int) ([]domain.ClusterInfo, error) {
 	return []domain.ClusterInfo{}, nil
@@ -279,6 +446,18 @@ func (s *ServiceImpl) InvalidateFile(ctx context.Context, projectRoot string, fi
 	return s.vectorStore.Delete(ctx, projectID, filePath)
 }
 
+// IsIndexing reports whether any project is being indexed
+func (s *ServiceImpl) IsIndexing() bool {
+	s.indexingMu.RLock()
+	defer s.indexingMu.RUnlock()
+	for _, state := range s.indexingState {
+		if state.InProgress {
+			return true
+		}
+	}
+	return false
+}
+
 // GetIndexingState returns the current indexing state
 func (s *ServiceImpl) GetIndexingState(projectRoot string) *IndexingState {
 	projectID := generateProjectID(projectRoot)
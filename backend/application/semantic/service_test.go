@@ -0,0 +1,417 @@
+package semantic
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"shotgun_code/domain/analysis"
+	"shotgun_code/infrastructure/embeddings"
+	"strings"
+	"testing"
+)
+
+// fileChunker returns every file as a single chunk
+type fileChunker struct{}
+
+func (fileChunker) ChunkFile(filePath string, content []byte, _ []domain.ChunkSymbolInfo) []domain.CodeChunk {
+	return []domain.CodeChunk{{
+		ID:        filePath,
+		FilePath:  filePath,
+		Content:   string(content),
+		StartLine: 1,
+		EndLine:   strings.Count(string(content), "\n") + 1,
+		ChunkType: domain.ChunkTypeFile,
+		Language:  "go",
+		Hash:      filePath,
+	}}
+}
+
+func newOfflineService(t *testing.T) *ServiceImpl {
+	t.Helper()
+	log := &domain.NoopLogger{}
+	store, err := embeddings.NewSQLiteVectorStore(t.TempDir(), log)
+	if err != nil {
+		t.Fatalf("failed to create vector store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return NewService(embeddings.NewFakeEmbeddingProvider(0), store, nil, log, fileChunker{}, domain.ChunkerConfig{})
+}
+
+func TestService_IndexAndSearchOffline(t *testing.T) {
+	projectRoot := t.TempDir()
+	files := map[string]string{
+		"config/loader.go": "package config\n\n// LoadConfig reads the config file from disk\nfunc LoadConfig(path string) error { return nil }\n",
+		"ui/button.go":     "package ui\n\n// RenderButton draws a button with a label\nfunc RenderButton(label string) string { return label }\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(projectRoot, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	service := newOfflineService(t)
+	ctx := context.Background()
+	if err := service.IndexProject(ctx, projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+	if !service.IsIndexed(ctx, projectRoot) {
+		t.Fatal("project should be indexed")
+	}
+
+	resp, err := service.Search(ctx, domain.SemanticSearchRequest{
+		Query:       "load config file",
+		ProjectRoot: projectRoot,
+		TopK:        2,
+		MinScore:    0.01,
+		SearchType:  domain.SearchTypeSemantic,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(resp.Results) == 0 || resp.Results[0].Chunk.FilePath != filepath.Join("config", "loader.go") {
+		t.Fatalf("expected config/loader.go as top result, got %+v", resp.Results)
+	}
+
+	hybrid, err := service.Search(ctx, domain.SemanticSearchRequest{
+		Query:       "render button label",
+		ProjectRoot: projectRoot,
+		TopK:        1,
+		MinScore:    0.01,
+		SearchType:  domain.SearchTypeHybrid,
+	})
+	if err != nil {
+		t.Fatalf("hybrid Search failed: %v", err)
+	}
+	if len(hybrid.Results) != 1 || hybrid.Results[0].Chunk.FilePath != filepath.Join("ui", "button.go") {
+		t.Fatalf("expected ui/button.go as hybrid result, got %+v", hybrid.Results)
+	}
+
+	filtered, err := service.Search(ctx, domain.SemanticSearchRequest{
+		Query:       "load config file",
+		ProjectRoot: projectRoot,
+		MinScore:    0.01,
+		SearchType:  domain.SearchTypeSemantic,
+		Filters:     &domain.SearchFilters{ExcludeDirs: []string{"config"}},
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	for _, result := range filtered.Results {
+		if strings.HasPrefix(result.Chunk.FilePath, "config") {
+			t.Errorf("excluded dir returned: %s", result.Chunk.FilePath)
+		}
+	}
+}
+
+// stubSymbolIndex returns fixed symbols for any name search
+type stubSymbolIndex struct {
+	analysis.SymbolIndex
+	symbols []analysis.Symbol
+}
+
+func (s stubSymbolIndex) SearchByName(string) []analysis.Symbol { return s.symbols }
+
+func TestService_HybridSearchWeights(t *testing.T) {
+	projectRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectRoot, "config"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "package config\n\n// LoadConfig reads the config file from disk\nfunc LoadConfig(path string) error { return nil }\n"
+	if err := os.WriteFile(filepath.Join(projectRoot, "config", "loader.go"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	service := newOfflineService(t)
+	ctx := context.Background()
+	if err := service.IndexProject(ctx, projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+	// The keyword hit lives in a file the semantic index doesn't rank
+	service.symbolIndex = stubSymbolIndex{symbols: []analysis.Symbol{
+		{Name: "LoadConfig", FilePath: "legacy/config.go", StartLine: 12, EndLine: 20},
+	}}
+
+	search := func(semanticWeight, keywordWeight float32) (*domain.SemanticSearchResponse, error) {
+		return service.Search(ctx, domain.SemanticSearchRequest{
+			Query:          "LoadConfig",
+			ProjectRoot:    projectRoot,
+			TopK:           2,
+			MinScore:       0.01,
+			SearchType:     domain.SearchTypeHybrid,
+			SemanticWeight: semanticWeight,
+			KeywordWeight:  keywordWeight,
+		})
+	}
+
+	keyword, err := search(0.05, 1)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(keyword.Results) == 0 || keyword.Results[0].Chunk.FilePath != "legacy/config.go" {
+		t.Errorf("expected keyword hit first with keyword-dominant weights, got %+v", keyword.Results)
+	}
+
+	semantic, err := search(1, 0.001)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(semantic.Results) == 0 || semantic.Results[0].Chunk.FilePath != filepath.Join("config", "loader.go") {
+		t.Errorf("expected semantic hit first with semantic-dominant weights, got %+v", semantic.Results)
+	}
+
+	// An identifier query picks keyword-heavy weights by itself
+	auto, err := service.Search(ctx, domain.SemanticSearchRequest{
+		Query:       "LoadConfig",
+		ProjectRoot: projectRoot,
+		TopK:        2,
+		MinScore:    0.01,
+		SearchType:  domain.SearchTypeAuto,
+	})
+	if err != nil {
+		t.Fatalf("auto Search failed: %v", err)
+	}
+	if len(auto.Results) == 0 || auto.Results[0].Chunk.FilePath != "legacy/config.go" || auto.SearchType != domain.SearchTypeHybrid {
+		t.Errorf("expected keyword hit first for auto identifier search, got %+v", auto)
+	}
+
+	_, err = search(-1, 1)
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeValidationError {
+		t.Errorf("expected validation error for negative weight, got %v", err)
+	}
+}
+
+func TestService_HybridSearchExplain(t *testing.T) {
+	projectRoot := t.TempDir()
+	content := "package config\n\n// LoadConfig reads the config file from disk\nfunc LoadConfig(path string) error { return nil }\n"
+	if err := os.WriteFile(filepath.Join(projectRoot, "loader.go"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	service := newOfflineService(t)
+	ctx := context.Background()
+	if err := service.IndexProject(ctx, projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+	// The keyword hit is the chunk the semantic search finds too
+	service.symbolIndex = stubSymbolIndex{symbols: []analysis.Symbol{
+		{Name: "LoadConfig", FilePath: "loader.go", StartLine: 1, EndLine: 4},
+	}}
+
+	resp, err := service.Search(ctx, domain.SemanticSearchRequest{
+		Query:          "LoadConfig",
+		ProjectRoot:    projectRoot,
+		TopK:           1,
+		MinScore:       0.01,
+		SearchType:     domain.SearchTypeHybrid,
+		SemanticWeight: 0.6,
+		KeywordWeight:  0.4,
+		Explain:        true,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Explanation == nil {
+		t.Fatalf("expected one explained result, got %+v", resp.Results)
+	}
+	result := resp.Results[0]
+	breakdown := result.Explanation
+	if breakdown.SemanticScore <= 0 || breakdown.KeywordScore != 1 || breakdown.SemanticWeight != 0.6 || breakdown.KeywordWeight != 0.4 {
+		t.Errorf("unexpected breakdown %+v", breakdown)
+	}
+	want := breakdown.SemanticScore*breakdown.SemanticWeight + breakdown.KeywordScore*breakdown.KeywordWeight
+	if diff := result.Score - want; diff > 1e-6 || diff < -1e-6 {
+		t.Errorf("breakdown adds up to %v, score is %v", want, result.Score)
+	}
+
+	resp, err = service.Search(ctx, domain.SemanticSearchRequest{
+		Query: "LoadConfig", ProjectRoot: projectRoot, TopK: 1, MinScore: 0.01, SearchType: domain.SearchTypeHybrid,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(resp.Results) == 0 || resp.Results[0].Explanation != nil {
+		t.Errorf("expected no explanation unless requested, got %+v", resp.Results)
+	}
+}
+
+func TestService_SearchPaging(t *testing.T) {
+	projectRoot := t.TempDir()
+	files := map[string]string{
+		"config/loader.go": "package config\n\n// LoadConfig reads the config file\nfunc LoadConfig(path string) error { return nil }\n",
+		"config/saver.go":  "package config\n\n// SaveConfig writes the config file\nfunc SaveConfig(path string) error { return nil }\n",
+		"ui/button.go":     "package ui\n\n// RenderButton draws the config file button\nfunc RenderButton() {}\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(projectRoot, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	service := newOfflineService(t)
+	ctx := context.Background()
+	if err := service.IndexProject(ctx, projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+
+	search := func(offset int, threshold float32) *domain.SemanticSearchResponse {
+		t.Helper()
+		resp, err := service.Search(ctx, domain.SemanticSearchRequest{
+			Query:          "config file",
+			ProjectRoot:    projectRoot,
+			TopK:           2,
+			MinScore:       0.0001,
+			SearchType:     domain.SearchTypeSemantic,
+			Offset:         offset,
+			ScoreThreshold: threshold,
+		})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		return resp
+	}
+
+	first := search(0, 0)
+	if len(first.Results) != 2 || first.TotalAvailable != 3 {
+		t.Fatalf("expected 2 of 3 results on the first page, got %d of %d", len(first.Results), first.TotalAvailable)
+	}
+	second := search(2, 0)
+	if len(second.Results) != 1 || second.TotalAvailable != 3 || second.Offset != 2 {
+		t.Fatalf("expected the last result on the second page, got %+v", second)
+	}
+	for _, result := range first.Results {
+		if result.Chunk.FilePath == second.Results[0].Chunk.FilePath {
+			t.Errorf("pages overlap on %s", result.Chunk.FilePath)
+		}
+	}
+	if past := search(5, 0); len(past.Results) != 0 || past.TotalAvailable != 3 {
+		t.Errorf("expected an empty page past the end, got %+v", past)
+	}
+
+	// A threshold between the pages keeps only the first page
+	threshold := (first.Results[1].Score + second.Results[0].Score) / 2
+	if top := search(0, threshold); top.TotalAvailable != 2 || len(top.Results) != 2 {
+		t.Errorf("expected only the first page above the threshold, got %+v", top)
+	}
+
+	_, err := service.Search(ctx, domain.SemanticSearchRequest{Query: "config", ProjectRoot: projectRoot, Offset: -1})
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeValidationError {
+		t.Errorf("expected validation error for negative offset, got %v", err)
+	}
+}
+
+func TestService_SearchRelated(t *testing.T) {
+	projectRoot := t.TempDir()
+	files := map[string]string{
+		"config/loader.go": "package config\n\n// LoadConfig reads the config file\nfunc LoadConfig(path string) error { return nil }\n",
+		"config/saver.go":  "package config\n\n// SaveConfig writes the config file\nfunc SaveConfig(path string) error { return nil }\n",
+		"ui/button.go":     "package ui\n\n// RenderButton draws a button\nfunc RenderButton() {}\n",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(projectRoot, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	service := newOfflineService(t)
+	ctx := context.Background()
+	if err := service.IndexProject(ctx, projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+
+	source := filepath.Join(projectRoot, "config", "loader.go")
+	resp, err := service.SearchRelated(ctx, projectRoot, source, 3, 1)
+	if err != nil {
+		t.Fatalf("SearchRelated failed: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Chunk.FilePath != filepath.Join("config", "saver.go") {
+		t.Fatalf("expected config/saver.go as the only related chunk, got %+v", resp.Results)
+	}
+
+	if _, err := service.SearchRelated(ctx, projectRoot, "missing.go", 1, 5); err == nil {
+		t.Error("expected an error for a location without indexed chunks")
+	}
+}
+
+func TestChunkAtLine(t *testing.T) {
+	chunk := func(id string, start, end int) domain.EmbeddedChunk {
+		return domain.EmbeddedChunk{Chunk: domain.CodeChunk{ID: id, StartLine: start, EndLine: end}}
+	}
+	chunks := []domain.EmbeddedChunk{chunk("file", 1, 100), chunk("func", 10, 20), chunk("tail", 120, 130)}
+
+	tests := []struct {
+		line int
+		want string
+	}{
+		{15, "func"},
+		{50, "file"},
+		{110, "tail"},
+	}
+	for _, tt := range tests {
+		if got := chunkAtLine(chunks, tt.line); got == nil || got.Chunk.ID != tt.want {
+			t.Errorf("chunkAtLine(%d) = %+v, want %s", tt.line, got, tt.want)
+		}
+	}
+	if chunkAtLine(nil, 1) != nil {
+		t.Error("expected nil for no chunks")
+	}
+}
+
+func TestService_IndexProjectRebuildsAfterModelChange(t *testing.T) {
+	projectRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectRoot, "loader.go"), []byte("package config\n\nfunc LoadConfig() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	log := &domain.NoopLogger{}
+	store, err := embeddings.NewSQLiteVectorStore(t.TempDir(), log)
+	if err != nil {
+		t.Fatalf("failed to create vector store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	provider := embeddings.NewFakeEmbeddingProvider(0)
+	store.SetEmbeddingModel(domain.EmbeddingModelInfo{Model: "old-model", Dimensions: provider.GetModelInfo().Dimensions})
+	service := NewService(provider, store, nil, log, fileChunker{}, domain.ChunkerConfig{})
+
+	ctx := context.Background()
+	search := func() error {
+		_, err := service.Search(ctx, domain.SemanticSearchRequest{
+			Query: "load config", ProjectRoot: projectRoot, MinScore: 0.0001, SearchType: domain.SearchTypeSemantic,
+		})
+		return err
+	}
+	if err := service.IndexProject(ctx, projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+
+	store.SetEmbeddingModel(provider.GetModelInfo())
+	var domainErr *domain.DomainError
+	if err := search(); !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeReindexRequired {
+		t.Fatalf("expected a re-index error after the model changed, got %v", err)
+	}
+	if err := service.IndexFile(ctx, projectRoot, "loader.go"); err == nil {
+		t.Error("expected IndexFile to refuse mixing models")
+	}
+
+	if err := service.IndexProject(ctx, projectRoot); err != nil {
+		t.Fatalf("IndexProject after the model change failed: %v", err)
+	}
+	if err := search(); err != nil {
+		t.Errorf("expected search to work after re-indexing, got %v", err)
+	}
+}
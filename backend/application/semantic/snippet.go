@@ -0,0 +1,170 @@
+package semantic
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"shotgun_code/domain"
+)
+
+// snippetLines is the height of the window extracted from a chunk
+const snippetLines = 5
+
+// snippetStopWords are query words too common to rank lines by
+var snippetStopWords = []string{
+	"the", "and", "for", "with", "from", "into", "that", "this", "where",
+	"what", "how", "which", "are", "was", "does", "all",
+}
+
+// addSnippets fills each result's snippet with the window of lines that
+// mentions the most query terms, optionally marking the matched terms
+func addSnippets(results []domain.SemanticSearchResult, query string, highlight bool) {
+	terms := queryTerms(query)
+	for i := range results {
+		r := &results[i]
+		if r.Chunk.Content == "" {
+			continue
+		}
+
+		lines := strings.Split(strings.TrimRight(r.Chunk.Content, "\n"), "\n")
+		from := bestSnippetWindow(lines, terms, snippetLines)
+		to := min(from+snippetLines, len(lines))
+		snippet := strings.Join(lines[from:to], "\n")
+
+		matched := matchedTerms(snippet, terms)
+		if highlight {
+			snippet = highlightTerms(snippet, matched)
+		}
+		r.Snippet = snippet
+		r.SnippetStartLine = r.Chunk.StartLine + from
+		r.SnippetEndLine = r.Chunk.StartLine + to - 1
+		r.Highlights = matched
+	}
+}
+
+// bestSnippetWindow returns the first line of the window scoring highest;
+// distinct terms weigh more than repeated mentions of the same term
+func bestSnippetWindow(lines []string, terms []string, size int) int {
+	if len(lines) <= size || len(terms) == 0 {
+		return 0
+	}
+
+	lineTerms := make([][]string, len(lines))
+	for i, line := range lines {
+		lineTerms[i] = matchedTerms(line, terms)
+	}
+
+	best, bestScore := 0, 0
+	for from := 0; from+size <= len(lines); from++ {
+		distinct := make(map[string]bool)
+		hits := 0
+		for _, lt := range lineTerms[from : from+size] {
+			for _, term := range lt {
+				distinct[term] = true
+				hits++
+			}
+		}
+		if score := len(distinct)*10 + hits; score > bestScore {
+			best, bestScore = from, score
+		}
+	}
+
+	// Start at the first matching line; sliding never lowers the score
+	for best+size < len(lines) && len(lineTerms[best]) == 0 {
+		best++
+	}
+	return best
+}
+
+// queryTerms splits a query into lowercase words, camelCase included,
+// dropping short words and stop words
+func queryTerms(query string) []string {
+	var terms []string
+	for _, word := range splitIdentifiers(query) {
+		word = strings.ToLower(word)
+		if len(word) < 3 || slices.Contains(snippetStopWords, word) || slices.Contains(terms, word) {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// splitIdentifiers splits text on non-alphanumeric characters and camelCase boundaries
+func splitIdentifiers(text string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// matchedTerms returns the terms occurring in text, case-insensitively
+func matchedTerms(text string, terms []string) []string {
+	lower := strings.ToLower(text)
+	var matched []string
+	for _, term := range terms {
+		if strings.Contains(lower, term) {
+			matched = append(matched, term)
+		}
+	}
+	return matched
+}
+
+// highlightTerms wraps every case-insensitive occurrence of the terms in highlight markers
+func highlightTerms(text string, terms []string) string {
+	if len(terms) == 0 {
+		return text
+	}
+
+	// Mark matched byte ranges first so overlapping terms produce one span
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		return text // case folding changed byte offsets
+	}
+	marked := make([]bool, len(text))
+	for _, term := range terms {
+		for offset := 0; ; {
+			idx := strings.Index(lower[offset:], term)
+			if idx < 0 {
+				break
+			}
+			for j := offset + idx; j < offset+idx+len(term); j++ {
+				marked[j] = true
+			}
+			offset += idx + len(term)
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(text); i++ {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			sb.WriteString(domain.HighlightStart)
+		}
+		sb.WriteByte(text[i])
+		if marked[i] && (i == len(text)-1 || !marked[i+1]) {
+			sb.WriteString(domain.HighlightEnd)
+		}
+	}
+	return sb.String()
+}
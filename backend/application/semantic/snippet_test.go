@@ -0,0 +1,65 @@
+package semantic
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"shotgun_code/domain"
+)
+
+func TestQueryTerms(t *testing.T) {
+	got := queryTerms("How does the loadUserConfig parse YAML files for the user?")
+	want := []string{"load", "user", "config", "parse", "yaml", "files"}
+	if !slices.Equal(got, want) {
+		t.Errorf("queryTerms = %v, want %v", got, want)
+	}
+}
+
+func TestAddSnippets(t *testing.T) {
+	content := strings.Join([]string{
+		"package config",
+		"",
+		"import \"os\"",
+		"",
+		"// unrelated helper",
+		"func helper() {}",
+		"",
+		"// LoadConfig reads the config file",
+		"func LoadConfig(path string) error {",
+		"\tdata, err := os.ReadFile(path)",
+		"\treturn parse(data, err)",
+		"}",
+	}, "\n")
+	results := []domain.SemanticSearchResult{
+		{Chunk: domain.CodeChunk{Content: content, StartLine: 10, EndLine: 21}},
+		{Chunk: domain.CodeChunk{SymbolName: "NoContent"}},
+	}
+
+	addSnippets(results, "read config file", true)
+
+	r := results[0]
+	if r.SnippetStartLine != 17 || r.SnippetEndLine != 21 {
+		t.Errorf("snippet lines = %d-%d, want 17-21", r.SnippetStartLine, r.SnippetEndLine)
+	}
+	if !strings.HasPrefix(r.Snippet, "// Load"+domain.HighlightStart+"Config"+domain.HighlightEnd+" "+domain.HighlightStart+"read"+domain.HighlightEnd+"s the ") {
+		t.Errorf("unexpected snippet start: %q", r.Snippet)
+	}
+	if !strings.Contains(r.Snippet, "Load"+domain.HighlightStart+"Config"+domain.HighlightEnd) {
+		t.Errorf("expected camelCase match to be highlighted: %q", r.Snippet)
+	}
+	if !slices.Equal(r.Highlights, []string{"read", "config", "file"}) {
+		t.Errorf("Highlights = %v", r.Highlights)
+	}
+	if results[1].Snippet != "" {
+		t.Errorf("results without content should have no snippet, got %q", results[1].Snippet)
+	}
+}
+
+func TestHighlightTerms_MergesOverlaps(t *testing.T) {
+	got := highlightTerms("ReadFile", []string{"read", "readfile"})
+	want := domain.HighlightStart + "ReadFile" + domain.HighlightEnd
+	if got != want {
+		t.Errorf("highlightTerms = %q, want %q", got, want)
+	}
+}
@@ -2,7 +2,9 @@ package settings
 
 import (
 	"fmt"
+	"maps"
 	"shotgun_code/domain"
+	"slices"
 	"sync"
 )
 
@@ -18,6 +20,12 @@ type Service struct {
 	settingsRepo                  domain.SettingsRepository
 	modelFetchers                 domain.ModelFetcherRegistry
 	aiCacheInvalidator            AIProviderCacheInvalidator
+	backgroundIndexingListener    func(enabled bool)
+	safeModeListener              func(enabled bool)
+	commandLimitsListener         func(limits domain.CommandLimits)
+	verificationGatesListener     func(gates map[string]domain.VerificationGate)
+	enabledLanguagesListener      func(languages []string)
+	embeddingModelListener        func(model domain.EmbeddingModel)
 	onIgnoreRulesChangedCallbacks []func() error
 	muCallbacks                   sync.RWMutex
 }
@@ -45,6 +53,16 @@ func (s *Service) GetSettingsDTO() (domain.SettingsDTO, error) {
 
 // SaveSettingsDTO принимает DTO с фронтенда и обновляет настройки.
 func (s *Service) SaveSettingsDTO(dto domain.SettingsDTO) error {
+	if err := domain.ValidateOptimizationProfile(dto.OptimizationProfile); err != nil {
+		return err
+	}
+	if err := validateEmbeddingModel(dto.EmbeddingModel); err != nil {
+		return err
+	}
+	if err := domain.ValidateVerificationGates(dto.VerificationGates); err != nil {
+		return err
+	}
+
 	// Track if AI-related settings changed
 	oldDTO, _ := s.settingsRepo.GetSettingsDTO()
 	aiSettingsChanged := oldDTO.SelectedProvider != dto.SelectedProvider ||
@@ -53,7 +71,9 @@ func (s *Service) SaveSettingsDTO(dto domain.SettingsDTO) error {
 		oldDTO.OpenRouterAPIKey != dto.OpenRouterAPIKey ||
 		oldDTO.LocalAIAPIKey != dto.LocalAIAPIKey ||
 		oldDTO.LocalAIHost != dto.LocalAIHost ||
-		oldDTO.QwenAPIKey != dto.QwenAPIKey
+		oldDTO.QwenAPIKey != dto.QwenAPIKey ||
+		!maps.Equal(oldDTO.SelectedModels, dto.SelectedModels) ||
+		!slices.Equal(oldDTO.AIProviderFallback, dto.AIProviderFallback)
 
 	s.settingsRepo.SetCustomIgnoreRules(dto.CustomIgnoreRules)
 	s.settingsRepo.SetCustomPromptRules(dto.CustomPromptRules)
@@ -66,6 +86,20 @@ func (s *Service) SaveSettingsDTO(dto domain.SettingsDTO) error {
 	s.settingsRepo.SetSelectedAIProvider(dto.SelectedProvider)
 	s.settingsRepo.SetUseGitignore(dto.UseGitignore)
 	s.settingsRepo.SetUseCustomIgnore(dto.UseCustomIgnore)
+	s.settingsRepo.SetPreciseGoAnalysis(dto.PreciseGoAnalysis)
+	s.settingsRepo.SetEmbeddingProvider(dto.EmbeddingProvider)
+	s.settingsRepo.SetEmbeddingModel(dto.EmbeddingModel)
+	s.settingsRepo.SetVectorStore(dto.VectorStore)
+	s.settingsRepo.SetSimilarityMetric(dto.SimilarityMetric)
+	s.settingsRepo.SetBackgroundIndexing(dto.BackgroundIndexing)
+	s.settingsRepo.SetAIAuditIncludePrompts(dto.AIAuditIncludePrompts)
+	s.settingsRepo.SetSafeMode(dto.SafeMode)
+	s.settingsRepo.SetCommandLimits(dto.CommandLimits)
+	s.settingsRepo.SetEnabledLanguages(dto.EnabledLanguages)
+	s.settingsRepo.SetAIProviderFallback(dto.AIProviderFallback)
+	s.settingsRepo.SetOptimizationProfile(dto.OptimizationProfile)
+	s.settingsRepo.SetCustomOptimization(dto.CustomOptimization)
+	s.settingsRepo.SetVerificationGates(dto.VerificationGates)
 
 	for provider, model := range dto.SelectedModels {
 		s.settingsRepo.SetSelectedModel(provider, model)
@@ -83,6 +117,23 @@ func (s *Service) SaveSettingsDTO(dto domain.SettingsDTO) error {
 		s.aiCacheInvalidator.InvalidateProviderCache()
 	}
 
+	if oldDTO.BackgroundIndexing != dto.BackgroundIndexing && s.backgroundIndexingListener != nil {
+		s.backgroundIndexingListener(dto.BackgroundIndexing)
+	}
+	if oldDTO.SafeMode != dto.SafeMode && s.safeModeListener != nil {
+		s.safeModeListener(dto.SafeMode)
+	}
+	if oldDTO.CommandLimits != dto.CommandLimits && s.commandLimitsListener != nil {
+		s.commandLimitsListener(dto.CommandLimits)
+	}
+	if !slices.Equal(oldDTO.EnabledLanguages, dto.EnabledLanguages) && s.enabledLanguagesListener != nil {
+		s.enabledLanguagesListener(dto.EnabledLanguages)
+	}
+	if !maps.EqualFunc(oldDTO.VerificationGates, dto.VerificationGates, equalVerificationGates) && s.verificationGatesListener != nil {
+		s.verificationGatesListener(dto.VerificationGates)
+	}
+	s.notifyEmbeddingModelChanged(oldDTO.EmbeddingModel, dto.EmbeddingModel)
+
 	s.notifyIgnoreRulesChanged()
 	return nil
 }
@@ -166,6 +217,75 @@ func (s *Service) SetAICacheInvalidator(invalidator AIProviderCacheInvalidator)
 	s.aiCacheInvalidator = invalidator
 }
 
+// SetBackgroundIndexingListener sets the callback run when background indexing
+// is turned on or off
+func (s *Service) SetBackgroundIndexingListener(listener func(enabled bool)) {
+	s.backgroundIndexingListener = listener
+}
+
+// SetSafeModeListener sets the callback run when safe mode is turned on or off
+func (s *Service) SetSafeModeListener(listener func(enabled bool)) {
+	s.safeModeListener = listener
+}
+
+// SetCommandLimitsListener sets the callback run when the command limits change
+func (s *Service) SetCommandLimitsListener(listener func(limits domain.CommandLimits)) {
+	s.commandLimitsListener = listener
+}
+
+// SetEnabledLanguagesListener sets the callback run when the enabled languages change
+func (s *Service) SetEnabledLanguagesListener(listener func(languages []string)) {
+	s.enabledLanguagesListener = listener
+}
+
+// SetVerificationGatesListener sets the callback run when the verification gates change
+func (s *Service) SetVerificationGatesListener(listener func(gates map[string]domain.VerificationGate)) {
+	s.verificationGatesListener = listener
+}
+
+func equalVerificationGates(a, b domain.VerificationGate) bool {
+	return a.MinCoverage == b.MinCoverage && slices.Equal(a.Categories, b.Categories)
+}
+
+// SetEmbeddingModelListener sets the callback run when the embedding model changes
+func (s *Service) SetEmbeddingModelListener(listener func(model domain.EmbeddingModel)) {
+	s.embeddingModelListener = listener
+}
+
+// SetEmbeddingModel records the embedding model used for semantic search.
+// Projects indexed with another model must be re-indexed before they can be searched.
+func (s *Service) SetEmbeddingModel(model string) error {
+	if err := validateEmbeddingModel(model); err != nil {
+		return err
+	}
+	old := s.settingsRepo.GetEmbeddingModel()
+	s.settingsRepo.SetEmbeddingModel(model)
+	if err := s.settingsRepo.Save(); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	s.notifyEmbeddingModelChanged(old, model)
+	return nil
+}
+
+// validateEmbeddingModel accepts the OpenAI embedding models and "" for the default
+func validateEmbeddingModel(model string) error {
+	if model != "" && !slices.Contains(domain.OpenAIEmbeddingModels(), domain.EmbeddingModel(model)) {
+		return domain.NewValidationError("unknown embedding model", map[string]interface{}{
+			"embeddingModel": model,
+			"supported":      domain.OpenAIEmbeddingModels(),
+		})
+	}
+	return nil
+}
+
+// notifyEmbeddingModelChanged runs the embedding model listener if the resolved model changed
+func (s *Service) notifyEmbeddingModelChanged(old, current string) {
+	oldModel, model := domain.ResolveEmbeddingModel(old), domain.ResolveEmbeddingModel(current)
+	if oldModel != model && s.embeddingModelListener != nil {
+		s.embeddingModelListener(model)
+	}
+}
+
 // GetCustomIgnoreRules returns custom ignore rules
 func (s *Service) GetCustomIgnoreRules() string {
 	return s.settingsRepo.GetCustomIgnoreRules()
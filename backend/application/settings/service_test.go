@@ -3,6 +3,7 @@ package settings
 import (
 	"fmt"
 	"shotgun_code/domain"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -29,6 +30,12 @@ type mockSettingsRepo struct {
 	customPromptRules string
 	useGitignore      bool
 	useCustomIgnore   bool
+	preciseGo         bool
+	embeddingProvider string
+	embeddingModel    string
+	vectorStore       string
+	similarityMetric  string
+	backgroundIndex   bool
 	selectedProvider  string
 	openAIKey         string
 	geminiKey         string
@@ -42,6 +49,15 @@ type mockSettingsRepo struct {
 	availableModels   map[string][]string
 	recentProjects    []domain.RecentProjectInfo
 	saveError         error
+
+	auditIncludePrompts bool
+	safeMode            bool
+	commandLimits       domain.CommandLimits
+	enabledLanguages    []string
+	providerFallback    []string
+	optimizationProfile string
+	customOptimization  domain.ContentOptimizeOptions
+	verificationGates   map[string]domain.VerificationGate
 }
 
 func newMockSettingsRepo() *mockSettingsRepo {
@@ -70,8 +86,19 @@ func (m *mockSettingsRepo) GetSettingsDTO() (domain.SettingsDTO, error) {
 		LocalAIHost:       m.localAIHost,
 		LocalAIModelName:  m.localAIModelName,
 		QwenAPIKey:        m.qwenAPIKey,
+		EmbeddingModel:    m.embeddingModel,
 		SelectedModels:    m.selectedModels,
 		AvailableModels:   m.availableModels,
+
+		BackgroundIndexing:    m.backgroundIndex,
+		AIAuditIncludePrompts: m.auditIncludePrompts,
+		SafeMode:              m.safeMode,
+		CommandLimits:         m.commandLimits,
+		EnabledLanguages:      m.enabledLanguages,
+		AIProviderFallback:    m.providerFallback,
+		OptimizationProfile:   m.optimizationProfile,
+		CustomOptimization:    m.customOptimization,
+		VerificationGates:     m.verificationGates,
 	}, nil
 }
 
@@ -123,6 +150,174 @@ func (m *mockSettingsRepo) SetUseCustomIgnore(use bool) {
 	m.useCustomIgnore = use
 }
 
+func (m *mockSettingsRepo) GetPreciseGoAnalysis() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.preciseGo
+}
+
+func (m *mockSettingsRepo) SetPreciseGoAnalysis(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.preciseGo = enabled
+}
+
+func (m *mockSettingsRepo) GetEmbeddingProvider() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.embeddingProvider
+}
+
+func (m *mockSettingsRepo) SetEmbeddingProvider(provider string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.embeddingProvider = provider
+}
+
+func (m *mockSettingsRepo) GetEmbeddingModel() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.embeddingModel
+}
+
+func (m *mockSettingsRepo) SetEmbeddingModel(model string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.embeddingModel = model
+}
+
+func (m *mockSettingsRepo) GetVectorStore() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.vectorStore
+}
+
+func (m *mockSettingsRepo) SetVectorStore(store string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.vectorStore = store
+}
+
+func (m *mockSettingsRepo) GetSimilarityMetric() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.similarityMetric
+}
+
+func (m *mockSettingsRepo) SetSimilarityMetric(metric string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.similarityMetric = metric
+}
+
+func (m *mockSettingsRepo) GetBackgroundIndexing() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.backgroundIndex
+}
+
+func (m *mockSettingsRepo) SetBackgroundIndexing(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.backgroundIndex = enabled
+}
+
+func (m *mockSettingsRepo) GetAIAuditIncludePrompts() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.auditIncludePrompts
+}
+
+func (m *mockSettingsRepo) SetAIAuditIncludePrompts(include bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auditIncludePrompts = include
+}
+
+func (m *mockSettingsRepo) GetSafeMode() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.safeMode
+}
+
+func (m *mockSettingsRepo) SetSafeMode(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.safeMode = enabled
+}
+
+func (m *mockSettingsRepo) GetCommandLimits() domain.CommandLimits {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.commandLimits
+}
+
+func (m *mockSettingsRepo) GetEnabledLanguages() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabledLanguages
+}
+
+func (m *mockSettingsRepo) SetEnabledLanguages(languages []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabledLanguages = languages
+}
+
+func (m *mockSettingsRepo) GetAIProviderFallback() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.providerFallback
+}
+
+func (m *mockSettingsRepo) SetAIProviderFallback(providers []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.providerFallback = providers
+}
+
+func (m *mockSettingsRepo) GetOptimizationProfile() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.optimizationProfile
+}
+
+func (m *mockSettingsRepo) SetOptimizationProfile(profile string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.optimizationProfile = profile
+}
+
+func (m *mockSettingsRepo) GetCustomOptimization() domain.ContentOptimizeOptions {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.customOptimization
+}
+
+func (m *mockSettingsRepo) SetCustomOptimization(opts domain.ContentOptimizeOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.customOptimization = opts
+}
+
+func (m *mockSettingsRepo) GetVerificationGates() map[string]domain.VerificationGate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.verificationGates
+}
+
+func (m *mockSettingsRepo) SetVerificationGates(gates map[string]domain.VerificationGate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verificationGates = gates
+}
+
+func (m *mockSettingsRepo) SetCommandLimits(limits domain.CommandLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.commandLimits = limits
+}
+
 func (m *mockSettingsRepo) GetSelectedAIProvider() string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -387,6 +582,163 @@ func TestSaveSettingsDTO_InvalidatesAICache(t *testing.T) {
 	}
 }
 
+func TestSaveSettingsDTO_TogglesBackgroundIndexing(t *testing.T) {
+	repo := newMockSettingsRepo()
+	repo.backgroundIndex = true
+
+	svc, _ := NewService(&mockLogger{}, &mockEventBus{}, repo, nil)
+
+	var toggles []bool
+	svc.SetBackgroundIndexingListener(func(enabled bool) { toggles = append(toggles, enabled) })
+
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{BackgroundIndexing: false}); err != nil {
+		t.Fatalf("SaveSettingsDTO returned error: %v", err)
+	}
+	// Saving again without a change doesn't notify
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{BackgroundIndexing: false}); err != nil {
+		t.Fatalf("SaveSettingsDTO returned error: %v", err)
+	}
+
+	if len(toggles) != 1 || toggles[0] {
+		t.Errorf("Expected one toggle to false, got %v", toggles)
+	}
+	if repo.backgroundIndex {
+		t.Error("Expected background indexing to be stored as disabled")
+	}
+}
+
+func TestSaveSettingsDTO_TogglesSafeMode(t *testing.T) {
+	repo := newMockSettingsRepo()
+	svc, _ := NewService(&mockLogger{}, &mockEventBus{}, repo, nil)
+
+	mode := domain.NewSafeMode(false)
+	svc.SetSafeModeListener(mode.SetEnabled)
+
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{SafeMode: true}); err != nil {
+		t.Fatalf("SaveSettingsDTO returned error: %v", err)
+	}
+	if !mode.Enabled() || !repo.safeMode {
+		t.Error("Expected safe mode to be enabled and stored")
+	}
+
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{SafeMode: false}); err != nil {
+		t.Fatalf("SaveSettingsDTO returned error: %v", err)
+	}
+	if mode.Enabled() {
+		t.Error("Expected safe mode to be disabled")
+	}
+}
+
+func TestSaveSettingsDTO_UpdatesEnabledLanguages(t *testing.T) {
+	repo := newMockSettingsRepo()
+	svc, _ := NewService(&mockLogger{}, &mockEventBus{}, repo, nil)
+
+	scope := domain.NewLanguageScope(nil)
+	svc.SetEnabledLanguagesListener(scope.SetLanguages)
+
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{EnabledLanguages: []string{"go"}}); err != nil {
+		t.Fatalf("SaveSettingsDTO returned error: %v", err)
+	}
+	if scope.Enabled("python") || !scope.Enabled("go") {
+		t.Errorf("Expected only Go to be enabled, got %v", scope.Languages())
+	}
+	if len(repo.enabledLanguages) != 1 || repo.enabledLanguages[0] != "go" {
+		t.Errorf("Expected enabled languages to be stored, got %v", repo.enabledLanguages)
+	}
+
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{}); err != nil {
+		t.Fatalf("SaveSettingsDTO returned error: %v", err)
+	}
+	if !scope.Enabled("python") {
+		t.Error("Expected all languages to be enabled again")
+	}
+}
+
+func TestSaveSettingsDTO_OptimizationProfile(t *testing.T) {
+	repo := newMockSettingsRepo()
+	svc, _ := NewService(&mockLogger{}, &mockEventBus{}, repo, nil)
+
+	custom := domain.ContentOptimizeOptions{StripComments: true, TrimWhitespace: true}
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{OptimizationProfile: "custom", CustomOptimization: custom}); err != nil {
+		t.Fatalf("SaveSettingsDTO returned error: %v", err)
+	}
+	if repo.optimizationProfile != "custom" || repo.customOptimization != custom {
+		t.Errorf("Expected profile to be stored, got %q %+v", repo.optimizationProfile, repo.customOptimization)
+	}
+
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{OptimizationProfile: "turbo"}); err == nil {
+		t.Error("Expected error for unknown optimization profile")
+	}
+	if repo.optimizationProfile != "custom" {
+		t.Errorf("Expected profile to stay unchanged, got %q", repo.optimizationProfile)
+	}
+}
+
+func TestSaveSettingsDTO_VerificationGates(t *testing.T) {
+	repo := newMockSettingsRepo()
+	svc, _ := NewService(&mockLogger{}, &mockEventBus{}, repo, nil)
+
+	var notified []map[string]domain.VerificationGate
+	svc.SetVerificationGatesListener(func(gates map[string]domain.VerificationGate) {
+		notified = append(notified, gates)
+	})
+
+	gates := map[string]domain.VerificationGate{"strict": {Categories: []string{domain.ValidationCategoryBuild}, MinCoverage: 60}}
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{VerificationGates: gates}); err != nil {
+		t.Fatalf("SaveSettingsDTO returned error: %v", err)
+	}
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{VerificationGates: gates}); err != nil {
+		t.Fatalf("SaveSettingsDTO returned error: %v", err)
+	}
+	if len(notified) != 1 || notified[0]["strict"].MinCoverage != 60 {
+		t.Errorf("Expected one notification with the new gates, got %v", notified)
+	}
+
+	invalid := []map[string]domain.VerificationGate{
+		{"urgent": {}},
+		{"lite": {Categories: []string{"lint"}}},
+		{"lite": {MinCoverage: 120}},
+	}
+	for _, gates := range invalid {
+		if err := svc.SaveSettingsDTO(domain.SettingsDTO{VerificationGates: gates}); err == nil {
+			t.Errorf("Expected error for verification gates %v", gates)
+		}
+	}
+}
+
+func TestSetEmbeddingModel(t *testing.T) {
+	repo := newMockSettingsRepo()
+	svc, _ := NewService(&mockLogger{}, &mockEventBus{}, repo, nil)
+
+	var changes []domain.EmbeddingModel
+	svc.SetEmbeddingModelListener(func(model domain.EmbeddingModel) {
+		changes = append(changes, model)
+	})
+
+	if err := svc.SetEmbeddingModel(string(domain.EmbeddingModelOpenAI3L)); err != nil {
+		t.Fatalf("SetEmbeddingModel returned error: %v", err)
+	}
+	if repo.embeddingModel != string(domain.EmbeddingModelOpenAI3L) {
+		t.Errorf("Expected model to be stored, got %q", repo.embeddingModel)
+	}
+
+	// Re-selecting the same model, or the default under its name, is no change
+	_ = svc.SetEmbeddingModel(string(domain.EmbeddingModelOpenAI3L))
+	_ = svc.SetEmbeddingModel("")
+	_ = svc.SetEmbeddingModel(string(domain.DefaultEmbeddingModel))
+	want := []domain.EmbeddingModel{domain.EmbeddingModelOpenAI3L, domain.DefaultEmbeddingModel}
+	if !slices.Equal(changes, want) {
+		t.Errorf("Expected listener calls %v, got %v", want, changes)
+	}
+
+	if err := svc.SetEmbeddingModel("word2vec"); err == nil {
+		t.Error("Expected error for unknown embedding model")
+	}
+	if err := svc.SaveSettingsDTO(domain.SettingsDTO{EmbeddingModel: "word2vec"}); err == nil {
+		t.Error("Expected SaveSettingsDTO to reject unknown embedding model")
+	}
+}
+
 func TestOnIgnoreRulesChanged(t *testing.T) {
 	repo := newMockSettingsRepo()
 	svc, _ := NewService(&mockLogger{}, &mockEventBus{}, repo, nil)
@@ -0,0 +1,127 @@
+package symbol
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"shotgun_code/domain"
+	"sort"
+)
+
+// SetReferenceFinder задает поиск ссылок, уточняющий позиции определений и
+// локальные объявления, затеняющие символ (опционально)
+func (s *Service) SetReferenceFinder(finder domain.ReferenceFinder) {
+	s.referenceFinder = finder
+}
+
+// definitionCandidate — возможное определение символа
+type definitionCandidate struct {
+	node      *domain.SymbolNode
+	fromGraph bool
+}
+
+// FindDefinition находит определение символа symbolName, на который ссылается
+// файл fromFile. Предпочтение отдается определениям в том же файле, затем в
+// том же пакете; объявление в fromFile, которого нет в графе (например, x := ...),
+// затеняет одноименные символы пакета.
+func (s *Service) FindDefinition(ctx context.Context, projectRoot, language, symbolName, fromFile string) (*domain.SymbolNode, error) {
+	graph, err := s.BuildSymbolGraph(ctx, projectRoot, language)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRel := relativeToProject(projectRoot, fromFile)
+	definitions := s.findDefinitionRefs(ctx, projectRoot, symbolName)
+
+	var candidates []definitionCandidate
+	graphFiles := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		if node.Name != symbolName || node.Type == domain.SymbolTypeImport || node.Type == domain.SymbolTypePackage {
+			continue
+		}
+		path := filepath.ToSlash(node.Path)
+		graphFiles[path] = true
+		// Копия: узлы графа лежат в кэше
+		found := *node
+		if ref, ok := definitions[path]; ok {
+			found.Line, found.Column = ref.Line, ref.Column
+		}
+		candidates = append(candidates, definitionCandidate{node: &found, fromGraph: true})
+	}
+	// Определения, которых нет в графе: локальные объявления
+	for path, ref := range definitions {
+		if !graphFiles[path] {
+			candidates = append(candidates, definitionCandidate{node: &domain.SymbolNode{
+				ID:         fmt.Sprintf("local:%s:%s:%d", path, symbolName, ref.Line),
+				Name:       symbolName,
+				Type:       domain.SymbolTypeVariable,
+				Path:       path,
+				Line:       ref.Line,
+				Column:     ref.Column,
+				Visibility: domain.VisibilityPrivate,
+			}})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, domain.NewNotFoundError("symbol definition", symbolName)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return definitionRank(candidates[i], fromRel) < definitionRank(candidates[j], fromRel)
+	})
+	return candidates[0].node, nil
+}
+
+// findDefinitionRefs возвращает первое определение символа в каждом файле по
+// данным поиска ссылок
+func (s *Service) findDefinitionRefs(ctx context.Context, projectRoot, symbolName string) map[string]domain.SymbolReference {
+	definitions := make(map[string]domain.SymbolReference)
+	if s.referenceFinder == nil {
+		return definitions
+	}
+	refs, err := s.referenceFinder.FindReferences(ctx, projectRoot, symbolName, "")
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to find references to %s: %v", symbolName, err))
+		return definitions
+	}
+	for _, ref := range refs {
+		if !ref.IsDefinition {
+			continue
+		}
+		path := relativeToProject(projectRoot, ref.FilePath)
+		if prev, ok := definitions[path]; !ok || ref.Line < prev.Line {
+			definitions[path] = ref
+		}
+	}
+	return definitions
+}
+
+// definitionRank упорядочивает кандидатов: тот же файл, тот же пакет, узлы
+// графа, объявления верхнего уровня; меньше — лучше
+func definitionRank(c definitionCandidate, fromFile string) int {
+	path := filepath.ToSlash(c.node.Path)
+	rank := 0
+	if path != fromFile {
+		rank += 8
+	}
+	if filepath.Dir(path) != filepath.Dir(fromFile) {
+		rank += 4
+	}
+	if !c.fromGraph {
+		rank += 2
+	}
+	if c.node.Type == domain.SymbolTypeMethod || c.node.Type == domain.SymbolTypeField {
+		rank++
+	}
+	return rank
+}
+
+// relativeToProject приводит путь к виду относительно корня проекта
+func relativeToProject(projectRoot, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(projectRoot, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
@@ -0,0 +1,97 @@
+package symbol
+
+import (
+	"context"
+	"shotgun_code/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubGraphBuilder struct {
+	graph *domain.SymbolGraph
+}
+
+func (b *stubGraphBuilder) BuildGraph(context.Context, string) (*domain.SymbolGraph, error) {
+	return b.graph, nil
+}
+
+func (b *stubGraphBuilder) UpdateGraph(context.Context, string, []string) (*domain.SymbolGraph, error) {
+	return b.graph, nil
+}
+
+func (b *stubGraphBuilder) GetSuggestions(context.Context, string, *domain.SymbolGraph) ([]*domain.SymbolNode, error) {
+	return nil, nil
+}
+
+func (b *stubGraphBuilder) GetDependencies(context.Context, string, *domain.SymbolGraph) ([]*domain.SymbolNode, error) {
+	return nil, nil
+}
+
+func (b *stubGraphBuilder) GetDependents(context.Context, string, *domain.SymbolGraph) ([]*domain.SymbolNode, error) {
+	return nil, nil
+}
+
+type stubReferenceFinder struct {
+	refs []domain.SymbolReference
+}
+
+func (f *stubReferenceFinder) FindReferences(context.Context, string, string, string) ([]domain.SymbolReference, error) {
+	return f.refs, nil
+}
+
+func (f *stubReferenceFinder) FindUsages(context.Context, string, string, int, int) (*domain.SymbolUsagePage, error) {
+	return nil, nil
+}
+
+func (f *stubReferenceFinder) FindImplementations(context.Context, string, string) ([]domain.SymbolReference, error) {
+	return nil, nil
+}
+
+func newDefinitionService(refs ...domain.SymbolReference) *Service {
+	graph := &domain.SymbolGraph{Nodes: []*domain.SymbolNode{
+		{ID: "func:api/handler.go:Load", Name: "Load", Type: domain.SymbolTypeFunction, Path: "api/handler.go", Package: "api"},
+		{ID: "func:store/load.go:Load", Name: "Load", Type: domain.SymbolTypeFunction, Path: "store/load.go", Package: "store"},
+		{ID: "import:store/db.go:Load", Name: "Load", Type: domain.SymbolTypeImport, Path: "store/db.go", Package: "store"},
+	}}
+	svc := NewService(&domain.NoopLogger{}, map[string]domain.SymbolGraphBuilder{"go": &stubGraphBuilder{graph: graph}}, nil)
+	svc.SetReferenceFinder(&stubReferenceFinder{refs: refs})
+	return svc
+}
+
+func TestFindDefinition_PrefersSamePackage(t *testing.T) {
+	svc := newDefinitionService(
+		domain.SymbolReference{FilePath: "store/load.go", Line: 12, Column: 6, IsDefinition: true},
+		domain.SymbolReference{FilePath: "store/db.go", Line: 30, Column: 9},
+	)
+
+	node, err := svc.FindDefinition(context.Background(), "/project", "go", "Load", "/project/store/db.go")
+
+	require.NoError(t, err)
+	assert.Equal(t, "func:store/load.go:Load", node.ID)
+	assert.Equal(t, 12, node.Line)
+	assert.Equal(t, 6, node.Column)
+}
+
+func TestFindDefinition_LocalDeclarationShadowsPackageSymbol(t *testing.T) {
+	svc := newDefinitionService(
+		domain.SymbolReference{FilePath: "store/load.go", Line: 12, Column: 6, IsDefinition: true},
+		domain.SymbolReference{FilePath: "store/cache.go", Line: 8, Column: 2, IsDefinition: true},
+	)
+
+	node, err := svc.FindDefinition(context.Background(), "/project", "go", "Load", "store/cache.go")
+
+	require.NoError(t, err)
+	assert.Equal(t, "store/cache.go", node.Path)
+	assert.Equal(t, 8, node.Line)
+	assert.Equal(t, domain.SymbolTypeVariable, node.Type)
+}
+
+func TestFindDefinition_UnknownSymbol(t *testing.T) {
+	svc := newDefinitionService()
+
+	_, err := svc.FindDefinition(context.Background(), "/project", "go", "Missing", "api/handler.go")
+
+	require.Error(t, err)
+}
@@ -13,6 +13,7 @@ type Service struct {
 	log                 domain.Logger
 	symbolGraphBuilders map[string]domain.SymbolGraphBuilder
 	importGraphBuilders map[string]domain.ImportGraphBuilder
+	referenceFinder     domain.ReferenceFinder
 	cache               map[string]*domain.SymbolGraph
 	cacheTimestamps     map[string]int64
 	lastAccessed        map[string]int64
@@ -10,6 +10,9 @@ import (
 
 // StartAutonomousTask starts an autonomous task
 func (s *Service) StartAutonomousTask(ctx context.Context, request domain.AutonomousTaskRequest) (*domain.AutonomousTaskResponse, error) {
+	if err := s.safeMode.Check("running autonomous tasks"); err != nil {
+		return nil, err
+	}
 	s.log.Info(fmt.Sprintf("Starting autonomous task: %s", request.Task))
 
 	if err := s.validateAutonomousTaskRequest(request); err != nil {
@@ -54,21 +57,25 @@ func (s *Service) CancelAutonomousTask(ctx context.Context, taskID string) error
 	s.log.Info(fmt.Sprintf("Cancelling autonomous task: %s", taskID))
 
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	status, exists := s.statuses[taskID]
 	if !exists {
+		s.mu.Unlock()
 		return domain.NewTaskNotFoundError(taskID)
 	}
 
 	if status.State == domain.TaskStateDone {
+		s.mu.Unlock()
 		return domain.NewInvalidTaskStateError(taskID, string(status.State), "cancellable")
 	}
 
 	status.State = domain.TaskStateFailed
 	status.Message = "Task cancelled by user"
+	event := progressEvent(status)
+	err := s.saveStatuses()
+	s.mu.Unlock()
 
-	if err := s.saveStatuses(); err != nil {
+	s.emitProgress(event)
+	if err != nil {
 		return domain.NewInternalError("Failed to save task status after cancellation", err)
 	}
 
@@ -95,6 +102,7 @@ func (s *Service) GetAutonomousTaskStatus(ctx context.Context, taskID string) (*
 		StartedAt:              time.Now(),
 		UpdatedAt:              status.UpdatedAt,
 		Error:                  status.Error,
+		CommitHash:             status.CommitHash,
 	}
 
 	if status.StartedAt != nil {
@@ -140,60 +148,19 @@ func (s *Service) ListAutonomousTasks(ctx context.Context, projectPath string) (
 	return autonomousTasks, nil
 }
 
-// GetTaskLogs returns logs for a task
+// GetTaskLogs returns the execution log of a task ordered by timestamp
 func (s *Service) GetTaskLogs(ctx context.Context, taskID string) ([]domain.LogEntry, error) {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	status, exists := s.statuses[taskID]
+	_, exists := s.statuses[taskID]
+	s.mu.RUnlock()
 	if !exists {
 		return nil, domain.NewTaskNotFoundError(taskID)
 	}
 
-	var logs []domain.LogEntry
-
-	if status.StartedAt != nil {
-		logs = append(logs, domain.LogEntry{
-			ID:        fmt.Sprintf("%s-created", taskID),
-			TaskID:    taskID,
-			Level:     "INFO",
-			Message:   "Task created",
-			Timestamp: *status.StartedAt,
-			Metadata:  map[string]interface{}{"event": "task_created"},
-		})
+	logs, err := s.taskLogEntries(taskID)
+	if err != nil {
+		return nil, err
 	}
-
-	logs = append(logs, domain.LogEntry{
-		ID:        fmt.Sprintf("%s-status", taskID),
-		TaskID:    taskID,
-		Level:     "INFO",
-		Message:   fmt.Sprintf("Task status: %s - %s", status.State, status.Message),
-		Timestamp: status.UpdatedAt,
-		Metadata:  map[string]interface{}{"state": string(status.State), "progress": status.Progress},
-	})
-
-	if status.Error != "" {
-		logs = append(logs, domain.LogEntry{
-			ID:        fmt.Sprintf("%s-error", taskID),
-			TaskID:    taskID,
-			Level:     "ERROR",
-			Message:   status.Error,
-			Timestamp: status.UpdatedAt,
-			Metadata:  map[string]interface{}{"event": "task_error"},
-		})
-	}
-
-	if status.CompletedAt != nil {
-		logs = append(logs, domain.LogEntry{
-			ID:        fmt.Sprintf("%s-completed", taskID),
-			TaskID:    taskID,
-			Level:     "INFO",
-			Message:   "Task completed",
-			Timestamp: *status.CompletedAt,
-			Metadata:  map[string]interface{}{"event": "task_completed", "duration": status.Duration.String()},
-		})
-	}
-
 	s.log.Debug(fmt.Sprintf("Retrieved %d log entries for task %s", len(logs), taskID))
 	return logs, nil
 }
@@ -0,0 +1,202 @@
+package taskflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"shotgun_code/domain"
+)
+
+// workingTreeSnapshot maps uncommitted files to their state before a task
+// runs; a nil snapshot means the working tree could not be read
+type workingTreeSnapshot map[string]snapshotFile
+
+// snapshotFile is the content of an uncommitted file; exists is false for
+// files deleted in the working tree
+type snapshotFile struct {
+	content string
+	exists  bool
+}
+
+// errChangesUnmeasured is returned when the changes of a task cannot be listed
+var errChangesUnmeasured = errors.New("changes made by the task cannot be measured")
+
+// snapshotWorkingTree records the uncommitted files of a project, so changes
+// made by a task can be told apart from the user's own pending edits
+func (s *Service) snapshotWorkingTree(projectPath string) workingTreeSnapshot {
+	if s.gitRepo == nil || projectPath == "" {
+		return nil
+	}
+	files, err := s.gitRepo.GetUncommittedFiles(projectPath)
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to snapshot working tree: %v", err))
+		return nil
+	}
+	snapshot := make(workingTreeSnapshot, len(files))
+	for _, f := range files {
+		content, exists := readWorkingFileState(projectPath, f.Path)
+		snapshot[f.Path] = snapshotFile{content: content, exists: exists}
+	}
+	return snapshot
+}
+
+// changesSince returns the files changed since the snapshot and the number
+// of added plus removed lines in them
+func (s *Service) changesSince(projectPath string, before workingTreeSnapshot) ([]string, int64, error) {
+	if s.gitRepo == nil || before == nil {
+		return nil, 0, errChangesUnmeasured
+	}
+	files, err := s.gitRepo.GetUncommittedFiles(projectPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", errChangesUnmeasured, err)
+	}
+
+	var changed []string
+	var lines int64
+	for _, f := range files {
+		current := readWorkingFile(projectPath, f.Path)
+		snapshot, dirtyBefore := before[f.Path]
+		previous := snapshot.content
+		if !dirtyBefore {
+			// Clean before the task: compare against the committed version
+			previous, _ = s.gitRepo.GetFileContentAtCommit(projectPath, f.Path, "HEAD")
+		}
+		if dirtyBefore && previous == current {
+			continue
+		}
+		changed = append(changed, f.Path)
+		lines += countChangedLines(previous, current)
+	}
+	// Files that were dirty and are now reverted also count as changed
+	for path, snapshot := range before {
+		if !slices.ContainsFunc(files, func(f domain.FileStatus) bool { return f.Path == path }) {
+			changed = append(changed, path)
+			committed, _ := s.gitRepo.GetFileContentAtCommit(projectPath, path, "HEAD")
+			lines += countChangedLines(snapshot.content, committed)
+		}
+	}
+	sort.Strings(changed)
+	return changed, lines, nil
+}
+
+// restoreWorkingTree reverts the given files to their state in the snapshot:
+// files the user had edited get their snapshot content back, the rest are
+// reset to HEAD and files that are not in HEAD are removed
+func (s *Service) restoreWorkingTree(projectPath string, before workingTreeSnapshot, files []string) error {
+	var errs []error
+	for _, path := range files {
+		fullPath := filepath.Join(projectPath, path)
+		snapshot, dirtyBefore := before[path]
+		if !dirtyBefore {
+			committed, err := s.gitRepo.GetFileContentAtCommit(projectPath, path, "HEAD")
+			snapshot = snapshotFile{content: committed, exists: err == nil}
+		}
+		if !snapshot.exists {
+			if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.WriteFile(fullPath, []byte(snapshot.content), 0o644); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkTaskChanges measures the changes a task made since the baseline and
+// validates them with the guardrails. Rejected changes are reverted to the
+// baseline. A task with budgets fails when its changes cannot be measured.
+func (s *Service) checkTaskChanges(taskID, projectPath string, baseline workingTreeSnapshot, budgets domain.TaskBudgets) ([]string, error) {
+	files, linesChanged, err := s.changesSince(projectPath, baseline)
+	if err != nil {
+		if budgets.MaxFiles > 0 || budgets.MaxChangedLines > 0 {
+			return nil, fmt.Errorf("cannot enforce the budgets of task %s: %w", taskID, err)
+		}
+		s.log.Warning(fmt.Sprintf("Task %s: %v", taskID, err))
+	}
+	if err := s.validateWithGuardrails(taskID, budgets, files, linesChanged); err != nil {
+		if len(files) > 0 {
+			if restoreErr := s.restoreWorkingTree(projectPath, baseline, files); restoreErr != nil {
+				s.log.Error(fmt.Sprintf("Failed to revert changes of task %s: %v", taskID, restoreErr))
+			} else {
+				s.log.Info(fmt.Sprintf("Reverted %d files changed by task %s", len(files), taskID))
+			}
+		}
+		return nil, err
+	}
+	return files, nil
+}
+
+// countChangedLines counts lines added plus lines removed between two
+// versions, ignoring moves within the file
+func countChangedLines(before, after string) int64 {
+	counts := make(map[string]int)
+	for _, line := range splitLines(before) {
+		counts[line]++
+	}
+	for _, line := range splitLines(after) {
+		counts[line]--
+	}
+	var changed int64
+	for _, n := range counts {
+		if n < 0 {
+			n = -n
+		}
+		changed += int64(n)
+	}
+	return changed
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+func readWorkingFile(projectPath, relPath string) string {
+	content, _ := readWorkingFileState(projectPath, relPath)
+	return content
+}
+
+func readWorkingFileState(projectPath, relPath string) (string, bool) {
+	content, err := os.ReadFile(filepath.Join(projectPath, relPath))
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+// taskBudgetViolations checks the changes against the budgets of a task;
+// zero limits are not enforced
+func taskBudgetViolations(budgets domain.TaskBudgets, files int, linesChanged int64) []domain.BudgetViolation {
+	var violations []domain.BudgetViolation
+	check := func(policyID string, budgetType domain.BudgetType, current, limit int64, what string) {
+		if limit <= 0 || current <= limit {
+			return
+		}
+		violations = append(violations, domain.BudgetViolation{
+			PolicyID:  policyID,
+			Type:      budgetType,
+			Current:   current,
+			Limit:     limit,
+			Unit:      domain.BudgetUnitCount,
+			Message:   fmt.Sprintf("Task budget exceeded: %d %s changed (limit: %d)", current, what, limit),
+			Timestamp: time.Now(),
+		})
+	}
+	check("task-max-files", domain.BudgetTypeFiles, int64(files), int64(budgets.MaxFiles), "files")
+	check("task-max-changed-lines", domain.BudgetTypeLines, linesChanged, int64(budgets.MaxChangedLines), "lines")
+	return violations
+}
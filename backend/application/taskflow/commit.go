@@ -0,0 +1,105 @@
+package taskflow
+
+import (
+	"context"
+	"fmt"
+	"shotgun_code/domain"
+	"strings"
+)
+
+// commitMessageDiffChars limits how much of the diff is sent to generate a commit message
+const commitMessageDiffChars = 12000
+
+// TextGenerator generates a completion for a system and user prompt
+type TextGenerator func(ctx context.Context, systemPrompt, userPrompt string) (string, error)
+
+// SetCommitMessageGenerator sets the generator of commit messages for tasks
+// committed on success; without it messages are derived from the task
+func (s *Service) SetCommitMessageGenerator(generate TextGenerator) {
+	s.generateText = generate
+}
+
+const commitMessageSystemPrompt = `You write git commit messages in the Conventional Commits format.
+Reply with the commit message only: a subject line "type(scope): summary" of at most 72 characters,
+optionally followed by a blank line and a short body. Use one of the types
+feat, fix, refactor, perf, test, docs, build, ci, chore.`
+
+// commitMessage generates a conventional commit message for the task's diff.
+// If generation fails the message is derived from the task description.
+func (s *Service) commitMessage(ctx context.Context, task, diff string, files []string) string {
+	if s.generateText == nil {
+		return fallbackCommitMessage(task)
+	}
+	prompt := fmt.Sprintf("Task: %s\n\nChanged files:\n%s\n\nDiff:\n%s", task, strings.Join(files, "\n"), domain.TruncateString(diff, commitMessageDiffChars))
+	response, err := s.generateText(ctx, commitMessageSystemPrompt, prompt)
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to generate a commit message, deriving it from the task: %v", err))
+		return fallbackCommitMessage(task)
+	}
+	if message := cleanCommitMessage(response); message != "" {
+		return message
+	}
+	return fallbackCommitMessage(task)
+}
+
+// cleanCommitMessage strips the code fences and quotes LLMs wrap replies in
+func cleanCommitMessage(response string) string {
+	message := strings.TrimSpace(response)
+	if strings.HasPrefix(message, "```") {
+		message = strings.TrimPrefix(message[strings.IndexByte(message+"\n", '\n'):], "\n")
+		message = strings.TrimSuffix(strings.TrimSpace(message), "```")
+	}
+	return strings.Trim(strings.TrimSpace(message), "\"'`")
+}
+
+// fallbackCommitMessage derives a conventional commit message from the first
+// line of the task description
+func fallbackCommitMessage(task string) string {
+	summary, _, _ := strings.Cut(strings.TrimSpace(task), "\n")
+	return domain.TruncateString("chore: "+strings.TrimSpace(summary), 72)
+}
+
+// commitTaskChanges commits the changes of a completed task with a generated
+// message and records the commit on the task status. Only the task's files
+// are committed: a working tree that had uncommitted changes before the task
+// is left alone unless Options.CommitAll is set, which commits everything.
+func (s *Service) commitTaskChanges(ctx context.Context, request domain.AutonomousTaskRequest, taskID string, files []string, dirtyBefore bool) error {
+	if s.gitRepo == nil || !s.gitRepo.IsGitRepository(request.ProjectPath) {
+		return nil
+	}
+	if dirtyBefore && !request.Options.CommitAll {
+		s.log.Warning(fmt.Sprintf("[Task %s] Not committing: the working tree had unrelated uncommitted changes", taskID))
+		s.appendTaskLog(taskID, domain.TaskLogWarn, "", "Changes not committed: the working tree had unrelated uncommitted changes")
+		return nil
+	}
+	commitFiles := files
+	if request.Options.CommitAll {
+		commitFiles = nil
+	} else if len(files) == 0 {
+		return nil
+	}
+
+	diff, err := s.gitRepo.GenerateDiffAgainst(request.ProjectPath, "HEAD")
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("[Task %s] Failed to diff changes for the commit message: %v", taskID, err))
+	}
+	hash, err := s.gitRepo.Commit(request.ProjectPath, s.commitMessage(ctx, request.Task, diff, files), commitFiles)
+	if err != nil {
+		return fmt.Errorf("failed to commit task changes: %w", err)
+	}
+	s.setTaskCommit(taskID, hash)
+	return nil
+}
+
+// setTaskCommit records the commit a task's changes were committed in
+func (s *Service) setTaskCommit(taskID, hash string) {
+	if hash == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status, exists := s.statuses[taskID]; exists {
+		status.CommitHash = hash
+	}
+	s.log.Info(fmt.Sprintf("[Task %s] Committed changes as %s", taskID, hash))
+}
@@ -0,0 +1,112 @@
+package taskflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"shotgun_code/domain"
+)
+
+// commitRepo records the commits of a task run in the project itself
+type commitRepo struct {
+	*workingTreeRepo
+	message string
+	files   []string
+	commits int
+}
+
+func (r *commitRepo) IsGitRepository(string) bool { return true }
+
+func (r *commitRepo) GenerateDiffAgainst(string, string) (string, error) {
+	return "+func Login() {}", nil
+}
+
+func (r *commitRepo) GenerateDiff(string) (string, error) { return "", nil }
+
+func (r *commitRepo) Commit(_, message string, files []string) (string, error) {
+	r.message, r.files = message, files
+	r.commits++
+	return "abc123", nil
+}
+
+func runCommitTask(t *testing.T, options domain.AutonomousTaskOptions, dirty bool) (*Service, *commitRepo, string) {
+	t.Helper()
+	projectPath := t.TempDir()
+	committed := map[string]string{"main.go": "package main\n", "notes.txt": "notes\n"}
+	for path, content := range committed {
+		if dirty && path == "notes.txt" {
+			content = "my pending edit\n"
+		}
+		if err := os.WriteFile(filepath.Join(projectPath, path), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := &commitRepo{workingTreeRepo: &workingTreeRepo{committed: committed}}
+	service := &Service{
+		log:              &domain.NoopLogger{},
+		tasks:            make(map[string]domain.Task),
+		statuses:         make(map[string]*domain.TaskStatus),
+		planner:          &applyingPlanner{projectPath: projectPath, files: map[string]string{"login.go": "package main\n\nfunc Login() {}\n"}},
+		routerLlmService: heuristicRouter{},
+		gitRepo:          repo,
+	}
+	service.SetCommitMessageGenerator(func(_ context.Context, _, userPrompt string) (string, error) {
+		if !strings.Contains(userPrompt, "login.go") || !strings.Contains(userPrompt, "func Login") {
+			t.Errorf("prompt should contain the changed files and the diff, got %q", userPrompt)
+		}
+		return "```\nfeat(auth): add login\n```", nil
+	})
+
+	options.CommitOnSuccess = true
+	request := domain.AutonomousTaskRequest{Task: "add login", SlaPolicy: "lite", ProjectPath: projectPath, Options: options}
+	status := &domain.AutonomousTaskStatus{TaskId: "autonomous_1"}
+	if err := service.executeAutonomousTask(context.Background(), request, status); err != nil {
+		t.Fatalf("executeAutonomousTask failed: %v", err)
+	}
+	return service, repo, status.TaskId
+}
+
+func TestExecuteAutonomousTask_CommitOnSuccess(t *testing.T) {
+	service, repo, taskID := runCommitTask(t, domain.AutonomousTaskOptions{}, false)
+	if repo.message != "feat(auth): add login" {
+		t.Errorf("expected the generated message without fences, got %q", repo.message)
+	}
+	if !slices.Equal(repo.files, []string{"login.go"}) {
+		t.Errorf("expected only the task's files committed, got %v", repo.files)
+	}
+	status, err := service.GetAutonomousTaskStatus(context.Background(), taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.CommitHash != "abc123" {
+		t.Errorf("expected the commit on the task status, got %q", status.CommitHash)
+	}
+}
+
+func TestExecuteAutonomousTask_CommitSkippedOnDirtyTree(t *testing.T) {
+	service, repo, taskID := runCommitTask(t, domain.AutonomousTaskOptions{}, true)
+	if repo.commits != 0 {
+		t.Errorf("expected no commit with unrelated changes, got %d", repo.commits)
+	}
+	if status, _ := service.GetAutonomousTaskStatus(context.Background(), taskID); status.CommitHash != "" {
+		t.Errorf("expected no commit on the task status, got %q", status.CommitHash)
+	}
+}
+
+func TestExecuteAutonomousTask_CommitAll(t *testing.T) {
+	_, repo, _ := runCommitTask(t, domain.AutonomousTaskOptions{CommitAll: true}, true)
+	if repo.commits != 1 || repo.files != nil {
+		t.Errorf("expected one commit of the whole tree, got %d commits of %v", repo.commits, repo.files)
+	}
+}
+
+func TestFallbackCommitMessage(t *testing.T) {
+	if got := fallbackCommitMessage("  add login\nwith sessions"); got != "chore: add login" {
+		t.Errorf("fallbackCommitMessage = %q", got)
+	}
+}
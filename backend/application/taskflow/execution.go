@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"shotgun_code/domain"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -12,37 +14,67 @@ func (s *Service) safeExecuteAutonomousTask(ctx context.Context, request domain.
 	defer func() {
 		if r := recover(); r != nil {
 			s.log.Error(fmt.Sprintf("PANIC in autonomous task execution: %v", r))
+			s.notifyTaskFailure(status.TaskId, fmt.Sprintf("Internal error: %v", r))
 			s.updateAutonomousTaskStatus(status.TaskId, "failed",
 				fmt.Sprintf("Task execution panicked: %v", r), 100.0)
-			s.notifyTaskFailure(status.TaskId, fmt.Sprintf("Internal error: %v", r))
 		}
 	}()
 
 	if err := s.executeAutonomousTask(ctx, request, status); err != nil {
 		s.log.Error(fmt.Sprintf("Autonomous task execution failed: %v", err))
-		s.updateAutonomousTaskStatus(status.TaskId, "failed", err.Error(), 100.0)
 		s.notifyTaskFailure(status.TaskId, err.Error())
+		s.updateAutonomousTaskStatus(status.TaskId, "failed", err.Error(), 100.0)
 	}
 }
 
-// executeAutonomousTask executes autonomous task with self-correction loop
+// executeAutonomousTask executes autonomous task with self-correction loop.
+// The task completes only once its pipeline completes and the changes pass the
+// verification gate of its SLA policy; gate failures are repaired like failed steps.
+// With Options.UseWorktree the task runs in its own git worktree and branch.
 func (s *Service) executeAutonomousTask(ctx context.Context, request domain.AutonomousTaskRequest, status *domain.AutonomousTaskStatus) error {
+	worktree, err := s.createTaskWorktree(request, status.TaskId)
+	if err != nil {
+		return err
+	}
+	if worktree != nil {
+		request.ProjectPath = worktree.path
+		defer s.removeTaskWorktree(worktree, status.TaskId)
+	}
+
 	basePipeline, planningTask, err := s.planAutonomousTask(ctx, request, status)
 	if err != nil {
 		return err
 	}
 
+	s.enableEphemeralMode(status.TaskId)
+	defer s.disableEphemeralMode(status.TaskId)
+	baseline := s.snapshotWorkingTree(request.ProjectPath)
+
+	ctx = domain.WithTaskLog(ctx, s.taskLog(status.TaskId))
 	maxRetries := 3
 	for i := 0; i < maxRetries; i++ {
 		s.log.Info(fmt.Sprintf("[Task %s] Starting pipeline execution, attempt %d/%d.", status.TaskId, i+1, maxRetries))
+		domain.LogTaskStep(ctx, domain.TaskLogInfo, "", fmt.Sprintf("Attempt %d/%d started", i+1, maxRetries))
 		currentPipeline := *basePipeline
 
 		if err := s.planner.ExecutePipeline(ctx, &currentPipeline); err == nil && currentPipeline.Status == PipelineStatusCompleted {
-			s.finishAutonomousTask(request, status)
-			return nil
+			files, err := s.checkTaskChanges(status.TaskId, request.ProjectPath, baseline, planningTask.Budgets)
+			if err != nil {
+				return err
+			}
+			failures, err := s.runVerificationGate(ctx, request, files, status)
+			if err != nil {
+				return err
+			}
+			if len(failures) == 0 {
+				return s.finishAutonomousTask(ctx, request, status, worktree, files, len(baseline) > 0)
+			}
+			s.log.Error(fmt.Sprintf("[Task %s] Verification gate failed: %s", status.TaskId, strings.Join(failures, "; ")))
+			currentPipeline.Steps = append(slices.Clip(currentPipeline.Steps), verificationGateStep(status.TaskId, failures))
+		} else {
+			s.log.Error(fmt.Sprintf("[Task %s] Pipeline execution failed", status.TaskId))
 		}
 
-		s.log.Error(fmt.Sprintf("[Task %s] Pipeline execution failed", status.TaskId))
 		if err := s.attemptRepair(ctx, planningTask, &currentPipeline, status, i); err != nil {
 			return err
 		}
@@ -85,16 +117,33 @@ func (s *Service) planAutonomousTask(ctx context.Context, request domain.Autonom
 	return basePipeline, planningTask, nil
 }
 
-// finishAutonomousTask completes the task and generates report
-func (s *Service) finishAutonomousTask(request domain.AutonomousTaskRequest, status *domain.AutonomousTaskStatus) {
+// finishAutonomousTask completes the task and generates report. Changes made
+// in a worktree are committed to the task's branch and merged if requested;
+// other changes are committed with Options.CommitOnSuccess.
+func (s *Service) finishAutonomousTask(ctx context.Context, request domain.AutonomousTaskRequest, status *domain.AutonomousTaskStatus, worktree *taskWorktree, files []string, dirtyBefore bool) error {
 	s.updateAutonomousTaskStatus(status.TaskId, "running", "Generating final report...", 95.0)
-	if diff, err := s.gitRepo.GenerateDiff(request.ProjectPath); err != nil {
+	if worktree != nil {
+		if err := s.commitTaskWorktree(ctx, worktree, request, files, status.TaskId); err != nil {
+			return err
+		}
+	} else if request.Options.CommitOnSuccess {
+		if err := s.commitTaskChanges(ctx, request, status.TaskId, files, dirtyBefore); err != nil {
+			return err
+		}
+	}
+	if diff, err := s.taskDiff(request.ProjectPath, worktree); err != nil {
 		s.log.Error(fmt.Sprintf("[Task %s] Failed to generate git diff: %v", status.TaskId, err))
 	} else {
 		s.log.Info(fmt.Sprintf("[Task %s] Git Diff:\n%s", status.TaskId, diff))
 	}
+	if worktree != nil {
+		if err := s.mergeTaskWorktree(worktree); err != nil {
+			return err
+		}
+	}
 	s.updateAutonomousTaskStatus(status.TaskId, "completed", "Task completed successfully", 100.0)
 	s.log.Info(fmt.Sprintf("[Task %s] Autonomous task finished.", status.TaskId))
+	return nil
 }
 
 // attemptRepair attempts to repair a failed pipeline step
@@ -218,7 +267,7 @@ func (s *Service) validateAutonomousTaskRequest(request domain.AutonomousTaskReq
 		return fmt.Errorf("SLA policy cannot be empty")
 	}
 
-	validSLAPolicies := []string{"lite", "standard", "strict"}
+	validSLAPolicies := domain.SLAPolicies()
 	for _, policy := range validSLAPolicies {
 		if request.SlaPolicy == policy {
 			return nil
@@ -242,24 +291,36 @@ func (s *Service) hasRunningTasks() bool {
 
 func (s *Service) createTaskStatus(taskID string, _ domain.AutonomousTaskRequest) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	s.statuses[taskID] = &domain.TaskStatus{
+	status := &domain.TaskStatus{
 		TaskID: taskID,
 		State:  domain.TaskStateTodo,
 	}
+	s.statuses[taskID] = status
+	event := progressEvent(status)
+	err := s.saveStatuses()
+	s.mu.Unlock()
 
-	return s.saveStatuses()
+	s.emitProgress(event)
+	return err
 }
 
+// notifyTaskFailure records the failure reason; the taskflow:failed event is
+// emitted when the status is switched to failed
 func (s *Service) notifyTaskFailure(taskID string, errorMsg string) {
 	s.log.Error(fmt.Sprintf("Task %s failed: %s", taskID, errorMsg))
-}
 
-func (s *Service) updateAutonomousTaskStatus(taskID, status, message string, progress float64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	taskStatus, exists := s.statuses[taskID]
+	if !exists {
+		taskStatus = &domain.TaskStatus{TaskID: taskID}
+		s.statuses[taskID] = taskStatus
+	}
+	taskStatus.Error = errorMsg
+}
 
+func (s *Service) updateAutonomousTaskStatus(taskID, status, message string, progress float64) {
+	s.mu.Lock()
 	taskStatus, exists := s.statuses[taskID]
 	if !exists {
 		taskStatus = &domain.TaskStatus{TaskID: taskID}
@@ -277,6 +338,48 @@ func (s *Service) updateAutonomousTaskStatus(taskID, status, message string, pro
 	case "failed":
 		taskStatus.State = domain.TaskStateFailed
 	}
+	event := progressEvent(taskStatus)
+	s.mu.Unlock()
+
+	s.emitProgress(event)
+}
+
+// progressEvent snapshots a task status for the UI; the caller holds s.mu
+func progressEvent(status *domain.TaskStatus) domain.TaskflowProgressEvent {
+	return domain.TaskflowProgressEvent{
+		TaskID:   status.TaskID,
+		State:    status.State,
+		Progress: status.Progress,
+		Message:  status.Message,
+		Error:    status.Error,
+		Attempts: status.Attempts,
+
+		CommitHash: status.CommitHash,
+	}
+}
+
+// emitProgress records a status change in the task log and publishes it, and
+// completion or failure of the task
+func (s *Service) emitProgress(event domain.TaskflowProgressEvent) {
+	level, message := domain.TaskLogInfo, fmt.Sprintf("Task %s: %s", event.State, event.Message)
+	if event.State == domain.TaskStateFailed {
+		level = domain.TaskLogError
+		if event.Error != "" {
+			message += ": " + event.Error
+		}
+	}
+	s.appendTaskLog(event.TaskID, level, "", message)
+
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Emit(domain.EventTaskflowProgress, event)
+	switch event.State {
+	case domain.TaskStateDone:
+		s.eventBus.Emit(domain.EventTaskflowCompleted, event)
+	case domain.TaskStateFailed:
+		s.eventBus.Emit(domain.EventTaskflowFailed, event)
+	}
 }
 
 func (s *Service) buildContextForTask(_ context.Context, request domain.AutonomousTaskRequest) (map[string]interface{}, error) {
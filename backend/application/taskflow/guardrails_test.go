@@ -0,0 +1,206 @@
+package taskflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"shotgun_code/domain"
+	"shotgun_code/testutils"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// applyingPlanner writes files into the project as if the pipeline applied AI edits
+type applyingPlanner struct {
+	projectPath string
+	files       map[string]string
+}
+
+func (p *applyingPlanner) CreatePipeline(_ context.Context, task domain.Task, _ *PipelinePolicy) (*TaskPipeline, error) {
+	return &TaskPipeline{TaskID: task.ID, Policy: &PipelinePolicy{}}, nil
+}
+
+func (p *applyingPlanner) ExecutePipeline(_ context.Context, pipeline *TaskPipeline) error {
+	for path, content := range p.files {
+		if err := os.WriteFile(filepath.Join(p.projectPath, path), []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	pipeline.Status = PipelineStatusCompleted
+	return nil
+}
+
+func (p *applyingPlanner) GetPipelineStatus(*TaskPipeline) map[string]any {
+	return map[string]any{"progress": 1.0}
+}
+
+// workingTreeRepo reports files that differ from the committed contents as uncommitted
+type workingTreeRepo struct {
+	domain.GitRepository
+	committed map[string]string
+}
+
+func (r *workingTreeRepo) GetUncommittedFiles(projectRoot string) ([]domain.FileStatus, error) {
+	entries, err := os.ReadDir(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	var files []domain.FileStatus
+	for _, e := range entries {
+		if readWorkingFile(projectRoot, e.Name()) != r.committed[e.Name()] {
+			files = append(files, domain.FileStatus{Path: e.Name(), Status: "M"})
+		}
+	}
+	return files, nil
+}
+
+func (r *workingTreeRepo) GetFileContentAtCommit(_, filePath, _ string) (string, error) {
+	content, ok := r.committed[filePath]
+	if !ok {
+		return "", fmt.Errorf("path %s does not exist in HEAD", filePath)
+	}
+	return content, nil
+}
+
+func newGuardrailTestService(t *testing.T, task domain.Task, edits map[string]string, guardrails domain.GuardrailService) *Service {
+	t.Helper()
+	projectPath := t.TempDir()
+	committed := map[string]string{"main.go": "package main\n\nfunc main() {}\n"}
+	for path, content := range committed {
+		if err := os.WriteFile(filepath.Join(projectPath, path), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	task.Metadata = map[string]interface{}{"project_path": projectPath}
+	return &Service{
+		log:        &domain.NoopLogger{},
+		tasks:      map[string]domain.Task{task.ID: task},
+		statuses:   make(map[string]*domain.TaskStatus),
+		planner:    &applyingPlanner{projectPath: projectPath, files: edits},
+		guardrails: guardrails,
+		gitRepo:    &workingTreeRepo{committed: committed},
+	}
+}
+
+func TestExecuteTask_ScaffoldBudgetExceeded(t *testing.T) {
+	guardrails := &testutils.MockGuardrailService{}
+	guardrails.On("EnableEphemeralMode", "scaffold_api", "scaffold", 5*time.Minute).Return(nil).Once()
+	guardrails.On("ValidateTask", "scaffold_api", []string{"handler.go", "main.go"}, int64(5)).
+		Return(&domain.TaskValidationResult{TaskID: "scaffold_api", Valid: true}, nil).Once()
+	guardrails.On("DisableEphemeralMode").Return().Once()
+
+	task := domain.Task{ID: "scaffold_api", Budgets: domain.TaskBudgets{MaxFiles: 1, MaxChangedLines: 10}}
+	service := newGuardrailTestService(t, task, map[string]string{
+		"main.go":    "package main\n\nfunc main() { serve() }\n",
+		"handler.go": "package main\n\nfunc serve() {}\n",
+	}, guardrails)
+
+	err := service.ExecuteTask(context.Background(), "scaffold_api")
+
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeGuardrailViolation {
+		t.Fatalf("expected guardrail violation, got %v", err)
+	}
+	violations, _ := domainErr.Context["budgetViolations"].([]domain.BudgetViolation)
+	if len(violations) != 1 || violations[0].Type != domain.BudgetTypeFiles || violations[0].Current != 2 || violations[0].Limit != 1 {
+		t.Errorf("unexpected budget violations %+v", violations)
+	}
+	if status, _ := service.GetTaskStatus("scaffold_api"); status == nil || status.State != domain.TaskStateFailed {
+		t.Errorf("expected failed status, got %+v", status)
+	}
+
+	// The rejected edits are reverted
+	projectPath := service.tasks["scaffold_api"].Metadata["project_path"].(string)
+	if content := readWorkingFile(projectPath, "main.go"); content != "package main\n\nfunc main() {}\n" {
+		t.Errorf("main.go was not restored: %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, "handler.go")); !os.IsNotExist(err) {
+		t.Errorf("handler.go created by the task should be removed, stat error: %v", err)
+	}
+	guardrails.AssertExpectations(t)
+}
+
+func TestExecuteTask_BudgetsFailWhenChangesUnmeasured(t *testing.T) {
+	task := domain.Task{ID: "no_project", Budgets: domain.TaskBudgets{MaxFiles: 1}}
+	service := newGuardrailTestService(t, task, map[string]string{"main.go": "package main\n"}, nil)
+	service.tasks["no_project"] = domain.Task{ID: "no_project", Budgets: task.Budgets}
+
+	if err := service.ExecuteTask(context.Background(), "no_project"); !errors.Is(err, errChangesUnmeasured) {
+		t.Fatalf("expected unmeasured changes error, got %v", err)
+	}
+	if status, _ := service.GetTaskStatus("no_project"); status == nil || status.State != domain.TaskStateFailed {
+		t.Errorf("expected failed status, got %+v", status)
+	}
+}
+
+func TestExecuteTask_WithinBudget(t *testing.T) {
+	guardrails := &testutils.MockGuardrailService{}
+	guardrails.On("ValidateTask", "feature_login", []string{"main.go"}, int64(2)).
+		Return(&domain.TaskValidationResult{TaskID: "feature_login", Valid: true}, nil).Once()
+
+	task := domain.Task{ID: "feature_login", Budgets: domain.TaskBudgets{MaxFiles: 1, MaxChangedLines: 10}}
+	service := newGuardrailTestService(t, task, map[string]string{
+		"main.go": "package main\n\nfunc main() { login() }\n",
+	}, guardrails)
+
+	if err := service.ExecuteTask(context.Background(), "feature_login"); err != nil {
+		t.Fatalf("ExecuteTask failed: %v", err)
+	}
+	if status, _ := service.GetTaskStatus("feature_login"); status == nil || status.State != domain.TaskStateDone {
+		t.Errorf("expected done status, got %+v", status)
+	}
+	guardrails.AssertNotCalled(t, "EnableEphemeralMode", mock.Anything, mock.Anything, mock.Anything)
+	guardrails.AssertExpectations(t)
+}
+
+func TestCountChangedLines(t *testing.T) {
+	if n := countChangedLines("a\nb\nc\n", "a\nc\nd\ne\n"); n != 3 {
+		t.Errorf("expected 3 changed lines, got %d", n)
+	}
+	if n := countChangedLines("", "a\nb\n"); n != 2 {
+		t.Errorf("expected 2 added lines, got %d", n)
+	}
+}
+
+func TestExecuteTask_PlanTaskBudgetsUseProjectRoot(t *testing.T) {
+	projectPath := t.TempDir()
+	committed := map[string]string{"main.go": "package main\n\nfunc main() {}\n"}
+	plan := "tasks:\n  - id: edit_api\n    budgets:\n      maxFiles: 1\n"
+	if err := os.WriteFile(filepath.Join(projectPath, "main.go"), []byte(committed["main.go"]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(projectPath, "tasks"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectPath, "tasks", "plan.yaml"), []byte(plan), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{
+		log:      &domain.NoopLogger{},
+		tasks:    make(map[string]domain.Task),
+		statuses: make(map[string]*domain.TaskStatus),
+		planner: &applyingPlanner{projectPath: projectPath, files: map[string]string{
+			"main.go":    "package main\n\nfunc main() { serve() }\n",
+			"handler.go": "package main\n\nfunc serve() {}\n",
+		}},
+		gitRepo: &workingTreeRepo{committed: committed},
+	}
+	if err := service.SetProjectRoot(projectPath); err != nil {
+		t.Fatal(err)
+	}
+
+	err := service.ExecuteTask(context.Background(), "edit_api")
+
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeGuardrailViolation {
+		t.Fatalf("expected the budgets to be enforced in the project root, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, "handler.go")); !os.IsNotExist(err) {
+		t.Errorf("handler.go created by the task should be removed, stat error: %v", err)
+	}
+}
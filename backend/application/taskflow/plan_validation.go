@@ -0,0 +1,291 @@
+package taskflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"shotgun_code/domain"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlErrorLine извлекает номер строки из ошибки разбора yaml.v3
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// planTaskNode - задача plan.yaml вместе с узлами YAML для указания строк
+type planTaskNode struct {
+	id        string
+	node      *yaml.Node
+	idNode    *yaml.Node
+	dependsOn []*yaml.Node
+	stepFile  *yaml.Node
+	budgets   *yaml.Node
+}
+
+// ValidatePlan проверяет plan.yaml до выполнения: дубликаты ID, ссылки dependsOn
+// на несуществующие задачи, циклы, отсутствующие stepFile и отрицательные бюджеты.
+// Ошибка возвращается, только если план не удалось прочитать
+func (s *Service) ValidatePlan(planPath string) (*domain.PlanValidationResult, error) {
+	if planPath == "" {
+		planPath = s.planPath
+	}
+
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+
+	result := validatePlan(planPath, data)
+	s.log.Info(fmt.Sprintf("Validated plan %s: %d tasks, %d problems", planPath, result.TaskCount, len(result.Problems)))
+	return result, nil
+}
+
+// validatePlan проверяет содержимое plan.yaml
+func validatePlan(planPath string, data []byte) *domain.PlanValidationResult {
+	v := &planValidator{
+		planDir: filepath.Dir(planPath),
+		lines:   strings.Split(string(data), "\n"),
+	}
+	result := &domain.PlanValidationResult{PlanPath: planPath, Problems: []domain.PlanProblem{}}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		line := 0
+		if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+		}
+		v.addAtLine(domain.PlanProblemInvalidYAML, "", "", line, fmt.Sprintf("failed to parse plan: %v", err))
+		result.Problems = v.problems
+		return result
+	}
+
+	tasks := v.collectTasks(&doc)
+	result.TaskCount = len(tasks)
+
+	ids := v.checkIDs(tasks)
+	for _, task := range tasks {
+		v.checkDependencies(task, ids)
+		v.checkStepFile(task)
+		v.checkBudgets(task)
+	}
+	v.checkCycles(tasks, ids)
+
+	sort.SliceStable(v.problems, func(i, j int) bool { return v.problems[i].Line < v.problems[j].Line })
+	result.Problems = v.problems
+	result.Valid = len(v.problems) == 0
+	return result
+}
+
+// planValidator накапливает проблемы плана
+type planValidator struct {
+	planDir  string
+	lines    []string
+	problems []domain.PlanProblem
+}
+
+// add добавляет проблему со строкой узла YAML
+func (v *planValidator) add(code, taskID, field string, node *yaml.Node, message string) {
+	line := 0
+	if node != nil {
+		line = node.Line
+	}
+	v.addAtLine(code, taskID, field, line, message)
+}
+
+// addAtLine добавляет проблему с указанной строкой и ее текстом
+func (v *planValidator) addAtLine(code, taskID, field string, line int, message string) {
+	problem := domain.PlanProblem{Code: code, TaskID: taskID, Field: field, Message: message, Line: line}
+	if line > 0 && line <= len(v.lines) {
+		problem.Context = strings.TrimRight(v.lines[line-1], "\r")
+	}
+	v.problems = append(v.problems, problem)
+}
+
+// collectTasks извлекает задачи из узла tasks
+func (v *planValidator) collectTasks(doc *yaml.Node) []*planTaskNode {
+	root := doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	tasksNode := mappingValue(root, "tasks")
+	if tasksNode == nil {
+		return nil
+	}
+	if tasksNode.Kind != yaml.SequenceNode {
+		v.add(domain.PlanProblemInvalidYAML, "", "tasks", tasksNode, "tasks must be a list")
+		return nil
+	}
+
+	tasks := make([]*planTaskNode, 0, len(tasksNode.Content))
+	for _, node := range tasksNode.Content {
+		if node.Kind != yaml.MappingNode {
+			v.add(domain.PlanProblemInvalidYAML, "", "", node, "task must be a mapping")
+			continue
+		}
+		task := &planTaskNode{
+			node:     node,
+			idNode:   mappingValue(node, "id"),
+			stepFile: mappingValue(node, "stepFile"),
+			budgets:  mappingValue(node, "budgets"),
+		}
+		if task.idNode != nil {
+			task.id = strings.TrimSpace(task.idNode.Value)
+		}
+		if deps := mappingValue(node, "dependsOn"); deps != nil {
+			if deps.Kind == yaml.SequenceNode {
+				task.dependsOn = deps.Content
+			} else {
+				v.add(domain.PlanProblemInvalidYAML, task.id, "dependsOn", deps, "dependsOn must be a list of task IDs")
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// checkIDs проверяет наличие и уникальность ID и возвращает известные ID
+func (v *planValidator) checkIDs(tasks []*planTaskNode) map[string]*planTaskNode {
+	ids := make(map[string]*planTaskNode, len(tasks))
+	for _, task := range tasks {
+		if task.id == "" {
+			v.add(domain.PlanProblemMissingID, "", "id", task.node, "task has no id")
+			continue
+		}
+		if first, exists := ids[task.id]; exists {
+			v.add(domain.PlanProblemDuplicateID, task.id, "id", task.idNode,
+				fmt.Sprintf("duplicate task id %q (first defined on line %d)", task.id, first.idNode.Line))
+			continue
+		}
+		ids[task.id] = task
+	}
+	return ids
+}
+
+// checkDependencies проверяет, что dependsOn ссылается на существующие задачи
+func (v *planValidator) checkDependencies(task *planTaskNode, ids map[string]*planTaskNode) {
+	for _, dep := range task.dependsOn {
+		if _, exists := ids[dep.Value]; !exists {
+			v.add(domain.PlanProblemUnknownDependency, task.id, "dependsOn", dep,
+				fmt.Sprintf("task %q depends on unknown task %q", task.id, dep.Value))
+		}
+	}
+}
+
+// checkStepFile проверяет, что stepFile существует (относительно рабочего каталога или каталога плана)
+func (v *planValidator) checkStepFile(task *planTaskNode) {
+	if task.stepFile == nil || task.stepFile.Value == "" {
+		return
+	}
+	path := task.stepFile.Value
+	if _, err := os.Stat(path); err == nil {
+		return
+	}
+	if !filepath.IsAbs(path) {
+		if _, err := os.Stat(filepath.Join(v.planDir, path)); err == nil {
+			return
+		}
+	}
+	v.add(domain.PlanProblemMissingStepFile, task.id, "stepFile", task.stepFile,
+		fmt.Sprintf("step file not found: %s", path))
+}
+
+// checkBudgets проверяет, что бюджеты задачи - неотрицательные целые числа
+func (v *planValidator) checkBudgets(task *planTaskNode) {
+	if task.budgets == nil {
+		return
+	}
+	for _, name := range []string{"maxFiles", "maxChangedLines"} {
+		node := mappingValue(task.budgets, name)
+		if node == nil {
+			continue
+		}
+		value, err := strconv.Atoi(node.Value)
+		if err != nil {
+			v.add(domain.PlanProblemInvalidYAML, task.id, "budgets."+name, node,
+				fmt.Sprintf("budgets.%s must be an integer, got %q", name, node.Value))
+			continue
+		}
+		if value < 0 {
+			v.add(domain.PlanProblemNegativeBudget, task.id, "budgets."+name, node,
+				fmt.Sprintf("budgets.%s must not be negative, got %d", name, value))
+		}
+	}
+}
+
+// checkCycles ищет циклы в зависимостях; каждый цикл сообщается один раз
+func (v *planValidator) checkCycles(tasks []*planTaskNode, ids map[string]*planTaskNode) {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int, len(ids))
+	var stack []string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = inProgress
+		stack = append(stack, id)
+		for _, dep := range ids[id].dependsOn {
+			if _, exists := ids[dep.Value]; !exists {
+				continue
+			}
+			switch state[dep.Value] {
+			case unvisited:
+				visit(dep.Value)
+			case inProgress:
+				start := 0
+				for i, stacked := range stack {
+					if stacked == dep.Value {
+						start = i
+						break
+					}
+				}
+				cycle := append(append([]string{}, stack[start:]...), dep.Value)
+				v.add(domain.PlanProblemDependencyCycle, id, "dependsOn", dep,
+					fmt.Sprintf("dependency cycle: %s", strings.Join(cycle, " -> ")))
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+
+	for _, task := range tasks {
+		if task.id != "" && ids[task.id] == task && state[task.id] == unvisited {
+			visit(task.id)
+		}
+	}
+}
+
+// mappingValue возвращает значение ключа узла-отображения или nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// planProblemsError описывает проблемы плана одной ошибкой
+func planProblemsError(result *domain.PlanValidationResult) error {
+	messages := make([]string, 0, len(result.Problems))
+	for _, problem := range result.Problems {
+		if problem.Line > 0 {
+			messages = append(messages, fmt.Sprintf("line %d: %s", problem.Line, problem.Message))
+		} else {
+			messages = append(messages, problem.Message)
+		}
+	}
+	return domain.NewValidationError(
+		fmt.Sprintf("plan %s has %d problems: %s", result.PlanPath, len(result.Problems), strings.Join(messages, "; ")),
+		map[string]interface{}{"planPath": result.PlanPath, "problems": result.Problems},
+	)
+}
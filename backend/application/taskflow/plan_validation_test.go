@@ -0,0 +1,113 @@
+package taskflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"shotgun_code/domain"
+)
+
+func writePlan(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "step_ok.md"), []byte("# step\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	planPath := filepath.Join(dir, "plan.yaml")
+	if err := os.WriteFile(planPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return planPath
+}
+
+func TestValidatePlan_ReportsProblemsWithLines(t *testing.T) {
+	planPath := writePlan(t, `version: 1
+tasks:
+  - id: setup
+    stepFile: step_ok.md
+  - id: build
+    dependsOn: [setup, deploy]
+    stepFile: missing.md
+  - id: setup
+    budgets:
+      maxFiles: -1
+  - id: a
+    dependsOn: [b]
+  - id: b
+    dependsOn: [a]
+`)
+	service := &Service{log: &domain.NoopLogger{}}
+
+	result, err := service.ValidatePlan(planPath)
+	if err != nil {
+		t.Fatalf("ValidatePlan failed: %v", err)
+	}
+	if result.Valid || result.TaskCount != 5 {
+		t.Fatalf("expected invalid plan with 5 tasks, got %+v", result)
+	}
+
+	expected := []struct {
+		code string
+		line int
+	}{
+		{domain.PlanProblemUnknownDependency, 6},
+		{domain.PlanProblemMissingStepFile, 7},
+		{domain.PlanProblemDuplicateID, 8},
+		{domain.PlanProblemNegativeBudget, 10},
+		{domain.PlanProblemDependencyCycle, 14},
+	}
+	if len(result.Problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %+v", len(expected), result.Problems)
+	}
+	for i, want := range expected {
+		got := result.Problems[i]
+		if got.Code != want.code || got.Line != want.line {
+			t.Errorf("problem %d: expected %s on line %d, got %s on line %d", i, want.code, want.line, got.Code, got.Line)
+		}
+	}
+	if result.Problems[3].Context != "      maxFiles: -1" {
+		t.Errorf("unexpected context %q", result.Problems[3].Context)
+	}
+	if result.Problems[4].Message != "dependency cycle: a -> b -> a" {
+		t.Errorf("unexpected cycle message %q", result.Problems[4].Message)
+	}
+}
+
+func TestValidatePlan_InvalidYAML(t *testing.T) {
+	planPath := writePlan(t, "tasks:\n  - id: a\n   name: [broken\n")
+	service := &Service{log: &domain.NoopLogger{}}
+
+	result, err := service.ValidatePlan(planPath)
+	if err != nil {
+		t.Fatalf("ValidatePlan failed: %v", err)
+	}
+	if result.Valid || len(result.Problems) != 1 || result.Problems[0].Code != domain.PlanProblemInvalidYAML {
+		t.Fatalf("expected a single invalid_yaml problem, got %+v", result.Problems)
+	}
+	if result.Problems[0].Line == 0 {
+		t.Errorf("expected the parse error line to be reported")
+	}
+}
+
+func TestExecuteTaskflow_RefusesInvalidPlan(t *testing.T) {
+	planPath := writePlan(t, "tasks:\n  - id: a\n    dependsOn: [missing]\n")
+	service := &Service{
+		log:      &domain.NoopLogger{},
+		planPath: planPath,
+		tasks:    make(map[string]domain.Task),
+		statuses: make(map[string]*domain.TaskStatus),
+	}
+
+	err := service.ExecuteTaskflow()
+
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodeValidationError {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	problems, _ := domainErr.Context["problems"].([]domain.PlanProblem)
+	if len(problems) != 1 || problems[0].Code != domain.PlanProblemUnknownDependency {
+		t.Errorf("unexpected problems %+v", problems)
+	}
+}
@@ -3,11 +3,11 @@ package taskflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"shotgun_code/application/router"
 	"shotgun_code/domain"
-	"strings"
 	"sync"
 	"time"
 
@@ -38,35 +38,53 @@ type Service struct {
 	mu               sync.RWMutex
 	planPath         string
 	statusPath       string
+	projectRoot      string // set by SetProjectRoot; the project of tasks without project_path
 	planner          RouterPlanner
 	routerLlmService RouterLLMService
 	guardrails       domain.GuardrailService
 	repo             domain.TaskflowRepository
 	gitRepo          domain.GitRepository
+	safeMode         *domain.SafeMode
+	eventBus         domain.EventBus
+
+	verifier          ProjectVerifier
+	verificationGates map[string]domain.VerificationGate // overrides of domain.DefaultVerificationGate
+	generateText      TextGenerator                      // generates commit messages
+
+	logsMu   sync.Mutex
+	taskLogs map[string]*taskLogBuffer // created on first write
+	logSeq   int64
+
+	logFileMu    sync.Mutex     // serializes writes to task log files
+	logFileLines map[string]int // lines appended to each log file since it was last compacted
 }
 
-// NewService creates a new taskflow service
-func NewService(log domain.Logger, planner RouterPlanner, routerLlmService RouterLLMService, guardrails domain.GuardrailService, repo domain.TaskflowRepository, gitRepo domain.GitRepository) domain.TaskflowService {
+// NewService creates a new taskflow service. Empty config paths default to tasks/plan.yaml
+// and tasks/status.json in the current directory; the repository is pointed at the same status file
+func NewService(log domain.Logger, config domain.TaskflowConfig, planner RouterPlanner, routerLlmService RouterLLMService, guardrails domain.GuardrailService, repo domain.TaskflowRepository, gitRepo domain.GitRepository) domain.TaskflowService {
+	defaults := domain.NewTaskflowConfig("")
+	if config.PlanPath == "" {
+		config.PlanPath = defaults.PlanPath
+	}
+	if config.StatusPath == "" {
+		config.StatusPath = defaults.StatusPath
+	}
+
 	service := &Service{
 		log:              log,
 		tasks:            make(map[string]domain.Task),
 		statuses:         make(map[string]*domain.TaskStatus),
-		planPath:         "tasks/plan.yaml",
-		statusPath:       "tasks/status.json",
+		planPath:         config.PlanPath,
+		statusPath:       config.StatusPath,
 		planner:          planner,
 		routerLlmService: routerLlmService,
 		guardrails:       guardrails,
 		repo:             repo,
 		gitRepo:          gitRepo,
-		config: domain.TaskflowConfig{
-			AutoStart:     true,
-			MaxConcurrent: 3,
-			RetryAttempts: 3,
-			RetryDelay:    5 * time.Second,
-			Timeout:       30 * time.Minute,
-			EnableLogging: true,
-			EnableMetrics: true,
-		},
+		config:           config,
+	}
+	if repo != nil {
+		repo.SetStatusPath(config.StatusPath)
 	}
 
 	if _, err := service.LoadTasks(); err != nil {
@@ -76,6 +94,46 @@ func NewService(log domain.Logger, planner RouterPlanner, routerLlmService Route
 	return service
 }
 
+// SetProjectRoot points plan.yaml and status.json at the tasks directory of the selected project
+// and reloads tasks. A missing plan is not an error: the project simply has no taskflow yet
+func (s *Service) SetProjectRoot(projectRoot string) error {
+	paths := domain.NewTaskflowConfig(projectRoot)
+
+	s.mu.Lock()
+	s.projectRoot = projectRoot
+	s.planPath = paths.PlanPath
+	s.statusPath = paths.StatusPath
+	s.config.PlanPath = paths.PlanPath
+	s.config.StatusPath = paths.StatusPath
+	s.tasks = make(map[string]domain.Task)
+	s.statuses = make(map[string]*domain.TaskStatus)
+	if s.repo != nil {
+		s.repo.SetStatusPath(paths.StatusPath)
+	}
+	s.mu.Unlock()
+	s.resetTaskLogs()
+
+	if _, err := os.Stat(paths.PlanPath); os.IsNotExist(err) {
+		s.log.Info(fmt.Sprintf("No taskflow plan in %s", projectRoot))
+		return nil
+	}
+	if _, err := s.LoadTasks(); err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+	return nil
+}
+
+// SetSafeMode sets the switch that blocks task execution while safe mode is on
+func (s *Service) SetSafeMode(mode *domain.SafeMode) {
+	s.safeMode = mode
+}
+
+// SetEventBus sets the bus that receives taskflow progress events; without it
+// the UI has to poll task statuses
+func (s *Service) SetEventBus(bus domain.EventBus) {
+	s.eventBus = bus
+}
+
 // GetTaskType returns task type by ID (TaskTypeProvider implementation)
 func (s *Service) GetTaskType(taskID string) (string, error) {
 	s.mu.RLock()
@@ -170,8 +228,6 @@ func (s *Service) GetTaskStatus(taskID string) (*domain.TaskStatus, error) {
 // UpdateTaskStatus updates task status
 func (s *Service) UpdateTaskStatus(taskID string, state domain.TaskState, message string) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	status, exists := s.statuses[taskID]
 	if !exists {
 		status = &domain.TaskStatus{TaskID: taskID}
@@ -180,6 +236,11 @@ func (s *Service) UpdateTaskStatus(taskID string, state domain.TaskState, messag
 
 	status.State = state
 	status.Message = message
+	if task, ok := s.tasks[taskID]; ok {
+		task.State = state
+		task.UpdatedAt = time.Now()
+		s.tasks[taskID] = task
+	}
 
 	switch state {
 	case domain.TaskStateDone, domain.TaskStateFailed, domain.TaskStateBlocked:
@@ -192,7 +253,12 @@ func (s *Service) UpdateTaskStatus(taskID string, state domain.TaskState, messag
 		}
 	}
 
-	if err := s.saveStatuses(); err != nil {
+	event := progressEvent(status)
+	err := s.saveStatuses()
+	s.mu.Unlock()
+
+	s.emitProgress(event)
+	if err != nil {
 		return fmt.Errorf("failed to save statuses: %w", err)
 	}
 
@@ -200,8 +266,13 @@ func (s *Service) UpdateTaskStatus(taskID string, state domain.TaskState, messag
 	return nil
 }
 
-// ExecuteTask executes a task
-func (s *Service) ExecuteTask(taskID string) error {
+// ExecuteTask executes a task. A failed pipeline is retried up to RetryAttempts times,
+// waiting RetryDelay (doubled after each attempt); the task is marked failed only
+// after the last attempt or when ctx is cancelled between attempts
+func (s *Service) ExecuteTask(ctx context.Context, taskID string) error {
+	if err := s.safeMode.Check("executing tasks"); err != nil {
+		return err
+	}
 	s.mu.Lock()
 	task, exists := s.tasks[taskID]
 	if !exists {
@@ -213,9 +284,11 @@ func (s *Service) ExecuteTask(taskID string) error {
 	if err := s.checkDependencies(taskID); err != nil {
 		return err
 	}
-	if err := s.validateWithGuardrails(taskID); err != nil {
-		return err
-	}
+	s.enableEphemeralMode(taskID)
+	defer s.disableEphemeralMode(taskID)
+
+	projectPath := s.taskProjectPath(task)
+	baseline := s.snapshotWorkingTree(projectPath)
 
 	now := time.Now()
 	status := &domain.TaskStatus{
@@ -228,65 +301,166 @@ func (s *Service) ExecuteTask(taskID string) error {
 
 	s.mu.Lock()
 	s.statuses[taskID] = status
+	event := progressEvent(status)
 	s.mu.Unlock()
+	s.emitProgress(event)
+
+	ctx = domain.WithTaskLog(ctx, s.taskLog(taskID))
+	maxAttempts := max(s.config.RetryAttempts, 0) + 1
+	delay := s.config.RetryDelay
+	for attempt := 1; ; attempt++ {
+		s.mu.Lock()
+		status.Attempts = attempt
+		s.mu.Unlock()
+		domain.LogTaskStep(ctx, domain.TaskLogInfo, "", fmt.Sprintf("Attempt %d/%d started", attempt, maxAttempts))
+
+		pipeline, err := s.runPipeline(ctx, task)
+		if err == nil {
+			progress := s.planner.GetPipelineStatus(pipeline)["progress"].(float64)
+			s.mu.Lock()
+			status.Progress = progress
+			s.mu.Unlock()
+			if pipeline.Status == PipelineStatusCompleted {
+				if _, err := s.checkTaskChanges(taskID, projectPath, baseline, task.Budgets); err != nil {
+					return s.failTask(taskID, fmt.Sprintf("Attempt %d/%d rejected: %v", attempt, maxAttempts, err), err)
+				}
+				return s.UpdateTaskStatus(taskID, domain.TaskStateDone, "Task completed successfully via pipeline")
+			}
+			err = fmt.Errorf("pipeline failed: %s", pipeline.Error)
+		}
 
-	s.log.Info(fmt.Sprintf("Creating pipeline for task: %s", taskID))
+		if attempt >= maxAttempts {
+			return s.failTask(taskID, fmt.Sprintf("Attempt %d/%d failed: %v", attempt, maxAttempts, err),
+				fmt.Errorf("task %s failed after %d attempts: %w", taskID, attempt, err))
+		}
 
-	pipeline, err := s.planner.CreatePipeline(context.Background(), task, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create pipeline: %w", err)
-	}
+		message := fmt.Sprintf("Attempt %d/%d failed: %v; retrying in %s", attempt, maxAttempts, err, delay)
+		s.log.Warning(fmt.Sprintf("Task %s: %s", taskID, message))
+		if updateErr := s.UpdateTaskStatus(taskID, domain.TaskStateRunning, message); updateErr != nil {
+			s.log.Error(fmt.Sprintf("Failed to update task status: %v", updateErr))
+		}
 
-	s.log.Info(fmt.Sprintf("Executing pipeline for task: %s", taskID))
-	if err := s.planner.ExecutePipeline(context.Background(), pipeline); err != nil {
-		return fmt.Errorf("failed to execute pipeline: %w", err)
+		select {
+		case <-ctx.Done():
+			return s.failTask(taskID, fmt.Sprintf("Cancelled after attempt %d/%d", attempt, maxAttempts), ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
 	}
+}
 
-	pipelineStatus := s.planner.GetPipelineStatus(pipeline)
-	progress := pipelineStatus["progress"].(float64)
-	status.Progress = progress
-	status.Message = fmt.Sprintf("Pipeline completed with %d steps", len(pipeline.Steps))
-
-	if pipeline.Status == PipelineStatusCompleted {
-		status.State = domain.TaskStateDone
-		status.Message = "Task completed successfully via pipeline"
-	} else {
-		status.State = domain.TaskStateFailed
-		status.Message = fmt.Sprintf("Pipeline failed: %s", pipeline.Error)
+// runPipeline creates and executes a pipeline for the task
+func (s *Service) runPipeline(ctx context.Context, task domain.Task) (*router.TaskPipeline, error) {
+	s.log.Info(fmt.Sprintf("Creating pipeline for task: %s", task.ID))
+	pipeline, err := s.planner.CreatePipeline(ctx, task, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipeline: %w", err)
 	}
 
-	if s.guardrails != nil && (strings.Contains(taskID, "scaffold") || strings.Contains(taskID, "deps_fix")) {
-		s.guardrails.DisableEphemeralMode()
+	s.log.Info(fmt.Sprintf("Executing pipeline for task: %s", task.ID))
+	if err := s.planner.ExecutePipeline(ctx, pipeline); err != nil {
+		return nil, fmt.Errorf("failed to execute pipeline: %w", err)
 	}
+	return pipeline, nil
+}
 
-	return s.UpdateTaskStatus(taskID, status.State, status.Message)
+// failTask marks the task failed and returns cause
+func (s *Service) failTask(taskID, message string, cause error) error {
+	if err := s.UpdateTaskStatus(taskID, domain.TaskStateFailed, message); err != nil {
+		s.log.Error(fmt.Sprintf("Failed to update task status: %v", err))
+	}
+	return cause
 }
 
-// ExecuteTaskflow executes the entire taskflow
+// ExecuteTaskflow executes the entire taskflow, running up to MaxConcurrent ready
+// tasks at once and starting newly unblocked tasks as soon as their dependencies
+// finish. Tasks of the same project run one at a time, since each measures and
+// reverts its changes in the shared working tree, and a task in guardrail
+// ephemeral mode runs alone. With FailFast the first failure cancels the
+// running tasks and nothing new is started; otherwise independent branches go
+// on and only the dependents of failed tasks are left undone
 func (s *Service) ExecuteTaskflow() error {
+	if err := s.safeMode.Check("executing the taskflow"); err != nil {
+		return err
+	}
+
+	validation, err := s.ValidatePlan(s.planPath)
+	if err != nil {
+		return fmt.Errorf("failed to validate plan: %w", err)
+	}
+	if !validation.Valid {
+		return planProblemsError(validation)
+	}
 	s.log.Info("Starting taskflow execution")
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type taskResult struct {
+		taskID  string
+		project string
+		err     error
+	}
+	results := make(chan taskResult)
+	workers := max(s.config.MaxConcurrent, 1)
+	started := make(map[string]bool)
+	busyProjects := make(map[string]bool)
+	running := 0
+	exclusive := false // an ephemeral task is running
+	var failures []error
+
 	for {
-		readyTasks, err := s.GetReadyTasks()
-		if err != nil {
-			return fmt.Errorf("failed to get ready tasks: %w", err)
+		if ctx.Err() == nil {
+			readyTasks, err := s.GetReadyTasks()
+			if err != nil {
+				failures = append(failures, fmt.Errorf("failed to get ready tasks: %w", err))
+				cancel()
+			}
+			for _, task := range readyTasks {
+				if running >= workers || exclusive {
+					break
+				}
+				project := s.taskProjectPath(task)
+				if started[task.ID] || busyProjects[project] {
+					continue
+				}
+				if isEphemeralTask(task.ID) {
+					// Ephemeral mode relaxes the guardrails for every running task
+					if running > 0 {
+						break
+					}
+					exclusive = true
+				}
+				started[task.ID] = true
+				busyProjects[project] = true
+				running++
+				go func(taskID, project string) {
+					results <- taskResult{taskID: taskID, project: project, err: s.ExecuteTask(ctx, taskID)}
+				}(task.ID, project)
+			}
 		}
 
-		if len(readyTasks) == 0 {
-			s.log.Info("No more tasks to execute")
+		if running == 0 {
 			break
 		}
+		result := <-results
+		running--
+		exclusive = false
+		delete(busyProjects, result.project)
+		if result.err == nil {
+			continue
+		}
 
-		for _, task := range readyTasks {
-			if err := s.ExecuteTask(task.ID); err != nil {
-				s.log.Error(fmt.Sprintf("Failed to execute task %s: %v", task.ID, err))
-				if err := s.UpdateTaskStatus(task.ID, domain.TaskStateFailed, err.Error()); err != nil {
-					s.log.Error(fmt.Sprintf("Failed to update task status: %v", err))
-				}
-			}
+		s.log.Error(fmt.Sprintf("Failed to execute task %s: %v", result.taskID, result.err))
+		failures = append(failures, fmt.Errorf("task %s: %w", result.taskID, result.err))
+		if s.config.FailFast {
+			cancel()
 		}
 	}
 
+	if len(failures) > 0 {
+		return fmt.Errorf("taskflow execution failed: %w", errors.Join(failures...))
+	}
 	s.log.Info("Taskflow execution completed")
 	return nil
 }
@@ -391,7 +565,9 @@ func (s *Service) ResetTaskflow() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for taskID := range s.tasks {
+	for taskID, task := range s.tasks {
+		task.State = domain.TaskStateTodo
+		s.tasks[taskID] = task
 		s.statuses[taskID] = &domain.TaskStatus{
 			TaskID: taskID,
 			State:  domain.TaskStateTodo,
@@ -462,26 +638,65 @@ func (s *Service) checkDependencies(taskID string) error {
 	return nil
 }
 
-func (s *Service) validateWithGuardrails(taskID string) error {
-	if s.guardrails == nil {
-		return nil
-	}
+// isEphemeralTask reports whether a task may run in guardrail ephemeral mode
+func isEphemeralTask(taskID string) bool {
+	taskType := domain.TaskTypeFromID(taskID)
+	return taskType == domain.TaskTypeScaffold || taskType == domain.TaskTypeDepsFix
+}
 
+func (s *Service) enableEphemeralMode(taskID string) {
+	if s.guardrails == nil || !isEphemeralTask(taskID) {
+		return
+	}
 	taskType := domain.TaskTypeFromID(taskID).String()
-	if taskType == "scaffold" || taskType == "deps_fix" {
-		if err := s.guardrails.EnableEphemeralMode(taskID, taskType, 5*time.Minute); err != nil {
-			s.log.Warning(fmt.Sprintf("Failed to enable ephemeral mode for task %s: %v", taskID, err))
+	if err := s.guardrails.EnableEphemeralMode(taskID, taskType, 5*time.Minute); err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to enable ephemeral mode for task %s: %v", taskID, err))
+	}
+}
+
+func (s *Service) disableEphemeralMode(taskID string) {
+	if s.guardrails != nil && isEphemeralTask(taskID) {
+		s.guardrails.DisableEphemeralMode()
+	}
+}
+
+// taskProjectPath returns the project a task changes: its project_path
+// metadata, else the root set by SetProjectRoot, or "" when neither is known
+func (s *Service) taskProjectPath(task domain.Task) string {
+	if path, _ := task.Metadata["project_path"].(string); path != "" {
+		return path
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.projectRoot
+}
+
+// validateWithGuardrails checks the files and lines changed by a task against
+// the guardrail policies and the task's own budgets. A rejection is returned
+// as a GUARDRAIL_VIOLATION domain error carrying the violations.
+func (s *Service) validateWithGuardrails(taskID string, budgets domain.TaskBudgets, files []string, linesChanged int64) error {
+	result := &domain.TaskValidationResult{TaskID: taskID, Valid: true, Timestamp: time.Now()}
+	if s.guardrails != nil {
+		validationResult, err := s.guardrails.ValidateTask(taskID, files, linesChanged)
+		if err != nil && validationResult == nil {
+			s.log.Error(fmt.Sprintf("Guardrail validation failed for task %s: %v", taskID, err))
+			return fmt.Errorf("guardrail validation failed: %w", err)
+		}
+		result = validationResult
+		if err != nil && result.Error == "" {
+			result.Error = err.Error()
 		}
 	}
 
-	validationResult, err := s.guardrails.ValidateTask(taskID, []string{}, 0)
-	if err != nil {
-		s.log.Error(fmt.Sprintf("Guardrail validation failed for task %s: %v", taskID, err))
-		return fmt.Errorf("guardrail validation failed: %w", err)
+	if violations := taskBudgetViolations(budgets, len(files), linesChanged); len(violations) > 0 {
+		result.Valid = false
+		result.BudgetViolations = append(result.BudgetViolations, violations...)
 	}
-	if !validationResult.Valid {
-		s.log.Error(fmt.Sprintf("Task %s failed guardrail validation: %s", taskID, validationResult.Error))
-		return fmt.Errorf("task validation failed: %s", validationResult.Error)
+
+	if !result.Valid {
+		violationErr := domain.NewGuardrailViolationError(result)
+		s.log.Error(violationErr.Error())
+		return violationErr
 	}
 	return nil
 }
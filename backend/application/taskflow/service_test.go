@@ -1,7 +1,14 @@
 package taskflow
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"shotgun_code/domain"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -139,3 +146,482 @@ func TestCalculateEstimatedTimeRemaining_MaxCapped(t *testing.T) {
 		t.Errorf("expected result capped at %d, got %d", MaxEstimatedTimeSeconds, result)
 	}
 }
+
+func TestSetProjectRoot_LoadsPlanFromProject(t *testing.T) {
+	projectRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectRoot, "tasks"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectRoot, domain.DefaultTaskflowPlanPath), []byte("tasks:\n  - id: a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{
+		log:      &domain.NoopLogger{},
+		tasks:    map[string]domain.Task{"stale": {ID: "stale"}},
+		statuses: make(map[string]*domain.TaskStatus),
+	}
+
+	if err := service.SetProjectRoot(projectRoot); err != nil {
+		t.Fatalf("SetProjectRoot failed: %v", err)
+	}
+
+	if service.planPath != filepath.Join(projectRoot, "tasks", "plan.yaml") {
+		t.Errorf("unexpected plan path %s", service.planPath)
+	}
+	if service.statusPath != filepath.Join(projectRoot, "tasks", "status.json") {
+		t.Errorf("unexpected status path %s", service.statusPath)
+	}
+	if _, ok := service.tasks["a"]; !ok || len(service.tasks) != 1 {
+		t.Errorf("expected tasks from the project plan, got %+v", service.tasks)
+	}
+}
+
+// flakyPlanner fails the given number of pipeline executions before succeeding
+type flakyPlanner struct {
+	failures int
+	calls    int
+}
+
+func (p *flakyPlanner) CreatePipeline(_ context.Context, task domain.Task, _ *PipelinePolicy) (*TaskPipeline, error) {
+	return &TaskPipeline{TaskID: task.ID}, nil
+}
+
+func (p *flakyPlanner) ExecutePipeline(ctx context.Context, pipeline *TaskPipeline) error {
+	p.calls++
+	domain.LogTaskStep(ctx, domain.TaskLogInfo, "compile", "Step compile started")
+	if p.calls <= p.failures {
+		domain.LogTaskStep(ctx, domain.TaskLogError, "compile", "Step compile failed")
+		pipeline.Status = PipelineStatusFailed
+		pipeline.Error = "build failed"
+		return nil
+	}
+	pipeline.Status = PipelineStatusCompleted
+	return nil
+}
+
+func (p *flakyPlanner) GetPipelineStatus(*TaskPipeline) map[string]any {
+	return map[string]any{"progress": 1.0}
+}
+
+func newRetryTestService(planner RouterPlanner, retryAttempts int, retryDelay time.Duration) *Service {
+	return &Service{
+		log:      &domain.NoopLogger{},
+		config:   domain.TaskflowConfig{RetryAttempts: retryAttempts, RetryDelay: retryDelay},
+		tasks:    map[string]domain.Task{"build": {ID: "build"}},
+		statuses: make(map[string]*domain.TaskStatus),
+		planner:  planner,
+	}
+}
+
+func TestExecuteTask_RetriesUntilSuccess(t *testing.T) {
+	planner := &flakyPlanner{failures: 2}
+	service := newRetryTestService(planner, 3, time.Millisecond)
+
+	if err := service.ExecuteTask(context.Background(), "build"); err != nil {
+		t.Fatalf("ExecuteTask failed: %v", err)
+	}
+	status, _ := service.GetTaskStatus("build")
+	if status.State != domain.TaskStateDone || status.Attempts != 3 || planner.calls != 3 {
+		t.Errorf("expected done after 3 attempts, got %+v (calls %d)", status, planner.calls)
+	}
+}
+
+func TestExecuteTask_FailsAfterExhaustingRetries(t *testing.T) {
+	planner := &flakyPlanner{failures: 5}
+	service := newRetryTestService(planner, 1, time.Millisecond)
+
+	if err := service.ExecuteTask(context.Background(), "build"); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	status, _ := service.GetTaskStatus("build")
+	if status.State != domain.TaskStateFailed || status.Attempts != 2 || planner.calls != 2 {
+		t.Errorf("expected failed after 2 attempts, got %+v (calls %d)", status, planner.calls)
+	}
+}
+
+func TestExecuteTask_StopsRetryingWhenCancelled(t *testing.T) {
+	planner := &flakyPlanner{failures: 5}
+	service := newRetryTestService(planner, 3, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := service.ExecuteTask(ctx, "build")
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	status, _ := service.GetTaskStatus("build")
+	if status.State != domain.TaskStateFailed || planner.calls != 1 {
+		t.Errorf("expected failed after 1 attempt, got %+v (calls %d)", status, planner.calls)
+	}
+}
+
+// recordingBus records emitted taskflow events
+type recordingBus struct {
+	mu     sync.Mutex
+	names  []string
+	events []domain.TaskflowProgressEvent
+}
+
+func (b *recordingBus) Emit(eventName string, data ...interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.names = append(b.names, eventName)
+	b.events = append(b.events, data[0].(domain.TaskflowProgressEvent))
+}
+
+func TestExecuteTask_EmitsProgressEvents(t *testing.T) {
+	bus := &recordingBus{}
+	service := newRetryTestService(&flakyPlanner{failures: 1}, 1, time.Millisecond)
+	service.SetEventBus(bus)
+
+	if err := service.ExecuteTask(context.Background(), "build"); err != nil {
+		t.Fatalf("ExecuteTask failed: %v", err)
+	}
+
+	expected := []string{domain.EventTaskflowProgress, domain.EventTaskflowProgress, domain.EventTaskflowProgress, domain.EventTaskflowCompleted}
+	if strings.Join(bus.names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected events %v, got %v", expected, bus.names)
+	}
+	if start := bus.events[0]; start.TaskID != "build" || start.Progress != 0 {
+		t.Errorf("unexpected start event %+v", start)
+	}
+	if retry := bus.events[1]; retry.State != domain.TaskStateRunning || retry.Attempts != 1 || !strings.Contains(retry.Message, "retrying") {
+		t.Errorf("unexpected retry event %+v", retry)
+	}
+	if done := bus.events[3]; done.TaskID != "build" || done.State != domain.TaskStateDone || done.Attempts != 2 || done.Progress != 1.0 {
+		t.Errorf("unexpected completion event %+v", done)
+	}
+}
+
+func TestAutonomousTaskFailure_EmitsFailedEventWithError(t *testing.T) {
+	bus := &recordingBus{}
+	service := newRetryTestService(nil, 0, 0)
+	service.SetEventBus(bus)
+
+	service.notifyTaskFailure("autonomous_1", "planner unavailable")
+	service.updateAutonomousTaskStatus("autonomous_1", "failed", "planner unavailable", 100.0)
+
+	if len(bus.names) != 2 || bus.names[1] != domain.EventTaskflowFailed {
+		t.Fatalf("expected progress and failed events, got %v", bus.names)
+	}
+	if failed := bus.events[1]; failed.Error != "planner unavailable" || failed.State != domain.TaskStateFailed {
+		t.Errorf("unexpected failed event %+v", failed)
+	}
+}
+
+func TestGetTaskLogs_ReturnsStepLogsInOrder(t *testing.T) {
+	service := newRetryTestService(&flakyPlanner{failures: 1}, 1, time.Millisecond)
+
+	if err := service.ExecuteTask(context.Background(), "build"); err != nil {
+		t.Fatalf("ExecuteTask failed: %v", err)
+	}
+	logs, err := service.GetTaskLogs(context.Background(), "build")
+	if err != nil {
+		t.Fatalf("GetTaskLogs failed: %v", err)
+	}
+
+	var messages []string
+	for i, entry := range logs {
+		if i > 0 && entry.Timestamp.Before(logs[i-1].Timestamp) {
+			t.Errorf("logs not ordered by timestamp at %d", i)
+		}
+		messages = append(messages, entry.Message)
+	}
+	expected := []string{
+		"Task todo: Starting task execution",
+		"Attempt 1/2 started",
+		"Step compile started",
+		"Step compile failed",
+		"Task running: Attempt 1/2 failed: pipeline failed: build failed; retrying in 1ms",
+		"Attempt 2/2 started",
+		"Step compile started",
+		"Task done: Task completed successfully via pipeline",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected log:\n%s", strings.Join(messages, "\n"))
+	}
+	if failed := logs[3]; failed.Level != domain.TaskLogError || failed.Metadata["stepId"] != "compile" {
+		t.Errorf("step error not tagged with the step: %+v", failed)
+	}
+}
+
+func TestGetTaskLogs_KeepsLastEntries(t *testing.T) {
+	service := newRetryTestService(nil, 0, 0)
+	service.config.MaxLogEntries = 3
+	service.statuses["build"] = &domain.TaskStatus{TaskID: "build"}
+
+	for i := 1; i <= 5; i++ {
+		service.appendTaskLog("build", domain.TaskLogInfo, "", fmt.Sprintf("line %d", i))
+	}
+
+	logs, err := service.GetTaskLogs(context.Background(), "build")
+	if err != nil {
+		t.Fatalf("GetTaskLogs failed: %v", err)
+	}
+	if len(logs) != 3 || logs[0].Message != "line 3" || logs[2].Message != "line 5" {
+		t.Errorf("expected the last 3 lines, got %+v", logs)
+	}
+}
+
+func TestGetTaskLogs_ReadsLogFile(t *testing.T) {
+	logDir := t.TempDir()
+	writer := newRetryTestService(nil, 0, 0)
+	writer.config.LogDir = logDir
+	writer.appendTaskLog("build", domain.TaskLogInfo, "compile", "Step compile started")
+
+	// A fresh service, as after a restart, reads the log back from the file
+	reader := newRetryTestService(nil, 0, 0)
+	reader.config.LogDir = logDir
+	reader.statuses["build"] = &domain.TaskStatus{TaskID: "build"}
+
+	logs, err := reader.GetTaskLogs(context.Background(), "build")
+	if err != nil {
+		t.Fatalf("GetTaskLogs failed: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Message != "Step compile started" || logs[0].Metadata["stepId"] != "compile" {
+		t.Errorf("unexpected logs from file: %+v", logs)
+	}
+}
+
+func TestAppendTaskLog_CapsLogFile(t *testing.T) {
+	logDir := t.TempDir()
+	service := newRetryTestService(nil, 0, 0)
+	service.config.LogDir = logDir
+	service.config.MaxLogEntries = 3
+
+	for i := 1; i <= 10; i++ {
+		service.appendTaskLog("build", domain.TaskLogInfo, "", fmt.Sprintf("line %d", i))
+	}
+
+	entries, err := readTaskLogFile(filepath.Join(logDir, "build.log"), 100)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if len(entries) >= 6 || entries[len(entries)-1].Message != "line 10" {
+		t.Errorf("expected the log file to be cut down to the last lines, got %d entries", len(entries))
+	}
+}
+
+// scheduledPlanner runs each task through run and records how many pipelines overlap
+type scheduledPlanner struct {
+	mu         sync.Mutex
+	running    int
+	maxRunning int
+	events     []string
+	run        func(ctx context.Context, taskID string) error
+}
+
+func (p *scheduledPlanner) CreatePipeline(_ context.Context, task domain.Task, _ *PipelinePolicy) (*TaskPipeline, error) {
+	return &TaskPipeline{TaskID: task.ID}, nil
+}
+
+func (p *scheduledPlanner) ExecutePipeline(ctx context.Context, pipeline *TaskPipeline) error {
+	p.mu.Lock()
+	p.running++
+	p.maxRunning = max(p.maxRunning, p.running)
+	p.events = append(p.events, "start:"+pipeline.TaskID)
+	p.mu.Unlock()
+
+	err := p.run(ctx, pipeline.TaskID)
+
+	p.mu.Lock()
+	p.running--
+	p.events = append(p.events, "end:"+pipeline.TaskID)
+	p.mu.Unlock()
+	if err != nil {
+		pipeline.Status = PipelineStatusFailed
+		pipeline.Error = err.Error()
+		return nil
+	}
+	pipeline.Status = PipelineStatusCompleted
+	return nil
+}
+
+func (p *scheduledPlanner) GetPipelineStatus(*TaskPipeline) map[string]any {
+	return map[string]any{"progress": 1.0}
+}
+
+func (p *scheduledPlanner) index(event string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, e := range p.events {
+		if e == event {
+			return i
+		}
+	}
+	return -1
+}
+
+// newTaskflowTestService loads the plan with every task in a project of its
+// own, so the scheduler may run independent tasks at once
+func newTaskflowTestService(t *testing.T, plan string, planner RouterPlanner, maxConcurrent int, failFast bool) *Service {
+	t.Helper()
+	service := &Service{
+		log:      &domain.NoopLogger{},
+		config:   domain.TaskflowConfig{MaxConcurrent: maxConcurrent, FailFast: failFast},
+		planPath: writePlan(t, plan),
+		tasks:    make(map[string]domain.Task),
+		statuses: make(map[string]*domain.TaskStatus),
+		planner:  planner,
+	}
+	if _, err := service.LoadTasks(); err != nil {
+		t.Fatal(err)
+	}
+	projects := t.TempDir()
+	for id, task := range service.tasks {
+		task.Metadata["project_path"] = filepath.Join(projects, id)
+		service.tasks[id] = task
+	}
+	return service
+}
+
+func taskState(service *Service, taskID string) domain.TaskState {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+	return service.tasks[taskID].State
+}
+
+func TestExecuteTaskflow_RunsReadyTasksConcurrently(t *testing.T) {
+	planner := &scheduledPlanner{run: func(context.Context, string) error {
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	}}
+	plan := "tasks:\n  - id: a\n  - id: b\n  - id: c\n  - id: d\n    dependsOn: [a]\n"
+	service := newTaskflowTestService(t, plan, planner, 2, false)
+
+	if err := service.ExecuteTaskflow(); err != nil {
+		t.Fatalf("ExecuteTaskflow failed: %v", err)
+	}
+
+	if planner.maxRunning != 2 {
+		t.Errorf("expected 2 tasks to run at once, got %d", planner.maxRunning)
+	}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if state := taskState(service, id); state != domain.TaskStateDone {
+			t.Errorf("expected task %s done, got %s", id, state)
+		}
+	}
+	if planner.index("start:d") < planner.index("end:a") {
+		t.Errorf("task d started before its dependency finished: %v", planner.events)
+	}
+}
+
+func TestExecuteTaskflow_ContinuesIndependentBranches(t *testing.T) {
+	planner := &scheduledPlanner{run: func(_ context.Context, taskID string) error {
+		if taskID == "a" {
+			return errors.New("build failed")
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}}
+	plan := "tasks:\n  - id: a\n  - id: b\n  - id: after-a\n    dependsOn: [a]\n  - id: after-b\n    dependsOn: [b]\n"
+	service := newTaskflowTestService(t, plan, planner, 2, false)
+
+	if err := service.ExecuteTaskflow(); err == nil || !strings.Contains(err.Error(), "task a") {
+		t.Fatalf("expected the failure of task a, got %v", err)
+	}
+
+	if state := taskState(service, "after-b"); state != domain.TaskStateDone {
+		t.Errorf("expected the independent branch to finish, got %s", state)
+	}
+	if state := taskState(service, "after-a"); state != domain.TaskStateTodo || planner.index("start:after-a") != -1 {
+		t.Errorf("expected the dependent of a failed task not to run, got %s", state)
+	}
+}
+
+func TestExecuteTaskflow_FailFastCancelsRunningTasks(t *testing.T) {
+	planner := &scheduledPlanner{run: func(ctx context.Context, taskID string) error {
+		if taskID == "a" {
+			return errors.New("build failed")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	plan := "tasks:\n  - id: a\n  - id: b\n  - id: after-b\n    dependsOn: [b]\n"
+	service := newTaskflowTestService(t, plan, planner, 2, true)
+
+	done := make(chan error, 1)
+	go func() { done <- service.ExecuteTaskflow() }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected ExecuteTaskflow to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("running task was not cancelled")
+	}
+
+	if state := taskState(service, "b"); state != domain.TaskStateFailed {
+		t.Errorf("expected the cancelled task to fail, got %s", state)
+	}
+	if planner.index("start:after-b") != -1 {
+		t.Errorf("expected no new tasks after the first failure: %v", planner.events)
+	}
+}
+
+func TestExecuteTaskflow_RunsTasksOfOneProjectOneAtATime(t *testing.T) {
+	projectPath := t.TempDir()
+	committed := map[string]string{"main.go": "package main\n"}
+	if err := os.WriteFile(filepath.Join(projectPath, "main.go"), []byte(committed["main.go"]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plan := "tasks:\n  - id: a\n    budgets:\n      maxFiles: 1\n  - id: b\n    budgets:\n      maxFiles: 1\n"
+	if err := os.MkdirAll(filepath.Join(projectPath, "tasks"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectPath, "tasks", "plan.yaml"), []byte(plan), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Each task edits its own file; run together, each would count the other's
+	// edit against its budget and revert it
+	planner := &scheduledPlanner{run: func(_ context.Context, taskID string) error {
+		time.Sleep(20 * time.Millisecond)
+		return os.WriteFile(filepath.Join(projectPath, taskID+".go"), []byte("package main\n"), 0o644)
+	}}
+	service := &Service{
+		log:      &domain.NoopLogger{},
+		config:   domain.TaskflowConfig{MaxConcurrent: 2},
+		tasks:    make(map[string]domain.Task),
+		statuses: make(map[string]*domain.TaskStatus),
+		planner:  planner,
+		gitRepo:  &workingTreeRepo{committed: committed},
+	}
+	if err := service.SetProjectRoot(projectPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.ExecuteTaskflow(); err != nil {
+		t.Fatalf("ExecuteTaskflow failed: %v", err)
+	}
+
+	if planner.maxRunning != 1 {
+		t.Errorf("expected tasks of one project to run one at a time, got %d at once", planner.maxRunning)
+	}
+	for _, id := range []string{"a", "b"} {
+		if state := taskState(service, id); state != domain.TaskStateDone {
+			t.Errorf("expected task %s done, got %s", id, state)
+		}
+		if _, err := os.Stat(filepath.Join(projectPath, id+".go")); err != nil {
+			t.Errorf("edit of task %s was lost: %v", id, err)
+		}
+	}
+}
+
+func TestExecuteTaskflow_RunsEphemeralTasksAlone(t *testing.T) {
+	planner := &scheduledPlanner{run: func(context.Context, string) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}}
+	plan := "tasks:\n  - id: a\n  - id: b\n  - id: scaffold_api\n"
+	service := newTaskflowTestService(t, plan, planner, 3, false)
+
+	if err := service.ExecuteTaskflow(); err != nil {
+		t.Fatalf("ExecuteTaskflow failed: %v", err)
+	}
+
+	start, end := planner.index("start:scaffold_api"), planner.index("end:scaffold_api")
+	if start == -1 || end != start+1 {
+		t.Errorf("expected the ephemeral task to run alone: %v", planner.events)
+	}
+}
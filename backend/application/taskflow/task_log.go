@@ -0,0 +1,228 @@
+package taskflow
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"sort"
+	"time"
+)
+
+// taskLogBuffer keeps the last cap entries of a task log
+type taskLogBuffer struct {
+	entries []domain.LogEntry
+	start   int // index of the oldest entry once the buffer is full
+}
+
+func newTaskLogBuffer(capacity int) *taskLogBuffer {
+	return &taskLogBuffer{entries: make([]domain.LogEntry, 0, capacity)}
+}
+
+func (b *taskLogBuffer) add(entry domain.LogEntry) {
+	if len(b.entries) < cap(b.entries) {
+		b.entries = append(b.entries, entry)
+		return
+	}
+	b.entries[b.start] = entry
+	b.start = (b.start + 1) % len(b.entries)
+}
+
+// snapshot returns the entries oldest first
+func (b *taskLogBuffer) snapshot() []domain.LogEntry {
+	out := make([]domain.LogEntry, 0, len(b.entries))
+	out = append(out, b.entries[b.start:]...)
+	return append(out, b.entries[:b.start]...)
+}
+
+// taskLog returns the callback pipeline steps of taskID write their log lines to
+func (s *Service) taskLog(taskID string) domain.TaskLogFunc {
+	return func(level, stepID, message string) {
+		s.appendTaskLog(taskID, level, stepID, message)
+	}
+}
+
+// appendTaskLog records a line in the task's in-memory log and, when LogDir is
+// configured, appends it to the task's log file
+func (s *Service) appendTaskLog(taskID, level, stepID, message string) {
+	entry := s.recordTaskLog(taskID, level, stepID, message)
+	if s.config.LogDir != "" {
+		s.writeTaskLogFile(taskID, entry)
+	}
+}
+
+// recordTaskLog adds a line to the task's in-memory log
+func (s *Service) recordTaskLog(taskID, level, stepID, message string) domain.LogEntry {
+	s.logsMu.Lock()
+	defer s.logsMu.Unlock()
+
+	s.logSeq++
+	entry := domain.LogEntry{
+		ID:        fmt.Sprintf("%s-%d", taskID, s.logSeq),
+		TaskID:    taskID,
+		Level:     level,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+	if stepID != "" {
+		entry.Metadata = map[string]interface{}{"stepId": stepID}
+	}
+
+	if s.taskLogs == nil {
+		s.taskLogs = make(map[string]*taskLogBuffer)
+	}
+	buf, ok := s.taskLogs[taskID]
+	if !ok {
+		buf = newTaskLogBuffer(s.maxLogEntries())
+		s.taskLogs[taskID] = buf
+	}
+	buf.add(entry)
+	return entry
+}
+
+// writeTaskLogFile appends entry to the task's log file without holding logsMu,
+// so readers of the in-memory log don't wait for the disk. After twice
+// maxLogEntries appended lines the file is cut down to the last maxLogEntries
+func (s *Service) writeTaskLogFile(taskID string, entry domain.LogEntry) {
+	s.logFileMu.Lock()
+	defer s.logFileMu.Unlock()
+
+	path := s.taskLogPath(taskID)
+	if err := appendTaskLogFile(path, entry); err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to write log of task %s: %v", taskID, err))
+		return
+	}
+	if s.logFileLines == nil {
+		s.logFileLines = make(map[string]int)
+	}
+	s.logFileLines[taskID]++
+	if s.logFileLines[taskID] < 2*s.maxLogEntries() {
+		return
+	}
+	if err := compactTaskLogFile(path, s.maxLogEntries()); err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to compact log of task %s: %v", taskID, err))
+		return
+	}
+	s.logFileLines[taskID] = s.maxLogEntries()
+}
+
+// taskLogEntries returns the log of a task ordered by timestamp. Without an
+// in-memory log, e.g. after a restart, the tail of the log file is used
+func (s *Service) taskLogEntries(taskID string) ([]domain.LogEntry, error) {
+	s.logsMu.Lock()
+	buf, ok := s.taskLogs[taskID]
+	var entries []domain.LogEntry
+	if ok {
+		entries = buf.snapshot()
+	}
+	s.logsMu.Unlock()
+
+	if !ok && s.config.LogDir != "" {
+		var err error
+		if entries, err = readTaskLogFile(s.taskLogPath(taskID), s.maxLogEntries()); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
+}
+
+// resetTaskLogs drops the in-memory logs, e.g. when another project is opened
+func (s *Service) resetTaskLogs() {
+	s.logsMu.Lock()
+	s.taskLogs = nil
+	s.logsMu.Unlock()
+
+	s.logFileMu.Lock()
+	s.logFileLines = nil
+	s.logFileMu.Unlock()
+}
+
+func (s *Service) maxLogEntries() int {
+	if s.config.MaxLogEntries > 0 {
+		return s.config.MaxLogEntries
+	}
+	return domain.DefaultMaxTaskLogEntries
+}
+
+func (s *Service) taskLogPath(taskID string) string {
+	return filepath.Join(s.config.LogDir, filepath.Base(taskID)+".log")
+}
+
+func appendTaskLogFile(path string, entry domain.LogEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(entry)
+}
+
+// compactTaskLogFile rewrites a log file with its last limit entries
+func compactTaskLogFile(path string, limit int) error {
+	entries, err := readTaskLogFile(path, limit)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := writeTaskLogEntries(tmp, entries); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func writeTaskLogEntries(path string, entries []domain.LogEntry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readTaskLogFile reads the last limit entries of a log file; a missing file is an empty log
+func readTaskLogFile(path string, limit int) ([]domain.LogEntry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task log: %w", err)
+	}
+	defer f.Close()
+
+	buf := newTaskLogBuffer(limit)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry domain.LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		buf.add(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read task log: %w", err)
+	}
+	return buf.snapshot(), nil
+}
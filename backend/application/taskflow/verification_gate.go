@@ -0,0 +1,116 @@
+package taskflow
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"shotgun_code/application/router"
+	"shotgun_code/domain"
+	"strings"
+)
+
+// ProjectVerifier runs the project checks of the verification gate
+type ProjectVerifier interface {
+	ValidateProjectSummary(ctx context.Context, config *domain.ValidationSummaryConfig) (*domain.ValidationSummary, error)
+}
+
+// CoverageReporter is implemented by verifiers that can measure test coverage
+type CoverageReporter interface {
+	GetTestCoverage(ctx context.Context, projectPath string) (*domain.TestCoverage, error)
+}
+
+// SetVerifier sets the checks autonomous tasks must pass before they complete;
+// without it tasks complete as soon as their pipeline does
+func (s *Service) SetVerifier(verifier ProjectVerifier) {
+	s.verifier = verifier
+}
+
+// SetVerificationGate overrides the checks required of tasks with an SLA policy
+func (s *Service) SetVerificationGate(slaPolicy string, gate domain.VerificationGate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.verificationGates == nil {
+		s.verificationGates = make(map[string]domain.VerificationGate)
+	}
+	s.verificationGates[slaPolicy] = gate
+}
+
+// SetVerificationGates replaces the overrides of all SLA policies, usually with
+// those from settings; policies left out use their default checks
+func (s *Service) SetVerificationGates(gates map[string]domain.VerificationGate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.verificationGates = maps.Clone(gates)
+}
+
+func (s *Service) verificationGate(slaPolicy string) domain.VerificationGate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if gate, ok := s.verificationGates[slaPolicy]; ok {
+		return gate
+	}
+	return domain.DefaultVerificationGate(slaPolicy)
+}
+
+// runVerificationGate runs the checks of the task's SLA policy on the project
+// and returns the failed ones. Tests are limited to the changed files.
+func (s *Service) runVerificationGate(ctx context.Context, request domain.AutonomousTaskRequest, changedFiles []string, status *domain.AutonomousTaskStatus) ([]string, error) {
+	if s.verifier == nil {
+		return nil, nil
+	}
+	gate := s.verificationGate(request.SlaPolicy)
+	s.updateAutonomousTaskStatus(status.TaskId, "running", fmt.Sprintf("Verifying changes: %s...", strings.Join(gate.Categories, ", ")), 90.0)
+
+	summary, err := s.verifier.ValidateProjectSummary(ctx, &domain.ValidationSummaryConfig{
+		ProjectPath:  request.ProjectPath,
+		Categories:   gate.Categories,
+		ChangedFiles: changedFiles,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("verification gate failed to run: %w", err)
+	}
+
+	var failures []string
+	for _, category := range summary.Categories {
+		if category.Status == domain.ValidationStatusFailed {
+			failures = append(failures, fmt.Sprintf("%s: %s", category.Category, category.Message))
+		}
+	}
+	if failure := s.checkCoverage(ctx, request.ProjectPath, gate.MinCoverage, status.TaskId); failure != "" {
+		failures = append(failures, failure)
+	}
+	return failures, nil
+}
+
+// checkCoverage returns a failure if test coverage is below minCoverage.
+// A required coverage that can't be measured fails the gate too.
+func (s *Service) checkCoverage(ctx context.Context, projectPath string, minCoverage float64, taskID string) string {
+	if minCoverage <= 0 {
+		return ""
+	}
+	reporter, ok := s.verifier.(CoverageReporter)
+	if !ok {
+		return fmt.Sprintf("coverage: %.1f%% is required but the verifier can't measure coverage", minCoverage)
+	}
+	coverage, err := reporter.GetTestCoverage(ctx, projectPath)
+	if err != nil || coverage == nil || (coverage.Lines == 0 && len(coverage.Files) == 0) {
+		s.log.Warning(fmt.Sprintf("[Task %s] Test coverage not measured: %v", taskID, err))
+		return fmt.Sprintf("coverage: %.1f%% is required but coverage could not be measured", minCoverage)
+	}
+	if coverage.Percentage < minCoverage {
+		return fmt.Sprintf("coverage: %.1f%% is below the required %.1f%%", coverage.Percentage, minCoverage)
+	}
+	return ""
+}
+
+// verificationGateStep records failed gate checks as a failed pipeline step,
+// so they are repaired like any other failed step
+func verificationGateStep(taskID string, failures []string) *TaskPipelineStep {
+	return &TaskPipelineStep{
+		ID:     taskID + "-verification-gate",
+		Name:   "Verification gate",
+		Type:   router.StepTypeValidate,
+		Status: StepStatusFailed,
+		Error:  strings.Join(failures, "\n"),
+	}
+}
@@ -0,0 +1,167 @@
+package taskflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"shotgun_code/domain"
+)
+
+// heuristicRouter makes tasks fall back to the heuristic pipeline policy
+type heuristicRouter struct{}
+
+func (heuristicRouter) CreatePipelineWithLLM(context.Context, domain.Task, map[string]any) (*LLMPipelineResponse, error) {
+	return nil, errors.New("no LLM")
+}
+
+// repairRecordingPlanner applies edits and records the errors repair pipelines are asked to fix
+type repairRecordingPlanner struct {
+	*applyingPlanner
+	repairs []string
+}
+
+func (p *repairRecordingPlanner) ExecutePipeline(ctx context.Context, pipeline *TaskPipeline) error {
+	for _, step := range pipeline.Steps {
+		if step.Type == StepTypeRepair {
+			p.repairs = append(p.repairs, step.Config["error_output"].(string))
+		}
+	}
+	return p.applyingPlanner.ExecutePipeline(ctx, pipeline)
+}
+
+type diffRepo struct {
+	*workingTreeRepo
+}
+
+func (diffRepo) GenerateDiff(string) (string, error) { return "", nil }
+
+// scriptedVerifier fails the tests category a number of times, then passes
+type scriptedVerifier struct {
+	failures int
+	configs  []*domain.ValidationSummaryConfig
+	coverage *domain.TestCoverage
+}
+
+func (v *scriptedVerifier) ValidateProjectSummary(_ context.Context, config *domain.ValidationSummaryConfig) (*domain.ValidationSummary, error) {
+	v.configs = append(v.configs, config)
+	summary := &domain.ValidationSummary{Success: true, Status: domain.ValidationStatusPassed}
+	for _, category := range config.Categories {
+		result := &domain.ValidationCategoryResult{Category: category, Status: domain.ValidationStatusPassed}
+		if category == domain.ValidationCategoryTests && v.failures > 0 {
+			v.failures--
+			result.Status, result.Message = domain.ValidationStatusFailed, "TestLogin failed"
+			summary.Success, summary.Status = false, domain.ValidationStatusFailed
+		}
+		summary.Categories = append(summary.Categories, result)
+	}
+	return summary, nil
+}
+
+func (v *scriptedVerifier) GetTestCoverage(context.Context, string) (*domain.TestCoverage, error) {
+	return v.coverage, nil
+}
+
+func newGateTestService(t *testing.T, verifier ProjectVerifier) (*Service, *repairRecordingPlanner, string) {
+	t.Helper()
+	projectPath := t.TempDir()
+	planner := &repairRecordingPlanner{applyingPlanner: &applyingPlanner{projectPath: projectPath, files: map[string]string{"main.go": "package main\n"}}}
+	service := &Service{
+		log:              &domain.NoopLogger{},
+		tasks:            make(map[string]domain.Task),
+		statuses:         make(map[string]*domain.TaskStatus),
+		planner:          planner,
+		routerLlmService: heuristicRouter{},
+		gitRepo:          diffRepo{&workingTreeRepo{committed: map[string]string{}}},
+	}
+	service.SetVerifier(verifier)
+	return service, planner, projectPath
+}
+
+func runGatedTask(service *Service, projectPath, slaPolicy string) (*domain.AutonomousTaskStatus, error) {
+	status := &domain.AutonomousTaskStatus{TaskId: "autonomous_1"}
+	request := domain.AutonomousTaskRequest{Task: "add login", SlaPolicy: slaPolicy, ProjectPath: projectPath}
+	return status, service.executeAutonomousTask(context.Background(), request, status)
+}
+
+func TestExecuteAutonomousTask_RepairsVerificationGateFailures(t *testing.T) {
+	verifier := &scriptedVerifier{failures: 1}
+	service, planner, projectPath := newGateTestService(t, verifier)
+
+	if _, err := runGatedTask(service, projectPath, "standard"); err != nil {
+		t.Fatalf("executeAutonomousTask failed: %v", err)
+	}
+	if len(planner.repairs) != 1 || !strings.Contains(planner.repairs[0], "tests: TestLogin failed") {
+		t.Errorf("expected the gate failure to be repaired, got %q", planner.repairs)
+	}
+	if len(verifier.configs) != 2 {
+		t.Fatalf("expected the gate to run again after the repair, ran %d times", len(verifier.configs))
+	}
+	want := []string{domain.ValidationCategoryBuild, domain.ValidationCategoryTests, domain.ValidationCategoryStatic}
+	if got := verifier.configs[0]; strings.Join(got.Categories, ",") != strings.Join(want, ",") || len(got.ChangedFiles) != 1 {
+		t.Errorf("expected the standard gate on the changed files, got %+v", got)
+	}
+	if status := service.statuses["autonomous_1"]; status.State != domain.TaskStateDone {
+		t.Errorf("expected the task done after passing the gate, got %s", status.State)
+	}
+}
+
+func TestExecuteAutonomousTask_FailsWhileGateFails(t *testing.T) {
+	service, planner, projectPath := newGateTestService(t, &scriptedVerifier{failures: 10})
+
+	if _, err := runGatedTask(service, projectPath, "standard"); err == nil {
+		t.Fatal("expected the task to fail while the gate fails")
+	}
+	if len(planner.repairs) != 3 {
+		t.Errorf("expected a repair per attempt, got %d", len(planner.repairs))
+	}
+	if status := service.statuses["autonomous_1"]; status.State == domain.TaskStateDone {
+		t.Error("a task failing the gate must not complete")
+	}
+}
+
+func TestExecuteAutonomousTask_GatePerSLAPolicy(t *testing.T) {
+	verifier := &scriptedVerifier{coverage: &domain.TestCoverage{Percentage: 50, Lines: 100}}
+	service, planner, projectPath := newGateTestService(t, verifier)
+
+	if _, err := runGatedTask(service, projectPath, "lite"); err != nil {
+		t.Fatalf("lite task failed: %v", err)
+	}
+	if got := verifier.configs[0].Categories; len(got) != 1 || got[0] != domain.ValidationCategoryBuild {
+		t.Errorf("expected lite to only build, got %v", got)
+	}
+
+	// strict also enforces coverage
+	if _, err := runGatedTask(service, projectPath, "strict"); err == nil {
+		t.Fatal("expected strict to fail on low coverage")
+	}
+	if len(planner.repairs) == 0 || !strings.Contains(planner.repairs[0], "coverage: 50.0% is below the required 80.0%") {
+		t.Errorf("expected the coverage failure to be repaired, got %q", planner.repairs)
+	}
+
+	service.SetVerificationGate("strict", domain.VerificationGate{Categories: []string{domain.ValidationCategoryBuild}, MinCoverage: 40})
+	if _, err := runGatedTask(service, projectPath, "strict"); err != nil {
+		t.Errorf("expected the configured strict gate to pass, got %v", err)
+	}
+}
+
+func TestExecuteAutonomousTask_UnmeasuredCoverageFailsGate(t *testing.T) {
+	service, planner, projectPath := newGateTestService(t, &scriptedVerifier{})
+	service.SetVerificationGates(map[string]domain.VerificationGate{
+		"standard": {Categories: []string{domain.ValidationCategoryBuild}, MinCoverage: 50},
+	})
+
+	if _, err := runGatedTask(service, projectPath, "standard"); err == nil {
+		t.Fatal("expected the task to fail when required coverage can't be measured")
+	}
+	if len(planner.repairs) == 0 || !strings.Contains(planner.repairs[0], "coverage could not be measured") {
+		t.Errorf("expected the unmeasured coverage to be reported, got %q", planner.repairs)
+	}
+
+	// Replacing the overrides restores the default gate of the policy
+	service.SetVerificationGates(nil)
+	if gate := service.verificationGate("standard"); gate.MinCoverage != 0 {
+		t.Errorf("expected the default standard gate, got %+v", gate)
+	}
+}
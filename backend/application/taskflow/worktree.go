@@ -0,0 +1,120 @@
+package taskflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+)
+
+// taskBranchPrefix prefixes the branches isolated autonomous tasks run on
+const taskBranchPrefix = "shotgun/"
+
+// taskWorktree is the git worktree an isolated autonomous task runs in
+type taskWorktree struct {
+	projectPath string // repository the task was started for
+	path        string
+	branch      string
+	baseCommit  string
+	merge       bool
+
+	committed bool // the task's changes are committed to branch
+	merged    bool
+}
+
+// createTaskWorktree creates a worktree on a new shotgun/<taskID> branch from
+// the project's HEAD if the request asks for isolation. Uncommitted changes
+// of the project are not carried over.
+func (s *Service) createTaskWorktree(request domain.AutonomousTaskRequest, taskID string) (*taskWorktree, error) {
+	if !request.Options.UseWorktree {
+		return nil, nil
+	}
+	if s.gitRepo == nil || !s.gitRepo.IsGitRepository(request.ProjectPath) {
+		return nil, fmt.Errorf("worktree isolation requires a git repository: %s", request.ProjectPath)
+	}
+	commits, err := s.gitRepo.GetCommitHistory(request.ProjectPath, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the base commit: %w", err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("worktree isolation requires at least one commit in %s", request.ProjectPath)
+	}
+
+	worktree := &taskWorktree{
+		projectPath: request.ProjectPath,
+		path:        filepath.Join(os.TempDir(), "shotgun-worktrees", taskID),
+		branch:      taskBranchPrefix + taskID,
+		baseCommit:  commits[0].Hash,
+		merge:       request.Options.MergeOnSuccess,
+	}
+	if err := s.gitRepo.CreateWorktree(worktree.projectPath, worktree.path, worktree.branch, worktree.baseCommit); err != nil {
+		return nil, err
+	}
+	s.log.Info(fmt.Sprintf("[Task %s] Running in worktree %s on branch %s", taskID, worktree.path, worktree.branch))
+	return worktree, nil
+}
+
+// commitTaskWorktree commits the files changed by the task to its branch,
+// with a generated message if Options.CommitOnSuccess is set. Other files in
+// the worktree, such as build artifacts, are left out.
+func (s *Service) commitTaskWorktree(ctx context.Context, worktree *taskWorktree, request domain.AutonomousTaskRequest, files []string, taskID string) error {
+	if len(files) == 0 {
+		s.log.Info(fmt.Sprintf("[Task %s] No changes to commit on branch %s", taskID, worktree.branch))
+		return nil
+	}
+	message := fmt.Sprintf("%s\n\nAutonomous task on %s", request.Task, worktree.branch)
+	if request.Options.CommitOnSuccess {
+		diff, err := s.gitRepo.GenerateDiffAgainst(worktree.path, worktree.baseCommit)
+		if err != nil {
+			s.log.Warning(fmt.Sprintf("[Task %s] Failed to diff changes for the commit message: %v", taskID, err))
+		}
+		message = s.commitMessage(ctx, request.Task, diff, files)
+	}
+	hash, err := s.gitRepo.Commit(worktree.path, message, files)
+	if err != nil {
+		return err
+	}
+	worktree.committed = hash != ""
+	s.setTaskCommit(taskID, hash)
+	return nil
+}
+
+// mergeTaskWorktree merges the task's branch into the project's checked out
+// branch if requested. If the merge is refused or fails, the branch is kept
+// for review.
+func (s *Service) mergeTaskWorktree(worktree *taskWorktree) error {
+	if !worktree.merge || !worktree.committed {
+		return nil
+	}
+	if err := s.gitRepo.MergeBranch(worktree.projectPath, worktree.branch); err != nil {
+		return fmt.Errorf("%w; the changes are left on branch %s", err, worktree.branch)
+	}
+	worktree.merged = true
+	return nil
+}
+
+// removeTaskWorktree removes the worktree once the task ended. Committed
+// changes that were not merged are left on the branch for review; otherwise
+// the branch is deleted.
+func (s *Service) removeTaskWorktree(worktree *taskWorktree, taskID string) {
+	if err := s.gitRepo.RemoveWorktree(worktree.projectPath, worktree.path); err != nil {
+		s.log.Warning(fmt.Sprintf("[Task %s] Failed to remove worktree: %v", taskID, err))
+	}
+	if worktree.committed && !worktree.merged {
+		s.log.Info(fmt.Sprintf("[Task %s] Changes left on branch %s for review", taskID, worktree.branch))
+		return
+	}
+	if err := s.gitRepo.DeleteBranch(worktree.projectPath, worktree.branch); err != nil {
+		s.log.Warning(fmt.Sprintf("[Task %s] Failed to delete branch %s: %v", taskID, worktree.branch, err))
+	}
+}
+
+// taskDiff returns the changes of a task: against the base commit for tasks
+// run in a worktree, otherwise the last commit of the project
+func (s *Service) taskDiff(projectPath string, worktree *taskWorktree) (string, error) {
+	if worktree != nil {
+		return s.gitRepo.GenerateDiffAgainst(worktree.path, worktree.baseCommit)
+	}
+	return s.gitRepo.GenerateDiff(projectPath)
+}
@@ -0,0 +1,146 @@
+package taskflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"shotgun_code/domain"
+)
+
+// worktreeRepo records the git calls of an isolated task
+type worktreeRepo struct {
+	domain.GitRepository
+	calls       []string
+	statusCalls int
+}
+
+func (r *worktreeRepo) record(format string, args ...any) {
+	r.calls = append(r.calls, fmt.Sprintf(format, args...))
+}
+
+func (r *worktreeRepo) IsGitRepository(string) bool { return true }
+
+func (r *worktreeRepo) GetCommitHistory(string, int) ([]domain.CommitInfo, error) {
+	return []domain.CommitInfo{{Hash: "base123"}}, nil
+}
+
+func (r *worktreeRepo) CreateWorktree(projectPath, _, branch, baseRef string) error {
+	r.record("create %s %s %s", projectPath, branch, baseRef)
+	return nil
+}
+
+func (r *worktreeRepo) RemoveWorktree(projectPath, _ string) error {
+	r.record("remove %s", projectPath)
+	return nil
+}
+
+func (r *worktreeRepo) Commit(_, _ string, files []string) (string, error) {
+	r.record("commit %v", files)
+	return "abc123", nil
+}
+
+func (r *worktreeRepo) GenerateDiffAgainst(_, baseRef string) (string, error) {
+	r.record("diff %s", baseRef)
+	return "", nil
+}
+
+func (r *worktreeRepo) MergeBranch(projectPath, branch string) error {
+	r.record("merge %s %s", projectPath, branch)
+	return nil
+}
+
+func (r *worktreeRepo) DeleteBranch(_, branch string) error {
+	r.record("delete %s", branch)
+	return nil
+}
+
+// GetUncommittedFiles reports login.go as changed once the task has run
+func (r *worktreeRepo) GetUncommittedFiles(string) ([]domain.FileStatus, error) {
+	r.statusCalls++
+	if r.statusCalls == 1 {
+		return nil, nil
+	}
+	return []domain.FileStatus{{Path: "login.go", Status: "??"}}, nil
+}
+
+func (r *worktreeRepo) GetFileContentAtCommit(_, filePath, _ string) (string, error) {
+	return "", fmt.Errorf("path %s does not exist in HEAD", filePath)
+}
+
+// pathRecordingPlanner records the project path tasks are planned for
+type pathRecordingPlanner struct {
+	projectPath string
+	fail        bool
+}
+
+func (p *pathRecordingPlanner) CreatePipeline(_ context.Context, task domain.Task, _ *PipelinePolicy) (*TaskPipeline, error) {
+	p.projectPath, _ = task.Metadata["project_path"].(string)
+	return &TaskPipeline{TaskID: task.ID, Policy: &PipelinePolicy{}}, nil
+}
+
+func (p *pathRecordingPlanner) ExecutePipeline(_ context.Context, pipeline *TaskPipeline) error {
+	if p.fail && !strings.HasSuffix(pipeline.TaskID, "-repair") {
+		return errors.New("step failed")
+	}
+	pipeline.Status = PipelineStatusCompleted
+	return nil
+}
+
+func (p *pathRecordingPlanner) GetPipelineStatus(*TaskPipeline) map[string]any { return nil }
+
+func runWorktreeTask(t *testing.T, options domain.AutonomousTaskOptions, fail bool) (*worktreeRepo, *pathRecordingPlanner, error) {
+	t.Helper()
+	repo := &worktreeRepo{}
+	planner := &pathRecordingPlanner{fail: fail}
+	service := &Service{
+		log:              &domain.NoopLogger{},
+		tasks:            make(map[string]domain.Task),
+		statuses:         make(map[string]*domain.TaskStatus),
+		planner:          planner,
+		routerLlmService: heuristicRouter{},
+		gitRepo:          repo,
+	}
+	options.UseWorktree = true
+	request := domain.AutonomousTaskRequest{Task: "add login", SlaPolicy: "lite", ProjectPath: "/repo", Options: options}
+	err := service.executeAutonomousTask(context.Background(), request, &domain.AutonomousTaskStatus{TaskId: "autonomous_1"})
+	return repo, planner, err
+}
+
+func TestExecuteAutonomousTask_Worktree(t *testing.T) {
+	repo, planner, err := runWorktreeTask(t, domain.AutonomousTaskOptions{}, false)
+	if err != nil {
+		t.Fatalf("executeAutonomousTask failed: %v", err)
+	}
+	if planner.projectPath == "/repo" || !strings.Contains(planner.projectPath, "autonomous_1") {
+		t.Errorf("expected the task planned in its worktree, got %q", planner.projectPath)
+	}
+	want := "create /repo shotgun/autonomous_1 base123|commit [login.go]|diff base123|remove /repo"
+	if got := strings.Join(repo.calls, "|"); got != want {
+		t.Errorf("git calls = %s, want %s", got, want)
+	}
+}
+
+func TestExecuteAutonomousTask_WorktreeMerge(t *testing.T) {
+	repo, _, err := runWorktreeTask(t, domain.AutonomousTaskOptions{MergeOnSuccess: true}, false)
+	if err != nil {
+		t.Fatalf("executeAutonomousTask failed: %v", err)
+	}
+	want := "create /repo shotgun/autonomous_1 base123|commit [login.go]|diff base123|merge /repo shotgun/autonomous_1|remove /repo|delete shotgun/autonomous_1"
+	if got := strings.Join(repo.calls, "|"); got != want {
+		t.Errorf("git calls = %s, want %s", got, want)
+	}
+}
+
+func TestExecuteAutonomousTask_WorktreeDiscardedOnFailure(t *testing.T) {
+	repo, _, err := runWorktreeTask(t, domain.AutonomousTaskOptions{}, true)
+	if err == nil {
+		t.Fatal("expected the task to fail")
+	}
+	want := "create /repo shotgun/autonomous_1 base123|remove /repo|delete shotgun/autonomous_1"
+	if got := strings.Join(repo.calls, "|"); got != want {
+		t.Errorf("git calls = %s, want %s", got, want)
+	}
+}
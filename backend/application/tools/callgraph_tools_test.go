@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"testing"
 
 	"shotgun_code/domain"
@@ -29,6 +30,14 @@ func (m *mockCallGraphBuilder) GetCallChain(startID, endID string, maxDepth int)
 	return nil
 }
 
+func (m *mockCallGraphBuilder) GetFileDependents(path string) []string {
+	return nil
+}
+
+func (m *mockCallGraphBuilder) BuildForFile(ctx context.Context, filePath string, content []byte) error {
+	return nil
+}
+
 func TestCallGraphHandler_CanHandle(t *testing.T) {
 	callGraph := &mockCallGraphBuilder{}
 	handler := NewCallGraphToolsHandler(nil, callGraph)
@@ -73,6 +73,8 @@ func (h *GitToolsHandler) GetTools() []domain.Tool {
 				Type: "object",
 				Properties: map[string]domain.ToolProperty{
 					"since":       {Type: "string", Description: "Time period (e.g., '1 week ago', '2024-01-01')"},
+					"until":       {Type: "string", Description: "End of the time period (optional)"},
+					"author":      {Type: "string", Description: "Filter by author name or email (optional)"},
 					"path_filter": {Type: "string", Description: "Filter by path pattern"},
 				},
 			},
@@ -231,10 +233,13 @@ func (h *GitToolsHandler) gitChangedFiles(args map[string]any, projectRoot strin
 		return "", fmt.Errorf("git context not initialized")
 	}
 
-	since, _ := args["since"].(string)
-	pathFilter, _ := args["path_filter"].(string)
+	opts := domain.RecentChangesOptions{}
+	opts.Since, _ = args["since"].(string)
+	opts.Until, _ = args["until"].(string)
+	opts.Author, _ = args["author"].(string)
+	opts.PathFilter, _ = args["path_filter"].(string)
 
-	changes, err := h.GitContext.GetRecentChanges(since, pathFilter)
+	changes, err := h.GitContext.GetRecentChangesFiltered(opts)
 	if err != nil {
 		return "", fmt.Errorf("failed to get recent changes: %w", err)
 	}
@@ -37,6 +37,18 @@ func (m *MockContextMemory) FindContextByTopic(projectRoot, topic string) ([]*do
 	return result, nil
 }
 
+func (m *MockContextMemory) FindSimilarContexts(projectRoot, query string, limit int) ([]*domain.ConversationContext, error) {
+	result, _ := m.FindContextByTopic(projectRoot, query)
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockContextMemory) PruneContexts(projectRoot string) (int, error) {
+	return 0, nil
+}
+
 func (m *MockContextMemory) GetRecentContexts(projectRoot string, limit int) ([]*domain.ConversationContext, error) {
 	var result []*domain.ConversationContext
 	for _, c := range m.contexts {
@@ -266,7 +266,41 @@ func (h *SymbolToolsHandler) FindReferences(args map[string]any, projectRoot str
 		if ref.IsDefinition {
 			marker = "* "
 		}
-		result.WriteString(fmt.Sprintf("%s%s:%d:%d\n", marker, ref.FilePath, ref.Line, ref.Column))
+		location := fmt.Sprintf("%s:%d:%d", ref.FilePath, ref.Line, ref.Column)
+		if ref.EnclosingFunction != "" {
+			location += " in " + ref.EnclosingFunction
+		}
+		result.WriteString(fmt.Sprintf("%s%s (confidence %.2f)\n", marker, location, ref.Confidence))
+		result.WriteString(fmt.Sprintf("    %s\n", ref.LineText))
+	}
+
+	return result.String(), nil
+}
+
+// FindImplementations finds Go types that implement an interface
+func (h *SymbolToolsHandler) FindImplementations(args map[string]any, projectRoot string) (interface{}, error) {
+	name, _ := args["interface_name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("interface_name is required")
+	}
+
+	if h.referenceFinder == nil {
+		return nil, fmt.Errorf("reference finder not initialized")
+	}
+
+	refs, err := h.referenceFinder.FindImplementations(context.Background(), projectRoot, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find implementations: %w", err)
+	}
+
+	if len(refs) == 0 {
+		return fmt.Sprintf("No implementations found for '%s'", name), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Found %d implementations of '%s':\n\n", len(refs), name))
+	for _, ref := range refs {
+		result.WriteString(fmt.Sprintf("  %s:%d (confidence %.2f)\n", ref.FilePath, ref.Line, ref.Confidence))
 		result.WriteString(fmt.Sprintf("    %s\n", ref.LineText))
 	}
 
@@ -459,13 +493,14 @@ func (h *SymbolToolsHandler) GetClassHierarchy(args map[string]any, projectRoot
 
 // symbolToolNames defines which tools this handler manages
 var symbolToolNames = map[string]bool{
-	"list_symbols":        true,
-	"search_symbols":      true,
-	"find_definition":     true,
-	"find_references":     true,
-	"get_symbol_info":     true,
-	"get_class_hierarchy": true,
-	"get_imports":         true,
+	"list_symbols":         true,
+	"search_symbols":       true,
+	"find_definition":      true,
+	"find_references":      true,
+	"find_implementations": true,
+	"get_symbol_info":      true,
+	"get_class_hierarchy":  true,
+	"get_imports":          true,
 }
 
 // CanHandle returns true if this handler can handle the given tool
@@ -525,6 +560,17 @@ func (h *SymbolToolsHandler) GetTools() []domain.Tool {
 				Required: []string{"name"},
 			},
 		},
+		{
+			Name:        "find_implementations",
+			Description: "Find Go types that implement an interface (go to implementations).",
+			Parameters: domain.ToolParameters{
+				Type: "object",
+				Properties: map[string]domain.ToolProperty{
+					"interface_name": {Type: "string", Description: "Interface name, optionally qualified with the package (e.g. 'domain.Logger')"},
+				},
+				Required: []string{"interface_name"},
+			},
+		},
 		{
 			Name:        "get_symbol_info",
 			Description: "Get detailed information about a symbol including signature, documentation, modifiers.",
@@ -578,6 +624,8 @@ func (h *SymbolToolsHandler) Execute(toolName string, args map[string]any, proje
 		result, err = h.FindDefinition(args, projectRoot)
 	case "find_references":
 		result, err = h.FindReferences(args, projectRoot)
+	case "find_implementations":
+		result, err = h.FindImplementations(args, projectRoot)
 	case "get_symbol_info":
 		result, err = h.GetSymbolInfo(args, projectRoot)
 	case "get_class_hierarchy":
@@ -0,0 +1,286 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"path/filepath"
+	"shotgun_code/domain"
+	"strings"
+	"time"
+)
+
+const (
+	// staticMaxIssueDensity - взвешенное число замечаний на файл, при котором
+	// оценка статического анализа падает до 0
+	staticMaxIssueDensity = 1.0
+
+	// cyclePenalty - штраф за каждую циклическую зависимость
+	cyclePenalty = 10.0
+)
+
+// vulnerabilityPenalties - штраф за уязвимость по ее серьезности
+var vulnerabilityPenalties = map[string]float64{
+	"critical": 25,
+	"high":     10,
+	"medium":   3,
+	"low":      1,
+}
+
+// healthSourceExtensions - расширения исходных файлов, по которым считается плотность замечаний
+var healthSourceExtensions = map[string]bool{
+	".go": true, ".ts": true, ".tsx": true, ".js": true, ".jsx": true, ".vue": true,
+	".py": true, ".java": true, ".kt": true, ".rs": true, ".cs": true,
+	".c": true, ".cc": true, ".cpp": true, ".h": true, ".hpp": true,
+}
+
+// VulnerabilityScanner сканирует уязвимости зависимостей проекта
+type VulnerabilityScanner interface {
+	ScanVulnerabilities(ctx context.Context, projectPath string) (*domain.VulnerabilityScanResult, error)
+}
+
+// DependencyCycleCounter возвращает число циклических зависимостей проекта
+type DependencyCycleCounter func(projectRoot string) (int, error)
+
+// SetVulnerabilityScanner подключает сканер уязвимостей для оценки здоровья проекта
+func (s *Service) SetVulnerabilityScanner(scanner VulnerabilityScanner) {
+	s.vulnScanner = scanner
+}
+
+// SetDependencyCycleCounter подключает поиск циклических зависимостей для оценки здоровья проекта
+func (s *Service) SetDependencyCycleCounter(counter DependencyCycleCounter) {
+	s.cycleCounter = counter
+}
+
+// ComputeProjectHealth оценивает здоровье проекта от 0 до 100 по плотности замечаний
+// статического анализа, покрытию тестами, уязвимостям, циклическим зависимостям и
+// статусу сборки. weights nil означает веса по умолчанию. Категории, которые не
+// удалось измерить, пропускаются, и оценка считается по остальным
+func (s *Service) ComputeProjectHealth(ctx context.Context, projectPath string, weights *domain.HealthWeights) (*domain.ProjectHealth, error) {
+	if projectPath == "" {
+		return nil, fmt.Errorf("project path is required")
+	}
+	health := &domain.ProjectHealth{
+		ProjectPath: projectPath,
+		Weights:     domain.DefaultHealthWeights(),
+	}
+	if weights != nil {
+		health.Weights = *weights
+	}
+
+	languages, err := s.buildService.DetectLanguages(ctx, projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect languages: %w", err)
+	}
+	s.log.Info(fmt.Sprintf("Computing project health for %s (languages: %v)", projectPath, languages))
+
+	health.Categories = []*domain.HealthCategoryScore{
+		s.staticHealth(ctx, projectPath, languages),
+		s.coverageHealth(ctx, projectPath),
+		s.vulnerabilityHealth(ctx, projectPath),
+		s.cycleHealth(projectPath),
+		s.buildHealth(ctx, projectPath, languages),
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	score, ok := weightedHealthScore(health.Categories, health.Weights)
+	if !ok {
+		return nil, fmt.Errorf("no health category could be measured for %s", projectPath)
+	}
+	health.Score = score
+	health.Grade = domain.HealthGrade(score)
+	health.ComputedAt = time.Now().UTC().Format(time.RFC3339)
+
+	s.log.Info(fmt.Sprintf("Project health: %.1f (%s)", health.Score, health.Grade))
+	return health, nil
+}
+
+// staticHealth оценивает плотность замечаний: ошибки весят 1, предупреждения 0.5
+func (s *Service) staticHealth(ctx context.Context, projectPath string, languages []string) *domain.HealthCategoryScore {
+	if s.staticAnalyzer == nil {
+		return skippedHealth(domain.HealthCategoryStatic, "static analyzer is not available")
+	}
+	report, err := s.staticAnalyzer.AnalyzeProject(ctx, projectPath, languages)
+	if err != nil || report == nil || report.Summary == nil {
+		return skippedHealth(domain.HealthCategoryStatic, fmt.Sprintf("static analysis failed: %v", err))
+	}
+	files, err := countSourceFiles(projectPath)
+	if err != nil || files == 0 {
+		return skippedHealth(domain.HealthCategoryStatic, "no source files found")
+	}
+	return staticHealthScore(report.Summary.TotalErrors, report.Summary.TotalWarnings, files)
+}
+
+// coverageHealth использует процент покрытия как оценку
+func (s *Service) coverageHealth(ctx context.Context, projectPath string) *domain.HealthCategoryScore {
+	if s.testService == nil {
+		return skippedHealth(domain.HealthCategoryCoverage, "test service is not available")
+	}
+	coverage, err := s.testService.GetTestCoverage(ctx, projectPath)
+	if err != nil || coverage == nil || (coverage.Lines == 0 && len(coverage.Files) == 0) {
+		return skippedHealth(domain.HealthCategoryCoverage, "test coverage is not measured")
+	}
+	return &domain.HealthCategoryScore{
+		Category: domain.HealthCategoryCoverage,
+		Score:    clampHealthScore(coverage.Percentage),
+		Value:    coverage.Percentage,
+		Message:  fmt.Sprintf("%.1f%% covered", coverage.Percentage),
+	}
+}
+
+// vulnerabilityHealth штрафует за уязвимости по их серьезности
+func (s *Service) vulnerabilityHealth(ctx context.Context, projectPath string) *domain.HealthCategoryScore {
+	if s.vulnScanner == nil {
+		return skippedHealth(domain.HealthCategoryVulnerabilities, "vulnerability scanner is not available")
+	}
+	result, err := s.vulnScanner.ScanVulnerabilities(ctx, projectPath)
+	if err != nil {
+		return skippedHealth(domain.HealthCategoryVulnerabilities, fmt.Sprintf("vulnerability scan failed: %v", err))
+	}
+	if result == nil || !result.Success {
+		reason := "vulnerability scan failed"
+		if result != nil && result.Error != "" {
+			reason += ": " + result.Error
+		}
+		return skippedHealth(domain.HealthCategoryVulnerabilities, reason)
+	}
+	return vulnerabilityHealthScore(result)
+}
+
+// cycleHealth штрафует за каждую циклическую зависимость
+func (s *Service) cycleHealth(projectPath string) *domain.HealthCategoryScore {
+	if s.cycleCounter == nil {
+		return skippedHealth(domain.HealthCategoryCycles, "dependency analysis is not available")
+	}
+	cycles, err := s.cycleCounter(projectPath)
+	if err != nil {
+		return skippedHealth(domain.HealthCategoryCycles, fmt.Sprintf("dependency analysis failed: %v", err))
+	}
+	return &domain.HealthCategoryScore{
+		Category: domain.HealthCategoryCycles,
+		Score:    clampHealthScore(100 - cyclePenalty*float64(cycles)),
+		Value:    float64(cycles),
+		Message:  fmt.Sprintf("%d dependency cycles", cycles),
+	}
+}
+
+// buildHealth оценивает долю языков проекта, которые собираются
+func (s *Service) buildHealth(ctx context.Context, projectPath string, languages []string) *domain.HealthCategoryScore {
+	if len(languages) == 0 {
+		return skippedHealth(domain.HealthCategoryBuild, "no languages detected")
+	}
+	result := s.validator.validateBuild(ctx, projectPath, languages)
+	built := 0
+	if details, ok := result.Details.(map[string]*domain.BuildResult); ok {
+		for _, build := range details {
+			if build.Success {
+				built++
+			}
+		}
+	}
+	ratio := float64(built) / float64(len(languages))
+	message := result.Message
+	if message == "" {
+		message = "build passed"
+	}
+	return &domain.HealthCategoryScore{
+		Category: domain.HealthCategoryBuild,
+		Score:    clampHealthScore(100 * ratio),
+		Value:    ratio,
+		Message:  message,
+	}
+}
+
+// staticHealthScore снижает оценку линейно до 0 при staticMaxIssueDensity замечаний на файл
+func staticHealthScore(errorCount, warningCount, files int) *domain.HealthCategoryScore {
+	density := (float64(errorCount) + 0.5*float64(warningCount)) / float64(files)
+	return &domain.HealthCategoryScore{
+		Category: domain.HealthCategoryStatic,
+		Score:    clampHealthScore(100 * (1 - density/staticMaxIssueDensity)),
+		Value:    density,
+		Message:  fmt.Sprintf("%d errors, %d warnings in %d files", errorCount, warningCount, files),
+	}
+}
+
+// vulnerabilityHealthScore вычитает из 100 штрафы vulnerabilityPenalties
+func vulnerabilityHealthScore(result *domain.VulnerabilityScanResult) *domain.HealthCategoryScore {
+	var penalty float64
+	var total int
+	if result.Summary != nil {
+		total = result.Summary.Total
+		penalty = vulnerabilityPenalties["critical"]*float64(result.Summary.Critical) +
+			vulnerabilityPenalties["high"]*float64(result.Summary.High) +
+			vulnerabilityPenalties["medium"]*float64(result.Summary.Medium) +
+			vulnerabilityPenalties["low"]*float64(result.Summary.Low)
+	} else {
+		total = len(result.Vulnerabilities)
+		for _, vulnerability := range result.Vulnerabilities {
+			penalty += vulnerabilityPenalties[strings.ToLower(vulnerability.Severity)]
+		}
+	}
+	return &domain.HealthCategoryScore{
+		Category: domain.HealthCategoryVulnerabilities,
+		Score:    clampHealthScore(100 - penalty),
+		Value:    float64(total),
+		Message:  fmt.Sprintf("%d vulnerabilities", total),
+	}
+}
+
+// weightedHealthScore возвращает средневзвешенную оценку измеренных категорий
+// и проставляет им веса; ok false, если ни одна категория не вошла в оценку
+func weightedHealthScore(categories []*domain.HealthCategoryScore, weights domain.HealthWeights) (float64, bool) {
+	var sum, totalWeight float64
+	for _, category := range categories {
+		category.Weight = weights.Weight(category.Category)
+		if category.Skipped || category.Weight <= 0 {
+			continue
+		}
+		sum += category.Score * category.Weight
+		totalWeight += category.Weight
+	}
+	if totalWeight == 0 {
+		return 0, false
+	}
+	return math.Round(sum/totalWeight*10) / 10, true
+}
+
+// countSourceFiles считает исходные файлы проекта, пропуская служебные каталоги
+func countSourceFiles(projectPath string) (int, error) {
+	skip := make(map[string]bool)
+	for _, dir := range domain.DefaultConfig().Tools.SkipDirectories {
+		skip[dir] = true
+	}
+	count := 0
+	err := filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path != projectPath && (skip[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if healthSourceExtensions[strings.ToLower(filepath.Ext(path))] {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+func clampHealthScore(score float64) float64 {
+	return math.Round(min(max(score, 0), 100)*10) / 10
+}
+
+// skippedHealth возвращает категорию, которую не удалось измерить
+func skippedHealth(category, reason string) *domain.HealthCategoryScore {
+	return &domain.HealthCategoryScore{Category: category, Skipped: true, Message: reason}
+}
@@ -0,0 +1,88 @@
+package verification
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"shotgun_code/domain"
+	"shotgun_code/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVulnScanner struct {
+	result *domain.VulnerabilityScanResult
+}
+
+func (f fakeVulnScanner) ScanVulnerabilities(context.Context, string) (*domain.VulnerabilityScanResult, error) {
+	return f.result, nil
+}
+
+func TestComputeProjectHealth_WeightsMeasuredCategories(t *testing.T) {
+	projectPath := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go", "d.go", "README.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(projectPath, name), []byte("package main\n"), 0o644))
+	}
+
+	buildService := &testutils.MockBuildService{}
+	testService := &testutils.MockTestService{}
+	staticAnalyzer := &testutils.MockStaticAnalyzerService{}
+	buildService.On("DetectLanguages", mock.Anything, projectPath).Return([]string{"go"}, nil)
+	buildService.On("Build", mock.Anything, projectPath, "go", domain.BuildTarget{}).Return(&domain.BuildResult{Success: true}, nil)
+	testService.On("GetTestCoverage", mock.Anything, projectPath).Return(&domain.TestCoverage{}, nil)
+	staticAnalyzer.On("AnalyzeProject", mock.Anything, projectPath, []string{"go"}).Return(&domain.StaticAnalysisReport{
+		Summary: &domain.StaticAnalysisReportSummary{TotalErrors: 1, TotalWarnings: 2},
+	}, nil)
+
+	service := NewService(&domain.NoopLogger{}, buildService, testService, staticAnalyzer, nil, nil, nil)
+	service.SetVulnerabilityScanner(fakeVulnScanner{result: &domain.VulnerabilityScanResult{
+		Success: true,
+		Summary: &domain.VulnerabilitySummary{Total: 2, High: 1, Low: 1},
+	}})
+	service.SetDependencyCycleCounter(func(string) (int, error) { return 3, nil })
+
+	health, err := service.ComputeProjectHealth(context.Background(), projectPath, nil)
+	require.NoError(t, err)
+
+	scores := make(map[string]*domain.HealthCategoryScore)
+	for _, category := range health.Categories {
+		scores[category.Category] = category
+	}
+	assert.Equal(t, 50.0, scores[domain.HealthCategoryStatic].Score) // 2 weighted issues in 4 files
+	assert.True(t, scores[domain.HealthCategoryCoverage].Skipped)
+	assert.Equal(t, 89.0, scores[domain.HealthCategoryVulnerabilities].Score)
+	assert.Equal(t, 70.0, scores[domain.HealthCategoryCycles].Score)
+	assert.Equal(t, 100.0, scores[domain.HealthCategoryBuild].Score)
+
+	// (50*0.25 + 89*0.25 + 70*0.10 + 100*0.20) / 0.80, coverage isn't measured
+	assert.Equal(t, 77.2, health.Score)
+	assert.Equal(t, "C", health.Grade)
+}
+
+func TestComputeProjectHealth_CustomWeights(t *testing.T) {
+	buildService := &testutils.MockBuildService{}
+	buildService.On("DetectLanguages", mock.Anything, "/proj").Return([]string{"go", "typescript"}, nil)
+	buildService.On("Build", mock.Anything, "/proj", "go", domain.BuildTarget{}).Return(&domain.BuildResult{Success: true}, nil)
+	buildService.On("Build", mock.Anything, "/proj", "typescript", domain.BuildTarget{}).Return(&domain.BuildResult{Error: "tsc failed"}, nil)
+
+	service := NewService(&domain.NoopLogger{}, buildService, nil, nil, nil, nil, nil)
+	service.SetDependencyCycleCounter(func(string) (int, error) { return 0, nil })
+
+	health, err := service.ComputeProjectHealth(context.Background(), "/proj", &domain.HealthWeights{Build: 3, Cycles: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 62.5, health.Score) // (50*3 + 100*1) / 4
+	assert.Equal(t, "D", health.Grade)
+}
+
+func TestComputeProjectHealth_NothingMeasured(t *testing.T) {
+	buildService := &testutils.MockBuildService{}
+	buildService.On("DetectLanguages", mock.Anything, "/proj").Return([]string{}, nil)
+
+	service := NewService(&domain.NoopLogger{}, buildService, nil, nil, nil, nil, nil)
+	_, err := service.ComputeProjectHealth(context.Background(), "/proj", nil)
+	assert.Error(t, err)
+}
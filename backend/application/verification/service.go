@@ -2,9 +2,11 @@ package verification
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"shotgun_code/domain"
+	"strings"
 	"time"
 )
 
@@ -22,6 +24,9 @@ type Service struct {
 	formatterService FormatterService
 	reportWriter     domain.FileSystemWriter
 	taskProtocol     domain.TaskProtocolService
+	validator        *ProjectValidator
+	vulnScanner      VulnerabilityScanner   // необязательный, для оценки здоровья проекта
+	cycleCounter     DependencyCycleCounter // необязательный, для оценки здоровья проекта
 }
 
 // NewService создает новый сервис verification pipeline
@@ -42,9 +47,20 @@ func NewService(
 		formatterService: formatterService,
 		reportWriter:     reportWriter,
 		taskProtocol:     taskProtocol,
+		validator:        NewProjectValidator(log, buildService, testService, staticAnalyzer),
 	}
 }
 
+// ValidateProjectSummary выполняет сводную проверку проекта по выбранным категориям
+func (s *Service) ValidateProjectSummary(ctx context.Context, config *domain.ValidationSummaryConfig) (*domain.ValidationSummary, error) {
+	return s.validator.Validate(ctx, config)
+}
+
+// GetTestCoverage возвращает покрытие тестами проекта
+func (s *Service) GetTestCoverage(ctx context.Context, projectPath string) (*domain.TestCoverage, error) {
+	return s.testService.GetTestCoverage(ctx, projectPath)
+}
+
 // RunVerificationPipeline выполняет полный verification pipeline
 func (s *Service) RunVerificationPipeline(ctx context.Context, config *domain.VerificationConfig) (*domain.VerificationResult, error) {
 	s.log.Info(fmt.Sprintf("Starting verification pipeline for project: %s", config.ProjectPath))
@@ -60,34 +76,26 @@ func (s *Service) RunVerificationPipeline(ctx context.Context, config *domain.Ve
 	formatStep := s.runStep(ctx, "format", config, s.runFormatStep)
 	result.Steps = append(result.Steps, formatStep)
 
-	// Шаг 2: Build и Type Check
-	buildStep := s.runStep(ctx, "build-typecheck", config, s.runBuildTypeCheckStep)
-	result.Steps = append(result.Steps, buildStep)
-	if buildStep.Error != nil {
-		result.Success = false
-		result.CompletedAt = time.Now().UTC().Format(time.RFC3339)
-		return result, fmt.Errorf("build/typecheck failed: %w", buildStep.Error)
-	}
-
-	// Шаг 3: Smoke Tests
-	testStep := s.runStep(ctx, "smoke-tests", config, s.runSmokeTestsStep)
-	result.Steps = append(result.Steps, testStep)
-	if testStep.Error != nil {
+	// Шаги 2-4: сборка и проверка типов, тесты и статический анализ одной сводной проверкой
+	summary, err := s.validator.Validate(ctx, &domain.ValidationSummaryConfig{
+		ProjectPath:  config.ProjectPath,
+		Languages:    config.Languages,
+		Categories:   config.Categories,
+		ChangedFiles: config.ChangedFiles,
+	})
+	if err != nil {
 		result.Success = false
 		result.CompletedAt = time.Now().UTC().Format(time.RFC3339)
-		return result, fmt.Errorf("smoke tests failed: %w", testStep.Error)
+		return result, fmt.Errorf("project validation failed: %w", err)
 	}
+	result.Summary = summary
+	result.Steps = append(result.Steps, summarySteps(summary)...)
+	result.Success = summary.Success
 
-	// Шаг 4: Static Analysis
-	staticStep := s.runStep(ctx, "static-analysis", config, s.runStaticAnalysisStep)
-	result.Steps = append(result.Steps, staticStep)
-
-	// Определяем общий успех
-	result.Success = true
 	for _, step := range result.Steps {
-		if !step.Success && step.Name != "format" && step.Name != "static-analysis" {
-			result.Success = false
-			break
+		if step.Error != nil && (step.Name == stepBuildTypeCheck || step.Name == stepSmokeTests) {
+			result.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+			return result, fmt.Errorf("%s failed: %w", step.Name, step.Error)
 		}
 	}
 
@@ -101,6 +109,61 @@ func (s *Service) RunVerificationPipeline(ctx context.Context, config *domain.Ve
 	return result, nil
 }
 
+// Имена шагов verification pipeline, построенных по сводной проверке
+const (
+	stepBuildTypeCheck = "build-typecheck"
+	stepSmokeTests     = "smoke-tests"
+	stepStaticAnalysis = "static-analysis"
+)
+
+// summarySteps представляет категории сводной проверки шагами pipeline.
+// Сборка и проверка типов объединяются в один шаг; пропущенные категории не попадают в шаги
+func summarySteps(summary *domain.ValidationSummary) []*domain.VerificationStep {
+	var steps []*domain.VerificationStep
+	newStep := func(name string, categories ...*domain.ValidationCategoryResult) *domain.VerificationStep {
+		step := &domain.VerificationStep{Name: name, Success: true, StartedAt: summary.StartedAt, CompletedAt: summary.CompletedAt}
+		var messages []string
+		for _, category := range categories {
+			if category.Status == domain.ValidationStatusFailed {
+				step.Success = false
+				messages = append(messages, category.Message)
+			}
+		}
+		if !step.Success {
+			step.Error = errors.New(strings.Join(messages, "; "))
+		}
+		return step
+	}
+
+	build := summary.Category(domain.ValidationCategoryBuild)
+	typeCheck := summary.Category(domain.ValidationCategoryTypeCheck)
+	var buildCategories []*domain.ValidationCategoryResult
+	for _, category := range []*domain.ValidationCategoryResult{typeCheck, build} {
+		if category != nil {
+			buildCategories = append(buildCategories, category)
+		}
+	}
+	if len(buildCategories) > 0 {
+		step := newStep(stepBuildTypeCheck, buildCategories...)
+		step.Result = buildCategories
+		steps = append(steps, step)
+	}
+
+	if tests := summary.Category(domain.ValidationCategoryTests); tests != nil && tests.Status != domain.ValidationStatusSkipped {
+		step := newStep(stepSmokeTests, tests)
+		step.Result = tests.Details
+		steps = append(steps, step)
+	}
+
+	if static := summary.Category(domain.ValidationCategoryStatic); static != nil && static.Status != domain.ValidationStatusSkipped {
+		step := newStep(stepStaticAnalysis, static)
+		// Отчет передается как есть, чтобы по нему можно было определить серьезность замечаний
+		step.Result = static.Details
+		steps = append(steps, step)
+	}
+	return steps
+}
+
 type stepFunc func(ctx context.Context, config *domain.VerificationConfig) (interface{}, error)
 
 func (s *Service) runStep(ctx context.Context, name string, config *domain.VerificationConfig, fn stepFunc) *domain.VerificationStep {
@@ -138,54 +201,6 @@ func (s *Service) runFormatStep(ctx context.Context, config *domain.Verification
 	return results, nil
 }
 
-func (s *Service) runBuildTypeCheckStep(ctx context.Context, config *domain.VerificationConfig) (interface{}, error) {
-	s.log.Info("Running build/typecheck step")
-
-	validation, err := s.buildService.ValidateProject(ctx, config.ProjectPath, config.Languages)
-	if err != nil {
-		return nil, fmt.Errorf("project validation failed: %w", err)
-	}
-
-	if !validation.Success {
-		return nil, fmt.Errorf("project validation failed for some languages")
-	}
-
-	return validation, nil
-}
-
-func (s *Service) runSmokeTestsStep(ctx context.Context, config *domain.VerificationConfig) (interface{}, error) {
-	s.log.Info("Running smoke tests step")
-
-	var allResults []*domain.TestResult
-
-	for _, language := range config.Languages {
-		results, err := s.testService.RunSmokeTests(ctx, config.ProjectPath, language)
-		if err != nil {
-			return nil, fmt.Errorf("smoke tests failed for %s: %w", language, err)
-		}
-
-		validation := s.testService.ValidateTestResults(results)
-		if !validation.Success {
-			return nil, fmt.Errorf("smoke tests failed for %s: %d tests failed", language, validation.FailedTests)
-		}
-
-		allResults = append(allResults, results...)
-	}
-
-	return allResults, nil
-}
-
-func (s *Service) runStaticAnalysisStep(ctx context.Context, config *domain.VerificationConfig) (interface{}, error) {
-	s.log.Info("Running static analysis step")
-
-	report, err := s.staticAnalyzer.AnalyzeProject(ctx, config.ProjectPath, config.Languages)
-	if err != nil {
-		return nil, fmt.Errorf("static analysis failed: %w", err)
-	}
-
-	return report, nil
-}
-
 func (s *Service) saveVerificationReport(ctx context.Context, result *domain.VerificationResult) error {
 	reportsDir := "tasks/reports"
 	if err := s.reportWriter.MkdirAll(reportsDir, 0o755); err != nil {
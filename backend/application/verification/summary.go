@@ -0,0 +1,278 @@
+package verification
+
+import (
+	"context"
+	"fmt"
+	"shotgun_code/domain"
+	"strings"
+	"time"
+)
+
+// ProjectValidator выполняет сводную проверку проекта: сборку, проверку типов,
+// тесты и статический анализ, и сводит результаты в один ValidationSummary
+type ProjectValidator struct {
+	log            domain.Logger
+	buildService   domain.IBuildService
+	testService    domain.ITestService
+	staticAnalyzer domain.IStaticAnalyzerService
+}
+
+// NewProjectValidator создает сервис сводной проверки проекта.
+// testService и staticAnalyzer могут быть nil - тогда их категории пропускаются
+func NewProjectValidator(
+	log domain.Logger,
+	buildService domain.IBuildService,
+	testService domain.ITestService,
+	staticAnalyzer domain.IStaticAnalyzerService,
+) *ProjectValidator {
+	return &ProjectValidator{
+		log:            log,
+		buildService:   buildService,
+		testService:    testService,
+		staticAnalyzer: staticAnalyzer,
+	}
+}
+
+// Validate запускает выбранные категории проверки. Ошибка возвращается, только если
+// проверку нельзя начать; провалы категорий отражаются в статусах результата.
+// Тесты пропускаются, если сборка или проверка типов не прошли
+func (v *ProjectValidator) Validate(ctx context.Context, config *domain.ValidationSummaryConfig) (*domain.ValidationSummary, error) {
+	if config == nil || config.ProjectPath == "" {
+		return nil, fmt.Errorf("project path is required")
+	}
+
+	categories, err := normalizeValidationCategories(config.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := config.Languages
+	if len(languages) == 0 {
+		languages, err = v.buildService.DetectLanguages(ctx, config.ProjectPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect languages: %w", err)
+		}
+	}
+
+	v.log.Info(fmt.Sprintf("Validating project at %s (languages: %v, categories: %v)", config.ProjectPath, languages, categories))
+
+	summary := &domain.ValidationSummary{
+		ProjectPath: config.ProjectPath,
+		Languages:   languages,
+		Categories:  make([]*domain.ValidationCategoryResult, 0, len(categories)),
+		StartedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+
+	for _, category := range categories {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		startTime := time.Now()
+		ctx := domain.WithCommandStep(ctx, "", string(category))
+		var result *domain.ValidationCategoryResult
+		switch category {
+		case domain.ValidationCategoryBuild:
+			result = v.validateBuild(ctx, config.ProjectPath, languages)
+		case domain.ValidationCategoryTypeCheck:
+			result = v.validateTypeCheck(ctx, config.ProjectPath, languages)
+		case domain.ValidationCategoryTests:
+			if buildFailed(summary) {
+				result = skippedCategory(category, "skipped because the build or type check failed")
+			} else {
+				result = v.validateTests(ctx, config.ProjectPath, languages, config.ChangedFiles)
+			}
+		case domain.ValidationCategoryStatic:
+			result = v.validateStatic(ctx, config.ProjectPath, languages)
+		}
+		result.Duration = time.Since(startTime).Seconds()
+		summary.Categories = append(summary.Categories, result)
+	}
+
+	summary.Success = true
+	summary.Status = domain.ValidationStatusPassed
+	for _, result := range summary.Categories {
+		if result.Status == domain.ValidationStatusFailed {
+			summary.Success = false
+			summary.Status = domain.ValidationStatusFailed
+			break
+		}
+	}
+	summary.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+
+	v.log.Info(fmt.Sprintf("Project validation completed with status: %s", summary.Status))
+	return summary, nil
+}
+
+// validateBuild собирает проект для каждого языка
+func (v *ProjectValidator) validateBuild(ctx context.Context, projectPath string, languages []string) *domain.ValidationCategoryResult {
+	details := make(map[string]*domain.BuildResult, len(languages))
+	var failed []string
+	for _, language := range languages {
+		result, err := v.buildService.Build(ctx, projectPath, language, domain.BuildTarget{})
+		if err != nil {
+			result = &domain.BuildResult{Language: language, ProjectPath: projectPath, Error: err.Error()}
+		}
+		details[language] = result
+		if !result.Success {
+			failed = append(failed, language)
+		}
+	}
+	return languageCategory(domain.ValidationCategoryBuild, "build failed for", failed, details)
+}
+
+// validateTypeCheck проверяет типы для каждого языка
+func (v *ProjectValidator) validateTypeCheck(ctx context.Context, projectPath string, languages []string) *domain.ValidationCategoryResult {
+	details := make(map[string]*domain.TypeCheckResult, len(languages))
+	var failed []string
+	for _, language := range languages {
+		result, err := v.buildService.TypeCheck(ctx, projectPath, language, domain.BuildTarget{})
+		if err != nil {
+			result = &domain.TypeCheckResult{Language: language, ProjectPath: projectPath, Error: err.Error()}
+		}
+		details[language] = result
+		if !result.Success {
+			failed = append(failed, language)
+		}
+	}
+	return languageCategory(domain.ValidationCategoryTypeCheck, "type check failed for", failed, details)
+}
+
+// validateTests запускает тесты, затронутые изменениями, или smoke тесты, если изменения не заданы
+func (v *ProjectValidator) validateTests(ctx context.Context, projectPath string, languages, changedFiles []string) *domain.ValidationCategoryResult {
+	if v.testService == nil {
+		return skippedCategory(domain.ValidationCategoryTests, "test service is not available")
+	}
+
+	details := make(map[string]*domain.TestValidationResult, len(languages))
+	var failed []string
+	totalTests, failedTests := 0, 0
+	for _, language := range languages {
+		var results []*domain.TestResult
+		var err error
+		if len(changedFiles) > 0 {
+			config := &domain.TestConfig{Language: language, ProjectPath: projectPath, Scope: domain.TestScopeAffected}
+			results, err = v.testService.RunTargetedTests(ctx, config, changedFiles)
+		} else {
+			results, err = v.testService.RunSmokeTests(ctx, projectPath, language)
+		}
+		if err != nil {
+			v.log.Warning(fmt.Sprintf("Tests failed to run for %s: %v", language, err))
+			failed = append(failed, language)
+			continue
+		}
+
+		validation := v.testService.ValidateTestResults(results)
+		details[language] = validation
+		totalTests += validation.TotalTests
+		failedTests += validation.FailedTests
+		if !validation.Success {
+			failed = append(failed, language)
+		}
+	}
+
+	result := languageCategory(domain.ValidationCategoryTests, "tests failed for", failed, details)
+	if result.Status == domain.ValidationStatusPassed {
+		result.Message = fmt.Sprintf("%d tests passed", totalTests)
+	} else if failedTests > 0 {
+		result.Message = fmt.Sprintf("%d of %d tests failed (%s)", failedTests, totalTests, strings.Join(failed, ", "))
+	}
+	return result
+}
+
+// validateStatic запускает статический анализ; категория проваливается при наличии ошибок
+func (v *ProjectValidator) validateStatic(ctx context.Context, projectPath string, languages []string) *domain.ValidationCategoryResult {
+	result := &domain.ValidationCategoryResult{Category: domain.ValidationCategoryStatic}
+	if v.staticAnalyzer == nil {
+		return skippedCategory(domain.ValidationCategoryStatic, "static analyzer is not available")
+	}
+
+	report, err := v.staticAnalyzer.AnalyzeProject(ctx, projectPath, languages)
+	if err != nil {
+		result.Status = domain.ValidationStatusFailed
+		result.Message = fmt.Sprintf("static analysis failed: %v", err)
+		return result
+	}
+
+	result.Details = report
+	result.Status = domain.ValidationStatusPassed
+	if report.Summary != nil {
+		result.Message = fmt.Sprintf("%d errors, %d warnings", report.Summary.TotalErrors, report.Summary.TotalWarnings)
+		if report.Summary.TotalErrors > 0 {
+			result.Status = domain.ValidationStatusFailed
+		}
+	}
+	return result
+}
+
+// languageCategory собирает результат категории, проверяемой по языкам
+func languageCategory(category, failurePrefix string, failed []string, details interface{}) *domain.ValidationCategoryResult {
+	result := &domain.ValidationCategoryResult{
+		Category: category,
+		Status:   domain.ValidationStatusPassed,
+		Details:  details,
+	}
+	if len(failed) > 0 {
+		result.Status = domain.ValidationStatusFailed
+		result.Message = fmt.Sprintf("%s: %s", failurePrefix, strings.Join(failed, ", "))
+	}
+	return result
+}
+
+// skippedCategory возвращает результат пропущенной категории
+func skippedCategory(category, reason string) *domain.ValidationCategoryResult {
+	return &domain.ValidationCategoryResult{
+		Category: category,
+		Status:   domain.ValidationStatusSkipped,
+		Message:  reason,
+	}
+}
+
+// buildFailed сообщает, провалились ли сборка или проверка типов
+func buildFailed(summary *domain.ValidationSummary) bool {
+	for _, name := range []string{domain.ValidationCategoryBuild, domain.ValidationCategoryTypeCheck} {
+		if result := summary.Category(name); result != nil && result.Status == domain.ValidationStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeValidationCategories проверяет категории и упорядочивает их по порядку выполнения;
+// пустой список означает все категории
+func normalizeValidationCategories(categories []string) ([]string, error) {
+	all := domain.AllValidationCategories()
+	if len(categories) == 0 {
+		return all, nil
+	}
+
+	requested := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if category == "" {
+			continue
+		}
+		known := false
+		for _, name := range all {
+			if name == category {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unsupported validation category: %s", category)
+		}
+		requested[category] = true
+	}
+
+	result := make([]string, 0, len(requested))
+	for _, name := range all {
+		if requested[name] {
+			result = append(result, name)
+		}
+	}
+	if len(result) == 0 {
+		return all, nil
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package verification
+
+import (
+	"context"
+	"testing"
+
+	"shotgun_code/domain"
+	"shotgun_code/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectValidator_AggregatesCategories(t *testing.T) {
+	buildService := &testutils.MockBuildService{}
+	testService := &testutils.MockTestService{}
+	staticAnalyzer := &testutils.MockStaticAnalyzerService{}
+
+	buildService.On("DetectLanguages", mock.Anything, "/proj").Return([]string{"go"}, nil)
+	buildService.On("Build", mock.Anything, "/proj", "go", domain.BuildTarget{}).Return(&domain.BuildResult{Success: true}, nil)
+	buildService.On("TypeCheck", mock.Anything, "/proj", "go", domain.BuildTarget{}).Return(&domain.TypeCheckResult{Success: true}, nil)
+	testService.On("RunTargetedTests", mock.Anything, mock.Anything, []string{"main.go"}).Return([]*domain.TestResult{{Success: true}}, nil)
+	testService.On("ValidateTestResults", mock.Anything).Return(&domain.TestValidationResult{Success: true, TotalTests: 1, PassedTests: 1})
+	staticAnalyzer.On("AnalyzeProject", mock.Anything, "/proj", []string{"go"}).Return(&domain.StaticAnalysisReport{
+		Summary: &domain.StaticAnalysisReportSummary{TotalErrors: 2, TotalWarnings: 1},
+	}, nil)
+
+	validator := NewProjectValidator(&domain.NoopLogger{}, buildService, testService, staticAnalyzer)
+	summary, err := validator.Validate(context.Background(), &domain.ValidationSummaryConfig{
+		ProjectPath:  "/proj",
+		ChangedFiles: []string{"main.go"},
+	})
+	require.NoError(t, err)
+
+	assert.False(t, summary.Success)
+	assert.Equal(t, domain.ValidationStatusFailed, summary.Status)
+	assert.Equal(t, []string{"go"}, summary.Languages)
+	require.Len(t, summary.Categories, 4)
+	assert.Equal(t, domain.ValidationStatusPassed, summary.Category(domain.ValidationCategoryBuild).Status)
+	assert.Equal(t, domain.ValidationStatusPassed, summary.Category(domain.ValidationCategoryTests).Status)
+	static := summary.Category(domain.ValidationCategoryStatic)
+	assert.Equal(t, domain.ValidationStatusFailed, static.Status)
+	assert.Equal(t, "2 errors, 1 warnings", static.Message)
+	testService.AssertNotCalled(t, "RunSmokeTests", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectValidator_RespectsCategoriesAndSkipsTestsAfterBuildFailure(t *testing.T) {
+	buildService := &testutils.MockBuildService{}
+	testService := &testutils.MockTestService{}
+	buildService.On("Build", mock.Anything, "/proj", "go", domain.BuildTarget{}).Return(&domain.BuildResult{Error: "exit status 1"}, nil)
+
+	validator := NewProjectValidator(&domain.NoopLogger{}, buildService, testService, nil)
+	summary, err := validator.Validate(context.Background(), &domain.ValidationSummaryConfig{
+		ProjectPath: "/proj",
+		Languages:   []string{"go"},
+		Categories:  []string{"tests", "build"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, summary.Categories, 2)
+	assert.Equal(t, domain.ValidationCategoryBuild, summary.Categories[0].Category)
+	assert.Equal(t, domain.ValidationStatusFailed, summary.Categories[0].Status)
+	assert.Equal(t, domain.ValidationStatusSkipped, summary.Categories[1].Status)
+	assert.Equal(t, domain.ValidationStatusFailed, summary.Status)
+	buildService.AssertNotCalled(t, "TypeCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	testService.AssertNotCalled(t, "RunSmokeTests", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProjectValidator_RejectsUnknownCategory(t *testing.T) {
+	validator := NewProjectValidator(&domain.NoopLogger{}, &testutils.MockBuildService{}, nil, nil)
+
+	_, err := validator.Validate(context.Background(), &domain.ValidationSummaryConfig{
+		ProjectPath: "/proj",
+		Languages:   []string{"go"},
+		Categories:  []string{"lint"},
+	})
+	assert.ErrorContains(t, err, "unsupported validation category")
+}
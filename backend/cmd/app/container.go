@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"shotgun_code/application"
 	appai "shotgun_code/application/ai"
@@ -18,6 +17,7 @@ import (
 	"shotgun_code/application/repair"
 	"shotgun_code/application/router"
 	"shotgun_code/application/sbom"
+	"shotgun_code/application/semantic"
 	"shotgun_code/application/settings"
 	"shotgun_code/application/symbol"
 	"shotgun_code/application/taskflow"
@@ -27,6 +27,7 @@ import (
 	domainanalysis "shotgun_code/domain/analysis"
 	"shotgun_code/handlers"
 	"shotgun_code/infrastructure/ai"
+	"shotgun_code/infrastructure/aiaudit"
 	"shotgun_code/infrastructure/analyzers"
 	"shotgun_code/infrastructure/applyengine"
 	"shotgun_code/infrastructure/contextbuilder"
@@ -39,6 +40,7 @@ import (
 	"shotgun_code/infrastructure/fswatcher"
 	"shotgun_code/infrastructure/git"
 	"shotgun_code/infrastructure/memory"
+	"shotgun_code/infrastructure/policystore"
 	"shotgun_code/infrastructure/projectstructure"
 	"shotgun_code/infrastructure/reportfs"
 	"shotgun_code/infrastructure/sbomlicensing"
@@ -85,7 +87,7 @@ type AppContainer struct {
 	SettingsService       *settings.Service
 	AIService             *appai.Service
 	ContextAnalysis       domain.ContextAnalyzer
-	SymbolGraph           *symbol.Service
+	SymbolGraph           *initmanager.LazyService[*symbol.Service]
 	TestService           domain.ITestService
 	StaticAnalyzerService domain.IStaticAnalyzerService
 	SBOMService           *sbom.Service
@@ -97,6 +99,8 @@ type AppContainer struct {
 	DiffService           *diff.Service
 	BuildService          domain.IBuildService
 	ExportService         *export.Service
+	SafeMode              *domain.SafeMode
+	LanguageScope         *domain.LanguageScope
 
 	// Unified internal services (new architecture)
 	ContextService *contextservice.Service
@@ -124,13 +128,11 @@ type AppContainer struct {
 	SmartContextService *rag.SmartContextService
 	QwenTaskService     *appai.QwenTaskService
 
-	// Semantic Search Services
-	SymbolIndex       domainanalysis.SymbolIndex
+	// Semantic Search Services. Semantic is nil when no embedding provider is configured.
 	EmbeddingProvider domain.EmbeddingProvider
-	VectorStore       domain.VectorStore
-	SemanticSearch    domain.SemanticSearchService
-	RAGService        domain.RAGService
+	Semantic          *initmanager.LazyService[*SemanticServices]
 	SemanticHandler   *handlers.SemanticHandler
+	Reindexer         *semantic.Reindexer
 
 	// Handlers (new architecture)
 	ProjectHandler  *handlers.ProjectHandler
@@ -150,7 +152,6 @@ type AppContainer struct {
 	testServiceOnce           sync.Once
 	staticAnalyzerServiceOnce sync.Once
 	sbomServiceOnce           sync.Once
-	symbolGraphOnce           sync.Once
 
 	// Lazy service manager for coordinated lifecycle management
 	lazyManager *initmanager.LazyServiceManager
@@ -164,14 +165,22 @@ type AppContainer struct {
 
 // NewContainer creates and wires up all the application dependencies.
 func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt string) (*AppContainer, error) {
-	c := &AppContainer{}
-	var err error
-
 	// Bridge for Wails (Logger and EventBus)
 	bridge := wailsbridge.New(ctx)
-	c.Bridge = bridge
-	c.Log = bridge
-	c.Bus = bridge
+	c := &AppContainer{Bridge: bridge, Log: bridge, Bus: bridge}
+	return wireContainer(ctx, c, embeddedIgnoreGlob, defaultCustomPrompt)
+}
+
+// NewHeadlessContainer wires the same dependencies without the Wails runtime,
+// logging and emitting events through log and bus. Bridge stays nil.
+func NewHeadlessContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt string, log domain.Logger, bus domain.EventBus) (*AppContainer, error) {
+	c := &AppContainer{Log: log, Bus: bus}
+	return wireContainer(ctx, c, embeddedIgnoreGlob, defaultCustomPrompt)
+}
+
+// wireContainer creates all services on top of the container's logger and event bus
+func wireContainer(ctx context.Context, c *AppContainer, embeddedIgnoreGlob, defaultCustomPrompt string) (*AppContainer, error) {
+	var err error
 
 	// Repositories and Infrastructure
 	c.SettingsRepo, err = settingsfs.New(c.Log, embeddedIgnoreGlob, defaultCustomPrompt)
@@ -182,11 +191,21 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 	c.GitRepo = git.New(c.Log)
 	c.TreeBuilder = fsscanner.New(c.SettingsRepo, c.Log)
 	c.ContextSplitter = textutils.NewContextSplitter(c.Log)
-	c.Watcher, err = fswatcher.New(ctx, c.Bus)
+	if c.Bridge != nil {
+		c.Watcher, err = fswatcher.New(ctx, c.Bus)
+	} else {
+		c.Watcher, err = fswatcher.NewWithLogger(c.Log, c.Bus)
+	}
 	if err != nil {
 		return nil, err
 	}
-	c.CommandRunner = execinfra.NewCommandRunnerImpl(c.Log)
+	commandRunner := execinfra.NewCommandRunnerImpl(c.Log)
+	commandRunner.SetLimits(c.SettingsRepo.GetCommandLimits())
+	if c.Bridge == nil {
+		// Headless mode serves external requests: only known tools may run
+		commandRunner.SetAllowedCommands(execinfra.DefaultAllowedCommands)
+	}
+	c.CommandRunner = commandRunner
 
 	// Application Services
 	modelFetchers := createModelFetchers(ctx, c.Log, c.SettingsRepo)
@@ -196,6 +215,7 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 	}
 	// Connect watcher to settings changes
 	c.SettingsService.OnIgnoreRulesChanged(c.Watcher.RefreshAndRescan)
+	c.SettingsService.SetCommandLimitsListener(commandRunner.SetLimits)
 
 	// AI Service needs to be created before context service
 	providerRegistry := createProviderRegistry(c.Log, c.SettingsService)
@@ -230,6 +250,10 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 	if homeErr != nil {
 		return nil, fmt.Errorf("failed to determine user home directory: %w", homeErr)
 	}
+	// Record every AI request to the audit trail
+	auditStore := aiaudit.NewFileStore(filepath.Join(homeDir, ".shotgun-code", "audit", "ai-requests.jsonl"))
+	c.AIService.SetAuditLogger(appai.NewAuditLogger(auditStore, c.SettingsService, c.Log))
+
 	contextDir := filepath.Join(homeDir, ".shotgun-code", "contexts")
 	if mkErr := os.MkdirAll(contextDir, 0o755); mkErr != nil {
 		return nil, fmt.Errorf("failed to create context directory: %w", mkErr)
@@ -237,7 +261,6 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 
 	// Create comment stripper for code preprocessing
 	commentStripper := textutils.NewCommentStripper(c.Log)
-	_ = commentStripper  // Will be used by ContextService internally
 	_ = opaService       // Will be used by ContextService internally
 	_ = pathProvider     // Will be used by ProjectService internally
 	_ = fileSystemWriter // Will be used by ContextService internally
@@ -252,6 +275,8 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 	if err != nil {
 		return nil, fmt.Errorf("failed to create context service: %w", err)
 	}
+	c.ContextService.SetContentOptimizer(textutils.NewDomainContentOptimizer(analyzers.NewAnalyzerRegistry(), commentStripper))
+	c.ContextService.SetOptimizationProfileSource(c.SettingsRepo)
 
 	// ContextService implements ContextRepository interface
 	c.ContextRepository = c.ContextService
@@ -283,7 +308,21 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 	// Create import graph builders (currently no implementation, using nil map)
 	importGraphBuilders := make(map[string]domain.ImportGraphBuilder)
 
-	c.SymbolGraph = symbol.NewService(c.Log, symbolGraphBuilders, importGraphBuilders)
+	// Memory-heavy services are created on first use and unloaded when idle
+	c.lazyManager = initmanager.NewLazyServiceManager()
+
+	// Symbol graph caches built graphs; unloading drops the cache
+	c.SymbolGraph = initmanager.NewLazyService(func(context.Context) (*symbol.Service, error) {
+		svc := symbol.NewService(c.Log, symbolGraphBuilders, importGraphBuilders)
+		if c.AnalysisContainer != nil {
+			svc.SetReferenceFinder(c.AnalysisContainer.GetReferenceFinder())
+		}
+		return svc, nil
+	}).WithCleanup(func(s *symbol.Service) error {
+		s.ClearCache()
+		return nil
+	})
+	c.lazyManager.Register("symbolgraph", c.SymbolGraph)
 
 	// Create CallStack Analyzer and Smart Context Service for Qwen integration
 	callStackAnalyzer := symbolgraph.NewCallStackAnalyzerAdapter(c.Log)
@@ -294,10 +333,14 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 		callStackAnalyzer,
 	)
 
+	c.LanguageScope = domain.NewLanguageScope(c.SettingsRepo.GetEnabledLanguages())
+
 	// Create TestService with lazy initialization
 	c.testServiceOnce.Do(func() {
 		testEngine := testengine.NewTestEngine(c.Log, goSymbolGraphBuilder)
-		testEngine.RegisterTestRunner("go", testengine.NewGoTestRunner(c.Log))
+		goTestRunner := testengine.NewGoTestRunner(c.Log)
+		goTestRunner.SetExecutor(commandRunner.Executor())
+		testEngine.RegisterTestRunner("go", goTestRunner)
 		// testEngine.RegisterTestRunner("typescript", testengine.NewTypeScriptTestRunner(c.Log))
 		// testEngine.RegisterTestRunner("java", testengine.NewJavaTestRunner(c.Log))
 
@@ -317,6 +360,7 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 	staticAnalyzerEngine.RegisterAnalyzer(staticanalyzer.NewErrorProneAnalyzer(c.Log))
 	staticAnalyzerEngine.RegisterAnalyzer(staticanalyzer.NewRuffAnalyzer(c.Log))
 	staticAnalyzerEngine.RegisterAnalyzer(staticanalyzer.NewClangTidyAnalyzer(c.Log))
+	staticAnalyzerEngine.SetExecutor(commandRunner.Executor())
 	c.StaticAnalyzerService = analysis.NewStaticAnalyzerService(c.Log, staticAnalyzerEngine)
 
 	// Create SBOM infrastructure components
@@ -327,9 +371,14 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 	c.SBOMService = sbom.NewService(c.Log, sbomGenerator, vulnScanner, licenseScanner, sbomFileStatProvider)
 
 	c.RepairService = repair.NewService(c.Log, c.CommandRunner)
+	// Custom repair rules and guardrail policies survive restarts
+	if repairService, ok := c.RepairService.(*repair.Service); ok {
+		repairService.SetRuleStore(policystore.NewRepairRuleStore(filepath.Join(homeDir, ".shotgun-code", "repair-rules.json")))
+	}
 
-	// Create TaskflowRepository
-	taskflowRepo := taskflowrepo.NewFileSystemTaskflowRepository("tasks/status.json")
+	// Create TaskflowRepository; plan and status paths move to the selected project via SetProjectRoot
+	taskflowConfig := domain.NewTaskflowConfig("")
+	taskflowRepo := taskflowrepo.NewFileSystemTaskflowRepository(taskflowConfig.StatusPath)
 
 	// Create RouterPlannerService
 	planner := router.NewPlannerService(c.Log, c.BuildService, c.TestService, c.StaticAnalyzerService, c.RepairService)
@@ -338,9 +387,15 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 	guardrailOPAService := policy.NewOPAService(c.Log)
 	guardrailFileStatProvider := &OSFileStatProvider{}
 	c.GuardrailService = guardrails.NewService(c.Log, guardrailOPAService, guardrailFileStatProvider)
+	if guardrailService, ok := c.GuardrailService.(*guardrails.ServiceImpl); ok {
+		guardrailService.SetPolicyStore(policystore.NewGuardrailStore(filepath.Join(homeDir, ".shotgun-code", "guardrails.json")))
+	}
 
 	// Create TaskflowService with injected dependencies
-	c.TaskflowService = taskflow.NewService(c.Log, planner, c.RouterLLMService, c.GuardrailService, taskflowRepo, c.GitRepo)
+	c.TaskflowService = taskflow.NewService(c.Log, taskflowConfig, planner, c.RouterLLMService, c.GuardrailService, taskflowRepo, c.GitRepo)
+	if publisher, ok := c.TaskflowService.(domain.EventBusSetter); ok {
+		publisher.SetEventBus(c.Bus)
+	}
 
 	// ⚠️ CRITICAL: Update GuardrailService with TaskTypeProvider to resolve circular dependency
 	// This MUST be called AFTER TaskflowService is created
@@ -385,6 +440,7 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 	}
 
 	c.ApplyService = diff.NewApplyService(c.Log, applyConfig, applyEngine, formatterMap, importFixerMap)
+	c.ApplyService.SetGuardrails(c.GuardrailService)
 
 	// Создаем движок diff
 	diffEngine := diffengine.NewDiffEngine(c.Log)
@@ -392,7 +448,13 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 
 	// Создаем build pipeline
 	buildPipeline := buildpipeline.NewBuildPipeline(c.Log)
-	c.BuildService = build.NewService(c.Log, buildPipeline)
+	buildPipeline.SetExecutor(commandRunner.Executor())
+	buildService := build.NewService(c.Log, buildPipeline)
+	// Языки определяются общим детектором; его кэш сбрасывается по событиям watcher
+	languageDetector := projectstructure.SharedLanguageDetector()
+	buildService.SetLanguageDetector(languageDetector)
+	c.Watcher.OnFilesChanged(languageDetector.OnFilesChanged)
+	c.BuildService = buildService
 
 	// new: wire PDF and ZIP implementations
 	pdfGen := pdfgen.NewGofpdfGenerator(c.Log)
@@ -411,6 +473,9 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 		return nil, fmt.Errorf("failed to initialize report repository: %w", err)
 	}
 	c.ReportService = export.NewReportService(c.Log, reportRepo)
+	if repairService, ok := c.RepairService.(*repair.Service); ok {
+		repairService.SetReportRepository(reportRepo)
+	}
 
 	// Initialize RouterLLMService
 	routerLLMConfig := router.LLMConfig{
@@ -453,12 +518,22 @@ func NewContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt s
 		return nil, fmt.Errorf("failed to initialize task protocol services: %w", err)
 	}
 
-	// Initialize lazy service manager for memory optimization
-	c.lazyManager = initmanager.NewLazyServiceManager()
+	// Safe mode blocks every service that writes project files
+	c.SafeMode = domain.NewSafeMode(c.SettingsRepo.GetSafeMode())
+	for _, svc := range []interface{}{c.ApplyService, c.TaskflowService, c.RepairService, c.CorrectionEngine} {
+		if setter, ok := svc.(domain.SafeModeSetter); ok {
+			setter.SetSafeMode(c.SafeMode)
+		}
+	}
+	c.SettingsService.SetSafeModeListener(c.SafeMode.SetEnabled)
 
-	// Note: Services are currently eagerly initialized for compatibility
-	// Future enhancement: wrap heavy services in LazyService[T] and register with manager
-	// Example: c.lazyManager.Register("symbolgraph", lazySymbolGraphService)
+	// Enabled languages scope analyzers, test runners and indexers
+	for _, svc := range []interface{}{c.StaticAnalyzerService, c.TestService} {
+		if setter, ok := svc.(domain.LanguageScopeSetter); ok {
+			setter.SetLanguageScope(c.LanguageScope)
+		}
+	}
+	c.SettingsService.SetEnabledLanguagesListener(c.LanguageScope.SetLanguages)
 
 	// Start periodic cleanup of unused services (runs every 5 minutes)
 	// Note: This goroutine will be stopped when lazyManager is shutdown
@@ -507,79 +582,51 @@ func (c *AppContainer) initializeSemanticSearch() error {
 	}
 	dataDir := filepath.Join(homeDir, ".shotgun-code", "embeddings")
 
-	// Create analyzer registry for symbol extraction
-	analyzerRegistry := analyzers.NewAnalyzerRegistry()
-
-	// Create symbol index with SQLite caching for incremental indexing
-	symbolCacheDir := filepath.Join(dataDir, "symbol_cache")
-	cachedSymbolIndex, err := analyzers.NewCachedSymbolIndex(analyzerRegistry, symbolCacheDir)
-	if err != nil {
-		c.Log.Warning(fmt.Sprintf("Failed to create cached symbol index, falling back to in-memory: %v", err))
-		c.SymbolIndex = analyzers.NewSymbolIndex(analyzerRegistry)
-	} else {
-		c.SymbolIndex = cachedSymbolIndex
-	}
-
-	// Create vector store (SQLite-based)
-	vectorStore, err := embeddings.NewSQLiteVectorStore(dataDir, c.Log)
-	if err != nil {
-		return fmt.Errorf("failed to create vector store: %w", err)
-	}
-	c.VectorStore = vectorStore
-
-	// Create embedding provider (OpenAI by default)
-	// Get API key from settings
 	settings, err := c.SettingsService.GetSettingsDTO()
 	if err != nil {
-		c.Log.Warning("Failed to get settings for embedding provider: " + err.Error())
+		c.Log.Warning("Failed to get settings for semantic search: " + err.Error())
 	}
 
-	apiKey := ""
-	if settings.OpenAIAPIKey != "" {
-		apiKey = settings.OpenAIAPIKey
-	}
+	// Create embedding provider (OpenAI by default)
 
-	if apiKey != "" {
-		embeddingProvider, err := embeddings.NewOpenAIEmbeddingProvider(
-			apiKey,
-			domain.EmbeddingModelOpenAI3S, // Use small model by default
-			c.Log,
-		)
-		if err != nil {
-			c.Log.Warning("Failed to create embedding provider: " + err.Error())
-		} else {
-			c.EmbeddingProvider = embeddingProvider
+	switch settings.EmbeddingProvider {
+	case domain.EmbeddingProviderFake:
+		c.EmbeddingProvider = embeddings.NewFakeEmbeddingProvider(0)
+		c.Log.Warning("Using fake embedding provider: semantic search results are not meaningful")
+	default:
+		if settings.OpenAIAPIKey != "" {
+			embeddingProvider, err := embeddings.NewOpenAIEmbeddingProvider(
+				settings.OpenAIAPIKey,
+				domain.ResolveEmbeddingModel(settings.EmbeddingModel),
+				c.Log,
+			)
+			if err != nil {
+				c.Log.Warning("Failed to create embedding provider: " + err.Error())
+			} else {
+				c.EmbeddingProvider = embeddingProvider
+			}
 		}
 	}
 
-	// Create semantic search service (only if embedding provider is available)
+	// Semantic search services open the symbol cache and vector store on first use
 	if c.EmbeddingProvider != nil {
-		// Create code chunker for semantic indexing
-		chunker := &codeChunkerAdapter{impl: embeddings.NewCodeChunker(embeddings.DefaultChunkerConfig())}
-
-		c.SemanticSearch = rag.NewSemanticSearchService(
-			c.EmbeddingProvider,
-			c.VectorStore,
-			c.SymbolIndex,
-			c.Log,
-			chunker,
-		)
-
-		// Create RAG service
-		c.RAGService = rag.NewService(
-			c.SemanticSearch,
-			c.EmbeddingProvider,
-			c.Log,
-		)
-
-		// Create semantic handler
-		c.SemanticHandler = handlers.NewSemanticHandler(
-			c.SemanticSearch,
-			c.RAGService,
-			c.Log,
-		)
-
-		c.Log.Info("Semantic search services initialized successfully")
+		vectorStoreKind := settings.VectorStore
+		metric := domain.ResolveSimilarityMetric(settings.SimilarityMetric, c.EmbeddingProvider.GetModelInfo().Model)
+		c.Log.Info(fmt.Sprintf("Semantic search compares embeddings by %s similarity", metric))
+		c.Semantic = initmanager.NewLazyService(func(context.Context) (*SemanticServices, error) {
+			services, err := newSemanticServices(dataDir, vectorStoreKind, metric, domain.ChunkerConfig{}, c.EmbeddingProvider, c.Log)
+			if err == nil {
+				services.SetLanguageScope(c.LanguageScope)
+				services.SetReranker(semantic.NewLLMReranker(c.AIService.GenerateCode))
+			}
+			return services, err
+		}).WithCleanup((*SemanticServices).Close).WithInUse((*SemanticServices).Busy)
+		c.lazyManager.Register("semanticsearch", c.Semantic)
+
+		c.SemanticHandler = handlers.NewSemanticHandler(c.semanticServices, c.Log)
+		c.SettingsService.SetEmbeddingModelListener(c.setEmbeddingModel)
+
+		c.Log.Info("Semantic search services configured")
 	} else {
 		c.Log.Warning("Semantic search disabled: no embedding provider configured (set OpenAI API key)")
 	}
@@ -595,22 +642,37 @@ func (c *AppContainer) initializeHandlers() error {
 			return analyzers.NewAnalyzerRegistry()
 		},
 		SymbolIndexFactory: func(registry domainanalysis.AnalyzerRegistry) domainanalysis.SymbolIndex {
-			return analyzers.NewSymbolIndex(registry)
+			symbolIndex := analyzers.NewSymbolIndex(registry)
+			symbolIndex.SetLanguageScope(c.LanguageScope)
+			return symbolIndex
 		},
 		CallGraphFactory: func(registry domainanalysis.AnalyzerRegistry) domain.CallGraphBuilder {
-			return &callGraphAdapter{impl: analyzers.NewCallGraphBuilder(registry)}
+			builder := analyzers.NewCallGraphBuilder(registry)
+			builder.SetPreciseGoAnalysis(c.SettingsRepo.GetPreciseGoAnalysis())
+			builder.SetLanguageScope(c.LanguageScope)
+			return &callGraphAdapter{impl: builder}
 		},
 		GitContextFactory: func(projectRoot string) domain.GitContextBuilder {
 			return &gitContextAdapter{impl: git.NewContextBuilder(projectRoot)}
 		},
 		ContextMemoryFactory: func(contextDir string) (domain.ContextMemory, error) {
-			return memory.NewContextMemory(contextDir)
+			cm, err := memory.NewContextMemoryWithRetention(contextDir, memory.DefaultRetentionPolicy())
+			if err != nil {
+				return nil, err
+			}
+			if c.EmbeddingProvider != nil {
+				cm.SetEmbeddingProvider(c.EmbeddingProvider)
+			}
+			return cm, nil
 		},
 		ProjectStructureFactory: func() domain.ProjectStructureDetector {
 			return &projectStructureAdapter{impl: projectstructure.NewDetector()}
 		},
-		ReferenceFinderFactory: func(registry domainanalysis.AnalyzerRegistry) domain.ReferenceFinder {
-			return &referenceFinderAdapter{impl: analyzers.NewReferenceFinder(registry)}
+		ReferenceFinderFactory: func(registry domainanalysis.AnalyzerRegistry, symbolIndex domainanalysis.SymbolIndex) domain.ReferenceFinder {
+			finder := analyzers.NewReferenceFinder(registry)
+			finder.SetSymbolIndex(symbolIndex)
+			finder.SetPreciseGoAnalysis(c.SettingsRepo.GetPreciseGoAnalysis())
+			return &referenceFinderAdapter{impl: finder}
 		},
 	}
 	c.AnalysisContainer = analysis.NewContainer(c.Log, analysisConfig)
@@ -628,11 +690,18 @@ func (c *AppContainer) initializeHandlers() error {
 	c.ToolExecutor.SetContextMemory(c.AnalysisContainer.GetContextMemory())
 
 	// Wire semantic search if available
-	if c.SemanticSearch != nil {
+	if c.Semantic != nil {
 		// Create adapter for SemanticSearcher interface
-		c.ToolExecutor.SetSemanticSearch(&semanticSearchAdapter{service: c.SemanticSearch})
+		c.ToolExecutor.SetSemanticSearch(&semanticSearchAdapter{container: c})
 	}
 
+	// Re-index changed files once per debounced watcher batch; the semantic
+	// index is updated only once something has loaded it
+	c.Reindexer = semantic.NewReindexer(c.Log, c.Bus, c.loadedSemanticSearch, c.AnalysisContainer.GetCallGraph)
+	c.Reindexer.SetEnabled(c.SettingsRepo.GetBackgroundIndexing())
+	c.Watcher.OnFilesChanged(c.Reindexer.Schedule)
+	c.SettingsService.SetBackgroundIndexingListener(c.Reindexer.SetEnabled)
+
 	// Project Handler - delegates to ProjectService
 	c.ProjectHandler = handlers.NewProjectHandler(
 		c.Log,
@@ -657,6 +726,7 @@ func (c *AppContainer) initializeHandlers() error {
 		c.ContextAnalysis,
 		c.ToolExecutor,
 	)
+	c.AIHandler.SetEventBus(c.Bus)
 
 	// Analysis Handler
 	c.AnalysisHandler = handlers.NewAnalysisHandler(
@@ -665,7 +735,7 @@ func (c *AppContainer) initializeHandlers() error {
 		c.StaticAnalyzerService,
 		c.BuildService,
 		c.SBOMService,
-		c.SymbolGraph,
+		c.SymbolGraph.Get,
 	)
 
 	// Settings Handler
@@ -698,6 +768,7 @@ func (c *AppContainer) initializeHandlers() error {
 		c.Log,
 		c.QwenTaskService,
 	)
+	c.QwenHandler.SetRequestTracker(c.AIHandler)
 
 	return nil
 }
@@ -911,7 +982,7 @@ func initializeTaskProtocolServices(c *AppContainer) error {
 	)
 
 	// Create VerificationPipelineService with Task Protocol integration
-	formatterService := export.NewFormatterService(c.Log, &CommandRunnerImpl{})
+	formatterService := export.NewFormatterService(c.Log, &CommandRunnerImpl{Timeout: executil.DefaultCommandTimeout})
 	c.VerificationPipelineService = verification.NewService(
 		c.Log,
 		c.BuildService,
@@ -921,6 +992,28 @@ func initializeTaskProtocolServices(c *AppContainer) error {
 		&OSFileSystemWriter{},
 		c.TaskProtocolService,
 	)
+	c.VerificationPipelineService.SetVulnerabilityScanner(c.SBOMService)
+	if counter, ok := c.AnalysisContainer.GetCallGraph().(interface {
+		CountDependencyCycles(projectRoot string) (int, error)
+	}); ok {
+		c.VerificationPipelineService.SetDependencyCycleCounter(counter.CountDependencyCycles)
+	}
+	if gated, ok := c.TaskflowService.(interface {
+		SetVerifier(taskflow.ProjectVerifier)
+	}); ok {
+		gated.SetVerifier(c.VerificationPipelineService)
+	}
+	if gates, ok := c.TaskflowService.(interface {
+		SetVerificationGates(map[string]domain.VerificationGate)
+	}); ok {
+		gates.SetVerificationGates(c.SettingsRepo.GetVerificationGates())
+		c.SettingsService.SetVerificationGatesListener(gates.SetVerificationGates)
+	}
+	if committer, ok := c.TaskflowService.(interface {
+		SetCommitMessageGenerator(taskflow.TextGenerator)
+	}); ok {
+		committer.SetCommitMessageGenerator(c.AIService.GenerateCode)
+	}
 
 	// Initialize Taskflow Protocol Integration
 	c.TaskflowProtocolIntegration = taskflow.NewProtocolIntegration(
@@ -996,20 +1089,35 @@ func (o *OSFileSystemProvider) MkdirAll(path string, perm int) error {
 	return os.MkdirAll(path, os.FileMode(perm))
 }
 
-// CommandRunnerImpl implements domain.CommandRunner
-type CommandRunnerImpl struct{}
+// CommandRunnerImpl implements domain.CommandRunner, returning stdout only.
+// Commands are killed with their children when ctx is done or Timeout passes.
+type CommandRunnerImpl struct {
+	Timeout time.Duration // per command; 0 means no limit
+}
 
-func (c *CommandRunnerImpl) RunCommand(_ context.Context, name string, args ...string) ([]byte, error) {
-	cmd := exec.Command(name, args...)
-	executil.HideWindow(cmd)
-	return cmd.Output()
+func (c *CommandRunnerImpl) RunCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return c.RunCommandInDir(ctx, "", name, args...)
 }
 
-func (c *CommandRunnerImpl) RunCommandInDir(_ context.Context, dir, name string, args ...string) ([]byte, error) {
-	cmd := exec.Command(name, args...)
-	executil.HideWindow(cmd)
+func (c *CommandRunnerImpl) RunCommandInDir(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	ctx, cancel := executil.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	cmd := executil.CommandContext(ctx, name, args...)
 	cmd.Dir = dir
-	return cmd.Output()
+	output, err := executil.Run(cmd)
+	if err != nil {
+		cmdErr := &domain.CommandError{
+			Command: name, Args: args, Dir: dir,
+			Stdout: output.Stdout, Stderr: output.Stderr,
+			ExitCode: executil.ExitCode(err), Err: err,
+		}
+		if ctx.Err() != nil {
+			cmdErr.Err = fmt.Errorf("%w: %v", ctx.Err(), err)
+		}
+		return output.Stdout, cmdErr
+	}
+	return output.Stdout, nil
 }
 
 // SimpleTokenCounter provides basic token estimation
@@ -1050,19 +1158,11 @@ func (c *AppContainer) Shutdown(ctx context.Context) error {
 		c.Watcher.Stop()
 	}
 
-	// Close cached symbol index if it supports closing
-	if c.SymbolIndex != nil {
-		if closer, ok := c.SymbolIndex.(interface{ Close() error }); ok {
-			if err := closer.Close(); err != nil {
-				shutdownErrors = append(shutdownErrors, fmt.Errorf("SymbolIndex close: %w", err))
-			}
-		}
-	}
-
-	// Shutdown lazy service manager
+	// Unload lazy services, closing the symbol cache and vector store
 	if c.lazyManager != nil {
-		// Force unload all services on shutdown
-		c.lazyManager.UnloadUnusedServices(0)
+		if err := c.lazyManager.Shutdown(); err != nil {
+			shutdownErrors = append(shutdownErrors, fmt.Errorf("lazy services shutdown: %w", err))
+		}
 	}
 
 	if len(shutdownErrors) > 0 {
@@ -1074,14 +1174,15 @@ func (c *AppContainer) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// semanticSearchAdapter adapts domain.SemanticSearchService to tools.SemanticSearcher
+// semanticSearchAdapter adapts the lazy semantic search service to tools.SemanticSearcher
 type semanticSearchAdapter struct {
-	service     domain.SemanticSearchService
+	container   *AppContainer
 	projectRoot string
 }
 
 func (a *semanticSearchAdapter) Search(query string, limit int) ([]domain.SemanticSearchResult, error) {
-	if a.service == nil {
+	service := a.container.GetSemanticSearch(context.Background())
+	if service == nil {
 		return nil, fmt.Errorf("semantic search service not available")
 	}
 
@@ -1093,7 +1194,7 @@ func (a *semanticSearchAdapter) Search(query string, limit int) ([]domain.Semant
 		SearchType:  domain.SearchTypeSemantic,
 	}
 
-	resp, err := a.service.Search(context.Background(), req)
+	resp, err := service.Search(context.Background(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -1110,7 +1211,6 @@ func (a *semanticSearchAdapter) SetProjectRoot(projectRoot string) {
 	a.projectRoot = projectRoot
 }
 
-
 // =============================================================================
 // Adapters for domain interfaces
 // =============================================================================
@@ -1174,13 +1274,75 @@ func (a *callGraphAdapter) GetCallChain(startID, endID string, maxDepth int) [][
 	return a.impl.GetCallChain(startID, endID, maxDepth)
 }
 
+func (a *callGraphAdapter) GetFileDependents(path string) []string {
+	var files []string
+	for _, dep := range a.impl.GetFileDependents(path) {
+		if dep.Type == "file" {
+			files = append(files, dep.FilePath)
+		}
+	}
+	return files
+}
+
+func (a *callGraphAdapter) BuildForFile(ctx context.Context, filePath string, content []byte) error {
+	return a.impl.BuildForFile(ctx, filePath, content)
+}
+
+func (a *callGraphAdapter) GetMostDepended(projectRoot string, limit int) ([]domain.FileDependencyRank, error) {
+	result, err := a.impl.GetMostDepended(projectRoot, limit)
+	if err != nil {
+		return nil, err
+	}
+	ranks := make([]domain.FileDependencyRank, len(result))
+	for i, r := range result {
+		ranks[i] = domain.FileDependencyRank{Path: r.Path, Type: r.Type, Dependents: r.Dependents, Dependencies: r.Dependencies}
+	}
+	return ranks, nil
+}
+
+func (a *callGraphAdapter) DependencyMermaid(projectRoot string, includeExternal bool, maxNodes int) (string, error) {
+	return a.impl.DependencyMermaid(projectRoot, domainanalysis.DependencyGraphOptions{IncludeExternal: includeExternal}, maxNodes)
+}
+
+func (a *callGraphAdapter) SuggestCycleBreak(projectRoot string, cycle []string) (*domain.CycleBreakSuggestion, error) {
+	result, err := a.impl.SuggestCycleBreakInProject(projectRoot, domainanalysis.CyclicDependency{Cycle: cycle})
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]domain.CycleEdgeUsage, len(result.Edges))
+	for i, e := range result.Edges {
+		edges[i] = domain.CycleEdgeUsage(e)
+	}
+	return &domain.CycleBreakSuggestion{
+		Cycle:       result.Cycle,
+		Edge:        domain.CycleEdgeUsage(result.Edge),
+		Edges:       edges,
+		NewPackage:  result.NewPackage,
+		Explanation: result.Explanation,
+	}, nil
+}
+
+func (a *callGraphAdapter) CountDependencyCycles(projectRoot string) (int, error) {
+	cycles, err := a.impl.FindCyclicDependencies(projectRoot)
+	return len(cycles), err
+}
+
 // gitContextAdapter adapts git.ContextBuilder to domain.GitContextBuilder
 type gitContextAdapter struct {
 	impl *git.ContextBuilder
 }
 
 func (a *gitContextAdapter) GetRecentChanges(since string, pathFilter string) ([]domain.RecentChange, error) {
-	result, err := a.impl.GetRecentChanges(since, pathFilter)
+	return a.GetRecentChangesFiltered(domain.RecentChangesOptions{Since: since, PathFilter: pathFilter})
+}
+
+func (a *gitContextAdapter) GetRecentChangesFiltered(opts domain.RecentChangesOptions) ([]domain.RecentChange, error) {
+	result, err := a.impl.GetRecentChangesFiltered(git.RecentChangesOptions{
+		Since:      opts.Since,
+		Until:      opts.Until,
+		Author:     opts.Author,
+		PathFilter: opts.PathFilter,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1233,12 +1395,9 @@ func (a *projectStructureAdapter) Detect(projectPath string) (*domain.ProjectStr
 }
 
 func (a *projectStructureAdapter) DetectLanguages(projectPath string) ([]string, error) {
-	result, err := a.impl.DetectStructure(projectPath)
-	if err != nil {
-		return nil, err
-	}
-	langs := make([]string, len(result.Languages))
-	for i, l := range result.Languages {
+	languages := a.impl.DetectLanguageInfo(projectPath)
+	langs := make([]string, len(languages))
+	for i, l := range languages {
 		langs[i] = l.Name
 	}
 	return langs, nil
@@ -1279,33 +1438,47 @@ func (a *referenceFinderAdapter) FindReferences(ctx context.Context, projectRoot
 	if err != nil {
 		return nil, err
 	}
+	return toDomainSymbolReferences(result), nil
+}
+
+func (a *referenceFinderAdapter) FindImplementations(ctx context.Context, projectRoot string, interfaceName string) ([]domain.SymbolReference, error) {
+	result, err := a.impl.FindImplementations(ctx, projectRoot, interfaceName)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainSymbolReferences(result), nil
+}
+
+func toDomainSymbolReferences(result []analyzers.Reference) []domain.SymbolReference {
 	refs := make([]domain.SymbolReference, len(result))
 	for i, r := range result {
 		refs[i] = domain.SymbolReference{
-			FilePath:     r.FilePath,
-			Line:         r.Line,
-			Column:       r.Column,
-			LineText:     r.LineText,
-			Context:      r.Context,
-			IsDefinition: r.IsDefinition,
+			FilePath:          r.FilePath,
+			Line:              r.Line,
+			Column:            r.Column,
+			LineText:          r.LineText,
+			Context:           r.Context,
+			IsDefinition:      r.IsDefinition,
+			Confidence:        r.Confidence,
+			EnclosingFunction: r.EnclosingFunction,
 		}
 	}
-	return refs, nil
+	return refs
 }
 
-func (a *referenceFinderAdapter) FindUsages(ctx context.Context, projectRoot string, symbolName string) ([]domain.SymbolReference, error) {
-	refs, err := a.FindReferences(ctx, projectRoot, symbolName, "")
+func (a *referenceFinderAdapter) FindUsages(ctx context.Context, projectRoot string, symbolName string, offset, limit int) (*domain.SymbolUsagePage, error) {
+	page, err := a.impl.FindUsages(ctx, projectRoot, symbolName, offset, limit)
 	if err != nil {
 		return nil, err
 	}
-	// Filter out definitions
-	usages := make([]domain.SymbolReference, 0, len(refs))
-	for _, r := range refs {
-		if !r.IsDefinition {
-			usages = append(usages, r)
-		}
-	}
-	return usages, nil
+	return &domain.SymbolUsagePage{
+		Usages:    toDomainSymbolReferences(page.Usages),
+		Total:     page.Total,
+		Offset:    page.Offset,
+		Limit:     page.Limit,
+		HasMore:   page.HasMore,
+		Truncated: page.Truncated,
+	}, nil
 }
 
 // codeChunkerAdapter adapts embeddings.CodeChunker to domain.CodeChunker
@@ -1326,3 +1499,7 @@ func (a *codeChunkerAdapter) ChunkFile(filePath string, content []byte, symbols
 	}
 	return a.impl.ChunkFile(filePath, content, embSymbols)
 }
+
+func (a *codeChunkerAdapter) Configure(config domain.ChunkerConfig) error {
+	return a.impl.Configure(config)
+}
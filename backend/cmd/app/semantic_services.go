@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"shotgun_code/application/rag"
+	"shotgun_code/domain"
+	domainanalysis "shotgun_code/domain/analysis"
+	"shotgun_code/infrastructure/analyzers"
+	"shotgun_code/infrastructure/embeddings"
+)
+
+// SemanticServices groups the semantic search services sharing one symbol
+// index and vector store, so they are loaded and unloaded together
+type SemanticServices struct {
+	Search domain.SemanticSearchService
+	RAG    domain.RAGService
+
+	symbolIndex domainanalysis.SymbolIndex
+	vectorStore domain.VectorStore
+	inMemory    bool
+}
+
+// newSemanticServices opens the symbol cache and vector store under dataDir;
+// the store compares embeddings with the given metric and files are chunked
+// as chunkerConfig says (the zero value keeps the chunker's defaults)
+func newSemanticServices(dataDir, vectorStoreKind string, metric domain.SimilarityMetric, chunkerConfig domain.ChunkerConfig, provider domain.EmbeddingProvider, log domain.Logger) (*SemanticServices, error) {
+	s := &SemanticServices{}
+
+	// Create symbol index with SQLite caching for incremental indexing
+	analyzerRegistry := analyzers.NewAnalyzerRegistry()
+	cachedSymbolIndex, err := analyzers.NewCachedSymbolIndex(analyzerRegistry, filepath.Join(dataDir, "symbol_cache"))
+	if err != nil {
+		log.Warning(fmt.Sprintf("Failed to create cached symbol index, falling back to in-memory: %v", err))
+		s.symbolIndex = analyzers.NewSymbolIndex(analyzerRegistry)
+	} else {
+		s.symbolIndex = cachedSymbolIndex
+	}
+
+	// Create vector store (SQLite-based by default)
+	switch vectorStoreKind {
+	case domain.VectorStoreMemory:
+		vectorStore := embeddings.NewInMemoryVectorStore()
+		vectorStore.SetSimilarityMetric(metric)
+		s.vectorStore = vectorStore
+		s.inMemory = true
+		log.Info("Using in-memory vector store: embeddings are not persisted")
+	default:
+		vectorStore, err := embeddings.NewSQLiteVectorStore(dataDir, log)
+		if err != nil {
+			_ = s.Close()
+			return nil, fmt.Errorf("failed to create vector store: %w", err)
+		}
+		vectorStore.SetSimilarityMetric(metric)
+		vectorStore.SetEmbeddingModel(provider.GetModelInfo())
+		s.vectorStore = vectorStore
+	}
+
+	chunker := &codeChunkerAdapter{impl: embeddings.NewCodeChunker(embeddings.DefaultChunkerConfig())}
+	search := rag.NewSemanticSearchService(provider, s.vectorStore, s.symbolIndex, log, chunker, chunkerConfig)
+	s.Search = search
+	s.RAG = rag.NewService(search, provider, log)
+
+	log.Info("Semantic search services initialized successfully")
+	return s, nil
+}
+
+// SetLanguageScope limits symbol and embedding indexing to the enabled languages
+func (s *SemanticServices) SetLanguageScope(scope *domain.LanguageScope) {
+	for _, svc := range []interface{}{s.symbolIndex, s.Search} {
+		if setter, ok := svc.(domain.LanguageScopeSetter); ok {
+			setter.SetLanguageScope(scope)
+		}
+	}
+}
+
+// SetReranker sets the reranker used by searches that request re-ranking
+func (s *SemanticServices) SetReranker(reranker domain.Reranker) {
+	if setter, ok := s.Search.(interface{ SetReranker(domain.Reranker) }); ok {
+		setter.SetReranker(reranker)
+	}
+}
+
+// SetEmbeddingModel tells the vector store which model generates embeddings,
+// so that projects indexed with another model are re-indexed
+func (s *SemanticServices) SetEmbeddingModel(info domain.EmbeddingModelInfo) {
+	if setter, ok := s.vectorStore.(interface {
+		SetEmbeddingModel(domain.EmbeddingModelInfo)
+	}); ok {
+		setter.SetEmbeddingModel(info)
+	}
+}
+
+// setEmbeddingModel switches the embedding provider to model. Projects indexed
+// with the previous model are rebuilt when they are next indexed.
+func (c *AppContainer) setEmbeddingModel(model domain.EmbeddingModel) {
+	setter, ok := c.EmbeddingProvider.(interface{ SetModel(domain.EmbeddingModel) })
+	if !ok {
+		return
+	}
+	setter.SetModel(model)
+	if c.Semantic != nil && c.Semantic.IsInitialized() {
+		if services, err := c.Semantic.Get(context.Background()); err == nil {
+			services.SetEmbeddingModel(c.EmbeddingProvider.GetModelInfo())
+		}
+	}
+	c.Log.Info(fmt.Sprintf("Embedding model set to %s; projects indexed with another model need re-indexing", model))
+}
+
+// Busy reports whether unloading would interrupt indexing or, for the
+// in-memory vector store, lose the embeddings
+func (s *SemanticServices) Busy() bool {
+	if s.inMemory {
+		return true
+	}
+	indexer, ok := s.Search.(interface{ IsIndexing() bool })
+	return ok && indexer.IsIndexing()
+}
+
+// Close closes the vector store and the symbol cache
+func (s *SemanticServices) Close() error {
+	var errs []error
+	if closer, ok := s.vectorStore.(interface{ Close() error }); ok {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("vector store close: %w", err))
+		}
+	}
+	if closer, ok := s.symbolIndex.(interface{ Close() error }); ok {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("symbol index close: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// semanticServices returns the semantic services, loading them if needed
+func (c *AppContainer) semanticServices(ctx context.Context) (domain.SemanticSearchService, domain.RAGService, error) {
+	if c.Semantic == nil {
+		return nil, nil, fmt.Errorf("semantic search not available: embedding provider not configured")
+	}
+	services, err := c.Semantic.Get(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return services.Search, services.RAG, nil
+}
+
+// GetSemanticSearch returns the semantic search service, loading it if needed.
+// Returns nil when semantic search is not configured or fails to load.
+func (c *AppContainer) GetSemanticSearch(ctx context.Context) domain.SemanticSearchService {
+	search, _, err := c.semanticServices(ctx)
+	if err != nil {
+		if c.Semantic != nil {
+			c.Log.Warning("Failed to load semantic search: " + err.Error())
+		}
+		return nil
+	}
+	return search
+}
+
+// loadedSemanticSearch returns the semantic search service only if it is
+// already loaded, so that background work doesn't open the vector store
+func (c *AppContainer) loadedSemanticSearch(ctx context.Context) domain.SemanticSearchService {
+	if c.Semantic == nil || !c.Semantic.IsInitialized() {
+		return nil
+	}
+	return c.GetSemanticSearch(ctx)
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"shotgun_code/domain"
+	"shotgun_code/infrastructure/analyzers"
+	"shotgun_code/infrastructure/embeddings"
+	"shotgun_code/internal/initmanager"
+)
+
+func TestSemanticServices_IdleUnloadClosesStores(t *testing.T) {
+	dataDir := t.TempDir()
+	log := &domain.NoopLogger{}
+	lazy := initmanager.NewLazyService(func(context.Context) (*SemanticServices, error) {
+		return newSemanticServices(dataDir, domain.VectorStoreSQLite, domain.SimilarityCosine, domain.ChunkerConfig{}, embeddings.NewFakeEmbeddingProvider(0), log)
+	}).WithCleanup((*SemanticServices).Close).WithInUse((*SemanticServices).Busy)
+
+	manager := initmanager.NewLazyServiceManager()
+	manager.Register("semanticsearch", lazy)
+
+	ctx := context.Background()
+	first, err := lazy.Get(ctx)
+	if err != nil {
+		t.Fatalf("failed to load semantic services: %v", err)
+	}
+	symbolIndex, ok := first.symbolIndex.(*analyzers.CachedSymbolIndex)
+	if !ok {
+		t.Fatalf("expected SQLite symbol index, got %T", first.symbolIndex)
+	}
+
+	if n := manager.UnloadUnusedServices(0); n != 1 {
+		t.Fatalf("expected semantic services to be unloaded, got %d", n)
+	}
+	if err := symbolIndex.InvalidateCache(); err == nil {
+		t.Error("symbol index should be closed after unload")
+	}
+	if _, err := first.vectorStore.GetStats(ctx, "p"); err == nil {
+		t.Error("vector store should be closed after unload")
+	}
+
+	// The next access reopens the same databases
+	second, err := lazy.Get(ctx)
+	if err != nil {
+		t.Fatalf("failed to reload semantic services: %v", err)
+	}
+	if second == first {
+		t.Fatal("expected fresh services after unload")
+	}
+	if err := second.symbolIndex.(*analyzers.CachedSymbolIndex).InvalidateCache(); err != nil {
+		t.Errorf("reopened symbol index should work: %v", err)
+	}
+	if err := manager.Shutdown(); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+}
+
+func TestSemanticServices_InMemoryStoreIsNeverIdle(t *testing.T) {
+	services, err := newSemanticServices(t.TempDir(), domain.VectorStoreMemory, domain.SimilarityCosine, domain.ChunkerConfig{}, embeddings.NewFakeEmbeddingProvider(0), &domain.NoopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+
+	if !services.Busy() {
+		t.Error("unloading an in-memory vector store would drop embeddings")
+	}
+}
+
+func TestLoadedSemanticSearch_DoesNotLoadServices(t *testing.T) {
+	loads := 0
+	c := &AppContainer{Log: &domain.NoopLogger{}}
+	c.Semantic = initmanager.NewLazyService(func(context.Context) (*SemanticServices, error) {
+		loads++
+		return &SemanticServices{}, nil
+	})
+
+	if search := c.loadedSemanticSearch(context.Background()); search != nil {
+		t.Fatalf("expected no search service before loading, got %T", search)
+	}
+	if loads != 0 {
+		t.Fatalf("semantic services were loaded %d times", loads)
+	}
+
+	if _, err := c.Semantic.Get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	c.loadedSemanticSearch(context.Background())
+	if loads != 1 {
+		t.Errorf("expected the loaded services to be reused, got %d loads", loads)
+	}
+}
@@ -6,36 +6,44 @@ import (
 
 // CLI представляет интерфейс командной строки
 type CLI struct {
-	container *CLIContainer
+	container  *CLIContainer
+	jsonOutput bool
 }
 
-// NewCLI создает новый экземпляр CLI
-func NewCLI(container *CLIContainer) *CLI {
+// NewCLI создает новый экземпляр CLI.
+// Если jsonOutput включен, команды не печатают результат в stdout,
+// а возвращают его для сериализации в JSON-конверт.
+func NewCLI(container *CLIContainer, jsonOutput bool) *CLI {
 	return &CLI{
-		container: container,
+		container:  container,
+		jsonOutput: jsonOutput,
 	}
 }
 
 // Index выполняет команду индексации проекта
-func (c *CLI) Index(ctx context.Context, args []string) error {
+func (c *CLI) Index(ctx context.Context, args []string) (interface{}, error) {
 	indexCmd := NewIndexCommand(c.container)
+	indexCmd.jsonOutput = c.jsonOutput
 	return indexCmd.Execute(ctx, args)
 }
 
 // Solve выполняет команду решения задач
-func (c *CLI) Solve(ctx context.Context, args []string) error {
+func (c *CLI) Solve(ctx context.Context, args []string) (interface{}, error) {
 	solveCmd := NewSolveCommand(c.container)
+	solveCmd.jsonOutput = c.jsonOutput
 	return solveCmd.Execute(ctx, args)
 }
 
 // Result выполняет команду показа результатов
-func (c *CLI) Result(ctx context.Context, args []string) error {
+func (c *CLI) Result(ctx context.Context, args []string) (interface{}, error) {
 	resultCmd := NewResultCommand(c.container)
+	resultCmd.jsonOutput = c.jsonOutput
 	return resultCmd.Execute(ctx, args)
 }
 
 // Verify выполняет команду верификации проекта
-func (c *CLI) Verify(ctx context.Context, args []string) error {
+func (c *CLI) Verify(ctx context.Context, args []string) (interface{}, error) {
 	verifyCmd := NewVerifyCommand(c.container)
+	verifyCmd.jsonOutput = c.jsonOutput
 	return verifyCmd.Execute(ctx, args)
 }
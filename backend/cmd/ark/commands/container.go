@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	appai "shotgun_code/application/ai"
@@ -19,14 +20,20 @@ import (
 	"shotgun_code/application/verification"
 	"shotgun_code/domain"
 	"shotgun_code/infrastructure/ai"
+	"shotgun_code/infrastructure/aiaudit"
+	"shotgun_code/infrastructure/analyzers"
 	"shotgun_code/infrastructure/contextbuilder"
 	"shotgun_code/infrastructure/exec"
 	"shotgun_code/infrastructure/filereader"
 	"shotgun_code/infrastructure/filesystem"
 	"shotgun_code/infrastructure/formatters"
 	"shotgun_code/infrastructure/fsscanner"
+	"shotgun_code/infrastructure/fswatcher"
 	"shotgun_code/infrastructure/git"
 	"shotgun_code/infrastructure/policy"
+	"shotgun_code/infrastructure/policystore"
+	"shotgun_code/infrastructure/projectstructure"
+	"shotgun_code/infrastructure/reportfs"
 	"shotgun_code/infrastructure/sbomlicensing"
 	"shotgun_code/infrastructure/settingsfs"
 	"shotgun_code/infrastructure/textutils"
@@ -52,7 +59,7 @@ const openRouterHost = "https://openrouter.ai/api/v1"
 // CLIContainer holds all the services and repositories for the application.
 type CLIContainer struct {
 	Log                   domain.Logger
-	EventBus              domain.EventBus
+	EventBus              *CLIEventBus
 	SettingsRepo          domain.SettingsRepository
 	FileReader            domain.FileContentReader
 	GitRepo               domain.GitRepository
@@ -76,18 +83,23 @@ type CLIContainer struct {
 	DiffService           *diff.Service
 	BuildService          domain.IBuildService
 	ExportService         *export.Service
+	SafeMode              *domain.SafeMode
+	LanguageScope         *domain.LanguageScope
 	VerificationService   *verification.Service
 	opaService            domain.OPAService
+	languageDetector      *projectstructure.LanguageDetector
 }
 
 // NewCLIContainer creates and wires up all the application dependencies.
-func NewCLIContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt string, verbose bool) (*CLIContainer, error) {
+// Log messages are written to logOut (stdout when nil).
+func NewCLIContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPrompt string, verbose bool, logOut io.Writer) (*CLIContainer, error) {
 	c := &CLIContainer{}
 	var err error
 
 	// Logger for CLI
-	logger := NewCLILogger(verbose)
+	logger := NewCLILogger(verbose, logOut)
 	c.Log = logger
+	c.EventBus = NewCLIEventBus()
 
 	// Repositories and Infrastructure
 	c.SettingsRepo, err = settingsfs.New(c.Log, embeddedIgnoreGlob, defaultCustomPrompt)
@@ -98,7 +110,10 @@ func NewCLIContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPromp
 	c.GitRepo = git.New(c.Log)
 	c.TreeBuilder = fsscanner.New(c.SettingsRepo, c.Log)
 	c.ContextSplitter = textutils.NewContextSplitter(c.Log)
-	c.CommandRunner = exec.NewCommandRunnerImpl(c.Log)
+	commandRunner := exec.NewCommandRunnerImpl(c.Log)
+	commandRunner.SetLimits(c.SettingsRepo.GetCommandLimits())
+	commandRunner.SetAllowedCommands(exec.DefaultAllowedCommands)
+	c.CommandRunner = commandRunner
 
 	// Application Services
 	modelFetchers := createModelFetchers(ctx, c.Log, c.SettingsRepo)
@@ -120,6 +135,13 @@ func NewCLIContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPromp
 	// Create AI service with intelligent service
 	c.AIService = appai.NewService(c.SettingsService, c.Log, providerRegistry, intelligentService)
 
+	// Record every AI request to the audit trail
+	homeDir, homeErr := os.UserHomeDir()
+	if homeErr == nil {
+		auditStore := aiaudit.NewFileStore(filepath.Join(homeDir, ".shotgun-code", "audit", "ai-requests.jsonl"))
+		c.AIService.SetAuditLogger(appai.NewAuditLogger(auditStore, c.SettingsService, c.Log))
+	}
+
 	// Create OPA service
 	c.opaService = policy.NewOPAService(c.Log)
 
@@ -136,6 +158,10 @@ func NewCLIContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPromp
 	if err != nil {
 		return nil, fmt.Errorf("failed to create context service: %w", err)
 	}
+	c.ContextService.SetContentOptimizer(textutils.NewDomainContentOptimizer(
+		analyzers.NewAnalyzerRegistry(), textutils.NewCommentStripper(c.Log),
+	))
+	c.ContextService.SetOptimizationProfileSource(c.SettingsRepo)
 
 	// Create unified ProjectService
 	c.ProjectService = projectservice.NewService(
@@ -170,11 +196,22 @@ func NewCLIContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPromp
 	c.SBOMService = sbom.NewService(c.Log, sbomGenerator, vulnScanner, licenseScanner, fileStatProvider)
 
 	c.RepairService = repair.NewService(c.Log, c.CommandRunner)
+	if reportRepo, reportErr := reportfs.NewReportFileSystemRepository(c.Log); reportErr != nil {
+		c.Log.Warning(fmt.Sprintf("Repair history disabled: %v", reportErr))
+	} else if repairService, ok := c.RepairService.(*repair.Service); ok {
+		repairService.SetReportRepository(reportRepo)
+	}
+	if repairService, ok := c.RepairService.(*repair.Service); ok && homeErr == nil {
+		repairService.SetRuleStore(policystore.NewRepairRuleStore(filepath.Join(homeDir, ".shotgun-code", "repair-rules.json")))
+	}
 
 	// Taskflow components not used in CLI currently
 
 	// Create Guardrail service with required dependencies
 	c.GuardrailService = guardrails.NewService(c.Log, c.opaService, fileStatProvider)
+	if guardrailService, ok := c.GuardrailService.(*guardrails.ServiceImpl); ok && homeErr == nil {
+		guardrailService.SetPolicyStore(policystore.NewGuardrailStore(filepath.Join(homeDir, ".shotgun-code", "guardrails.json")))
+	}
 
 	// Create UX Metrics infrastructure components
 	uxRepo := uxreports.NewInMemoryUXReportRepository()
@@ -225,13 +262,34 @@ func NewCLIContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPromp
 		Languages:      []string{"go", "typescript", "ts"},
 	}
 	c.ApplyService = diff.NewApplyService(c.Log, applyConfig, applyEngine, formattersMap, importFixers)
+	c.ApplyService.SetGuardrails(c.GuardrailService)
+
+	// Safe mode blocks every service that writes project files
+	c.SafeMode = domain.NewSafeMode(c.SettingsRepo.GetSafeMode())
+	c.ApplyService.SetSafeMode(c.SafeMode)
+	if setter, ok := c.RepairService.(domain.SafeModeSetter); ok {
+		setter.SetSafeMode(c.SafeMode)
+	}
+
+	// Enabled languages scope analyzers and test runners
+	c.LanguageScope = domain.NewLanguageScope(c.SettingsRepo.GetEnabledLanguages())
+	for _, svc := range []interface{}{c.StaticAnalyzerService, c.TestService} {
+		if setter, ok := svc.(domain.LanguageScopeSetter); ok {
+			setter.SetLanguageScope(c.LanguageScope)
+		}
+	}
 
 	// Create Diff service
 	diffEngine := diffengine.NewDiffEngine(c.Log)
 	c.DiffService = diff.NewService(c.Log, diffEngine)
 
 	buildPipeline := buildpipeline.NewBuildPipeline(c.Log)
-	c.BuildService = build.NewService(c.Log, buildPipeline)
+	buildPipeline.SetExecutor(commandRunner.Executor())
+	buildService := build.NewService(c.Log, buildPipeline)
+	// Языки определяются общим детектором; его кэш сбрасывается по событиям watcher
+	c.languageDetector = projectstructure.SharedLanguageDetector()
+	buildService.SetLanguageDetector(c.languageDetector)
+	c.BuildService = buildService
 
 	// Create formatter service
 	formatterService := export.NewFormatterService(c.Log, c.CommandRunner)
@@ -246,6 +304,13 @@ func NewCLIContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPromp
 		&OSFileSystemWriter{},
 		nil, // Task Protocol Service not needed for CLI
 	)
+	c.VerificationService.SetVulnerabilityScanner(c.SBOMService)
+	c.VerificationService.SetDependencyCycleCounter(func(projectRoot string) (int, error) {
+		builder := analyzers.NewCallGraphBuilder(analyzers.NewAnalyzerRegistry())
+		builder.SetLanguageScope(c.LanguageScope)
+		cycles, err := builder.FindCyclicDependencies(projectRoot)
+		return len(cycles), err
+	})
 
 	// new: wire PDF and ZIP implementations
 	pdfGen := pdfgen.NewGofpdfGenerator(c.Log)
@@ -268,45 +333,73 @@ func NewCLIContainer(ctx context.Context, embeddedIgnoreGlob, defaultCustomPromp
 	return c, nil
 }
 
+// LoadProjectEnv применяет commandEnv из конфига проекта к командам сборки,
+// анализа и тестов; ошибка чтения конфига только логируется
+func (c *CLIContainer) LoadProjectEnv(projectPath string) {
+	loader, ok := c.CommandRunner.(domain.ProjectEnvLoader)
+	if !ok {
+		return
+	}
+	if err := loader.LoadProjectEnv(projectPath); err != nil {
+		c.Log.Warning(fmt.Sprintf("Failed to load project config for %s: %v", projectPath, err))
+	}
+}
+
+// NewWatcher создает watcher файлов проекта для verify --watch; его события
+// сбрасывают кэш детектора языков сборки. Другим командам watcher не нужен
+func (c *CLIContainer) NewWatcher() (*fswatcher.Watcher, error) {
+	watcher, err := fswatcher.NewWithLogger(c.Log, c.EventBus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	watcher.OnFilesChanged(c.languageDetector.OnFilesChanged)
+	return watcher, nil
+}
+
 // CLILogger реализует простой логгер для CLI
 type CLILogger struct {
 	verbose bool
+	out     io.Writer
 }
 
-// NewCLILogger создает новый CLI логгер
-func NewCLILogger(verbose bool) *CLILogger {
+// NewCLILogger создает новый CLI логгер, пишущий в out (stdout если nil)
+func NewCLILogger(verbose bool, out io.Writer) *CLILogger {
+	if out == nil {
+		out = os.Stdout
+	}
 	return &CLILogger{
 		verbose: verbose,
+		out:     out,
 	}
 }
 
 // Info логирует информационное сообщение
 func (l *CLILogger) Info(message string) {
 	if l.verbose {
-		fmt.Printf("[INFO] %s\n", message)
+		fmt.Fprintf(l.out, "[INFO] %s\n", message)
 	}
 }
 
 // Warning логирует предупреждение
 func (l *CLILogger) Warning(message string) {
-	fmt.Printf("[WARN] %s\n", message)
+	fmt.Fprintf(l.out, "[WARN] %s\n", message)
 }
 
 // Error логирует ошибку
 func (l *CLILogger) Error(message string) {
-	fmt.Printf("[ERROR] %s\n", message)
+	fmt.Fprintf(l.out, "[ERROR] %s\n", message)
 }
 
 // Debug логирует отладочное сообщение
 func (l *CLILogger) Debug(message string) {
 	if l.verbose {
-		fmt.Printf("[DEBUG] %s\n", message)
+		fmt.Fprintf(l.out, "[DEBUG] %s\n", message)
 	}
 }
 
 // Fatal логирует фатальную ошибку и завершает программу
 func (l *CLILogger) Fatal(message string) {
-	fmt.Printf("[FATAL] %s\n", message)
+	fmt.Fprintf(l.out, "[FATAL] %s\n", message)
 	os.Exit(1)
 }
 
@@ -335,7 +428,7 @@ func createModelFetchers(ctx context.Context, log domain.Logger, repo domain.Set
 
 	// OpenRouter
 	fetchers["openrouter"] = func(apiKey string) ([]string, error) {
-		p, err := ai.NewOpenAI(apiKey, openRouterHost, log)
+		p, err := ai.NewOpenRouter(apiKey, openRouterHost, log)
 		if err != nil {
 			log.Warning("Failed to create OpenRouter client for model listing: " + err.Error())
 			return nil, err
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"sync"
+)
+
+// CLIEventBus is a minimal in-process domain.EventBus for CLI commands
+type CLIEventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]func(data ...interface{})
+}
+
+// NewCLIEventBus creates a new CLI event bus
+func NewCLIEventBus() *CLIEventBus {
+	return &CLIEventBus{
+		handlers: make(map[string][]func(data ...interface{})),
+	}
+}
+
+// Emit synchronously invokes all handlers registered for the event
+func (b *CLIEventBus) Emit(eventName string, data ...interface{}) {
+	b.mu.RLock()
+	handlers := append([]func(data ...interface{}){}, b.handlers[eventName]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(data...)
+	}
+}
+
+// On registers a handler for the event
+func (b *CLIEventBus) On(eventName string, handler func(data ...interface{})) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventName] = append(b.handlers[eventName], handler)
+}
@@ -3,16 +3,18 @@ package commands
 import (
 	"context"
 	"encoding/json"
-	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"shotgun_code/domain"
+	"shotgun_code/domain/analysis"
+	"shotgun_code/infrastructure/analyzers"
 	"time"
 )
 
 // IndexCommand представляет команду индексации
 type IndexCommand struct {
+	commandOutput
 	container *CLIContainer
 }
 
@@ -24,62 +26,70 @@ func NewIndexCommand(container *CLIContainer) *IndexCommand {
 }
 
 // Execute выполняет команду индексации
-func (c *IndexCommand) Execute(ctx context.Context, args []string) error {
+func (c *IndexCommand) Execute(ctx context.Context, args []string) (interface{}, error) {
 	// Создаем флаги для команды
-	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	fs := c.newFlagSet("index")
 	var (
 		projectPath = fs.String("project", ".", "Project path to index")
 		output      = fs.String("output", "", "Output file for index data (JSON)")
 		language    = fs.String("language", "go", "Primary language to index")
+		depsMermaid = fs.String("deps-mermaid", "", "Output file for the file dependency graph (Mermaid)")
+		external    = fs.Bool("include-external", false, "Include external modules in the dependency graph")
 		verbose     = fs.Bool("verbose", false, "Verbose output")
 		help        = fs.Bool("help", false, "Show help")
 	)
 
 	// Парсим аргументы
 	if err := fs.Parse(args); err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return nil, fmt.Errorf("failed to parse arguments: %w", err)
 	}
 
 	// Показываем помощь если запрошено
 	if *help {
 		c.printHelp()
-		return nil
+		return nil, nil
 	}
 
 	// Проверяем существование проекта
 	if _, err := os.Stat(*projectPath); os.IsNotExist(err) {
-		return fmt.Errorf("project path does not exist: %s", *projectPath)
+		return nil, fmt.Errorf("project path does not exist: %s", *projectPath)
 	}
 
 	// Получаем абсолютный путь
 	absPath, err := filepath.Abs(*projectPath)
 	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
 	if *verbose {
-		fmt.Printf("Indexing project: %s\n", absPath)
-		fmt.Printf("Language: %s\n", *language)
+		c.printf("Indexing project: %s\n", absPath)
+		c.printf("Language: %s\n", *language)
 	}
 
 	// Индексируем файлы проекта
 	files, err := c.container.ProjectService.ListFiles(absPath, true, true)
 	if err != nil {
-		return fmt.Errorf("failed to list project files: %w", err)
+		return nil, fmt.Errorf("failed to list project files: %w", err)
 	}
 
 	if *verbose {
-		fmt.Printf("Found %d files\n", len(files))
+		c.printf("Found %d files\n", len(files))
 	}
 
 	// Строим граф символов
 	symbolGraph, err := c.container.SymbolGraph.BuildSymbolGraph(ctx, absPath, *language)
 	if err != nil {
-		return fmt.Errorf("failed to build symbol graph: %w", err)
+		return nil, fmt.Errorf("failed to build symbol graph: %w", err)
 	}
 
 	if *verbose {
-		fmt.Printf("Built symbol graph with %d nodes\n", len(symbolGraph.Nodes))
+		c.printf("Built symbol graph with %d nodes\n", len(symbolGraph.Nodes))
+	}
+
+	if *depsMermaid != "" {
+		if err := c.writeDependencyMermaid(absPath, *depsMermaid, *external); err != nil {
+			return nil, err
+		}
 	}
 
 	// Создаем результат индексации
@@ -96,30 +106,46 @@ func (c *IndexCommand) Execute(ctx context.Context, args []string) error {
 		// Сохраняем в файл
 		data, err := json.MarshalIndent(indexResult, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal index result: %w", err)
+			return nil, fmt.Errorf("failed to marshal index result: %w", err)
 		}
 
 		if err := os.WriteFile(*output, data, 0o644); err != nil {
-			return fmt.Errorf("failed to write output file: %w", err)
+			return nil, fmt.Errorf("failed to write output file: %w", err)
 		}
 
-		fmt.Printf("Index data saved to: %s\n", *output)
-	} else {
+		c.printf("Index data saved to: %s\n", *output)
+	} else if !c.jsonOutput {
 		// Выводим в stdout
 		data, err := json.MarshalIndent(indexResult, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal index result: %w", err)
+			return nil, fmt.Errorf("failed to marshal index result: %w", err)
 		}
 
-		fmt.Println(string(data))
+		c.println(string(data))
 	}
 
+	return indexResult, nil
+}
+
+// writeDependencyMermaid сохраняет граф зависимостей файлов проекта в виде диаграммы Mermaid
+func (c *IndexCommand) writeDependencyMermaid(projectPath, output string, includeExternal bool) error {
+	builder := analyzers.NewCallGraphBuilder(analyzers.NewAnalyzerRegistry())
+	builder.SetLanguageScope(c.container.LanguageScope)
+	opts := analysis.DependencyGraphOptions{IncludeExternal: includeExternal}
+	mermaid, err := builder.DependencyMermaid(projectPath, opts, domain.DefaultConfig().CallGraph.MaxMermaidNodes)
+	if err != nil {
+		return fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+	if err := os.WriteFile(output, []byte(mermaid), 0o644); err != nil {
+		return fmt.Errorf("failed to write dependency graph: %w", err)
+	}
+	c.printf("Dependency graph saved to: %s\n", output)
 	return nil
 }
 
 // printHelp выводит справку по команде
 func (c *IndexCommand) printHelp() {
-	fmt.Printf(`ark index - Index project files and build symbol graph
+	c.printf(`ark index - Index project files and build symbol graph
 
 Usage: ark index [options]
 
@@ -130,6 +156,10 @@ Options:
         Output file for index data (JSON)
   -language string
         Primary language to index (default "go")
+  -deps-mermaid string
+        Output file for the file dependency graph (Mermaid)
+  -include-external
+        Include external modules in the dependency graph
   -verbose
         Verbose output
   -help
@@ -139,6 +169,7 @@ Examples:
   ark index --project ./my-project
   ark index --project ./my-project --output index.json --language typescript
   ark index --project ./my-project --verbose
+  ark index --project ./my-project --deps-mermaid deps.mmd --include-external
 `)
 }
 
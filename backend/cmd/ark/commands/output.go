@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Envelope statuses
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// JSONEnvelope is the structured output emitted by every command when --json is set
+type JSONEnvelope struct {
+	Command string      `json:"command"`
+	Status  string      `json:"status"`
+	Data    interface{} `json:"data"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// ExitError is returned by commands that need a specific process exit code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for a command error:
+// 0 for nil, the carried code for ExitError and 1 otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
+// WriteJSONEnvelope writes the envelope for a finished command and returns
+// the process exit code that matches it.
+func WriteJSONEnvelope(w io.Writer, command string, data interface{}, cmdErr error) int {
+	envelope := JSONEnvelope{
+		Command: command,
+		Status:  StatusSuccess,
+		Data:    data,
+	}
+	exitCode := ExitCode(cmdErr)
+	if cmdErr != nil {
+		envelope.Status = StatusError
+		envelope.Error = cmdErr.Error()
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(envelope); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode JSON output: %v\n", err)
+		return 1
+	}
+	return exitCode
+}
+
+// commandOutput routes the human-readable output of a command.
+// In JSON mode stdout is reserved for the envelope, so progress and help
+// text are written to stderr instead.
+type commandOutput struct {
+	jsonOutput bool
+}
+
+// writer returns the destination for human-readable output
+func (o *commandOutput) writer() io.Writer {
+	if o.jsonOutput {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// printf prints a human-readable message
+func (o *commandOutput) printf(format string, a ...interface{}) {
+	fmt.Fprintf(o.writer(), format, a...)
+}
+
+// println prints a human-readable line
+func (o *commandOutput) println(a ...interface{}) {
+	fmt.Fprintln(o.writer(), a...)
+}
+
+// newFlagSet creates a flag set that reports parse errors instead of exiting,
+// so they can be serialized into the JSON envelope.
+func (o *commandOutput) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
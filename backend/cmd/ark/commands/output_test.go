@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWriteJSONEnvelope_Success(t *testing.T) {
+	var buf bytes.Buffer
+	code := WriteJSONEnvelope(&buf, "index", map[string]int{"files": 3}, nil)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+
+	var envelope JSONEnvelope
+	if err := json.Unmarshal(buf.Bytes(), &envelope); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if envelope.Command != "index" || envelope.Status != StatusSuccess || envelope.Error != "" {
+		t.Errorf("unexpected envelope: %+v", envelope)
+	}
+}
+
+func TestWriteJSONEnvelope_Error(t *testing.T) {
+	var buf bytes.Buffer
+	code := WriteJSONEnvelope(&buf, "verify", nil, errors.New("boom"))
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	var envelope JSONEnvelope
+	if err := json.Unmarshal(buf.Bytes(), &envelope); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if envelope.Status != StatusError || envelope.Error != "boom" || envelope.Data != nil {
+		t.Errorf("unexpected envelope: %+v", envelope)
+	}
+}
+
+func TestWriteJSONEnvelope_ExitError(t *testing.T) {
+	var buf bytes.Buffer
+	code := WriteJSONEnvelope(&buf, "verify", nil, &ExitError{Code: 2, Err: errors.New("build failed")})
+	if code != 2 {
+		t.Fatalf("expected exit code 2, got %d", code)
+	}
+}
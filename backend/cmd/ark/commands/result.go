@@ -3,15 +3,19 @@ package commands
 import (
 	"context"
 	"encoding/json"
-	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"shotgun_code/domain"
+	"shotgun_code/infrastructure/taskflowrepo"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // ResultCommand представляет команду показа результатов
 type ResultCommand struct {
+	commandOutput
 	container *CLIContainer
 }
 
@@ -23,9 +27,9 @@ func NewResultCommand(container *CLIContainer) *ResultCommand {
 }
 
 // Execute выполняет команду показа результатов
-func (c *ResultCommand) Execute(ctx context.Context, args []string) error {
+func (c *ResultCommand) Execute(ctx context.Context, args []string) (interface{}, error) {
 	// Создаем флаги для команды
-	fs := flag.NewFlagSet("result", flag.ExitOnError)
+	fs := c.newFlagSet("result")
 	var (
 		projectPath = fs.String("project", ".", "Project path")
 		format      = fs.String("format", "json", "Output format (json, text)")
@@ -37,30 +41,30 @@ func (c *ResultCommand) Execute(ctx context.Context, args []string) error {
 
 	// Парсим аргументы
 	if err := fs.Parse(args); err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return nil, fmt.Errorf("failed to parse arguments: %w", err)
 	}
 
 	// Показываем помощь если запрошено
 	if *help {
 		c.printHelp()
-		return nil
+		return nil, nil
 	}
 
 	// Проверяем существование проекта
 	if _, err := os.Stat(*projectPath); os.IsNotExist(err) {
-		return fmt.Errorf("project path does not exist: %s", *projectPath)
+		return nil, fmt.Errorf("project path does not exist: %s", *projectPath)
 	}
 
 	// Получаем абсолютный путь
 	absPath, err := filepath.Abs(*projectPath)
 	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
 	if *verbose {
-		fmt.Printf("Generating results for project: %s\n", absPath)
-		fmt.Printf("Report type: %s\n", *reportType)
-		fmt.Printf("Output format: %s\n", *format)
+		c.printf("Generating results for project: %s\n", absPath)
+		c.printf("Report type: %s\n", *reportType)
+		c.printf("Output format: %s\n", *format)
 	}
 
 	// Создаем результат
@@ -74,19 +78,19 @@ func (c *ResultCommand) Execute(ctx context.Context, args []string) error {
 	switch *reportType {
 	case "all", "ux":
 		if err := c.collectUXMetrics(result); err != nil {
-			return fmt.Errorf("failed to collect UX metrics: %w", err)
+			return nil, fmt.Errorf("failed to collect UX metrics: %w", err)
 		}
 	}
 
 	if *reportType == "all" || *reportType == "guardrails" {
 		if err := c.collectGuardrailInfo(result); err != nil {
-			return fmt.Errorf("failed to collect guardrail info: %w", err)
+			return nil, fmt.Errorf("failed to collect guardrail info: %w", err)
 		}
 	}
 
 	if *reportType == "all" || *reportType == "tasks" {
 		if err := c.collectTaskInfo(result); err != nil {
-			return fmt.Errorf("failed to collect task info: %w", err)
+			return nil, fmt.Errorf("failed to collect task info: %w", err)
 		}
 	}
 
@@ -103,28 +107,28 @@ func (c *ResultCommand) Execute(ctx context.Context, args []string) error {
 		}
 
 		if err != nil {
-			return fmt.Errorf("failed to marshal result: %w", err)
+			return nil, fmt.Errorf("failed to marshal result: %w", err)
 		}
 
 		if err := os.WriteFile(*output, data, 0o644); err != nil {
-			return fmt.Errorf("failed to write output file: %w", err)
+			return nil, fmt.Errorf("failed to write output file: %w", err)
 		}
 
-		fmt.Printf("Results saved to: %s\n", *output)
-	} else {
+		c.printf("Results saved to: %s\n", *output)
+	} else if !c.jsonOutput {
 		// Выводим в stdout
 		if *format == "json" {
 			data, err := json.MarshalIndent(result, "", "  ")
 			if err != nil {
-				return fmt.Errorf("failed to marshal result: %w", err)
+				return nil, fmt.Errorf("failed to marshal result: %w", err)
 			}
-			fmt.Println(string(data))
+			c.println(string(data))
 		} else {
-			fmt.Println(c.formatAsText(result))
+			c.println(c.formatAsText(result))
 		}
 	}
 
-	return nil
+	return result, nil
 }
 
 // collectUXMetrics собирает UX метрики
@@ -154,16 +158,45 @@ func (c *ResultCommand) collectGuardrailInfo(result *ResultData) error {
 	return nil
 }
 
-// collectTaskInfo собирает информацию о задачах
+// collectTaskInfo собирает статусы задач из tasks/plan.yaml и tasks/status.json проекта
 func (c *ResultCommand) collectTaskInfo(result *ResultData) error {
-	// Здесь можно добавить сбор информации о задачах
-	// Пока возвращаем заглушку
-	result.Tasks = &TaskData{
-		TotalTasks: 0,
-		Completed:  0,
-		Failed:     0,
-		Pending:    0,
+	config := domain.NewTaskflowConfig(result.ProjectPath)
+	statuses, err := taskflowrepo.NewFileSystemTaskflowRepository(config.StatusPath).LoadStatuses()
+	if err != nil {
+		return fmt.Errorf("failed to read task statuses: %w", err)
+	}
+
+	// Задачи без записи в status.json еще не запускались
+	taskIDs := make(map[string]bool, len(statuses))
+	for id := range statuses {
+		taskIDs[id] = true
+	}
+	if data, err := os.ReadFile(config.PlanPath); err == nil {
+		var plan struct {
+			Tasks []struct {
+				ID string `yaml:"id"`
+			} `yaml:"tasks"`
+		}
+		if err := yaml.Unmarshal(data, &plan); err != nil {
+			return fmt.Errorf("failed to parse plan file: %w", err)
+		}
+		for _, task := range plan.Tasks {
+			taskIDs[task.ID] = true
+		}
+	}
+
+	tasks := &TaskData{TotalTasks: len(taskIDs)}
+	for id := range taskIDs {
+		switch statuses[id] {
+		case domain.TaskStateDone:
+			tasks.Completed++
+		case domain.TaskStateFailed:
+			tasks.Failed++
+		default:
+			tasks.Pending++
+		}
 	}
+	result.Tasks = tasks
 	return nil
 }
 
@@ -197,7 +230,7 @@ func (c *ResultCommand) formatAsText(result *ResultData) string {
 
 // printHelp выводит справку по команде
 func (c *ResultCommand) printHelp() {
-	fmt.Printf(`ark result - Show results and reports
+	c.printf(`ark result - Show results and reports
 
 Usage: ark result [options]
 
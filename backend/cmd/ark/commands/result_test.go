@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResultCommand_CollectTaskInfoUsesProjectPaths(t *testing.T) {
+	projectPath := t.TempDir()
+	tasksDir := filepath.Join(projectPath, "tasks")
+	if err := os.MkdirAll(tasksDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plan := "tasks:\n  - id: a\n  - id: b\n  - id: c\n"
+	status := `{"tasks":[{"id":"a","state":"done"},{"id":"b","state":"failed"}]}`
+	if err := os.WriteFile(filepath.Join(tasksDir, "plan.yaml"), []byte(plan), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tasksDir, "status.json"), []byte(status), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &ResultData{ProjectPath: projectPath}
+	if err := (&ResultCommand{}).collectTaskInfo(result); err != nil {
+		t.Fatalf("collectTaskInfo failed: %v", err)
+	}
+
+	want := TaskData{TotalTasks: 3, Completed: 1, Failed: 1, Pending: 1}
+	if *result.Tasks != want {
+		t.Errorf("expected %+v, got %+v", want, *result.Tasks)
+	}
+}
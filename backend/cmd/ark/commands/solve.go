@@ -3,132 +3,202 @@ package commands
 import (
 	"context"
 	"encoding/json"
-	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	appai "shotgun_code/application/ai"
+	"strings"
 	"time"
 )
 
 // SolveCommand представляет команду решения задач
 type SolveCommand struct {
+	commandOutput
 	container *CLIContainer
+	stdin     io.Reader
 }
 
 // NewSolveCommand создает новую команду решения задач
 func NewSolveCommand(container *CLIContainer) *SolveCommand {
 	return &SolveCommand{
 		container: container,
+		stdin:     os.Stdin,
 	}
 }
 
 // Execute выполняет команду решения задач
-func (c *SolveCommand) Execute(ctx context.Context, args []string) error {
+func (c *SolveCommand) Execute(ctx context.Context, args []string) (interface{}, error) {
 	// Создаем флаги для команды
-	fs := flag.NewFlagSet("solve", flag.ExitOnError)
+	fs := c.newFlagSet("solve")
 	var (
-		task        = fs.String("task", "", "Task description to solve")
+		task        = fs.String("task", "", "Task description to solve (\"-\" reads from stdin)")
+		taskFile    = fs.String("task-file", "", "File with the task description")
 		projectPath = fs.String("project", ".", "Project path")
 		output      = fs.String("output", "", "Output file for solution (JSON)")
 		provider    = fs.String("provider", "openai", "AI provider (openai, gemini, localai)")
 		model       = fs.String("model", "", "AI model to use")
+		apply       = fs.Bool("apply", true, "Write the edits to the project; -apply=false only previews them")
+		diffOut     = fs.String("diff-out", "", "Write the full diff of the changes to this file")
+		noCache     = fs.Bool("no-cache", false, "Always query the provider instead of reusing a cached response")
 		verbose     = fs.Bool("verbose", false, "Verbose output")
 		help        = fs.Bool("help", false, "Show help")
 	)
 
 	// Парсим аргументы
 	if err := fs.Parse(args); err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return nil, fmt.Errorf("failed to parse arguments: %w", err)
 	}
 
 	// Показываем помощь если запрошено
 	if *help {
 		c.printHelp()
-		return nil
+		return nil, nil
 	}
 
-	// Проверяем обязательные параметры
-	if *task == "" {
-		return fmt.Errorf("task description is required (use -task flag)")
+	// Получаем текст задачи из флага, файла или stdin
+	taskText, err := c.resolveTask(*task, *taskFile)
+	if err != nil {
+		return nil, err
 	}
 
 	// Проверяем существование проекта
 	if _, err := os.Stat(*projectPath); os.IsNotExist(err) {
-		return fmt.Errorf("project path does not exist: %s", *projectPath)
+		return nil, fmt.Errorf("project path does not exist: %s", *projectPath)
 	}
 
 	// Получаем абсолютный путь
 	absPath, err := filepath.Abs(*projectPath)
 	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
+	c.container.LoadProjectEnv(absPath)
 
 	if *verbose {
-		fmt.Printf("Solving task: %s\n", *task)
-		fmt.Printf("Project path: %s\n", absPath)
-		fmt.Printf("AI provider: %s\n", *provider)
+		c.printf("Solving task: %s\n", taskText)
+		c.printf("Project path: %s\n", absPath)
+		c.printf("AI provider: %s\n", *provider)
 		if *model != "" {
-			fmt.Printf("AI model: %s\n", *model)
+			c.printf("AI model: %s\n", *model)
 		}
 	}
 
 	// Получаем настройки (пока не используем, но могут понадобиться в будущем)
 	_, err = c.container.SettingsService.GetSettingsDTO()
 	if err != nil {
-		return fmt.Errorf("failed to get settings: %w", err)
+		return nil, fmt.Errorf("failed to get settings: %w", err)
 	}
 
 	// Создаем системный промпт
 	systemPrompt := c.createSystemPrompt(absPath, *provider, *model)
 
 	if *verbose {
-		fmt.Printf("System prompt length: %d characters\n", len(systemPrompt))
+		c.printf("System prompt length: %d characters\n", len(systemPrompt))
 	}
 
 	// Генерируем код
-	generatedCode, err := c.container.AIService.GenerateCode(ctx, systemPrompt, *task)
+	generation, err := c.container.AIService.GenerateCodeDetailed(ctx, systemPrompt, taskText, appai.GenerationOptions{
+		Model:   *model,
+		NoCache: *noCache,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to generate code: %w", err)
+		return nil, fmt.Errorf("failed to generate code: %w", err)
 	}
+	generatedCode := generation.Content
 
 	if *verbose {
-		fmt.Printf("Generated code length: %d characters\n", len(generatedCode))
+		c.printf("Generated code length: %d characters\n", len(generatedCode))
+		c.printf("Served by: %s (%s)\n", generation.Provider, generation.Model)
+		if generation.Cached {
+			c.printf("Response taken from cache (use -no-cache to query the provider)\n")
+		}
+	}
+
+	// Применяем правки (или показываем их в dry-run) и строим diff
+	changes, err := c.applyGeneratedEdits(ctx, absPath, generatedCode, *apply)
+	if err != nil {
+		return nil, err
 	}
 
 	// Создаем результат решения
 	solveResult := &SolveResult{
-		Task:          *task,
+		Task:          taskText,
 		ProjectPath:   absPath,
-		Provider:      *provider,
-		Model:         *model,
+		Provider:      generation.Provider,
+		Model:         generation.Model,
 		GeneratedCode: generatedCode,
+		Changes:       changes,
 		Timestamp:     time.Now(),
 	}
 
+	if changes == nil {
+		c.println("AI response contained no edits; nothing to apply")
+	} else {
+		c.printChanges(changes)
+		if *diffOut != "" {
+			if err := os.WriteFile(*diffOut, []byte(changes.Diff), 0o644); err != nil {
+				return nil, fmt.Errorf("failed to write diff file: %w", err)
+			}
+			solveResult.DiffFile = *diffOut
+			c.printf("Diff saved to: %s\n", *diffOut)
+		}
+	}
+
 	// Выводим результат
 	if *output != "" {
 		// Сохраняем в файл
 		data, err := json.MarshalIndent(solveResult, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal solve result: %w", err)
+			return nil, fmt.Errorf("failed to marshal solve result: %w", err)
 		}
 
 		if err := os.WriteFile(*output, data, 0o644); err != nil {
-			return fmt.Errorf("failed to write output file: %w", err)
+			return nil, fmt.Errorf("failed to write output file: %w", err)
 		}
 
-		fmt.Printf("Solution saved to: %s\n", *output)
-	} else {
+		c.printf("Solution saved to: %s\n", *output)
+	} else if !c.jsonOutput {
 		// Выводим в stdout
 		data, err := json.MarshalIndent(solveResult, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal solve result: %w", err)
+			return nil, fmt.Errorf("failed to marshal solve result: %w", err)
 		}
 
-		fmt.Println(string(data))
+		c.println(string(data))
+	}
+
+	return solveResult, nil
+}
+
+// resolveTask возвращает текст задачи из --task, --task-file или stdin (--task -)
+func (c *SolveCommand) resolveTask(task, taskFile string) (string, error) {
+	if task != "" && taskFile != "" {
+		return "", fmt.Errorf("--task and --task-file cannot be used together")
 	}
 
-	return nil
+	var text string
+	switch {
+	case taskFile != "":
+		data, err := os.ReadFile(taskFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read task file: %w", err)
+		}
+		text = string(data)
+	case task == "-":
+		data, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read task from stdin: %w", err)
+		}
+		text = string(data)
+	default:
+		text = task
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("task description is required (use -task, -task - or -task-file)")
+	}
+	return text, nil
 }
 
 // createSystemPrompt создает системный промпт для AI
@@ -155,22 +225,28 @@ Please provide:
 - Appropriate comments and documentation
 - Error handling where necessary
 - Tests if applicable
-- Any additional context or explanations
 
-Focus on writing code that integrates well with the existing codebase.`
+Focus on writing code that integrates well with the existing codebase.
+
+Respond with the changes as Edits JSON in a single json code block:
+{"schemaVersion": "1.0", "edits": [{"id": "e1", "kind": "fullFile", "op": "create|modify|delete",
+"path": "path/relative/to/project", "language": "go", "content": "complete new file content"}]}
+Each edit replaces the whole file, so content must be the full file.`
 
 	return prompt
 }
 
 // printHelp выводит справку по команде
 func (c *SolveCommand) printHelp() {
-	fmt.Printf(`ark solve - Solve coding tasks using AI
+	c.printf(`ark solve - Solve coding tasks using AI
 
 Usage: ark solve [options]
 
 Options:
   -task string
-        Task description to solve (required)
+        Task description to solve; "-" reads it from stdin
+  -task-file string
+        File with the task description (cannot be combined with -task)
   -project string
         Project path (default ".")
   -output string
@@ -179,6 +255,14 @@ Options:
         AI provider: openai, gemini, localai (default "openai")
   -model string
         AI model to use (uses default if not specified)
+  -apply
+        Write the edits to the project (default true); -apply=false previews
+        the edits and their diff without changing any file
+  -diff-out string
+        Write the full diff of the changes to this file
+  -no-cache
+        Always query the provider; by default an identical earlier request
+        is answered from the response cache
   -verbose
         Verbose output
   -help
@@ -189,15 +273,20 @@ Examples:
   ark solve --task "implement user authentication" --project ./my-app
   ark solve --task "add unit tests" --provider gemini --output solution.json
   ark solve --task "refactor database queries" --verbose
+  ark solve --task "rename config fields" --apply=false --diff-out changes.diff
+  ark solve --task-file TASK.md
+  cat TASK.md | ark solve --task -
 `)
 }
 
 // SolveResult представляет результат решения задачи
 type SolveResult struct {
-	Task          string    `json:"task"`
-	ProjectPath   string    `json:"project_path"`
-	Provider      string    `json:"provider"`
-	Model         string    `json:"model"`
-	GeneratedCode string    `json:"generated_code"`
-	Timestamp     time.Time `json:"timestamp"`
+	Task          string        `json:"task"`
+	ProjectPath   string        `json:"project_path"`
+	Provider      string        `json:"provider"`
+	Model         string        `json:"model"`
+	GeneratedCode string        `json:"generated_code"`
+	Changes       *SolveChanges `json:"changes,omitempty"`
+	DiffFile      string        `json:"diff_file,omitempty"`
+	Timestamp     time.Time     `json:"timestamp"`
 }
@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"shotgun_code/application/diff"
+	"shotgun_code/domain"
+	"strings"
+)
+
+// SolveChanges описывает правки из ответа AI и их diff
+type SolveChanges struct {
+	DryRun  bool                  `json:"dry_run"`
+	Results []*domain.ApplyResult `json:"results"`
+	Summary *domain.DiffSummary   `json:"summary"`
+	Diff    string                `json:"-"`
+}
+
+var editsBlockPattern = regexp.MustCompile("(?s)```(?:json)?\\s*(\\{.*?\\})\\s*```")
+
+// parseEdits извлекает Edits JSON из ответа AI: из блока ```json или из всего ответа.
+// Ответ без правок возвращает nil
+func parseEdits(response string) (*domain.EditsJSON, error) {
+	candidates := make([]string, 0, 2)
+	for _, match := range editsBlockPattern.FindAllStringSubmatch(response, -1) {
+		candidates = append(candidates, match[1])
+	}
+	if start, end := strings.Index(response, "{"), strings.LastIndex(response, "}"); start >= 0 && end > start {
+		candidates = append(candidates, response[start:end+1])
+	}
+
+	var parseErr error
+	for _, candidate := range candidates {
+		var edits domain.EditsJSON
+		if err := json.Unmarshal([]byte(candidate), &edits); err != nil {
+			parseErr = err
+			continue
+		}
+		if len(edits.Edits) > 0 {
+			return &edits, nil
+		}
+	}
+	// Ответ без Edits JSON (например, только код) ошибкой не считается
+	if parseErr != nil && strings.Contains(response, `"edits"`) {
+		return nil, fmt.Errorf("failed to parse edits from AI response: %w", parseErr)
+	}
+	return nil, nil
+}
+
+// applyGeneratedEdits применяет правки из ответа AI (или, если apply=false, только
+// показывает их через предпросмотр ApplyService) и строит diff затронутых файлов
+func (c *SolveCommand) applyGeneratedEdits(ctx context.Context, projectPath, response string, apply bool) (*SolveChanges, error) {
+	edits, err := parseEdits(response)
+	if err != nil || edits == nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "ark-solve-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	// Снимок затронутых файлов до правок
+	beforeDir := filepath.Join(workDir, "before")
+	var paths []string
+	seen := make(map[string]bool)
+	for _, edit := range edits.Edits {
+		rel, err := diff.ProjectRelPath(projectPath, edit.Path)
+		if err != nil {
+			return nil, err
+		}
+		edit.Path = filepath.Join(projectPath, rel)
+		if seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		paths = append(paths, rel)
+		if err := copyIfExists(edit.Path, filepath.Join(beforeDir, rel)); err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s: %w", rel, err)
+		}
+	}
+
+	changes := &SolveChanges{DryRun: !apply}
+	afterDir := projectPath
+	if apply {
+		changes.Results, err = c.container.ApplyService.ApplyEditsInProject(ctx, edits, projectPath)
+	} else {
+		afterDir = filepath.Join(workDir, "after")
+		changes.Results, err = c.container.ApplyService.PreviewEdits(ctx, edits, projectPath, afterDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply edits: %w", err)
+	}
+
+	changes.Diff, changes.Summary, err = c.diffFiles(ctx, beforeDir, afterDir, paths)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// diffFiles строит git diff каждого файла между beforeDir и afterDir через DiffService
+// и суммирует сводки DiffEngine
+func (c *SolveCommand) diffFiles(ctx context.Context, beforeDir, afterDir string, paths []string) (string, *domain.DiffSummary, error) {
+	var content strings.Builder
+	summary := &domain.DiffSummary{}
+	for _, rel := range paths {
+		before := existingOrDevNull(filepath.Join(beforeDir, rel))
+		after := existingOrDevNull(filepath.Join(afterDir, rel))
+		if before == os.DevNull && after == os.DevNull {
+			continue
+		}
+
+		result, err := c.container.DiffService.GenerateDiff(ctx, before, after, domain.DiffFormatGit)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to diff %s: %w", rel, err)
+		}
+		content.WriteString(relabelDiff(result.Content, before, after, filepath.ToSlash(rel)))
+
+		if s := result.Summary; s != nil {
+			summary.TotalFiles += s.TotalFiles
+			summary.AddedFiles += s.AddedFiles
+			summary.ModifiedFiles += s.ModifiedFiles
+			summary.DeletedFiles += s.DeletedFiles
+			summary.AddedLines += s.AddedLines
+			summary.RemovedLines += s.RemovedLines
+			summary.TotalLines += s.TotalLines
+		}
+	}
+	return content.String(), summary, nil
+}
+
+// relabelDiff заменяет в заголовках git diff временные пути на путь файла в проекте
+func relabelDiff(content, before, after, rel string) string {
+	for _, path := range []string{before, after} {
+		if path == os.DevNull {
+			continue
+		}
+		slashed := strings.TrimPrefix(filepath.ToSlash(path), "/")
+		content = strings.ReplaceAll(content, "a/"+slashed, "a/"+rel)
+		content = strings.ReplaceAll(content, "b/"+slashed, "b/"+rel)
+	}
+	return content
+}
+
+// printChanges выводит сводку изменений: файлы, добавленные и удаленные строки
+func (c *SolveCommand) printChanges(changes *SolveChanges) {
+	if changes.DryRun {
+		c.println("Changes (dry run, nothing was written):")
+	} else {
+		c.println("Changes applied:")
+	}
+	s := changes.Summary
+	c.printf("  %d files changed (%d added, %d modified, %d deleted), +%d -%d lines\n",
+		s.TotalFiles, s.AddedFiles, s.ModifiedFiles, s.DeletedFiles, s.AddedLines, s.RemovedLines)
+	for _, result := range changes.Results {
+		if !result.Success {
+			c.printf("  failed: %s: %s\n", result.Path, result.Error)
+		}
+	}
+}
+
+func existingOrDevNull(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return os.DevNull
+	}
+	return path
+}
+
+func copyIfExists(src, dst string) error {
+	content, err := os.ReadFile(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, content, 0o644)
+}
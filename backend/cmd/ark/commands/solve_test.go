@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"shotgun_code/application/diff"
+	"shotgun_code/domain"
+	"shotgun_code/infrastructure/applyengine"
+	"shotgun_code/infrastructure/diffengine"
+)
+
+func TestSolveCommand_ResolveTask(t *testing.T) {
+	taskFile := filepath.Join(t.TempDir(), "TASK.md")
+	if err := os.WriteFile(taskFile, []byte("# Task\n\nadd tests\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &SolveCommand{stdin: strings.NewReader("from stdin\nsecond line\n")}
+
+	tests := []struct {
+		name     string
+		task     string
+		taskFile string
+		want     string
+		wantErr  bool
+	}{
+		{name: "inline", task: "fix bug", want: "fix bug"},
+		{name: "stdin", task: "-", want: "from stdin\nsecond line"},
+		{name: "file", taskFile: taskFile, want: "# Task\n\nadd tests"},
+		{name: "both", task: "fix bug", taskFile: taskFile, wantErr: true},
+		{name: "missing", wantErr: true},
+		{name: "missing file", taskFile: filepath.Join(t.TempDir(), "none.md"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.resolveTask(tt.task, tt.taskFile)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEdits(t *testing.T) {
+	response := "Here is the change:\n```json\n" +
+		`{"schemaVersion": "1.0", "edits": [{"id": "e1", "kind": "fullFile", "op": "modify", "path": "main.go", "language": "go", "content": "package main\n"}]}` +
+		"\n```\n"
+
+	edits, err := parseEdits(response)
+	if err != nil {
+		t.Fatalf("parseEdits failed: %v", err)
+	}
+	if edits == nil || len(edits.Edits) != 1 || edits.Edits[0].Path != "main.go" {
+		t.Fatalf("unexpected edits %+v", edits)
+	}
+
+	if edits, err := parseEdits("func main() {}"); err != nil || edits != nil {
+		t.Errorf("expected no edits for plain code, got %+v, %v", edits, err)
+	}
+	if _, err := parseEdits(`{"edits": [broken]}`); err == nil {
+		t.Error("expected error for malformed edits")
+	}
+}
+
+func TestSolveCommand_DryRunPreviewsDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	log := &domain.NoopLogger{}
+	config := &domain.ApplyEngineConfig{}
+	cmd := &SolveCommand{container: &CLIContainer{
+		ApplyService: diff.NewApplyService(log, config, applyengine.NewApplyEngine(log, config), nil, nil),
+		DiffService:  diff.NewService(log, diffengine.NewDiffEngine(log)),
+	}}
+	cmd.jsonOutput = true
+
+	project := t.TempDir()
+	original := "package main\n\nfunc main() {}\n"
+	if err := os.WriteFile(filepath.Join(project, "main.go"), []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	response := `{"edits": [` +
+		`{"id": "e1", "kind": "fullFile", "op": "modify", "path": "main.go", "language": "go", "content": "package main\n\nfunc main() {\n\tprintln(1)\n}\n"},` +
+		`{"id": "e2", "kind": "fullFile", "op": "create", "path": "util/util.go", "language": "go", "content": "package util\n"}]}`
+
+	changes, err := cmd.applyGeneratedEdits(context.Background(), project, response, false)
+	if err != nil {
+		t.Fatalf("applyGeneratedEdits failed: %v", err)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(project, "main.go")); string(content) != original {
+		t.Errorf("dry run modified main.go: %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(project, "util", "util.go")); !os.IsNotExist(err) {
+		t.Error("dry run created util/util.go")
+	}
+	if !changes.DryRun || changes.Summary.TotalFiles != 2 || changes.Summary.AddedFiles != 1 || changes.Summary.ModifiedFiles != 1 {
+		t.Errorf("unexpected summary %+v", changes.Summary)
+	}
+	for _, want := range []string{"a/main.go b/main.go", "+\tprintln(1)", "b/util/util.go"} {
+		if !strings.Contains(changes.Diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, changes.Diff)
+		}
+	}
+}
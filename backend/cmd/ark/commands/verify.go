@@ -3,16 +3,18 @@ package commands
 import (
 	"context"
 	"encoding/json"
-	"flag"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"shotgun_code/domain"
+	"strings"
 	"time"
 )
 
 // VerifyCommand represents the verification command
 type VerifyCommand struct {
+	commandOutput
 	container *CLIContainer
 }
 
@@ -23,80 +25,107 @@ func NewVerifyCommand(container *CLIContainer) *VerifyCommand {
 	}
 }
 
-// Execute executes the verification command
-func (c *VerifyCommand) Execute(ctx context.Context, args []string) error {
+// Execute executes the verification command.
+// Errors carry the verify exit code: failures that prevent verification
+// from running at all are reported like build failures (exit 2).
+func (c *VerifyCommand) Execute(ctx context.Context, args []string) (interface{}, error) {
+	data, err := c.run(ctx, args)
+	var exitErr *ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		err = &ExitError{Code: verifyExitBuild, Err: err}
+	}
+	return data, err
+}
+
+func (c *VerifyCommand) run(ctx context.Context, args []string) (interface{}, error) {
 	// Create flags for the command
-	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fs := c.newFlagSet("verify")
 	var (
 		projectPath = fs.String("project", ".", "Project path to verify")
 		languages   = fs.String("languages", "", "Comma-separated list of languages to verify (default: auto-detect)")
+		categories  = fs.String("categories", "", "Comma-separated checks to run: build, typecheck, tests, static (default: all)")
+		changed     = fs.String("changed", "", "Comma-separated changed files; runs only the affected tests instead of smoke tests")
 		output      = fs.String("output", "", "Output file for verification report (JSON)")
+		failOnFlag  = fs.String("fail-on", failOnError, "Lowest severity that fails the run: error, warning, any, never")
+		watch       = fs.Bool("watch", false, "Re-run verification whenever source files change")
+		health      = fs.Bool("health", false, "Score project health from 0 to 100 instead of running the pipeline")
+		weights     = fs.String("health-weights", "", "Comma-separated category=weight overrides for --health, e.g. static=0.3,cycles=0")
 		verbose     = fs.Bool("verbose", false, "Verbose output")
 		help        = fs.Bool("help", false, "Show help")
 	)
 
 	// Parse arguments
 	if err := fs.Parse(args); err != nil {
-		return fmt.Errorf("failed to parse arguments: %w", err)
+		return nil, fmt.Errorf("failed to parse arguments: %w", err)
 	}
 
 	// Show help if requested
 	if *help {
 		c.printHelp()
-		return nil
+		return nil, nil
+	}
+
+	failOn, err := parseFailOn(*failOnFlag)
+	if err != nil {
+		return nil, err
+	}
+	if *watch && c.jsonOutput {
+		return nil, fmt.Errorf("--watch cannot be combined with --json")
 	}
 
 	// Check if project exists
 	if _, err := os.Stat(*projectPath); os.IsNotExist(err) {
-		return fmt.Errorf("project path does not exist: %s", *projectPath)
+		return nil, fmt.Errorf("project path does not exist: %s", *projectPath)
 	}
 
 	// Get absolute path
 	absPath, err := filepath.Abs(*projectPath)
 	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	c.container.LoadProjectEnv(absPath)
+
+	if *health {
+		return c.health(ctx, absPath, *weights)
 	}
 
 	if *verbose {
-		fmt.Printf("Verifying project: %s\n", absPath)
+		c.printf("Verifying project: %s\n", absPath)
 	}
 
 	// Parse languages
 	var languageList []string
 	if *languages != "" {
-		languageList = []string{*languages}
+		languageList = splitList(*languages)
 	} else {
 		// Auto-detect languages
 		languageList, err = c.container.VerificationService.DetectLanguages(ctx, absPath)
 		if err != nil {
-			return fmt.Errorf("failed to detect languages: %w", err)
+			return nil, fmt.Errorf("failed to detect languages: %w", err)
 		}
 		if *verbose {
-			fmt.Printf("Detected languages: %v\n", languageList)
+			c.printf("Detected languages: %v\n", languageList)
 		}
 	}
 
 	// Create verification config
 	config := &domain.VerificationConfig{
-		ProjectPath: absPath,
-		Languages:   languageList,
-		Timeout:     300, // 5 minutes
-		Verbose:     *verbose,
+		ProjectPath:  absPath,
+		Languages:    languageList,
+		Timeout:      300, // 5 minutes
+		Verbose:      *verbose,
+		Categories:   splitList(*categories),
+		ChangedFiles: splitList(*changed),
 	}
 
-	// Run verification pipeline
-	result, err := c.container.VerificationService.RunVerificationPipeline(ctx, config)
-	if err != nil {
-		return fmt.Errorf("verification failed: %w", err)
+	if *watch {
+		return nil, c.watch(ctx, config, failOn)
 	}
 
-	// Create verification result
-	verifyResult := &VerifyResult{
-		ProjectPath: absPath,
-		Languages:   languageList,
-		Success:     result.Success,
-		Steps:       result.Steps,
-		Timestamp:   time.Now(),
+	// Run verification pipeline
+	verifyResult, err := c.verifyOnce(ctx, config, failOn)
+	if err != nil {
+		return nil, err
 	}
 
 	// Output result
@@ -104,48 +133,84 @@ func (c *VerifyCommand) Execute(ctx context.Context, args []string) error {
 		// Save to file
 		data, err := json.MarshalIndent(verifyResult, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal verification result: %w", err)
+			return nil, fmt.Errorf("failed to marshal verification result: %w", err)
 		}
 
 		if err := os.WriteFile(*output, data, 0o644); err != nil {
-			return fmt.Errorf("failed to write output file: %w", err)
+			return nil, fmt.Errorf("failed to write output file: %w", err)
 		}
 
-		fmt.Printf("Verification report saved to: %s\n", *output)
-	} else {
+		c.printf("Verification report saved to: %s\n", *output)
+	} else if !c.jsonOutput {
 		// Print to stdout
-		if result.Success {
-			fmt.Println("✅ Verification completed successfully!")
+		if verifyResult.Success {
+			c.println("✅ Verification completed successfully!")
 		} else {
-			fmt.Println("❌ Verification failed!")
+			c.println("❌ Verification failed!")
 		}
 
 		// Print step results
-		for _, step := range result.Steps {
+		for _, step := range verifyResult.Steps {
 			status := "✅"
 			if !step.Success {
 				status = "❌"
 			}
-			fmt.Printf("%s %s\n", status, step.Name)
+			c.printf("%s %s\n", status, step.Name)
+		}
+		if verifyResult.Summary != nil {
+			c.printf("Project status: %s\n", verifyResult.Summary.Status)
+			for _, category := range verifyResult.Summary.Categories {
+				c.printf("  %-9s %-7s %s\n", category.Category, category.Status, category.Message)
+			}
 		}
+		c.printf("Highest severity: %s (fail-on: %s, exit code: %d)\n", verifyResult.HighestSeverity, failOn, verifyResult.ExitCode)
 
 		// Print detailed results in verbose mode
 		if *verbose {
 			data, err := json.MarshalIndent(verifyResult, "", "  ")
 			if err != nil {
-				return fmt.Errorf("failed to marshal verification result: %w", err)
+				return nil, fmt.Errorf("failed to marshal verification result: %w", err)
 			}
-			fmt.Println("\nDetailed Results:")
-			fmt.Println(string(data))
+			c.println("\nDetailed Results:")
+			c.println(string(data))
 		}
 	}
 
-	return nil
+	if verifyResult.ExitCode != verifyExitOK {
+		return verifyResult, &ExitError{
+			Code: verifyResult.ExitCode,
+			Err:  fmt.Errorf("verification failed: highest severity %q with --fail-on=%s", verifyResult.HighestSeverity, failOn),
+		}
+	}
+
+	return verifyResult, nil
+}
+
+// verifyOnce runs the verification pipeline and grades the result.
+// A failed build or test step still yields a result to grade.
+func (c *VerifyCommand) verifyOnce(ctx context.Context, config *domain.VerificationConfig, failOn string) (*VerifyResult, error) {
+	result, pipelineErr := c.container.VerificationService.RunVerificationPipeline(ctx, config)
+	if pipelineErr != nil && result == nil {
+		return nil, fmt.Errorf("verification failed: %w", pipelineErr)
+	}
+
+	highest := highestSeverity(result)
+	return &VerifyResult{
+		ProjectPath:     config.ProjectPath,
+		Languages:       config.Languages,
+		Success:         result.Success,
+		Steps:           result.Steps,
+		Summary:         result.Summary,
+		HighestSeverity: highest.String(),
+		FailOn:          failOn,
+		ExitCode:        verifyExitCode(highest, failOn),
+		Timestamp:       time.Now(),
+	}, nil
 }
 
 // printHelp prints help for the command
 func (c *VerifyCommand) printHelp() {
-	fmt.Printf(`ark verify - Verify project quality and health
+	c.printf(`ark verify - Verify project quality and health
 
 Usage: ark verify [options]
 
@@ -154,25 +219,84 @@ Options:
         Project path to verify (default ".")
   -languages string
         Comma-separated list of languages to verify (default: auto-detect)
+  -categories string
+        Comma-separated checks to run: build, typecheck, tests, static (default: all)
+  -changed string
+        Comma-separated changed files; runs only the affected tests instead of smoke tests
   -output string
         Output file for verification report (JSON)
+  -fail-on string
+        Lowest severity that fails the run: error, warning, any, never (default "error")
+  -watch
+        Re-run verification whenever source files change (Ctrl+C to stop)
+  -health
+        Score project health from 0 to 100 with a letter grade instead of
+        running the pipeline
+  -health-weights string
+        Comma-separated category=weight overrides for --health; categories:
+        static, coverage, vulnerabilities, cycles, build
   -verbose
         Verbose output
   -help
         Show this help message
 
+Exit codes:
+  0  No findings at or above the --fail-on severity
+  1  Findings at or above the --fail-on severity
+  2  Build/typecheck failed or verification could not run
+
+Severity mapping:
+  build    build-typecheck step failed (always exit 2 unless --fail-on=never)
+  error    smoke tests failed or static analysis reported errors
+  warning  static analysis reported warnings
+  info     static analysis reported info/hint issues
+
+--fail-on:
+  error    exit 1 on error findings (default)
+  warning  exit 1 on warning or error findings
+  any      exit 1 on any finding, including info/hint
+  never    always exit 0
+
+Health score:
+  Weighted average of the categories that could be measured, 0-100:
+  static           issue density, 0 at one error (or two warnings) per file (weight 0.25)
+  coverage         test coverage percentage (weight 0.20)
+  vulnerabilities  minus 25/10/3/1 per critical/high/medium/low vulnerability (weight 0.25)
+  cycles           minus 10 per dependency cycle (weight 0.10)
+  build            share of languages that build (weight 0.20)
+  Grades: A >= 90, B >= 80, C >= 70, D >= 60, F below
+
 Examples:
   ark verify --project ./my-project
+  ark verify --project ./my-project --health --health-weights coverage=0.4
+  ark verify --project ./my-project --fail-on=warning
+  ark verify --project ./my-project --watch
   ark verify --project ./my-project --languages go,typescript
+  ark verify --project ./my-project --categories build,typecheck
   ark verify --project ./my-project --output report.json --verbose
 `)
 }
 
 // VerifyResult represents the result of verification
 type VerifyResult struct {
-	ProjectPath string                     `json:"project_path"`
-	Languages   []string                   `json:"languages"`
-	Success     bool                       `json:"success"`
-	Steps       []*domain.VerificationStep `json:"steps"`
-	Timestamp   time.Time                  `json:"timestamp"`
+	ProjectPath     string                     `json:"project_path"`
+	Languages       []string                   `json:"languages"`
+	Success         bool                       `json:"success"`
+	Steps           []*domain.VerificationStep `json:"steps"`
+	Summary         *domain.ValidationSummary  `json:"summary,omitempty"` // overall status and per-category results
+	HighestSeverity string                     `json:"highest_severity"`  // none, info, warning, error or build
+	FailOn          string                     `json:"fail_on"`
+	ExitCode        int                        `json:"exit_code"`
+	Timestamp       time.Time                  `json:"timestamp"`
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"shotgun_code/domain"
+	"strconv"
+	"strings"
+)
+
+// health runs `ark verify --health`: it scores the project instead of running
+// the verification pipeline
+func (c *VerifyCommand) health(ctx context.Context, projectPath, weightsFlag string) (*domain.ProjectHealth, error) {
+	weights, err := parseHealthWeights(weightsFlag)
+	if err != nil {
+		return nil, err
+	}
+	health, err := c.container.VerificationService.ComputeProjectHealth(ctx, projectPath, &weights)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute project health: %w", err)
+	}
+
+	if !c.jsonOutput {
+		c.printf("Project health: %.1f/100 (%s)\n", health.Score, health.Grade)
+		for _, category := range health.Categories {
+			score := fmt.Sprintf("%5.1f", category.Score)
+			if category.Skipped {
+				score = "    -"
+			}
+			c.printf("  %-15s %s  weight %.2f  %s\n", category.Category, score, category.Weight, category.Message)
+		}
+	}
+	return health, nil
+}
+
+// parseHealthWeights parses --health-weights, e.g. "static=0.3,cycles=0".
+// Categories left out keep their default weight.
+func parseHealthWeights(value string) (domain.HealthWeights, error) {
+	weights := domain.DefaultHealthWeights()
+	for _, item := range splitList(value) {
+		name, raw, ok := strings.Cut(item, "=")
+		if !ok {
+			return weights, fmt.Errorf("invalid health weight %q, expected category=weight", item)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || weight < 0 {
+			return weights, fmt.Errorf("invalid health weight %q: must be a non-negative number", item)
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case domain.HealthCategoryStatic:
+			weights.StaticAnalysis = weight
+		case domain.HealthCategoryCoverage:
+			weights.Coverage = weight
+		case domain.HealthCategoryVulnerabilities:
+			weights.Vulnerabilities = weight
+		case domain.HealthCategoryCycles:
+			weights.Cycles = weight
+		case domain.HealthCategoryBuild:
+			weights.Build = weight
+		default:
+			return weights, fmt.Errorf("unknown health category %q: use static, coverage, vulnerabilities, cycles or build", name)
+		}
+	}
+	return weights, nil
+}
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"shotgun_code/domain"
+	"testing"
+)
+
+func TestParseHealthWeights(t *testing.T) {
+	weights, err := parseHealthWeights("static=0.5, cycles=0")
+	if err != nil {
+		t.Fatalf("parseHealthWeights failed: %v", err)
+	}
+	expected := domain.DefaultHealthWeights()
+	expected.StaticAnalysis, expected.Cycles = 0.5, 0
+	if weights != expected {
+		t.Errorf("parseHealthWeights = %+v, want %+v", weights, expected)
+	}
+
+	for _, invalid := range []string{"static", "static=-1", "style=0.2"} {
+		if _, err := parseHealthWeights(invalid); err == nil {
+			t.Errorf("parseHealthWeights(%q) should fail", invalid)
+		}
+	}
+}
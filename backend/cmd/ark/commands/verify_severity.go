@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"fmt"
+	"shotgun_code/domain"
+	"strings"
+)
+
+// Verify exit codes
+const (
+	verifyExitOK       = 0 // nothing at or above the --fail-on threshold
+	verifyExitFindings = 1 // findings at or above the --fail-on threshold
+	verifyExitBuild    = 2 // build failed or verification could not run
+)
+
+// --fail-on values
+const (
+	failOnError   = "error"
+	failOnWarning = "warning"
+	failOnAny     = "any"
+	failOnNever   = "never"
+)
+
+// findingSeverity orders verification findings from harmless to fatal
+type findingSeverity int
+
+const (
+	severityNone findingSeverity = iota
+	severityInfo
+	severityWarning
+	severityError
+	severityBuild
+)
+
+// String returns the report name of the severity
+func (s findingSeverity) String() string {
+	switch s {
+	case severityInfo:
+		return "info"
+	case severityWarning:
+		return "warning"
+	case severityError:
+		return "error"
+	case severityBuild:
+		return "build"
+	default:
+		return "none"
+	}
+}
+
+// parseFailOn validates a --fail-on value
+func parseFailOn(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case failOnError, failOnWarning, failOnAny, failOnNever:
+		return value, nil
+	default:
+		return "", fmt.Errorf("invalid --fail-on value %q (expected error, warning, any or never)", value)
+	}
+}
+
+// failOnThreshold returns the lowest severity that fails the run
+func failOnThreshold(failOn string) findingSeverity {
+	switch failOn {
+	case failOnWarning:
+		return severityWarning
+	case failOnAny:
+		return severityInfo
+	default:
+		return severityError
+	}
+}
+
+// verifyExitCode maps the highest finding severity to a process exit code.
+// Build failures always exit 2 unless failOn is "never", which always exits 0.
+func verifyExitCode(highest findingSeverity, failOn string) int {
+	switch {
+	case failOn == failOnNever:
+		return verifyExitOK
+	case highest == severityBuild:
+		return verifyExitBuild
+	case highest >= failOnThreshold(failOn):
+		return verifyExitFindings
+	default:
+		return verifyExitOK
+	}
+}
+
+// highestSeverity finds the most severe finding of a verification run
+func highestSeverity(result *domain.VerificationResult) findingSeverity {
+	highest := severityNone
+	for _, step := range result.Steps {
+		var severity findingSeverity
+		switch step.Name {
+		case "build-typecheck":
+			if !step.Success {
+				severity = severityBuild
+			}
+		case "smoke-tests":
+			if !step.Success {
+				severity = severityError
+			}
+		case "static-analysis":
+			if report, ok := step.Result.(*domain.StaticAnalysisReport); ok {
+				severity = staticReportSeverity(report)
+			}
+		}
+		if severity > highest {
+			highest = severity
+		}
+	}
+	return highest
+}
+
+// staticReportSeverity returns the most severe issue of a static analysis report
+func staticReportSeverity(report *domain.StaticAnalysisReport) findingSeverity {
+	highest := severityNone
+	if report.Summary != nil {
+		switch {
+		case report.Summary.TotalErrors > 0:
+			return severityError
+		case report.Summary.TotalWarnings > 0:
+			highest = severityWarning
+		}
+	}
+	for _, langResult := range report.Results {
+		if langResult == nil {
+			continue
+		}
+		for _, issue := range langResult.Issues {
+			var severity findingSeverity
+			switch strings.ToLower(issue.Severity) {
+			case "error":
+				severity = severityError
+			case "warning":
+				severity = severityWarning
+			default:
+				severity = severityInfo
+			}
+			if severity > highest {
+				highest = severity
+			}
+		}
+	}
+	return highest
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"errors"
+	"shotgun_code/domain"
+	"testing"
+)
+
+func TestVerifyExitCode(t *testing.T) {
+	tests := []struct {
+		highest  findingSeverity
+		failOn   string
+		expected int
+	}{
+		{severityNone, failOnError, 0},
+		{severityWarning, failOnError, 0},
+		{severityError, failOnError, 1},
+		{severityWarning, failOnWarning, 1},
+		{severityInfo, failOnWarning, 0},
+		{severityInfo, failOnAny, 1},
+		{severityNone, failOnAny, 0},
+		{severityBuild, failOnError, 2},
+		{severityBuild, failOnAny, 2},
+		{severityBuild, failOnNever, 0},
+		{severityError, failOnNever, 0},
+	}
+
+	for _, tt := range tests {
+		if got := verifyExitCode(tt.highest, tt.failOn); got != tt.expected {
+			t.Errorf("verifyExitCode(%s, %s) = %d, want %d", tt.highest, tt.failOn, got, tt.expected)
+		}
+	}
+}
+
+func TestParseFailOn(t *testing.T) {
+	if v, err := parseFailOn("WARNING"); err != nil || v != failOnWarning {
+		t.Errorf("parseFailOn(WARNING) = %q, %v", v, err)
+	}
+	if _, err := parseFailOn("critical"); err == nil {
+		t.Error("expected error for unknown value")
+	}
+}
+
+func TestHighestSeverity(t *testing.T) {
+	report := &domain.StaticAnalysisReport{
+		Results: map[string]*domain.StaticAnalysisResult{
+			"go": {Issues: []*domain.StaticIssue{{Severity: "warning"}, {Severity: "info"}}},
+		},
+	}
+	result := &domain.VerificationResult{
+		Steps: []*domain.VerificationStep{
+			{Name: "build-typecheck", Success: true},
+			{Name: "smoke-tests", Success: true},
+			{Name: "static-analysis", Success: true, Result: report},
+		},
+	}
+	if got := highestSeverity(result); got != severityWarning {
+		t.Errorf("expected warning, got %s", got)
+	}
+
+	result.Steps[0] = &domain.VerificationStep{Name: "build-typecheck", Error: errors.New("boom")}
+	if got := highestSeverity(result); got != severityBuild {
+		t.Errorf("expected build, got %s", got)
+	}
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"shotgun_code/domain"
+	"shotgun_code/infrastructure/fsscanner"
+	"syscall"
+	"time"
+)
+
+// watchDebounceDelay is how long verify --watch waits for changes to settle
+const watchDebounceDelay = 300 * time.Millisecond
+
+// watch re-runs verification whenever project files change until SIGINT/SIGTERM
+func (c *VerifyCommand) watch(ctx context.Context, config *domain.VerificationConfig, failOn string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Buffered so that changes during a run trigger exactly one re-run
+	changes := make(chan struct{}, 1)
+	c.container.EventBus.On("projectFilesChanged", func(data ...interface{}) {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	})
+
+	watcher, err := c.container.NewWatcher()
+	if err != nil {
+		return err
+	}
+	watcher.SetDebounceDelay(watchDebounceDelay)
+	watcher.SetIgnoreFunc(fsscanner.NewIgnoreMatcher(c.container.SettingsRepo, config.ProjectPath))
+	if err := watcher.Start(config.ProjectPath); err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer watcher.Stop()
+
+	c.printf("Watching %s for changes (Ctrl+C to stop)\n", config.ProjectPath)
+	c.runWatchCycle(ctx, config, failOn)
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.println("Watch stopped")
+			return nil
+		case <-changes:
+			c.runWatchCycle(ctx, config, failOn)
+		}
+	}
+}
+
+// runWatchCycle runs one verification and prints a one-line summary
+func (c *VerifyCommand) runWatchCycle(ctx context.Context, config *domain.VerificationConfig, failOn string) {
+	started := time.Now()
+	result, err := c.verifyOnce(ctx, config, failOn)
+	if ctx.Err() != nil {
+		return
+	}
+
+	timestamp := started.Format("15:04:05")
+	if err != nil {
+		c.printf("[%s] ❌ ERROR %v\n", timestamp, err)
+		return
+	}
+
+	status := "✅ PASS"
+	if result.ExitCode != verifyExitOK {
+		status = "❌ FAIL"
+	}
+	c.printf("[%s] %s highest severity: %s (%s)\n",
+		timestamp, status, result.HighestSeverity, time.Since(started).Round(time.Millisecond))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"shotgun_code/cmd/ark/commands"
@@ -16,9 +17,10 @@ const (
 
 func main() {
 	// Парсим флаги
-	var showVersion bool
+	var showVersion, jsonOutput bool
 	flag.BoolVar(&showVersion, "version", false, "Show version information")
 	flag.BoolVar(&showVersion, "v", false, "Show version information")
+	flag.BoolVar(&jsonOutput, "json", false, "Emit structured JSON output")
 	flag.Parse()
 
 	// Показываем версию если запрошено
@@ -37,49 +39,76 @@ func main() {
 	// Создаем контекст
 	ctx := context.Background()
 
+	command := args[0]
+	commandArgs := args[1:]
+
+	switch command {
+	case "help", "--help", "-h":
+		printUsage()
+		return
+	case "index", "solve", "result", "verify":
+	default:
+		if jsonOutput {
+			os.Exit(commands.WriteJSONEnvelope(os.Stdout, command, nil, fmt.Errorf("unknown command: %s", command)))
+		}
+		fmt.Printf("Unknown command: %s\n", command)
+		printUsage()
+		os.Exit(1)
+	}
+
+	// В JSON режиме stdout зарезервирован для конверта, логи идут в stderr
+	var logOut io.Writer = os.Stdout
+	if jsonOutput {
+		logOut = os.Stderr
+	}
+
 	// Создаем CLI контейнер
-	container, err := commands.NewCLIContainer(ctx, "", "", false)
+	container, err := commands.NewCLIContainer(ctx, "", "", false, logOut)
 	if err != nil {
+		if jsonOutput {
+			os.Exit(commands.WriteJSONEnvelope(os.Stdout, command, nil, fmt.Errorf("failed to create CLI container: %w", err)))
+		}
 		log.Fatalf("Failed to create CLI container: %v", err)
 	}
 
 	// Создаем CLI команды
-	cli := commands.NewCLI(container)
+	cli := commands.NewCLI(container, jsonOutput)
 
 	// Выполняем команду
-	command := args[0]
-	commandArgs := args[1:]
-
+	var data interface{}
+	var commandName string
 	switch command {
 	case "index":
-		if err := cli.Index(ctx, commandArgs); err != nil {
-			log.Fatalf("Index command failed: %v", err)
-		}
+		commandName = "Index"
+		data, err = cli.Index(ctx, commandArgs)
 	case "solve":
-		if err := cli.Solve(ctx, commandArgs); err != nil {
-			log.Fatalf("Solve command failed: %v", err)
-		}
+		commandName = "Solve"
+		data, err = cli.Solve(ctx, commandArgs)
 	case "result":
-		if err := cli.Result(ctx, commandArgs); err != nil {
-			log.Fatalf("Result command failed: %v", err)
-		}
+		commandName = "Result"
+		data, err = cli.Result(ctx, commandArgs)
 	case "verify":
-		if err := cli.Verify(ctx, commandArgs); err != nil {
-			log.Fatalf("Verify command failed: %v", err)
-		}
-	case "help", "--help", "-h":
-		printUsage()
-	default:
-		fmt.Printf("Unknown command: %s\n", command)
-		printUsage()
-		os.Exit(1)
+		commandName = "Verify"
+		data, err = cli.Verify(ctx, commandArgs)
+	}
+
+	if jsonOutput {
+		os.Exit(commands.WriteJSONEnvelope(os.Stdout, command, data, err))
+	}
+	if err != nil {
+		log.Printf("%s command failed: %v", commandName, err)
+		os.Exit(commands.ExitCode(err))
 	}
 }
 
 func printUsage() {
 	fmt.Printf(`%s - ARK/Shotgun Code CLI
 
-Usage: %s <command> [options]
+Usage: %s [--json] <command> [options]
+
+Global options:
+  --json  - Emit a JSON envelope {command, status, data, error} to stdout
+            and exit with a non-zero code on failure
 
 Commands:
   index   - Index project files and build symbol graph
@@ -91,8 +120,10 @@ Commands:
 Examples:
   %s index --project ./my-project
   %s solve --task "add error handling"
+  cat TASK.md | %s solve --task -
   %s result --format json
+  %s --json verify --project ./my-project
 
 Use '%s <command> --help' for more information about a command.
-`, appName, appName, appName, appName, appName, appName)
+`, appName, appName, appName, appName, appName, appName, appName, appName)
 }
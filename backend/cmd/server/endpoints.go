@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"shotgun_code/cmd/app"
+	"shotgun_code/domain"
+	"shotgun_code/handlers"
+)
+
+// errSemanticUnavailable is returned by semantic endpoints without an embedding provider
+var errSemanticUnavailable = domain.NewConfigurationError("semantic search not available: embedding provider not configured", nil)
+
+// analysisEndpoints maps API methods, named like their desktop App counterparts,
+// to the analysis and semantic handlers of the container
+func analysisEndpoints(c *app.AppContainer) map[string]endpoint {
+	h := c.AnalysisHandler
+	return map[string]endpoint{
+		"BuildSymbolGraph": bind(func(ctx context.Context, p struct{ ProjectRoot, Language string }) (any, error) {
+			return h.BuildSymbolGraph(ctx, p.ProjectRoot, p.Language)
+		}),
+		"FindSymbolDefinition": bind(func(ctx context.Context, p struct{ ProjectRoot, Language, SymbolName, FromFile string }) (any, error) {
+			return h.FindSymbolDefinition(ctx, p.ProjectRoot, p.Language, p.SymbolName, p.FromFile)
+		}),
+		"GetSymbolSuggestions": bind(func(ctx context.Context, p struct {
+			Query, Language string
+			Graph           *domain.SymbolGraph
+		}) (any, error) {
+			return h.GetSymbolSuggestions(ctx, p.Query, p.Language, p.Graph)
+		}),
+		"DetectLanguages": bind(func(ctx context.Context, p struct{ ProjectPath string }) (any, error) {
+			return h.DetectLanguages(ctx, p.ProjectPath)
+		}),
+		"AnalyzeProject": bind(func(ctx context.Context, p struct {
+			ProjectPath string
+			Languages   []string
+		}) (any, error) {
+			return h.AnalyzeProject(ctx, p.ProjectPath, p.Languages)
+		}),
+		"AnalyzeFile": bind(func(ctx context.Context, p struct{ FilePath, Language string }) (any, error) {
+			return h.AnalyzeFile(ctx, p.FilePath, p.Language)
+		}),
+		"DiscoverTests": bind(func(ctx context.Context, p struct{ ProjectPath, Language string }) (any, error) {
+			return h.DiscoverTests(ctx, p.ProjectPath, p.Language)
+		}),
+		"BuildAffectedGraph": bind(func(ctx context.Context, p struct {
+			ChangedFiles []string
+			ProjectPath  string
+		}) (any, error) {
+			return h.BuildAffectedGraph(ctx, p.ChangedFiles, p.ProjectPath)
+		}),
+		"GetFileQuickInfo": bind(func(_ context.Context, p struct{ ProjectPath, FilePath string }) (any, error) {
+			return h.GetFileQuickInfo(p.ProjectPath, p.FilePath)
+		}),
+		"GetImpactPreview": bind(func(_ context.Context, p struct {
+			ProjectPath string
+			FilePaths   []string
+			MaxDepth    int
+		}) (any, error) {
+			return h.GetImpactPreview(p.ProjectPath, p.FilePaths, p.MaxDepth)
+		}),
+		"ScanVulnerabilities": bind(func(ctx context.Context, p struct{ ProjectPath string }) (any, error) {
+			return h.ScanVulnerabilities(ctx, p.ProjectPath)
+		}),
+		"ScanLicenses": bind(func(ctx context.Context, p struct{ ProjectPath string }) (any, error) {
+			return h.ScanLicenses(ctx, p.ProjectPath)
+		}),
+		"GenerateSBOM": bind(func(ctx context.Context, p struct {
+			ProjectPath string
+			Format      domain.SBOMFormat
+		}) (any, error) {
+			return h.GenerateSBOM(ctx, p.ProjectPath, p.Format)
+		}),
+
+		// Semantic handlers take and return JSON documents; params are the request itself
+		"SemanticSearch":          semanticJSON(c, (*handlers.SemanticHandler).Search),
+		"SemanticHybridSearch":    semanticJSON(c, (*handlers.SemanticHandler).HybridSearch),
+		"SemanticFindSimilar":     semanticJSON(c, (*handlers.SemanticHandler).FindSimilar),
+		"SemanticSearchRelated":   semanticJSON(c, (*handlers.SemanticHandler).SearchRelated),
+		"SemanticRetrieveContext": semanticJSON(c, (*handlers.SemanticHandler).RetrieveContext),
+		"SemanticIndexProject": bind(func(ctx context.Context, p struct{ ProjectRoot string }) (any, error) {
+			if c.SemanticHandler == nil {
+				return nil, errSemanticUnavailable
+			}
+			if err := c.SemanticHandler.IndexProject(ctx, p.ProjectRoot); err != nil {
+				return nil, err
+			}
+			return map[string]bool{"indexed": true}, nil
+		}),
+		"SemanticGetStats": bind(func(ctx context.Context, p struct{ ProjectRoot string }) (any, error) {
+			if c.SemanticHandler == nil {
+				return nil, errSemanticUnavailable
+			}
+			stats, err := c.SemanticHandler.GetStats(ctx, p.ProjectRoot)
+			if err != nil {
+				return nil, err
+			}
+			return json.RawMessage(stats), nil
+		}),
+	}
+}
+
+// semanticJSON adapts a semantic handler method that takes a JSON request string.
+// The handler is looked up per call since semantic search initializes lazily.
+func semanticJSON(c *app.AppContainer, method func(*handlers.SemanticHandler, context.Context, string) (string, error)) endpoint {
+	return func(ctx context.Context, params json.RawMessage) (any, error) {
+		if c.SemanticHandler == nil {
+			return nil, errSemanticUnavailable
+		}
+		result, err := method(c.SemanticHandler, ctx, string(params))
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(result), nil
+	}
+}
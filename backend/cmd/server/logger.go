@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+// stdLogger implements domain.Logger on top of the standard logger (stderr)
+type stdLogger struct {
+	verbose bool
+	out     *log.Logger
+}
+
+func newStdLogger(verbose bool) *stdLogger {
+	return &stdLogger{verbose: verbose, out: log.New(os.Stderr, "", log.LstdFlags)}
+}
+
+func (l *stdLogger) Debug(message string) {
+	if l.verbose {
+		l.out.Printf("[DEBUG] %s", message)
+	}
+}
+
+func (l *stdLogger) Info(message string) {
+	if l.verbose {
+		l.out.Printf("[INFO] %s", message)
+	}
+}
+
+func (l *stdLogger) Warning(message string) { l.out.Printf("[WARN] %s", message) }
+
+func (l *stdLogger) Error(message string) { l.out.Printf("[ERROR] %s", message) }
+
+func (l *stdLogger) Fatal(message string) { l.out.Fatalf("[FATAL] %s", message) }
+
+// nopEventBus drops events; there is no frontend to notify
+type nopEventBus struct{}
+
+func (nopEventBus) Emit(string, ...interface{}) {}
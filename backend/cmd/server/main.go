@@ -0,0 +1,80 @@
+// Command server exposes the analysis APIs of the desktop app over HTTP for
+// web frontends and CI. Every request must carry the shared token in the
+// X-Shotgun-Token header; the token is read from -token or SHOTGUN_SERVER_TOKEN.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"shotgun_code/cmd/app"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultAddr                = "127.0.0.1:8787"
+	tokenEnv                   = "SHOTGUN_SERVER_TOKEN"
+	defaultCustomPromptContent = "no additional rules"
+	shutdownTimeout            = 15 * time.Second
+)
+
+func main() {
+	addr := flag.String("addr", defaultAddr, "Address to listen on")
+	token := flag.String("token", os.Getenv(tokenEnv), "Shared token expected in the "+tokenHeader+" header (default $"+tokenEnv+")")
+	verbose := flag.Bool("verbose", false, "Log debug and info messages")
+	flag.Parse()
+
+	if *token == "" {
+		fmt.Fprintf(os.Stderr, "a shared token is required: pass -token or set %s\n", tokenEnv)
+		os.Exit(2)
+	}
+
+	if err := run(*addr, *token, *verbose); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves the API until SIGINT/SIGTERM, then drains requests and shuts the container down
+func run(addr, token string, verbose bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := newStdLogger(verbose)
+	container, err := app.NewHeadlessContainer(ctx, "", defaultCustomPromptContent, logger, nopEventBus{})
+	if err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           NewServer(logger, token, analysisEndpoints(container)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Listening on %s", addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			_ = container.Shutdown(context.Background())
+			return err
+		}
+	case <-ctx.Done():
+		log.Print("Shutting down...")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	httpErr := srv.Shutdown(shutdownCtx)
+	return errors.Join(httpErr, container.Shutdown(shutdownCtx))
+}
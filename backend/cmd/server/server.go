@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"shotgun_code/domain"
+)
+
+const (
+	// tokenHeader carries the shared secret on every API request
+	tokenHeader = "X-Shotgun-Token"
+
+	// maxRequestBytes limits the size of request bodies
+	maxRequestBytes = 16 << 20
+
+	jsonRPCVersion = "2.0"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+)
+
+// errBadParams marks errors from decoding endpoint parameters
+var errBadParams = errors.New("invalid params")
+
+// endpoint handles one API method; params is the raw JSON object of named arguments
+type endpoint func(ctx context.Context, params json.RawMessage) (any, error)
+
+// bind adapts a function taking a decoded parameter struct to an endpoint.
+// Missing params decode to the zero value.
+func bind[P any](fn func(ctx context.Context, params P) (any, error)) endpoint {
+	return func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var params P
+		if len(raw) > 0 && string(raw) != "null" {
+			if err := json.Unmarshal(raw, &params); err != nil {
+				return nil, fmt.Errorf("%w: %v", errBadParams, err)
+			}
+		}
+		return fn(ctx, params)
+	}
+}
+
+// Server exposes API endpoints over HTTP:
+//
+//	POST /api/{method}  body: named params, response: result
+//	POST /rpc           body: JSON-RPC 2.0 request
+//	GET  /healthz       liveness probe, no token required
+type Server struct {
+	log       domain.Logger
+	token     string
+	endpoints map[string]endpoint
+	mux       *http.ServeMux
+}
+
+// NewServer creates a server for the given endpoints guarded by a shared token
+func NewServer(log domain.Logger, token string, endpoints map[string]endpoint) *Server {
+	s := &Server{log: log, token: token, endpoints: endpoints, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("POST /api/{method}", s.authorized(s.handleAPI))
+	s.mux.HandleFunc("POST /rpc", s.authorized(s.handleRPC))
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized rejects requests without the shared token
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get(tokenHeader)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, apiError{Code: string(domain.ErrCodeUnauthorized), Message: "missing or invalid " + tokenHeader})
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBytes)
+		next(w, r)
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// apiError is the error body of /api responses
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	method := r.PathValue("method")
+	call, ok := s.endpoints[method]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, apiError{Code: string(domain.ErrCodeNotFound), Message: "unknown method " + method})
+		return
+	}
+
+	params, err := io.ReadAll(r.Body)
+	if err != nil || (len(bytes.TrimSpace(params)) > 0 && !json.Valid(params)) {
+		writeJSON(w, http.StatusBadRequest, apiError{Code: string(domain.ErrCodeValidationError), Message: "request body must be a JSON object"})
+		return
+	}
+
+	result, err := call(r.Context(), params)
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("API %s failed: %v", method, err))
+		status, code := errorStatus(err)
+		writeJSON(w, status, apiError{Code: code, Message: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// rpcRequest is a JSON-RPC 2.0 request; params must be an object of named arguments
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"` // domain error code
+}
+
+func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusOK, rpcResponse{JSONRPC: jsonRPCVersion, ID: json.RawMessage("null"),
+			Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+		return
+	}
+
+	resp := rpcResponse{JSONRPC: jsonRPCVersion, ID: req.ID}
+	if resp.ID == nil {
+		resp.ID = json.RawMessage("null")
+	}
+	call, ok := s.endpoints[req.Method]
+	switch {
+	case req.JSONRPC != jsonRPCVersion || req.Method == "":
+		resp.Error = &rpcError{Code: rpcInvalidRequest, Message: "expected a JSON-RPC 2.0 request"}
+	case !ok:
+		resp.Error = &rpcError{Code: rpcMethodNotFound, Message: "unknown method " + req.Method}
+	default:
+		result, err := call(r.Context(), req.Params)
+		if err != nil {
+			s.log.Warning(fmt.Sprintf("RPC %s failed: %v", req.Method, err))
+			_, code := errorStatus(err)
+			resp.Error = &rpcError{Code: rpcServerError, Message: err.Error(), Data: code}
+			if errors.Is(err, errBadParams) {
+				resp.Error.Code = rpcInvalidParams
+			}
+		} else {
+			resp.Result = result
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// errorStatus maps an endpoint error to an HTTP status and error code
+func errorStatus(err error) (int, string) {
+	if errors.Is(err, errBadParams) {
+		return http.StatusBadRequest, string(domain.ErrCodeValidationError)
+	}
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) {
+		return http.StatusInternalServerError, string(domain.ErrCodeInternalError)
+	}
+	switch domainErr.Code {
+	case domain.ErrCodeNotFound, domain.ErrCodeTaskNotFound:
+		return http.StatusNotFound, string(domainErr.Code)
+	case domain.ErrCodeValidationError:
+		return http.StatusBadRequest, string(domainErr.Code)
+	case domain.ErrCodePermissionDenied, domain.ErrCodeGuardrailViolation, domain.ErrCodeSafeModeEnabled:
+		return http.StatusForbidden, string(domainErr.Code)
+	case domain.ErrCodeTimeout:
+		return http.StatusGatewayTimeout, string(domainErr.Code)
+	case domain.ErrCodeConfigurationError:
+		return http.StatusServiceUnavailable, string(domainErr.Code)
+	}
+	return http.StatusInternalServerError, string(domainErr.Code)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
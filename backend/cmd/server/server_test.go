@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"shotgun_code/domain"
+	"strings"
+	"testing"
+)
+
+func newTestServer() *Server {
+	return NewServer(&domain.NoopLogger{}, "secret", map[string]endpoint{
+		"Echo": bind(func(_ context.Context, p struct {
+			ProjectPath string
+			MaxDepth    int
+		}) (any, error) {
+			return map[string]any{"path": p.ProjectPath, "depth": p.MaxDepth}, nil
+		}),
+		"Missing": bind(func(context.Context, struct{}) (any, error) {
+			return nil, domain.NewNotFoundError("function", "store.Load")
+		}),
+	})
+}
+
+func doRequest(t *testing.T, s *Server, method, path, token, body string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set(tokenHeader, token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	var decoded map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("response is not JSON: %q", rec.Body.String())
+	}
+	return rec.Code, decoded
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	s := newTestServer()
+
+	if code, _ := doRequest(t, s, http.MethodPost, "/api/Echo", "", `{}`); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", code)
+	}
+	if code, _ := doRequest(t, s, http.MethodPost, "/rpc", "wrong", `{}`); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with a wrong token, got %d", code)
+	}
+	if code, _ := doRequest(t, s, http.MethodGet, "/healthz", "", ""); code != http.StatusOK {
+		t.Errorf("expected health check without token, got %d", code)
+	}
+}
+
+func TestServer_API(t *testing.T) {
+	s := newTestServer()
+
+	code, body := doRequest(t, s, http.MethodPost, "/api/Echo", "secret", `{"projectPath":"/repo","maxDepth":2}`)
+	if code != http.StatusOK || body["path"] != "/repo" || body["depth"] != float64(2) {
+		t.Errorf("unexpected response %d %v", code, body)
+	}
+
+	if code, _ := doRequest(t, s, http.MethodPost, "/api/Echo", "secret", ""); code != http.StatusOK {
+		t.Errorf("expected empty body to mean no params, got %d", code)
+	}
+
+	code, body = doRequest(t, s, http.MethodPost, "/api/Missing", "secret", `{}`)
+	if code != http.StatusNotFound || body["code"] != string(domain.ErrCodeNotFound) {
+		t.Errorf("expected domain not-found error as 404, got %d %v", code, body)
+	}
+
+	if code, _ := doRequest(t, s, http.MethodPost, "/api/Echo", "secret", `{"maxDepth":"deep"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for mistyped params, got %d", code)
+	}
+	if code, _ := doRequest(t, s, http.MethodPost, "/api/Nope", "secret", `{}`); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown method, got %d", code)
+	}
+}
+
+func TestServer_RPC(t *testing.T) {
+	s := newTestServer()
+
+	_, body := doRequest(t, s, http.MethodPost, "/rpc", "secret",
+		`{"jsonrpc":"2.0","id":7,"method":"Echo","params":{"projectPath":"/repo"}}`)
+	result, _ := body["result"].(map[string]any)
+	if body["id"] != float64(7) || result["path"] != "/repo" || body["error"] != nil {
+		t.Errorf("unexpected RPC response %v", body)
+	}
+
+	_, body = doRequest(t, s, http.MethodPost, "/rpc", "secret", `{"jsonrpc":"2.0","id":"a","method":"Nope"}`)
+	if rpcErr, _ := body["error"].(map[string]any); rpcErr["code"] != float64(rpcMethodNotFound) {
+		t.Errorf("expected method-not-found error, got %v", body)
+	}
+
+	_, body = doRequest(t, s, http.MethodPost, "/rpc", "secret", `{"jsonrpc":"2.0","id":1,"method":"Missing"}`)
+	if rpcErr, _ := body["error"].(map[string]any); rpcErr["data"] != string(domain.ErrCodeNotFound) {
+		t.Errorf("expected domain error code in error data, got %v", body)
+	}
+}
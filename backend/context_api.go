@@ -121,6 +121,33 @@ func (a *App) GetFullContextContent(contextID string) (string, error) {
 	return content, nil
 }
 
+// ExportContextBundle packs a stored context, its manifest and the files it
+// references into a ZIP archive and returns the archive path
+func (a *App) ExportContextBundle(contextID string) (string, error) {
+	if a.contextService == nil {
+		return "", a.transformError(domain.NewConfigurationError("context service not available", nil))
+	}
+
+	summary, err := a.contextService.GetContextSummary(a.ctx, contextID)
+	if err != nil {
+		return "", a.transformError(err)
+	}
+	content, err := a.contextService.ReadContextContent(a.ctx, contextID)
+	if err != nil {
+		return "", a.transformError(err)
+	}
+	files, err := a.fileReader.ReadContents(a.ctx, summary.Metadata.SelectedFiles, summary.ProjectPath, nil)
+	if err != nil {
+		return "", a.transformError(err)
+	}
+
+	path, err := a.exportService.ExportContextBundle(summary, content, files)
+	if err != nil {
+		return "", a.transformError(err)
+	}
+	return path, nil
+}
+
 // BuildContextLegacy is deprecated - use BuildContext instead
 func (a *App) BuildContextLegacy() (string, error) {
 	return "", a.transformError(domain.NewConfigurationError("legacy context building is no longer supported", nil))
@@ -293,6 +320,31 @@ func (a *App) GetContextLines(contextID string, startLine, endLine int64) (strin
 	return string(chunkJson), nil
 }
 
+// EstimateContextTokens estimates the token size of a context before it is built,
+// so the UI can warn about huge contexts
+func (a *App) EstimateContextTokens(projectPath string, includedPaths []string, optionsJson string) (int, error) {
+	tokens, err := a.contextHandler.EstimateContextTokens(projectPath, includedPaths, optionsJson)
+	if err != nil {
+		return 0, a.transformError(err)
+	}
+	return tokens, nil
+}
+
+// EstimateContextOptimization reports the tokens the content optimizations in
+// optionsJson would save on the given files, so the UI can weigh them before a build
+func (a *App) EstimateContextOptimization(projectPath string, filePaths []string, optionsJson string) (*domain.OptimizationStats, error) {
+	stats, err := a.contextHandler.EstimateOptimization(a.ctx, projectPath, filePaths, optionsJson)
+	if err != nil {
+		return nil, a.transformError(err)
+	}
+	return stats, nil
+}
+
+// GetSkeletonSupport reports which files skeleton mode can shorten
+func (a *App) GetSkeletonSupport(filePaths []string) map[string]bool {
+	return a.contextHandler.GetSkeletonSupport(filePaths)
+}
+
 // CreateStreamingContext delegates to BuildContext to create a disk-backed context summary
 func (a *App) CreateStreamingContext(projectPath string, includedPaths []string, optionsJson string) (string, error) {
 	return a.BuildContext(projectPath, includedPaths, optionsJson)
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// AI audit outcomes
+const (
+	AIAuditOutcomeSuccess  = "success"
+	AIAuditOutcomeError    = "error"
+	AIAuditOutcomeCacheHit = "cache_hit"
+)
+
+// AIAuditEntry is a record of a single AI request
+type AIAuditEntry struct {
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"requestId"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	Streaming bool      `json:"streaming,omitempty"`
+
+	// PromptHash is the SHA-256 of the system and user prompts; the prompts
+	// themselves are only stored when enabled in the settings
+	PromptHash   string `json:"promptHash"`
+	SystemPrompt string `json:"systemPrompt,omitempty"`
+	UserPrompt   string `json:"userPrompt,omitempty"`
+
+	// Token counts are split from the provider total by prompt length
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	TotalTokens      int     `json:"totalTokens"`
+	Cost             float64 `json:"cost"`
+	Currency         string  `json:"currency,omitempty"`
+
+	LatencyMs int64  `json:"latencyMs"`
+	Outcome   string `json:"outcome"`
+	Error     string `json:"error,omitempty"`
+}
+
+// AIAuditQuery filters AI audit entries; zero fields match everything
+type AIAuditQuery struct {
+	Provider string    `json:"provider,omitempty"`
+	Model    string    `json:"model,omitempty"`
+	Outcome  string    `json:"outcome,omitempty"`
+	Since    time.Time `json:"since,omitempty"`
+	Until    time.Time `json:"until,omitempty"`
+	Limit    int       `json:"limit,omitempty"`
+}
+
+// Matches reports whether the entry passes the query filters
+func (q AIAuditQuery) Matches(e AIAuditEntry) bool {
+	if q.Provider != "" && e.Provider != q.Provider {
+		return false
+	}
+	if q.Model != "" && e.Model != q.Model {
+		return false
+	}
+	if q.Outcome != "" && e.Outcome != q.Outcome {
+		return false
+	}
+	if !q.Since.IsZero() && e.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && e.Timestamp.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// AIAuditRepository stores the AI request audit trail
+type AIAuditRepository interface {
+	// Append adds an entry to the audit trail
+	Append(entry AIAuditEntry) error
+	// Query returns matching entries, newest first
+	Query(query AIAuditQuery) ([]AIAuditEntry, error)
+}
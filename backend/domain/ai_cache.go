@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// AICacheEntry is a cached AI completion. Key hashes the provider, model,
+// prompts and sampling parameters of the request.
+type AICacheEntry struct {
+	Key        string    `json:"key"`
+	Content    string    `json:"content"`
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model"`
+	TokensUsed int       `json:"tokensUsed"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
@@ -8,3 +8,16 @@ type AIProviderGetter interface {
 	// GetProvider returns the current AI provider and selected model
 	GetProvider(ctx context.Context) (AIProvider, string, error)
 }
+
+// AIProviderChoice is a provider from the fallback chain with its model
+type AIProviderChoice struct {
+	Name     string // provider type from settings, e.g. "qwen"
+	Provider AIProvider
+	Model    string
+}
+
+// AIProviderChainGetter provides the selected provider followed by the
+// configured fallback providers, in the order they should be tried
+type AIProviderChainGetter interface {
+	GetProviderChain(ctx context.Context) ([]AIProviderChoice, error)
+}
@@ -27,6 +27,14 @@ type CallEdge struct {
 	CallType string `json:"callType"` // direct, method, callback, etc.
 }
 
+// DependencyGraphOptions configures how a dependency graph is built
+type DependencyGraphOptions struct {
+	// Languages restricts the graph to one module system; combined by default
+	Languages []string
+	// IncludeExternal adds the external modules files import as terminal nodes
+	IncludeExternal bool
+}
+
 // DependencyGraph represents file/package dependencies
 type DependencyGraph struct {
 	Nodes map[string]*DependencyNode `json:"nodes"`
@@ -58,6 +66,33 @@ type CyclicDependency struct {
 	Type  string   `json:"type"`  // "file" or "package"
 }
 
+// FileDependencyRank is a dependency graph node ranked by its dependents
+type FileDependencyRank struct {
+	Path         string `json:"path"`         // node ID: file path or Go package directory
+	Type         string `json:"type"`         // "file" or "package"
+	Dependents   int    `json:"dependents"`   // files importing it
+	Dependencies int    `json:"dependencies"` // nodes it imports
+}
+
+// CycleEdgeUsage describes how much one node of a cycle uses the next one
+type CycleEdgeUsage struct {
+	From       string   `json:"from"`       // importing node ID
+	To         string   `json:"to"`         // imported node ID
+	ImportPath string   `json:"importPath"` // import path/statement
+	Line       int      `json:"line"`       // line of import
+	Symbols    []string `json:"symbols"`    // symbols of To referenced from From
+	Usages     int      `json:"usages"`     // total references across the edge
+}
+
+// CycleBreakSuggestion proposes the cheapest edge to cut in a dependency cycle
+type CycleBreakSuggestion struct {
+	Cycle       []string         `json:"cycle"`       // nodes forming the cycle
+	Edge        CycleEdgeUsage   `json:"edge"`        // suggested edge to remove
+	Edges       []CycleEdgeUsage `json:"edges"`       // usage of every edge of the cycle
+	NewPackage  string           `json:"newPackage"`  // package/module to extract the used symbols into
+	Explanation string           `json:"explanation"` // human-readable rationale
+}
+
 // CallGraphBuilder builds call graphs from source code
 type CallGraphBuilder interface {
 	// Build builds call graph for the project
@@ -75,12 +110,17 @@ type CallGraphBuilder interface {
 	// GetImpact returns all functions affected if given function changes
 	GetImpact(functionID string, maxDepth int) []CallNode
 
-	// BuildDependencyGraph builds file/package dependency graph
-	BuildDependencyGraph(projectRoot string) (*DependencyGraph, error)
+	// BuildDependencyGraph builds file/package dependency graph.
+	// Optional languages restrict it to one module system; combined by default.
+	BuildDependencyGraph(projectRoot string, languages ...string) (*DependencyGraph, error)
 
 	// FindCyclicDependencies finds all cyclic dependencies
 	FindCyclicDependencies(projectRoot string) ([]CyclicDependency, error)
 
+	// SuggestCycleBreak picks the edge of a cycle with the fewest symbols used
+	// across it and suggests where to extract them
+	SuggestCycleBreak(cycle CyclicDependency) (*CycleBreakSuggestion, error)
+
 	// GetFileDependencies returns files that a file depends on
 	GetFileDependencies(filePath string) []DependencyNode
 
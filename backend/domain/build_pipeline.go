@@ -4,27 +4,39 @@ import "context"
 
 // BuildResult представляет результат сборки
 type BuildResult struct {
-	Success     bool                   `json:"success"`
-	Language    string                 `json:"language"`
-	ProjectPath string                 `json:"projectPath"`
-	Output      string                 `json:"output"`
-	Error       string                 `json:"error,omitempty"`
-	Duration    float64                `json:"duration"`
-	Artifacts   []string               `json:"artifacts,omitempty"`
-	Warnings    []string               `json:"warnings,omitempty"`
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Success     bool                    `json:"success"`
+	Language    string                  `json:"language"`
+	ProjectPath string                  `json:"projectPath"`
+	Output      string                  `json:"output"`
+	Error       string                  `json:"error,omitempty"`
+	Duration    float64                 `json:"duration"`
+	CacheHit    bool                    `json:"cacheHit"` // все пакеты взяты из кэша сборки (только Go)
+	Artifacts   []string                `json:"artifacts,omitempty"`
+	Warnings    []string                `json:"warnings,omitempty"`
+	Metadata    map[string]interface{}  `json:"metadata,omitempty"`
+	Modules     map[string]*BuildResult `json:"modules,omitempty"` // результаты по модулям go.work, ключ - путь модуля
 }
 
 // TypeCheckResult представляет результат проверки типов
 type TypeCheckResult struct {
-	Success     bool                   `json:"success"`
-	Language    string                 `json:"language"`
-	ProjectPath string                 `json:"projectPath"`
-	Output      string                 `json:"output"`
-	Error       string                 `json:"error,omitempty"`
-	Duration    float64                `json:"duration"`
-	Issues      []*TypeIssue           `json:"issues,omitempty"`
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Success     bool                        `json:"success"`
+	Language    string                      `json:"language"`
+	ProjectPath string                      `json:"projectPath"`
+	Output      string                      `json:"output"`
+	Error       string                      `json:"error,omitempty"`
+	Duration    float64                     `json:"duration"`
+	Issues      []*TypeIssue                `json:"issues,omitempty"`
+	Metadata    map[string]interface{}      `json:"metadata,omitempty"`
+	Modules     map[string]*TypeCheckResult `json:"modules,omitempty"` // результаты по модулям go.work, ключ - путь модуля
+}
+
+// BuildTarget задает build-теги и целевую платформу сборки.
+// Пустые поля означают настройки по умолчанию; Tags, GOOS и GOARCH учитываются только для Go
+type BuildTarget struct {
+	Tags   []string `json:"tags,omitempty"`
+	GOOS   string   `json:"goos,omitempty"`
+	GOARCH string   `json:"goarch,omitempty"`
+	Clean  bool     `json:"clean,omitempty"` // собирать заново без прежних артефактов; кэш сборки Go не сбрасывается
 }
 
 // TypeIssue представляет проблему с типами
@@ -40,13 +52,13 @@ type TypeIssue struct {
 // BuildPipeline определяет интерфейс для build/type-check pipeline
 type BuildPipeline interface {
 	// Build выполняет сборку проекта
-	Build(ctx context.Context, projectPath, language string) (*BuildResult, error)
+	Build(ctx context.Context, projectPath, language string, target BuildTarget) (*BuildResult, error)
 
 	// TypeCheck выполняет проверку типов
-	TypeCheck(ctx context.Context, projectPath, language string) (*TypeCheckResult, error)
+	TypeCheck(ctx context.Context, projectPath, language string, target BuildTarget) (*TypeCheckResult, error)
 
 	// BuildAndTypeCheck выполняет сборку и проверку типов
-	BuildAndTypeCheck(ctx context.Context, projectPath, language string) (*BuildResult, *TypeCheckResult, error)
+	BuildAndTypeCheck(ctx context.Context, projectPath, language string, target BuildTarget) (*BuildResult, *TypeCheckResult, error)
 
 	// GetSupportedLanguages возвращает поддерживаемые языки
 	GetSupportedLanguages() []string
@@ -85,10 +97,12 @@ type ProjectValidationResult struct {
 
 // VerificationConfig представляет конфигурацию verification pipeline
 type VerificationConfig struct {
-	ProjectPath string   `json:"projectPath"`
-	Languages   []string `json:"languages"`
-	Timeout     int      `json:"timeout"` // в секундах
-	Verbose     bool     `json:"verbose"`
+	ProjectPath  string   `json:"projectPath"`
+	Languages    []string `json:"languages"`
+	Timeout      int      `json:"timeout"` // в секундах
+	Verbose      bool     `json:"verbose"`
+	Categories   []string `json:"categories,omitempty"`   // категории проверки; пусто - все
+	ChangedFiles []string `json:"changedFiles,omitempty"` // если заданы, запускаются только затронутые тесты
 }
 
 // VerificationResult представляет результат verification pipeline
@@ -99,6 +113,66 @@ type VerificationResult struct {
 	StartedAt   string              `json:"startedAt"`
 	CompletedAt string              `json:"completedAt"`
 	Steps       []*VerificationStep `json:"steps"`
+	Summary     *ValidationSummary  `json:"summary,omitempty"`
+}
+
+// Категории сводной проверки проекта
+const (
+	ValidationCategoryBuild     = "build"
+	ValidationCategoryTypeCheck = "typecheck"
+	ValidationCategoryTests     = "tests"
+	ValidationCategoryStatic    = "static"
+)
+
+// Статусы категорий и сводной проверки проекта
+const (
+	ValidationStatusPassed  = "passed"
+	ValidationStatusFailed  = "failed"
+	ValidationStatusSkipped = "skipped"
+)
+
+// AllValidationCategories возвращает все категории проверки в порядке выполнения
+func AllValidationCategories() []string {
+	return []string{ValidationCategoryBuild, ValidationCategoryTypeCheck, ValidationCategoryTests, ValidationCategoryStatic}
+}
+
+// ValidationSummaryConfig задает параметры сводной проверки проекта
+type ValidationSummaryConfig struct {
+	ProjectPath  string   `json:"projectPath"`
+	Languages    []string `json:"languages,omitempty"`    // пусто - определить автоматически
+	Categories   []string `json:"categories,omitempty"`   // пусто - все категории
+	ChangedFiles []string `json:"changedFiles,omitempty"` // если заданы, запускаются только затронутые тесты, иначе smoke тесты
+}
+
+// ValidationCategoryResult представляет результат одной категории проверки
+type ValidationCategoryResult struct {
+	Category string      `json:"category"`
+	Status   string      `json:"status"` // passed, failed или skipped
+	Message  string      `json:"message,omitempty"`
+	Duration float64     `json:"duration"`
+	Details  interface{} `json:"details,omitempty"` // результаты по языкам или отчет анализатора
+}
+
+// ValidationSummary представляет сводный результат проверки проекта:
+// общий статус и результаты по категориям в порядке выполнения
+type ValidationSummary struct {
+	ProjectPath string                      `json:"projectPath"`
+	Languages   []string                    `json:"languages"`
+	Success     bool                        `json:"success"`
+	Status      string                      `json:"status"` // passed или failed
+	Categories  []*ValidationCategoryResult `json:"categories"`
+	StartedAt   string                      `json:"startedAt"`
+	CompletedAt string                      `json:"completedAt"`
+}
+
+// Category возвращает результат категории или nil, если она не запускалась
+func (s *ValidationSummary) Category(name string) *ValidationCategoryResult {
+	for _, category := range s.Categories {
+		if category.Category == name {
+			return category
+		}
+	}
+	return nil
 }
 
 // SandboxConfig определяет конфигурацию песочницы
@@ -0,0 +1,65 @@
+package domain
+
+import "context"
+
+// EventCommandOutput is emitted with a CommandOutputLine for every line a
+// build, test, static analysis or other external command writes
+const EventCommandOutput = "command:output"
+
+// Streams of CommandOutputLine
+const (
+	CommandStreamStdout = "stdout"
+	CommandStreamStderr = "stderr"
+)
+
+// CommandOutputLine is one line of command output for the live console in the UI.
+// TaskID and Step route the line to the task or pipeline step that ran the command
+type CommandOutputLine struct {
+	TaskID string `json:"taskId,omitempty"`
+	Step   string `json:"step,omitempty"`
+	Stream string `json:"stream"`
+	Line   string `json:"line"`
+}
+
+type commandOutputKey struct{}
+
+type commandOutput struct {
+	bus    EventBus
+	taskID string
+	step   string
+}
+
+// WithCommandOutput streams the output of commands run with ctx to bus
+func WithCommandOutput(ctx context.Context, bus EventBus) context.Context {
+	out, _ := ctx.Value(commandOutputKey{}).(commandOutput)
+	out.bus = bus
+	return context.WithValue(ctx, commandOutputKey{}, out)
+}
+
+// WithCommandStep labels streamed output with the step and task that run the commands;
+// an empty taskID keeps the task of the parent context
+func WithCommandStep(ctx context.Context, taskID, step string) context.Context {
+	out, _ := ctx.Value(commandOutputKey{}).(commandOutput)
+	if taskID != "" {
+		out.taskID = taskID
+	}
+	out.step = step
+	return context.WithValue(ctx, commandOutputKey{}, out)
+}
+
+// CommandOutputFunc returns the callback that publishes output lines of commands
+// run with ctx, or nil when ctx does not stream output
+func CommandOutputFunc(ctx context.Context) func(stream, line string) {
+	out, _ := ctx.Value(commandOutputKey{}).(commandOutput)
+	if out.bus == nil {
+		return nil
+	}
+	return func(stream, line string) {
+		out.bus.Emit(EventCommandOutput, CommandOutputLine{
+			TaskID: out.taskID,
+			Step:   out.step,
+			Stream: stream,
+			Line:   line,
+		})
+	}
+}
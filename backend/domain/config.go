@@ -57,6 +57,12 @@ type Config struct {
 
 	// Agentic chat settings
 	AgenticChat AgenticChatConfig `json:"agenticChat"`
+
+	// Environment variables for external commands run in the project
+	// (e.g. CGO_ENABLED, GOFLAGS, NODE_OPTIONS). $VAR expands to the inherited
+	// value. PATH, LD_* and other variables that change which programs or
+	// libraries run are ignored
+	CommandEnv map[string]string `json:"commandEnv,omitempty"`
 }
 
 // ToolsConfig holds tool execution settings
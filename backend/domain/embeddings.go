@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -17,8 +19,67 @@ const (
 	EmbeddingModelOpenAI3L EmbeddingModel = "text-embedding-3-large"
 	EmbeddingModelLocal    EmbeddingModel = "all-MiniLM-L6-v2"
 	EmbeddingModelCodeBERT EmbeddingModel = "codebert-base"
+	EmbeddingModelFake     EmbeddingModel = "fake-hash" // deterministic offline embeddings for tests
 )
 
+// DefaultEmbeddingModel is used when no embedding model is configured
+const DefaultEmbeddingModel = EmbeddingModelOpenAI3S
+
+// OpenAIEmbeddingModels returns the embedding models selectable for the OpenAI provider
+func OpenAIEmbeddingModels() []EmbeddingModel {
+	return []EmbeddingModel{EmbeddingModelOpenAI3S, EmbeddingModelOpenAI3L, EmbeddingModelOpenAI}
+}
+
+// ResolveEmbeddingModel returns the configured embedding model, falling back
+// to the default for empty or unknown settings
+func ResolveEmbeddingModel(setting string) EmbeddingModel {
+	if model := EmbeddingModel(setting); slices.Contains(OpenAIEmbeddingModels(), model) {
+		return model
+	}
+	return DefaultEmbeddingModel
+}
+
+// Embedding providers selectable in settings
+const (
+	EmbeddingProviderOpenAI = "openai"
+	EmbeddingProviderFake   = "fake"
+)
+
+// Vector stores selectable in settings
+const (
+	VectorStoreSQLite = "sqlite"
+	VectorStoreMemory = "memory" // not persisted, for tests and small projects
+)
+
+// SimilarityMetric is how vector stores compare embeddings. Scores are always
+// "higher is more similar".
+type SimilarityMetric string
+
+const (
+	SimilarityAuto      SimilarityMetric = "auto"      // the embedding model's default
+	SimilarityCosine    SimilarityMetric = "cosine"    // angle only; vectors are normalized
+	SimilarityDot       SimilarityMetric = "dot"       // raw dot product, magnitude matters; thresholds are not scaled
+	SimilarityEuclidean SimilarityMetric = "euclidean" // 1/(1+L2 distance), in (0, 1]
+)
+
+// DefaultSimilarityMetric returns the metric the model's embeddings are meant
+// for. All supported models are trained for cosine similarity; local models
+// with unnormalized output can be switched to dot or euclidean in settings.
+func (m EmbeddingModel) DefaultSimilarityMetric() SimilarityMetric {
+	return SimilarityCosine
+}
+
+// ResolveSimilarityMetric returns the configured metric, falling back to the
+// model's default for "auto", empty or unknown settings
+func ResolveSimilarityMetric(setting string, model EmbeddingModel) SimilarityMetric {
+	switch metric := SimilarityMetric(setting); metric {
+	case SimilarityCosine, SimilarityDot, SimilarityEuclidean:
+		return metric
+	default:
+		return model.DefaultSimilarityMetric()
+	}
+}
+
 // EmbeddingDimensions returns the dimension size for each model
 func (m EmbeddingModel) Dimensions() int {
 	switch m {
@@ -32,6 +93,8 @@ func (m EmbeddingModel) Dimensions() int {
 		return 384
 	case EmbeddingModelCodeBERT:
 		return 768
+	case EmbeddingModelFake:
+		return 256
 	default:
 		return 1536
 	}
@@ -89,11 +152,89 @@ type SemanticSearchRequest struct {
 	Query       string         `json:"query"`
 	ProjectRoot string         `json:"projectRoot"`
 	TopK        int            `json:"topK"`
-	MinScore    float32        `json:"minScore"`
+	MinScore    float32        `json:"minScore"` // a cosine similarity; stores scale it to the configured metric
 	Filters     *SearchFilters `json:"filters,omitempty"`
 	SearchType  SearchType     `json:"searchType"`
+	Snippets    bool           `json:"snippets,omitempty"`  // fill SemanticSearchResult.Snippet
+	Highlight   bool           `json:"highlight,omitempty"` // wrap query terms in snippets with highlight markers
+	// Blend of hybrid search; both zero means the defaults
+	SemanticWeight float32 `json:"semanticWeight,omitempty"`
+	KeywordWeight  float32 `json:"keywordWeight,omitempty"`
+	// Offset skips that many results for paging; TopK is the page size
+	Offset int `json:"offset,omitempty"`
+	// ScoreThreshold drops results whose final score, after hybrid weighting,
+	// is lower; MinScore applies to the raw similarity of candidates
+	ScoreThreshold float32 `json:"scoreThreshold,omitempty"`
+	// Rerank re-scores the top candidates with the configured Reranker, if any
+	Rerank bool `json:"rerank,omitempty"`
+	// Explain fills SemanticSearchResult.Explanation with how each score was computed
+	Explain bool `json:"explain,omitempty"`
+}
+
+// Default hybrid search weights
+const (
+	DefaultSemanticWeight float32 = 0.7
+	DefaultKeywordWeight  float32 = 0.3
+)
+
+// HybridWeights returns the semantic and keyword weights of a hybrid search
+func (r SemanticSearchRequest) HybridWeights() (semantic, keyword float32) {
+	if r.SemanticWeight == 0 && r.KeywordWeight == 0 {
+		return DefaultSemanticWeight, DefaultKeywordWeight
+	}
+	return r.SemanticWeight, r.KeywordWeight
+}
+
+// Validate checks the request parameters
+func (r SemanticSearchRequest) Validate() error {
+	if r.SemanticWeight < 0 || r.KeywordWeight < 0 {
+		return NewValidationError("search weights must be non-negative", map[string]interface{}{
+			"semanticWeight": r.SemanticWeight,
+			"keywordWeight":  r.KeywordWeight,
+		})
+	}
+	if r.Offset < 0 || r.ScoreThreshold < 0 {
+		return NewValidationError("offset and score threshold must be non-negative", map[string]interface{}{
+			"offset":         r.Offset,
+			"scoreThreshold": r.ScoreThreshold,
+		})
+	}
+	return nil
+}
+
+// Window returns how many top results must be collected to serve the page
+func (r SemanticSearchRequest) Window() int {
+	return r.Offset + r.TopK
 }
 
+// Page drops results under ScoreThreshold and returns the requested page of
+// the remaining ones, which must be sorted by score, along with their count
+func (r SemanticSearchRequest) Page(results []SemanticSearchResult) ([]SemanticSearchResult, int) {
+	if r.ScoreThreshold > 0 {
+		kept := results[:0:0]
+		for _, result := range results {
+			if result.Score >= r.ScoreThreshold {
+				kept = append(kept, result)
+			}
+		}
+		results = kept
+	}
+
+	total := len(results)
+	start := min(r.Offset, total)
+	end := total
+	if r.TopK > 0 {
+		end = min(start+r.TopK, total)
+	}
+	return results[start:end], total
+}
+
+// Highlight markers wrapped around query terms in search snippets
+const (
+	HighlightStart = "\u27e6" // ⟦
+	HighlightEnd   = "\u27e7" // ⟧
+)
+
 // SearchFilters for filtering search results
 type SearchFilters struct {
 	Languages   []string    `json:"languages,omitempty"`
@@ -102,6 +243,36 @@ type SearchFilters struct {
 	ExcludeDirs []string    `json:"excludeDirs,omitempty"`
 }
 
+// IsEmpty reports whether the filters don't restrict anything
+func (f *SearchFilters) IsEmpty() bool {
+	return f == nil || (len(f.Languages) == 0 && len(f.ChunkTypes) == 0 && len(f.FilePaths) == 0 && len(f.ExcludeDirs) == 0)
+}
+
+// Matches reports whether a chunk passes the filters. FilePaths are prefixes;
+// ExcludeDirs exclude any path containing them. Nil filters match everything.
+func (f *SearchFilters) Matches(chunk *CodeChunk) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Languages) > 0 && !slices.Contains(f.Languages, chunk.Language) {
+		return false
+	}
+	if len(f.ChunkTypes) > 0 && !slices.Contains(f.ChunkTypes, chunk.ChunkType) {
+		return false
+	}
+	if len(f.FilePaths) > 0 && !slices.ContainsFunc(f.FilePaths, func(prefix string) bool {
+		return strings.HasPrefix(chunk.FilePath, prefix)
+	}) {
+		return false
+	}
+	for _, dir := range f.ExcludeDirs {
+		if strings.Contains(chunk.FilePath, dir) {
+			return false
+		}
+	}
+	return true
+}
+
 // SearchType represents the type of search
 type SearchType string
 
@@ -109,6 +280,8 @@ const (
 	SearchTypeSemantic SearchType = "semantic"
 	SearchTypeKeyword  SearchType = "keyword"
 	SearchTypeHybrid   SearchType = "hybrid"
+	// SearchTypeAuto runs a hybrid search weighted by the shape of the query
+	SearchTypeAuto SearchType = "auto"
 )
 
 // SemanticSearchResult represents a single search result
@@ -117,6 +290,42 @@ type SemanticSearchResult struct {
 	Score      float32   `json:"score"`
 	Highlights []string  `json:"highlights,omitempty"`
 	Reason     string    `json:"reason,omitempty"`
+
+	// Snippet is the most query-relevant window of the chunk, set on request
+	Snippet          string `json:"snippet,omitempty"`
+	SnippetStartLine int    `json:"snippetStartLine,omitempty"`
+	SnippetEndLine   int    `json:"snippetEndLine,omitempty"`
+
+	// Scores of a re-ranked result: Score is the rerank score and VectorScore
+	// the score the result had before re-ranking
+	VectorScore float32 `json:"vectorScore,omitempty"`
+	RerankScore float32 `json:"rerankScore,omitempty"`
+
+	// Explanation breaks the score down, set when the request asks to Explain
+	Explanation *ScoreBreakdown `json:"explanation,omitempty"`
+}
+
+// ScoreBreakdown explains a search score:
+// SemanticScore*SemanticWeight + KeywordScore*KeywordWeight + the sum of Boosts.
+// The components are similarities, or reciprocal ranks when results are fused
+// by rank. A re-ranked result is scored by its RerankScore instead
+type ScoreBreakdown struct {
+	SemanticScore  float32            `json:"semanticScore"`
+	KeywordScore   float32            `json:"keywordScore"`
+	SemanticWeight float32            `json:"semanticWeight"`
+	KeywordWeight  float32            `json:"keywordWeight"`
+	Boosts         map[string]float32 `json:"boosts,omitempty"` // signal name -> amount added to the score, negative for penalties
+}
+
+// AddBoost records a non-zero boost under name
+func (b *ScoreBreakdown) AddBoost(name string, value float32) {
+	if value == 0 {
+		return
+	}
+	if b.Boosts == nil {
+		b.Boosts = make(map[string]float32)
+	}
+	b.Boosts[name] += value
 }
 
 // SemanticSearchResponse represents the search response
@@ -125,6 +334,10 @@ type SemanticSearchResponse struct {
 	TotalResults int                    `json:"totalResults"`
 	QueryTime    time.Duration          `json:"queryTime"`
 	SearchType   SearchType             `json:"searchType"`
+	// TotalAvailable counts the matches before paging; for semantic search it is
+	// capped by the number of candidates taken from the vector store
+	TotalAvailable int `json:"totalAvailable"`
+	Offset         int `json:"offset,omitempty"`
 }
 
 // SimilarCodeRequest represents a request to find similar code
@@ -159,12 +372,44 @@ type EmbeddingProvider interface {
 	ValidateRequest(req EmbeddingRequest) error
 }
 
+// Reranker re-scores search candidates by their relevance to a query
+type Reranker interface {
+	// Rerank returns a score in [0, 1] for each chunk, in the order given
+	Rerank(ctx context.Context, query string, chunks []CodeChunk) ([]float32, error)
+}
+
 // EmbeddingModelInfo contains information about an embedding model
 type EmbeddingModelInfo struct {
 	Model      EmbeddingModel `json:"model"`
 	Dimensions int            `json:"dimensions"`
-	MaxTokens  int            `json:"maxTokens"`
+	MaxTokens  int            `json:"maxTokens"` // per input text
 	Provider   string         `json:"provider"`
+
+	// Limits of a single GenerateEmbeddings request; 0 means unlimited
+	MaxBatchInputs int `json:"maxBatchInputs,omitempty"`
+	MaxBatchTokens int `json:"maxBatchTokens,omitempty"`
+}
+
+// Batches splits texts into consecutive batches within MaxBatchInputs and
+// MaxBatchTokens. Tokens are estimated at four bytes each; a text over the
+// token cap on its own gets a batch of its own.
+func (i EmbeddingModelInfo) Batches(texts []string) [][]string {
+	var batches [][]string
+	start, tokens := 0, 0
+	for end, text := range texts {
+		textTokens := (len(text) + 3) / 4
+		full := i.MaxBatchInputs > 0 && end-start >= i.MaxBatchInputs
+		overCap := i.MaxBatchTokens > 0 && tokens+textTokens > i.MaxBatchTokens
+		if end > start && (full || overCap) {
+			batches = append(batches, texts[start:end])
+			start, tokens = end, 0
+		}
+		tokens += textTokens
+	}
+	if start < len(texts) {
+		batches = append(batches, texts[start:])
+	}
+	return batches
 }
 
 // VectorStore stores and retrieves embeddings
@@ -175,8 +420,9 @@ type VectorStore interface {
 	// StoreBatch stores multiple embedded chunks
 	StoreBatch(ctx context.Context, projectID string, chunks []EmbeddedChunk) error
 
-	// Search performs vector similarity search
-	Search(ctx context.Context, projectID string, query EmbeddingVector, topK int, minScore float32) ([]SemanticSearchResult, error)
+	// Search performs vector similarity search; filters (may be nil) are applied
+	// before the topK results are selected
+	Search(ctx context.Context, projectID string, query EmbeddingVector, topK int, minScore float32, filters *SearchFilters) ([]SemanticSearchResult, error)
 
 	// Delete removes embeddings for a file
 	Delete(ctx context.Context, projectID string, filePath string) error
@@ -218,6 +464,10 @@ type SemanticSearchService interface {
 	// FindSimilar finds similar code
 	FindSimilar(ctx context.Context, req SimilarCodeRequest) (*SemanticSearchResponse, error)
 
+	// SearchRelated returns the nearest neighbors of the indexed chunk at the
+	// given file location, excluding that chunk
+	SearchRelated(ctx context.Context, projectRoot, filePath string, startLine, topK int) (*SemanticSearchResponse, error)
+
 	// GetClusters returns code clusters
 	GetClusters(ctx context.Context, projectRoot string, numClusters int) ([]ClusterInfo, error)
 
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -23,6 +24,9 @@ const (
 	ErrCodeExternalService    ErrorCode = "EXTERNAL_SERVICE_ERROR"
 	ErrCodeTimeout            ErrorCode = "TIMEOUT"
 	ErrCodePermissionDenied   ErrorCode = "PERMISSION_DENIED"
+	ErrCodeGuardrailViolation ErrorCode = "GUARDRAIL_VIOLATION"
+	ErrCodeSafeModeEnabled    ErrorCode = "SAFE_MODE_ENABLED"
+	ErrCodeReindexRequired    ErrorCode = "REINDEX_REQUIRED"
 )
 
 // DomainError represents a structured error with context and recovery information
@@ -169,6 +173,176 @@ func NewPermissionError(resource, action string) *DomainError {
 	}
 }
 
+// NewGuardrailViolationError creates an error rejecting a task's changes, with
+// the validation result (path and budget violations) in its context
+func NewGuardrailViolationError(result *TaskValidationResult) *DomainError {
+	message := result.Error
+	if message == "" {
+		var reasons []string
+		for _, v := range result.Violations {
+			reasons = append(reasons, v.Message)
+		}
+		for _, v := range result.BudgetViolations {
+			reasons = append(reasons, v.Message)
+		}
+		message = strings.Join(reasons, "; ")
+	}
+	return &DomainError{
+		Code:    ErrCodeGuardrailViolation,
+		Message: fmt.Sprintf("Task %s rejected by guardrails: %s", result.TaskID, message),
+		Context: map[string]interface{}{
+			"taskId":           result.TaskID,
+			"violations":       result.Violations,
+			"budgetViolations": result.BudgetViolations,
+		},
+		Recoverable: false,
+	}
+}
+
+// NewPathBlockedError creates an error rejecting an edit of a path blocked by
+// guardrail policies
+func NewPathBlockedError(path string, violations []GuardrailViolation) *DomainError {
+	reasons := make([]string, 0, len(violations))
+	for _, v := range violations {
+		reasons = append(reasons, v.Message)
+	}
+	return &DomainError{
+		Code:    ErrCodeGuardrailViolation,
+		Message: fmt.Sprintf("Edit of %s blocked by guardrails: %s", path, strings.Join(reasons, "; ")),
+		Context: map[string]interface{}{
+			"path":       path,
+			"violations": violations,
+		},
+		Recoverable: false,
+	}
+}
+
+// NewSafeModeError creates an error rejecting a file-writing operation while
+// safe mode is enabled
+func NewSafeModeError(operation string) *DomainError {
+	return &DomainError{
+		Code:    ErrCodeSafeModeEnabled,
+		Message: fmt.Sprintf("safe mode enabled: %s is blocked; turn off safe mode in settings to modify files", operation),
+		Context: map[string]interface{}{
+			"operation": operation,
+		},
+		Recoverable: false,
+	}
+}
+
+// NewCommandNotAllowedError creates an error rejecting a command whose
+// executable is not on the command runner's allowlist
+func NewCommandNotAllowedError(command string, allowed []string) *DomainError {
+	return &DomainError{
+		Code:    ErrCodePermissionDenied,
+		Message: fmt.Sprintf("command %q is not allowed; permitted executables: %s", command, strings.Join(allowed, ", ")),
+		Context: map[string]interface{}{
+			"command": command,
+			"allowed": allowed,
+		},
+		Recoverable: false,
+	}
+}
+
+// NewReindexRequiredError creates an error rejecting a search of a semantic
+// index built with another embedding model than the active one
+func NewReindexRequiredError(projectID, indexed, active string) *DomainError {
+	return &DomainError{
+		Code:    ErrCodeReindexRequired,
+		Message: fmt.Sprintf("semantic index was built with %s but the active embedding model is %s; re-index the project", indexed, active),
+		Context: map[string]interface{}{
+			"projectId": projectID,
+			"indexed":   indexed,
+			"active":    active,
+		},
+		Recoverable: true,
+	}
+}
+
+// CommandError is returned by a CommandRunner when a command fails; it keeps
+// what the command wrote so callers can parse diagnostics from Stderr
+type CommandError struct {
+	Command  string
+	Args     []string
+	Dir      string
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int // -1 if the command didn't exit by itself (not started, killed)
+	Err      error
+}
+
+// maxCommandErrorStderr limits the stderr excerpt in CommandError messages
+const maxCommandErrorStderr = 2000
+
+func (e *CommandError) Error() string {
+	msg := fmt.Sprintf("command %s failed", e.Command)
+	if e.Dir != "" {
+		msg += " in directory " + e.Dir
+	}
+	msg += fmt.Sprintf(": %v", e.Err)
+	if stderr := strings.TrimSpace(string(e.Stderr)); stderr != "" {
+		if len(stderr) > maxCommandErrorStderr {
+			stderr = "..." + stderr[len(stderr)-maxCommandErrorStderr:]
+		}
+		msg += "\n" + stderr
+	}
+	return msg
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ProviderError is a failed request to an AI or embedding provider. Retryable
+// is set for rate limits, timeouts and server errors; RetryAfter is the delay
+// the provider asked for, 0 if it didn't.
+type ProviderError struct {
+	Provider   string
+	StatusCode int // HTTP status, 0 if the request got no response
+	Retryable  bool
+	RetryAfter time.Duration
+	Err        error
+}
+
+// NewProviderError creates a provider error for an HTTP status, deciding
+// from the status whether the request is worth retrying
+func NewProviderError(provider string, statusCode int, retryAfter time.Duration, cause error) *ProviderError {
+	return &ProviderError{
+		Provider:   provider,
+		StatusCode: statusCode,
+		Retryable:  statusCode == 408 || statusCode == 429 || statusCode >= 500,
+		RetryAfter: retryAfter,
+		Err:        cause,
+	}
+}
+
+func (e *ProviderError) Error() string {
+	msg := e.Provider + " request failed"
+	if e.StatusCode > 0 {
+		msg += fmt.Sprintf(" with status %d", e.StatusCode)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches rate limit and authentication failures with ErrRateLimitExceeded
+// and ErrInvalidAPIKey
+func (e *ProviderError) Is(target error) bool {
+	switch target {
+	case ErrRateLimitExceeded:
+		return e.StatusCode == 429
+	case ErrInvalidAPIKey:
+		return e.StatusCode == 401
+	}
+	return false
+}
+
 // Sentinel errors used across the application domain.
 var (
 	// ErrInvalidAPIKey is returned when an AI provider rejects the API key.
@@ -80,6 +80,19 @@ const (
 	BudgetUnitCount BudgetUnit = "count"
 )
 
+// GuardrailPolicySet — сохраняемый набор политик guardrails и бюджетов
+type GuardrailPolicySet struct {
+	Policies []GuardrailPolicy
+	Budgets  []BudgetPolicy
+}
+
+// GuardrailPolicyStore хранит политики guardrails и бюджетов между запусками
+type GuardrailPolicyStore interface {
+	// Load возвращает nil, если политики еще не сохранялись
+	Load() (*GuardrailPolicySet, error)
+	Save(set GuardrailPolicySet) error
+}
+
 // GuardrailViolation нарушение guardrail
 type GuardrailViolation struct {
 	PolicyID   string
@@ -112,8 +125,16 @@ type GuardrailConfig struct {
 	EnableTaskValidation bool
 	EnableBudgetTracking bool
 	EnablePathValidation bool
+	// AllowedPaths ограничивает правки этими glob-паттернами (поддерживается **);
+	// пустой список разрешает любые пути
+	AllowedPaths []string
+	// DeniedPaths запрещает правки путей, подходящих под паттерны; имеет приоритет над AllowedPaths
+	DeniedPaths []string
 }
 
+// DefaultDeniedPaths пути, правки которых запрещены по умолчанию
+var DefaultDeniedPaths = []string{"vendor/**", ".git/**", "go.sum"}
+
 // TaskValidationResult результат валидации задачи
 type TaskValidationResult struct {
 	TaskID           string
@@ -128,6 +149,7 @@ type TaskValidationResult struct {
 type OPAViolation struct {
 	Type    string
 	Message string
+	Rule    string // паттерн, сработавший для пути
 }
 
 // OPAValidationResult результат валидации OPA
@@ -135,6 +157,8 @@ type OPAValidationResult struct {
 	Valid            bool
 	Violations       []OPAViolation
 	EphemeralAllowed bool
+	// MatchedRule правило allow/deny, решившее исход проверки пути, например "deny:vendor/**"
+	MatchedRule string
 }
 
 // GuardrailService интерфейс для сервиса guardrails
@@ -30,6 +30,11 @@ type EventBus interface {
 	Emit(eventName string, data ...interface{})
 }
 
+// EventBusSetter реализуют сервисы, публикующие события для UI
+type EventBusSetter interface {
+	SetEventBus(bus EventBus)
+}
+
 // TreeBuilder определяет интерфейс для построения дерева файлов
 type TreeBuilder interface {
 	BuildTree(dirPath string, useGitignore bool, useCustomIgnore bool) ([]*FileNode, error)
@@ -46,6 +51,12 @@ type FileContentReader interface {
 	) (map[string]string, error)
 }
 
+// PathExpander is implemented by readers that can list the files
+// ReadContents would read for the paths, without reading them
+type PathExpander interface {
+	ExpandPaths(filePaths []string, rootDir string) []string
+}
+
 // GitRepository определяет интерфейс для работы с Git
 type GitRepository interface {
 	GetUncommittedFiles(projectRoot string) ([]FileStatus, error)
@@ -67,6 +78,13 @@ type GitRepository interface {
 	// Read files at specific ref without checkout
 	ListFilesAtRef(projectPath, ref string) ([]string, error)
 	GetFileAtRef(projectPath, filePath, ref string) (string, error)
+	// Commits and worktrees for running tasks on their own branch
+	CreateWorktree(projectPath, worktreePath, branch, baseRef string) error
+	RemoveWorktree(projectPath, worktreePath string) error
+	Commit(projectPath, message string, files []string) (string, error)
+	GenerateDiffAgainst(projectPath, baseRef string) (string, error)
+	MergeBranch(projectPath, branch string) error
+	DeleteBranch(projectPath, branch string) error
 }
 
 // SettingsRepository определяет интерфейс для работы с настройками
@@ -101,6 +119,34 @@ type SettingsRepository interface {
 	SetUseGitignore(use bool)
 	GetUseCustomIgnore() bool
 	SetUseCustomIgnore(use bool)
+	GetPreciseGoAnalysis() bool
+	SetPreciseGoAnalysis(enabled bool)
+	GetEmbeddingProvider() string
+	SetEmbeddingProvider(provider string)
+	GetEmbeddingModel() string
+	SetEmbeddingModel(model string)
+	GetVectorStore() string
+	SetVectorStore(store string)
+	GetSimilarityMetric() string
+	SetSimilarityMetric(metric string)
+	GetBackgroundIndexing() bool
+	SetBackgroundIndexing(enabled bool)
+	GetAIAuditIncludePrompts() bool
+	SetAIAuditIncludePrompts(include bool)
+	GetSafeMode() bool
+	SetSafeMode(enabled bool)
+	GetCommandLimits() CommandLimits
+	SetCommandLimits(limits CommandLimits)
+	GetEnabledLanguages() []string
+	SetEnabledLanguages(languages []string)
+	GetAIProviderFallback() []string
+	SetAIProviderFallback(providers []string)
+	GetOptimizationProfile() string
+	SetOptimizationProfile(profile string)
+	GetCustomOptimization() ContentOptimizeOptions
+	SetCustomOptimization(opts ContentOptimizeOptions)
+	GetVerificationGates() map[string]VerificationGate
+	SetVerificationGates(gates map[string]VerificationGate)
 	GetRecentProjects() []RecentProjectInfo
 	AddRecentProject(path, name string)
 	RemoveRecentProject(path string)
@@ -114,6 +160,8 @@ type FileSystemWatcher interface {
 	Start(rootPath string) error
 	Stop()
 	RefreshAndRescan() error
+	// OnFilesChanged регистрирует обработчик пакета изменений после debounce
+	OnFilesChanged(callback func(rootDir string, files []string))
 }
 
 // ContextSplitter определяет интерфейс для разбиения большого контекста на части.
@@ -214,6 +262,9 @@ type OPAService interface {
 
 	// ValidateConfig проверяет конфигурацию через OPA политики
 	ValidateConfig(config GuardrailConfig) (*OPAValidationResult, error)
+
+	// SetPathPolicy задает allowlist/denylist путей, проверяемые в ValidatePath
+	SetPathPolicy(allowed, denied []string)
 }
 
 // FileStatProvider определяет интерфейс для получения информации о файлах
@@ -250,6 +301,11 @@ type CommandRunner interface {
 	RunCommandInDir(ctx context.Context, dir, name string, args ...string) ([]byte, error)
 }
 
+// ProjectEnvLoader реализуют исполнители команд, применяющие commandEnv из конфига проекта
+type ProjectEnvLoader interface {
+	LoadProjectEnv(projectRoot string) error
+}
+
 // Task Protocol Verification System Interfaces
 
 // TaskProtocolService defines the interface for task protocol verification
@@ -278,11 +334,12 @@ type ErrorAnalyzer interface {
 
 // CorrectionEngine defines the interface for applying corrections
 type CorrectionEngine interface {
-	// ApplyCorrection applies a single correction step
-	ApplyCorrection(ctx context.Context, step *CorrectionStep, projectPath string) (*CorrectionResult, error)
+	// ApplyCorrection applies a single correction step; with dryRun the files
+	// are left untouched and the result carries the proposed diff
+	ApplyCorrection(ctx context.Context, step *CorrectionStep, projectPath string, dryRun bool) (*CorrectionResult, error)
 
-	// ApplyCorrections applies multiple correction steps
-	ApplyCorrections(ctx context.Context, steps []*CorrectionStep, projectPath string) (*CorrectionResult, error)
+	// ApplyCorrections applies multiple correction steps, see ApplyCorrection for dryRun
+	ApplyCorrections(ctx context.Context, steps []*CorrectionStep, projectPath string, dryRun bool) (*CorrectionResult, error)
 
 	// CanHandle checks if the engine can handle a specific error type
 	CanHandle(error *ErrorDetails) bool
@@ -333,6 +390,7 @@ type FileSystemProvider interface {
 // ContextBuilder определяет интерфейс для построения контекста
 type ContextBuilder interface {
 	// BuildContext builds a context from project files and returns a ContextSummary to prevent OOM issues
+	// Included paths may limit a file to a line range, e.g. "handlers/foo.go:120-180"
 	BuildContext(ctx context.Context, projectPath string, includedPaths []string, options *ContextBuildOptions) (*ContextSummary, error)
 }
 
@@ -371,14 +429,14 @@ type ContextRepository interface {
 
 // IBuildService defines the interface for build service operations
 type IBuildService interface {
-	// Build executes project build
-	Build(ctx context.Context, projectPath, language string) (*BuildResult, error)
+	// Build executes project build for the given build tags and platform
+	Build(ctx context.Context, projectPath, language string, target BuildTarget) (*BuildResult, error)
 
-	// TypeCheck performs type checking
-	TypeCheck(ctx context.Context, projectPath, language string) (*TypeCheckResult, error)
+	// TypeCheck performs type checking for the given build tags and platform
+	TypeCheck(ctx context.Context, projectPath, language string, target BuildTarget) (*TypeCheckResult, error)
 
 	// BuildAndTypeCheck executes build and type checking
-	BuildAndTypeCheck(ctx context.Context, projectPath, language string) (*BuildResult, *TypeCheckResult, error)
+	BuildAndTypeCheck(ctx context.Context, projectPath, language string, target BuildTarget) (*BuildResult, *TypeCheckResult, error)
 
 	// BuildMultiLanguage executes build for multiple languages
 	BuildMultiLanguage(ctx context.Context, projectPath string, languages []string) (map[string]*BuildResult, error)
@@ -540,6 +598,26 @@ type CodeChunker interface {
 	ChunkFile(filePath string, content []byte, symbols []ChunkSymbolInfo) []CodeChunk
 }
 
+// Chunking strategies of a CodeChunker
+const (
+	ChunkStrategySymbol = "symbol" // a chunk per symbol, the code between symbols as is
+	ChunkStrategyWindow = "window" // overlapping windows of whole lines, symbols ignored
+	ChunkStrategyHybrid = "hybrid" // a chunk per symbol, windows over the code between symbols
+)
+
+// ChunkerConfig tunes how a CodeChunker splits files. Zero fields keep the
+// chunker's defaults. Files without symbols are always chunked by windows.
+type ChunkerConfig struct {
+	Strategy  string `json:"strategy,omitempty"`  // symbol, window or hybrid
+	MaxTokens int    `json:"maxTokens,omitempty"` // maximum tokens per chunk
+	Overlap   int    `json:"overlap,omitempty"`   // tokens repeated at the start of the next window
+}
+
+// ChunkerConfigurer is implemented by CodeChunkers that can be tuned
+type ChunkerConfigurer interface {
+	Configure(config ChunkerConfig) error
+}
+
 // ChunkSymbolInfo represents symbol information for chunking
 type ChunkSymbolInfo struct {
 	Name      string `json:"name"`
@@ -557,18 +635,35 @@ type ReferenceFinder interface {
 	// FindReferences finds all references to a symbol in the project
 	FindReferences(ctx context.Context, projectRoot string, symbolName string, symbolKind string) ([]SymbolReference, error)
 
-	// FindUsages finds where a symbol is used (excluding definition)
-	FindUsages(ctx context.Context, projectRoot string, symbolName string) ([]SymbolReference, error)
+	// FindUsages finds where a symbol is used (excluding definition), one page
+	// at a time; limit <= 0 selects the default page size
+	FindUsages(ctx context.Context, projectRoot string, symbolName string, offset, limit int) (*SymbolUsagePage, error)
+
+	// FindImplementations finds Go types that implement the named interface ("Logger" or "domain.Logger")
+	FindImplementations(ctx context.Context, projectRoot string, interfaceName string) ([]SymbolReference, error)
 }
 
 // SymbolReference represents a reference to a symbol in code
 type SymbolReference struct {
-	FilePath     string `json:"filePath"`
-	Line         int    `json:"line"`
-	Column       int    `json:"column"`
-	LineText     string `json:"lineText"`
-	Context      string `json:"context"`
-	IsDefinition bool   `json:"isDefinition"`
+	FilePath     string  `json:"filePath"`
+	Line         int     `json:"line"`
+	Column       int     `json:"column"`
+	LineText     string  `json:"lineText"`
+	Context      string  `json:"context"`
+	IsDefinition bool    `json:"isDefinition"`
+	Confidence   float64 `json:"confidence"` // 0..1, how likely this is a real reference
+	// EnclosingFunction is the function or method containing the reference
+	EnclosingFunction string `json:"enclosingFunction,omitempty"`
+}
+
+// SymbolUsagePage is one page of FindUsages results
+type SymbolUsagePage struct {
+	Usages    []SymbolReference `json:"usages"`
+	Total     int               `json:"total"`
+	Offset    int               `json:"offset"`
+	Limit     int               `json:"limit"`
+	HasMore   bool              `json:"hasMore"`
+	Truncated bool              `json:"truncated"` // the scan stopped early, Total is a lower bound
 }
 
 // =============================================================================
@@ -580,6 +675,9 @@ type GitContextBuilder interface {
 	// GetRecentChanges returns files changed recently, sorted by relevance
 	GetRecentChanges(since string, pathFilter string) ([]RecentChange, error)
 
+	// GetRecentChangesFiltered returns recently changed files filtered by date range, author and path
+	GetRecentChangesFiltered(opts RecentChangesOptions) ([]RecentChange, error)
+
 	// GetCoChangedFiles returns files that are often changed together with the given file
 	GetCoChangedFiles(filePath string, limit int) ([]string, error)
 
@@ -590,6 +688,14 @@ type GitContextBuilder interface {
 	GetRelatedByAuthor(filePath string, limit int) ([]string, error)
 }
 
+// RecentChangesOptions filters the git history used for recent changes
+type RecentChangesOptions struct {
+	Since      string `json:"since,omitempty"`
+	Until      string `json:"until,omitempty"`
+	Author     string `json:"author,omitempty"`
+	PathFilter string `json:"pathFilter,omitempty"`
+}
+
 // RecentChange represents a recently changed file from git history
 type RecentChange struct {
 	FilePath    string    `json:"filePath"`
@@ -618,6 +724,13 @@ type CallGraphBuilder interface {
 
 	// GetCallChain finds call chains between two functions
 	GetCallChain(startID, endID string, maxDepth int) [][]string
+
+	// GetFileDependents returns the files importing a file or, for Go, a package directory
+	GetFileDependents(path string) []string
+
+	// BuildForFile updates the graph for a single changed file (relative
+	// path); nil content means the file was deleted
+	BuildForFile(ctx context.Context, filePath string, content []byte) error
 }
 
 // CallGraph represents a call graph for a project
@@ -642,6 +755,33 @@ type CallGraphEdge struct {
 	Line int    `json:"line,omitempty"`
 }
 
+// FileDependencyRank is a file (or Go package) ranked by how many files depend on it
+type FileDependencyRank struct {
+	Path         string `json:"path"`
+	Type         string `json:"type"` // "file" or "package"
+	Dependents   int    `json:"dependents"`
+	Dependencies int    `json:"dependencies"`
+}
+
+// CycleEdgeUsage is an import edge of a dependency cycle with the symbols used across it
+type CycleEdgeUsage struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	ImportPath string   `json:"importPath"`
+	Line       int      `json:"line"`
+	Symbols    []string `json:"symbols"`
+	Usages     int      `json:"usages"`
+}
+
+// CycleBreakSuggestion is the cheapest edge to cut in a dependency cycle
+type CycleBreakSuggestion struct {
+	Cycle       []string         `json:"cycle"`
+	Edge        CycleEdgeUsage   `json:"edge"`
+	Edges       []CycleEdgeUsage `json:"edges"`
+	NewPackage  string           `json:"newPackage"`
+	Explanation string           `json:"explanation"`
+}
+
 // =============================================================================
 // Project Structure Interface
 // =============================================================================
@@ -673,6 +813,12 @@ type ProjectStructureDetector interface {
 	SuggestRelatedFiles(projectPath, filePath string) ([]string, error)
 }
 
+// LanguageStatsDetector counts source files per language
+type LanguageStatsDetector interface {
+	// Detect returns languages sorted by file count with their percentages; the first one is primary
+	Detect(projectPath string) []LanguageInfo
+}
+
 // ProjectStructureInfo contains detected project structure information
 type ProjectStructureInfo struct {
 	Languages   []string          `json:"languages"`
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// languageAliases maps alternative language names to the canonical ones
+var languageAliases = map[string]string{
+	"golang": "go",
+	"ts":     "typescript",
+	"js":     "javascript",
+	"py":     "python",
+	"kt":     "kotlin",
+	"rs":     "rust",
+	"c#":     "csharp",
+	"cs":     "csharp",
+	"c++":    "cpp",
+}
+
+// extensionLanguages maps source file extensions to canonical language names
+var extensionLanguages = map[string]string{
+	".go":    "go",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".mts":   "typescript",
+	".cts":   "typescript",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".mjs":   "javascript",
+	".cjs":   "javascript",
+	".vue":   "vue",
+	".py":    "python",
+	".java":  "java",
+	".kt":    "kotlin",
+	".rs":    "rust",
+	".cs":    "csharp",
+	".cpp":   "cpp",
+	".cc":    "cpp",
+	".hpp":   "cpp",
+	".c":     "c",
+	".h":     "c",
+	".rb":    "ruby",
+	".php":   "php",
+	".swift": "swift",
+	".dart":  "dart",
+}
+
+// NormalizeLanguage returns the canonical lower-case name of a language ("TS" -> "typescript")
+func NormalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if canonical, ok := languageAliases[language]; ok {
+		return canonical
+	}
+	return language
+}
+
+// LanguageForFile returns the canonical language of a source file by its extension,
+// or an empty string if the extension is not a known source language
+func LanguageForFile(path string) string {
+	return extensionLanguages[strings.ToLower(filepath.Ext(path))]
+}
+
+// LanguageScope is the set of enabled languages shared by analyzers, test runners,
+// symbol/call graph builders and indexers: they skip work for other languages.
+// Language detection still reports every language. An empty scope and a nil
+// *LanguageScope enable all languages.
+type LanguageScope struct {
+	mu        sync.RWMutex
+	languages map[string]bool
+}
+
+// NewLanguageScope creates a scope limited to the given languages
+func NewLanguageScope(languages []string) *LanguageScope {
+	s := &LanguageScope{}
+	s.SetLanguages(languages)
+	return s
+}
+
+// SetLanguages replaces the enabled languages; an empty list enables all languages
+func (s *LanguageScope) SetLanguages(languages []string) {
+	enabled := make(map[string]bool, len(languages))
+	for _, language := range languages {
+		if language = NormalizeLanguage(language); language != "" {
+			enabled[language] = true
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.languages = enabled
+}
+
+// Languages returns the enabled languages sorted by name; empty means all languages
+func (s *LanguageScope) Languages() []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	languages := make([]string, 0, len(s.languages))
+	for language := range s.languages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Enabled reports whether operations should run for the language
+func (s *LanguageScope) Enabled(language string) bool {
+	if s == nil {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.languages) == 0 || s.languages[NormalizeLanguage(language)]
+}
+
+// FileEnabled reports whether a file should be analyzed or indexed.
+// Files that are not in a known source language are always enabled
+func (s *LanguageScope) FileEnabled(path string) bool {
+	language := LanguageForFile(path)
+	return language == "" || s.Enabled(language)
+}
+
+// Filter returns the enabled languages from the list, keeping their order
+func (s *LanguageScope) Filter(languages []string) []string {
+	filtered := make([]string, 0, len(languages))
+	for _, language := range languages {
+		if s.Enabled(language) {
+			filtered = append(filtered, language)
+		}
+	}
+	return filtered
+}
+
+// LanguageScopeSetter is implemented by services that honor the enabled languages
+type LanguageScopeSetter interface {
+	SetLanguageScope(scope *LanguageScope)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLanguageScope_EmptyEnablesAll(t *testing.T) {
+	var nilScope *LanguageScope
+	for _, scope := range []*LanguageScope{nilScope, NewLanguageScope(nil)} {
+		if !scope.Enabled("rust") || !scope.FileEnabled("main.go") {
+			t.Errorf("expected all languages enabled for %v", scope)
+		}
+	}
+}
+
+func TestLanguageScope_LimitsLanguages(t *testing.T) {
+	scope := NewLanguageScope([]string{"Go", "ts"})
+
+	if got := scope.Languages(); !reflect.DeepEqual(got, []string{"go", "typescript"}) {
+		t.Errorf("Languages() = %v", got)
+	}
+	if !scope.Enabled("golang") || !scope.Enabled("TypeScript") || scope.Enabled("python") {
+		t.Error("unexpected Enabled results")
+	}
+	if !scope.FileEnabled("web/app.tsx") || scope.FileEnabled("tool.py") || !scope.FileEnabled("README.md") {
+		t.Error("unexpected FileEnabled results")
+	}
+	if got := scope.Filter([]string{"python", "go", "java"}); !reflect.DeepEqual(got, []string{"go"}) {
+		t.Errorf("Filter() = %v", got)
+	}
+
+	scope.SetLanguages(nil)
+	if !scope.Enabled("python") {
+		t.Error("expected all languages enabled after reset")
+	}
+}
@@ -7,7 +7,9 @@ type ContextMemory interface {
 	SaveContext(ctx *ConversationContext) error
 	GetContext(id string) (*ConversationContext, error)
 	FindContextByTopic(projectRoot, topic string) ([]*ConversationContext, error)
+	FindSimilarContexts(projectRoot, query string, limit int) ([]*ConversationContext, error)
 	GetRecentContexts(projectRoot string, limit int) ([]*ConversationContext, error)
+	PruneContexts(projectRoot string) (removed int, err error)
 	SetPreference(key, value string) error
 	GetPreference(key string) (string, error)
 	GetAllPreferences() (map[string]string, error)
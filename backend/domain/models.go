@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"math"
 	"time"
 )
 
@@ -96,6 +97,10 @@ type AutonomousTaskOptions struct {
 	EnableStaticAnalysis bool    `json:"enableStaticAnalysis"`
 	EnableTests          bool    `json:"enableTests"`
 	EnableSBOM           bool    `json:"enableSBOM"`
+	UseWorktree          bool    `json:"useWorktree"`     // выполнять в отдельном git worktree на ветке shotgun/<taskID>
+	MergeOnSuccess       bool    `json:"mergeOnSuccess"`  // слить ветку задачи после успешного выполнения
+	CommitOnSuccess      bool    `json:"commitOnSuccess"` // закоммитить изменения со сгенерированным сообщением
+	CommitAll            bool    `json:"commitAll"`       // коммитить и несвязанные незакоммиченные изменения
 }
 
 // AutonomousTaskResponse ответ на запуск автономной задачи
@@ -115,6 +120,7 @@ type AutonomousTaskStatus struct {
 	StartedAt              time.Time `json:"startedAt"`
 	UpdatedAt              time.Time `json:"updatedAt"`
 	Error                  string    `json:"error"`
+	CommitHash             string    `json:"commitHash,omitempty"` // коммит с изменениями задачи
 }
 
 // GenericReport общий отчет
@@ -134,6 +140,7 @@ type ContextBuildOptions struct {
 	StripComments        bool   `json:"stripComments"`
 	IncludeManifest      bool   `json:"includeManifest"`
 	IncludeLineNumbers   bool   `json:"includeLineNumbers"`
+	IncludeTree          bool   `json:"includeTree"` // дерево включённых путей в начале контекста
 	MaxTokens            int    `json:"maxTokens"`
 	MaxMemoryMB          int    `json:"maxMemoryMB"`
 	IncludeTests         bool   `json:"includeTests"`
@@ -141,6 +148,7 @@ type ContextBuildOptions struct {
 	ForceStream          bool   `json:"forceStream"`
 	EnableProgressEvents bool   `json:"enableProgressEvents"`
 	OutputFormat         string `json:"outputFormat"` // markdown, xml, json, plain
+	Incremental          bool   `json:"incremental"`  // write content to disk file by file instead of reading everything first
 
 	// Content optimization options
 	ExcludeTests       bool `json:"excludeTests"`       // Исключать тестовые файлы из контекста
@@ -149,6 +157,11 @@ type ContextBuildOptions struct {
 	CompactDataFiles   bool `json:"compactDataFiles"`   // Сжимать JSON/YAML файлы
 	SkeletonMode       bool `json:"skeletonMode"`       // Генерировать только скелет кода (AST-based)
 	TrimWhitespace     bool `json:"trimWhitespace"`     // Удалять trailing whitespace
+
+	// OptimizationProfile заменяет опции оптимизации выше опциями профиля (none, balanced, aggressive, custom)
+	OptimizationProfile string `json:"optimizationProfile,omitempty"`
+
+	SkeletonThresholdKB int `json:"skeletonThresholdKB"` // Скелет только для файлов больше порога (0 - порог по умолчанию)
 }
 
 // Context представляет контекст проекта
@@ -164,6 +177,8 @@ type Context struct {
 	TokenCount  int       `json:"tokenCount"`
 	TotalLines  int64     `json:"totalLines"`
 	TotalChars  int64     `json:"totalChars"`
+
+	Optimization *OptimizationStats `json:"optimization,omitempty"`
 }
 
 // CRITICAL OOM FIX: ContextSummary replaces full Context content to prevent memory issues
@@ -195,6 +210,52 @@ type ContextMetadata struct {
 	ProjectPath    string               `json:"projectPath,omitempty"`
 	ChunksCount    int                  `json:"chunksCount,omitempty"`
 	SplitStrategy  string               `json:"splitStrategy,omitempty"`
+
+	// Optimization is set when content optimizations were enabled for the build
+	Optimization *OptimizationStats `json:"optimization,omitempty"`
+}
+
+// OptimizationStats compares the tokens of context files before and after
+// content optimizations, per file and in total
+type OptimizationStats struct {
+	OriginalTokens  int                     `json:"originalTokens"`
+	OptimizedTokens int                     `json:"optimizedTokens"`
+	SavedPercent    float64                 `json:"savedPercent"`
+	Files           []FileOptimizationStats `json:"files,omitempty"`
+}
+
+// FileOptimizationStats is the token count of one file before and after optimization
+type FileOptimizationStats struct {
+	FilePath        string  `json:"filePath"`
+	OriginalTokens  int     `json:"originalTokens"`
+	OptimizedTokens int     `json:"optimizedTokens"`
+	SavedPercent    float64 `json:"savedPercent"`
+}
+
+// NewFileOptimizationStats measures the savings of one file
+func NewFileOptimizationStats(filePath string, originalTokens, optimizedTokens int) FileOptimizationStats {
+	return FileOptimizationStats{
+		FilePath:        filePath,
+		OriginalTokens:  originalTokens,
+		OptimizedTokens: optimizedTokens,
+		SavedPercent:    savedPercent(originalTokens, optimizedTokens),
+	}
+}
+
+// Add records a file and updates the totals
+func (s *OptimizationStats) Add(file FileOptimizationStats) {
+	s.Files = append(s.Files, file)
+	s.OriginalTokens += file.OriginalTokens
+	s.OptimizedTokens += file.OptimizedTokens
+	s.SavedPercent = savedPercent(s.OriginalTokens, s.OptimizedTokens)
+}
+
+// savedPercent returns the share of tokens saved, rounded to one decimal
+func savedPercent(original, optimized int) float64 {
+	if original <= 0 {
+		return 0
+	}
+	return math.Round(float64(original-optimized)*1000/float64(original)) / 10
 }
 
 // ContextChunk represents a paginated piece of context content
@@ -479,6 +540,10 @@ type CorrectionResult struct {
 	Success      bool     `json:"success"`
 	Message      string   `json:"message"`
 	FilesChanged []string `json:"filesChanged"`
+	Diff         string   `json:"diff,omitempty"` // unified diff of the proposed changes in dry-run mode
+	// SkippedRules lists the rules left out because their edits overlap those
+	// of a higher-priority rule
+	SkippedRules []string `json:"skippedRules,omitempty"`
 }
 
 // CorrectionGuidance provides AI-generated guidance for error correction
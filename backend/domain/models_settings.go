@@ -18,7 +18,46 @@ type SettingsDTO struct {
 	AvailableModels   map[string][]string `json:"availableModels"` // provider -> available models
 	UseGitignore      bool                `json:"useGitignore"`
 	UseCustomIgnore   bool                `json:"useCustomIgnore"`
+	PreciseGoAnalysis bool                `json:"preciseGoAnalysis"` // type-check Go modules with go/packages (slower)
+	EmbeddingProvider string              `json:"embeddingProvider"` // "openai" (default) or "fake" for offline use
+	EmbeddingModel    string              `json:"embeddingModel"`    // OpenAI embedding model, text-embedding-3-small by default
+	VectorStore       string              `json:"vectorStore"`       // "sqlite" (default) or "memory"
+	SimilarityMetric  string              `json:"similarityMetric"`  // "auto" (model default), "cosine", "dot" or "euclidean"
 	RecentProjects    []RecentProjectInfo `json:"recentProjects,omitempty"`
+
+	// BackgroundIndexing re-indexes changed files as they are saved (default on)
+	BackgroundIndexing bool `json:"backgroundIndexing"`
+	// AIAuditIncludePrompts stores prompt text in the AI audit log, not only its hash
+	AIAuditIncludePrompts bool `json:"aiAuditIncludePrompts"`
+	// SafeMode blocks all file-writing operations (apply, tasks, repair)
+	SafeMode bool `json:"safeMode"`
+	// CommandLimits caps the external tools run by the app
+	CommandLimits CommandLimits `json:"commandLimits"`
+	// EnabledLanguages limits analysis, tests and indexing to these languages; empty means all
+	EnabledLanguages []string `json:"enabledLanguages"`
+	// AIProviderFallback lists providers tried in order when the selected one is unavailable
+	AIProviderFallback []string `json:"aiProviderFallback"`
+	// OptimizationProfile is the default profile of context builds; empty uses the individual options
+	OptimizationProfile string `json:"optimizationProfile"`
+	// CustomOptimization holds the options of the "custom" optimization profile
+	CustomOptimization ContentOptimizeOptions `json:"customOptimization"`
+	// VerificationGates overrides the checks autonomous tasks must pass, by SLA policy
+	VerificationGates map[string]VerificationGate `json:"verificationGates,omitempty"`
+}
+
+// CommandLimits caps the external tools (linters, compilers, test runners) the
+// app runs. Zero fields use the default timeout or set no limit.
+type CommandLimits struct {
+	// TimeoutSeconds is the wall-clock limit per command; 0 uses the default
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+	// MemoryMB caps the resident memory of the command and the processes it
+	// starts together
+	MemoryMB int `json:"memoryMB,omitempty"`
+	// CPUSeconds caps the CPU time of each process the command starts
+	CPUSeconds int `json:"cpuSeconds,omitempty"`
+	// MaxOutputKB caps the captured output of each command; the middle of longer
+	// output is replaced by a truncation marker. 0 uses the default (4 MiB)
+	MaxOutputKB int `json:"maxOutputKB,omitempty"`
 }
 
 // RecentProjectInfo stores information about a recently opened project
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Named optimization profiles selectable per context build
+const (
+	OptimizationProfileNone       = "none"       // content is kept as is
+	OptimizationProfileBalanced   = "balanced"   // safe cleanups that keep all code
+	OptimizationProfileAggressive = "aggressive" // skeletons, no comments, compact data files
+	OptimizationProfileCustom     = "custom"     // the custom options from settings
+)
+
+// optimizationProfiles maps preset profile names to their options
+var optimizationProfiles = map[string]ContentOptimizeOptions{
+	OptimizationProfileNone: {},
+	OptimizationProfileBalanced: {
+		StripLicense:       true,
+		CollapseEmptyLines: true,
+		TrimWhitespace:     true,
+	},
+	OptimizationProfileAggressive: {
+		StripLicense:       true,
+		CollapseEmptyLines: true,
+		TrimWhitespace:     true,
+		StripComments:      true,
+		CompactDataFiles:   true,
+		SkeletonMode:       true,
+	},
+}
+
+// OptimizationProfiles lists the profile names in order from least to most control
+func OptimizationProfiles() []string {
+	return []string{OptimizationProfileNone, OptimizationProfileBalanced, OptimizationProfileAggressive, OptimizationProfileCustom}
+}
+
+// IsOptimizationProfile reports whether name is a known profile; empty means no profile
+func IsOptimizationProfile(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	_, ok := optimizationProfiles[name]
+	return ok || name == "" || name == OptimizationProfileCustom
+}
+
+// ResolveOptimizationProfile returns the options of a profile.
+// The custom profile resolves to custom; unknown names are a validation error.
+func ResolveOptimizationProfile(name string, custom ContentOptimizeOptions) (ContentOptimizeOptions, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == OptimizationProfileCustom {
+		return custom, nil
+	}
+	if err := ValidateOptimizationProfile(name); err != nil {
+		return ContentOptimizeOptions{}, err
+	}
+	return optimizationProfiles[name], nil
+}
+
+// ValidateOptimizationProfile returns a validation error for unknown profile names
+func ValidateOptimizationProfile(name string) error {
+	if IsOptimizationProfile(name) {
+		return nil
+	}
+	return NewValidationError(fmt.Sprintf("unknown optimization profile %q, expected one of %s",
+		strings.ToLower(strings.TrimSpace(name)), strings.Join(OptimizationProfiles(), ", ")), nil)
+}
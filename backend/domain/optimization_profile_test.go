@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveOptimizationProfile(t *testing.T) {
+	custom := ContentOptimizeOptions{StripComments: true}
+
+	if opts, err := ResolveOptimizationProfile("none", custom); err != nil || opts != (ContentOptimizeOptions{}) {
+		t.Errorf("none = %+v, %v", opts, err)
+	}
+	if opts, _ := ResolveOptimizationProfile("Balanced", custom); !opts.StripLicense || !opts.CollapseEmptyLines || opts.SkeletonMode {
+		t.Errorf("balanced = %+v", opts)
+	}
+	if opts, _ := ResolveOptimizationProfile("aggressive", custom); !opts.SkeletonMode || !opts.StripComments || !opts.CompactDataFiles {
+		t.Errorf("aggressive = %+v", opts)
+	}
+	if opts, _ := ResolveOptimizationProfile("custom", custom); opts != custom {
+		t.Errorf("custom = %+v", opts)
+	}
+
+	_, err := ResolveOptimizationProfile("turbo", custom)
+	var domainErr *DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != ErrCodeValidationError {
+		t.Errorf("expected validation error for unknown profile, got %v", err)
+	}
+	if IsOptimizationProfile("turbo") || !IsOptimizationProfile("") || !IsOptimizationProfile("custom") {
+		t.Error("unexpected IsOptimizationProfile results")
+	}
+}
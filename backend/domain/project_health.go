@@ -0,0 +1,84 @@
+package domain
+
+// Категории оценки здоровья проекта
+const (
+	HealthCategoryStatic          = "static"
+	HealthCategoryCoverage        = "coverage"
+	HealthCategoryVulnerabilities = "vulnerabilities"
+	HealthCategoryCycles          = "cycles"
+	HealthCategoryBuild           = "build"
+)
+
+// HealthWeights задает веса категорий в оценке здоровья проекта. Веса
+// относительные: оценка - средневзвешенное измеренных категорий, поэтому
+// сумма весов не обязана быть равна 1, а нулевой вес исключает категорию
+type HealthWeights struct {
+	StaticAnalysis  float64 `json:"staticAnalysis"`  // плотность замечаний статического анализа, по умолчанию 0.25
+	Coverage        float64 `json:"coverage"`        // покрытие тестами, по умолчанию 0.20
+	Vulnerabilities float64 `json:"vulnerabilities"` // уязвимости зависимостей из SBOM, по умолчанию 0.25
+	Cycles          float64 `json:"cycles"`          // циклические зависимости, по умолчанию 0.10
+	Build           float64 `json:"build"`           // статус сборки, по умолчанию 0.20
+}
+
+// DefaultHealthWeights возвращает веса категорий по умолчанию
+func DefaultHealthWeights() HealthWeights {
+	return HealthWeights{
+		StaticAnalysis:  0.25,
+		Coverage:        0.20,
+		Vulnerabilities: 0.25,
+		Cycles:          0.10,
+		Build:           0.20,
+	}
+}
+
+// Weight возвращает вес категории
+func (w HealthWeights) Weight(category string) float64 {
+	switch category {
+	case HealthCategoryStatic:
+		return w.StaticAnalysis
+	case HealthCategoryCoverage:
+		return w.Coverage
+	case HealthCategoryVulnerabilities:
+		return w.Vulnerabilities
+	case HealthCategoryCycles:
+		return w.Cycles
+	case HealthCategoryBuild:
+		return w.Build
+	}
+	return 0
+}
+
+// HealthCategoryScore представляет оценку одной категории здоровья проекта
+type HealthCategoryScore struct {
+	Category string  `json:"category"`
+	Score    float64 `json:"score"` // 0-100
+	Weight   float64 `json:"weight"`
+	Value    float64 `json:"value"`             // измеренное значение: плотность замечаний, процент покрытия, число уязвимостей или циклов, доля собранных языков
+	Skipped  bool    `json:"skipped,omitempty"` // категорию не удалось измерить, она не входит в оценку
+	Message  string  `json:"message,omitempty"`
+}
+
+// ProjectHealth представляет сводную оценку здоровья проекта
+type ProjectHealth struct {
+	ProjectPath string                 `json:"projectPath"`
+	Score       float64                `json:"score"` // 0-100, средневзвешенное измеренных категорий
+	Grade       string                 `json:"grade"` // A-F
+	Categories  []*HealthCategoryScore `json:"categories"`
+	Weights     HealthWeights          `json:"weights"`
+	ComputedAt  string                 `json:"computedAt"`
+}
+
+// HealthGrade переводит оценку 0-100 в буквенную: A от 90, B от 80, C от 70, D от 60, иначе F
+func HealthGrade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	}
+	return "F"
+}
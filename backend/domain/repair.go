@@ -11,12 +11,18 @@ type RepairRule struct {
 	Name        string
 	Description string
 	Pattern     string // regex pattern для поиска ошибок
-	Fix         string // шаблон исправления
+	Fix         string // шаблон исправления; для категории correction - набор правил CorrectionEngine (imports, syntax, types, lint), пусто - все
 	Priority    int    // приоритет правила (выше = важнее)
 	Language    string // язык программирования
 	Category    string // категория ошибки (syntax, lint, build, etc.)
 }
 
+// RepairRuleStore хранит пользовательские правила repair между запусками
+type RepairRuleStore interface {
+	Load() ([]RepairRule, error)
+	Save(rules []RepairRule) error
+}
+
 // RepairResult результат выполнения repair операции
 type RepairResult struct {
 	Success    bool
@@ -25,6 +31,8 @@ type RepairResult struct {
 	Error      string
 	Duration   time.Duration
 	Attempts   int
+	DryRun     bool
+	Diff       string // в режиме DryRun - предлагаемые исправления, файлы не изменяются
 }
 
 // RepairRequest запрос на выполнение repair
@@ -34,6 +42,7 @@ type RepairRequest struct {
 	Language    string
 	MaxAttempts int
 	Rules       []RepairRule
+	DryRun      bool // только показать исправления в виде diff, не изменяя файлы
 }
 
 // RepairService интерфейс для сервиса repair
@@ -52,4 +61,51 @@ type RepairService interface {
 
 	// ValidateRule проверяет корректность правила
 	ValidateRule(rule RepairRule) error
+
+	// TestRepairRule применяет правило к образцам файлов в памяти, не изменяя проект
+	TestRepairRule(rule RepairRule, sampleError string, sampleFiles map[string]string) (*RepairResult, error)
+
+	// GetRepairHistory возвращает отчеты repair для проекта, новые первыми
+	GetRepairHistory(ctx context.Context, projectPath string) ([]RepairReport, error)
+}
+
+// RepairReportType тип GenericReport, в котором хранятся отчеты repair
+const RepairReportType = "repair"
+
+// Статусы сборки в отчете repair
+const (
+	RepairBuildFailed  = "failed"
+	RepairBuildPassed  = "passed"
+	RepairBuildSkipped = "skipped" // ни одно правило не применилось, сборка не запускалась
+)
+
+// RepairCorrection правило, примененное в попытке repair
+type RepairCorrection struct {
+	RuleID   string   `json:"ruleId"`
+	RuleName string   `json:"ruleName"`
+	Category string   `json:"category"`
+	Files    []string `json:"files"`
+}
+
+// RepairAttemptReport запись об одной попытке repair
+type RepairAttemptReport struct {
+	Attempt     int                `json:"attempt"`
+	ErrorType   ErrorType          `json:"errorType"`
+	Corrections []RepairCorrection `json:"corrections"`
+	BuildBefore string             `json:"buildBefore"`
+	BuildAfter  string             `json:"buildAfter"`
+	ErrorsAfter string             `json:"errorsAfter,omitempty"`
+}
+
+// RepairReport отчет о запуске repair цикла
+type RepairReport struct {
+	ID            string                `json:"id"`
+	ProjectPath   string                `json:"projectPath"`
+	Language      string                `json:"language"`
+	StartedAt     time.Time             `json:"startedAt"`
+	DurationMs    int64                 `json:"durationMs"`
+	Success       bool                  `json:"success"`
+	Error         string                `json:"error,omitempty"`
+	InitialErrors string                `json:"initialErrors"`
+	Attempts      []RepairAttemptReport `json:"attempts"`
 }
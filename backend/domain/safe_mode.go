@@ -0,0 +1,39 @@
+package domain
+
+import "sync/atomic"
+
+// SafeMode is the read-only switch shared by services that write files:
+// while it is enabled they refuse to run. A nil *SafeMode is never enabled.
+type SafeMode struct {
+	enabled atomic.Bool
+}
+
+// NewSafeMode creates a switch in the given state
+func NewSafeMode(enabled bool) *SafeMode {
+	m := &SafeMode{}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled reports whether file-writing operations are blocked
+func (m *SafeMode) Enabled() bool {
+	return m != nil && m.enabled.Load()
+}
+
+// SetEnabled turns safe mode on or off
+func (m *SafeMode) SetEnabled(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// Check returns a safe mode error for operation while safe mode is enabled
+func (m *SafeMode) Check(operation string) error {
+	if m.Enabled() {
+		return NewSafeModeError(operation)
+	}
+	return nil
+}
+
+// SafeModeSetter is implemented by services that block writes in safe mode
+type SafeModeSetter interface {
+	SetSafeMode(mode *SafeMode)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+// Levels of task log entries
+const (
+	TaskLogInfo  = "INFO"
+	TaskLogWarn  = "WARN"
+	TaskLogError = "ERROR"
+)
+
+// TaskLogFunc records one line of a task's execution log; stepID is empty for
+// lines that don't belong to a pipeline step
+type TaskLogFunc func(level, stepID, message string)
+
+type taskLogKey struct{}
+
+// WithTaskLog makes code run with ctx write its task log lines to fn
+func WithTaskLog(ctx context.Context, fn TaskLogFunc) context.Context {
+	return context.WithValue(ctx, taskLogKey{}, fn)
+}
+
+// LogTaskStep writes a line to the task log attached to ctx, if any
+func LogTaskStep(ctx context.Context, level, stepID, message string) {
+	if fn, ok := ctx.Value(taskLogKey{}).(TaskLogFunc); ok && fn != nil {
+		fn(level, stepID, message)
+	}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"path/filepath"
 	"time"
 )
 
@@ -47,21 +48,103 @@ type TaskStatus struct {
 	Progress    float64 // 0.0 - 1.0
 	Message     string
 	Error       string
+	Attempts    int    // число попыток выполнения, включая повторы
+	CommitHash  string // коммит с изменениями задачи, если они были закоммичены
 	StartedAt   *time.Time
 	CompletedAt *time.Time
 	UpdatedAt   time.Time
 	Duration    time.Duration
 }
 
+// Пути plan.yaml и status.json относительно корня проекта
+const (
+	DefaultTaskflowPlanPath   = "tasks/plan.yaml"
+	DefaultTaskflowStatusPath = "tasks/status.json"
+)
+
+// События taskflow для UI: progress при каждом изменении статуса задачи,
+// completed и failed дополнительно при завершении задачи
+const (
+	EventTaskflowProgress  = "taskflow:progress"
+	EventTaskflowCompleted = "taskflow:completed"
+	EventTaskflowFailed    = "taskflow:failed"
+)
+
+// TaskflowProgressEvent данные событий taskflow
+type TaskflowProgressEvent struct {
+	TaskID   string    `json:"taskId"`
+	State    TaskState `json:"state"`
+	Progress float64   `json:"progress"` // 0.0 - 1.0
+	Message  string    `json:"message"`
+	Error    string    `json:"error,omitempty"`
+	Attempts int       `json:"attempts,omitempty"`
+
+	CommitHash string `json:"commitHash,omitempty"`
+}
+
 // TaskflowConfig конфигурация taskflow
 type TaskflowConfig struct {
 	AutoStart     bool
-	MaxConcurrent int
+	MaxConcurrent int  // сколько готовых задач ExecuteTaskflow выполняет одновременно; 0 - по одной
+	FailFast      bool // первая упавшая задача отменяет остальные; иначе независимые ветки продолжаются
 	RetryAttempts int
 	RetryDelay    time.Duration
 	Timeout       time.Duration
 	EnableLogging bool
 	EnableMetrics bool
+	PlanPath      string // путь к plan.yaml
+	StatusPath    string // путь к status.json
+	MaxLogEntries int    // сколько последних записей лога хранится на задачу; 0 - DefaultMaxTaskLogEntries
+	LogDir        string // если задан, лог задачи дописывается в <LogDir>/<taskID>.log (JSON Lines)
+}
+
+// DefaultMaxTaskLogEntries размер кольцевого буфера лога одной задачи по умолчанию
+const DefaultMaxTaskLogEntries = 1000
+
+// NewTaskflowConfig возвращает конфигурацию по умолчанию с путями plan.yaml и status.json
+// внутри projectRoot; пустой projectRoot означает текущий каталог
+func NewTaskflowConfig(projectRoot string) TaskflowConfig {
+	return TaskflowConfig{
+		AutoStart:     true,
+		MaxConcurrent: 3,
+		RetryAttempts: 3,
+		RetryDelay:    5 * time.Second,
+		Timeout:       30 * time.Minute,
+		EnableLogging: true,
+		EnableMetrics: true,
+		MaxLogEntries: DefaultMaxTaskLogEntries,
+		PlanPath:      filepath.Join(projectRoot, DefaultTaskflowPlanPath),
+		StatusPath:    filepath.Join(projectRoot, DefaultTaskflowStatusPath),
+	}
+}
+
+// Коды проблем plan.yaml
+const (
+	PlanProblemInvalidYAML       = "invalid_yaml"
+	PlanProblemMissingID         = "missing_id"
+	PlanProblemDuplicateID       = "duplicate_id"
+	PlanProblemUnknownDependency = "unknown_dependency"
+	PlanProblemDependencyCycle   = "dependency_cycle"
+	PlanProblemMissingStepFile   = "missing_step_file"
+	PlanProblemNegativeBudget    = "negative_budget"
+)
+
+// PlanProblem описывает ошибку в plan.yaml
+type PlanProblem struct {
+	Code    string `json:"code"`
+	TaskID  string `json:"taskId,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`    // номер строки в plan.yaml, если известен
+	Context string `json:"context,omitempty"` // текст этой строки
+}
+
+// PlanValidationResult результат проверки plan.yaml до выполнения
+type PlanValidationResult struct {
+	PlanPath  string        `json:"planPath"`
+	Valid     bool          `json:"valid"`
+	TaskCount int           `json:"taskCount"`
+	Problems  []PlanProblem `json:"problems"`
 }
 
 // TaskflowRepository интерфейс для работы с taskflow statuses
@@ -71,6 +154,9 @@ type TaskflowRepository interface {
 
 	// SaveStatuses сохраняет статусы задач в хранилище
 	SaveStatuses(statuses map[string]TaskState) error
+
+	// SetStatusPath задает путь к status.json
+	SetStatusPath(statusPath string)
 }
 
 // TaskflowService интерфейс для сервиса taskflow
@@ -84,8 +170,8 @@ type TaskflowService interface {
 	// UpdateTaskStatus обновляет статус задачи
 	UpdateTaskStatus(taskID string, state TaskState, message string) error
 
-	// ExecuteTask выполняет задачу
-	ExecuteTask(taskID string) error
+	// ExecuteTask выполняет задачу, повторяя неудачные попытки согласно RetryAttempts/RetryDelay
+	ExecuteTask(ctx context.Context, taskID string) error
 
 	// ExecuteTaskflow выполняет весь taskflow
 	ExecuteTaskflow() error
@@ -99,6 +185,12 @@ type TaskflowService interface {
 	// ValidateTaskflow проверяет корректность taskflow
 	ValidateTaskflow() error
 
+	// SetProjectRoot переносит plan.yaml и status.json в каталог tasks выбранного проекта и перезагружает задачи
+	SetProjectRoot(projectRoot string) error
+
+	// ValidatePlan проверяет plan.yaml без загрузки задач; пустой путь означает план по умолчанию
+	ValidatePlan(planPath string) (*PlanValidationResult, error)
+
 	// GetTaskflowProgress возвращает прогресс выполнения
 	GetTaskflowProgress() (float64, error)
 
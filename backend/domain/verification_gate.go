@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SLAPolicies возвращает SLA политики автономных задач
+func SLAPolicies() []string {
+	return []string{"lite", "standard", "strict"}
+}
+
+// VerificationGate задает проверки, которые автономная задача должна пройти,
+// прежде чем считаться выполненной
+type VerificationGate struct {
+	Categories  []string `json:"categories"`            // категории сводной проверки проекта
+	MinCoverage float64  `json:"minCoverage,omitempty"` // минимальное покрытие тестами в процентах; 0 - не проверяется
+}
+
+// DefaultVerificationGate возвращает проверки SLA политики по умолчанию:
+// lite - только сборка, standard - сборка, тесты и статический анализ,
+// strict - все категории и покрытие тестами
+func DefaultVerificationGate(slaPolicy string) VerificationGate {
+	switch slaPolicy {
+	case "lite":
+		return VerificationGate{Categories: []string{ValidationCategoryBuild}}
+	case "strict":
+		return VerificationGate{Categories: AllValidationCategories(), MinCoverage: 80}
+	default:
+		return VerificationGate{Categories: []string{ValidationCategoryBuild, ValidationCategoryTests, ValidationCategoryStatic}}
+	}
+}
+
+// ValidateVerificationGates проверяет SLA политики, категории и порог покрытия переопределений
+func ValidateVerificationGates(gates map[string]VerificationGate) error {
+	for policy, gate := range gates {
+		if !slices.Contains(SLAPolicies(), policy) {
+			return NewValidationError(fmt.Sprintf("unknown SLA policy %q in verification gates", policy), nil)
+		}
+		for _, category := range gate.Categories {
+			if !slices.Contains(AllValidationCategories(), category) {
+				return NewValidationError(fmt.Sprintf("unknown validation category %q in the %s verification gate", category, policy), nil)
+			}
+		}
+		if gate.MinCoverage < 0 || gate.MinCoverage > 100 {
+			return NewValidationError(fmt.Sprintf("minimum coverage of the %s verification gate must be between 0 and 100", policy), nil)
+		}
+	}
+	return nil
+}
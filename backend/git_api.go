@@ -175,6 +175,9 @@ func (a *App) GetGitignoreContentForProject(projectPath string) (string, error)
 
 // AddToGitignore adds a pattern to .gitignore file
 func (a *App) AddToGitignore(projectPath string, pattern string) error {
+	if err := a.container.SafeMode.Check("editing .gitignore"); err != nil {
+		return err
+	}
 	gitignorePath := filepath.Join(projectPath, ".gitignore")
 
 	content, err := os.ReadFile(gitignorePath)
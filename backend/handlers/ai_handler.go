@@ -7,6 +7,7 @@ import (
 	"shotgun_code/application"
 	"shotgun_code/application/ai"
 	"shotgun_code/domain"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,6 +19,11 @@ type AIHandler struct {
 	aiService       *ai.Service
 	contextAnalysis domain.ContextAnalyzer
 	toolExecutor    *application.ToolExecutorImpl // Injected, shared across requests
+	eventBus        domain.EventBus               // Optional, receives AI request events
+
+	// In-flight requests by ID
+	requestsMu sync.Mutex
+	requests   map[string]*activeAIRequest
 
 	// Rate limiting
 	requestCount int64
@@ -34,6 +40,30 @@ const (
 	maxRequestsPerMin = 60
 )
 
+// AI request states reported in AIRequestInfo
+const (
+	AIRequestRunning   = "running"
+	AIRequestFinished  = "finished"
+	AIRequestCancelled = "cancelled"
+)
+
+// EventAIRequest is emitted with AIRequestInfo when an AI request starts and ends
+const EventAIRequest = "ai:request"
+
+// AIRequestInfo describes an AI request tracked by the handler
+type AIRequestInfo struct {
+	RequestID string    `json:"requestId"`
+	Kind      string    `json:"kind"`
+	State     string    `json:"state"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+type activeAIRequest struct {
+	info      AIRequestInfo
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
 // NewAIHandler creates a new AI handler.
 // toolExecutor is optional - if nil, AgenticChat will create a basic one.
 func NewAIHandler(
@@ -57,6 +87,7 @@ func NewAIHandlerWithTools(
 		aiService:       aiService,
 		contextAnalysis: contextAnalysis,
 		toolExecutor:    toolExecutor,
+		requests:        make(map[string]*activeAIRequest),
 		lastReset:       time.Now(),
 		stopCh:          make(chan struct{}),
 	}
@@ -69,6 +100,11 @@ func (h *AIHandler) SetToolExecutor(te *application.ToolExecutorImpl) {
 	h.toolExecutor = te
 }
 
+// SetEventBus sets the bus that receives EventAIRequest events
+func (h *AIHandler) SetEventBus(bus domain.EventBus) {
+	h.eventBus = bus
+}
+
 // Shutdown gracefully stops the AI handler
 func (h *AIHandler) Shutdown(ctx context.Context) error {
 	h.stopOnce.Do(func() {
@@ -100,6 +136,8 @@ func (h *AIHandler) GenerateCode(ctx context.Context, systemPrompt, userPrompt s
 	}
 
 	atomic.AddInt64(&h.requestCount, 1)
+	ctx, _, finish := h.BeginRequest(ctx, "generate")
+	defer finish()
 	return h.aiService.GenerateCode(ctx, systemPrompt, userPrompt)
 }
 
@@ -115,6 +153,8 @@ func (h *AIHandler) GenerateIntelligentCode(ctx context.Context, task, contextSt
 	}
 
 	atomic.AddInt64(&h.requestCount, 1)
+	ctx, _, finish := h.BeginRequest(ctx, "intelligent")
+	defer finish()
 
 	result, err := h.aiService.GenerateIntelligentCode(ctx, task, contextStr, options)
 	if err != nil {
@@ -141,6 +181,8 @@ func (h *AIHandler) GenerateCodeWithOptions(ctx context.Context, systemPrompt, u
 	}
 
 	atomic.AddInt64(&h.requestCount, 1)
+	ctx, _, finish := h.BeginRequest(ctx, "generate")
+	defer finish()
 	return h.aiService.GenerateCodeWithOptions(ctx, systemPrompt, userPrompt, options)
 }
 
@@ -164,12 +206,46 @@ func (h *AIHandler) ListAvailableModels(ctx context.Context) ([]string, error) {
 	return h.aiService.ListAvailableModels(ctx)
 }
 
+// QueryAIAuditLog returns AI audit entries matching a JSON domain.AIAuditQuery
+// (an empty string matches all), newest first
+func (h *AIHandler) QueryAIAuditLog(queryJSON string) (string, error) {
+	var query domain.AIAuditQuery
+	if queryJSON != "" {
+		if err := json.Unmarshal([]byte(queryJSON), &query); err != nil {
+			return "", fmt.Errorf("failed to parse audit query JSON: %w", err)
+		}
+	}
+
+	entries, err := h.aiService.QueryAuditLog(query)
+	if err != nil {
+		return "", err
+	}
+
+	entriesJSON, err := json.Marshal(entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal audit entries: %w", err)
+	}
+	return string(entriesJSON), nil
+}
+
+// GetCacheStats returns AI response cache statistics
+func (h *AIHandler) GetCacheStats() ai.CacheStats {
+	return h.aiService.GetCacheStats()
+}
+
+// GetRateLimits returns the adaptive rate limit state per provider
+func (h *AIHandler) GetRateLimits() map[string]ai.RateLimitState {
+	return h.aiService.GetRateLimits()
+}
+
 // SuggestContextFiles suggests relevant files for a task
 func (h *AIHandler) SuggestContextFiles(ctx context.Context, task string, allFiles []*domain.FileNode) ([]string, error) {
 	if h.contextAnalysis == nil {
 		return nil, fmt.Errorf("context analysis service not available")
 	}
 
+	ctx, _, finish := h.BeginRequest(ctx, "planning")
+	defer finish()
 	return h.contextAnalysis.SuggestFiles(ctx, task, allFiles)
 }
 
@@ -195,6 +271,8 @@ func (h *AIHandler) AnalyzeTaskAndCollectContext(ctx context.Context, task, allF
 		return "", fmt.Errorf("context analysis service does not support AnalyzeTaskAndCollectContext")
 	}
 
+	ctx, _, finish := h.BeginRequest(ctx, "planning")
+	defer finish()
 	result, err := analyzer.AnalyzeTaskAndCollectContext(ctx, task, allFiles, rootDir)
 	if err != nil {
 		return "", err
@@ -234,15 +312,22 @@ func (h *AIHandler) GetMetrics() map[string]interface{} {
 	}
 }
 
-// GenerateCodeStream generates code with streaming response
-func (h *AIHandler) GenerateCodeStream(ctx context.Context, systemPrompt, userPrompt string, onChunk func(chunk domain.StreamChunk)) error {
+// GenerateCodeStream generates code in the background, streaming the response
+// to onChunk, and returns the request ID to cancel it with
+func (h *AIHandler) GenerateCodeStream(ctx context.Context, systemPrompt, userPrompt string, onChunk func(chunk domain.StreamChunk)) (string, error) {
 	if err := h.checkRateLimit(); err != nil {
-		onChunk(domain.StreamChunk{Done: true, Error: err.Error()})
-		return err
+		return "", err
 	}
 
 	atomic.AddInt64(&h.requestCount, 1)
-	return h.aiService.GenerateCodeStream(ctx, systemPrompt, userPrompt, onChunk)
+	ctx, requestID, finish := h.BeginRequest(ctx, "stream")
+	go func() {
+		defer finish()
+		if err := h.aiService.GenerateCodeStream(ctx, systemPrompt, userPrompt, onChunk); err != nil {
+			h.log.Warning(fmt.Sprintf("AI stream %s failed: %v", requestID, err))
+		}
+	}()
+	return requestID, nil
 }
 
 // AgenticChat performs agentic chat with tool use
@@ -261,6 +346,8 @@ func (h *AIHandler) AgenticChat(ctx context.Context, requestJSON string) (string
 	agenticService := ai.NewAgenticChatService(h.log, h.aiService, toolExecutor)
 
 	atomic.AddInt64(&h.requestCount, 1)
+	ctx, _, finish := h.BeginRequest(ctx, "agentic")
+	defer finish()
 
 	result, err := agenticService.Chat(ctx, req)
 	if err != nil {
@@ -293,10 +380,84 @@ func (h *AIHandler) AgenticChatStream(ctx context.Context, requestJSON string, o
 	agenticService := ai.NewAgenticChatService(h.log, h.aiService, toolExecutor)
 
 	atomic.AddInt64(&h.requestCount, 1)
+	ctx, _, finish := h.BeginRequest(ctx, "agentic")
+	defer finish()
 
 	return agenticService.ChatStream(ctx, req, onEvent)
 }
 
+// CancelRequest cancels the context of an in-flight AI request
+func (h *AIHandler) CancelRequest(requestID string) error {
+	h.requestsMu.Lock()
+	defer h.requestsMu.Unlock()
+
+	req, ok := h.requests[requestID]
+	if !ok {
+		return domain.NewNotFoundError("AI request", requestID)
+	}
+	req.cancelled = true
+	req.cancel()
+	h.log.Info(fmt.Sprintf("AI request %s cancelled by user", requestID))
+	return nil
+}
+
+// ActiveRequests returns in-flight AI requests, oldest first
+func (h *AIHandler) ActiveRequests() []AIRequestInfo {
+	h.requestsMu.Lock()
+	defer h.requestsMu.Unlock()
+
+	infos := make([]AIRequestInfo, 0, len(h.requests))
+	for _, req := range h.requests {
+		infos = append(infos, req.info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].StartedAt.Before(infos[j].StartedAt)
+	})
+	return infos
+}
+
+// BeginRequest registers a cancellable AI request and returns its context and
+// ID; finish must be called when it ends. Other handlers use it to make their
+// AI work cancellable through CancelRequest.
+func (h *AIHandler) BeginRequest(ctx context.Context, kind string) (_ context.Context, requestID string, finish func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	req := &activeAIRequest{
+		info: AIRequestInfo{
+			RequestID: h.aiService.NewRequestID(),
+			Kind:      kind,
+			State:     AIRequestRunning,
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+	}
+
+	h.requestsMu.Lock()
+	h.requests[req.info.RequestID] = req
+	h.requestsMu.Unlock()
+	h.emitRequest(req.info)
+
+	return ai.WithRequestID(ctx, req.info.RequestID), req.info.RequestID, func() {
+		h.requestsMu.Lock()
+		delete(h.requests, req.info.RequestID)
+		info := req.info
+		info.State = AIRequestFinished
+		if req.cancelled {
+			info.State = AIRequestCancelled
+		}
+		h.requestsMu.Unlock()
+
+		cancel()
+		h.emitRequest(info)
+	}
+}
+
+// emitRequest publishes an AI request state change
+func (h *AIHandler) emitRequest(info AIRequestInfo) {
+	if h.eventBus != nil {
+		h.eventBus.Emit(EventAIRequest, info)
+	}
+}
+
 // getToolExecutor returns the injected tool executor or creates a basic fallback.
 func (h *AIHandler) getToolExecutor(_ string) *application.ToolExecutorImpl {
 	if h.toolExecutor != nil {
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"shotgun_code/application/ai"
+	"shotgun_code/domain"
+)
+
+type aiTestSettings struct{}
+
+func (aiTestSettings) GetSettingsDTO() (domain.SettingsDTO, error) {
+	// localai needs no API key
+	return domain.SettingsDTO{SelectedProvider: "localai", SelectedModels: map[string]string{"localai": "test-model"}}, nil
+}
+
+// blockingProvider streams until the request is cancelled
+type blockingProvider struct {
+	started chan struct{}
+}
+
+func (p *blockingProvider) Generate(ctx context.Context, _ domain.AIRequest) (domain.AIResponse, error) {
+	close(p.started)
+	<-ctx.Done()
+	return domain.AIResponse{}, ctx.Err()
+}
+
+func (p *blockingProvider) GenerateStream(ctx context.Context, _ domain.AIRequest, _ func(domain.StreamChunk)) error {
+	close(p.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (p *blockingProvider) ListModels(context.Context) ([]string, error) { return nil, nil }
+func (p *blockingProvider) GetProviderInfo() domain.ProviderInfo {
+	return domain.ProviderInfo{Name: "blocking"}
+}
+func (p *blockingProvider) ValidateRequest(domain.AIRequest) error { return nil }
+func (p *blockingProvider) EstimateTokens(domain.AIRequest) (int, error) {
+	return 0, nil
+}
+func (p *blockingProvider) GetPricing(model string) domain.PricingInfo {
+	return domain.PricingInfo{Model: model}
+}
+
+type recordingBus struct {
+	mu     sync.Mutex
+	events []AIRequestInfo
+}
+
+func (b *recordingBus) Emit(_ string, data ...interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, data[0].(AIRequestInfo))
+}
+
+func (b *recordingBus) states() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	states := make([]string, len(b.events))
+	for i, event := range b.events {
+		states[i] = event.State
+	}
+	return states
+}
+
+func newCancellableAIHandler(t *testing.T) (*AIHandler, *blockingProvider, *recordingBus) {
+	t.Helper()
+	provider := &blockingProvider{started: make(chan struct{})}
+	registry := map[string]domain.AIProviderFactory{
+		"localai": func(string, string) (domain.AIProvider, error) { return provider, nil },
+	}
+	service := ai.NewService(aiTestSettings{}, &domain.NoopLogger{}, registry, nil)
+	handler := NewAIHandler(&domain.NoopLogger{}, service, nil)
+	bus := &recordingBus{}
+	handler.SetEventBus(bus)
+	t.Cleanup(func() {
+		_ = handler.Shutdown(context.Background())
+		_ = service.Shutdown(context.Background())
+	})
+	return handler, provider, bus
+}
+
+func TestAIHandler_CancelRequestStopsGeneration(t *testing.T) {
+	handler, provider, bus := newCancellableAIHandler(t)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := handler.GenerateCode(context.Background(), "system", "task")
+		done <- err
+	}()
+	<-provider.started
+
+	active := handler.ActiveRequests()
+	if len(active) != 1 || active[0].Kind != "generate" {
+		t.Fatalf("expected one running generate request, got %+v", active)
+	}
+	if err := handler.CancelRequest(active[0].RequestID); err != nil {
+		t.Fatalf("CancelRequest failed: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the generation to stop with context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("generation did not stop after cancellation")
+	}
+	if got := handler.ActiveRequests(); len(got) != 0 {
+		t.Errorf("expected no active requests, got %+v", got)
+	}
+	if states := bus.states(); len(states) != 2 || states[0] != AIRequestRunning || states[1] != AIRequestCancelled {
+		t.Errorf("expected running and cancelled events, got %v", states)
+	}
+}
+
+func TestAIHandler_GenerateCodeStreamReturnsCancellableRequestID(t *testing.T) {
+	handler, provider, bus := newCancellableAIHandler(t)
+
+	requestID, err := handler.GenerateCodeStream(context.Background(), "system", "task", func(domain.StreamChunk) {})
+	if err != nil {
+		t.Fatalf("GenerateCodeStream failed: %v", err)
+	}
+	<-provider.started
+	if err := handler.CancelRequest(requestID); err != nil {
+		t.Fatalf("CancelRequest(%s) failed: %v", requestID, err)
+	}
+
+	// The request ends in the background
+	deadline := time.Now().Add(5 * time.Second)
+	for states := bus.states(); states[len(states)-1] != AIRequestCancelled; states = bus.states() {
+		if time.Now().After(deadline) {
+			t.Fatalf("stream did not stop after cancellation, events %v", states)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := handler.ActiveRequests(); len(got) != 0 {
+		t.Errorf("expected no active requests, got %+v", got)
+	}
+}
+
+func TestAIHandler_CancelUnknownRequest(t *testing.T) {
+	handler, _, _ := newCancellableAIHandler(t)
+
+	if err := handler.CancelRequest("ai_missing"); err == nil {
+		t.Error("expected an error for an unknown request")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"shotgun_code/application/sbom"
 	"shotgun_code/application/symbol"
+	"shotgun_code/application/verification"
 	"shotgun_code/domain"
 )
 
@@ -13,8 +14,9 @@ type AnalysisHandler struct {
 	testService           domain.ITestService
 	staticAnalyzerService domain.IStaticAnalyzerService
 	buildService          domain.IBuildService
+	projectValidator      *verification.ProjectValidator
 	sbomService           *sbom.Service
-	symbolGraph           *symbol.Service
+	symbolGraph           func(context.Context) (*symbol.Service, error) // lazy getter
 
 	// Semaphore for limiting concurrent analysis operations
 	sem chan struct{}
@@ -29,13 +31,14 @@ func NewAnalysisHandler(
 	staticAnalyzerService domain.IStaticAnalyzerService,
 	buildService domain.IBuildService,
 	sbomService *sbom.Service,
-	symbolGraph *symbol.Service,
+	symbolGraph func(context.Context) (*symbol.Service, error),
 ) *AnalysisHandler {
 	return &AnalysisHandler{
 		log:                   log,
 		testService:           testService,
 		staticAnalyzerService: staticAnalyzerService,
 		buildService:          buildService,
+		projectValidator:      verification.NewProjectValidator(log, buildService, testService, staticAnalyzerService),
 		sbomService:           sbomService,
 		symbolGraph:           symbolGraph,
 		sem:                   make(chan struct{}, maxConcurrentAnalysis),
@@ -60,33 +63,33 @@ func (h *AnalysisHandler) releaseSem() {
 // === Build Operations ===
 
 // Build executes project build
-func (h *AnalysisHandler) Build(ctx context.Context, projectPath, language string) (*domain.BuildResult, error) {
+func (h *AnalysisHandler) Build(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, error) {
 	if err := h.acquireSem(ctx); err != nil {
 		return nil, err
 	}
 	defer h.releaseSem()
 
-	return h.buildService.Build(ctx, projectPath, language)
+	return h.buildService.Build(ctx, projectPath, language, target)
 }
 
 // TypeCheck performs type checking
-func (h *AnalysisHandler) TypeCheck(ctx context.Context, projectPath, language string) (*domain.TypeCheckResult, error) {
+func (h *AnalysisHandler) TypeCheck(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.TypeCheckResult, error) {
 	if err := h.acquireSem(ctx); err != nil {
 		return nil, err
 	}
 	defer h.releaseSem()
 
-	return h.buildService.TypeCheck(ctx, projectPath, language)
+	return h.buildService.TypeCheck(ctx, projectPath, language, target)
 }
 
 // BuildAndTypeCheck executes build and type checking
-func (h *AnalysisHandler) BuildAndTypeCheck(ctx context.Context, projectPath, language string) (*domain.BuildResult, *domain.TypeCheckResult, error) {
+func (h *AnalysisHandler) BuildAndTypeCheck(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, *domain.TypeCheckResult, error) {
 	if err := h.acquireSem(ctx); err != nil {
 		return nil, nil, err
 	}
 	defer h.releaseSem()
 
-	return h.buildService.BuildAndTypeCheck(ctx, projectPath, language)
+	return h.buildService.BuildAndTypeCheck(ctx, projectPath, language, target)
 }
 
 // ValidateProject performs full project validation
@@ -99,6 +102,17 @@ func (h *AnalysisHandler) ValidateProject(ctx context.Context, projectPath strin
 	return h.buildService.ValidateProject(ctx, projectPath, languages)
 }
 
+// ValidateProjectSummary runs build, type check, tests and static analysis and
+// aggregates them into an overall status with per-category results
+func (h *AnalysisHandler) ValidateProjectSummary(ctx context.Context, config *domain.ValidationSummaryConfig) (*domain.ValidationSummary, error) {
+	if err := h.acquireSem(ctx); err != nil {
+		return nil, err
+	}
+	defer h.releaseSem()
+
+	return h.projectValidator.Validate(ctx, config)
+}
+
 // DetectLanguages detects languages in project
 func (h *AnalysisHandler) DetectLanguages(ctx context.Context, projectPath string) ([]string, error) {
 	return h.buildService.DetectLanguages(ctx, projectPath)
@@ -264,20 +278,45 @@ func (h *AnalysisHandler) ValidateSBOM(ctx context.Context, sbomPath string, for
 
 // BuildSymbolGraph builds symbol graph for project
 func (h *AnalysisHandler) BuildSymbolGraph(ctx context.Context, projectRoot, language string) (*domain.SymbolGraph, error) {
-	return h.symbolGraph.BuildSymbolGraph(ctx, projectRoot, language)
+	symbolGraph, err := h.symbolGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return symbolGraph.BuildSymbolGraph(ctx, projectRoot, language)
 }
 
 // GetSymbolSuggestions returns symbol suggestions
 func (h *AnalysisHandler) GetSymbolSuggestions(ctx context.Context, query, language string, graph *domain.SymbolGraph) ([]*domain.SymbolNode, error) {
-	return h.symbolGraph.GetSuggestions(ctx, query, language, graph)
+	symbolGraph, err := h.symbolGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return symbolGraph.GetSuggestions(ctx, query, language, graph)
 }
 
 // GetSymbolDependencies returns symbol dependencies
 func (h *AnalysisHandler) GetSymbolDependencies(ctx context.Context, symbolID, language string, graph *domain.SymbolGraph) ([]*domain.SymbolNode, error) {
-	return h.symbolGraph.GetDependencies(ctx, symbolID, language, graph)
+	symbolGraph, err := h.symbolGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return symbolGraph.GetDependencies(ctx, symbolID, language, graph)
+}
+
+// FindSymbolDefinition resolves a reference to symbolName in fromFile to its definition
+func (h *AnalysisHandler) FindSymbolDefinition(ctx context.Context, projectRoot, language, symbolName, fromFile string) (*domain.SymbolNode, error) {
+	symbolGraph, err := h.symbolGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return symbolGraph.FindDefinition(ctx, projectRoot, language, symbolName, fromFile)
 }
 
 // GetSymbolDependents returns symbols depending on the specified one
 func (h *AnalysisHandler) GetSymbolDependents(ctx context.Context, symbolID, language string, graph *domain.SymbolGraph) ([]*domain.SymbolNode, error) {
-	return h.symbolGraph.GetDependents(ctx, symbolID, language, graph)
+	symbolGraph, err := h.symbolGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return symbolGraph.GetDependents(ctx, symbolID, language, graph)
 }
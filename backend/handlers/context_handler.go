@@ -197,6 +197,48 @@ func (h *ContextHandler) CloseStreamingContext(ctx context.Context, contextID st
 	return h.DeleteContext(ctx, contextID)
 }
 
+// GetSkeletonSupport reports for each file whether skeleton mode
+// (the skeletonMode build option) can replace it with its signatures
+func (h *ContextHandler) GetSkeletonSupport(filePaths []string) map[string]bool {
+	support := make(map[string]bool, len(filePaths))
+	for _, filePath := range filePaths {
+		support[filePath] = h.contextService != nil && h.contextService.CanGenerateSkeleton(filePath)
+	}
+	return support
+}
+
+// EstimateContextTokens estimates the tokens of the context a build with
+// optionsJSON would produce, without building it
+func (h *ContextHandler) EstimateContextTokens(projectPath string, includedPaths []string, optionsJSON string) (int, error) {
+	if h.contextService == nil {
+		return 0, fmt.Errorf("context service not available")
+	}
+
+	var options domain.ContextBuildOptions
+	if optionsJSON != "" {
+		if err := json.Unmarshal([]byte(optionsJSON), &options); err != nil {
+			return 0, fmt.Errorf("failed to parse options JSON: %w", err)
+		}
+	}
+	return h.contextService.EstimateContextTokens(projectPath, includedPaths, &options)
+}
+
+// EstimateOptimization reports the tokens the optimizations in optionsJSON
+// would save on the given files, per file and in total
+func (h *ContextHandler) EstimateOptimization(ctx context.Context, projectPath string, filePaths []string, optionsJSON string) (*domain.OptimizationStats, error) {
+	if h.contextService == nil {
+		return nil, fmt.Errorf("context service not available")
+	}
+
+	var options domain.ContextBuildOptions
+	if optionsJSON != "" {
+		if err := json.Unmarshal([]byte(optionsJSON), &options); err != nil {
+			return nil, fmt.Errorf("failed to parse options JSON: %w", err)
+		}
+	}
+	return h.contextService.EstimateOptimization(ctx, projectPath, filePaths, &options)
+}
+
 // GetMetrics returns handler metrics
 func (h *ContextHandler) GetMetrics() map[string]interface{} {
 	return map[string]interface{}{
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"shotgun_code/application/project"
+	"shotgun_code/infrastructure/analyzers"
+	"sort"
+	"strings"
+)
+
+// FileQuickInfo contains quick statistics about a file
+type FileQuickInfo struct {
+	SymbolCount         int     `json:"symbolCount"`
+	ImportCount         int     `json:"importCount"`
+	DependentCount      int     `json:"dependentCount"`
+	AvgComplexity       float64 `json:"avgComplexity"`       // average cyclomatic complexity per function
+	MaxComplexity       int     `json:"maxComplexity"`       // highest cyclomatic complexity of a function
+	ComplexityEstimated bool    `json:"complexityEstimated"` // line-based estimate for non-Go files
+	ChangeRisk          float64 `json:"changeRisk"`
+	RiskLevel           string  `json:"riskLevel"` // "low", "medium", "high"
+}
+
+// GetFileQuickInfo returns quick statistics for a file
+func (h *AnalysisHandler) GetFileQuickInfo(projectPath, filePath string) (*FileQuickInfo, error) {
+	info := &FileQuickInfo{}
+
+	// Get symbol count using symbol index
+	service := project.NewStructureServiceLazy(h.log)
+	symbols, _ := service.GetFileSymbols(projectPath, filePath)
+	info.SymbolCount = len(symbols)
+
+	// Get import count
+	imports, _ := service.GetFileImports(projectPath, filePath)
+	info.ImportCount = len(imports)
+
+	// Get dependent files count
+	dependents, _ := service.GetDependentFiles(projectPath, filePath)
+	info.DependentCount = len(dependents)
+
+	// Get cyclomatic complexity
+	if content, err := os.ReadFile(filepath.Join(projectPath, filePath)); err == nil {
+		complexity := analyzers.AnalyzeFileComplexity(filePath, content)
+		info.AvgComplexity = complexity.Average
+		info.MaxComplexity = complexity.Max
+		info.ComplexityEstimated = complexity.Estimated
+	}
+
+	// Calculate change risk based on dependents and complexity
+	info.ChangeRisk = calculateRisk(info.DependentCount, info.SymbolCount, info.MaxComplexity)
+	info.RiskLevel = getRiskLevel(info.ChangeRisk)
+
+	return info, nil
+}
+
+func calculateRisk(dependents, symbols, maxComplexity int) float64 {
+	// Adjust by complexity of the most complex function
+	var complexityRisk float64
+	switch {
+	case maxComplexity > 20:
+		complexityRisk = 0.2
+	case maxComplexity > 10:
+		complexityRisk = 0.1
+	}
+
+	if dependents == 0 {
+		return 0.1 + complexityRisk
+	}
+	risk := float64(dependents) / 20.0 // normalize to 0-1 range
+	if risk > 1.0 {
+		risk = 1.0
+	}
+	// Adjust by symbol count (more symbols = more risk)
+	if symbols > 20 {
+		risk += 0.1
+	}
+	risk += complexityRisk
+	if risk > 1.0 {
+		risk = 1.0
+	}
+	return risk
+}
+
+func getRiskLevel(risk float64) string {
+	if risk < 0.3 {
+		return "low"
+	}
+	if risk < 0.7 {
+		return "medium"
+	}
+	return "high"
+}
+
+// ImpactPreviewResult contains impact analysis for selected files
+type ImpactPreviewResult struct {
+	TotalDependents int            `json:"totalDependents"`
+	AggregateRisk   float64        `json:"aggregateRisk"`
+	RiskLevel       string         `json:"riskLevel"`
+	AffectedFiles   []AffectedFile `json:"affectedFiles"`
+	RelatedTests    []string       `json:"relatedTests"`
+}
+
+// AffectedFile represents a file affected by changes
+type AffectedFile struct {
+	Path       string  `json:"path"`
+	Type       string  `json:"type"` // "direct", "transitive"
+	Depth      int     `json:"depth"`
+	Dependents int     `json:"dependents"`
+	Risk       float64 `json:"risk"`
+}
+
+const (
+	defaultImpactDepth = 3
+	maxImpactDepth     = 6
+	maxAffectedFiles   = 20
+)
+
+// GetImpactPreview returns impact analysis for selected files.
+// Dependents are followed transitively up to maxDepth levels (<= 0 uses the default).
+func (h *AnalysisHandler) GetImpactPreview(projectPath string, filePaths []string, maxDepth int) (*ImpactPreviewResult, error) {
+	result := &ImpactPreviewResult{
+		AffectedFiles: []AffectedFile{},
+		RelatedTests:  []string{},
+	}
+
+	service := project.NewStructureServiceLazy(h.log)
+	affected := collectImpact(filePaths, maxDepth, func(filePath string) []string {
+		dependents, err := service.GetDependentFiles(projectPath, filePath)
+		if err != nil {
+			return nil
+		}
+		return dependents
+	})
+
+	var weightedDependents float64
+	for _, file := range affected {
+		weightedDependents += impactWeight(file.Depth)
+		if isTestFile(file.Path) {
+			result.RelatedTests = append(result.RelatedTests, file.Path)
+		}
+	}
+
+	// Risk of the selected files themselves
+	var totalRisk float64
+	for _, filePath := range filePaths {
+		info, _ := h.GetFileQuickInfo(projectPath, filePath)
+		if info != nil {
+			totalRisk += info.ChangeRisk
+		}
+	}
+
+	result.TotalDependents = len(affected)
+	if len(filePaths) > 0 {
+		result.AggregateRisk = totalRisk / float64(len(filePaths))
+	}
+	// Blast radius: each dependent counts less the further away it is
+	result.AggregateRisk = max(result.AggregateRisk, min(weightedDependents/20.0, 1.0))
+	result.RiskLevel = getRiskLevel(result.AggregateRisk)
+
+	// Keep the highest-risk files
+	if len(affected) > maxAffectedFiles {
+		affected = affected[:maxAffectedFiles]
+	}
+	result.AffectedFiles = append(result.AffectedFiles, affected...)
+
+	return result, nil
+}
+
+// collectImpact walks dependents breadth-first from the given files and returns
+// every affected file, sorted by risk (highest first). dependentsOf is called
+// at most once per file
+func collectImpact(filePaths []string, maxDepth int, dependentsOf func(string) []string) []AffectedFile {
+	if maxDepth <= 0 {
+		maxDepth = defaultImpactDepth
+	}
+	maxDepth = min(maxDepth, maxImpactDepth)
+
+	// A dependent is counted at one level and expanded at the next
+	cache := make(map[string][]string)
+	lookup := dependentsOf
+	dependentsOf = func(filePath string) []string {
+		dependents, ok := cache[filePath]
+		if !ok {
+			dependents = lookup(filePath)
+			cache[filePath] = dependents
+		}
+		return dependents
+	}
+
+	seen := make(map[string]bool)
+	for _, filePath := range filePaths {
+		seen[filePath] = true
+	}
+
+	var affected []AffectedFile
+	level := filePaths
+	for depth := 1; depth <= maxDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, filePath := range level {
+			for _, dep := range dependentsOf(filePath) {
+				if seen[dep] {
+					continue
+				}
+				seen[dep] = true
+				next = append(next, dep)
+			}
+		}
+
+		for _, dep := range next {
+			depType := "transitive"
+			if depth == 1 {
+				depType = "direct"
+			}
+			dependents := len(dependentsOf(dep))
+			affected = append(affected, AffectedFile{
+				Path:       dep,
+				Type:       depType,
+				Depth:      depth,
+				Dependents: dependents,
+				Risk:       calculateRisk(dependents, 0, 0) * impactWeight(depth),
+			})
+		}
+		level = next
+	}
+
+	sort.SliceStable(affected, func(i, j int) bool {
+		if affected[i].Risk != affected[j].Risk {
+			return affected[i].Risk > affected[j].Risk
+		}
+		if affected[i].Depth != affected[j].Depth {
+			return affected[i].Depth < affected[j].Depth
+		}
+		return affected[i].Path < affected[j].Path
+	})
+	return affected
+}
+
+// impactWeight is how much a dependent at the given distance counts
+func impactWeight(depth int) float64 {
+	return 1.0 / float64(depth)
+}
+
+func isTestFile(path string) bool {
+	return strings.Contains(path, "_test.") ||
+		strings.Contains(path, ".test.") ||
+		strings.Contains(path, ".spec.") ||
+		strings.Contains(path, "/tests/") ||
+		strings.Contains(path, "/__tests__/")
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import "testing"
+
+func TestCollectImpact_Transitive(t *testing.T) {
+	graph := map[string][]string{
+		"core.go":    {"service.go", "core_test.go"},
+		"service.go": {"api.go", "worker.go", "core.go"},
+		"api.go":     {"main.go"},
+	}
+	calls := make(map[string]int)
+	dependentsOf := func(path string) []string {
+		calls[path]++
+		return graph[path]
+	}
+
+	affected := collectImpact([]string{"core.go"}, 2, dependentsOf)
+	byPath := make(map[string]AffectedFile)
+	for _, file := range affected {
+		byPath[file.Path] = file
+	}
+
+	if len(affected) != 4 {
+		t.Fatalf("expected 4 affected files within depth 2, got %+v", affected)
+	}
+	if f := byPath["service.go"]; f.Type != "direct" || f.Depth != 1 || f.Dependents != 3 {
+		t.Errorf("unexpected service.go entry %+v", f)
+	}
+	if f := byPath["api.go"]; f.Type != "transitive" || f.Depth != 2 {
+		t.Errorf("unexpected api.go entry %+v", f)
+	}
+	if _, ok := byPath["main.go"]; ok {
+		t.Error("main.go is beyond the depth limit")
+	}
+	if affected[0].Path != "service.go" {
+		t.Errorf("expected highest-risk file first, got %s", affected[0].Path)
+	}
+	for i := 1; i < len(affected); i++ {
+		if affected[i].Risk > affected[i-1].Risk {
+			t.Errorf("files not sorted by risk: %+v", affected)
+		}
+	}
+	for path, n := range calls {
+		if n > 1 {
+			t.Errorf("dependents of %s looked up %d times", path, n)
+		}
+	}
+}
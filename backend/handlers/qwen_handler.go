@@ -11,6 +11,12 @@ import (
 type QwenHandler struct {
 	log             domain.Logger
 	qwenTaskService *ai.QwenTaskService
+	requests        aiRequestTracker // Optional, makes tasks cancellable by request ID
+}
+
+// aiRequestTracker registers cancellable AI requests (see AIHandler.BeginRequest)
+type aiRequestTracker interface {
+	BeginRequest(ctx context.Context, kind string) (context.Context, string, func())
 }
 
 // NewQwenHandler creates a new Qwen handler
@@ -24,6 +30,11 @@ func NewQwenHandler(
 	}
 }
 
+// SetRequestTracker makes task executions cancellable through the tracker
+func (h *QwenHandler) SetRequestTracker(tracker aiRequestTracker) {
+	h.requests = tracker
+}
+
 // ExecuteTaskRequest is the request for executing a task
 type ExecuteTaskRequest struct {
 	Task          string   `json:"task"`
@@ -38,6 +49,7 @@ type ExecuteTaskRequest struct {
 
 // ExecuteTaskResponse is the response from task execution
 type ExecuteTaskResponse struct {
+	RequestID      string               `json:"requestId,omitempty"`
 	Content        string               `json:"content"`
 	Model          string               `json:"model"`
 	TokensUsed     int                  `json:"tokensUsed"`
@@ -74,10 +86,17 @@ type CallStackInfo struct {
 	Dependencies []string `json:"dependencies"`
 }
 
-// ExecuteTask executes a task with Qwen
-func (h *QwenHandler) ExecuteTask(req ExecuteTaskRequest) ExecuteTaskResponse {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+// ExecuteTask executes a task with Qwen; the task stops when ctx is done or
+// its request is cancelled
+func (h *QwenHandler) ExecuteTask(ctx context.Context, req ExecuteTaskRequest) ExecuteTaskResponse {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
+	var requestID string
+	if h.requests != nil {
+		var finish func()
+		ctx, requestID, finish = h.requests.BeginRequest(ctx, "qwen")
+		defer finish()
+	}
 
 	taskReq := ai.TaskRequest{
 		Task:          req.Task,
@@ -93,12 +112,14 @@ func (h *QwenHandler) ExecuteTask(req ExecuteTaskRequest) ExecuteTaskResponse {
 	result, err := h.qwenTaskService.ExecuteTask(ctx, taskReq)
 	if err != nil {
 		return ExecuteTaskResponse{
-			Success: false,
-			Error:   err.Error(),
+			RequestID: requestID,
+			Success:   false,
+			Error:     err.Error(),
 		}
 	}
 
 	return ExecuteTaskResponse{
+		RequestID:      requestID,
 		Content:        result.Content,
 		Model:          result.Model,
 		TokensUsed:     result.TokensUsed,
@@ -110,8 +131,8 @@ func (h *QwenHandler) ExecuteTask(req ExecuteTaskRequest) ExecuteTaskResponse {
 }
 
 // PreviewContext returns a preview of the context that would be collected
-func (h *QwenHandler) PreviewContext(req ExecuteTaskRequest) PreviewContextResponse {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (h *QwenHandler) PreviewContext(ctx context.Context, req ExecuteTaskRequest) PreviewContextResponse {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	taskReq := ai.TaskRequest{
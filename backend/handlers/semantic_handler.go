@@ -7,26 +7,45 @@ import (
 	"shotgun_code/domain"
 )
 
+// SemanticServicesGetter returns the semantic search and RAG services,
+// loading them on demand
+type SemanticServicesGetter func(ctx context.Context) (domain.SemanticSearchService, domain.RAGService, error)
+
 // SemanticHandler handles semantic search API requests
 type SemanticHandler struct {
-	semanticSearch domain.SemanticSearchService
-	ragService     domain.RAGService
-	log            domain.Logger
+	services SemanticServicesGetter
+	log      domain.Logger
 }
 
 // NewSemanticHandler creates a new semantic handler
 func NewSemanticHandler(
-	semanticSearch domain.SemanticSearchService,
-	ragService domain.RAGService,
+	services SemanticServicesGetter,
 	log domain.Logger,
 ) *SemanticHandler {
 	return &SemanticHandler{
-		semanticSearch: semanticSearch,
-		ragService:     ragService,
-		log:            log,
+		services: services,
+		log:      log,
 	}
 }
 
+// semanticSearch returns the semantic search service
+func (h *SemanticHandler) semanticSearch(ctx context.Context) (domain.SemanticSearchService, error) {
+	search, _, err := h.services(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search unavailable: %w", err)
+	}
+	return search, nil
+}
+
+// ragService returns the RAG service
+func (h *SemanticHandler) ragService(ctx context.Context) (domain.RAGService, error) {
+	_, ragService, err := h.services(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search unavailable: %w", err)
+	}
+	return ragService, nil
+}
+
 // SemanticSearchRequest represents a search request from frontend
 type SemanticSearchRequest struct {
 	Query       string   `json:"query"`
@@ -36,6 +55,16 @@ type SemanticSearchRequest struct {
 	SearchType  string   `json:"searchType"`
 	Languages   []string `json:"languages,omitempty"`
 	ChunkTypes  []string `json:"chunkTypes,omitempty"`
+	Snippets    bool     `json:"snippets,omitempty"`
+	Highlight   bool     `json:"highlight,omitempty"`
+	// Hybrid search blend; both zero means the defaults
+	SemanticWeight float32 `json:"semanticWeight,omitempty"`
+	KeywordWeight  float32 `json:"keywordWeight,omitempty"`
+	// Paging and post-filtering of the final scores
+	Offset         int     `json:"offset,omitempty"`
+	ScoreThreshold float32 `json:"scoreThreshold,omitempty"`
+	// Rerank re-scores the top candidates with the LLM reranker
+	Rerank bool `json:"rerank,omitempty"`
 }
 
 // Search performs semantic search
@@ -59,6 +88,8 @@ func (h *SemanticHandler) Search(ctx context.Context, requestJSON string) (strin
 		searchType = domain.SearchTypeSemantic
 	case "keyword":
 		searchType = domain.SearchTypeKeyword
+	case "auto":
+		searchType = domain.SearchTypeAuto
 	}
 
 	searchReq := domain.SemanticSearchRequest{
@@ -67,6 +98,14 @@ func (h *SemanticHandler) Search(ctx context.Context, requestJSON string) (strin
 		TopK:        req.TopK,
 		MinScore:    req.MinScore,
 		SearchType:  searchType,
+		Snippets:    req.Snippets,
+		Highlight:   req.Highlight,
+
+		SemanticWeight: req.SemanticWeight,
+		KeywordWeight:  req.KeywordWeight,
+		Offset:         req.Offset,
+		ScoreThreshold: req.ScoreThreshold,
+		Rerank:         req.Rerank,
 	}
 
 	// Add filters if provided
@@ -82,7 +121,11 @@ func (h *SemanticHandler) Search(ctx context.Context, requestJSON string) (strin
 		}
 	}
 
-	results, err := h.semanticSearch.Search(ctx, searchReq)
+	semanticSearch, err := h.semanticSearch(ctx)
+	if err != nil {
+		return "", err
+	}
+	results, err := semanticSearch.Search(ctx, searchReq)
 	if err != nil {
 		return "", fmt.Errorf("search failed: %w", err)
 	}
@@ -128,7 +171,11 @@ func (h *SemanticHandler) FindSimilar(ctx context.Context, requestJSON string) (
 		ExcludeSelf: req.ExcludeSelf,
 	}
 
-	results, err := h.semanticSearch.FindSimilar(ctx, searchReq)
+	semanticSearch, err := h.semanticSearch(ctx)
+	if err != nil {
+		return "", err
+	}
+	results, err := semanticSearch.FindSimilar(ctx, searchReq)
 	if err != nil {
 		return "", fmt.Errorf("find similar failed: %w", err)
 	}
@@ -141,20 +188,64 @@ func (h *SemanticHandler) FindSimilar(ctx context.Context, requestJSON string) (
 	return string(resultJSON), nil
 }
 
+// SearchRelatedRequest represents a "more like this" request for a known location
+type SearchRelatedRequest struct {
+	ProjectRoot string `json:"projectRoot"`
+	FilePath    string `json:"filePath"`
+	StartLine   int    `json:"startLine"`
+	TopK        int    `json:"topK"`
+}
+
+// SearchRelated finds chunks related to the chunk at a file location
+func (h *SemanticHandler) SearchRelated(ctx context.Context, requestJSON string) (string, error) {
+	var req SearchRelatedRequest
+	if err := json.Unmarshal([]byte(requestJSON), &req); err != nil {
+		return "", fmt.Errorf("invalid request: %w", err)
+	}
+
+	semanticSearch, err := h.semanticSearch(ctx)
+	if err != nil {
+		return "", err
+	}
+	results, err := semanticSearch.SearchRelated(ctx, req.ProjectRoot, req.FilePath, req.StartLine, req.TopK)
+	if err != nil {
+		return "", fmt.Errorf("search related failed: %w", err)
+	}
+
+	resultJSON, err := json.Marshal(results)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal results: %w", err)
+	}
+
+	return string(resultJSON), nil
+}
+
 // IndexProject indexes a project for semantic search
 func (h *SemanticHandler) IndexProject(ctx context.Context, projectRoot string) error {
 	h.log.Info(fmt.Sprintf("Starting semantic indexing for: %s", projectRoot))
-	return h.semanticSearch.IndexProject(ctx, projectRoot)
+	semanticSearch, err := h.semanticSearch(ctx)
+	if err != nil {
+		return err
+	}
+	return semanticSearch.IndexProject(ctx, projectRoot)
 }
 
 // IndexFile indexes a single file
 func (h *SemanticHandler) IndexFile(ctx context.Context, projectRoot, filePath string) error {
-	return h.semanticSearch.IndexFile(ctx, projectRoot, filePath)
+	semanticSearch, err := h.semanticSearch(ctx)
+	if err != nil {
+		return err
+	}
+	return semanticSearch.IndexFile(ctx, projectRoot, filePath)
 }
 
 // GetStats returns indexing statistics
 func (h *SemanticHandler) GetStats(ctx context.Context, projectRoot string) (string, error) {
-	stats, err := h.semanticSearch.GetStats(ctx, projectRoot)
+	semanticSearch, err := h.semanticSearch(ctx)
+	if err != nil {
+		return "", err
+	}
+	stats, err := semanticSearch.GetStats(ctx, projectRoot)
 	if err != nil {
 		return "", err
 	}
@@ -169,7 +260,12 @@ func (h *SemanticHandler) GetStats(ctx context.Context, projectRoot string) (str
 
 // IsIndexed checks if a project is indexed
 func (h *SemanticHandler) IsIndexed(ctx context.Context, projectRoot string) bool {
-	return h.semanticSearch.IsIndexed(ctx, projectRoot)
+	semanticSearch, err := h.semanticSearch(ctx)
+	if err != nil {
+		h.log.Warning(err.Error())
+		return false
+	}
+	return semanticSearch.IsIndexed(ctx, projectRoot)
 }
 
 // RetrieveContextRequest represents a RAG context request
@@ -190,7 +286,11 @@ func (h *SemanticHandler) RetrieveContext(ctx context.Context, requestJSON strin
 		req.MaxTokens = 4000
 	}
 
-	chunks, err := h.ragService.RetrieveContext(ctx, req.Query, req.ProjectRoot, req.MaxTokens)
+	ragService, err := h.ragService(ctx)
+	if err != nil {
+		return "", err
+	}
+	chunks, err := ragService.RetrieveContext(ctx, req.Query, req.ProjectRoot, req.MaxTokens)
 	if err != nil {
 		return "", fmt.Errorf("retrieve context failed: %w", err)
 	}
@@ -223,9 +323,18 @@ func (h *SemanticHandler) HybridSearch(ctx context.Context, requestJSON string)
 		TopK:        req.TopK,
 		MinScore:    req.MinScore,
 		SearchType:  domain.SearchTypeHybrid,
+
+		SemanticWeight: req.SemanticWeight,
+		KeywordWeight:  req.KeywordWeight,
+		Offset:         req.Offset,
+		ScoreThreshold: req.ScoreThreshold,
 	}
 
-	results, err := h.ragService.HybridSearch(ctx, searchReq)
+	ragService, err := h.ragService(ctx)
+	if err != nil {
+		return "", err
+	}
+	results, err := ragService.HybridSearch(ctx, searchReq)
 	if err != nil {
 		return "", fmt.Errorf("hybrid search failed: %w", err)
 	}
@@ -74,12 +74,12 @@ func (h *TaskflowHandler) UpdateTaskStatus(taskID string, state domain.TaskState
 }
 
 // ExecuteTask executes a task
-func (h *TaskflowHandler) ExecuteTask(taskID string) error {
+func (h *TaskflowHandler) ExecuteTask(ctx context.Context, taskID string) error {
 	atomic.AddInt64(&h.activeTaskCount, 1)
 	defer atomic.AddInt64(&h.activeTaskCount, -1)
 	atomic.AddInt64(&h.totalTasks, 1)
 
-	err := h.taskflowService.ExecuteTask(taskID)
+	err := h.taskflowService.ExecuteTask(ctx, taskID)
 	if err != nil {
 		atomic.AddInt64(&h.failedTasks, 1)
 	}
@@ -106,6 +106,11 @@ func (h *TaskflowHandler) ValidateTaskflow() error {
 	return h.taskflowService.ValidateTaskflow()
 }
 
+// ValidateTaskflowPlan validates plan.yaml and returns problems with line context
+func (h *TaskflowHandler) ValidateTaskflowPlan(planPath string) (*domain.PlanValidationResult, error) {
+	return h.taskflowService.ValidatePlan(planPath)
+}
+
 // GetTaskflowProgress returns taskflow progress
 func (h *TaskflowHandler) GetTaskflowProgress() (float64, error) {
 	return h.taskflowService.GetTaskflowProgress()
@@ -4,31 +4,54 @@ package common
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"shotgun_code/domain"
+	"strings"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"google.golang.org/api/googleapi"
 )
 
-// HandleOpenAIError converts OpenAI API errors to domain errors
+// HandleOpenAIError converts OpenAI API errors to domain.ProviderError, with
+// the Retry-After delay recorded by RetryAfterTransport (0 if none). Errors
+// without an HTTP status are returned unchanged.
 // This is used by providers that use the go-openai client (OpenAI, Qwen, OpenRouter)
-func HandleOpenAIError(err error) error {
+func HandleOpenAIError(provider string, err error, retryAfter time.Duration) error {
 	if err == nil {
 		return nil
 	}
 
 	var apiErr *openai.APIError
-	if errors.As(err, &apiErr) {
-		switch apiErr.HTTPStatusCode {
-		case http.StatusUnauthorized:
-			return domain.ErrInvalidAPIKey
-		case http.StatusTooManyRequests:
-			return domain.ErrRateLimitExceeded
-		}
+	if errors.As(err, &apiErr) && apiErr.HTTPStatusCode > 0 {
+		return domain.NewProviderError(provider, apiErr.HTTPStatusCode, retryAfter, err)
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) && reqErr.HTTPStatusCode > 0 {
+		return domain.NewProviderError(provider, reqErr.HTTPStatusCode, retryAfter, err)
 	}
 	return err
 }
 
+// HandleGoogleAPIError converts googleapi errors returned by the Gemini client
+// to domain.ProviderError, reading Retry-After from the response headers.
+// Errors without an HTTP status are returned unchanged
+func HandleGoogleAPIError(provider string, err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code > 0 {
+		return domain.NewProviderError(provider, apiErr.Code, ParseRetryAfter(apiErr.Header.Get("Retry-After"), time.Now()), err)
+	}
+	return err
+}
+
+// HTTPStatusError converts a failed HTTP response of a provider with a plain
+// HTTP API to domain.ProviderError, keeping the response body as the cause
+func HTTPStatusError(provider string, resp *http.Response, body []byte) error {
+	cause := fmt.Errorf("%s", strings.TrimSpace(string(body)))
+	return domain.NewProviderError(provider, resp.StatusCode, ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), cause)
+}
+
 // IsContextCanceled checks if the error is due to context cancellation
 func IsContextCanceled(err error) bool {
 	return errors.Is(err, context.Canceled)
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type retryAfterKey struct{}
+
+// WithRetryAfter returns a context whose requests sent through
+// RetryAfterTransport record the Retry-After delay of their response.
+// The delay can be read once the request has returned.
+func WithRetryAfter(ctx context.Context) (context.Context, *time.Duration) {
+	retryAfter := new(time.Duration)
+	return context.WithValue(ctx, retryAfterKey{}, retryAfter), retryAfter
+}
+
+// RetryAfterTransport records the Retry-After header of responses for
+// contexts created with WithRetryAfter. Clients such as go-openai only
+// return the status of a failed request, not its headers.
+type RetryAfterTransport struct {
+	Base http.RoundTripper // http.DefaultTransport if nil
+}
+
+// NewRetryAfterClient returns an HTTP client using RetryAfterTransport
+func NewRetryAfterClient() *http.Client {
+	return &http.Client{Transport: &RetryAfterTransport{}}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RetryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err == nil {
+		if retryAfter, ok := req.Context().Value(retryAfterKey{}).(*time.Duration); ok {
+			*retryAfter = ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+	}
+	return resp, err
+}
+
+// ParseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date; missing, invalid and past values are 0
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"shotgun_code/domain"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/api/googleapi"
+)
+
+func TestHandleOpenAIError_RetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"rate limited","type":"rate_limit"}}`))
+	}))
+	defer server.Close()
+
+	config := openai.DefaultConfig("key")
+	config.BaseURL = server.URL
+	config.HTTPClient = NewRetryAfterClient()
+	client := openai.NewClientWithConfig(config)
+
+	ctx, retryAfter := WithRetryAfter(context.Background())
+	_, err := client.ListModels(ctx)
+	err = HandleOpenAIError("OpenAI", err, *retryAfter)
+
+	var providerErr *domain.ProviderError
+	if !errors.As(err, &providerErr) {
+		t.Fatalf("expected a ProviderError, got %v", err)
+	}
+	if providerErr.StatusCode != 429 || !providerErr.Retryable || providerErr.RetryAfter != 7*time.Second {
+		t.Errorf("unexpected provider error %+v", providerErr)
+	}
+	if !errors.Is(err, domain.ErrRateLimitExceeded) {
+		t.Error("expected the error to match ErrRateLimitExceeded")
+	}
+}
+
+func TestHandleGoogleAPIError_RetryAfter(t *testing.T) {
+	apiErr := &googleapi.Error{
+		Code:    http.StatusTooManyRequests,
+		Message: "quota exceeded",
+		Header:  http.Header{"Retry-After": []string{"5"}},
+	}
+	err := HandleGoogleAPIError("Gemini", fmt.Errorf("generate: %w", apiErr))
+
+	var providerErr *domain.ProviderError
+	if !errors.As(err, &providerErr) {
+		t.Fatalf("expected a ProviderError, got %v", err)
+	}
+	if providerErr.Provider != "Gemini" || providerErr.StatusCode != 429 || providerErr.RetryAfter != 5*time.Second {
+		t.Errorf("unexpected provider error %+v", providerErr)
+	}
+
+	plain := errors.New("connection reset")
+	if got := HandleGoogleAPIError("Gemini", plain); got != plain {
+		t.Errorf("expected an error without status to be returned unchanged, got %v", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"120":                           2 * time.Minute,
+		"soon":                          0,
+		"Fri, 02 Jan 2026 03:04:35 GMT": 30 * time.Second,
+		"Fri, 02 Jan 2026 03:00:00 GMT": 0,
+	}
+	for value, want := range tests {
+		if got := ParseRetryAfter(value, now); got != want {
+			t.Errorf("ParseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
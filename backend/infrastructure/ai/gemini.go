@@ -15,6 +15,9 @@ import (
 	"google.golang.org/api/option"
 )
 
+// geminiProviderName labels Gemini errors converted to domain.ProviderError
+const geminiProviderName = "Gemini"
+
 type GeminiProviderImpl struct {
 	log    domain.Logger
 	apiKey string
@@ -50,7 +53,7 @@ func (p *GeminiProviderImpl) ListModels(ctx context.Context) ([]string, error) {
 			if strings.Contains(err.Error(), "API_KEY_INVALID") {
 				return nil, domain.ErrInvalidAPIKey
 			}
-			return nil, fmt.Errorf("failed to iterate models: %w", err)
+			return nil, fmt.Errorf("failed to iterate models: %w", common.HandleGoogleAPIError(geminiProviderName, err))
 		}
 
 		isSupported := false
@@ -102,7 +105,7 @@ func (p *GeminiProviderImpl) Generate(ctx context.Context, req domain.AIRequest)
 	resp, err := model.GenerateContent(ctx, genai.Text(req.UserPrompt))
 	if err != nil {
 		p.log.Error(fmt.Sprintf("Gemini API request failed: %v", err))
-		return domain.AIResponse{}, err
+		return domain.AIResponse{}, common.HandleGoogleAPIError(geminiProviderName, err)
 	}
 
 	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
@@ -191,8 +194,9 @@ func (p *GeminiProviderImpl) GenerateStream(ctx context.Context, req domain.AIRe
 		}
 		if err != nil {
 			p.log.Error(fmt.Sprintf("Gemini stream error: %v", err))
-			onChunk(domain.StreamChunk{Done: true, Error: err.Error()})
-			return err
+			providerErr := common.HandleGoogleAPIError(geminiProviderName, err)
+			onChunk(domain.StreamChunk{Done: true, Error: providerErr.Error()})
+			return providerErr
 		}
 
 		if len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"shotgun_code/domain"
+	"shotgun_code/infrastructure/ai/common"
 	"strings"
 	"time"
 )
@@ -287,7 +288,7 @@ func (c *LlamaCppClient) makeRequest(ctx context.Context, request LlamaCppReques
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("llama.cpp server returned status %d: %s", resp.StatusCode, string(body))
+		return nil, common.HTTPStatusError("llama.cpp", resp, body)
 	}
 
 	var response LlamaCppResponse
@@ -319,9 +320,9 @@ func (c *LlamaCppClient) createStreamHTTPRequest(ctx context.Context, request Ll
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("llama.cpp server returned status %d: %s", resp.StatusCode, string(body))
+		resp.Body.Close()
+		return nil, common.HTTPStatusError("llama.cpp", resp, body)
 	}
 	return resp, nil
 }
@@ -192,7 +192,7 @@ func (p *LocalAIProviderImpl) Generate(ctx context.Context, req domain.AIRequest
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return domain.AIResponse{}, fmt.Errorf("LocalAI request failed: %s, status: %d", string(body), resp.StatusCode)
+		return domain.AIResponse{}, common.HTTPStatusError("LocalAI", resp, body)
 	}
 
 	// Парсим ответ
@@ -2,7 +2,6 @@ package ai
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"shotgun_code/domain"
 	"shotgun_code/infrastructure/ai/common"
@@ -15,29 +14,39 @@ import (
 type OpenAIProviderImpl struct {
 	client *openai.Client
 	log    domain.Logger
+	name   string // provider label in errors: OpenAI or an OpenAI compatible service
 }
 
 func NewOpenAI(apiKey, host string, log domain.Logger) (domain.AIProvider, error) {
+	return newOpenAICompatible("OpenAI", apiKey, host, log), nil
+}
+
+// NewOpenRouter creates a provider for the OpenAI compatible OpenRouter API
+func NewOpenRouter(apiKey, host string, log domain.Logger) (domain.AIProvider, error) {
+	return newOpenAICompatible("OpenRouter", apiKey, host, log), nil
+}
+
+func newOpenAICompatible(name, apiKey, host string, log domain.Logger) *OpenAIProviderImpl {
 	config := openai.DefaultConfig(apiKey)
 	if host != "" {
 		config.BaseURL = host
 	}
+	config.HTTPClient = common.NewRetryAfterClient()
 	client := openai.NewClientWithConfig(config)
 	return &OpenAIProviderImpl{
 		client: client,
 		log:    log,
-	}, nil
+		name:   name,
+	}
 }
 
 func (p *OpenAIProviderImpl) ListModels(ctx context.Context) ([]string, error) {
 	p.log.Info("Requesting model list from OpenAI compatible API...")
+	ctx, retryAfter := common.WithRetryAfter(ctx)
 	resp, err := p.client.ListModels(ctx)
 	if err != nil {
 		p.log.Error(fmt.Sprintf("Error getting model list: %v", err))
-		if domainErr := common.HandleOpenAIError(err); !errors.Is(domainErr, err) {
-			return nil, domainErr
-		}
-		return nil, fmt.Errorf("failed to list models: %w", err)
+		return nil, fmt.Errorf("failed to list models: %w", common.HandleOpenAIError(p.name, err, *retryAfter))
 	}
 
 	models := make([]string, 0, len(resp.Models))
@@ -54,11 +63,12 @@ func (p *OpenAIProviderImpl) Generate(ctx context.Context, req domain.AIRequest)
 	p.log.Info(fmt.Sprintf("Sending request to OpenAI compatible API with model: %s", req.Model))
 
 	completionReq := common.BuildCompletionRequest(req, false)
+	ctx, retryAfter := common.WithRetryAfter(ctx)
 	resp, err := p.client.CreateChatCompletion(ctx, completionReq)
 
 	if err != nil {
 		p.log.Error(fmt.Sprintf("OpenAI API request failed: %v", err))
-		return domain.AIResponse{}, err
+		return domain.AIResponse{}, common.HandleOpenAIError(p.name, err, *retryAfter)
 	}
 
 	if len(resp.Choices) == 0 {
@@ -82,7 +92,7 @@ func (p *OpenAIProviderImpl) Generate(ctx context.Context, req domain.AIRequest)
 
 func (p *OpenAIProviderImpl) GetProviderInfo() domain.ProviderInfo {
 	return domain.ProviderInfo{
-		Name:            "OpenAI",
+		Name:            p.name,
 		Version:         "1.0",
 		Capabilities:    []string{"chat", "completion", "embeddings"},
 		Limitations:     []string{"rate_limited", "token_limited"},
@@ -107,11 +117,13 @@ func (p *OpenAIProviderImpl) GenerateStream(ctx context.Context, req domain.AIRe
 	p.log.Info(fmt.Sprintf("Starting streaming request to OpenAI API with model: %s", req.Model))
 
 	completionReq := common.BuildCompletionRequest(req, true)
+	ctx, retryAfter := common.WithRetryAfter(ctx)
 	stream, err := p.client.CreateChatCompletionStream(ctx, completionReq)
 	if err != nil {
 		p.log.Error(fmt.Sprintf("OpenAI API stream request failed: %v", err))
-		onChunk(domain.StreamChunk{Done: true, Error: err.Error()})
-		return err
+		providerErr := common.HandleOpenAIError(p.name, err, *retryAfter)
+		onChunk(domain.StreamChunk{Done: true, Error: providerErr.Error()})
+		return providerErr
 	}
 	defer stream.Close()
 	return common.StreamProcessor(stream, onChunk, p.log)
@@ -45,10 +45,10 @@ func GetProviderRegistry(openRouterHost string) map[string]ProviderConfig {
 				if effectiveHost == "" {
 					effectiveHost = openRouterHost
 				}
-				return NewOpenAI(apiKey, effectiveHost, log)
+				return NewOpenRouter(apiKey, effectiveHost, log)
 			},
 			ModelFetcher: func(ctx context.Context, apiKey, host string, log domain.Logger) ([]string, error) {
-				p, err := NewOpenAI(apiKey, openRouterHost, log)
+				p, err := NewOpenRouter(apiKey, openRouterHost, log)
 				if err != nil {
 					return nil, err
 				}
@@ -2,7 +2,6 @@ package ai
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"shotgun_code/domain"
 	"shotgun_code/infrastructure/ai/common"
@@ -36,6 +35,7 @@ func NewQwen(apiKey, host string, log domain.Logger) (domain.AIProvider, error)
 
 	config := openai.DefaultConfig(apiKey)
 	config.BaseURL = host
+	config.HTTPClient = common.NewRetryAfterClient()
 
 	client := openai.NewClientWithConfig(config)
 	return &QwenProviderImpl{
@@ -87,13 +87,11 @@ func (p *QwenProviderImpl) Generate(ctx context.Context, req domain.AIRequest) (
 	p.log.Info(fmt.Sprintf("Sending request to Qwen API with model: %s", req.Model))
 
 	completionReq := common.BuildCompletionRequest(req, false)
+	ctx, retryAfter := common.WithRetryAfter(ctx)
 	resp, err := p.client.CreateChatCompletion(ctx, completionReq)
 	if err != nil {
 		p.log.Error(fmt.Sprintf("Qwen API request failed: %v", err))
-		if domainErr := common.HandleOpenAIError(err); !errors.Is(domainErr, err) {
-			return domain.AIResponse{}, domainErr
-		}
-		return domain.AIResponse{}, err
+		return domain.AIResponse{}, common.HandleOpenAIError("Qwen", err, *retryAfter)
 	}
 
 	if len(resp.Choices) == 0 {
@@ -118,10 +116,11 @@ func (p *QwenProviderImpl) GenerateStream(ctx context.Context, req domain.AIRequ
 	p.log.Info(fmt.Sprintf("Starting streaming request to Qwen API with model: %s", req.Model))
 
 	completionReq := common.BuildCompletionRequest(req, true)
+	ctx, retryAfter := common.WithRetryAfter(ctx)
 	stream, err := p.client.CreateChatCompletionStream(ctx, completionReq)
 	if err != nil {
 		p.log.Error(fmt.Sprintf("Qwen API stream request failed: %v", err))
-		domainErr := common.HandleOpenAIError(err)
+		domainErr := common.HandleOpenAIError("Qwen", err, *retryAfter)
 		onChunk(domain.StreamChunk{Done: true, Error: domainErr.Error()})
 		return domainErr
 	}
@@ -0,0 +1,92 @@
+// Package aiaudit stores the AI request audit trail.
+package aiaudit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"sort"
+	"sync"
+)
+
+// maxLineSize bounds a single entry; entries with prompt text can be large
+const maxLineSize = 16 * 1024 * 1024
+
+// FileStore implements AIAuditRepository as a JSON Lines file, one entry per line
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore creates a store appending to the file at path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Append adds an entry to the end of the file
+func (s *FileStore) Append(entry domain.AIAuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return f.Close()
+}
+
+// Query reads the file and returns matching entries, newest first. Lines that
+// can't be parsed are skipped.
+func (s *FileStore) Query(query domain.AIAuditQuery) ([]domain.AIAuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []domain.AIAuditEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	entries := make([]domain.AIAuditEntry, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		var entry domain.AIAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if query.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+	return entries, nil
+}
@@ -0,0 +1,67 @@
+package aiaudit
+
+import (
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore_AppendAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "ai-requests.jsonl")
+	store := NewFileStore(path)
+
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	entries := []domain.AIAuditEntry{
+		{ID: "1", Timestamp: base, Provider: "openai", Model: "gpt-4", Outcome: domain.AIAuditOutcomeSuccess},
+		{ID: "2", Timestamp: base.Add(time.Minute), Provider: "gemini", Model: "gemini-pro", Outcome: domain.AIAuditOutcomeError},
+		{ID: "3", Timestamp: base.Add(2 * time.Minute), Provider: "openai", Model: "gpt-4", Outcome: domain.AIAuditOutcomeCacheHit},
+	}
+	for _, e := range entries {
+		require.NoError(t, store.Append(e))
+	}
+
+	all, err := store.Query(domain.AIAuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "3", all[0].ID, "newest first")
+
+	openai, err := store.Query(domain.AIAuditQuery{Provider: "openai"})
+	require.NoError(t, err)
+	assert.Len(t, openai, 2)
+
+	errs, err := store.Query(domain.AIAuditQuery{Outcome: domain.AIAuditOutcomeError})
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "2", errs[0].ID)
+
+	since, err := store.Query(domain.AIAuditQuery{Since: base.Add(30 * time.Second), Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, since, 1)
+	assert.Equal(t, "3", since[0].ID)
+}
+
+func TestFileStore_QuerySkipsBadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai-requests.jsonl")
+	store := NewFileStore(path)
+
+	entries, err := store.Query(domain.AIAuditQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, entries, "missing file is an empty log")
+
+	require.NoError(t, store.Append(domain.AIAuditEntry{ID: "1", Outcome: domain.AIAuditOutcomeSuccess}))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString("{truncated\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	entries, err = store.Query(domain.AIAuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "1", entries[0].ID)
+}
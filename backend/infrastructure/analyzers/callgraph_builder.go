@@ -9,7 +9,9 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"shotgun_code/domain"
 	"shotgun_code/domain/analysis"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -17,6 +19,7 @@ import (
 
 const (
 	extGo          = ".go"
+	goModFile      = "go.mod"
 	dirVendor      = "vendor"
 	dirNodeModules = "node_modules"
 )
@@ -28,6 +31,11 @@ type CallGraphBuilderImpl struct {
 	graph       *analysis.CallGraph
 	depGraph    *analysis.DependencyGraph
 	fileImports map[string][]importInfo // file -> imports
+	goModules   map[string]string       // module dir (relative) -> Go module path
+	depRoot     string                  // project root of the last dependency graph
+	tsConfigs   []*tsConfigPaths        // tsconfig/jsconfig path aliases
+	preciseGo   bool                    // use go/packages type information for Go
+	scope       *domain.LanguageScope   // languages to analyze; nil means all
 
 	// Caching fields for one-time initialization
 	buildOnce    sync.Once
@@ -54,6 +62,7 @@ func NewCallGraphBuilder(registry analysis.AnalyzerRegistry) *CallGraphBuilderIm
 			Edges: make([]analysis.DependencyEdge, 0),
 		},
 		fileImports: make(map[string][]importInfo),
+		goModules:   make(map[string]string),
 	}
 }
 
@@ -87,6 +96,13 @@ func (b *CallGraphBuilderImpl) EnsureBuilt(projectRoot string) (*analysis.CallGr
 	return b.graph, b.lastBuildErr
 }
 
+// SetLanguageScope limits the call graph to files of the enabled languages
+func (b *CallGraphBuilderImpl) SetLanguageScope(scope *domain.LanguageScope) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.scope = scope
+}
+
 // Invalidate resets the call graph, forcing rebuild on next EnsureBuilt call.
 func (b *CallGraphBuilderImpl) Invalidate() {
 	b.mu.Lock()
@@ -100,6 +116,7 @@ func (b *CallGraphBuilderImpl) Invalidate() {
 		Edges: make([]analysis.DependencyEdge, 0),
 	}
 	b.fileImports = make(map[string][]importInfo)
+	b.goModules = make(map[string]string)
 	b.buildOnce = sync.Once{} // Reset sync.Once
 	b.lastBuildErr = nil
 	b.projectRoot = ""
@@ -128,6 +145,13 @@ func (b *CallGraphBuilderImpl) Build(projectRoot string) (*analysis.CallGraph, e
 	}
 	b.fileImports = make(map[string][]importInfo)
 
+	// Precise mode covers all Go packages at once; fall back to per-file
+	// syntactic analysis when the module doesn't load or type-check
+	preciseGoDone := false
+	if b.preciseGo && b.scope.Enabled("go") {
+		preciseGoDone = b.buildGoCallGraphPrecise(projectRoot) == nil
+	}
+
 	// Walk project and analyze Go files
 	err := filepath.Walk(projectRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -142,12 +166,18 @@ func (b *CallGraphBuilderImpl) Build(projectRoot string) (*analysis.CallGraph, e
 			return nil
 		}
 
+		if !b.scope.FileEnabled(path) {
+			return nil
+		}
+
 		ext := filepath.Ext(path)
 		relPath, _ := filepath.Rel(projectRoot, path)
 
 		switch ext {
 		case extGo:
-			b.analyzeGoFile(path, relPath)
+			if !preciseGoDone {
+				b.analyzeGoFile(path, relPath)
+			}
 		case ".ts", ".js", ".tsx", ".jsx":
 			b.analyzeJSFile(path, relPath)
 		case ".vue":
@@ -513,24 +543,69 @@ func (b *CallGraphBuilderImpl) GetDependencyPath(fromID, toID string, maxDepth i
 	return nil // No path found
 }
 
-// BuildForFile builds call graph for a single file
+// BuildForFile rebuilds the call graph nodes of a single file. Functions that
+// no longer exist in the file are dropped together with their edges; nil
+// content means the file was deleted.
 func (b *CallGraphBuilderImpl) BuildForFile(ctx context.Context, filePath string, content []byte) error {
 	ext := filepath.Ext(filePath)
+	if ext != extGo {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
 
-	switch ext {
-	case extGo:
-		b.analyzeGoFileContent(filePath, content)
+	declared := make(map[string]bool)
+	if content != nil {
+		ids, ok := b.analyzeGoFileContent(filePath, content)
+		if !ok {
+			// Keep the previous nodes while the file doesn't parse (e.g. mid-edit)
+			return nil
+		}
+		declared = ids
 	}
+	b.removeStaleNodes(filePath, declared)
 
 	return nil
 }
 
-func (b *CallGraphBuilderImpl) analyzeGoFileContent(relPath string, content []byte) {
+// removeStaleNodes drops nodes of a file that are not in keep, and their edges.
+// Caller must hold b.mu.
+func (b *CallGraphBuilderImpl) removeStaleNodes(relPath string, keep map[string]bool) {
+	removed := make(map[string]bool)
+	for id, node := range b.graph.Nodes {
+		if node.FilePath == relPath && !keep[id] {
+			delete(b.graph.Nodes, id)
+			removed[id] = true
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	edges := b.graph.Edges[:0]
+	for _, e := range b.graph.Edges {
+		if !removed[e.From] && !removed[e.To] {
+			edges = append(edges, e)
+		}
+	}
+	b.graph.Edges = edges
+
+	for _, node := range b.graph.Nodes {
+		node.Callers = slices.DeleteFunc(node.Callers, func(id string) bool { return removed[id] })
+		node.Callees = slices.DeleteFunc(node.Callees, func(id string) bool { return removed[id] })
+	}
+}
+
+// analyzeGoFileContent adds nodes for the functions declared in content and
+// returns their IDs; ok is false if the file doesn't parse
+func (b *CallGraphBuilderImpl) analyzeGoFileContent(relPath string, content []byte) (ids map[string]bool, ok bool) {
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, relPath, content, parser.ParseComments)
 	if err != nil {
-		return
+		return nil, false
 	}
+	ids = make(map[string]bool)
 
 	pkgName := ""
 	if file.Name != nil {
@@ -541,7 +616,14 @@ func (b *CallGraphBuilderImpl) analyzeGoFileContent(relPath string, content []by
 		if decl, ok := n.(*ast.FuncDecl); ok {
 			nodeID := b.makeFunctionID(pkgName, decl.Name.Name, relPath)
 			pos := fset.Position(decl.Pos())
+			ids[nodeID] = true
 
+			if existing, found := b.graph.Nodes[nodeID]; found {
+				// Keep known edges, only the position may have moved
+				existing.FilePath = relPath
+				existing.Line = pos.Line
+				return true
+			}
 			b.graph.Nodes[nodeID] = &analysis.CallNode{
 				ID:       nodeID,
 				Name:     decl.Name.Name,
@@ -554,6 +636,7 @@ func (b *CallGraphBuilderImpl) analyzeGoFileContent(relPath string, content []by
 		}
 		return true
 	})
+	return ids, true
 }
 
 // analyzeVueFile analyzes Vue SFC files
@@ -702,8 +785,48 @@ func isJSKeyword(name string) bool {
 	return keywords[name]
 }
 
-// collectImportsFromProject walks project and collects imports
-func (b *CallGraphBuilderImpl) collectImportsFromProject(projectRoot string) error {
+// depGraphLanguageExtensions maps dependency graph language filters to file extensions
+var depGraphLanguageExtensions = map[string][]string{
+	"go":         {extGo},
+	"typescript": {".ts", ".tsx"},
+	"ts":         {".ts", ".tsx"},
+	"javascript": {".js", ".jsx"},
+	"js":         {".js", ".jsx"},
+	"vue":        {".vue"},
+}
+
+// resolveDepGraphExtensions converts a language filter into a set of extensions.
+// Returns nil (no filtering) for an empty filter or "all".
+func resolveDepGraphExtensions(languages []string) (map[string]bool, error) {
+	exts := make(map[string]bool)
+	for _, lang := range languages {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		switch {
+		case lang == "":
+			continue
+		case lang == "all":
+			return nil, nil
+		case strings.HasPrefix(lang, "."):
+			exts[lang] = true
+		default:
+			langExts, ok := depGraphLanguageExtensions[lang]
+			if !ok {
+				return nil, fmt.Errorf("unsupported dependency graph language: %s", lang)
+			}
+			for _, ext := range langExts {
+				exts[ext] = true
+			}
+		}
+	}
+	if len(exts) == 0 {
+		return nil, nil
+	}
+	return exts, nil
+}
+
+// collectImportsFromProject walks project and collects imports.
+// If exts is non-nil, only files with these extensions are considered.
+func (b *CallGraphBuilderImpl) collectImportsFromProject(projectRoot string, exts map[string]bool) error {
 	return filepath.Walk(projectRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
@@ -715,8 +838,22 @@ func (b *CallGraphBuilderImpl) collectImportsFromProject(projectRoot string) err
 			}
 			return nil
 		}
+		ext := filepath.Ext(path)
+		if info.Name() == goModFile && (exts == nil || exts[extGo]) {
+			b.collectGoModule(path, projectRoot)
+			return nil
+		}
+		if isTSConfigFile(info.Name()) && includesJSFamily(exts) {
+			if cfg := loadTSConfigPaths(path, projectRoot); cfg != nil {
+				b.tsConfigs = append(b.tsConfigs, cfg)
+			}
+			return nil
+		}
+		if exts != nil && !exts[ext] {
+			return nil
+		}
 		relPath, _ := filepath.Rel(projectRoot, path)
-		switch filepath.Ext(path) {
+		switch ext {
 		case extGo:
 			b.collectGoImports(path, relPath)
 		case ".ts", ".tsx", ".js", ".jsx", ".vue":
@@ -726,6 +863,48 @@ func (b *CallGraphBuilderImpl) collectImportsFromProject(projectRoot string) err
 	})
 }
 
+// includesJSFamily reports whether an extension filter admits JS/TS sources
+func includesJSFamily(exts map[string]bool) bool {
+	if exts == nil {
+		return true
+	}
+	for _, ext := range []string{".ts", ".tsx", ".js", ".jsx", ".vue"} {
+		if exts[ext] {
+			return true
+		}
+	}
+	return false
+}
+
+// collectGoModule records the module path declared in a go.mod file
+func (b *CallGraphBuilderImpl) collectGoModule(path, projectRoot string) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	modulePath := parseGoModulePath(string(content))
+	if modulePath == "" {
+		return
+	}
+	moduleDir, _ := filepath.Rel(projectRoot, filepath.Dir(path))
+	b.goModules[moduleDir] = modulePath
+}
+
+// parseGoModulePath extracts the module path from go.mod content
+func parseGoModulePath(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "module") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
 // ensureDepNode ensures a dependency node exists
 func (b *CallGraphBuilderImpl) ensureDepNode(filePath string) {
 	if _, exists := b.depGraph.Nodes[filePath]; !exists {
@@ -737,37 +916,102 @@ func (b *CallGraphBuilderImpl) ensureDepNode(filePath string) {
 	}
 }
 
-// buildDepEdges builds dependency edges from collected imports
-func (b *CallGraphBuilderImpl) buildDepEdges(projectRoot string) {
+// ensurePackageDepNode ensures a package (directory) dependency node exists
+func (b *CallGraphBuilderImpl) ensurePackageDepNode(pkgDir string) {
+	if _, exists := b.depGraph.Nodes[pkgDir]; !exists {
+		b.depGraph.Nodes[pkgDir] = &analysis.DependencyNode{
+			ID: pkgDir, Name: filepath.Base(pkgDir), Type: "package",
+			Package:      pkgDir,
+			Dependencies: make([]string, 0), Dependents: make([]string, 0),
+		}
+	}
+}
+
+// addDepEdge adds a dependency edge and links both nodes
+func (b *CallGraphBuilderImpl) addDepEdge(from, to string, imp importInfo) {
+	b.depGraph.Edges = append(b.depGraph.Edges, analysis.DependencyEdge{
+		From: from, To: to, ImportPath: imp.path, Line: imp.line,
+	})
+	b.depGraph.Nodes[from].Dependencies = append(b.depGraph.Nodes[from].Dependencies, to)
+	b.depGraph.Nodes[to].Dependents = append(b.depGraph.Nodes[to].Dependents, from)
+}
+
+// addGoPackageEdges links a Go file to the imported package and, for non-test
+// files, the file's own package to it, so import cycles show up between packages.
+func (b *CallGraphBuilderImpl) addGoPackageEdges(filePath, targetPkg string, imp importInfo) {
+	b.ensurePackageDepNode(targetPkg)
+	b.addDepEdge(filePath, targetPkg, imp)
+
+	fromPkg := filepath.Dir(filePath)
+	if fromPkg == targetPkg || strings.HasSuffix(filePath, "_test.go") {
+		return
+	}
+	b.ensurePackageDepNode(fromPkg)
+	for _, dep := range b.depGraph.Nodes[fromPkg].Dependencies {
+		if dep == targetPkg {
+			return
+		}
+	}
+	b.addDepEdge(fromPkg, targetPkg, imp)
+}
+
+// buildDepEdges builds dependency edges from collected imports.
+// Targets outside of exts (when non-nil) are dropped; imports that don't
+// resolve to a project file link to external module nodes if includeExternal.
+func (b *CallGraphBuilderImpl) buildDepEdges(projectRoot string, exts map[string]bool, includeExternal bool) {
 	for filePath, imports := range b.fileImports {
 		b.ensureDepNode(filePath)
 		for _, imp := range imports {
 			targetPath := b.resolveImportPath(filePath, imp.path, projectRoot)
 			if targetPath == "" {
+				if includeExternal {
+					b.addExternalDepEdge(filePath, imp)
+				}
+				continue
+			}
+			if filepath.Ext(filePath) == extGo {
+				b.addGoPackageEdges(filePath, targetPath, imp)
+				continue
+			}
+			if exts != nil && !exts[filepath.Ext(targetPath)] {
 				continue
 			}
 			b.ensureDepNode(targetPath)
-			b.depGraph.Edges = append(b.depGraph.Edges, analysis.DependencyEdge{
-				From: filePath, To: targetPath, ImportPath: imp.path, Line: imp.line,
-			})
-			b.depGraph.Nodes[filePath].Dependencies = append(b.depGraph.Nodes[filePath].Dependencies, targetPath)
-			b.depGraph.Nodes[targetPath].Dependents = append(b.depGraph.Nodes[targetPath].Dependents, filePath)
+			b.addDepEdge(filePath, targetPath, imp)
 		}
 	}
 }
 
-// BuildDependencyGraph builds file/package dependency graph
-func (b *CallGraphBuilderImpl) BuildDependencyGraph(projectRoot string) (*analysis.DependencyGraph, error) {
+// BuildDependencyGraph builds file/package dependency graph.
+// languages optionally restricts the graph to one module system, e.g. "go",
+// "typescript", "javascript", "vue" or raw extensions like ".go".
+// Without a filter (or with "all") every supported language is combined.
+func (b *CallGraphBuilderImpl) BuildDependencyGraph(projectRoot string, languages ...string) (*analysis.DependencyGraph, error) {
+	return b.BuildDependencyGraphWithOptions(projectRoot, analysis.DependencyGraphOptions{Languages: languages})
+}
+
+// BuildDependencyGraphWithOptions builds the dependency graph like
+// BuildDependencyGraph, optionally with external modules as terminal nodes
+// (see addExternalDepEdge)
+func (b *CallGraphBuilderImpl) BuildDependencyGraphWithOptions(projectRoot string, opts analysis.DependencyGraphOptions) (*analysis.DependencyGraph, error) {
+	exts, err := resolveDepGraphExtensions(opts.Languages)
+	if err != nil {
+		return nil, err
+	}
+
 	b.depGraph = &analysis.DependencyGraph{
 		Nodes: make(map[string]*analysis.DependencyNode),
 		Edges: make([]analysis.DependencyEdge, 0),
 	}
 	b.fileImports = make(map[string][]importInfo)
+	b.goModules = make(map[string]string)
+	b.tsConfigs = nil
+	b.depRoot = projectRoot
 
-	if err := b.collectImportsFromProject(projectRoot); err != nil {
+	if err := b.collectImportsFromProject(projectRoot, exts); err != nil {
 		return nil, err
 	}
-	b.buildDepEdges(projectRoot)
+	b.buildDepEdges(projectRoot, exts, opts.IncludeExternal)
 
 	return b.depGraph, nil
 }
@@ -827,7 +1071,24 @@ func (b *CallGraphBuilderImpl) collectJSImports(path, relPath string) {
 	}
 }
 
+// resolveImportPath resolves an import to a project-relative path.
+// Go imports resolve to the package directory, other imports to a file.
 func (b *CallGraphBuilderImpl) resolveImportPath(fromFile, importPath, projectRoot string) string {
+	if filepath.Ext(fromFile) == extGo {
+		return b.resolveGoImportPath(importPath, projectRoot)
+	}
+
+	// Aliases and baseUrl from the nearest tsconfig/jsconfig take precedence
+	if !strings.HasPrefix(importPath, ".") {
+		if cfg := nearestTSConfig(b.tsConfigs, fromFile); cfg != nil {
+			for _, candidate := range cfg.candidates(importPath) {
+				if resolved := resolveJSFile(filepath.FromSlash(candidate), projectRoot); resolved != "" {
+					return resolved
+				}
+			}
+		}
+	}
+
 	// Skip external packages
 	if !strings.HasPrefix(importPath, ".") && !strings.HasPrefix(importPath, "@/") && !strings.HasPrefix(importPath, "~/") {
 		return ""
@@ -835,7 +1096,7 @@ func (b *CallGraphBuilderImpl) resolveImportPath(fromFile, importPath, projectRo
 
 	fromDir := filepath.Dir(fromFile)
 
-	// Handle alias imports (@/, ~/)
+	// Fallback for conventional aliases (@/, ~/) without tsconfig paths
 	if strings.HasPrefix(importPath, "@/") {
 		importPath = strings.TrimPrefix(importPath, "@/")
 		fromDir = "src" // Common convention
@@ -845,15 +1106,19 @@ func (b *CallGraphBuilderImpl) resolveImportPath(fromFile, importPath, projectRo
 	}
 
 	// Resolve relative path
-	resolved := filepath.Join(fromDir, importPath)
-	resolved = filepath.Clean(resolved)
+	return resolveJSFile(filepath.Join(fromDir, importPath), projectRoot)
+}
+
+// resolveJSFile finds the file a JS/TS module path refers to by trying common extensions
+func resolveJSFile(modulePath, projectRoot string) string {
+	resolved := filepath.Clean(modulePath)
 
 	// Try common extensions
 	extensions := []string{"", ".ts", ".tsx", ".js", ".jsx", ".vue", "/index.ts", "/index.js"}
 	for _, ext := range extensions {
 		candidate := resolved + ext
 		fullPath := filepath.Join(projectRoot, candidate)
-		if _, err := os.Stat(fullPath); err == nil {
+		if info, err := os.Stat(fullPath); err == nil && !info.IsDir() {
 			return candidate
 		}
 	}
@@ -861,6 +1126,32 @@ func (b *CallGraphBuilderImpl) resolveImportPath(fromFile, importPath, projectRo
 	return ""
 }
 
+// resolveGoImportPath resolves a same-module Go import path to its package
+// directory using the module paths read from go.mod files.
+// Returns "" for standard library and third-party packages.
+func (b *CallGraphBuilderImpl) resolveGoImportPath(importPath, projectRoot string) string {
+	bestDir, bestModule := "", ""
+	for moduleDir, modulePath := range b.goModules {
+		if importPath != modulePath && !strings.HasPrefix(importPath, modulePath+"/") {
+			continue
+		}
+		// Prefer the most specific (nested) module
+		if len(modulePath) > len(bestModule) {
+			bestDir, bestModule = moduleDir, modulePath
+		}
+	}
+	if bestModule == "" {
+		return ""
+	}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(importPath, bestModule), "/")
+	pkgDir := filepath.Clean(filepath.Join(bestDir, filepath.FromSlash(rest)))
+	if info, err := os.Stat(filepath.Join(projectRoot, pkgDir)); err != nil || !info.IsDir() {
+		return ""
+	}
+	return pkgDir
+}
+
 // cycleDFSState holds state for cycle detection DFS
 type cycleDFSState struct {
 	visited  map[string]bool
@@ -870,7 +1161,7 @@ type cycleDFSState struct {
 }
 
 // extractCycle extracts a cycle from the current path
-func (s *cycleDFSState) extractCycle(dep string) {
+func (s *cycleDFSState) extractCycle(dep, cycleType string) {
 	cycleStart := -1
 	for i, p := range s.path {
 		if p == dep {
@@ -882,7 +1173,7 @@ func (s *cycleDFSState) extractCycle(dep string) {
 		cycle := make([]string, len(s.path)-cycleStart+1)
 		copy(cycle, s.path[cycleStart:])
 		cycle[len(cycle)-1] = dep
-		s.cycles = append(s.cycles, analysis.CyclicDependency{Cycle: cycle, Type: "file"})
+		s.cycles = append(s.cycles, analysis.CyclicDependency{Cycle: cycle, Type: cycleType})
 	}
 }
 
@@ -899,7 +1190,7 @@ func (b *CallGraphBuilderImpl) dfsForCycles(node string, state *cycleDFSState) b
 					return true
 				}
 			} else if state.recStack[dep] {
-				state.extractCycle(dep)
+				state.extractCycle(dep, b.depGraph.Nodes[dep].Type)
 				return true
 			}
 		}
@@ -1006,7 +1297,17 @@ func (b *CallGraphBuilderImpl) ExportMermaid(maxNodes int) string {
 	return sb.String()
 }
 
-// ExportDependencyMermaid exports the dependency graph as Mermaid diagram
+// DependencyMermaid builds the dependency graph of the project with opts and
+// exports it as a Mermaid diagram of up to maxNodes nodes
+func (b *CallGraphBuilderImpl) DependencyMermaid(projectRoot string, opts analysis.DependencyGraphOptions, maxNodes int) (string, error) {
+	if _, err := b.BuildDependencyGraphWithOptions(projectRoot, opts); err != nil {
+		return "", err
+	}
+	return b.ExportDependencyMermaid(maxNodes), nil
+}
+
+// ExportDependencyMermaid exports the last built dependency graph as Mermaid
+// diagram. External modules are drawn as dashed stadium-shaped nodes.
 func (b *CallGraphBuilderImpl) ExportDependencyMermaid(maxNodes int) string {
 	var sb strings.Builder
 	sb.WriteString("graph TD\n")
@@ -1019,6 +1320,7 @@ func (b *CallGraphBuilderImpl) ExportDependencyMermaid(maxNodes int) string {
 	sort.Strings(nodeIDs)
 
 	nodeMap := make(map[string]string)
+	hasExternal := false
 	for _, id := range nodeIDs {
 		if nodeCount >= maxNodes {
 			break
@@ -1026,9 +1328,17 @@ func (b *CallGraphBuilderImpl) ExportDependencyMermaid(maxNodes int) string {
 		node := b.depGraph.Nodes[id]
 		safeID := fmt.Sprintf("F%d", nodeCount)
 		nodeMap[id] = safeID
-		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", safeID, node.Name))
+		if node.Type == depNodeExternal {
+			hasExternal = true
+			sb.WriteString(fmt.Sprintf("    %s([\"%s\"]):::%s\n", safeID, node.Name, depNodeExternal))
+		} else {
+			sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", safeID, node.Name))
+		}
 		nodeCount++
 	}
+	if hasExternal {
+		sb.WriteString("    classDef external fill:#f4f4f4,stroke:#999,stroke-dasharray:4 3\n")
+	}
 
 	for _, edge := range b.depGraph.Edges {
 		fromSafe, fromOK := nodeMap[edge.From]
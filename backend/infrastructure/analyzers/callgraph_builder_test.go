@@ -1,9 +1,12 @@
 package analyzers
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"shotgun_code/domain/analysis"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -164,6 +167,190 @@ func main() {
 	}
 }
 
+func TestCallGraphBuilder_BuildDependencyGraph_LanguageFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "main.go", "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n")
+	writeTestFile(t, tmpDir, "src/a.ts", "import { b } from './b'\n")
+	writeTestFile(t, tmpDir, "src/b.ts", "export const b = 1\n")
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+
+	tsGraph, err := builder.BuildDependencyGraph(tmpDir, "typescript")
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+	if len(tsGraph.Edges) != 1 {
+		t.Errorf("expected 1 TS edge, got %d", len(tsGraph.Edges))
+	}
+	if _, ok := tsGraph.Nodes["main.go"]; ok {
+		t.Error("TS-only graph should not contain Go files")
+	}
+
+	goGraph, err := builder.BuildDependencyGraph(tmpDir, "go")
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+	if len(goGraph.Edges) != 0 {
+		t.Errorf("expected no edges in Go-only graph, got %d", len(goGraph.Edges))
+	}
+	if _, ok := goGraph.Nodes[filepath.Join("src", "a.ts")]; ok {
+		t.Error("Go-only graph should not contain TS files")
+	}
+
+	combined, err := builder.BuildDependencyGraph(tmpDir, "all")
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+	if _, ok := combined.Nodes["main.go"]; !ok {
+		t.Error("combined graph should contain Go files")
+	}
+
+	if _, err := builder.BuildDependencyGraph(tmpDir, "cobol"); err == nil {
+		t.Error("expected error for unsupported language")
+	}
+}
+
+func TestCallGraphBuilder_GoModuleImports(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, tmpDir, "a/a.go", "package a\n\nimport (\n\t\"fmt\"\n\t\"example.com/app/b\"\n)\n\nvar _ = b.B\nvar _ = fmt.Sprint\n")
+	writeTestFile(t, tmpDir, "b/b.go", "package b\n\nimport \"example.com/app/a\"\n\nvar B = a.A\n")
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+
+	depGraph, err := builder.BuildDependencyGraph(tmpDir, "go")
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+
+	aFile := filepath.Join("a", "a.go")
+	if node, ok := depGraph.Nodes[aFile]; !ok || len(node.Dependencies) != 1 || node.Dependencies[0] != "b" {
+		t.Errorf("expected %s to depend only on package b, got %+v", aFile, node)
+	}
+	if node, ok := depGraph.Nodes["b"]; !ok || node.Type != "package" {
+		t.Errorf("expected package node for b, got %+v", node)
+	}
+
+	cycles, err := builder.FindCyclicDependencies(tmpDir)
+	if err != nil {
+		t.Fatalf("FindCyclicDependencies failed: %v", err)
+	}
+	if len(cycles) == 0 {
+		t.Fatal("expected import cycle between packages a and b")
+	}
+	if cycles[0].Type != "package" {
+		t.Errorf("expected package cycle, got %s", cycles[0].Type)
+	}
+}
+
+func TestCallGraphBuilder_SuggestCycleBreak(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, tmpDir, "store/store.go", "package store\n\nimport \"example.com/app/audit\"\n\n"+
+		"type Item struct{}\n\nfunc Save(i Item) { audit.Record(\"save\") }\n\nfunc Load() Item { audit.Record(\"load\"); return Item{} }\n")
+	writeTestFile(t, tmpDir, "audit/audit.go", "package audit\n\nimport \"example.com/app/store\"\n\n"+
+		"var last store.Item\n\nfunc Record(event string) { last = store.Load(); store.Save(last) }\n")
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	cycles, err := builder.FindCyclicDependencies(tmpDir)
+	if err != nil || len(cycles) == 0 {
+		t.Fatalf("expected an import cycle, got %v (err %v)", cycles, err)
+	}
+
+	suggestion, err := builder.SuggestCycleBreak(cycles[0])
+	if err != nil {
+		t.Fatalf("SuggestCycleBreak failed: %v", err)
+	}
+	if suggestion.Edge.From != "store" || suggestion.Edge.To != "audit" {
+		t.Fatalf("expected to cut store -> audit, got %s -> %s", suggestion.Edge.From, suggestion.Edge.To)
+	}
+	if len(suggestion.Edge.Symbols) != 1 || suggestion.Edge.Symbols[0] != "Record" || suggestion.Edge.Usages != 2 {
+		t.Errorf("unexpected usage of the cut edge: %+v", suggestion.Edge)
+	}
+	if suggestion.Edge.ImportPath != "example.com/app/audit" {
+		t.Errorf("expected import path of audit, got %q", suggestion.Edge.ImportPath)
+	}
+	if suggestion.NewPackage != "auditiface" {
+		t.Errorf("expected new package auditiface, got %q", suggestion.NewPackage)
+	}
+	if len(suggestion.Edges) != 2 || !strings.Contains(suggestion.Explanation, "Record") {
+		t.Errorf("unexpected suggestion: %+v", suggestion)
+	}
+
+	if _, err := builder.SuggestCycleBreak(analysis.CyclicDependency{Cycle: []string{"missing", "store"}}); err == nil {
+		t.Error("expected error for node outside the dependency graph")
+	}
+}
+
+func TestCallGraphBuilder_SuggestCycleBreakInProjectConcurrently(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, tmpDir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, tmpDir, "store/store.go", "package store\n\nimport \"example.com/app/audit\"\n\nfunc Save() { audit.Record() }\n")
+	writeTestFile(t, tmpDir, "audit/audit.go", "package audit\n\nimport \"example.com/app/store\"\n\nfunc Record() {}\n\nvar _ = store.Save\n")
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	cycle := analysis.CyclicDependency{Cycle: []string{"store", "audit"}}
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each call rebuilds the graph the others read
+			_, err := builder.SuggestCycleBreakInProject(tmpDir, cycle)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("SuggestCycleBreakInProject failed: %v", err)
+		}
+	}
+}
+
+func TestCallGraphBuilder_SuggestCycleBreakJS(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "src/api.ts", "import { formatDate, formatSize, type Unit } from './format'\n\n"+
+		"export function fetchItem(u: Unit) { return formatDate(formatSize(u)) }\n")
+	writeTestFile(t, tmpDir, "src/format.ts", "import * as api from './api'\n\n"+
+		"export type Unit = string\nexport function formatDate(v: string) { return v }\n"+
+		"export function formatSize(v: Unit) { return api.fetchItem ? v : v }\n")
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	cycles, err := builder.FindCyclicDependencies(tmpDir)
+	if err != nil || len(cycles) == 0 {
+		t.Fatalf("expected an import cycle, got %v (err %v)", cycles, err)
+	}
+
+	suggestion, err := builder.SuggestCycleBreak(cycles[0])
+	if err != nil {
+		t.Fatalf("SuggestCycleBreak failed: %v", err)
+	}
+	format := filepath.Join("src", "format.ts")
+	if suggestion.Edge.From != format || len(suggestion.Edge.Symbols) != 1 || suggestion.Edge.Symbols[0] != "fetchItem" {
+		t.Fatalf("expected to cut %s -> api.ts using fetchItem, got %+v", format, suggestion.Edge)
+	}
+	if suggestion.NewPackage != filepath.Join("src", "api.types.ts") {
+		t.Errorf("unexpected extraction target %q", suggestion.NewPackage)
+	}
+}
+
+func TestParseGoModulePath(t *testing.T) {
+	content := "// comment\nmodule shotgun_code\n\ngo 1.24.0\n"
+	if got := parseGoModulePath(content); got != "shotgun_code" {
+		t.Errorf("expected shotgun_code, got %q", got)
+	}
+	if got := parseGoModulePath("go 1.22\n"); got != "" {
+		t.Errorf("expected empty module path, got %q", got)
+	}
+}
+
 func TestCallGraphBuilder_GetImpact(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -228,3 +415,59 @@ func writeTestFile(t *testing.T, base, path, content string) {
 		t.Fatalf("failed to create file: %v", err)
 	}
 }
+
+func TestCallGraphBuilder_BuildForFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, tmpDir, "main.go", `package main
+
+func main() {
+	helper()
+}
+
+func helper() {}
+`)
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	if _, err := builder.Build(tmpDir); err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if builder.graph.Nodes["main.helper"] == nil {
+		t.Fatalf("helper not in graph: %v", builder.graph.Nodes)
+	}
+
+	// helper is renamed; main keeps its edges until the next full build
+	err := builder.BuildForFile(context.Background(), "main.go", []byte(`package main
+
+func main() {
+	assist()
+}
+
+func assist() {}
+`))
+	if err != nil {
+		t.Fatalf("BuildForFile failed: %v", err)
+	}
+	if builder.graph.Nodes["main.helper"] != nil {
+		t.Error("stale node kept")
+	}
+	if builder.graph.Nodes["main.assist"] == nil {
+		t.Error("new node missing")
+	}
+	for _, e := range builder.graph.Edges {
+		if e.To == "main.helper" {
+			t.Errorf("edge to removed node kept: %+v", e)
+		}
+	}
+
+	// Unparseable content keeps the previous nodes
+	_ = builder.BuildForFile(context.Background(), "main.go", []byte("package main\nfunc broken( {"))
+	if builder.graph.Nodes["main.assist"] == nil {
+		t.Error("nodes dropped for unparseable file")
+	}
+
+	// Deleted file drops all its nodes
+	_ = builder.BuildForFile(context.Background(), "main.go", nil)
+	if len(builder.graph.Nodes) != 0 {
+		t.Errorf("expected empty graph, got %v", builder.graph.Nodes)
+	}
+}
@@ -0,0 +1,247 @@
+package analyzers
+
+import (
+	"context"
+	"go/ast"
+	"go/types"
+	"shotgun_code/domain/analysis"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Call types produced by precise Go analysis
+const (
+	callTypeDirect    = "direct"
+	callTypeInterface = "interface" // call through an interface method
+	callTypeDynamic   = "dynamic"   // possible target of an interface call
+)
+
+// goPreciseGraph maps type-checked functions to call graph node IDs
+type goPreciseGraph struct {
+	nodeIDs         map[*types.Func]string
+	implementations map[*types.Func][]*types.Func // interface method -> concrete methods
+}
+
+// SetPreciseGoAnalysis enables building the Go part of the call graph from
+// go/packages type information. Interface calls are resolved to their
+// implementations; if the module doesn't type-check, Build falls back to
+// syntactic analysis.
+func (b *CallGraphBuilderImpl) SetPreciseGoAnalysis(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.preciseGo = enabled
+}
+
+// buildGoCallGraphPrecise adds nodes and edges for all Go packages of the module
+func (b *CallGraphBuilderImpl) buildGoCallGraphPrecise(projectRoot string) error {
+	pkgs, err := loadGoPackages(context.Background(), projectRoot)
+	if err != nil {
+		return err
+	}
+
+	g := &goPreciseGraph{
+		nodeIDs:         make(map[*types.Func]string),
+		implementations: findGoImplementations(pkgs),
+	}
+	goPackageFiles(pkgs, projectRoot, func(pkg *packages.Package, file *ast.File, relPath string) {
+		b.collectPreciseGoNodes(g, pkg, file, relPath)
+	})
+	goPackageFiles(pkgs, projectRoot, func(pkg *packages.Package, file *ast.File, relPath string) {
+		b.collectPreciseGoCalls(g, pkg, file, relPath)
+	})
+	return nil
+}
+
+// preciseGoNodeID returns the node ID of a function; methods are qualified with their receiver
+func preciseGoNodeID(pkgName string, fn *types.Func) string {
+	if recv := goRecvTypeName(fn); recv != "" {
+		return pkgName + "." + recv + "." + fn.Name()
+	}
+	return pkgName + "." + fn.Name()
+}
+
+// goRecvTypeName returns the receiver type name of a method (or its interface), "" for functions
+func goRecvTypeName(fn *types.Func) string {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return ""
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	if named, ok := recv.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+// collectPreciseGoNodes adds nodes for functions, methods and interface methods of a file
+func (b *CallGraphBuilderImpl) collectPreciseGoNodes(g *goPreciseGraph, pkg *packages.Package, file *ast.File, relPath string) {
+	addNode := func(ident *ast.Ident, signature string) {
+		fn, ok := pkg.TypesInfo.Defs[ident].(*types.Func)
+		if !ok {
+			return
+		}
+		nodeID := preciseGoNodeID(pkg.Name, fn)
+		g.nodeIDs[fn] = nodeID
+		b.graph.Nodes[nodeID] = &analysis.CallNode{
+			ID: nodeID, Name: fn.Name(), FilePath: relPath, Line: pkg.Fset.Position(ident.Pos()).Line,
+			Package: pkg.Name, Signature: signature,
+			Callers: make([]string, 0), Callees: make([]string, 0),
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			addNode(node.Name, types.ObjectString(pkg.TypesInfo.Defs[node.Name], types.RelativeTo(pkg.Types)))
+		case *ast.InterfaceType:
+			for _, method := range node.Methods.List {
+				for _, name := range method.Names {
+					if fn, ok := pkg.TypesInfo.Defs[name].(*types.Func); ok {
+						addNode(name, "interface "+types.ObjectString(fn, types.RelativeTo(pkg.Types)))
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// collectPreciseGoCalls adds call edges for all function bodies of a file
+func (b *CallGraphBuilderImpl) collectPreciseGoCalls(g *goPreciseGraph, pkg *packages.Package, file *ast.File, relPath string) {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		caller, ok := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		callerID := g.nodeIDs[caller]
+
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			callee, viaInterface := goStaticCallee(pkg.TypesInfo, call)
+			if callee == nil {
+				return true
+			}
+			calleeID, ok := g.nodeIDs[callee]
+			if !ok {
+				return true // not part of the project
+			}
+			line := pkg.Fset.Position(call.Pos()).Line
+			if !viaInterface {
+				b.addTypedCallEdge(callerID, calleeID, relPath, line, callTypeDirect)
+				return true
+			}
+			b.addTypedCallEdge(callerID, calleeID, relPath, line, callTypeInterface)
+			for _, impl := range g.implementations[callee] {
+				if implID, ok := g.nodeIDs[impl]; ok {
+					b.addTypedCallEdge(callerID, implID, relPath, line, callTypeDynamic)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// addTypedCallEdge adds a call edge of the given type and updates caller/callee lists
+func (b *CallGraphBuilderImpl) addTypedCallEdge(callerID, calleeID, relPath string, line int, callType string) {
+	b.addCallEdge(callerID, calleeID, relPath, line)
+	b.graph.Edges[len(b.graph.Edges)-1].CallType = callType
+}
+
+// goStaticCallee returns the function a call refers to and whether it is
+// dispatched through an interface. Calls of function values return nil.
+func goStaticCallee(info *types.Info, call *ast.CallExpr) (*types.Func, bool) {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr: // explicit instantiation f[T]()
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	switch f := fun.(type) {
+	case *ast.Ident:
+		fn, _ := info.Uses[f].(*types.Func)
+		return goFuncOrigin(fn), false
+	case *ast.SelectorExpr:
+		if sel, ok := info.Selections[f]; ok {
+			fn, _ := sel.Obj().(*types.Func)
+			return goFuncOrigin(fn), fn != nil && types.IsInterface(sel.Recv())
+		}
+		fn, _ := info.Uses[f.Sel].(*types.Func) // qualified call pkg.Func
+		return goFuncOrigin(fn), false
+	}
+	return nil, false
+}
+
+// goFuncOrigin maps instantiated generic functions and methods to their declaration
+func goFuncOrigin(fn *types.Func) *types.Func {
+	if fn == nil {
+		return nil
+	}
+	return fn.Origin()
+}
+
+// collectGoNamedTypes splits the non-generic named types declared in the
+// packages into interfaces (with methods) and concrete types
+func collectGoNamedTypes(pkgs []*packages.Package) (interfaces, concrete []*types.Named) {
+	for _, pkg := range pkgs {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() {
+				continue
+			}
+			named, ok := typeName.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 {
+				continue
+			}
+			if iface, ok := named.Underlying().(*types.Interface); ok {
+				if iface.IsMethodSet() && iface.NumMethods() > 0 {
+					interfaces = append(interfaces, named)
+				}
+				continue
+			}
+			concrete = append(concrete, named)
+		}
+	}
+	return interfaces, concrete
+}
+
+// findGoImplementations maps each interface method declared in the packages to
+// the methods of concrete types that implement the interface.
+func findGoImplementations(pkgs []*packages.Package) map[*types.Func][]*types.Func {
+	interfaces, concrete := collectGoNamedTypes(pkgs)
+
+	result := make(map[*types.Func][]*types.Func)
+	for _, named := range interfaces {
+		iface := named.Underlying().(*types.Interface)
+		for _, typ := range concrete {
+			ptr := types.NewPointer(typ)
+			if !types.Implements(ptr, iface) {
+				continue
+			}
+			methods := types.NewMethodSet(ptr)
+			for i := 0; i < iface.NumMethods(); i++ {
+				method := iface.Method(i)
+				sel := methods.Lookup(method.Pkg(), method.Name())
+				if sel == nil {
+					continue
+				}
+				if impl, ok := sel.Obj().(*types.Func); ok {
+					result[method] = append(result[method], impl)
+				}
+			}
+		}
+	}
+	return result
+}
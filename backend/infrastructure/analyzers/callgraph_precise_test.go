@@ -0,0 +1,121 @@
+package analyzers
+
+import (
+	"context"
+	"testing"
+)
+
+func writePreciseTestModule(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "go.mod", "module example.com/app\n\ngo 1.21\n")
+	writeTestFile(t, tmpDir, "store/store.go", `package store
+
+type Store interface {
+	Save(key string) error
+}
+
+type MemoryStore struct{}
+
+func (m *MemoryStore) Save(key string) error { return nil }
+
+type DiskStore struct{}
+
+func (d DiskStore) Save(key string) error { return nil }
+`)
+	writeTestFile(t, tmpDir, "app/app.go", `package app
+
+import "example.com/app/store"
+
+func Persist(s store.Store) error {
+	return s.Save("key")
+}
+
+func New() store.Store {
+	return &store.MemoryStore{}
+}
+`)
+	return tmpDir
+}
+
+func TestCallGraphBuilder_PreciseGoInterfaceCalls(t *testing.T) {
+	tmpDir := writePreciseTestModule(t)
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	builder.SetPreciseGoAnalysis(true)
+	graph, err := builder.Build(tmpDir)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	edgeTypes := make(map[string]string)
+	for _, edge := range graph.Edges {
+		if edge.From == "app.Persist" {
+			edgeTypes[edge.To] = edge.CallType
+		}
+	}
+
+	expected := map[string]string{
+		"store.Store.Save":       callTypeInterface,
+		"store.MemoryStore.Save": callTypeDynamic,
+		"store.DiskStore.Save":   callTypeDynamic,
+	}
+	for to, callType := range expected {
+		if edgeTypes[to] != callType {
+			t.Errorf("expected %s edge app.Persist -> %s, got edges %v", callType, to, edgeTypes)
+		}
+	}
+
+	node, ok := graph.Nodes["store.MemoryStore.Save"]
+	if !ok || len(node.Callers) == 0 || node.Callers[0] != "app.Persist" {
+		t.Errorf("expected app.Persist as caller of MemoryStore.Save, got %+v", node)
+	}
+}
+
+func TestCallGraphBuilder_PreciseGoFallsBackOnTypeErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, tmpDir, "go.mod", "module example.com/broken\n\ngo 1.21\n")
+	writeTestFile(t, tmpDir, "main.go", `package main
+
+func main() {
+	helper(undefinedValue)
+}
+
+func helper(v int) {}
+`)
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	builder.SetPreciseGoAnalysis(true)
+	graph, err := builder.Build(tmpDir)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if _, ok := graph.Nodes["main.helper"]; !ok {
+		t.Error("expected syntactic analysis to produce main.helper")
+	}
+}
+
+func TestReferenceFinder_PreciseGoCrossPackage(t *testing.T) {
+	tmpDir := writePreciseTestModule(t)
+
+	finder := NewReferenceFinder(NewAnalyzerRegistry())
+	finder.SetPreciseGoAnalysis(true)
+	refs, err := finder.FindReferences(context.Background(), tmpDir, "MemoryStore", "")
+	if err != nil {
+		t.Fatalf("FindReferences failed: %v", err)
+	}
+
+	var foundUse bool
+	for _, ref := range refs {
+		if ref.FilePath == "app/app.go" && ref.Line == 10 {
+			foundUse = true
+			if ref.Confidence != goConfidenceResolved {
+				t.Errorf("expected resolved confidence for cross-package use, got %v", ref.Confidence)
+			}
+		}
+	}
+	if !foundUse {
+		t.Errorf("expected reference in app/app.go, got %+v", refs)
+	}
+}
@@ -0,0 +1,113 @@
+package analyzers
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileComplexity summarizes the cyclomatic complexity of the functions in a file
+type FileComplexity struct {
+	Functions int     `json:"functions"`
+	Average   float64 `json:"average"`
+	Max       int     `json:"max"`
+	Estimated bool    `json:"estimated"` // true if computed with the line-based heuristic
+}
+
+// AnalyzeFileComplexity computes cyclomatic complexity for a file. Go files are
+// parsed and complexity is counted per function; other languages (and Go files
+// that don't parse) use a line-based estimate.
+func AnalyzeFileComplexity(filePath string, content []byte) FileComplexity {
+	if filepath.Ext(filePath) == extGo {
+		if complexity, ok := goFileComplexity(content); ok {
+			return complexity
+		}
+	}
+	return estimateFileComplexity(string(content))
+}
+
+// goFileComplexity counts 1 + branch points for every function and method
+func goFileComplexity(content []byte) (FileComplexity, bool) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", content, parser.SkipObjectResolution)
+	if err != nil {
+		return FileComplexity{}, false
+	}
+
+	var result FileComplexity
+	total := 0
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		complexity := goCyclomaticComplexity(fn.Body)
+		result.Functions++
+		total += complexity
+		result.Max = max(result.Max, complexity)
+	}
+	if result.Functions > 0 {
+		result.Average = float64(total) / float64(result.Functions)
+	}
+	return result, true
+}
+
+// goCyclomaticComplexity returns 1 + the number of branch points in a function
+// body. Function literals are counted as part of the enclosing function.
+func goCyclomaticComplexity(body *ast.BlockStmt) int {
+	complexity := 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if node.List != nil { // default is not a branch
+				complexity++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+var (
+	// complexityBranchPattern matches branch keywords and boolean operators in C-like and scripting languages
+	complexityBranchPattern = regexp.MustCompile(`\b(?:if|elif|for|foreach|while|case|catch|except|when)\b|&&|\|\||\band\b|\bor\b`)
+	// complexityFuncPattern matches common function declarations
+	complexityFuncPattern = regexp.MustCompile(`\b(?:func|function|def|fn|fun|sub)\b|=>|^\s*(?:public|private|protected|static|internal)\b[^=;]*\(`)
+)
+
+// estimateFileComplexity approximates complexity by counting branch keywords
+// per line and spreading them over the detected functions
+func estimateFileComplexity(content string) FileComplexity {
+	branches, functions := 0, 0
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		branches += len(complexityBranchPattern.FindAllStringIndex(trimmed, -1))
+		if complexityFuncPattern.MatchString(line) {
+			functions++
+		}
+	}
+
+	units := max(functions, 1)
+	average := 1 + float64(branches)/float64(units)
+	return FileComplexity{
+		Functions: functions,
+		Average:   average,
+		Max:       int(average + 0.5),
+		Estimated: true,
+	}
+}
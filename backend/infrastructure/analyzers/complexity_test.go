@@ -0,0 +1,55 @@
+package analyzers
+
+import "testing"
+
+func TestAnalyzeFileComplexity_Go(t *testing.T) {
+	src := `package p
+
+func simple() int { return 1 }
+
+func branchy(xs []int, ch chan int) int {
+	total := 0
+	for _, x := range xs {
+		if x > 0 && x < 10 || x == 42 {
+			total += x
+		}
+	}
+	switch total {
+	case 1, 2:
+		total++
+	case 3:
+	default:
+	}
+	select {
+	case v := <-ch:
+		total += v
+	default:
+	}
+	return total
+}
+`
+	got := AnalyzeFileComplexity("p.go", []byte(src))
+	// branchy: 1 + range + if + && + || + 2 cases + 1 comm case = 8
+	if got.Functions != 2 || got.Max != 8 || got.Average != 4.5 || got.Estimated {
+		t.Errorf("unexpected complexity %+v", got)
+	}
+}
+
+func TestAnalyzeFileComplexity_Estimated(t *testing.T) {
+	src := `def check(x):
+    if x > 0 and x < 10:
+        return True
+    for i in range(x):
+        pass
+    return False
+`
+	got := AnalyzeFileComplexity("check.py", []byte(src))
+	if !got.Estimated || got.Functions != 1 || got.Max != 4 {
+		t.Errorf("unexpected complexity %+v", got)
+	}
+
+	invalidGo := AnalyzeFileComplexity("broken.go", []byte("func broken( {\n\tif x {\n"))
+	if !invalidGo.Estimated {
+		t.Error("unparsable Go should fall back to the estimate")
+	}
+}
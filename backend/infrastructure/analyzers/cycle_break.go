@@ -0,0 +1,332 @@
+package analyzers
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"shotgun_code/domain/analysis"
+	"sort"
+	"strings"
+)
+
+var (
+	// jsImportClausePattern captures the clause and module of an ES import
+	jsImportClausePattern = regexp.MustCompile(`(?m)import\s+(?:type\s+)?([^'";]*?)\s+from\s+['"]([^'"]+)['"]`)
+	// jsWordPattern finds the identifiers counted as usages of imported bindings
+	jsWordPattern = regexp.MustCompile(`\w+`)
+)
+
+// SuggestCycleBreak picks the edge of a cycle whose target is used the least
+// by its importer: the fewer symbols cross the edge, the cheaper it is to move
+// them behind an interface. Usage is counted from the call graph and from
+// qualified references in the importing sources, so types and constants count
+// too. The cycle must come from FindCyclicDependencies on this builder.
+func (b *CallGraphBuilderImpl) SuggestCycleBreak(cycle analysis.CyclicDependency) (*analysis.CycleBreakSuggestion, error) {
+	// Build below replaces the call graph, so hold the write lock
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.suggestCycleBreak(cycle)
+}
+
+// SuggestCycleBreakInProject builds the dependency graph of projectRoot and
+// suggests a break like SuggestCycleBreak, holding b.mu for both so another
+// build can't replace the graph in between
+func (b *CallGraphBuilderImpl) SuggestCycleBreakInProject(projectRoot string, cycle analysis.CyclicDependency) (*analysis.CycleBreakSuggestion, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, err := b.BuildDependencyGraph(projectRoot); err != nil {
+		return nil, err
+	}
+	return b.suggestCycleBreak(cycle)
+}
+
+// suggestCycleBreak implements SuggestCycleBreak; the caller holds b.mu
+func (b *CallGraphBuilderImpl) suggestCycleBreak(cycle analysis.CyclicDependency) (*analysis.CycleBreakSuggestion, error) {
+	nodes := cycle.Cycle
+	if len(nodes) > 1 && nodes[0] != nodes[len(nodes)-1] {
+		nodes = append(append([]string{}, nodes...), nodes[0])
+	}
+	if len(nodes) < 3 {
+		return nil, fmt.Errorf("cycle must contain at least two nodes")
+	}
+	for _, id := range nodes {
+		if _, ok := b.depGraph.Nodes[id]; !ok {
+			return nil, fmt.Errorf("node %s is not in the dependency graph, run FindCyclicDependencies first", id)
+		}
+	}
+
+	if len(b.graph.Nodes) == 0 && b.depRoot != "" {
+		if _, err := b.Build(b.depRoot); err != nil {
+			return nil, fmt.Errorf("failed to build call graph: %w", err)
+		}
+	}
+
+	edges := make([]analysis.CycleEdgeUsage, 0, len(nodes)-1)
+	for i := 0; i+1 < len(nodes); i++ {
+		edges = append(edges, b.cycleEdgeUsage(nodes[i], nodes[i+1]))
+	}
+
+	best := 0
+	for i, edge := range edges {
+		if len(edge.Symbols) < len(edges[best].Symbols) ||
+			len(edge.Symbols) == len(edges[best].Symbols) && edge.Usages < edges[best].Usages {
+			best = i
+		}
+	}
+
+	suggestion := &analysis.CycleBreakSuggestion{
+		Cycle: nodes,
+		Edge:  edges[best],
+		Edges: edges,
+	}
+	suggestion.NewPackage = b.extractionTarget(edges[best].To)
+	suggestion.Explanation = cycleBreakExplanation(suggestion, b.depGraph.Nodes[edges[best].To].Type == "package")
+	return suggestion, nil
+}
+
+// cycleEdgeUsage collects the symbols of to referenced from from.
+// Each symbol counts the larger of its call graph and source reference tallies.
+func (b *CallGraphBuilderImpl) cycleEdgeUsage(from, to string) analysis.CycleEdgeUsage {
+	edge := analysis.CycleEdgeUsage{From: from, To: to}
+	for _, dep := range b.depGraph.Edges {
+		if dep.From == from && dep.To == to {
+			edge.ImportPath, edge.Line = dep.ImportPath, dep.Line
+			break
+		}
+	}
+
+	usages := b.callGraphUsages(from, to)
+	var source map[string]int
+	if b.depGraph.Nodes[from].Type == "package" {
+		source = b.goSelectorUsages(from, to)
+	} else {
+		source = b.jsImportUsages(from, to)
+	}
+	for symbol, n := range source {
+		usages[symbol] = max(usages[symbol], n)
+	}
+
+	for symbol, n := range usages {
+		edge.Symbols = append(edge.Symbols, symbol)
+		edge.Usages += n
+	}
+	sort.Strings(edge.Symbols)
+	return edge
+}
+
+// ownsFile reports whether a file belongs to a dependency node
+func (b *CallGraphBuilderImpl) ownsFile(nodeID, filePath string) bool {
+	if b.depGraph.Nodes[nodeID].Type == "package" {
+		return filepath.Dir(filePath) == nodeID && !strings.HasSuffix(filePath, "_test.go")
+	}
+	return filePath == nodeID
+}
+
+// callGraphUsages counts calls made from files of from into functions of to
+func (b *CallGraphBuilderImpl) callGraphUsages(from, to string) map[string]int {
+	usages := make(map[string]int)
+	for _, edge := range b.graph.Edges {
+		callee, ok := b.graph.Nodes[edge.To]
+		if !ok || !b.ownsFile(from, edge.FilePath) || !b.ownsFile(to, callee.FilePath) {
+			continue
+		}
+		symbol := callee.Name
+		if callee.Package != "" {
+			symbol = strings.TrimPrefix(callee.ID, callee.Package+".")
+		}
+		usages[symbol]++
+	}
+	return usages
+}
+
+// goSelectorUsages counts pkg.Symbol references to package to in the Go files of package from
+func (b *CallGraphBuilderImpl) goSelectorUsages(from, to string) map[string]int {
+	usages := make(map[string]int)
+	entries, err := os.ReadDir(filepath.Join(b.depRoot, from))
+	if err != nil {
+		return usages
+	}
+	targetName := goPackageName(filepath.Join(b.depRoot, to))
+
+	for _, entry := range entries {
+		relPath := filepath.Join(from, entry.Name())
+		if entry.IsDir() || filepath.Ext(relPath) != extGo || !b.ownsFile(from, relPath) {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(b.depRoot, relPath), nil, 0)
+		if err != nil {
+			continue
+		}
+
+		local := ""
+		for _, imp := range file.Imports {
+			if b.resolveGoImportPath(strings.Trim(imp.Path.Value, `"`), b.depRoot) != to {
+				continue
+			}
+			local = targetName
+			if imp.Name != nil {
+				local = imp.Name.Name
+			}
+		}
+		if local == "" || local == "_" || local == "." {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == local {
+					usages[sel.Sel.Name]++
+				}
+			}
+			return true
+		})
+	}
+	return usages
+}
+
+// goPackageName returns the package clause of the first non-test Go file in dir
+func goPackageName(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != extGo || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, name), nil, parser.PackageClauseOnly)
+		if err == nil {
+			return file.Name.Name
+		}
+	}
+	return filepath.Base(dir)
+}
+
+// jsImportUsages counts references to the bindings that file from imports from file to.
+// Imports without bindings (side effects, require) count as a single "*" usage.
+func (b *CallGraphBuilderImpl) jsImportUsages(from, to string) map[string]int {
+	usages := make(map[string]int)
+	content, err := os.ReadFile(filepath.Join(b.depRoot, from))
+	if err != nil {
+		return usages
+	}
+	text := string(content)
+	words, members := jsWordUsages(text)
+
+	for _, match := range jsImportClausePattern.FindAllStringSubmatch(text, -1) {
+		if b.resolveImportPath(from, match[2], b.depRoot) != to {
+			continue
+		}
+		for symbol, local := range jsImportBindings(match[1]) {
+			if strings.HasPrefix(symbol, "* as ") {
+				for member, n := range members[local] {
+					usages[member] += n
+				}
+				continue
+			}
+			// The import clause itself is not a usage
+			usages[symbol] += max(words[local]-1, 1)
+		}
+	}
+
+	if len(usages) == 0 {
+		usages["*"] = 1
+	}
+	return usages
+}
+
+// jsWordUsages counts the identifiers of a source and, for each identifier,
+// the members accessed on it as ident.member
+func jsWordUsages(text string) (map[string]int, map[string]map[string]int) {
+	words := make(map[string]int)
+	members := make(map[string]map[string]int)
+	spans := jsWordPattern.FindAllStringIndex(text, -1)
+	for i, span := range spans {
+		word := text[span[0]:span[1]]
+		words[word]++
+		if i+1 < len(spans) && spans[i+1][0] == span[1]+1 && text[span[1]] == '.' {
+			if members[word] == nil {
+				members[word] = make(map[string]int)
+			}
+			members[word][text[spans[i+1][0]:spans[i+1][1]]]++
+		}
+	}
+	return words, members
+}
+
+// jsImportBindings maps imported symbols to their local names.
+// Default imports are keyed "default", namespace imports "* as <name>".
+func jsImportBindings(clause string) map[string]string {
+	bindings := make(map[string]string)
+	named := ""
+	if start := strings.Index(clause, "{"); start >= 0 {
+		end := strings.LastIndex(clause, "}")
+		if end > start {
+			named = clause[start+1 : end]
+			clause = clause[:start] + clause[end+1:]
+		}
+	}
+
+	for _, spec := range strings.Split(named, ",") {
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(spec), "type "))
+		switch {
+		case len(fields) == 1:
+			bindings[fields[0]] = fields[0]
+		case len(fields) == 3 && fields[1] == "as":
+			bindings[fields[0]] = fields[2]
+		}
+	}
+
+	for _, part := range strings.Split(clause, ",") {
+		fields := strings.Fields(part)
+		switch {
+		case len(fields) == 1:
+			bindings["default"] = fields[0]
+		case len(fields) == 3 && fields[0] == "*" && fields[1] == "as":
+			bindings["* as "+fields[2]] = fields[2]
+		}
+	}
+	return bindings
+}
+
+// extractionTarget suggests a new package (Go) or module (JS/TS) for the symbols of node
+func (b *CallGraphBuilderImpl) extractionTarget(nodeID string) string {
+	if b.depGraph.Nodes[nodeID].Type == "package" {
+		return filepath.Join(filepath.Dir(nodeID), filepath.Base(nodeID)+"iface")
+	}
+	ext := filepath.Ext(nodeID)
+	if ext == ".vue" {
+		ext = ".ts"
+	}
+	stem := strings.TrimSuffix(filepath.Base(nodeID), filepath.Ext(nodeID))
+	return filepath.Join(filepath.Dir(nodeID), stem+".types"+ext)
+}
+
+// cycleBreakExplanation describes the suggested edge in plain words
+func cycleBreakExplanation(s *analysis.CycleBreakSuggestion, goPackage bool) string {
+	edge := s.Edge
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Break the cycle %s by removing the import of %s from %s.",
+		strings.Join(s.Cycle, " -> "), edge.To, edge.From)
+
+	if len(edge.Symbols) == 0 {
+		sb.WriteString(" No symbols are used across this edge, so the import can be dropped as is.")
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, " It is the weakest link: %s uses %d symbol(s) of %s (%s) in %d place(s).",
+		edge.From, len(edge.Symbols), edge.To, strings.Join(edge.Symbols, ", "), edge.Usages)
+	if goPackage {
+		fmt.Fprintf(&sb, " Declare an interface covering them in a new package %s, make %s depend on it"+
+			" and inject the implementation from %s.", s.NewPackage, edge.From, edge.To)
+	} else {
+		fmt.Fprintf(&sb, " Move the shared declarations into %s and import them from there in both %s and %s.",
+			s.NewPackage, edge.From, edge.To)
+	}
+	return sb.String()
+}
@@ -0,0 +1,105 @@
+package analyzers
+
+import (
+	"path/filepath"
+	"shotgun_code/domain/analysis"
+	"strings"
+)
+
+const (
+	// depNodeExternal is the type of dependency nodes for external modules
+	depNodeExternal = "external"
+
+	// externalNodePrefix keeps external module IDs apart from project paths
+	externalNodePrefix = "external:"
+)
+
+// goHostsWithOwner are Go module hosts whose module paths have the form host/owner/repo
+var goHostsWithOwner = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+	"golang.org":    true,
+}
+
+// nodeBuiltinModules are the Node.js core modules, importable without the node: prefix
+var nodeBuiltinModules = map[string]bool{
+	"assert": true, "async_hooks": true, "buffer": true, "child_process": true, "cluster": true,
+	"console": true, "constants": true, "crypto": true, "dgram": true, "diagnostics_channel": true,
+	"dns": true, "domain": true, "events": true, "fs": true, "http": true, "http2": true,
+	"https": true, "inspector": true, "module": true, "net": true, "os": true, "path": true,
+	"perf_hooks": true, "process": true, "punycode": true, "querystring": true, "readline": true,
+	"repl": true, "stream": true, "string_decoder": true, "sys": true, "timers": true,
+	"tls": true, "trace_events": true, "tty": true, "url": true, "util": true, "v8": true,
+	"vm": true, "wasi": true, "worker_threads": true, "zlib": true,
+}
+
+// addExternalDepEdge links a file to the external module of an import that
+// didn't resolve to a project file, one node per top-level module path (e.g.
+// github.com/gin-gonic/gin or @vue/runtime-core). The Go standard library and
+// Node built-ins are left out.
+func (b *CallGraphBuilderImpl) addExternalDepEdge(filePath string, imp importInfo) {
+	var module string
+	if filepath.Ext(filePath) == extGo {
+		module = b.externalGoModule(imp.path)
+	} else {
+		module = externalJSModule(imp.path)
+	}
+	if module == "" {
+		return
+	}
+
+	id := externalNodePrefix + module
+	node, exists := b.depGraph.Nodes[id]
+	if !exists {
+		node = &analysis.DependencyNode{
+			ID: id, Name: module, Type: depNodeExternal, Package: module,
+			Dependencies: make([]string, 0), Dependents: make([]string, 0),
+		}
+		b.depGraph.Nodes[id] = node
+	}
+	// A file importing several packages of a module links to it once
+	for _, dependent := range node.Dependents {
+		if dependent == filePath {
+			return
+		}
+	}
+	b.addDepEdge(filePath, id, imp)
+}
+
+// externalGoModule returns the top-level module path of a third-party Go
+// import, or "" for the standard library and the project's own modules
+func (b *CallGraphBuilderImpl) externalGoModule(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	if !strings.Contains(parts[0], ".") {
+		return ""
+	}
+	for _, modulePath := range b.goModules {
+		if importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/") {
+			return ""
+		}
+	}
+
+	n := 2
+	if goHostsWithOwner[parts[0]] {
+		n = 3
+	}
+	return strings.Join(parts[:min(n, len(parts))], "/")
+}
+
+// externalJSModule returns the package name of a bare JS/TS import, or "" for
+// relative and alias imports and Node built-ins
+func externalJSModule(importPath string) string {
+	if importPath == "" || strings.HasPrefix(importPath, ".") || strings.HasPrefix(importPath, "/") ||
+		strings.HasPrefix(importPath, "@/") || strings.HasPrefix(importPath, "~/") || strings.HasPrefix(importPath, "node:") {
+		return ""
+	}
+	parts := strings.Split(importPath, "/")
+	if strings.HasPrefix(importPath, "@") && len(parts) > 1 {
+		return parts[0] + "/" + parts[1]
+	}
+	if nodeBuiltinModules[parts[0]] {
+		return ""
+	}
+	return parts[0]
+}
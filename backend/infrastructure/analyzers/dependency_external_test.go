@@ -0,0 +1,71 @@
+package analyzers
+
+import (
+	"path/filepath"
+	"shotgun_code/domain/analysis"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCallGraphBuilder_ExternalDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, tmpDir, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, tmpDir, "api/server.go", `package api
+
+import (
+	"net/http"
+
+	"example.com/app/store"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap/zapcore"
+)
+`)
+	writeTestFile(t, tmpDir, "store/store.go", "package store\n")
+	writeTestFile(t, tmpDir, "web/main.ts", "import { ref } from '@vue/runtime-core/dist'\nimport x from 'lodash/fp'\nimport fs from 'node:fs'\nimport path from 'path'\nimport { readFile } from 'fs/promises'\nimport { a } from './util'\n")
+	writeTestFile(t, tmpDir, "web/util.ts", "export const a = 1\n")
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	depGraph, err := builder.BuildDependencyGraph(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id := range depGraph.Nodes {
+		if strings.HasPrefix(id, externalNodePrefix) {
+			t.Fatalf("external modules should be left out by default, got %s", id)
+		}
+	}
+
+	depGraph, err = builder.BuildDependencyGraphWithOptions(tmpDir, analysis.DependencyGraphOptions{IncludeExternal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := depGraph.Nodes[filepath.Join("api", "server.go")]
+	wantServer := []string{"external:github.com/gin-gonic/gin", "external:go.uber.org/zap", "store"}
+	if got := slices.Sorted(slices.Values(server.Dependencies)); !slices.Equal(got, wantServer) {
+		t.Errorf("server.go dependencies = %v, want %v", got, wantServer)
+	}
+	main := depGraph.Nodes[filepath.Join("web", "main.ts")]
+	wantMain := []string{"external:@vue/runtime-core", "external:lodash", filepath.Join("web", "util.ts")}
+	if got := slices.Sorted(slices.Values(main.Dependencies)); !slices.Equal(got, wantMain) {
+		t.Errorf("main.ts dependencies = %v, want %v", got, wantMain)
+	}
+
+	gin := depGraph.Nodes["external:github.com/gin-gonic/gin"]
+	if gin.Type != depNodeExternal || gin.Name != "github.com/gin-gonic/gin" || len(gin.Dependencies) != 0 {
+		t.Errorf("unexpected external node %+v", gin)
+	}
+
+	mermaid, err := builder.DependencyMermaid(tmpDir, analysis.DependencyGraphOptions{IncludeExternal: true}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mermaid, `(["github.com/gin-gonic/gin"]):::external`) || !strings.Contains(mermaid, "classDef external") {
+		t.Errorf("expected external nodes styled apart, got:\n%s", mermaid)
+	}
+	if !strings.Contains(mermaid, `["server.go"]`) {
+		t.Errorf("expected internal nodes drawn as boxes, got:\n%s", mermaid)
+	}
+}
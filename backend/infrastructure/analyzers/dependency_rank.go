@@ -0,0 +1,56 @@
+package analyzers
+
+import (
+	"shotgun_code/domain/analysis"
+	"sort"
+)
+
+// defaultMostDependedLimit is how many nodes GetMostDepended returns without a limit
+const defaultMostDependedLimit = 20
+
+// GetMostDepended builds the dependency graph and ranks the files most
+// depended on, the hubs a change to which affects the most code. Go files
+// are imported through their package, so Go packages are ranked instead.
+// Dependents are counted in files; nodes without dependents are left out.
+func (b *CallGraphBuilderImpl) GetMostDepended(projectRoot string, limit int) ([]analysis.FileDependencyRank, error) {
+	// Hold the lock SuggestCycleBreakInProject builds under, so the graph
+	// isn't replaced while it is ranked
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, err := b.BuildDependencyGraph(projectRoot); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultMostDependedLimit
+	}
+
+	ranks := make([]analysis.FileDependencyRank, 0)
+	for id, node := range b.depGraph.Nodes {
+		dependents := 0
+		for _, dependent := range node.Dependents {
+			if b.depGraph.Nodes[dependent].Type == "file" {
+				dependents++
+			}
+		}
+		if dependents == 0 {
+			continue
+		}
+		ranks = append(ranks, analysis.FileDependencyRank{
+			Path: id, Type: node.Type, Dependents: dependents, Dependencies: len(node.Dependencies),
+		})
+	}
+
+	sort.Slice(ranks, func(i, j int) bool {
+		if ranks[i].Dependents != ranks[j].Dependents {
+			return ranks[i].Dependents > ranks[j].Dependents
+		}
+		if ranks[i].Dependencies != ranks[j].Dependencies {
+			return ranks[i].Dependencies > ranks[j].Dependencies
+		}
+		return ranks[i].Path < ranks[j].Path
+	})
+	if len(ranks) > limit {
+		ranks = ranks[:limit]
+	}
+	return ranks, nil
+}
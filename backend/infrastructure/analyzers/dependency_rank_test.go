@@ -0,0 +1,67 @@
+package analyzers
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestCallGraphBuilder_GetMostDepended(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, tmpDir, "src/utils.ts", "export const a = 1\n")
+	writeTestFile(t, tmpDir, "src/api.ts", "import { a } from './utils'\n")
+	writeTestFile(t, tmpDir, "src/main.ts", "import { a } from './utils'\nimport { b } from './api'\n")
+	writeTestFile(t, tmpDir, "src/page.ts", "import { a } from './utils'\nimport { b } from './api'\nimport x from 'lodash'\n")
+	writeTestFile(t, tmpDir, "go.mod", "module example.com/app\n")
+	writeTestFile(t, tmpDir, "store/store.go", "package store\n")
+	writeTestFile(t, tmpDir, "api/a.go", "package api\n\nimport \"example.com/app/store\"\n")
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	ranks, err := builder.GetMostDepended(tmpDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		path                     string
+		dependents, dependencies int
+	}{
+		{filepath.Join("src", "utils.ts"), 3, 0},
+		{filepath.Join("src", "api.ts"), 2, 1},
+		{"store", 1, 0},
+	}
+	if len(ranks) != len(want) {
+		t.Fatalf("expected %d ranked nodes, got %+v", len(want), ranks)
+	}
+	for i, w := range want {
+		if ranks[i].Path != w.path || ranks[i].Dependents != w.dependents || ranks[i].Dependencies != w.dependencies {
+			t.Errorf("rank %d = %+v, want %+v", i, ranks[i], w)
+		}
+	}
+	if ranks[2].Type != "package" {
+		t.Errorf("expected the Go package ranked as a package, got %s", ranks[2].Type)
+	}
+
+	if ranks, _ := builder.GetMostDepended(tmpDir, 1); len(ranks) != 1 {
+		t.Errorf("expected the limit to apply, got %d", len(ranks))
+	}
+}
+
+func TestCallGraphBuilder_GetMostDependedConcurrently(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, tmpDir, "src/utils.ts", "export const a = 1\n")
+	writeTestFile(t, tmpDir, "src/api.ts", "import { a } from './utils'\n")
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ranks, err := builder.GetMostDepended(tmpDir, 0); err != nil || len(ranks) != 1 {
+				t.Errorf("expected utils.ts ranked, got %+v (err %v)", ranks, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
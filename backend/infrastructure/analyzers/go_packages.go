@@ -0,0 +1,124 @@
+package analyzers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"shotgun_code/domain/analysis"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// goPackagesLoadMode is what precise analysis needs from go/packages.
+// Dependencies are type-checked from source so that objects are shared
+// between the packages of the module.
+const goPackagesLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+	packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps
+
+// goConfidenceExternal is used for precise matches that resolve to a dependency
+const goConfidenceExternal = 0.3
+
+// loadGoPackages loads and type-checks all packages of the Go module at projectRoot.
+// It fails when the module does not type-check, so callers can fall back to
+// syntactic analysis.
+func loadGoPackages(ctx context.Context, projectRoot string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    goPackagesLoadMode,
+		Dir:     projectRoot,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Go packages: %w", err)
+	}
+	if len(pkgs) == 0 {
+		return nil, errors.New("no Go packages found")
+	}
+
+	var errCount int
+	var firstErr error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, pkgErr := range pkg.Errors {
+			if firstErr == nil {
+				firstErr = pkgErr
+			}
+			errCount++
+		}
+	})
+	if errCount > 0 {
+		return nil, fmt.Errorf("type checking failed with %d errors: %w", errCount, firstErr)
+	}
+	return pkgs, nil
+}
+
+// goPackageFiles calls fn for every parsed file of the loaded packages with its project-relative path
+func goPackageFiles(pkgs []*packages.Package, projectRoot string, fn func(pkg *packages.Package, file *ast.File, relPath string)) {
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			filename := pkg.Fset.Position(file.Pos()).Filename
+			relPath, err := filepath.Rel(projectRoot, filename)
+			if err != nil {
+				continue
+			}
+			fn(pkg, file, relPath)
+		}
+	}
+}
+
+// findGoReferencesPrecise classifies every identifier named symbolName in the
+// loaded packages. Matches are grouped by project-relative file path.
+func findGoReferencesPrecise(pkgs []*packages.Package, projectRoot, symbolName string, symbolKind analysis.SymbolKind) map[string][]goIdentMatch {
+	projectPkgs := make(map[*types.Package]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		projectPkgs[pkg.Types] = true
+	}
+
+	result := make(map[string][]goIdentMatch)
+	goPackageFiles(pkgs, projectRoot, func(pkg *packages.Package, file *ast.File, relPath string) {
+		matches := []goIdentMatch{}
+		ast.Inspect(file, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok || ident.Name != symbolName {
+				return true
+			}
+			confidence, isDef := classifyGoIdentPrecise(pkg.TypesInfo, ident, projectPkgs, symbolKind)
+			if confidence > 0 {
+				pos := pkg.Fset.Position(ident.Pos())
+				matches = append(matches, goIdentMatch{
+					line: pos.Line, column: pos.Column,
+					confidence: confidence, isDefinition: isDef,
+				})
+			}
+			return true
+		})
+		result[relPath] = matches
+	})
+	return result
+}
+
+// classifyGoIdentPrecise scores an identifier using complete type information
+func classifyGoIdentPrecise(info *types.Info, ident *ast.Ident, projectPkgs map[*types.Package]bool, symbolKind analysis.SymbolKind) (float64, bool) {
+	if obj, ok := info.Defs[ident]; ok {
+		if obj == nil || isGoLocalObject(obj, obj.Pkg()) {
+			return goConfidenceLocal, false
+		}
+		return goConfidenceDefinition * goKindFactor(obj, symbolKind), true
+	}
+
+	obj := info.Uses[ident]
+	switch {
+	case obj == nil:
+		return goConfidenceUnresolved, false
+	case obj.Pkg() == nil:
+		return 0, false // builtin such as len or error
+	case isGoLocalObject(obj, obj.Pkg()):
+		return goConfidenceLocal, false
+	case !projectPkgs[obj.Pkg()]:
+		return goConfidenceExternal, false
+	default:
+		return goConfidenceResolved * goKindFactor(obj, symbolKind), false
+	}
+}
@@ -0,0 +1,336 @@
+package analyzers
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Confidence levels for interface implementations
+const (
+	implConfidencePrecise   = 1.0 // method sets compared with go/types
+	implConfidenceSyntactic = 0.6 // method names and arity match
+)
+
+// maxEmbeddedInterfaceDepth limits how deep embedded interfaces are expanded syntactically
+const maxEmbeddedInterfaceDepth = 5
+
+// splitQualifiedName splits "pkg.Name" into package and name; pkg is empty for plain names
+func splitQualifiedName(name string) (string, string) {
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return name[:idx], name[idx+1:]
+	}
+	return "", name
+}
+
+// FindImplementations finds Go types that implement the named interface.
+// The name may be qualified with the package name ("domain.Logger").
+// With precise Go analysis enabled and a module that type-checks, method sets
+// are compared with go/types; otherwise types are matched by method names and
+// parameter counts.
+func (rf *ReferenceFinder) FindImplementations(ctx context.Context, projectRoot, interfaceName string) ([]Reference, error) {
+	pkgName, name := splitQualifiedName(interfaceName)
+	if name == "" {
+		return nil, fmt.Errorf("interface name is required")
+	}
+
+	var refs []Reference
+	found := false
+	if rf.preciseGo {
+		if pkgs, err := loadGoPackages(ctx, projectRoot); err == nil {
+			refs, found = findGoImplementationsPrecise(pkgs, projectRoot, pkgName, name)
+		}
+	}
+	if !found {
+		var err error
+		refs, found, err = findGoImplementationsSyntactic(ctx, projectRoot, pkgName, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("interface %s not found", interfaceName)
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].FilePath != refs[j].FilePath {
+			return refs[i].FilePath < refs[j].FilePath
+		}
+		return refs[i].Line < refs[j].Line
+	})
+	return refs, nil
+}
+
+// implementationRef builds a reference to a type declaration
+func implementationRef(projectRoot string, pos token.Position, confidence float64) (Reference, bool) {
+	relPath, err := filepath.Rel(projectRoot, pos.Filename)
+	if err != nil {
+		return Reference{}, false
+	}
+	content, err := os.ReadFile(pos.Filename)
+	if err != nil {
+		return Reference{}, false
+	}
+	lines := strings.Split(string(content), "\n")
+	if pos.Line < 1 || pos.Line > len(lines) {
+		return Reference{}, false
+	}
+	return Reference{
+		FilePath:   relPath,
+		Line:       pos.Line,
+		Column:     pos.Column,
+		LineText:   strings.TrimSpace(lines[pos.Line-1]),
+		Context:    getLineContext(lines, pos.Line-1),
+		Confidence: confidence,
+	}, true
+}
+
+// findGoImplementationsPrecise uses type information; found is false if no such interface exists
+func findGoImplementationsPrecise(pkgs []*packages.Package, projectRoot, pkgName, name string) ([]Reference, bool) {
+	interfaces, concrete := collectGoNamedTypes(pkgs)
+	fsets := make(map[*types.Package]*token.FileSet, len(pkgs))
+	for _, pkg := range pkgs {
+		fsets[pkg.Types] = pkg.Fset
+	}
+
+	var targets []*types.Interface
+	for _, named := range interfaces {
+		obj := named.Obj()
+		if obj.Name() == name && (pkgName == "" || obj.Pkg().Name() == pkgName) {
+			targets = append(targets, named.Underlying().(*types.Interface))
+		}
+	}
+	if len(targets) == 0 {
+		return nil, false
+	}
+
+	var refs []Reference
+	for _, typ := range concrete {
+		for _, iface := range targets {
+			if !types.Implements(typ, iface) && !types.Implements(types.NewPointer(typ), iface) {
+				continue
+			}
+			obj := typ.Obj()
+			if ref, ok := implementationRef(projectRoot, fsets[obj.Pkg()].Position(obj.Pos()), implConfidencePrecise); ok {
+				refs = append(refs, ref)
+			}
+			break
+		}
+	}
+	return refs, true
+}
+
+// goMethodShape is the syntactic shape of a method: name and parameter/result counts
+type goMethodShape struct {
+	params  int
+	results int
+}
+
+// goSyntacticInterface is an interface declaration found by parsing
+type goSyntacticInterface struct {
+	pkgName  string
+	methods  map[string]goMethodShape
+	embedded []string
+}
+
+// goSyntacticType is a concrete type declaration found by parsing
+type goSyntacticType struct {
+	pos     token.Position
+	methods map[string]goMethodShape
+}
+
+// findGoImplementationsSyntactic matches types by method names and arity.
+// found is false if no interface with that name is declared in the project.
+func findGoImplementationsSyntactic(ctx context.Context, projectRoot, pkgName, name string) ([]Reference, bool, error) {
+	fset := token.NewFileSet()
+	interfaces := make(map[string][]*goSyntacticInterface) // name -> declarations
+	typesByKey := make(map[string]*goSyntacticType)        // dir|name -> type
+
+	err := filepath.Walk(projectRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") || refFinderSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != extGo {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil
+		}
+		collectGoSyntacticTypes(fset, file, filepath.Dir(path), interfaces, typesByKey)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var targets []map[string]goMethodShape
+	for _, iface := range interfaces[name] {
+		if pkgName == "" || iface.pkgName == pkgName {
+			targets = append(targets, expandGoInterfaceMethods(iface, interfaces, 0))
+		}
+	}
+	if len(targets) == 0 {
+		return nil, false, nil
+	}
+
+	var refs []Reference
+	for _, typ := range typesByKey {
+		if typ.pos.Filename == "" {
+			continue // methods without a type declaration in the project
+		}
+		for _, methods := range targets {
+			if !hasGoMethods(typ.methods, methods) {
+				continue
+			}
+			if ref, ok := implementationRef(projectRoot, typ.pos, implConfidenceSyntactic); ok {
+				refs = append(refs, ref)
+			}
+			break
+		}
+	}
+	return refs, true, nil
+}
+
+// collectGoSyntacticTypes records interfaces, type declarations and methods of a file
+func collectGoSyntacticTypes(fset *token.FileSet, file *ast.File, dir string, interfaces map[string][]*goSyntacticInterface, typesByKey map[string]*goSyntacticType) {
+	typeFor := func(name string) *goSyntacticType {
+		key := dir + "|" + name
+		typ, ok := typesByKey[key]
+		if !ok {
+			typ = &goSyntacticType{methods: make(map[string]goMethodShape)}
+			typesByKey[key] = typ
+		}
+		return typ
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok || typeSpec.TypeParams != nil {
+					continue
+				}
+				if ifaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+					interfaces[typeSpec.Name.Name] = append(interfaces[typeSpec.Name.Name], parseGoSyntacticInterface(file.Name.Name, ifaceType))
+					continue
+				}
+				typeFor(typeSpec.Name.Name).pos = fset.Position(typeSpec.Name.Pos())
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				continue
+			}
+			if recvName := goReceiverTypeName(d.Recv.List[0].Type); recvName != "" {
+				typeFor(recvName).methods[d.Name.Name] = goFuncShape(d.Type)
+			}
+		}
+	}
+}
+
+// parseGoSyntacticInterface extracts methods and embedded interface names
+func parseGoSyntacticInterface(pkgName string, ifaceType *ast.InterfaceType) *goSyntacticInterface {
+	iface := &goSyntacticInterface{pkgName: pkgName, methods: make(map[string]goMethodShape)}
+	for _, field := range ifaceType.Methods.List {
+		switch t := field.Type.(type) {
+		case *ast.FuncType:
+			for _, name := range field.Names {
+				iface.methods[name.Name] = goFuncShape(t)
+			}
+		case *ast.Ident:
+			iface.embedded = append(iface.embedded, t.Name)
+		case *ast.SelectorExpr:
+			iface.embedded = append(iface.embedded, t.Sel.Name)
+		}
+	}
+	return iface
+}
+
+// expandGoInterfaceMethods returns the methods of an interface including embedded ones
+func expandGoInterfaceMethods(iface *goSyntacticInterface, interfaces map[string][]*goSyntacticInterface, depth int) map[string]goMethodShape {
+	methods := make(map[string]goMethodShape, len(iface.methods))
+	for name, shape := range iface.methods {
+		methods[name] = shape
+	}
+	if depth >= maxEmbeddedInterfaceDepth {
+		return methods
+	}
+	for _, embeddedName := range iface.embedded {
+		candidates := interfaces[embeddedName]
+		if len(candidates) == 0 {
+			continue
+		}
+		for name, shape := range expandGoInterfaceMethods(candidates[0], interfaces, depth+1) {
+			methods[name] = shape
+		}
+	}
+	return methods
+}
+
+// goReceiverTypeName returns the type name of a method receiver expression
+func goReceiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return goReceiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return goReceiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return goReceiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// goFuncShape counts parameters and results of a function type
+func goFuncShape(fn *ast.FuncType) goMethodShape {
+	return goMethodShape{params: goFieldCount(fn.Params), results: goFieldCount(fn.Results)}
+}
+
+// goFieldCount counts the entries of a parameter list, expanding "a, b int"
+func goFieldCount(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+	count := 0
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			count++
+		} else {
+			count += len(field.Names)
+		}
+	}
+	return count
+}
+
+// hasGoMethods reports whether a type has every required method with matching arity
+func hasGoMethods(have, required map[string]goMethodShape) bool {
+	if len(required) == 0 {
+		return false
+	}
+	for name, shape := range required {
+		if got, ok := have[name]; !ok || got != shape {
+			return false
+		}
+	}
+	return true
+}
@@ -1,18 +1,26 @@
 package analyzers
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"os"
 	"path/filepath"
 	"regexp"
 	"shotgun_code/domain/analysis"
+	"sort"
 	"strings"
 )
 
-// ReferenceFinder finds references to symbols across the project
+// ReferenceFinder finds references to symbols across the project.
+// Name matches are classified instead of reported verbatim: matches in
+// comments and strings are dropped, Go identifiers are resolved with go/types,
+// and candidates are cross-checked against the symbol index when one is set:
+// a match in a file that cannot see any indexed definition is dropped.
 type ReferenceFinder struct {
-	registry analysis.AnalyzerRegistry
+	registry    analysis.AnalyzerRegistry
+	symbolIndex analysis.SymbolIndex
+	preciseGo   bool
 }
 
 // NewReferenceFinder creates a new reference finder
@@ -20,16 +28,77 @@ func NewReferenceFinder(registry analysis.AnalyzerRegistry) *ReferenceFinder {
 	return &ReferenceFinder{registry: registry}
 }
 
+// SetSymbolIndex sets the symbol index used to cross-check candidates (optional)
+func (rf *ReferenceFinder) SetSymbolIndex(index analysis.SymbolIndex) {
+	rf.symbolIndex = index
+}
+
+// SetPreciseGoAnalysis enables type-checking the whole Go module with go/packages.
+// It resolves cross-package references exactly but is slower and requires a
+// buildable module; otherwise packages are checked one by one with stubbed imports.
+func (rf *ReferenceFinder) SetPreciseGoAnalysis(enabled bool) {
+	rf.preciseGo = enabled
+}
+
 // Reference represents a reference to a symbol
 type Reference struct {
-	FilePath     string `json:"filePath"`
-	Line         int    `json:"line"`
-	Column       int    `json:"column"`
-	LineText     string `json:"lineText"`
-	Context      string `json:"context"` // surrounding context
-	IsDefinition bool   `json:"isDefinition"`
+	FilePath     string  `json:"filePath"`
+	Line         int     `json:"line"`
+	Column       int     `json:"column"`
+	LineText     string  `json:"lineText"`
+	Context      string  `json:"context"` // surrounding context
+	IsDefinition bool    `json:"isDefinition"`
+	Confidence   float64 `json:"confidence"` // 0..1, how likely this is a real reference
+	// EnclosingFunction is the function or method containing the reference
+	// ("Type.Method" for methods), empty at file level
+	EnclosingFunction string `json:"enclosingFunction,omitempty"`
+}
+
+// UsagePage is one page of FindUsages results
+type UsagePage struct {
+	Usages  []Reference `json:"usages"`
+	Total   int         `json:"total"` // usages found, at most maxUsageCandidates
+	Offset  int         `json:"offset"`
+	Limit   int         `json:"limit"`
+	HasMore bool        `json:"hasMore"`
+	// Truncated reports that scanning stopped at maxUsageCandidates, so Total
+	// is a lower bound
+	Truncated bool `json:"truncated"`
 }
 
+const (
+	// maxReferenceResults is the number of references returned
+	maxReferenceResults = 50
+	// maxReferenceCandidates bounds scanning before ranking by confidence
+	maxReferenceCandidates = 500
+	// minReferenceConfidence drops matches that are almost certainly coincidental
+	minReferenceConfidence = 0.15
+
+	// defaultUsageLimit is the FindUsages page size when none is given
+	defaultUsageLimit = 100
+	// maxUsageLimit bounds the FindUsages page size
+	maxUsageLimit = 1000
+	// maxUsageCandidates bounds scanning for FindUsages pagination
+	maxUsageCandidates = 10000
+)
+
+// Confidence levels for syntax-only (non-Go) matches
+const (
+	textConfidenceDefinition   = 1.0
+	textConfidenceSameFile     = 0.8 // symbol is defined in the same file
+	textConfidenceIndexed      = 0.7 // symbol is defined elsewhere in the project
+	textConfidenceUnknown      = 0.5 // no symbol index to cross-check
+	textConfidenceNotInProject = 0.3 // index has no definition with this name
+	textConfidenceOutOfScope   = 0.1 // file neither defines nor imports the symbol
+)
+
+// importClausePattern matches import statements of the languages matched
+// syntax-only: ES modules and require, Python, Java/Kotlin, Rust, C# and C/C++
+var importClausePattern = regexp.MustCompile(`(?m)^[ \t]*(?:import\b[^;]*?['"][^'"\n]*['"]|import[ \t]+[\w., \t*]+|from[ \t]+[\w.]+[ \t]+import[ \t]+(?:\([^)]*\)|[^\n]+)|(?:pub[ \t]+)?use[ \t]+[^;]+;|using[ \t]+[^;]+;|#include[ \t]*[<"][^>"\n]+[>"])|\brequire\(\s*['"][^'"\n]+['"]\s*\)`)
+
+// importWordPattern splits import statements into the words a symbol or module stem is compared with
+var importWordPattern = regexp.MustCompile(`\w+`)
+
 // skipDirs contains directories to skip during reference search
 var refFinderSkipDirs = map[string]bool{
 	"node_modules": true, "vendor": true, "build": true, "dist": true,
@@ -48,65 +117,240 @@ func getLineContext(lines []string, lineIdx int) string {
 	return strings.Join(lines[start:end], "\n")
 }
 
-// isDefinition checks if a reference is the symbol definition
-func (rf *ReferenceFinder) isDefinition(ctx context.Context, analyzer analysis.LanguageAnalyzer, relPath string, content []byte, symbolName string, lineNum int) bool {
-	symbols, _ := analyzer.ExtractSymbols(ctx, relPath, content)
-	for _, sym := range symbols {
-		if sym.Name == symbolName && sym.StartLine == lineNum {
+// indexDefinitions returns symbols from the index with exactly this name
+func indexDefinitions(index analysis.SymbolIndex, symbolName string) []analysis.Symbol {
+	if index == nil || !index.IsIndexed() {
+		return nil
+	}
+	var defs []analysis.Symbol
+	for _, sym := range index.FindByExactName(symbolName) {
+		if sym.Name == symbolName {
+			defs = append(defs, sym)
+		}
+	}
+	return defs
+}
+
+// hasDefinitionIn reports whether any definition lives in dir
+func hasDefinitionIn(defs []analysis.Symbol, dir string) bool {
+	for _, def := range defs {
+		if filepath.Dir(def.FilePath) == dir {
 			return true
 		}
 	}
 	return false
 }
 
+// moduleStem returns the name under which a file is imported: its base name
+// without extension, or the directory name for index and __init__ files
+func moduleStem(path string) string {
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if stem == "index" || stem == "__init__" || stem == "mod" {
+		return filepath.Base(filepath.Dir(path))
+	}
+	return stem
+}
+
+// visibleDefinition reports whether a file can see one of defs: it defines
+// the symbol, shares its directory (package), or its imports name the symbol
+// or the module defining it
+func visibleDefinition(defs []analysis.Symbol, relPath, symbolName string, content []byte) bool {
+	for _, def := range defs {
+		if def.FilePath == relPath || filepath.Dir(def.FilePath) == filepath.Dir(relPath) {
+			return true
+		}
+	}
+	imports := strings.Join(importClausePattern.FindAllString(string(content), -1), "\n")
+	if imports == "" {
+		return false
+	}
+	names := []string{symbolName}
+	for _, def := range defs {
+		names = append(names, moduleStem(def.FilePath))
+	}
+	words := make(map[string]bool)
+	for _, word := range importWordPattern.FindAllString(imports, -1) {
+		words[word] = true
+	}
+	for _, name := range names {
+		// stems such as date-utils span several words
+		if words[name] || importWordPattern.FindString(name) != name && strings.Contains(imports, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// textMatchConfidence scores a non-Go match using the symbol index
+func (rf *ReferenceFinder) textMatchConfidence(relPath, symbolName string, symbolKind analysis.SymbolKind, content []byte) float64 {
+	if rf.symbolIndex == nil || !rf.symbolIndex.IsIndexed() {
+		return textConfidenceUnknown
+	}
+	defs := indexDefinitions(rf.symbolIndex, symbolName)
+	if len(defs) == 0 {
+		return textConfidenceNotInProject
+	}
+	if !visibleDefinition(defs, relPath, symbolName, content) {
+		return textConfidenceOutOfScope
+	}
+
+	confidence := textConfidenceIndexed
+	kindMatches := symbolKind == ""
+	for _, def := range defs {
+		if def.FilePath == relPath {
+			confidence = textConfidenceSameFile
+		}
+		if def.Kind == symbolKind {
+			kindMatches = true
+		}
+	}
+	if !kindMatches {
+		confidence *= 0.5
+	}
+	return confidence
+}
+
+// definitionLines returns the lines where the file defines symbolName
+func definitionLines(symbols []analysis.Symbol, symbolName string) map[int]bool {
+	lines := make(map[int]bool)
+	for _, sym := range symbols {
+		if sym.Name == symbolName {
+			lines[sym.StartLine] = true
+		}
+	}
+	return lines
+}
+
+// enclosingFunction returns the innermost function or method of symbols whose
+// lines contain line; skip excludes the symbol defined on that line
+func enclosingFunction(symbols []analysis.Symbol, line int, skip string) string {
+	var best *analysis.Symbol
+	for i := range symbols {
+		sym := &symbols[i]
+		if sym.Kind != analysis.KindFunction && sym.Kind != analysis.KindMethod {
+			continue
+		}
+		if line < sym.StartLine || line > sym.EndLine || (sym.StartLine == line && sym.Name == skip) {
+			continue
+		}
+		if best == nil || sym.StartLine > best.StartLine {
+			best = sym
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	if best.Parent != "" {
+		return best.Parent + "." + best.Name
+	}
+	return best.Name
+}
+
 // findReferencesInFile finds references in a single file
-func (rf *ReferenceFinder) findReferencesInFile(ctx context.Context, pattern *regexp.Regexp, path, relPath string, symbolName string, symbolKind analysis.SymbolKind, maxRefs int) ([]Reference, bool) {
+func (rf *ReferenceFinder) findReferencesInFile(ctx context.Context, goResolver *goRefResolver, pattern *regexp.Regexp, path, relPath string, symbolName string, symbolKind analysis.SymbolKind) []Reference {
 	analyzer := rf.registry.GetAnalyzer(path)
 	if analyzer == nil {
-		return nil, false
+		return nil
 	}
 
 	content, err := os.ReadFile(path)
 	if err != nil {
-		return nil, false
+		return nil
+	}
+	if !pattern.Match(content) {
+		return nil
 	}
 
 	lines := strings.Split(string(content), "\n")
-	var refs []Reference
+	newRef := func(lineIdx, column int, confidence float64, isDef bool) Reference {
+		return Reference{
+			FilePath:     relPath,
+			Line:         lineIdx + 1,
+			Column:       column,
+			LineText:     strings.TrimSpace(lines[lineIdx]),
+			Context:      getLineContext(lines, lineIdx),
+			IsDefinition: isDef,
+			Confidence:   confidence,
+		}
+	}
 
-	for i, line := range lines {
-		for _, match := range pattern.FindAllStringIndex(line, -1) {
-			ref := Reference{
-				FilePath: relPath,
-				Line:     i + 1,
-				Column:   match[0] + 1,
-				LineText: strings.TrimSpace(line),
-				Context:  getLineContext(lines, i),
-			}
+	symbols, _ := analyzer.ExtractSymbols(ctx, relPath, content)
+	refs := rf.classifyMatches(goResolver, pattern, path, relPath, content, symbols, symbolName, symbolKind, newRef)
+	for i := range refs {
+		skip := ""
+		if refs[i].IsDefinition {
+			skip = symbolName
+		}
+		refs[i].EnclosingFunction = enclosingFunction(symbols, refs[i].Line, skip)
+	}
+	return refs
+}
 
-			if symbolKind != "" {
-				ref.IsDefinition = rf.isDefinition(ctx, analyzer, relPath, content, symbolName, i+1)
+// classifyMatches scores the occurrences of symbolName in a file: Go files
+// are resolved with go/types, others are matched syntax-only
+func (rf *ReferenceFinder) classifyMatches(goResolver *goRefResolver, pattern *regexp.Regexp, path, relPath string, content []byte, symbols []analysis.Symbol, symbolName string, symbolKind analysis.SymbolKind, newRef func(lineIdx, column int, confidence float64, isDef bool) Reference) []Reference {
+	var refs []Reference
+	if filepath.Ext(path) == extGo {
+		if matches, ok := goResolver.findGoReferences(path, relPath, symbolName, symbolKind, rf.symbolIndex); ok {
+			lineCount := bytes.Count(content, []byte("\n")) + 1
+			for _, m := range matches {
+				if m.line-1 < lineCount {
+					refs = append(refs, newRef(m.line-1, m.column, m.confidence, m.isDefinition))
+				}
 			}
+			return refs
+		}
+	}
 
-			refs = append(refs, ref)
-			if len(refs) >= maxRefs {
-				return refs, true
+	// Syntax-only matching: ignore comments and string literals
+	codeLines := strings.Split(blankCommentsAndStrings(string(content), path), "\n")
+	defLines := definitionLines(symbols, symbolName)
+	baseConfidence := rf.textMatchConfidence(relPath, symbolName, symbolKind, content)
+	for i, line := range codeLines {
+		for _, match := range pattern.FindAllStringIndex(line, -1) {
+			if defLines[i+1] {
+				refs = append(refs, newRef(i, match[0]+1, textConfidenceDefinition, true))
+				continue
 			}
+			refs = append(refs, newRef(i, match[0]+1, baseConfidence, false))
 		}
 	}
-	return refs, false
+	return refs
 }
 
-// FindReferences finds all references to a symbol in the project
+// FindReferences finds all references to a symbol in the project,
+// ordered by confidence (most likely real references first).
 func (rf *ReferenceFinder) FindReferences(ctx context.Context, projectRoot string, symbolName string, symbolKind analysis.SymbolKind) ([]Reference, error) {
+	references, _, err := rf.collectReferences(ctx, projectRoot, symbolName, symbolKind, maxReferenceCandidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(references) > maxReferenceResults {
+		references = references[:maxReferenceResults]
+	}
+	return references, nil
+}
+
+// collectReferences scans the project until maxCandidates references are
+// found and returns them ordered by confidence; truncated reports that the
+// scan stopped early
+func (rf *ReferenceFinder) collectReferences(ctx context.Context, projectRoot string, symbolName string, symbolKind analysis.SymbolKind, maxCandidates int) ([]Reference, bool, error) {
 	pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(symbolName) + `\b`)
+	goResolver := newGoRefResolver()
+	if rf.preciseGo {
+		if pkgs, err := loadGoPackages(ctx, projectRoot); err == nil {
+			goResolver.precise = findGoReferencesPrecise(pkgs, projectRoot, symbolName, symbolKind)
+		}
+	}
 	var references []Reference
-	const maxResults = 50
 
 	err := filepath.Walk(projectRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 
 		if info.IsDir() {
 			if strings.HasPrefix(info.Name(), ".") || refFinderSkipDirs[info.Name()] {
@@ -116,25 +360,40 @@ func (rf *ReferenceFinder) FindReferences(ctx context.Context, projectRoot strin
 		}
 
 		relPath, _ := filepath.Rel(projectRoot, path)
-		refs, limitReached := rf.findReferencesInFile(ctx, pattern, path, relPath, symbolName, symbolKind, maxResults-len(references))
-		references = append(references, refs...)
+		for _, ref := range rf.findReferencesInFile(ctx, goResolver, pattern, path, relPath, symbolName, symbolKind) {
+			if ref.Confidence >= minReferenceConfidence {
+				references = append(references, ref)
+			}
+		}
 
-		if limitReached || len(references) >= maxResults {
+		if len(references) >= maxCandidates {
 			return filepath.SkipAll
 		}
 		return nil
 	})
 
-	if err != nil && !errors.Is(err, filepath.SkipAll) {
-		return nil, err
+	truncated := errors.Is(err, filepath.SkipAll)
+	if err != nil && !truncated {
+		return nil, false, err
 	}
 
-	return references, nil
+	sort.SliceStable(references, func(i, j int) bool {
+		return references[i].Confidence > references[j].Confidence
+	})
+	return references, truncated, nil
 }
 
-// FindUsages finds where a symbol is used (excluding definition)
-func (rf *ReferenceFinder) FindUsages(ctx context.Context, projectRoot string, symbolName string) ([]Reference, error) {
-	refs, err := rf.FindReferences(ctx, projectRoot, symbolName, "")
+// FindUsages finds where a symbol is used (excluding definition), one page at
+// a time: offset skips the first usages, limit defaults to defaultUsageLimit
+// and is capped at maxUsageLimit
+func (rf *ReferenceFinder) FindUsages(ctx context.Context, projectRoot string, symbolName string, offset, limit int) (*UsagePage, error) {
+	if limit <= 0 {
+		limit = defaultUsageLimit
+	}
+	limit = min(limit, maxUsageLimit)
+	offset = max(offset, 0)
+
+	refs, truncated, err := rf.collectReferences(ctx, projectRoot, symbolName, "", maxUsageCandidates)
 	if err != nil {
 		return nil, err
 	}
@@ -147,5 +406,11 @@ func (rf *ReferenceFinder) FindUsages(ctx context.Context, projectRoot string, s
 		}
 	}
 
-	return usages, nil
+	page := &UsagePage{Total: len(usages), Offset: offset, Limit: limit, Truncated: truncated}
+	if offset < len(usages) {
+		end := min(offset+limit, len(usages))
+		page.Usages = usages[offset:end]
+		page.HasMore = end < len(usages)
+	}
+	return page, nil
 }
@@ -0,0 +1,271 @@
+package analyzers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReferenceFinder_GoResolution(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "server/server.go", `package server
+
+// Handle handles requests. Handle is mentioned in this comment.
+func Handle() string {
+	return "Handle"
+}
+
+func Run() {
+	Handle()
+}
+
+func other() {
+	Handle := 1
+	_ = Handle
+}
+`)
+
+	finder := NewReferenceFinder(NewAnalyzerRegistry())
+	refs, err := finder.FindReferences(context.Background(), tmpDir, "Handle", "")
+	if err != nil {
+		t.Fatalf("FindReferences failed: %v", err)
+	}
+
+	file := filepath.Join("server", "server.go")
+	byLine := make(map[int]Reference)
+	for _, ref := range refs {
+		if ref.FilePath == file {
+			byLine[ref.Line] = ref
+		}
+	}
+
+	if _, ok := byLine[3]; ok {
+		t.Error("match in comment should be ignored")
+	}
+	if _, ok := byLine[5]; ok {
+		t.Error("match in string literal should be ignored")
+	}
+	if ref, ok := byLine[4]; !ok || !ref.IsDefinition || ref.Confidence != goConfidenceDefinition {
+		t.Errorf("expected definition on line 4, got %+v", ref)
+	}
+	if ref, ok := byLine[9]; !ok || ref.Confidence != goConfidenceResolved {
+		t.Errorf("expected resolved call on line 9, got %+v", ref)
+	}
+	if _, ok := byLine[13]; ok {
+		t.Error("local variable shadowing the name should be dropped")
+	}
+	if refs[0].Confidence < refs[len(refs)-1].Confidence {
+		t.Error("references should be ordered by confidence")
+	}
+}
+
+func TestReferenceFinder_TextMatchesSkipCommentsAndStrings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "app.ts", `// handle is documented here
+export function handle() {}
+const label = "handle"
+handle()
+`)
+
+	finder := NewReferenceFinder(NewAnalyzerRegistry())
+	refs, err := finder.FindReferences(context.Background(), tmpDir, "handle", "")
+	if err != nil {
+		t.Fatalf("FindReferences failed: %v", err)
+	}
+
+	lines := make(map[int]bool)
+	for _, ref := range refs {
+		lines[ref.Line] = true
+	}
+	if lines[1] || lines[3] {
+		t.Errorf("comment and string matches should be ignored, got lines %v", lines)
+	}
+	if !lines[2] || !lines[4] {
+		t.Errorf("expected matches on lines 2 and 4, got %v", lines)
+	}
+}
+
+func indexedReferenceFinder(t *testing.T, root string) *ReferenceFinder {
+	t.Helper()
+	registry := NewAnalyzerRegistry()
+	index := NewSymbolIndex(registry)
+	if err := index.IndexProject(context.Background(), root); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+	finder := NewReferenceFinder(registry)
+	finder.SetSymbolIndex(index)
+	return finder
+}
+
+func TestReferenceFinder_ScopesMatchesWithSymbolIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, tmpDir, "go.mod", "module example.com/app\n\ngo 1.21\n")
+	writeTestFile(t, tmpDir, "store/store.go", "package store\n\nfunc New() int { return 1 }\n")
+	writeTestFile(t, tmpDir, "cmd/main.go", `package main
+
+import (
+	"errors"
+
+	"example.com/app/store"
+)
+
+func main() {
+	_ = store.New()
+	_ = errors.New("x")
+}
+`)
+	writeTestFile(t, tmpDir, "web/api.ts", "export function fetchAll() {}\n")
+	writeTestFile(t, tmpDir, "web/view.ts", "import { fetchAll } from './api'\n\nexport function render() {\n  fetchAll()\n}\n")
+	writeTestFile(t, tmpDir, "scripts/legacy.ts", "function run() {\n  fetchAll()\n}\n")
+
+	finder := indexedReferenceFinder(t, tmpDir)
+
+	refs, err := finder.FindReferences(context.Background(), tmpDir, "New", "")
+	if err != nil {
+		t.Fatalf("FindReferences failed: %v", err)
+	}
+	var lines []int
+	for _, ref := range refs {
+		if ref.FilePath == filepath.Join("cmd", "main.go") {
+			lines = append(lines, ref.Line)
+		}
+	}
+	if len(lines) != 1 || lines[0] != 10 {
+		t.Errorf("expected only store.New on line 10, got lines %v", lines)
+	}
+
+	refs, err = finder.FindReferences(context.Background(), tmpDir, "fetchAll", "")
+	if err != nil {
+		t.Fatalf("FindReferences failed: %v", err)
+	}
+	files := make(map[string]Reference)
+	for _, ref := range refs {
+		if !ref.IsDefinition {
+			files[filepath.ToSlash(ref.FilePath)] = ref
+		}
+	}
+	if _, ok := files["scripts/legacy.ts"]; ok {
+		t.Error("file that does not import fetchAll should be dropped")
+	}
+	if ref, ok := files["web/view.ts"]; !ok || ref.EnclosingFunction != "render" {
+		t.Errorf("expected usage in render in web/view.ts, got %+v", ref)
+	}
+}
+
+func TestReferenceFinder_EnclosingFunction(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, tmpDir, "svc/svc.go", `package svc
+
+type Service struct{}
+
+func helper() int { return 1 }
+
+func (s *Service) Run() int {
+	return helper()
+}
+
+var value = helper()
+`)
+
+	finder := NewReferenceFinder(NewAnalyzerRegistry())
+	refs, err := finder.FindReferences(context.Background(), tmpDir, "helper", "")
+	if err != nil {
+		t.Fatalf("FindReferences failed: %v", err)
+	}
+	enclosing := make(map[int]string)
+	for _, ref := range refs {
+		enclosing[ref.Line] = ref.EnclosingFunction
+	}
+	want := map[int]string{5: "", 8: "Service.Run", 11: ""}
+	for line, fn := range want {
+		if got, ok := enclosing[line]; !ok || got != fn {
+			t.Errorf("line %d: expected enclosing %q, got %q (found=%v)", line, fn, got, ok)
+		}
+	}
+}
+
+func TestReferenceFinder_FindUsagesPagination(t *testing.T) {
+	tmpDir := t.TempDir()
+	var body strings.Builder
+	body.WriteString("package calc\n\nfunc Get() int { return 0 }\n\nfunc Sum() int {\n\tn := 0\n")
+	for i := 0; i < 25; i++ {
+		body.WriteString("\tn += Get()\n")
+	}
+	body.WriteString("\treturn n\n}\n")
+	writeTestFile(t, tmpDir, "calc/calc.go", body.String())
+
+	finder := NewReferenceFinder(NewAnalyzerRegistry())
+	seen := make(map[int]bool)
+	for offset := 0; ; offset += 10 {
+		page, err := finder.FindUsages(context.Background(), tmpDir, "Get", offset, 10)
+		if err != nil {
+			t.Fatalf("FindUsages failed: %v", err)
+		}
+		if page.Total != 25 || page.Truncated {
+			t.Fatalf("expected 25 usages, got total=%d truncated=%v", page.Total, page.Truncated)
+		}
+		for _, ref := range page.Usages {
+			if ref.IsDefinition || seen[ref.Line] {
+				t.Errorf("unexpected usage %+v", ref)
+			}
+			seen[ref.Line] = true
+		}
+		if !page.HasMore {
+			break
+		}
+	}
+	if len(seen) != 25 {
+		t.Errorf("expected 25 distinct usages across pages, got %d", len(seen))
+	}
+
+	page, err := finder.FindUsages(context.Background(), tmpDir, "Get", 100, 0)
+	if err != nil {
+		t.Fatalf("FindUsages failed: %v", err)
+	}
+	if len(page.Usages) != 0 || page.HasMore || page.Limit != defaultUsageLimit {
+		t.Errorf("expected an empty page past the end with the default limit, got %+v", page)
+	}
+}
+
+func TestBlankCommentsAndStrings(t *testing.T) {
+	input := "a := \"x // y\" // c\n/* b\nc */ d"
+	expected := "a :=              \n    \n     d"
+	if got := blankCommentsAndStrings(input, "main.go"); got != expected {
+		t.Errorf("unexpected result: %q, want %q", got, expected)
+	}
+}
+
+func TestReferenceFinder_FindImplementations(t *testing.T) {
+	tmpDir := writePreciseTestModule(t)
+	writeTestFile(t, tmpDir, "store/other.go", `package store
+
+type Cache struct{}
+
+func (c *Cache) Save(key string, ttl int) error { return nil }
+`)
+
+	for _, precise := range []bool{false, true} {
+		finder := NewReferenceFinder(NewAnalyzerRegistry())
+		finder.SetPreciseGoAnalysis(precise)
+		refs, err := finder.FindImplementations(context.Background(), tmpDir, "store.Store")
+		if err != nil {
+			t.Fatalf("FindImplementations(precise=%v) failed: %v", precise, err)
+		}
+
+		var names []string
+		for _, ref := range refs {
+			names = append(names, ref.LineText)
+		}
+		if len(refs) != 2 || names[0] != "type MemoryStore struct{}" || names[1] != "type DiskStore struct{}" {
+			t.Errorf("precise=%v: expected MemoryStore and DiskStore, got %v", precise, names)
+		}
+	}
+
+	finder := NewReferenceFinder(NewAnalyzerRegistry())
+	if _, err := finder.FindImplementations(context.Background(), tmpDir, "Missing"); err == nil {
+		t.Error("expected error for unknown interface")
+	}
+}
@@ -0,0 +1,298 @@
+package analyzers
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"shotgun_code/domain/analysis"
+	"strconv"
+	"strings"
+)
+
+// Confidence levels for Go references resolved with go/types
+const (
+	goConfidenceDefinition = 1.0
+	goConfidenceResolved   = 0.95 // use of a package-level object of the checked package
+	goConfidenceImported   = 0.85 // pkg.Name selector with a definition elsewhere in the project
+	goConfidenceSelector   = 0.6  // x.Name selector that could not be resolved
+	goConfidenceUnresolved = 0.4  // bare identifier that could not be resolved
+	goConfidenceLocal      = 0.1  // local variable/type that merely shares the name
+	goConfidenceOtherPkg   = 0.05 // pkg.Name selector on a package with no definition in the index
+)
+
+// goCheckedPackage is a type-checked Go package (one directory, one package name)
+type goCheckedPackage struct {
+	files map[string]*ast.File // absolute path -> file
+	info  *types.Info
+	pkg   *types.Package
+}
+
+// goRefResolver type-checks Go packages on demand to classify identifier matches.
+// Imports are stubbed, so checking works without building dependencies: objects
+// of the package itself resolve precisely, imported ones fall back to syntax.
+type goRefResolver struct {
+	fset     *token.FileSet
+	packages map[string]*goCheckedPackage // dir + package name -> package
+
+	// precise holds matches from go/packages analysis (relative path -> matches);
+	// files it doesn't cover fall back to per-package checking with stubbed imports
+	precise map[string][]goIdentMatch
+}
+
+func newGoRefResolver() *goRefResolver {
+	return &goRefResolver{
+		fset:     token.NewFileSet(),
+		packages: make(map[string]*goCheckedPackage),
+	}
+}
+
+// stubImporter satisfies imports with empty packages so type checking can proceed
+type stubImporter struct{}
+
+func (stubImporter) Import(importPath string) (*types.Package, error) {
+	name := path.Base(importPath)
+	if idx := strings.IndexAny(name, ".-"); idx > 0 {
+		name = name[:idx]
+	}
+	pkg := types.NewPackage(importPath, name)
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// packageFor returns the checked package containing the file, or nil if it can't be parsed
+func (r *goRefResolver) packageFor(filePath string) *goCheckedPackage {
+	target, err := parser.ParseFile(r.fset, filePath, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return nil
+	}
+	dir := filepath.Dir(filePath)
+	key := dir + "|" + target.Name.Name
+	if cp, ok := r.packages[key]; ok {
+		return cp
+	}
+
+	cp := r.checkPackage(dir, target.Name.Name)
+	r.packages[key] = cp
+	return cp
+}
+
+// checkPackage parses and type-checks all files of a package in dir
+func (r *goRefResolver) checkPackage(dir, pkgName string) *goCheckedPackage {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	cp := &goCheckedPackage{files: make(map[string]*ast.File)}
+	var files []*ast.File
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != extGo {
+			continue
+		}
+		fullPath := filepath.Join(dir, entry.Name())
+		file, err := parser.ParseFile(r.fset, fullPath, nil, parser.ParseComments)
+		if err != nil || file.Name.Name != pkgName {
+			continue
+		}
+		cp.files[fullPath] = file
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	cp.info = &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer: stubImporter{},
+		Error:    func(error) {}, // stubbed imports produce errors; keep checking
+	}
+	cp.pkg, _ = conf.Check(pkgName, r.fset, files, cp.info)
+	return cp
+}
+
+// goIdentMatch is a classified occurrence of the symbol name in a Go file
+type goIdentMatch struct {
+	line         int
+	column       int
+	confidence   float64
+	isDefinition bool
+}
+
+// findGoReferences classifies every identifier named symbolName in a Go file.
+// ok is false when the file cannot be parsed and syntax-free matching should be used.
+func (r *goRefResolver) findGoReferences(filePath, relPath, symbolName string, symbolKind analysis.SymbolKind, index analysis.SymbolIndex) ([]goIdentMatch, bool) {
+	if matches, ok := r.precise[relPath]; ok {
+		return matches, true
+	}
+
+	cp := r.packageFor(filePath)
+	if cp == nil {
+		return nil, false
+	}
+	file, ok := cp.files[filePath]
+	if !ok {
+		return nil, false
+	}
+
+	// Selector expressions tell us what an identifier is qualified with
+	selectorX := make(map[*ast.Ident]ast.Expr)
+	var idents []*ast.Ident
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if node.Sel.Name == symbolName {
+				selectorX[node.Sel] = node.X
+			}
+		case *ast.Ident:
+			if node.Name == symbolName {
+				idents = append(idents, node)
+			}
+		}
+		return true
+	})
+
+	imports := goFileImportNames(file)
+	pkgDir := filepath.Dir(relPath)
+	var matches []goIdentMatch
+	for _, ident := range idents {
+		confidence, isDef := r.classifyGoIdent(cp, ident, selectorX, imports, pkgDir, symbolName, symbolKind, index)
+		if confidence <= 0 {
+			continue
+		}
+		pos := r.fset.Position(ident.Pos())
+		matches = append(matches, goIdentMatch{
+			line: pos.Line, column: pos.Column,
+			confidence: confidence, isDefinition: isDef,
+		})
+	}
+	return matches, true
+}
+
+// classifyGoIdent scores a single identifier occurrence
+func (r *goRefResolver) classifyGoIdent(cp *goCheckedPackage, ident *ast.Ident, selectorX map[*ast.Ident]ast.Expr, imports map[string]string, pkgDir, symbolName string, symbolKind analysis.SymbolKind, index analysis.SymbolIndex) (float64, bool) {
+	if obj, ok := cp.info.Defs[ident]; ok {
+		if obj == nil || isGoLocalObject(obj, cp.pkg) {
+			return goConfidenceLocal, false
+		}
+		return goConfidenceDefinition * goKindFactor(obj, symbolKind), true
+	}
+
+	if obj := cp.info.Uses[ident]; obj != nil {
+		if obj.Pkg() == nil {
+			return 0, false // builtin such as len or error
+		}
+		if isGoLocalObject(obj, cp.pkg) {
+			return goConfidenceLocal, false
+		}
+		return goConfidenceResolved * goKindFactor(obj, symbolKind), false
+	}
+
+	defs := indexDefinitions(index, symbolName)
+	if x, ok := selectorX[ident]; ok {
+		if xIdent, ok := x.(*ast.Ident); ok && imports[xIdent.Name] != "" {
+			if hasDefinitionInImport(defs, imports[xIdent.Name]) {
+				return goConfidenceImported, false
+			}
+			if len(defs) > 0 {
+				// errors.New when searching for the project's New
+				return goConfidenceOtherPkg, false
+			}
+			return goConfidenceSelector, false
+		}
+		if len(defs) > 0 {
+			return goConfidenceSelector + 0.1, false
+		}
+		return goConfidenceSelector, false
+	}
+
+	if hasDefinitionIn(defs, pkgDir) {
+		return goConfidenceUnresolved + 0.3, false
+	}
+	return goConfidenceUnresolved, false
+}
+
+// hasDefinitionInImport reports whether any definition lives in the package
+// with this import path; definitions in the module root always match, since
+// the module path is unknown
+func hasDefinitionInImport(defs []analysis.Symbol, importPath string) bool {
+	for _, def := range defs {
+		dir := filepath.ToSlash(filepath.Dir(def.FilePath))
+		if dir == "." || importPath == dir || strings.HasSuffix(importPath, "/"+dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGoLocalObject reports whether obj is declared inside a function body
+func isGoLocalObject(obj types.Object, pkg *types.Package) bool {
+	if pkg == nil {
+		return false
+	}
+	switch o := obj.(type) {
+	case *types.Var:
+		if o.IsField() {
+			return false
+		}
+	case *types.Func:
+		return false
+	}
+	return obj.Parent() != nil && obj.Parent() != pkg.Scope()
+}
+
+// goKindFactor halves the confidence when the object kind contradicts the requested kind
+func goKindFactor(obj types.Object, kind analysis.SymbolKind) float64 {
+	if kind == "" {
+		return 1
+	}
+	var matches bool
+	switch o := obj.(type) {
+	case *types.Func:
+		isMethod := o.Type().(*types.Signature).Recv() != nil
+		matches = (kind == analysis.KindMethod && isMethod) || (kind == analysis.KindFunction && !isMethod)
+	case *types.TypeName:
+		switch kind {
+		case analysis.KindType, analysis.KindStruct, analysis.KindInterface, analysis.KindClass:
+			matches = true
+		}
+	case *types.Const:
+		matches = kind == analysis.KindConstant
+	case *types.Var:
+		if o.IsField() {
+			matches = kind == analysis.KindField || kind == analysis.KindProperty
+		} else {
+			matches = kind == analysis.KindVariable
+		}
+	default:
+		matches = true
+	}
+	if matches {
+		return 1
+	}
+	return 0.5
+}
+
+// goFileImportNames maps the local names under which packages are imported
+// to their import paths
+func goFileImportNames(file *ast.File) map[string]string {
+	names := make(map[string]string)
+	for _, imp := range file.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		if imp.Name != nil {
+			names[imp.Name.Name] = importPath
+			continue
+		}
+		names[path.Base(importPath)] = importPath
+	}
+	return names
+}
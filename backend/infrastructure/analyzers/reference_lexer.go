@@ -0,0 +1,103 @@
+package analyzers
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// hashCommentExts lists extensions whose line comments start with '#'
+var hashCommentExts = map[string]bool{
+	".py": true, ".rb": true, ".sh": true, ".bash": true, ".yaml": true, ".yml": true, ".toml": true,
+}
+
+// blankCommentsAndStrings replaces comments and string literals with spaces,
+// keeping newlines and byte offsets intact, so that identifier matches in the
+// result can be mapped back to the original lines and columns.
+func blankCommentsAndStrings(content, filePath string) string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	hashComments := hashCommentExts[ext]
+	cStyleComments := !hashComments
+
+	out := []byte(content)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(out); i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(content); {
+		ch := content[i]
+		switch {
+		case cStyleComments && ch == '/' && i+1 < len(content) && content[i+1] == '/',
+			hashComments && ch == '#':
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = len(content) - i
+			}
+			blank(i, i+end)
+			i += end
+		case cStyleComments && ch == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = len(content) - i - 2
+			} else {
+				end += 2
+			}
+			blank(i, i+2+end)
+			i += 2 + end
+		case ch == '"' || ch == '\'' || ch == '`':
+			end := stringLiteralEnd(content, i, ext)
+			blank(i, end)
+			i = end
+		default:
+			i++
+		}
+	}
+	return string(out)
+}
+
+// stringLiteralEnd returns the offset just past the string literal starting at start
+func stringLiteralEnd(content string, start int, ext string) int {
+	quote := content[start]
+
+	// Python triple-quoted strings
+	if ext == ".py" && strings.HasPrefix(content[start:], strings.Repeat(string(quote), 3)) {
+		delim := strings.Repeat(string(quote), 3)
+		end := strings.Index(content[start+3:], delim)
+		if end < 0 {
+			return len(content)
+		}
+		return start + 3 + end + 3
+	}
+
+	// Rust lifetimes ('a) look like unterminated char literals
+	if ext == ".rs" && quote == '\'' {
+		rest := content[start+1:]
+		if len(rest) > 4 {
+			rest = rest[:4]
+		}
+		if end := strings.IndexByte(rest, '\''); end < 0 {
+			return start + 1
+		}
+	}
+
+	// Backtick strings span lines; other quotes end at the line break
+	multiline := quote == '`'
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			if quote != '`' || ext != ".go" {
+				i++
+			}
+		case '\n':
+			if !multiline {
+				return i
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return len(content)
+}
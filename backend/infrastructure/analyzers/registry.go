@@ -23,9 +23,10 @@ func NewAnalyzerRegistry() *AnalyzerRegistryImpl {
 		byExt:     make(map[string]analysis.LanguageAnalyzer),
 	}
 
-	// Register default analyzers (using existing implementations)
+	// Register default analyzers (using existing implementations); TypeScript,
+	// Python and Rust symbols come from Tree-sitter when it is available
 	registry.Register(NewGoAnalyzer())
-	registry.Register(NewTypeScriptAnalyzer())
+	registry.Register(withTreeSitter(NewTypeScriptAnalyzer()))
 	registry.Register(NewJavaScriptAnalyzer())
 	registry.Register(NewJavaAnalyzer())
 	registry.Register(NewKotlinAnalyzer())
@@ -33,8 +34,8 @@ func NewAnalyzerRegistry() *AnalyzerRegistryImpl {
 	registry.Register(NewVueAnalyzer())
 	registry.Register(NewDartAnalyzer())
 	// New language analyzers
-	registry.Register(NewPythonAnalyzer())
-	registry.Register(NewRustAnalyzer())
+	registry.Register(withTreeSitter(NewPythonAnalyzer()))
+	registry.Register(withTreeSitter(NewRustAnalyzer()))
 	registry.Register(NewCSharpAnalyzer())
 
 	return registry
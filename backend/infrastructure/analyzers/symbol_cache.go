@@ -25,6 +25,20 @@ type CachedSymbolIndex struct {
 	mu          sync.Mutex
 }
 
+// cachedFile is the state of a file when its symbols were cached
+type cachedFile struct {
+	hash  string
+	mtime int64 // modification time in Unix nanoseconds, 0 if unknown
+	size  int64
+}
+
+// symbolCacheChanges lists how the project tree differs from the cache
+type symbolCacheChanges struct {
+	toIndex  []string         // new files and files whose content changed
+	toRemove []string         // cached files no longer in the tree
+	touched  map[string]int64 // files with a new mtime but unchanged content
+}
+
 // NewCachedSymbolIndex creates a symbol index with SQLite caching
 func NewCachedSymbolIndex(registry analysis.AnalyzerRegistry, cacheDir string) (*CachedSymbolIndex, error) {
 	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
@@ -56,6 +70,8 @@ func (idx *CachedSymbolIndex) initDB() error {
 	CREATE TABLE IF NOT EXISTS files (
 		path TEXT PRIMARY KEY,
 		hash TEXT NOT NULL,
+		mtime INTEGER NOT NULL DEFAULT 0,
+		size INTEGER NOT NULL DEFAULT 0,
 		indexed_at INTEGER NOT NULL
 	);
 	
@@ -79,50 +95,121 @@ func (idx *CachedSymbolIndex) initDB() error {
 	CREATE INDEX IF NOT EXISTS idx_symbols_kind ON symbols(kind);
 	CREATE INDEX IF NOT EXISTS idx_symbols_name_lower ON symbols(lower(name));
 	`
-	_, err := idx.db.Exec(schema)
-	return err
+	if _, err := idx.db.Exec(schema); err != nil {
+		return err
+	}
+	return idx.migrateDB()
+}
+
+// migrateDB adds the mtime and size columns to caches created before them;
+// their files are rehashed once on the next scan
+func (idx *CachedSymbolIndex) migrateDB() error {
+	rows, err := idx.db.Query("PRAGMA table_info(files)")
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err == nil {
+			columns[name] = true
+		}
+	}
+	rows.Close()
+
+	for _, column := range []string{"mtime", "size"} {
+		if columns[column] {
+			continue
+		}
+		if _, err := idx.db.Exec("ALTER TABLE files ADD COLUMN " + column + " INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// IndexProject indexes project with caching
+// IndexProject indexes project with caching. Only files whose content
+// changed since they were cached are re-indexed, and removed files are
+// dropped from the cache.
 func (idx *CachedSymbolIndex) IndexProject(ctx context.Context, projectRoot string) error {
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 
+	return idx.indexProjectLocked(ctx, projectRoot)
+}
+
+func (idx *CachedSymbolIndex) indexProjectLocked(ctx context.Context, projectRoot string) error {
 	idx.projectRoot = projectRoot
 	idx.SymbolIndexImpl.Clear()
 
-	cachedFiles := idx.loadCachedFileHashes()
-	filesToIndex, filesToRemove := idx.scanProjectFiles(projectRoot, cachedFiles)
+	changes := idx.scanProjectFiles(projectRoot, idx.loadCachedFiles())
 
-	idx.removeStaleFiles(filesToRemove)
-	idx.indexNewFiles(ctx, projectRoot, filesToIndex)
+	idx.removeStaleFiles(changes.toRemove)
+	idx.indexNewFiles(ctx, projectRoot, changes.toIndex)
+	idx.touchFiles(changes.touched)
 	idx.loadFromCache()
 
 	idx.indexed = true
 	return nil
 }
 
-// loadCachedFileHashes loads file hashes from cache
-func (idx *CachedSymbolIndex) loadCachedFileHashes() map[string]string {
-	cachedFiles := make(map[string]string)
-	rows, err := idx.db.Query("SELECT path, hash FROM files")
+// Refresh reconciles the index with the project tree after changes made
+// outside the app, such as a git checkout: changed files are re-indexed,
+// symbols of removed files are dropped, and files with an unchanged mtime
+// and size aren't read. An index of another project is rebuilt.
+func (idx *CachedSymbolIndex) Refresh(projectRoot string) error {
+	ctx := context.Background()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !idx.indexed || idx.projectRoot != projectRoot {
+		return idx.indexProjectLocked(ctx, projectRoot)
+	}
+
+	changes := idx.scanProjectFiles(projectRoot, idx.loadCachedFiles())
+	for _, relPath := range changes.toRemove {
+		idx.removeSymbolsForFile(relPath)
+		idx.removeFileFromCache(relPath)
+	}
+	for _, relPath := range changes.toIndex {
+		idx.removeSymbolsForFile(relPath)
+		symbols, err := idx.indexProjectFile(ctx, projectRoot, relPath)
+		if err != nil {
+			continue
+		}
+		for _, sym := range symbols {
+			idx.addSymbolLocked(sym)
+		}
+	}
+	idx.touchFiles(changes.touched)
+	return nil
+}
+
+// loadCachedFiles loads the cached state of all files
+func (idx *CachedSymbolIndex) loadCachedFiles() map[string]cachedFile {
+	cachedFiles := make(map[string]cachedFile)
+	rows, err := idx.db.Query("SELECT path, hash, mtime, size FROM files")
 	if err != nil {
 		return cachedFiles
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var path, hash string
-		if rows.Scan(&path, &hash) == nil {
-			cachedFiles[path] = hash
+		var path string
+		var file cachedFile
+		if rows.Scan(&path, &file.hash, &file.mtime, &file.size) == nil {
+			cachedFiles[path] = file
 		}
 	}
 	return cachedFiles
 }
 
 // scanProjectFiles scans project and determines what needs indexing
-func (idx *CachedSymbolIndex) scanProjectFiles(projectRoot string, cachedFiles map[string]string) ([]string, []string) {
-	var filesToIndex []string
+func (idx *CachedSymbolIndex) scanProjectFiles(projectRoot string, cachedFiles map[string]cachedFile) symbolCacheChanges {
+	changes := symbolCacheChanges{touched: make(map[string]int64)}
 	visited := make(map[string]bool)
+	scope := idx.languageScope()
 
 	_ = filepath.Walk(projectRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
@@ -131,26 +218,29 @@ func (idx *CachedSymbolIndex) scanProjectFiles(projectRoot string, cachedFiles m
 			}
 			return nil
 		}
-		if idx.registry.GetAnalyzer(path) == nil {
+		if !scope.FileEnabled(path) || idx.registry.GetAnalyzer(path) == nil {
 			return nil
 		}
 
 		relPath, _ := filepath.Rel(projectRoot, path)
 		visited[relPath] = true
 
-		if idx.needsReindex(path, relPath, cachedFiles) {
-			filesToIndex = append(filesToIndex, relPath)
+		cached, exists := cachedFiles[relPath]
+		reindex, touched := idx.needsReindex(path, info, cached, exists)
+		if reindex {
+			changes.toIndex = append(changes.toIndex, relPath)
+		} else if touched {
+			changes.touched[relPath] = info.ModTime().UnixNano()
 		}
 		return nil
 	})
 
-	filesToRemove := make([]string, 0, len(cachedFiles))
 	for path := range cachedFiles {
 		if !visited[path] {
-			filesToRemove = append(filesToRemove, path)
+			changes.toRemove = append(changes.toRemove, path)
 		}
 	}
-	return filesToIndex, filesToRemove
+	return changes
 }
 
 // shouldSkipDir checks if directory should be skipped
@@ -158,14 +248,27 @@ func (idx *CachedSymbolIndex) shouldSkipDir(name string) bool {
 	return strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || name == "build" || name == "dist"
 }
 
-// needsReindex checks if file needs reindexing
-func (idx *CachedSymbolIndex) needsReindex(fullPath, relPath string, cachedFiles map[string]string) bool {
+// needsReindex checks if file needs reindexing. A file with the cached mtime
+// and size isn't read; touched reports a new mtime with unchanged content.
+func (idx *CachedSymbolIndex) needsReindex(fullPath string, info os.FileInfo, cached cachedFile, exists bool) (reindex, touched bool) {
+	if exists && cached.mtime != 0 && cached.mtime == info.ModTime().UnixNano() && cached.size == info.Size() {
+		return false, false
+	}
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
-		return false
+		return false, false
+	}
+	if exists && cached.hash == hashContent(content) {
+		return false, true
+	}
+	return true, false
+}
+
+// touchFiles records the new mtimes of files whose content didn't change
+func (idx *CachedSymbolIndex) touchFiles(mtimes map[string]int64) {
+	for path, mtime := range mtimes {
+		_, _ = idx.db.Exec("UPDATE files SET mtime = ? WHERE path = ?", mtime, path)
 	}
-	cachedHash, exists := cachedFiles[relPath]
-	return !exists || cachedHash != hashContent(content)
 }
 
 // removeStaleFiles removes files no longer in project
@@ -178,21 +281,35 @@ func (idx *CachedSymbolIndex) removeStaleFiles(files []string) {
 // indexNewFiles indexes new or changed files
 func (idx *CachedSymbolIndex) indexNewFiles(ctx context.Context, projectRoot string, files []string) {
 	for _, relPath := range files {
-		if content, err := os.ReadFile(filepath.Join(projectRoot, relPath)); err == nil {
-			_ = idx.indexFileWithCache(ctx, relPath, content)
-		}
+		_, _ = idx.indexProjectFile(ctx, projectRoot, relPath)
+	}
+}
+
+// indexProjectFile caches the symbols of a project file along with its mtime
+func (idx *CachedSymbolIndex) indexProjectFile(ctx context.Context, projectRoot, relPath string) ([]analysis.Symbol, error) {
+	fullPath := filepath.Join(projectRoot, relPath)
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, err
 	}
+	return idx.indexFileWithCache(ctx, relPath, content, info.ModTime().UnixNano())
 }
 
-func (idx *CachedSymbolIndex) indexFileWithCache(ctx context.Context, filePath string, content []byte) error {
+// indexFileWithCache extracts a file's symbols and replaces its cached ones;
+// mtime 0 makes the next scan compare the file by hash
+func (idx *CachedSymbolIndex) indexFileWithCache(ctx context.Context, filePath string, content []byte, mtime int64) ([]analysis.Symbol, error) {
 	analyzer := idx.registry.GetAnalyzer(filePath)
 	if analyzer == nil {
-		return nil
+		return nil, nil
 	}
 
 	symbols, err := analyzer.ExtractSymbols(ctx, filePath, content)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	hash := hashContent(content)
@@ -200,7 +317,7 @@ func (idx *CachedSymbolIndex) indexFileWithCache(ctx context.Context, filePath s
 	// Begin transaction
 	tx, err := idx.db.Begin()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() { _ = tx.Rollback() }()
 
@@ -209,8 +326,8 @@ func (idx *CachedSymbolIndex) indexFileWithCache(ctx context.Context, filePath s
 	_, _ = tx.Exec("DELETE FROM files WHERE path = ?", filePath)
 
 	// Insert file record
-	_, _ = tx.Exec("INSERT INTO files (path, hash, indexed_at) VALUES (?, ?, ?)",
-		filePath, hash, time.Now().Unix())
+	_, _ = tx.Exec("INSERT INTO files (path, hash, mtime, size, indexed_at) VALUES (?, ?, ?, ?, ?)",
+		filePath, hash, mtime, len(content), time.Now().Unix())
 
 	// Insert symbols
 	stmt, err := tx.Prepare(`
@@ -218,7 +335,7 @@ func (idx *CachedSymbolIndex) indexFileWithCache(ctx context.Context, filePath s
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer stmt.Close()
 
@@ -233,7 +350,7 @@ func (idx *CachedSymbolIndex) indexFileWithCache(ctx context.Context, filePath s
 			sym.StartLine, sym.EndLine, sym.Signature, sym.DocComment, sym.Parent, extra)
 	}
 
-	return tx.Commit()
+	return symbols, tx.Commit()
 }
 
 func (idx *CachedSymbolIndex) removeFileFromCache(filePath string) {
@@ -268,6 +385,9 @@ func (idx *CachedSymbolIndex) loadFromCache() {
 
 // IndexFile indexes a single file with caching
 func (idx *CachedSymbolIndex) IndexFile(ctx context.Context, filePath string, content []byte) error {
+	if !idx.languageScope().FileEnabled(filePath) {
+		return nil
+	}
 	idx.mu.Lock()
 	defer idx.mu.Unlock()
 
@@ -275,21 +395,12 @@ func (idx *CachedSymbolIndex) IndexFile(ctx context.Context, filePath string, co
 	idx.removeSymbolsForFile(filePath)
 
 	// Index with cache
-	if err := idx.indexFileWithCache(ctx, filePath, content); err != nil {
-		return err
-	}
-
-	// Add to memory index
-	analyzer := idx.registry.GetAnalyzer(filePath)
-	if analyzer == nil {
-		return nil
-	}
-
-	symbols, err := analyzer.ExtractSymbols(ctx, filePath, content)
+	symbols, err := idx.indexFileWithCache(ctx, filePath, content, 0)
 	if err != nil {
 		return err
 	}
 
+	// Add to memory index
 	for _, sym := range symbols {
 		idx.addSymbolLocked(sym)
 	}
@@ -410,27 +521,27 @@ func (idx *CachedSymbolIndex) OnFileChanged(ctx context.Context, filePath string
 	}
 
 	// Check if file actually changed (compare hash)
-	cachedFiles := idx.loadCachedFileHashes()
-	currentHash := hashContent(content)
-	if cachedHash, exists := cachedFiles[relPath]; exists && cachedHash == currentHash {
+	var cachedHash string
+	if err := idx.db.QueryRow("SELECT hash FROM files WHERE path = ?", relPath).Scan(&cachedHash); err == nil && cachedHash == hashContent(content) {
 		// File hasn't changed
 		return nil
 	}
 
+	var mtime int64
+	if info, err := os.Stat(filePath); err == nil {
+		mtime = info.ModTime().UnixNano()
+	}
+
 	// Remove old symbols from memory index
 	idx.removeSymbolsForFile(relPath)
 
 	// Index with cache
-	if err := idx.indexFileWithCache(ctx, relPath, content); err != nil {
-		return err
-	}
-
-	// Add to memory index
-	symbols, err := analyzer.ExtractSymbols(ctx, relPath, content)
+	symbols, err := idx.indexFileWithCache(ctx, relPath, content, mtime)
 	if err != nil {
 		return err
 	}
 
+	// Add to memory index
 	for _, sym := range symbols {
 		idx.addSymbolLocked(sym)
 	}
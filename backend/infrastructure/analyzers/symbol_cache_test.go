@@ -2,9 +2,12 @@ package analyzers
 
 import (
 	"context"
+	"database/sql"
 	"os"
+	"path/filepath"
 	"shotgun_code/domain/analysis"
 	"testing"
+	"time"
 )
 
 func TestCachedSymbolIndex_RemoveSymbolsForFile(t *testing.T) {
@@ -339,6 +342,96 @@ func Unchanged() {}
 	}
 }
 
+func TestCachedSymbolIndex_RefreshAfterCheckout(t *testing.T) {
+	projectRoot := t.TempDir()
+	idx, err := NewCachedSymbolIndex(NewAnalyzerRegistry(), t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create cached index: %v", err)
+	}
+	defer idx.Close()
+
+	writeTestFile(t, projectRoot, "kept.go", "package main\n\nfunc Kept() {}\n")
+	writeTestFile(t, projectRoot, "changed.go", "package main\n\nfunc OldName() {}\n")
+	writeTestFile(t, projectRoot, "removed.go", "package main\n\nfunc Removed() {}\n")
+	if err := idx.IndexProject(context.Background(), projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+
+	// Simulate a branch switch done outside the app
+	writeTestFile(t, projectRoot, "changed.go", "package main\n\nfunc NewName() {}\n")
+	writeTestFile(t, projectRoot, "added.go", "package main\n\nfunc Added() {}\n")
+	if err := os.Remove(filepath.Join(projectRoot, "removed.go")); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Refresh(projectRoot); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	for name, expected := range map[string]int{"Kept": 1, "OldName": 0, "NewName": 1, "Removed": 0, "Added": 1} {
+		if got := len(idx.FindByExactName(name)); got != expected {
+			t.Errorf("expected %d symbols named %s after refresh, got %d", expected, name, got)
+		}
+	}
+	if stats := idx.GetCacheStats(); stats["cached_files"] != 3 {
+		t.Errorf("expected 3 cached files after refresh, got %d", stats["cached_files"])
+	}
+}
+
+func TestCachedSymbolIndex_TouchedFileKeepsSymbols(t *testing.T) {
+	projectRoot := t.TempDir()
+	idx, err := NewCachedSymbolIndex(NewAnalyzerRegistry(), t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create cached index: %v", err)
+	}
+	defer idx.Close()
+
+	writeTestFile(t, projectRoot, "main.go", "package main\n\nfunc Serve() {}\n")
+	if err := idx.IndexProject(context.Background(), projectRoot); err != nil {
+		t.Fatalf("IndexProject failed: %v", err)
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(projectRoot, "main.go"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	changes := idx.scanProjectFiles(projectRoot, idx.loadCachedFiles())
+	if len(changes.toIndex) != 0 || changes.touched["main.go"] != later.UnixNano() {
+		t.Fatalf("expected main.go touched but not re-indexed, got %+v", changes)
+	}
+
+	if err := idx.Refresh(projectRoot); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got := idx.loadCachedFiles()["main.go"].mtime; got != later.UnixNano() {
+		t.Errorf("expected the new mtime cached, got %d", got)
+	}
+	if got := len(idx.FindByExactName("Serve")); got != 1 {
+		t.Errorf("expected 1 symbol named Serve, got %d", got)
+	}
+}
+
+func TestCachedSymbolIndex_MigratesCacheWithoutMtime(t *testing.T) {
+	cacheDir := t.TempDir()
+	db, err := sql.Open("sqlite", filepath.Join(cacheDir, "symbols.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE files (path TEXT PRIMARY KEY, hash TEXT NOT NULL, indexed_at INTEGER NOT NULL);
+		INSERT INTO files (path, hash, indexed_at) VALUES ('main.go', 'abc', 1);`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	idx, err := NewCachedSymbolIndex(NewAnalyzerRegistry(), cacheDir)
+	if err != nil {
+		t.Fatalf("Failed to open an old cache: %v", err)
+	}
+	defer idx.Close()
+	if file := idx.loadCachedFiles()["main.go"]; file.hash != "abc" || file.mtime != 0 {
+		t.Errorf("expected the cached file kept with an unknown mtime, got %+v", file)
+	}
+}
+
 func writeSymbolTestFile(path string, content []byte) error {
 	return os.WriteFile(path, content, 0644)
 }
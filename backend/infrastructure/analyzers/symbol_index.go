@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"shotgun_code/domain"
 	"shotgun_code/domain/analysis"
 	"strings"
 	"sync"
@@ -18,6 +19,7 @@ type SymbolIndexImpl struct {
 	byKind   map[analysis.SymbolKind][]int
 	registry analysis.AnalyzerRegistry
 	indexed  bool
+	scope    *domain.LanguageScope // languages to index; nil means all
 
 	// Caching fields for one-time initialization
 	indexOnce    sync.Once
@@ -146,6 +148,9 @@ func (idx *SymbolIndexImpl) IndexProject(ctx context.Context, projectRoot string
 			return nil
 		}
 
+		if !idx.scope.FileEnabled(path) {
+			return nil
+		}
 		analyzer := idx.registry.GetAnalyzer(path)
 		if analyzer == nil {
 			return nil
@@ -172,7 +177,25 @@ func (idx *SymbolIndexImpl) IndexProject(ctx context.Context, projectRoot string
 	return err
 }
 
+// SetLanguageScope limits indexing to files of the enabled languages
+func (idx *SymbolIndexImpl) SetLanguageScope(scope *domain.LanguageScope) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.scope = scope
+}
+
+// languageScope returns the current language scope
+func (idx *SymbolIndexImpl) languageScope() *domain.LanguageScope {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.scope
+}
+
 func (idx *SymbolIndexImpl) IndexFile(ctx context.Context, filePath string, content []byte) error {
+	if !idx.languageScope().FileEnabled(filePath) {
+		return nil
+	}
+
 	analyzer := idx.registry.GetAnalyzer(filePath)
 	if analyzer == nil {
 		return nil
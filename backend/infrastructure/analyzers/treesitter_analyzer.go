@@ -0,0 +1,273 @@
+//go:build cgo
+
+package analyzers
+
+import (
+	"context"
+	"path/filepath"
+	"shotgun_code/domain/analysis"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/python"
+	"github.com/smacker/go-tree-sitter/rust"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+// TreeSitterAnalyzer extracts symbols from a Tree-sitter syntax tree and
+// delegates everything else (imports, exports, function bodies) to the regex
+// analyzer it wraps. Files that fail to parse fall back to the regex analyzer.
+type TreeSitterAnalyzer struct {
+	analysis.LanguageAnalyzer
+	grammar func(filePath string) *sitter.Language
+	walk    func(w *treeSitterWalker, node *sitter.Node, parent string)
+}
+
+// withTreeSitter wraps analyzer with Tree-sitter symbol extraction when a
+// grammar is available for its language
+func withTreeSitter(analyzer analysis.LanguageAnalyzer) analysis.LanguageAnalyzer {
+	switch analyzer.Language() {
+	case "typescript":
+		return &TreeSitterAnalyzer{LanguageAnalyzer: analyzer, grammar: typeScriptGrammar, walk: walkTypeScript}
+	case "python":
+		return &TreeSitterAnalyzer{LanguageAnalyzer: analyzer, grammar: func(string) *sitter.Language { return python.GetLanguage() }, walk: walkPython}
+	case "rust":
+		return &TreeSitterAnalyzer{LanguageAnalyzer: analyzer, grammar: func(string) *sitter.Language { return rust.GetLanguage() }, walk: walkRust}
+	default:
+		return analyzer
+	}
+}
+
+func typeScriptGrammar(filePath string) *sitter.Language {
+	if strings.EqualFold(filepath.Ext(filePath), ".tsx") {
+		return tsx.GetLanguage()
+	}
+	return typescript.GetLanguage()
+}
+
+// ExtractSymbols extracts symbols with Tree-sitter
+func (a *TreeSitterAnalyzer) ExtractSymbols(ctx context.Context, filePath string, content []byte) ([]analysis.Symbol, error) {
+	parser := sitter.NewParser()
+	defer parser.Close()
+	parser.SetLanguage(a.grammar(filePath))
+
+	tree, err := parser.ParseCtx(ctx, nil, content)
+	if err != nil || tree.RootNode().HasError() {
+		if tree != nil {
+			tree.Close()
+		}
+		return a.LanguageAnalyzer.ExtractSymbols(ctx, filePath, content)
+	}
+	defer tree.Close()
+
+	w := &treeSitterWalker{content: content, filePath: filePath, language: a.Language()}
+	a.walk(w, tree.RootNode(), "")
+	return w.symbols, nil
+}
+
+// treeSitterWalker collects symbols while walking a syntax tree
+type treeSitterWalker struct {
+	content  []byte
+	filePath string
+	language string
+	symbols  []analysis.Symbol
+}
+
+// add records the declaration node under the given name field. The symbol
+// starts on the line of its name so decorators and attributes don't shift it.
+func (w *treeSitterWalker) add(node *sitter.Node, name *sitter.Node, kind analysis.SymbolKind, parent string, modifiers []string) string {
+	if name == nil {
+		return ""
+	}
+	symbolName := name.Content(w.content)
+	start := int(name.StartPoint().Row) + 1
+	w.symbols = append(w.symbols, analysis.Symbol{
+		Name:      symbolName,
+		Kind:      kind,
+		Language:  w.language,
+		FilePath:  w.filePath,
+		Line:      start,
+		StartLine: start,
+		EndLine:   int(node.EndPoint().Row) + 1,
+		StartCol:  int(name.StartPoint().Column) + 1,
+		EndCol:    int(node.EndPoint().Column) + 1,
+		Parent:    parent,
+		Modifiers: modifiers,
+	})
+	return symbolName
+}
+
+// namedChildren iterates over the named children of node
+func namedChildren(node *sitter.Node) []*sitter.Node {
+	children := make([]*sitter.Node, 0, node.NamedChildCount())
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		children = append(children, node.NamedChild(i))
+	}
+	return children
+}
+
+// decoratorNames returns "@Name" for each decorator child of node
+func (w *treeSitterWalker) decoratorNames(node *sitter.Node) []string {
+	var names []string
+	for _, child := range namedChildren(node) {
+		if child.Type() == "decorator" {
+			names = append(names, w.decoratorName(child))
+		}
+	}
+	return names
+}
+
+// decoratorName returns "@Component" for @Component({...})
+func (w *treeSitterWalker) decoratorName(decorator *sitter.Node) string {
+	name := strings.TrimPrefix(strings.TrimSpace(decorator.Content(w.content)), "@")
+	if i := strings.IndexAny(name, "( \t\n"); i >= 0 {
+		name = name[:i]
+	}
+	return "@" + name
+}
+
+func walkTypeScript(w *treeSitterWalker, node *sitter.Node, parent string) {
+	for _, child := range namedChildren(node) {
+		switch child.Type() {
+		case "class_declaration", "abstract_class_declaration":
+			decorators := w.decoratorNames(child)
+			if node.Type() == "export_statement" {
+				// @Component() export class ... keeps the decorators on the export
+				decorators = append(w.decoratorNames(node), decorators...)
+			}
+			class := w.add(child, child.ChildByFieldName("name"), analysis.KindClass, parent, decorators)
+			if body := child.ChildByFieldName("body"); body != nil {
+				walkTypeScriptClass(w, body, class)
+			}
+		case "interface_declaration":
+			w.add(child, child.ChildByFieldName("name"), analysis.KindInterface, parent, nil)
+		case "type_alias_declaration":
+			w.add(child, child.ChildByFieldName("name"), analysis.KindType, parent, nil)
+		case "enum_declaration":
+			w.add(child, child.ChildByFieldName("name"), analysis.KindEnum, parent, nil)
+		case "function_declaration", "generator_function_declaration":
+			w.add(child, child.ChildByFieldName("name"), analysis.KindFunction, parent, nil)
+		case "lexical_declaration", "variable_declaration":
+			// const handler = () => {} declares a function
+			for _, decl := range namedChildren(child) {
+				if decl.Type() == "variable_declarator" && isFunctionValue(decl.ChildByFieldName("value")) {
+					w.add(decl, decl.ChildByFieldName("name"), analysis.KindFunction, parent, nil)
+				}
+			}
+		case "export_statement", "internal_module", "module", "statement_block", "ambient_declaration":
+			walkTypeScript(w, child, parent)
+		}
+	}
+}
+
+func walkTypeScriptClass(w *treeSitterWalker, body *sitter.Node, class string) {
+	// Method decorators are siblings that precede the method in the class body
+	var decorators []string
+	for _, member := range namedChildren(body) {
+		switch member.Type() {
+		case "decorator":
+			decorators = append(decorators, w.decoratorName(member))
+			continue
+		case "method_definition", "abstract_method_signature", "method_signature":
+			w.add(member, member.ChildByFieldName("name"), analysis.KindMethod, class, append(decorators, w.decoratorNames(member)...))
+		case "public_field_definition":
+			// handler = () => {} declares a method
+			kind := analysis.KindProperty
+			if isFunctionValue(member.ChildByFieldName("value")) {
+				kind = analysis.KindMethod
+			}
+			w.add(member, member.ChildByFieldName("name"), kind, class, append(decorators, w.decoratorNames(member)...))
+		}
+		decorators = nil
+	}
+}
+
+func isFunctionValue(value *sitter.Node) bool {
+	if value == nil {
+		return false
+	}
+	switch value.Type() {
+	case "arrow_function", "function", "function_expression", "generator_function":
+		return true
+	}
+	return false
+}
+
+func walkPython(w *treeSitterWalker, node *sitter.Node, parent string) {
+	for _, child := range namedChildren(node) {
+		definition, decorators := child, []string(nil)
+		if child.Type() == "decorated_definition" {
+			definition = child.ChildByFieldName("definition")
+			decorators = w.decoratorNames(child)
+			if definition == nil {
+				continue
+			}
+		}
+
+		switch definition.Type() {
+		case "class_definition":
+			class := w.add(definition, definition.ChildByFieldName("name"), analysis.KindClass, parent, decorators)
+			if body := definition.ChildByFieldName("body"); body != nil {
+				walkPython(w, body, class)
+			}
+		case "function_definition":
+			kind := analysis.KindFunction
+			if parent != "" {
+				kind = analysis.KindMethod
+			}
+			w.add(definition, definition.ChildByFieldName("name"), kind, parent, decorators)
+		case "if_statement", "try_statement", "block":
+			// if TYPE_CHECKING: / try: import ... blocks at module or class level
+			walkPython(w, definition, parent)
+		}
+	}
+}
+
+func walkRust(w *treeSitterWalker, node *sitter.Node, parent string) {
+	for _, child := range namedChildren(node) {
+		switch child.Type() {
+		case "function_item", "function_signature_item":
+			kind := analysis.KindFunction
+			if parent != "" {
+				kind = analysis.KindMethod
+			}
+			w.add(child, child.ChildByFieldName("name"), kind, parent, nil)
+		case "struct_item", "union_item":
+			w.add(child, child.ChildByFieldName("name"), analysis.KindStruct, parent, nil)
+		case "enum_item":
+			w.add(child, child.ChildByFieldName("name"), analysis.KindEnum, parent, nil)
+		case "trait_item":
+			trait := w.add(child, child.ChildByFieldName("name"), analysis.KindInterface, parent, nil)
+			if body := child.ChildByFieldName("body"); body != nil {
+				walkRust(w, body, trait)
+			}
+		case "type_item":
+			w.add(child, child.ChildByFieldName("name"), analysis.KindType, parent, nil)
+		case "const_item":
+			w.add(child, child.ChildByFieldName("name"), analysis.KindConstant, parent, nil)
+		case "static_item":
+			w.add(child, child.ChildByFieldName("name"), analysis.KindVariable, parent, nil)
+		case "mod_item":
+			module := w.add(child, child.ChildByFieldName("name"), analysis.KindModule, parent, nil)
+			if body := child.ChildByFieldName("body"); body != nil {
+				walkRust(w, body, module)
+			}
+		case "impl_item":
+			// Methods of impl Trait for Type belong to Type
+			if implType := child.ChildByFieldName("type"); implType != nil {
+				if body := child.ChildByFieldName("body"); body != nil {
+					walkRust(w, body, rustTypeName(implType.Content(w.content)))
+				}
+			}
+		}
+	}
+}
+
+// rustTypeName strips generic arguments: Cache<K, V> becomes Cache
+func rustTypeName(typ string) string {
+	if i := strings.Index(typ, "<"); i >= 0 {
+		typ = typ[:i]
+	}
+	return strings.TrimSpace(typ)
+}
@@ -0,0 +1,140 @@
+//go:build cgo
+
+package analyzers
+
+import (
+	"context"
+	"shotgun_code/domain/analysis"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findSymbol returns the symbol with name and parent, failing the test if it is missing
+func findSymbol(t *testing.T, symbols []analysis.Symbol, name, parent string) analysis.Symbol {
+	t.Helper()
+	for _, sym := range symbols {
+		if sym.Name == name && sym.Parent == parent {
+			return sym
+		}
+	}
+	require.Failf(t, "symbol not found", "%s (parent %q) in %+v", name, parent, symbols)
+	return analysis.Symbol{}
+}
+
+func TestTreeSitterAnalyzer_TypeScript(t *testing.T) {
+	src := `import { Component } from '@angular/core'
+
+@Component({ selector: 'app-root' })
+export class AppComponent {
+  title = 'app'
+
+  @Input()
+  set value(v: string) {}
+
+  handleClick = (event: MouseEvent) => {
+    console.log(event)
+  }
+
+  render(): string {
+    return this.title
+  }
+}
+
+export const formatName = (name: string): string => name.trim()
+`
+	analyzer := NewAnalyzerRegistry().GetAnalyzer("app.component.ts")
+	symbols, err := analyzer.ExtractSymbols(context.Background(), "app.component.ts", []byte(src))
+	require.NoError(t, err)
+
+	class := findSymbol(t, symbols, "AppComponent", "")
+	assert.Equal(t, analysis.KindClass, class.Kind)
+	assert.Equal(t, []string{"@Component"}, class.Modifiers)
+	assert.Equal(t, 4, class.StartLine)
+	assert.Equal(t, 17, class.EndLine)
+
+	handler := findSymbol(t, symbols, "handleClick", "AppComponent")
+	assert.Equal(t, analysis.KindMethod, handler.Kind, "arrow function fields are methods")
+	assert.Equal(t, 10, handler.StartLine)
+
+	setter := findSymbol(t, symbols, "value", "AppComponent")
+	assert.Equal(t, []string{"@Input"}, setter.Modifiers)
+
+	assert.Equal(t, analysis.KindProperty, findSymbol(t, symbols, "title", "AppComponent").Kind)
+	assert.Equal(t, analysis.KindMethod, findSymbol(t, symbols, "render", "AppComponent").Kind)
+	assert.Equal(t, analysis.KindFunction, findSymbol(t, symbols, "formatName", "").Kind)
+
+	// Imports still come from the regex analyzer
+	imports, err := analyzer.GetImports(context.Background(), "app.component.ts", []byte(src))
+	require.NoError(t, err)
+	require.Len(t, imports, 1)
+	assert.Equal(t, "@angular/core", imports[0].Path)
+}
+
+func TestTreeSitterAnalyzer_Python(t *testing.T) {
+	src := `class Repository:
+    @property
+    def name(self):
+        return "repo"
+
+    @staticmethod
+    def create():
+        return Repository()
+
+
+@app.route("/")
+def index():
+    pass
+`
+	symbols, err := NewAnalyzerRegistry().GetAnalyzer("app.py").ExtractSymbols(context.Background(), "app.py", []byte(src))
+	require.NoError(t, err)
+
+	assert.Equal(t, analysis.KindClass, findSymbol(t, symbols, "Repository", "").Kind)
+	name := findSymbol(t, symbols, "name", "Repository")
+	assert.Equal(t, analysis.KindMethod, name.Kind)
+	assert.Equal(t, []string{"@property"}, name.Modifiers)
+	assert.Equal(t, 3, name.StartLine)
+
+	index := findSymbol(t, symbols, "index", "")
+	assert.Equal(t, analysis.KindFunction, index.Kind)
+	assert.Equal(t, []string{"@app.route"}, index.Modifiers)
+}
+
+func TestTreeSitterAnalyzer_Rust(t *testing.T) {
+	src := `#[derive(Debug)]
+pub struct Cache<K, V> {
+    items: Vec<(K, V)>,
+}
+
+pub trait Store {
+    fn get(&self, key: &str) -> Option<String>;
+}
+
+impl<K, V> Cache<K, V> {
+    pub fn new() -> Self {
+        Cache { items: Vec::new() }
+    }
+}
+
+fn main() {}
+`
+	symbols, err := NewAnalyzerRegistry().GetAnalyzer("lib.rs").ExtractSymbols(context.Background(), "lib.rs", []byte(src))
+	require.NoError(t, err)
+
+	cache := findSymbol(t, symbols, "Cache", "")
+	assert.Equal(t, analysis.KindStruct, cache.Kind)
+	assert.Equal(t, 2, cache.StartLine)
+	assert.Equal(t, analysis.KindInterface, findSymbol(t, symbols, "Store", "").Kind)
+	assert.Equal(t, analysis.KindMethod, findSymbol(t, symbols, "get", "Store").Kind)
+	assert.Equal(t, analysis.KindMethod, findSymbol(t, symbols, "new", "Cache").Kind)
+	assert.Equal(t, analysis.KindFunction, findSymbol(t, symbols, "main", "").Kind)
+}
+
+func TestTreeSitterAnalyzer_FallsBackOnSyntaxErrors(t *testing.T) {
+	src := "export class Broken {\n  method( {\n}\n"
+	symbols, err := NewAnalyzerRegistry().GetAnalyzer("broken.ts").ExtractSymbols(context.Background(), "broken.ts", []byte(src))
+	require.NoError(t, err)
+
+	findSymbol(t, symbols, "Broken", "")
+}
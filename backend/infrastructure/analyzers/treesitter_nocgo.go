@@ -0,0 +1,11 @@
+//go:build !cgo
+
+package analyzers
+
+import "shotgun_code/domain/analysis"
+
+// withTreeSitter returns analyzer unchanged: Tree-sitter grammars need cgo,
+// so builds without it keep the regex analyzers
+func withTreeSitter(analyzer analysis.LanguageAnalyzer) analysis.LanguageAnalyzer {
+	return analyzer
+}
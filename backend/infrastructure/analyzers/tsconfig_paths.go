@@ -0,0 +1,216 @@
+package analyzers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxTSConfigExtendsDepth limits how many "extends" hops are followed
+const maxTSConfigExtendsDepth = 5
+
+// tsPathAlias is a single compilerOptions.paths mapping, e.g. "@core/*" -> ["libs/core/src/*"]
+type tsPathAlias struct {
+	prefix   string   // part of the pattern before "*"
+	suffix   string   // part of the pattern after "*"
+	wildcard bool     // whether the pattern contains "*"
+	targets  []string // project-relative targets, "*" is substituted
+}
+
+// tsConfigPaths holds the alias configuration of one tsconfig/jsconfig file
+type tsConfigPaths struct {
+	dir     string // directory of the config, relative to the project root
+	baseDir string // resolved baseUrl, relative to the project root ("" if unset)
+	aliases []tsPathAlias
+}
+
+// rawTSConfig is the subset of tsconfig.json used for import resolution
+type rawTSConfig struct {
+	Extends         string `json:"extends"`
+	CompilerOptions struct {
+		BaseURL *string             `json:"baseUrl"`
+		Paths   map[string][]string `json:"paths"`
+	} `json:"compilerOptions"`
+}
+
+// isTSConfigFile reports whether name is a tsconfig/jsconfig file
+func isTSConfigFile(name string) bool {
+	if name == "tsconfig.json" || name == "jsconfig.json" {
+		return true
+	}
+	return strings.HasPrefix(name, "tsconfig.") && strings.HasSuffix(name, ".json")
+}
+
+// loadTSConfigPaths reads baseUrl/paths from a tsconfig or jsconfig file,
+// following relative "extends" chains. Returns nil if nothing is configured.
+func loadTSConfigPaths(path, projectRoot string) *tsConfigPaths {
+	baseURL, baseURLDir, paths, pathsDir := readTSConfigChain(path, 0)
+	if baseURL == nil && len(paths) == 0 {
+		return nil
+	}
+
+	configDir, _ := filepath.Rel(projectRoot, filepath.Dir(path))
+	cfg := &tsConfigPaths{dir: filepath.Clean(configDir)}
+
+	// Paths are resolved relative to baseUrl, or to the declaring config without it
+	pathsBase := pathsDir
+	if baseURL != nil {
+		abs := filepath.Join(baseURLDir, *baseURL)
+		rel, err := filepath.Rel(projectRoot, abs)
+		if err == nil {
+			cfg.baseDir = filepath.Clean(rel)
+		}
+		pathsBase = abs
+	}
+
+	for pattern, targets := range paths {
+		alias := tsPathAlias{}
+		if idx := strings.Index(pattern, "*"); idx >= 0 {
+			alias.wildcard = true
+			alias.prefix = pattern[:idx]
+			alias.suffix = pattern[idx+1:]
+		} else {
+			alias.prefix = pattern
+		}
+		for _, target := range targets {
+			rel, err := filepath.Rel(projectRoot, filepath.Join(pathsBase, target))
+			if err != nil {
+				continue
+			}
+			alias.targets = append(alias.targets, filepath.ToSlash(rel))
+		}
+		cfg.aliases = append(cfg.aliases, alias)
+	}
+
+	// Longest prefix wins, as in the TypeScript compiler
+	sort.Slice(cfg.aliases, func(i, j int) bool {
+		return len(cfg.aliases[i].prefix) > len(cfg.aliases[j].prefix)
+	})
+	return cfg
+}
+
+// readTSConfigChain returns baseUrl and paths with the directories they are
+// relative to. Values in the extending config override the extended one.
+func readTSConfigChain(path string, depth int) (baseURL *string, baseURLDir string, paths map[string][]string, pathsDir string) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", nil, ""
+	}
+	var raw rawTSConfig
+	if err := json.Unmarshal([]byte(stripJSONComments(string(content))), &raw); err != nil {
+		return nil, "", nil, ""
+	}
+
+	dir := filepath.Dir(path)
+	if raw.Extends != "" && strings.HasPrefix(raw.Extends, ".") && depth < maxTSConfigExtendsDepth {
+		parent := filepath.Join(dir, raw.Extends)
+		if filepath.Ext(parent) != ".json" {
+			parent += ".json"
+		}
+		baseURL, baseURLDir, paths, pathsDir = readTSConfigChain(parent, depth+1)
+	}
+
+	if raw.CompilerOptions.BaseURL != nil {
+		baseURL, baseURLDir = raw.CompilerOptions.BaseURL, dir
+	}
+	if raw.CompilerOptions.Paths != nil {
+		paths, pathsDir = raw.CompilerOptions.Paths, dir
+	}
+	return baseURL, baseURLDir, paths, pathsDir
+}
+
+// candidates returns project-relative paths an import may refer to under this config
+func (c *tsConfigPaths) candidates(importPath string) []string {
+	for _, alias := range c.aliases {
+		if !alias.wildcard {
+			if importPath == alias.prefix {
+				return alias.targets
+			}
+			continue
+		}
+		if len(importPath) < len(alias.prefix)+len(alias.suffix) ||
+			!strings.HasPrefix(importPath, alias.prefix) || !strings.HasSuffix(importPath, alias.suffix) {
+			continue
+		}
+		matched := importPath[len(alias.prefix) : len(importPath)-len(alias.suffix)]
+		result := make([]string, 0, len(alias.targets))
+		for _, target := range alias.targets {
+			result = append(result, strings.Replace(target, "*", matched, 1))
+		}
+		return result
+	}
+
+	// Non-relative imports are also looked up from baseUrl
+	if c.baseDir != "" && !strings.HasPrefix(importPath, ".") {
+		return []string{filepath.ToSlash(filepath.Join(c.baseDir, importPath))}
+	}
+	return nil
+}
+
+// nearestTSConfig returns the config whose directory most closely contains relPath
+func nearestTSConfig(configs []*tsConfigPaths, relPath string) *tsConfigPaths {
+	var best *tsConfigPaths
+	for _, cfg := range configs {
+		if cfg.dir != "." && relPath != cfg.dir && !strings.HasPrefix(relPath, cfg.dir+string(filepath.Separator)) {
+			continue
+		}
+		if best == nil || len(cfg.dir) > len(best.dir) || (best.dir == "." && cfg.dir != ".") {
+			best = cfg
+		}
+	}
+	return best
+}
+
+// stripJSONComments removes // and /* */ comments and trailing commas,
+// which are allowed in tsconfig.json but not by encoding/json.
+func stripJSONComments(src string) string {
+	var sb strings.Builder
+	inString := false
+	for i := 0; i < len(src); i++ {
+		ch := src[i]
+		if inString {
+			sb.WriteByte(ch)
+			if ch == '\\' && i+1 < len(src) {
+				i++
+				sb.WriteByte(src[i])
+			} else if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case ch == '"':
+			inString = true
+			sb.WriteByte(ch)
+		case ch == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			if i < len(src) {
+				sb.WriteByte('\n')
+			}
+		case ch == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 3
+			}
+		case ch == ',':
+			// Drop trailing commas before a closing bracket
+			j := i + 1
+			for j < len(src) && strings.ContainsRune(" \t\r\n", rune(src[j])) {
+				j++
+			}
+			if j < len(src) && (src[j] == '}' || src[j] == ']') {
+				continue
+			}
+			sb.WriteByte(ch)
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package analyzers
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStripJSONComments(t *testing.T) {
+	input := `{
+  // line comment
+  "a": "http://x", /* block */
+  "b": [1, 2,],
+}`
+	expected := "{\n  \n  \"a\": \"http://x\", \n  \"b\": [1, 2]\n}"
+	if got := stripJSONComments(input); got != expected {
+		t.Errorf("unexpected result:\n%q\nwant:\n%q", got, expected)
+	}
+}
+
+func TestCallGraphBuilder_TSConfigPathAliases(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "tsconfig.base.json", `{
+  "compilerOptions": {
+    "baseUrl": ".",
+    // Monorepo aliases
+    "paths": {
+      "@core/*": ["libs/core/src/*"],
+      "@shared": ["libs/shared/index.ts"],
+    },
+  },
+}`)
+	writeTestFile(t, tmpDir, "apps/web/tsconfig.json", `{ "extends": "../../tsconfig.base.json" }`)
+	writeTestFile(t, tmpDir, "apps/web/main.ts", "import { a } from '@core/utils'\nimport { s } from '@shared'\nimport x from 'lodash'\n")
+	writeTestFile(t, tmpDir, "libs/core/src/utils/index.ts", "export const a = 1\n")
+	writeTestFile(t, tmpDir, "libs/shared/index.ts", "export const s = 1\n")
+
+	builder := NewCallGraphBuilder(NewAnalyzerRegistry())
+	depGraph, err := builder.BuildDependencyGraph(tmpDir, "typescript")
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+
+	node, ok := depGraph.Nodes[filepath.Join("apps", "web", "main.ts")]
+	if !ok {
+		t.Fatal("main.ts node not found")
+	}
+
+	expected := map[string]bool{
+		filepath.Join("libs", "core", "src", "utils", "index.ts"): true,
+		filepath.Join("libs", "shared", "index.ts"):               true,
+	}
+	if len(node.Dependencies) != len(expected) {
+		t.Fatalf("expected %d dependencies, got %v", len(expected), node.Dependencies)
+	}
+	for _, dep := range node.Dependencies {
+		if !expected[dep] {
+			t.Errorf("unexpected dependency %s", dep)
+		}
+	}
+}
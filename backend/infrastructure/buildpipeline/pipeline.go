@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"shotgun_code/domain"
 	"shotgun_code/infrastructure/sandbox"
+	"shotgun_code/internal/executil"
 	"strings"
 	"time"
 )
@@ -18,12 +19,14 @@ const (
 	langGo         = "go"
 	langTypeScript = "typescript"
 	langJava       = "java"
+	langPython     = "python"
 )
 
 // Impl реализует BuildPipeline
 type Impl struct {
 	log           domain.Logger
 	sandboxRunner domain.SandboxRunner
+	executor      *executil.Executor
 }
 
 // NewBuildPipeline создает новый build pipeline
@@ -34,8 +37,13 @@ func NewBuildPipeline(log domain.Logger) *Impl {
 	}
 }
 
+// SetExecutor подключает исполнитель команд с лимитами ресурсов из настроек
+func (p *Impl) SetExecutor(executor *executil.Executor) {
+	p.executor = executor
+}
+
 // Build выполняет сборку проекта
-func (p *Impl) Build(ctx context.Context, projectPath, language string) (*domain.BuildResult, error) {
+func (p *Impl) Build(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, error) {
 	p.log.Info(fmt.Sprintf("Building %s project at %s", language, projectPath))
 	startTime := time.Now()
 
@@ -43,11 +51,13 @@ func (p *Impl) Build(ctx context.Context, projectPath, language string) (*domain
 	var err error
 	switch language {
 	case langGo:
-		result, err = p.buildGo(ctx, projectPath)
+		result, err = p.buildGo(ctx, projectPath, target)
 	case langTypeScript, "ts":
-		result, err = p.buildTypeScript(ctx, projectPath)
+		result, err = p.buildTypeScript(ctx, projectPath, target.Clean)
 	case langJava:
-		result, err = p.buildJava(ctx, projectPath)
+		result, err = p.buildJava(ctx, projectPath, target.Clean)
+	case langPython:
+		result, err = p.buildPython(ctx, projectPath)
 	default:
 		return nil, fmt.Errorf("unsupported language: %s", language)
 	}
@@ -59,7 +69,7 @@ func (p *Impl) Build(ctx context.Context, projectPath, language string) (*domain
 }
 
 // TypeCheck выполняет проверку типов
-func (p *Impl) TypeCheck(ctx context.Context, projectPath, language string) (*domain.TypeCheckResult, error) {
+func (p *Impl) TypeCheck(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.TypeCheckResult, error) {
 	p.log.Info(fmt.Sprintf("Type checking %s project at %s", language, projectPath))
 	startTime := time.Now()
 
@@ -67,11 +77,13 @@ func (p *Impl) TypeCheck(ctx context.Context, projectPath, language string) (*do
 	var err error
 	switch language {
 	case langGo:
-		result, err = p.typeCheckGo(ctx, projectPath)
+		result, err = p.typeCheckGo(ctx, projectPath, target)
 	case langTypeScript, "ts":
 		result, err = p.typeCheckTypeScript(ctx, projectPath)
 	case langJava:
 		result, err = p.typeCheckJava(ctx, projectPath)
+	case langPython:
+		result, err = p.typeCheckPython(ctx, projectPath)
 	default:
 		return nil, fmt.Errorf("unsupported language: %s", language)
 	}
@@ -83,17 +95,17 @@ func (p *Impl) TypeCheck(ctx context.Context, projectPath, language string) (*do
 }
 
 // BuildAndTypeCheck выполняет сборку и проверку типов
-func (p *Impl) BuildAndTypeCheck(ctx context.Context, projectPath, language string) (*domain.BuildResult, *domain.TypeCheckResult, error) {
+func (p *Impl) BuildAndTypeCheck(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, *domain.TypeCheckResult, error) {
 	p.log.Info(fmt.Sprintf("Building and type checking %s project at %s", language, projectPath))
 
 	// Сначала выполняем проверку типов
-	typeCheckResult, err := p.TypeCheck(ctx, projectPath, language)
+	typeCheckResult, err := p.TypeCheck(ctx, projectPath, language, target)
 	if err != nil {
 		return nil, nil, fmt.Errorf("type check failed: %w", err)
 	}
 
 	// Затем выполняем сборку
-	buildResult, err := p.Build(ctx, projectPath, language)
+	buildResult, err := p.Build(ctx, projectPath, language, target)
 	if err != nil {
 		return nil, typeCheckResult, fmt.Errorf("build failed: %w", err)
 	}
@@ -108,7 +120,7 @@ func (p *Impl) BuildInSandbox(ctx context.Context, projectPath, language string,
 	// Проверяем доступность песочницы
 	if !p.sandboxRunner.IsAvailable(ctx) {
 		p.log.Warning("Sandbox not available, falling back to local build")
-		return p.Build(ctx, projectPath, language)
+		return p.Build(ctx, projectPath, language, domain.BuildTarget{})
 	}
 
 	// Определяем команду для сборки
@@ -182,14 +194,26 @@ func (p *Impl) BuildInSandbox(ctx context.Context, projectPath, language string,
 
 // GetSupportedLanguages возвращает поддерживаемые языки
 func (p *Impl) GetSupportedLanguages() []string {
-	return []string{"go", "typescript", "ts", "java"}
+	return []string{"go", "typescript", "ts", "java", "python"}
 }
 
 // buildGo выполняет сборку Go проекта
-func (p *Impl) buildGo(ctx context.Context, projectPath string) (*domain.BuildResult, error) {
+func (p *Impl) buildGo(ctx context.Context, projectPath string, target domain.BuildTarget) (*domain.BuildResult, error) {
 	result := &domain.BuildResult{
 		Language:    "go",
 		ProjectPath: projectPath,
+		Metadata:    goTargetMetadata(target),
+	}
+
+	// В рабочем пространстве go.work собираем каждый модуль
+	modules, err := goWorkspaceModules(projectPath)
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return result, nil
+	}
+	if len(modules) > 0 {
+		return p.buildGoWorkspace(ctx, projectPath, modules, target), nil
 	}
 
 	// Проверяем наличие go.mod
@@ -199,11 +223,18 @@ func (p *Impl) buildGo(ctx context.Context, projectPath string) (*domain.BuildRe
 		return result, nil
 	}
 
-	// Выполняем go build
-	cmd := exec.CommandContext(ctx, "go", "build", "-o", "shotgun.exe", ".")
+	// Выполняем go build; -v печатает пакеты, которые пришлось собрать, пустой
+	// вывод означает, что все взято из кэша. Clean удаляет прежний артефакт
+	if target.Clean {
+		_ = os.Remove(filepath.Join(projectPath, "shotgun.exe"))
+	}
+	args := []string{"-v", "-o", "shotgun.exe", "."}
+
+	cmd := p.executor.Command(ctx, "go", goArgs(target, "build", args...)...)
 	cmd.Dir = projectPath
+	withEnv(cmd, goEnv(target))
 
-	output, err := cmd.CombinedOutput()
+	output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	result.Output = string(output)
 
 	if err != nil {
@@ -213,6 +244,7 @@ func (p *Impl) buildGo(ctx context.Context, projectPath string) (*domain.BuildRe
 	}
 
 	result.Success = true
+	result.CacheHit = !target.Clean && goBuiltNothing(result.Output)
 
 	// Ищем артефакты
 	if _, err := os.Stat(filepath.Join(projectPath, "shotgun.exe")); err == nil {
@@ -223,16 +255,17 @@ func (p *Impl) buildGo(ctx context.Context, projectPath string) (*domain.BuildRe
 }
 
 // runTypeCheck is a helper for running type check commands
-func (p *Impl) runTypeCheck(ctx context.Context, projectPath, language string, cmdName string, cmdArgs []string, parseIssues func(string) []*domain.TypeIssue) (*domain.TypeCheckResult, error) {
+func (p *Impl) runTypeCheck(ctx context.Context, projectPath, language string, cmdName string, cmdArgs []string, env map[string]string, parseIssues func(string) []*domain.TypeIssue) (*domain.TypeCheckResult, error) {
 	result := &domain.TypeCheckResult{
 		Language:    language,
 		ProjectPath: projectPath,
 	}
 
-	cmd := exec.CommandContext(ctx, cmdName, cmdArgs...)
+	cmd := p.executor.Command(ctx, cmdName, cmdArgs...)
 	cmd.Dir = projectPath
+	withEnv(cmd, env)
 
-	output, err := cmd.CombinedOutput()
+	output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	result.Output = string(output)
 
 	if err != nil {
@@ -249,12 +282,77 @@ func (p *Impl) runTypeCheck(ctx context.Context, projectPath, language string, c
 }
 
 // typeCheckGo выполняет проверку типов Go проекта
-func (p *Impl) typeCheckGo(ctx context.Context, projectPath string) (*domain.TypeCheckResult, error) {
-	return p.runTypeCheck(ctx, projectPath, langGo, "go", []string{"vet", "./..."}, p.parseGoVetIssues)
+func (p *Impl) typeCheckGo(ctx context.Context, projectPath string, target domain.BuildTarget) (*domain.TypeCheckResult, error) {
+	modules, err := goWorkspaceModules(projectPath)
+	if err != nil {
+		return &domain.TypeCheckResult{Language: langGo, ProjectPath: projectPath, Error: err.Error()}, nil
+	}
+	if len(modules) > 0 {
+		return p.typeCheckGoWorkspace(ctx, projectPath, modules, target), nil
+	}
+
+	result, err := p.runTypeCheck(ctx, projectPath, langGo, "go", goArgs(target, "vet", "./..."), goEnv(target), p.parseGoVetIssues)
+	if result != nil {
+		result.Metadata = goTargetMetadata(target)
+	}
+	return result, err
+}
+
+// goBuiltNothing сообщает, что go build -v не собрал ни одного пакета
+func goBuiltNothing(output string) bool {
+	return strings.TrimSpace(output) == ""
+}
+
+// goArgs вставляет -tags после подкоманды go
+func goArgs(target domain.BuildTarget, subcommand string, args ...string) []string {
+	result := []string{subcommand}
+	if len(target.Tags) > 0 {
+		result = append(result, "-tags", strings.Join(target.Tags, ","))
+	}
+	return append(result, args...)
+}
+
+// goEnv возвращает переменные GOOS/GOARCH цели или nil, если платформа не задана
+func goEnv(target domain.BuildTarget) map[string]string {
+	if target.GOOS == "" && target.GOARCH == "" {
+		return nil
+	}
+	env := make(map[string]string, 2)
+	if target.GOOS != "" {
+		env["GOOS"] = target.GOOS
+	}
+	if target.GOARCH != "" {
+		env["GOARCH"] = target.GOARCH
+	}
+	return env
+}
+
+// withEnv добавляет переменные к окружению команды, заданному исполнителем
+func withEnv(cmd *exec.Cmd, env map[string]string) {
+	if len(env) == 0 {
+		return
+	}
+	base := cmd.Env
+	if base == nil {
+		base = os.Environ()
+	}
+	cmd.Env = executil.MergeEnv(base, env)
+}
+
+// goTargetMetadata описывает цель сборки в метаданных результата
+func goTargetMetadata(target domain.BuildTarget) map[string]interface{} {
+	if len(target.Tags) == 0 && target.GOOS == "" && target.GOARCH == "" {
+		return nil
+	}
+	return map[string]interface{}{
+		"tags":   target.Tags,
+		"goos":   target.GOOS,
+		"goarch": target.GOARCH,
+	}
 }
 
 // buildTypeScript выполняет сборку TypeScript проекта
-func (p *Impl) buildTypeScript(ctx context.Context, projectPath string) (*domain.BuildResult, error) {
+func (p *Impl) buildTypeScript(ctx context.Context, projectPath string, clean bool) (*domain.BuildResult, error) {
 	result := &domain.BuildResult{
 		Language:    langTypeScript,
 		ProjectPath: projectPath,
@@ -267,19 +365,24 @@ func (p *Impl) buildTypeScript(ctx context.Context, projectPath string) (*domain
 		return result, nil
 	}
 
+	// Инкрементальная сборка tsc хранит состояние в *.tsbuildinfo
+	if clean {
+		p.removeTSBuildInfo(projectPath)
+	}
+
 	// Выполняем npm run build или tsc
-	cmd := exec.CommandContext(ctx, "npm", "run", "build")
+	cmd := p.executor.Command(ctx, "npm", "run", "build")
 	cmd.Dir = projectPath
 
-	output, err := cmd.CombinedOutput()
+	output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	result.Output = string(output)
 
 	if err != nil {
 		// Пробуем tsc напрямую
-		cmd = exec.CommandContext(ctx, "npx", "tsc")
+		cmd = p.executor.Command(ctx, "npx", "tsc")
 		cmd.Dir = projectPath
 
-		output, err = cmd.CombinedOutput()
+		output, err = p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 		result.Output = string(output)
 
 		if err != nil {
@@ -301,13 +404,23 @@ func (p *Impl) buildTypeScript(ctx context.Context, projectPath string) (*domain
 	return result, nil
 }
 
+// removeTSBuildInfo удаляет файлы состояния инкрементальной сборки tsc в корне проекта
+func (p *Impl) removeTSBuildInfo(projectPath string) {
+	matches, _ := filepath.Glob(filepath.Join(projectPath, "*.tsbuildinfo"))
+	for _, match := range matches {
+		if err := os.Remove(match); err != nil {
+			p.log.Warning(fmt.Sprintf("Failed to remove %s: %v", match, err))
+		}
+	}
+}
+
 // typeCheckTypeScript выполняет проверку типов TypeScript проекта
 func (p *Impl) typeCheckTypeScript(ctx context.Context, projectPath string) (*domain.TypeCheckResult, error) {
-	return p.runTypeCheck(ctx, projectPath, langTypeScript, "npx", []string{"tsc", "--noEmit"}, p.parseTypeScriptIssues)
+	return p.runTypeCheck(ctx, projectPath, langTypeScript, "npx", []string{"tsc", "--noEmit"}, nil, p.parseTypeScriptIssues)
 }
 
 // buildJava выполняет сборку Java проекта
-func (p *Impl) buildJava(ctx context.Context, projectPath string) (*domain.BuildResult, error) {
+func (p *Impl) buildJava(ctx context.Context, projectPath string, clean bool) (*domain.BuildResult, error) {
 	result := &domain.BuildResult{
 		Language:    langJava,
 		ProjectPath: projectPath,
@@ -325,12 +438,12 @@ func (p *Impl) buildJava(ctx context.Context, projectPath string) (*domain.Build
 	// Проверяем наличие pom.xml или build.gradle
 	if _, err := os.Stat(filepath.Join(projectPath, "pom.xml")); err == nil {
 		// Maven проект
-		return p.buildMavenProject(ctx, projectPath)
+		return p.buildMavenProject(ctx, projectPath, clean)
 	}
 
 	if _, err := os.Stat(filepath.Join(projectPath, "build.gradle")); err == nil {
 		// Gradle проект
-		return p.buildGradleProject(ctx, projectPath)
+		return p.buildGradleProject(ctx, projectPath, clean)
 	}
 
 	err := fmt.Errorf("neither pom.xml nor build.gradle found")
@@ -357,10 +470,10 @@ func (p *Impl) typeCheckJava(ctx context.Context, projectPath string) (*domain.T
 	// Проверяем наличие pom.xml или build.gradle
 	if _, err := os.Stat(filepath.Join(projectPath, "pom.xml")); err == nil {
 		// Maven проект - компиляция вкл��чает проверку типов
-		cmd := exec.CommandContext(ctx, "mvn", "compile", "-q")
+		cmd := p.executor.Command(ctx, "mvn", "compile", "-q")
 		cmd.Dir = projectPath
 
-		output, err := cmd.CombinedOutput()
+		output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 		result.Output = string(output)
 
 		if err != nil {
@@ -381,10 +494,10 @@ func (p *Impl) typeCheckJava(ctx context.Context, projectPath string) (*domain.T
 		}
 	} else if _, err := os.Stat(filepath.Join(projectPath, "build.gradle")); err == nil {
 		// Gradle проект - компиляция включает проверку типов
-		cmd := exec.CommandContext(ctx, "gradle", "compileJava", "--quiet")
+		cmd := p.executor.Command(ctx, "gradle", "compileJava", "--quiet")
 		cmd.Dir = projectPath
 
-		output, err := cmd.CombinedOutput()
+		output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 		result.Output = string(output)
 
 		if err != nil {
@@ -539,10 +652,10 @@ func (p *Impl) buildJavaProject(ctx context.Context, projectPath, toolName strin
 		return result, err
 	}
 
-	cmd := exec.CommandContext(ctx, toolName, cmdArgs...)
+	cmd := p.executor.Command(ctx, toolName, cmdArgs...)
 	cmd.Dir = projectPath
 
-	output, err := cmd.CombinedOutput()
+	output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	result.Output = string(output)
 
 	if err != nil {
@@ -567,14 +680,22 @@ func (p *Impl) buildJavaProject(ctx context.Context, projectPath, toolName strin
 	return result, nil
 }
 
-// buildMavenProject выполняет сборку Maven проекта
-func (p *Impl) buildMavenProject(ctx context.Context, projectPath string) (*domain.BuildResult, error) {
-	return p.buildJavaProject(ctx, projectPath, "mvn", []string{"compile", "-q"}, "target/")
+// buildMavenProject выполняет сборку Maven проекта; без clean Maven компилирует инкрементально
+func (p *Impl) buildMavenProject(ctx context.Context, projectPath string, clean bool) (*domain.BuildResult, error) {
+	args := []string{"compile", "-q"}
+	if clean {
+		args = append([]string{"clean"}, args...)
+	}
+	return p.buildJavaProject(ctx, projectPath, "mvn", args, "target/")
 }
 
-// buildGradleProject выполняет сборку Gradle проекта
-func (p *Impl) buildGradleProject(ctx context.Context, projectPath string) (*domain.BuildResult, error) {
-	return p.buildJavaProject(ctx, projectPath, "gradle", []string{"build", "--quiet"}, "build/")
+// buildGradleProject выполняет сборку Gradle проекта; без clean Gradle пропускает актуальные задачи
+func (p *Impl) buildGradleProject(ctx context.Context, projectPath string, clean bool) (*domain.BuildResult, error) {
+	args := []string{"build", "--quiet"}
+	if clean {
+		args = append([]string{"clean"}, args...)
+	}
+	return p.buildJavaProject(ctx, projectPath, "gradle", args, "build/")
 }
 
 // hasJUnitTests проверяет наличие JUnit тестов
@@ -604,13 +725,13 @@ func (p *Impl) runJUnitTests(ctx context.Context, projectPath, buildTool string)
 
 	var cmd *exec.Cmd
 	if buildTool == "mvn" {
-		cmd = exec.CommandContext(ctx, "mvn", "test", "-q")
+		cmd = p.executor.Command(ctx, "mvn", "test", "-q")
 	} else {
-		cmd = exec.CommandContext(ctx, "gradle", "test", "--quiet")
+		cmd = p.executor.Command(ctx, "gradle", "test", "--quiet")
 	}
 	cmd.Dir = projectPath
 
-	output, err := cmd.CombinedOutput()
+	output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	result.Output = string(output)
 
 	if err != nil {
@@ -651,10 +772,10 @@ func (p *Impl) runErrorProne(ctx context.Context, projectPath, buildTool string)
 	}
 
 	// Запускаем ErrorProne анализ
-	cmd := exec.CommandContext(ctx, "mvn", "compile", "-Perror-prone")
+	cmd := p.executor.Command(ctx, "mvn", "compile", "-Perror-prone")
 	cmd.Dir = projectPath
 
-	output, err := cmd.CombinedOutput()
+	output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	result.Output = string(output)
 
 	if err != nil {
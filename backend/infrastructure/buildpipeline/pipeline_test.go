@@ -0,0 +1,221 @@
+package buildpipeline
+
+import (
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"testing"
+)
+
+func TestTypeCheckGo_HonorsTarget(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/target\n\ngo 1.21\n",
+		"main.go":         "package main\n\nfunc main() { platform() }\n",
+		"main_other.go":   "//go:build !windows\n\npackage main\n\nfunc platform() {}\n",
+		"main_windows.go": "//go:build windows\n\npackage main\n\nfunc platform() { undefinedCall() }\n",
+		"extra.go":        "//go:build extra\n\npackage main\n\nvar _ = missingSymbol\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := NewBuildPipeline(&domain.NoopLogger{})
+
+	result, err := p.TypeCheck(t.Context(), dir, langGo, domain.BuildTarget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success {
+		t.Fatalf("expected the host build to pass, got: %s", result.Output)
+	}
+
+	result, err = p.TypeCheck(t.Context(), dir, langGo, domain.BuildTarget{GOOS: "windows", GOARCH: "amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Success {
+		t.Error("expected the windows-only file to be checked")
+	}
+	if result.Metadata["goos"] != "windows" {
+		t.Errorf("expected the target in metadata, got %v", result.Metadata)
+	}
+
+	result, err = p.TypeCheck(t.Context(), dir, langGo, domain.BuildTarget{Tags: []string{"extra"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Success {
+		t.Error("expected the tagged file to be checked")
+	}
+}
+
+func TestBuildGo_ReportsCacheHit(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/cache\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewBuildPipeline(&domain.NoopLogger{})
+
+	first, err := p.Build(t.Context(), dir, langGo, domain.BuildTarget{})
+	if err != nil || !first.Success {
+		t.Fatalf("first build failed: %v %s", err, first.Output)
+	}
+
+	second, err := p.Build(t.Context(), dir, langGo, domain.BuildTarget{})
+	if err != nil || !second.Success {
+		t.Fatalf("second build failed: %v %s", err, second.Output)
+	}
+	if !second.CacheHit {
+		t.Error("expected an unchanged project to build from the cache")
+	}
+
+	clean, err := p.Build(t.Context(), dir, langGo, domain.BuildTarget{Clean: true})
+	if err != nil || !clean.Success {
+		t.Fatalf("clean build failed: %v %s", err, clean.Output)
+	}
+	if clean.CacheHit {
+		t.Error("expected a clean build not to report a cache hit")
+	}
+}
+
+func TestGoWorkspace_BuildsEveryModule(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.work":      "go 1.21\n\nuse (\n\t./api\n\t./lib\n)\n",
+		"api/go.mod":   "module example.com/api\n\ngo 1.21\n",
+		"api/main.go":  "package main\n\nfunc main() {}\n",
+		"lib/go.mod":   "module example.com/lib\n\ngo 1.21\n",
+		"lib/lib.go":   "package lib\n\nfunc Value() int { return 1 }\n",
+		"lib/inner.go": "package lib\n\nvar _ = missingSymbol\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := NewBuildPipeline(&domain.NoopLogger{})
+
+	build, err := p.Build(t.Context(), dir, langGo, domain.BuildTarget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build.Success {
+		t.Error("expected the broken module to fail the workspace build")
+	}
+	if len(build.Modules) != 2 {
+		t.Fatalf("expected results for both modules, got %v", build.Modules)
+	}
+	if !build.Modules["example.com/api"].Success {
+		t.Errorf("expected example.com/api to build, got: %s", build.Modules["example.com/api"].Output)
+	}
+	if build.Modules["example.com/lib"].Success {
+		t.Error("expected example.com/lib to fail")
+	}
+
+	typeCheck, err := p.TypeCheck(t.Context(), dir, langGo, domain.BuildTarget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typeCheck.Success || len(typeCheck.Modules) != 2 {
+		t.Errorf("expected a failed type check covering both modules, got success=%v modules=%d", typeCheck.Success, len(typeCheck.Modules))
+	}
+	if !typeCheck.Modules["example.com/api"].Success {
+		t.Errorf("expected example.com/api to pass type check, got: %s", typeCheck.Modules["example.com/api"].Output)
+	}
+}
+
+func TestParsePyrightOutput(t *testing.T) {
+	output := []byte(`{
+  "version": "1.1.380",
+  "generalDiagnostics": [
+    {
+      "file": "/proj/app/main.py",
+      "severity": "error",
+      "message": "Type \"str\" is not assignable to declared type \"int\"",
+      "rule": "reportAssignmentType",
+      "range": {"start": {"line": 4, "character": 8}, "end": {"line": 4, "character": 13}}
+    },
+    {
+      "file": "/proj/app/util.py",
+      "severity": "warning",
+      "message": "Import \"yaml\" could not be resolved from source",
+      "range": {"start": {"line": 0, "character": 7}, "end": {"line": 0, "character": 11}}
+    }
+  ],
+  "summary": {"filesAnalyzed": 2, "errorCount": 1, "warningCount": 1, "informationCount": 0}
+}`)
+
+	report, err := parsePyrightOutput(output, "/proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.errorCount != 1 || report.warningCount != 1 || report.filesAnalyzed != 2 {
+		t.Errorf("unexpected summary: %+v", report)
+	}
+	if len(report.issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(report.issues))
+	}
+	first := report.issues[0]
+	if first.File != "app/main.py" || first.Line != 5 || first.Column != 9 || first.Code != "reportAssignmentType" || first.Severity != "error" {
+		t.Errorf("unexpected issue: %+v", first)
+	}
+
+	if _, err := parsePyrightOutput([]byte("npm ERR! not found"), "/proj"); err == nil {
+		t.Error("expected an error for non-JSON output")
+	}
+}
+
+func TestTypeCheckPython_RequiresProjectFiles(t *testing.T) {
+	p := NewBuildPipeline(&domain.NoopLogger{})
+
+	result, err := p.TypeCheck(t.Context(), t.TempDir(), langPython, domain.BuildTarget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Success || result.Error == "" {
+		t.Errorf("expected a failed result without pyproject.toml, got %+v", result)
+	}
+}
+
+func TestBuildPython_KeepsBytecodeOutOfProject(t *testing.T) {
+	if _, err := pythonCommand(); err != nil {
+		t.Skip(err)
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"requirements.txt": "",
+		"pkg/app.py":       "def main():\n    return 1\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := NewBuildPipeline(&domain.NoopLogger{}).Build(t.Context(), dir, langPython, domain.BuildTarget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success {
+		t.Fatalf("expected build to pass, got %s: %s", result.Error, result.Output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pkg", "__pycache__")); !os.IsNotExist(err) {
+		t.Errorf("expected no __pycache__ in the project, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package buildpipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"shotgun_code/domain"
+	"strings"
+)
+
+// pythonProjectFiles - файлы, по которым определяется Python проект
+var pythonProjectFiles = []string{"pyproject.toml", "requirements.txt", "setup.py", "setup.cfg"}
+
+// pythonExcludeDirs - каталоги, которые не компилируются при сборке
+const pythonExcludeDirs = `(^|[/\\])(\.venv|venv|env|\.tox|node_modules|\.git|build|dist)[/\\]`
+
+// pyrightOutput - отчет pyright --outputjson
+type pyrightOutput struct {
+	GeneralDiagnostics []pyrightDiagnostic `json:"generalDiagnostics"`
+	Summary            struct {
+		FilesAnalyzed int `json:"filesAnalyzed"`
+		ErrorCount    int `json:"errorCount"`
+		WarningCount  int `json:"warningCount"`
+	} `json:"summary"`
+}
+
+// pyrightDiagnostic - одно замечание pyright; строки и столбцы считаются с нуля
+type pyrightDiagnostic struct {
+	File     string `json:"file"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Rule     string `json:"rule"`
+	Range    struct {
+		Start struct {
+			Line      int `json:"line"`
+			Character int `json:"character"`
+		} `json:"start"`
+	} `json:"range"`
+}
+
+// isPythonProject проверяет наличие файлов Python проекта в корне
+func isPythonProject(projectPath string) bool {
+	for _, name := range pythonProjectFiles {
+		if _, err := os.Stat(filepath.Join(projectPath, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// buildPython компилирует исходники в байткод, чтобы найти синтаксические ошибки
+func (p *Impl) buildPython(ctx context.Context, projectPath string) (*domain.BuildResult, error) {
+	result := &domain.BuildResult{
+		Language:    langPython,
+		ProjectPath: projectPath,
+	}
+
+	if !isPythonProject(projectPath) {
+		result.Error = "pyproject.toml or requirements.txt not found"
+		return result, nil
+	}
+
+	python, err := pythonCommand()
+	if err != nil {
+		result.Error = err.Error()
+		result.Warnings = append(result.Warnings, "python interpreter not available")
+		return result, nil
+	}
+
+	// Байткод пишется во временный каталог, чтобы не создавать __pycache__ в проекте
+	cacheDir, err := os.MkdirTemp("", "shotgun-pycache-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bytecode cache dir: %w", err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	cmd := p.executor.Command(ctx, python, "-X", "pycache_prefix="+cacheDir, "-m", "compileall", "-q", "-x", pythonExcludeDirs, ".")
+	cmd.Dir = projectPath
+
+	output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
+	result.Output = string(output)
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+// typeCheckPython выполняет проверку типов Python проекта через pyright
+func (p *Impl) typeCheckPython(ctx context.Context, projectPath string) (*domain.TypeCheckResult, error) {
+	result := &domain.TypeCheckResult{
+		Language:    langPython,
+		ProjectPath: projectPath,
+	}
+
+	if !isPythonProject(projectPath) {
+		result.Error = "pyproject.toml or requirements.txt not found"
+		return result, nil
+	}
+
+	pyright, err := lookupPythonTool("pyright")
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	cmd := p.executor.Command(ctx, pyright, "--outputjson")
+	cmd.Dir = projectPath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// pyright завершается с кодом 1, если нашел ошибки, поэтому результат определяется по отчету
+	output, runErr := cmd.Output()
+	result.Output = string(output)
+
+	report, err := parsePyrightOutput(output, projectPath)
+	if err != nil {
+		result.Error = err.Error()
+		if runErr != nil {
+			result.Error = fmt.Sprintf("%v: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+		return result, nil
+	}
+
+	result.Issues = report.issues
+	result.Metadata = map[string]interface{}{
+		"filesAnalyzed": report.filesAnalyzed,
+		"errorCount":    report.errorCount,
+		"warningCount":  report.warningCount,
+	}
+	result.Success = report.errorCount == 0
+	if !result.Success {
+		result.Error = fmt.Sprintf("pyright found %d errors", report.errorCount)
+	}
+	return result, nil
+}
+
+// pyrightReport - разобранный отчет pyright
+type pyrightReport struct {
+	issues        []*domain.TypeIssue
+	filesAnalyzed int
+	errorCount    int
+	warningCount  int
+}
+
+// parsePyrightOutput разбирает JSON отчет pyright; пути файлов делаются относительными к проекту
+func parsePyrightOutput(output []byte, projectPath string) (*pyrightReport, error) {
+	var parsed pyrightOutput
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse pyright output: %w", err)
+	}
+
+	report := &pyrightReport{
+		filesAnalyzed: parsed.Summary.FilesAnalyzed,
+		errorCount:    parsed.Summary.ErrorCount,
+		warningCount:  parsed.Summary.WarningCount,
+	}
+	for _, diagnostic := range parsed.GeneralDiagnostics {
+		file := diagnostic.File
+		if rel, err := filepath.Rel(projectPath, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = filepath.ToSlash(rel)
+		}
+		report.issues = append(report.issues, &domain.TypeIssue{
+			File:     file,
+			Line:     diagnostic.Range.Start.Line + 1,
+			Column:   diagnostic.Range.Start.Character + 1,
+			Severity: diagnostic.Severity,
+			Message:  diagnostic.Message,
+			Code:     diagnostic.Rule,
+		})
+	}
+	return report, nil
+}
+
+// pythonCommand возвращает путь к интерпретатору Python: python3 или python
+func pythonCommand() (string, error) {
+	if path, err := lookupPythonTool("python3"); err == nil {
+		return path, nil
+	}
+	return lookupPythonTool("python")
+}
+
+// lookupPythonTool ищет установленный инструмент в PATH. Инструменты не
+// устанавливаются автоматически (например, через npx), чтобы проверка не
+// скачивала пакеты из сети
+func lookupPythonTool(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("%s not found in PATH", name)
+	}
+	return path, nil
+}
@@ -0,0 +1,209 @@
+package buildpipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/modfile"
+)
+
+// goWorkspaceModule - модуль из директивы use файла go.work
+type goWorkspaceModule struct {
+	Path string // путь модуля из его go.mod
+	Dir  string // абсолютный каталог модуля
+}
+
+// goWorkspaceModules читает go.work в корне проекта и возвращает его модули,
+// отсортированные по пути. Если go.work нет, возвращает nil
+func goWorkspaceModules(projectPath string) ([]goWorkspaceModule, error) {
+	workPath := filepath.Join(projectPath, "go.work")
+	data, err := os.ReadFile(workPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.work: %w", err)
+	}
+
+	work, err := modfile.ParseWork(workPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.work: %w", err)
+	}
+
+	modules := make([]goWorkspaceModule, 0, len(work.Use))
+	for _, use := range work.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(projectPath, dir)
+		}
+		modules = append(modules, goWorkspaceModule{Path: goModulePath(projectPath, dir), Dir: dir})
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Path < modules[j].Path })
+	return modules, nil
+}
+
+// goModulePath возвращает путь модуля из go.mod, а если его не удалось
+// прочитать - каталог модуля относительно корня рабочего пространства
+func goModulePath(projectPath, dir string) string {
+	if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		if modulePath := modfile.ModulePath(data); modulePath != "" {
+			return modulePath
+		}
+	}
+	if rel, err := filepath.Rel(projectPath, dir); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(dir)
+}
+
+// buildGoWorkspace собирает каждый модуль рабочего пространства и объединяет результаты
+func (p *Impl) buildGoWorkspace(ctx context.Context, projectPath string, modules []goWorkspaceModule, target domain.BuildTarget) *domain.BuildResult {
+	result := &domain.BuildResult{
+		Success:     true,
+		Language:    langGo,
+		ProjectPath: projectPath,
+		CacheHit:    !target.Clean,
+		Metadata:    goTargetMetadata(target),
+		Modules:     make(map[string]*domain.BuildResult, len(modules)),
+	}
+
+	var output strings.Builder
+	var failed []string
+	for _, module := range modules {
+		moduleResult := p.buildGoModule(ctx, module.Dir, target)
+		result.Modules[module.Path] = moduleResult
+		result.CacheHit = result.CacheHit && moduleResult.CacheHit
+		if !moduleResult.Success {
+			result.Success = false
+			failed = append(failed, module.Path)
+		}
+		writeModuleOutput(&output, module.Path, moduleResult.Output)
+	}
+
+	result.Output = output.String()
+	if len(failed) > 0 {
+		result.Error = fmt.Sprintf("build failed for modules: %s", strings.Join(failed, ", "))
+	}
+	return result
+}
+
+// buildGoModule собирает все пакеты одного модуля рабочего пространства
+func (p *Impl) buildGoModule(ctx context.Context, moduleDir string, target domain.BuildTarget) *domain.BuildResult {
+	startTime := time.Now()
+	result := &domain.BuildResult{Language: langGo, ProjectPath: moduleDir}
+
+	if _, err := os.Stat(filepath.Join(moduleDir, "go.mod")); os.IsNotExist(err) {
+		result.Error = "go.mod not found"
+		return result
+	}
+
+	// -v печатает собранные пакеты, пустой вывод означает сборку из кэша
+	env := p.goWorkspaceEnv(target)
+	cmd := p.executor.Command(ctx, "go", goArgs(target, "build", "-v", "./...")...)
+	cmd.Dir = moduleDir
+	withEnv(cmd, env)
+
+	output, err := p.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
+	result.Output = string(output)
+	result.Duration = time.Since(startTime).Seconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Success = true
+	result.CacheHit = !target.Clean && goBuiltNothing(result.Output)
+	return result
+}
+
+// typeCheckGoWorkspace проверяет типы в каждом модуле рабочего пространства и объединяет результаты.
+// Пути файлов в объединенных замечаниях указываются относительно корня рабочего пространства
+func (p *Impl) typeCheckGoWorkspace(ctx context.Context, projectPath string, modules []goWorkspaceModule, target domain.BuildTarget) *domain.TypeCheckResult {
+	result := &domain.TypeCheckResult{
+		Success:     true,
+		Language:    langGo,
+		ProjectPath: projectPath,
+		Metadata:    goTargetMetadata(target),
+		Modules:     make(map[string]*domain.TypeCheckResult, len(modules)),
+	}
+
+	var output strings.Builder
+	var failed []string
+	for _, module := range modules {
+		startTime := time.Now()
+		moduleResult, _ := p.runTypeCheck(ctx, module.Dir, langGo, "go", goArgs(target, "vet", "./..."), p.goWorkspaceEnv(target), p.parseGoVetIssues)
+		moduleResult.Duration = time.Since(startTime).Seconds()
+		result.Modules[module.Path] = moduleResult
+
+		if !moduleResult.Success {
+			result.Success = false
+			failed = append(failed, module.Path)
+		}
+		for _, issue := range moduleResult.Issues {
+			workspaceIssue := *issue
+			if !filepath.IsAbs(issue.File) {
+				if rel, err := filepath.Rel(projectPath, filepath.Join(module.Dir, issue.File)); err == nil {
+					workspaceIssue.File = filepath.ToSlash(rel)
+				}
+			}
+			result.Issues = append(result.Issues, &workspaceIssue)
+		}
+		writeModuleOutput(&output, module.Path, moduleResult.Output)
+	}
+
+	result.Output = output.String()
+	if len(failed) > 0 {
+		result.Error = fmt.Sprintf("type check failed for modules: %s", strings.Join(failed, ", "))
+	}
+	return result
+}
+
+// goWorkspaceEnv возвращает окружение для сборки модуля рабочего пространства:
+// go отказывается работать в режиме go.work с -mod=mod, поэтому флаг убирается из GOFLAGS
+func (p *Impl) goWorkspaceEnv(target domain.BuildTarget) map[string]string {
+	env := goEnv(target)
+	goflags := lookupEnv(p.executor.Environ(), "GOFLAGS")
+	if !strings.Contains(goflags, "-mod=mod") {
+		return env
+	}
+	if env == nil {
+		env = make(map[string]string, 1)
+	}
+	flags := strings.Fields(goflags)
+	kept := flags[:0]
+	for _, flag := range flags {
+		if flag != "-mod=mod" {
+			kept = append(kept, flag)
+		}
+	}
+	env["GOFLAGS"] = strings.Join(kept, " ")
+	return env
+}
+
+// lookupEnv возвращает значение переменной из окружения вида "KEY=value"
+func lookupEnv(environ []string, key string) string {
+	value := ""
+	for _, entry := range environ {
+		if k, v, ok := strings.Cut(entry, "="); ok && k == key {
+			value = v
+		}
+	}
+	return value
+}
+
+// writeModuleOutput добавляет вывод модуля в общий вывод под заголовком с его путем
+func writeModuleOutput(output *strings.Builder, modulePath, moduleOutput string) {
+	if strings.TrimSpace(moduleOutput) == "" {
+		return
+	}
+	fmt.Fprintf(output, "== %s ==\n%s", modulePath, moduleOutput)
+	if !strings.HasSuffix(moduleOutput, "\n") {
+		output.WriteString("\n")
+	}
+}
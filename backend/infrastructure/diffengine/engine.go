@@ -262,11 +262,12 @@ func (e *DiffEngineImpl) createDiffEntries(ctx context.Context, beforePath, afte
 	beforeExists := false
 	afterExists := false
 
-	if _, err := os.Stat(beforePath); err == nil {
+	// os.DevNull, как и в git diff, обозначает отсутствующую сторону
+	if _, err := os.Stat(beforePath); err == nil && beforePath != os.DevNull {
 		beforeExists = true
 	}
 
-	if _, err := os.Stat(afterPath); err == nil {
+	if _, err := os.Stat(afterPath); err == nil && afterPath != os.DevNull {
 		afterExists = true
 	}
 
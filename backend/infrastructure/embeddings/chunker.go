@@ -12,16 +12,18 @@ import (
 
 // ChunkerConfig configuration for code chunking
 type ChunkerConfig struct {
-	MaxChunkTokens int  `json:"maxChunkTokens"`
-	MinChunkTokens int  `json:"minChunkTokens"`
-	OverlapTokens  int  `json:"overlapTokens"`
-	PreferSymbols  bool `json:"preferSymbols"`  // prefer function/class boundaries
-	IncludeContext bool `json:"includeContext"` // include surrounding context
+	Strategy       string `json:"strategy"` // domain.ChunkStrategy*; empty uses PreferSymbols
+	MaxChunkTokens int    `json:"maxChunkTokens"`
+	MinChunkTokens int    `json:"minChunkTokens"`
+	OverlapTokens  int    `json:"overlapTokens"`
+	PreferSymbols  bool   `json:"preferSymbols"`  // prefer function/class boundaries
+	IncludeContext bool   `json:"includeContext"` // include surrounding context
 }
 
 // DefaultChunkerConfig returns default chunking configuration
 func DefaultChunkerConfig() ChunkerConfig {
 	return ChunkerConfig{
+		Strategy:       domain.ChunkStrategySymbol,
 		MaxChunkTokens: 512,
 		MinChunkTokens: 50,
 		OverlapTokens:  50,
@@ -40,18 +42,60 @@ func NewCodeChunker(config ChunkerConfig) *CodeChunker {
 	return &CodeChunker{config: config}
 }
 
-// ChunkFile splits a file into chunks
+// Configure applies the strategy, max tokens and overlap of config; zero
+// fields keep the current values
+func (c *CodeChunker) Configure(config domain.ChunkerConfig) error {
+	switch config.Strategy {
+	case "", domain.ChunkStrategySymbol, domain.ChunkStrategyWindow, domain.ChunkStrategyHybrid:
+	default:
+		return fmt.Errorf("unknown chunking strategy %q: use symbol, window or hybrid", config.Strategy)
+	}
+	if config.MaxTokens < 0 || config.Overlap < 0 {
+		return fmt.Errorf("chunk max tokens and overlap must not be negative")
+	}
+
+	updated := c.config
+	if config.Strategy != "" {
+		updated.Strategy = config.Strategy
+	}
+	if config.MaxTokens > 0 {
+		updated.MaxChunkTokens = config.MaxTokens
+	}
+	if config.Overlap > 0 {
+		updated.OverlapTokens = config.Overlap
+	}
+	if config.Overlap == 0 && updated.OverlapTokens >= updated.MaxChunkTokens {
+		updated.OverlapTokens = updated.MaxChunkTokens / 4
+	}
+	if updated.OverlapTokens >= updated.MaxChunkTokens {
+		return fmt.Errorf("chunk overlap (%d) must be less than max tokens (%d)", updated.OverlapTokens, updated.MaxChunkTokens)
+	}
+	c.config = updated
+	return nil
+}
+
+// ChunkFile splits a file into chunks. Chunks always consist of whole lines.
 func (c *CodeChunker) ChunkFile(filePath string, content []byte, symbols []SymbolInfo) []domain.CodeChunk {
 	language := detectLanguage(filePath)
 	lines := strings.Split(string(content), "\n")
 
-	// If we have symbols and prefer symbol-based chunking
-	if c.config.PreferSymbols && len(symbols) > 0 {
-		return c.chunkBySymbols(filePath, lines, symbols, language)
+	// Without symbol boundaries fall back to sliding windows
+	if len(symbols) == 0 || c.strategy() == domain.ChunkStrategyWindow {
+		return c.chunkByWindow(filePath, lines, language)
 	}
+	return c.chunkBySymbols(filePath, lines, symbols, language)
+}
 
-	// Fall back to fixed-size chunking
-	return c.chunkBySize(filePath, lines, language)
+// strategy returns the configured strategy; without one PreferSymbols picks
+// symbol or window chunking
+func (c *CodeChunker) strategy() string {
+	if c.config.Strategy != "" {
+		return c.config.Strategy
+	}
+	if c.config.PreferSymbols {
+		return domain.ChunkStrategySymbol
+	}
+	return domain.ChunkStrategyWindow
 }
 
 // SymbolInfo represents a symbol for chunking
@@ -111,7 +155,11 @@ func (c *CodeChunker) chunkBySymbols(filePath string, lines []string, symbols []
 	}
 
 	// Create chunks for remaining code (imports, constants, etc.)
-	chunks = append(chunks, c.chunkRemainingLines(filePath, lines, usedLines, language)...)
+	if c.strategy() == domain.ChunkStrategyHybrid {
+		chunks = append(chunks, c.chunkRemainingLinesByWindow(filePath, lines, usedLines, language)...)
+	} else {
+		chunks = append(chunks, c.chunkRemainingLines(filePath, lines, usedLines, language)...)
+	}
 
 	return chunks
 }
@@ -178,67 +226,86 @@ func (c *CodeChunker) splitLargeSymbol(filePath string, lines []string, sym Symb
 	return chunks
 }
 
-// chunkBySize creates fixed-size chunks
-func (c *CodeChunker) chunkBySize(filePath string, lines []string, language string) []domain.CodeChunk {
-	chunks := make([]domain.CodeChunk, 0, len(lines)/50+1)
-
-	currentStart := 1
-	currentTokens := 0
-	currentLines := make([]string, 0, 50)
-
-	for i, line := range lines {
-		lineTokens := estimateTokens(line)
+// chunkByWindow splits a file into overlapping windows of whole lines.
+// A file smaller than MinChunkTokens yields no chunks.
+func (c *CodeChunker) chunkByWindow(filePath string, lines []string, language string) []domain.CodeChunk {
+	windows := c.windowRanges(lines)
+	chunks := make([]domain.CodeChunk, 0, len(windows))
+	for _, window := range windows {
+		chunk := newBlockChunk(filePath, lines, window[0], window[1], language)
+		if len(windows) == 1 && chunk.TokenCount < c.config.MinChunkTokens {
+			break
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
 
-		if currentTokens+lineTokens > c.config.MaxChunkTokens && len(currentLines) > 0 {
-			content := strings.Join(currentLines, "\n")
-			// EndLine should be currentStart + len(currentLines) - 1 (1-based, inclusive)
-			endLine := currentStart + len(currentLines) - 1
-			chunk := domain.CodeChunk{
-				ID:         generateChunkID(filePath, currentStart, endLine),
-				FilePath:   filePath,
-				Content:    content,
-				StartLine:  currentStart,
-				EndLine:    endLine,
-				ChunkType:  domain.ChunkTypeBlock,
-				Language:   language,
-				TokenCount: currentTokens,
-				Hash:       hashContent(content),
+// chunkRemainingLinesByWindow splits each run of lines not covered by symbols
+// into windows, dropping runs smaller than MinChunkTokens
+func (c *CodeChunker) chunkRemainingLinesByWindow(filePath string, lines []string, usedLines map[int]bool, language string) []domain.CodeChunk {
+	var chunks []domain.CodeChunk
+	for start := 0; start < len(lines); {
+		if usedLines[start+1] {
+			start++
+			continue
+		}
+		end := start
+		for end < len(lines) && !usedLines[end+1] {
+			end++
+		}
+		if estimateTokens(strings.Join(lines[start:end], "\n")) >= c.config.MinChunkTokens {
+			for _, window := range c.windowRanges(lines[start:end]) {
+				chunks = append(chunks, newBlockChunk(filePath, lines, start+window[0], start+window[1], language))
 			}
-			chunks = append(chunks, chunk)
-
-			// Start new chunk with overlap
-			overlapLines := getOverlapLines(currentLines, c.config.OverlapTokens)
-			currentLines = overlapLines
-			currentStart = i + 2 - len(overlapLines) // i+1 is next line (0-based), +1 for 1-based
-			currentTokens = estimateTokens(strings.Join(overlapLines, "\n"))
 		}
-
-		currentLines = append(currentLines, line)
-		currentTokens += lineTokens
+		start = end
 	}
+	return chunks
+}
 
-	// Add remaining lines
-	if len(currentLines) > 0 && currentTokens >= c.config.MinChunkTokens {
-		content := strings.Join(currentLines, "\n")
-		endLine := currentStart + len(currentLines) - 1
-		if endLine > len(lines) {
-			endLine = len(lines)
-		}
-		chunk := domain.CodeChunk{
-			ID:         generateChunkID(filePath, currentStart, endLine),
-			FilePath:   filePath,
-			Content:    content,
-			StartLine:  currentStart,
-			EndLine:    endLine,
-			ChunkType:  domain.ChunkTypeBlock,
-			Language:   language,
-			TokenCount: currentTokens,
-			Hash:       hashContent(content),
+// windowRanges splits lines into windows of at most MaxChunkTokens, each
+// starting with the trailing lines of the previous one that fit in
+// OverlapTokens. Windows never split a line: a line over MaxChunkTokens is a
+// window of its own. Ranges are 0-based with an exclusive end.
+func (c *CodeChunker) windowRanges(lines []string) [][2]int {
+	var windows [][2]int
+	start, runes := 0, 0 // runes of lines[start:i], each with its newline
+	for i, line := range lines {
+		lineRunes := utf8.RuneCountInString(line) + 1
+		if i > start && runesToTokens(runes+lineRunes-1) > c.config.MaxChunkTokens {
+			windows = append(windows, [2]int{start, i})
+
+			// Each window starts at least one line after the previous one
+			next, overlap := i, 0
+			for next-1 > start && runesToTokens(overlap+utf8.RuneCountInString(lines[next-1])) <= c.config.OverlapTokens {
+				next--
+				overlap += utf8.RuneCountInString(lines[next]) + 1
+			}
+			start, runes = next, overlap
 		}
-		chunks = append(chunks, chunk)
+		runes += lineRunes
 	}
+	if start < len(lines) {
+		windows = append(windows, [2]int{start, len(lines)})
+	}
+	return windows
+}
 
-	return chunks
+// newBlockChunk creates a block chunk of lines[start:end]
+func newBlockChunk(filePath string, lines []string, start, end int, language string) domain.CodeChunk {
+	content := strings.Join(lines[start:end], "\n")
+	return domain.CodeChunk{
+		ID:         generateChunkID(filePath, start+1, end),
+		FilePath:   filePath,
+		Content:    content,
+		StartLine:  start + 1,
+		EndLine:    end,
+		ChunkType:  domain.ChunkTypeBlock,
+		Language:   language,
+		TokenCount: estimateTokens(content),
+		Hash:       hashContent(content),
+	}
 }
 
 // chunkRemainingLines creates chunks for lines not covered by symbols
@@ -316,8 +383,12 @@ func hashContent(content string) string {
 }
 
 func estimateTokens(text string) int {
+	return runesToTokens(utf8.RuneCountInString(text))
+}
+
+func runesToTokens(runes int) int {
 	// Rough estimation: ~4 characters per token for code
-	return utf8.RuneCountInString(text) / 4
+	return runes / 4
 }
 
 func getOverlapLines(lines []string, overlapTokens int) []string {
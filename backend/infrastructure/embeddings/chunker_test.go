@@ -1,6 +1,9 @@
 package embeddings
 
 import (
+	"fmt"
+	"shotgun_code/domain"
+	"strings"
 	"testing"
 )
 
@@ -277,3 +280,110 @@ func TestCodeChunker_LargeFile(t *testing.T) {
 		}
 	}
 }
+
+// assertWholeLines checks that every chunk is exactly lines StartLine..EndLine of content
+func assertWholeLines(t *testing.T, content string, chunks []domain.CodeChunk) {
+	t.Helper()
+	lines := strings.Split(content, "\n")
+	for _, chunk := range chunks {
+		if chunk.StartLine < 1 || chunk.EndLine > len(lines) || chunk.StartLine > chunk.EndLine {
+			t.Fatalf("chunk has invalid range %d-%d", chunk.StartLine, chunk.EndLine)
+		}
+		if expected := strings.Join(lines[chunk.StartLine-1:chunk.EndLine], "\n"); chunk.Content != expected {
+			t.Errorf("chunk %d-%d splits a line: %q", chunk.StartLine, chunk.EndLine, chunk.Content)
+		}
+	}
+}
+
+func TestCodeChunker_WindowNeverSplitsLines(t *testing.T) {
+	chunker := NewCodeChunker(DefaultChunkerConfig())
+	if err := chunker.Configure(domain.ChunkerConfig{MaxTokens: 40, Overlap: 10}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	var b strings.Builder
+	for i := 0; i < 60; i++ {
+		fmt.Fprintf(&b, "x%d := compute(%d) // step\n", i, i)
+		if i == 30 {
+			b.WriteString("veryLong := \"" + strings.Repeat("a", 400) + "\"\n")
+		}
+	}
+	content := b.String()
+
+	chunks := chunker.ChunkFile("script.py", []byte(content), nil)
+	if len(chunks) < 3 {
+		t.Fatalf("expected several windows, got %d", len(chunks))
+	}
+	assertWholeLines(t, content, chunks)
+
+	for i := 1; i < len(chunks); i++ {
+		prev, cur := chunks[i-1], chunks[i]
+		if cur.StartLine <= prev.StartLine || cur.StartLine > prev.EndLine+1 {
+			t.Errorf("window %d-%d doesn't follow %d-%d", cur.StartLine, cur.EndLine, prev.StartLine, prev.EndLine)
+		}
+	}
+	if last := chunks[len(chunks)-1]; last.EndLine != len(strings.Split(content, "\n")) {
+		t.Errorf("expected the windows to reach the end of the file, last ends at %d", last.EndLine)
+	}
+}
+
+func TestCodeChunker_Strategies(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("package main\n\n")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&b, "var setting%d = \"value number %d\"\n", i, i)
+	}
+	b.WriteString("\nfunc handler() {\n\tprintln(\"handling the request here\")\n}\n")
+	content := b.String()
+	symbols := []SymbolInfo{{Name: "handler", Kind: "function", StartLine: 43, EndLine: 45}}
+
+	chunk := func(strategy string) []domain.CodeChunk {
+		config := DefaultChunkerConfig()
+		config.MinChunkTokens = 5
+		chunker := NewCodeChunker(config)
+		if err := chunker.Configure(domain.ChunkerConfig{Strategy: strategy, MaxTokens: 100, Overlap: 20}); err != nil {
+			t.Fatalf("Configure(%s) failed: %v", strategy, err)
+		}
+		chunks := chunker.ChunkFile("main.go", []byte(content), symbols)
+		assertWholeLines(t, content, chunks)
+		return chunks
+	}
+	hasSymbolChunk := func(chunks []domain.CodeChunk) bool {
+		for _, c := range chunks {
+			if c.SymbolName == "handler" {
+				return true
+			}
+		}
+		return false
+	}
+
+	symbolChunks := chunk(domain.ChunkStrategySymbol)
+	if !hasSymbolChunk(symbolChunks) || len(symbolChunks) != 2 {
+		t.Errorf("symbol strategy: expected the function and one chunk of the rest, got %d chunks", len(symbolChunks))
+	}
+	hybridChunks := chunk(domain.ChunkStrategyHybrid)
+	if !hasSymbolChunk(hybridChunks) || len(hybridChunks) <= len(symbolChunks) {
+		t.Errorf("hybrid strategy: expected the function and windows over the rest, got %d chunks", len(hybridChunks))
+	}
+	for _, c := range hybridChunks {
+		if c.TokenCount > 100 {
+			t.Errorf("hybrid chunk %d-%d exceeds max tokens: %d", c.StartLine, c.EndLine, c.TokenCount)
+		}
+	}
+	if windowChunks := chunk(domain.ChunkStrategyWindow); hasSymbolChunk(windowChunks) {
+		t.Error("window strategy should ignore symbols")
+	}
+}
+
+func TestCodeChunker_Configure(t *testing.T) {
+	chunker := NewCodeChunker(DefaultChunkerConfig())
+	if err := chunker.Configure(domain.ChunkerConfig{Strategy: "paragraph"}); err == nil {
+		t.Error("expected an unknown strategy to fail")
+	}
+	if err := chunker.Configure(domain.ChunkerConfig{MaxTokens: 100, Overlap: 100}); err == nil {
+		t.Error("expected an overlap of max tokens to fail")
+	}
+	if err := chunker.Configure(domain.ChunkerConfig{MaxTokens: 40}); err != nil || chunker.config.OverlapTokens != 10 {
+		t.Errorf("expected the default overlap scaled to the new max tokens, got %d (%v)", chunker.config.OverlapTokens, err)
+	}
+}
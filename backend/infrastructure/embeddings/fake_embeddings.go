@@ -0,0 +1,135 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"shotgun_code/domain"
+	"strings"
+	"unicode"
+)
+
+// fakeMaxBatchInputs is the batch limit the fake provider reports and enforces,
+// so callers exercise batching offline
+const fakeMaxBatchInputs = 256
+
+// FakeEmbeddingProvider implements EmbeddingProvider without network access.
+// Texts are embedded as hashed bags of identifier tokens, so the vectors are
+// deterministic and texts sharing words are similar. Intended for tests and
+// offline development, not for real semantic search quality.
+type FakeEmbeddingProvider struct {
+	dimensions int
+}
+
+// NewFakeEmbeddingProvider creates a fake provider; dimensions <= 0 uses the fake model default
+func NewFakeEmbeddingProvider(dimensions int) *FakeEmbeddingProvider {
+	if dimensions <= 0 {
+		dimensions = domain.EmbeddingModelFake.Dimensions()
+	}
+	return &FakeEmbeddingProvider{dimensions: dimensions}
+}
+
+// GenerateEmbeddings generates deterministic pseudo-embeddings for the given texts
+func (p *FakeEmbeddingProvider) GenerateEmbeddings(_ context.Context, req domain.EmbeddingRequest) (*domain.EmbeddingResponse, error) {
+	if err := p.ValidateRequest(req); err != nil {
+		return nil, err
+	}
+
+	embeddings := make([]domain.EmbeddingVector, len(req.Texts))
+	tokensUsed := 0
+	for i, text := range req.Texts {
+		tokens := embeddingTokens(text)
+		tokensUsed += len(tokens)
+		embeddings[i] = p.embed(tokens)
+	}
+
+	return &domain.EmbeddingResponse{
+		Embeddings: embeddings,
+		Model:      domain.EmbeddingModelFake,
+		TokensUsed: tokensUsed,
+	}, nil
+}
+
+// embed hashes each token into a signed bucket and normalizes the vector
+func (p *FakeEmbeddingProvider) embed(tokens []string) domain.EmbeddingVector {
+	vector := make(domain.EmbeddingVector, p.dimensions)
+	for _, token := range tokens {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(token))
+		sum := h.Sum64()
+		sign := float32(1)
+		if sum&1 == 1 {
+			sign = -1
+		}
+		vector[(sum>>1)%uint64(p.dimensions)] += sign
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return vector
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+	return vector
+}
+
+// embeddingTokens splits text into lowercase words, also splitting camelCase identifiers
+func embeddingTokens(text string) []string {
+	var tokens []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) {
+				flush()
+			}
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+// GetModelInfo returns information about the fake embedding model
+func (p *FakeEmbeddingProvider) GetModelInfo() domain.EmbeddingModelInfo {
+	return domain.EmbeddingModelInfo{
+		Model:      domain.EmbeddingModelFake,
+		Dimensions: p.dimensions,
+		MaxTokens:  8191,
+		Provider:   "fake",
+
+		MaxBatchInputs: fakeMaxBatchInputs,
+	}
+}
+
+// ValidateRequest validates the embedding request
+func (p *FakeEmbeddingProvider) ValidateRequest(req domain.EmbeddingRequest) error {
+	if len(req.Texts) == 0 {
+		return fmt.Errorf("at least one text is required")
+	}
+	if len(req.Texts) > fakeMaxBatchInputs {
+		return fmt.Errorf("batch size exceeds maximum of %d", fakeMaxBatchInputs)
+	}
+	for i, text := range req.Texts {
+		if text == "" {
+			return fmt.Errorf("text at index %d is empty", i)
+		}
+	}
+	return nil
+}
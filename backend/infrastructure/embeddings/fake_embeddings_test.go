@@ -0,0 +1,61 @@
+package embeddings
+
+import (
+	"context"
+	"shotgun_code/domain"
+	"testing"
+)
+
+func TestFakeEmbeddingProvider_Deterministic(t *testing.T) {
+	provider := NewFakeEmbeddingProvider(64)
+	req := domain.EmbeddingRequest{Texts: []string{"parseConfig reads JSON", "parseConfig reads JSON"}}
+
+	first, err := provider.GenerateEmbeddings(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+	second, err := NewFakeEmbeddingProvider(64).GenerateEmbeddings(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+
+	if len(first.Embeddings[0]) != 64 {
+		t.Fatalf("expected 64 dimensions, got %d", len(first.Embeddings[0]))
+	}
+	for i := range first.Embeddings[0] {
+		if first.Embeddings[0][i] != second.Embeddings[1][i] {
+			t.Fatal("embeddings of the same text should be identical")
+		}
+	}
+}
+
+func TestFakeEmbeddingProvider_Similarity(t *testing.T) {
+	provider := NewFakeEmbeddingProvider(0)
+	resp, err := provider.GenerateEmbeddings(context.Background(), domain.EmbeddingRequest{Texts: []string{
+		"load config file",
+		"func loadConfig(path string) (*Config, error)",
+		"func renderButton(label string) string",
+	}})
+	if err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+
+	related := cosineSimilarity(resp.Embeddings[0], resp.Embeddings[1])
+	unrelated := cosineSimilarity(resp.Embeddings[0], resp.Embeddings[2])
+	if related <= unrelated {
+		t.Errorf("expected shared tokens to increase similarity: related=%v unrelated=%v", related, unrelated)
+	}
+	if got := provider.GetModelInfo().Dimensions; got != domain.EmbeddingModelFake.Dimensions() {
+		t.Errorf("expected default dimensions %d, got %d", domain.EmbeddingModelFake.Dimensions(), got)
+	}
+}
+
+func TestFakeEmbeddingProvider_ValidateRequest(t *testing.T) {
+	provider := NewFakeEmbeddingProvider(8)
+	if err := provider.ValidateRequest(domain.EmbeddingRequest{}); err == nil {
+		t.Error("expected error for empty request")
+	}
+	if err := provider.ValidateRequest(domain.EmbeddingRequest{Texts: []string{"a", ""}}); err == nil {
+		t.Error("expected error for empty text")
+	}
+}
@@ -0,0 +1,328 @@
+package embeddings
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"shotgun_code/domain"
+	"sort"
+)
+
+// hnswIndex is a Hierarchical Navigable Small World graph for approximate
+// nearest neighbor search. For cosine similarity vectors are normalized on
+// insert so that similarity is a dot product. Deleted nodes stay in the graph
+// as tombstones (they are still traversed, but never returned) until the
+// index is compacted.
+//
+// The index is not safe for concurrent use while it is being modified.
+type hnswIndex struct {
+	m              int     // max neighbors per node on layers > 0 (2*m on layer 0)
+	efConstruction int     // candidate list size while inserting
+	levelMult      float64 // 1/ln(m), level generation factor
+	metric         domain.SimilarityMetric
+
+	nodes    []*hnswNode
+	ids      map[string]int32 // chunk ID -> live node
+	entry    int32
+	maxLevel int
+	deleted  int
+	rng      *rand.Rand
+}
+
+// hnswNode is a vector in the graph with its neighbor lists per layer
+type hnswNode struct {
+	id        string
+	vector    domain.EmbeddingVector
+	level     int
+	neighbors [][]int32
+	deleted   bool
+}
+
+// hnswCandidate is a node with its similarity to the current query
+type hnswCandidate struct {
+	node  int32
+	score float32
+}
+
+// newHNSWIndex creates an empty index; m and efConstruction control graph quality
+func newHNSWIndex(m, efConstruction int, metric domain.SimilarityMetric) *hnswIndex {
+	if m < 2 {
+		m = 2
+	}
+	return &hnswIndex{
+		m:              m,
+		efConstruction: max(efConstruction, m),
+		levelMult:      1 / math.Log(float64(m)),
+		metric:         metric,
+		ids:            make(map[string]int32),
+		entry:          -1,
+		rng:            rand.New(rand.NewSource(1)), // deterministic graphs for the same input
+	}
+}
+
+// Len returns the number of live vectors
+func (h *hnswIndex) Len() int {
+	return len(h.ids)
+}
+
+// tombstoneRatio returns the share of deleted nodes in the graph
+func (h *hnswIndex) tombstoneRatio() float64 {
+	if len(h.nodes) == 0 {
+		return 0
+	}
+	return float64(h.deleted) / float64(len(h.nodes))
+}
+
+// Insert adds a vector; an existing vector with the same ID is replaced
+func (h *hnswIndex) Insert(id string, vector domain.EmbeddingVector) {
+	h.Delete(id)
+
+	level := int(math.Floor(-math.Log(1-h.rng.Float64()) * h.levelMult))
+	node := &hnswNode{
+		id:        id,
+		vector:    prepareVector(h.metric, vector),
+		level:     level,
+		neighbors: make([][]int32, level+1),
+	}
+	idx := int32(len(h.nodes))
+	h.nodes = append(h.nodes, node)
+	h.ids[id] = idx
+
+	if h.entry < 0 {
+		h.entry = idx
+		h.maxLevel = level
+		return
+	}
+
+	ep := []hnswCandidate{{node: h.entry, score: h.score(node.vector, h.nodes[h.entry].vector)}}
+	for l := h.maxLevel; l > level; l-- {
+		ep = h.searchLayer(node.vector, ep, 1, l)
+	}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		candidates := h.searchLayer(node.vector, ep, h.efConstruction, l)
+		node.neighbors[l] = h.selectNeighbors(candidates, h.maxNeighbors(l))
+		for _, neighbor := range node.neighbors[l] {
+			h.link(neighbor, idx, l)
+		}
+		ep = candidates
+	}
+
+	if level > h.maxLevel {
+		h.maxLevel = level
+		h.entry = idx
+	}
+}
+
+// Delete marks the vector with the given ID as deleted
+func (h *hnswIndex) Delete(id string) {
+	idx, ok := h.ids[id]
+	if !ok {
+		return
+	}
+	h.nodes[idx].deleted = true
+	delete(h.ids, id)
+	h.deleted++
+}
+
+// Search returns up to k live vectors most similar to the query, best first.
+// ef is the candidate list size: larger values are slower but more accurate.
+func (h *hnswIndex) Search(query domain.EmbeddingVector, k, ef int) []hnswCandidate {
+	if h.entry < 0 || k <= 0 {
+		return nil
+	}
+	query = prepareVector(h.metric, query)
+
+	ep := []hnswCandidate{{node: h.entry, score: h.score(query, h.nodes[h.entry].vector)}}
+	for l := h.maxLevel; l > 0; l-- {
+		ep = h.searchLayer(query, ep, 1, l)
+	}
+	candidates := h.searchLayer(query, ep, max(ef, k), 0)
+
+	results := make([]hnswCandidate, 0, k)
+	for _, c := range candidates {
+		if !h.nodes[c.node].deleted {
+			results = append(results, c)
+			if len(results) == k {
+				break
+			}
+		}
+	}
+	return results
+}
+
+// Compact rebuilds the graph without tombstones
+func (h *hnswIndex) Compact() {
+	live := make([]*hnswNode, 0, len(h.ids))
+	for _, node := range h.nodes {
+		if !node.deleted {
+			live = append(live, node)
+		}
+	}
+	rebuilt := newHNSWIndex(h.m, h.efConstruction, h.metric)
+	for _, node := range live {
+		rebuilt.Insert(node.id, node.vector)
+	}
+	*h = *rebuilt
+}
+
+// maxNeighbors returns the neighbor limit for a layer
+func (h *hnswIndex) maxNeighbors(level int) int {
+	if level == 0 {
+		return 2 * h.m
+	}
+	return h.m
+}
+
+// link adds a directed edge from -> to on a layer, pruning from's neighbors if needed
+func (h *hnswIndex) link(from, to int32, level int) {
+	node := h.nodes[from]
+	node.neighbors[level] = append(node.neighbors[level], to)
+	limit := h.maxNeighbors(level)
+	if len(node.neighbors[level]) <= limit {
+		return
+	}
+
+	candidates := make([]hnswCandidate, len(node.neighbors[level]))
+	for i, n := range node.neighbors[level] {
+		candidates[i] = hnswCandidate{node: n, score: h.score(node.vector, h.nodes[n].vector)}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	node.neighbors[level] = h.selectNeighbors(candidates, limit)
+}
+
+// selectNeighbors picks up to limit neighbors from candidates sorted best first.
+// A candidate is skipped if it is closer to an already selected neighbor than
+// to the base node, which keeps edges pointing in diverse directions; the
+// remaining slots are filled with the closest skipped candidates.
+func (h *hnswIndex) selectNeighbors(candidates []hnswCandidate, limit int) []int32 {
+	selected := make([]int32, 0, limit)
+	var skipped []int32
+	for _, c := range candidates {
+		if len(selected) >= limit {
+			break
+		}
+		diverse := true
+		for _, s := range selected {
+			if h.score(h.nodes[c.node].vector, h.nodes[s].vector) > c.score {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			selected = append(selected, c.node)
+		} else {
+			skipped = append(skipped, c.node)
+		}
+	}
+	for _, n := range skipped {
+		if len(selected) >= limit {
+			break
+		}
+		selected = append(selected, n)
+	}
+	return selected
+}
+
+// searchLayer runs a best-first search on one layer and returns up to ef
+// closest nodes (including tombstones), best first
+func (h *hnswIndex) searchLayer(query domain.EmbeddingVector, entryPoints []hnswCandidate, ef, level int) []hnswCandidate {
+	visited := make(map[int32]bool, ef*4)
+	candidates := &candidateHeap{best: true}
+	results := &candidateHeap{}
+
+	for _, ep := range entryPoints {
+		visited[ep.node] = true
+		heap.Push(candidates, ep)
+		heap.Push(results, ep)
+		if results.Len() > ef {
+			heap.Pop(results)
+		}
+	}
+
+	for candidates.Len() > 0 {
+		current := heap.Pop(candidates).(hnswCandidate)
+		if results.Len() >= ef && current.score < results.items[0].score {
+			break
+		}
+		node := h.nodes[current.node]
+		if level >= len(node.neighbors) {
+			continue
+		}
+		for _, n := range node.neighbors[level] {
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+			score := h.score(query, h.nodes[n].vector)
+			if results.Len() < ef || score > results.items[0].score {
+				heap.Push(candidates, hnswCandidate{node: n, score: score})
+				heap.Push(results, hnswCandidate{node: n, score: score})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	sorted := results.items
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].score > sorted[j].score })
+	return sorted
+}
+
+// candidateHeap is a heap of candidates: best-first if best is set, worst-first otherwise
+type candidateHeap struct {
+	items []hnswCandidate
+	best  bool
+}
+
+func (c *candidateHeap) Len() int { return len(c.items) }
+func (c *candidateHeap) Less(i, j int) bool {
+	if c.best {
+		return c.items[i].score > c.items[j].score
+	}
+	return c.items[i].score < c.items[j].score
+}
+func (c *candidateHeap) Swap(i, j int) { c.items[i], c.items[j] = c.items[j], c.items[i] }
+func (c *candidateHeap) Push(x any)    { c.items = append(c.items, x.(hnswCandidate)) }
+func (c *candidateHeap) Pop() any {
+	last := c.items[len(c.items)-1]
+	c.items = c.items[:len(c.items)-1]
+	return last
+}
+
+// normalizeVector returns a unit-length copy of v (zero vectors stay zero)
+func normalizeVector(v domain.EmbeddingVector) domain.EmbeddingVector {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	out := make(domain.EmbeddingVector, len(v))
+	if norm == 0 {
+		return out
+	}
+	scale := 1 / math.Sqrt(norm)
+	for i, x := range v {
+		out[i] = float32(float64(x) * scale)
+	}
+	return out
+}
+
+// score compares two prepared vectors of the index
+func (h *hnswIndex) score(a, b domain.EmbeddingVector) float32 {
+	if normalizedMetric(h.metric) {
+		return dot(a, b)
+	}
+	return similarity(h.metric, a, b)
+}
+
+// dot returns the dot product of two vectors, 0 if their lengths differ
+func dot(a, b domain.EmbeddingVector) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
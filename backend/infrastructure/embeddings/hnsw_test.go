@@ -0,0 +1,238 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"shotgun_code/domain"
+	"sort"
+	"testing"
+	"time"
+)
+
+func randomVectors(n, dims int, seed int64) []domain.EmbeddingVector {
+	rng := rand.New(rand.NewSource(seed))
+	vectors := make([]domain.EmbeddingVector, n)
+	for i := range vectors {
+		v := make(domain.EmbeddingVector, dims)
+		for j := range v {
+			v[j] = float32(rng.NormFloat64())
+		}
+		vectors[i] = v
+	}
+	return vectors
+}
+
+// exactTopK returns the indices of the k vectors most similar to the query
+func exactTopK(vectors []domain.EmbeddingVector, query domain.EmbeddingVector, k int) []int {
+	indices := make([]int, len(vectors))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.Slice(indices, func(a, b int) bool {
+		return cosineSimilarity(query, vectors[indices[a]]) > cosineSimilarity(query, vectors[indices[b]])
+	})
+	return indices[:k]
+}
+
+func TestHNSWIndex_Recall(t *testing.T) {
+	vectors := randomVectors(1000, 32, 1)
+	index := newHNSWIndex(16, 100, domain.SimilarityCosine)
+	for i, v := range vectors {
+		index.Insert(fmt.Sprintf("c%d", i), v)
+	}
+
+	const k = 10
+	hits, total := 0, 0
+	for _, query := range randomVectors(50, 32, 2) {
+		expected := make(map[string]bool)
+		for _, i := range exactTopK(vectors, query, k) {
+			expected[fmt.Sprintf("c%d", i)] = true
+		}
+		for _, c := range index.Search(query, k, 64) {
+			if expected[index.nodes[c.node].id] {
+				hits++
+			}
+		}
+		total += k
+	}
+
+	if recall := float64(hits) / float64(total); recall < 0.9 {
+		t.Errorf("recall@%d = %.2f, want >= 0.9", k, recall)
+	}
+}
+
+func TestHNSWIndex_DeleteAndCompact(t *testing.T) {
+	vectors := randomVectors(200, 8, 3)
+	index := newHNSWIndex(8, 50, domain.SimilarityCosine)
+	for i, v := range vectors {
+		index.Insert(fmt.Sprintf("c%d", i), v)
+	}
+
+	index.Delete("c0")
+	for _, c := range index.Search(vectors[0], 5, 50) {
+		if index.nodes[c.node].id == "c0" {
+			t.Fatal("deleted vector returned")
+		}
+	}
+
+	for i := 1; i < 100; i++ {
+		index.Delete(fmt.Sprintf("c%d", i))
+	}
+	index.Compact()
+	if index.Len() != 100 || len(index.nodes) != 100 || index.deleted != 0 {
+		t.Errorf("compact left %d live of %d nodes, %d deleted", index.Len(), len(index.nodes), index.deleted)
+	}
+	results := index.Search(vectors[150], 1, 50)
+	if len(results) != 1 || index.nodes[results[0].node].id != "c150" {
+		t.Errorf("expected c150 as nearest to itself, got %+v", results)
+	}
+}
+
+func annTestChunks(vectors []domain.EmbeddingVector) []domain.EmbeddedChunk {
+	now := time.Now()
+	chunks := make([]domain.EmbeddedChunk, len(vectors))
+	for i, v := range vectors {
+		chunks[i] = domain.EmbeddedChunk{
+			Chunk: domain.CodeChunk{
+				ID: fmt.Sprintf("c%d", i), FilePath: fmt.Sprintf("file%d.go", i%20),
+				Content: "x", StartLine: i, EndLine: i, ChunkType: domain.ChunkTypeFunction,
+				Language: "go", Hash: "h",
+			},
+			Embedding: v,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	return chunks
+}
+
+func TestSQLiteVectorStore_ANNSearchAndPersistence(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cfg := DefaultANNConfig()
+	cfg.MinChunks = 100
+
+	store, err := NewSQLiteVectorStore(dir, &domain.NoopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.SetANNConfig(cfg)
+
+	vectors := randomVectors(300, 16, 4)
+	if err := store.StoreBatch(ctx, "p", annTestChunks(vectors)); err != nil {
+		t.Fatal(err)
+	}
+
+	query := vectors[42]
+	results, err := store.Search(ctx, "p", query, 5, 0, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 5 || results[0].Chunk.ID != "c42" || results[0].Chunk.FilePath != "file2.go" {
+		t.Fatalf("expected c42 as top result, got %+v", results)
+	}
+	if _, ok := store.ann["p"]; !ok {
+		t.Fatal("ANN index should be built for a project above MinChunks")
+	}
+
+	filtered, err := store.Search(ctx, "p", query, 5, 0, &domain.SearchFilters{FilePaths: []string{"file3.go"}})
+	if err != nil {
+		t.Fatalf("filtered Search failed: %v", err)
+	}
+	if len(filtered) != 5 {
+		t.Fatalf("expected 5 filtered results, got %d", len(filtered))
+	}
+	for _, r := range filtered {
+		if r.Chunk.FilePath != "file3.go" {
+			t.Errorf("filter not applied: %s", r.Chunk.FilePath)
+		}
+	}
+
+	// Changes are applied incrementally and the index is persisted on Close
+	extra := annTestChunks(randomVectors(301, 16, 5))[300]
+	if err := store.Store(ctx, "p", extra); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, "p", "file2.go"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewSQLiteVectorStore(dir, &domain.NoopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	reopened.SetANNConfig(cfg)
+
+	index, err := reopened.loadANN(ctx, "p")
+	if err != nil || index == nil {
+		t.Fatalf("expected persisted index, got %v, %v", index, err)
+	}
+	if index.Len() != 300+1-15 {
+		t.Errorf("persisted index has %d live vectors", index.Len())
+	}
+
+	results, err = reopened.Search(ctx, "p", extra.Embedding, 3, 0, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) == 0 || results[0].Chunk.ID != "c300" {
+		t.Errorf("expected inserted chunk c300 as top result, got %+v", results)
+	}
+	for _, r := range results {
+		if r.Chunk.FilePath == "file2.go" {
+			t.Errorf("deleted file returned: %s", r.Chunk.ID)
+		}
+	}
+}
+
+func TestSQLiteVectorStore_ANNSimilarityMetric(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultANNConfig()
+	cfg.MinChunks = 10
+
+	store, err := NewSQLiteVectorStore(t.TempDir(), &domain.NoopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	store.SetANNConfig(cfg)
+
+	// Same directions at growing magnitudes: cosine can't tell them apart,
+	// euclidean finds the one closest in space
+	vectors := randomVectors(200, 8, 6)
+	for i := range vectors {
+		scale := float32(1 + i%5)
+		for j := range vectors[i] {
+			vectors[i][j] *= scale
+		}
+	}
+	if err := store.StoreBatch(ctx, "p", annTestChunks(vectors)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Search(ctx, "p", vectors[0], 1, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.saveANN(ctx, "p", store.ann["p"].index); err != nil {
+		t.Fatal(err)
+	}
+
+	store.SetSimilarityMetric(domain.SimilarityEuclidean)
+	if _, err := store.loadANN(ctx, "p"); err == nil {
+		t.Error("index built for cosine should not load for euclidean")
+	}
+	results, err := store.Search(ctx, "p", vectors[37], 1, 0, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Chunk.ID != "c37" || results[0].Score != 1 {
+		t.Fatalf("expected exact euclidean match c37, got %+v", results)
+	}
+	if got := store.ann["p"].index.metric; got != domain.SimilarityEuclidean {
+		t.Errorf("index rebuilt with metric %q", got)
+	}
+}
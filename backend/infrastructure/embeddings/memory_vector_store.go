@@ -0,0 +1,178 @@
+package embeddings
+
+import (
+	"context"
+	"shotgun_code/domain"
+	"sort"
+	"sync"
+)
+
+// InMemoryVectorStore implements VectorStore with maps and brute-force search.
+// Nothing is persisted; it is meant for tests and small projects, and serves as
+// a reference implementation for the SQLite store.
+type InMemoryVectorStore struct {
+	mu       sync.RWMutex
+	projects map[string]map[string]domain.EmbeddedChunk // projectID -> chunkID -> chunk
+	metric   domain.SimilarityMetric
+}
+
+// NewInMemoryVectorStore creates an empty in-memory vector store
+func NewInMemoryVectorStore() *InMemoryVectorStore {
+	return &InMemoryVectorStore{
+		projects: make(map[string]map[string]domain.EmbeddedChunk),
+		metric:   domain.SimilarityCosine,
+	}
+}
+
+// SetSimilarityMetric changes how embeddings are compared
+func (s *InMemoryVectorStore) SetSimilarityMetric(metric domain.SimilarityMetric) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metric = metric
+}
+
+// Store stores an embedded chunk
+func (s *InMemoryVectorStore) Store(ctx context.Context, projectID string, chunk domain.EmbeddedChunk) error {
+	return s.StoreBatch(ctx, projectID, []domain.EmbeddedChunk{chunk})
+}
+
+// StoreBatch stores multiple embedded chunks, replacing chunks with the same ID
+func (s *InMemoryVectorStore) StoreBatch(_ context.Context, projectID string, chunks []domain.EmbeddedChunk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.projects[projectID]
+	if !ok {
+		project = make(map[string]domain.EmbeddedChunk)
+		s.projects[projectID] = project
+	}
+	for _, chunk := range chunks {
+		chunk.Embedding = append(domain.EmbeddingVector(nil), chunk.Embedding...)
+		project[chunk.Chunk.ID] = chunk
+	}
+	return nil
+}
+
+// Search performs vector similarity search using the store's similarity metric.
+// minScore is calibrated for cosine similarity and scaled to the metric.
+func (s *InMemoryVectorStore) Search(_ context.Context, projectID string, query domain.EmbeddingVector, topK int, minScore float32, filters *domain.SearchFilters) ([]domain.SemanticSearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	minScore = scoreThreshold(s.metric, minScore)
+
+	results := make([]domain.SemanticSearchResult, 0)
+	for _, chunk := range s.projects[projectID] {
+		if !filters.Matches(&chunk.Chunk) {
+			continue
+		}
+		score := similarity(s.metric, query, chunk.Embedding)
+		if score >= minScore {
+			results = append(results, domain.SemanticSearchResult{Chunk: chunk.Chunk, Score: score})
+		}
+	}
+
+	// Ties are broken by chunk ID so that results don't depend on map order
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Chunk.ID < results[j].Chunk.ID
+	})
+
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+// Delete removes embeddings for a file
+func (s *InMemoryVectorStore) Delete(_ context.Context, projectID, filePath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, chunk := range s.projects[projectID] {
+		if chunk.Chunk.FilePath == filePath {
+			delete(s.projects[projectID], id)
+		}
+	}
+	return nil
+}
+
+// DeleteProject removes all embeddings for a project
+func (s *InMemoryVectorStore) DeleteProject(_ context.Context, projectID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.projects, projectID)
+	return nil
+}
+
+// GetStats returns statistics about stored embeddings
+func (s *InMemoryVectorStore) GetStats(_ context.Context, projectID string) (*domain.VectorStoreStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var stats domain.VectorStoreStats
+	files := make(map[string]bool)
+	for _, chunk := range s.projects[projectID] {
+		stats.TotalChunks++
+		stats.TotalTokens += chunk.Chunk.TokenCount
+		files[chunk.Chunk.FilePath] = true
+		if chunk.UpdatedAt.After(stats.LastUpdated) {
+			stats.LastUpdated = chunk.UpdatedAt
+		}
+		if stats.Dimensions == 0 {
+			stats.Dimensions = len(chunk.Embedding)
+		}
+	}
+	stats.TotalFiles = len(files)
+	return &stats, nil
+}
+
+// GetChunkByID retrieves a specific chunk; returns nil if it doesn't exist
+func (s *InMemoryVectorStore) GetChunkByID(_ context.Context, projectID, chunkID string) (*domain.EmbeddedChunk, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	chunk, ok := s.projects[projectID][chunkID]
+	if !ok {
+		return nil, nil
+	}
+	return &chunk, nil
+}
+
+// ListChunks lists all chunks for a file ordered by start line
+func (s *InMemoryVectorStore) ListChunks(_ context.Context, projectID, filePath string) ([]domain.EmbeddedChunk, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var chunks []domain.EmbeddedChunk
+	for _, chunk := range s.projects[projectID] {
+		if chunk.Chunk.FilePath == filePath {
+			chunks = append(chunks, chunk)
+		}
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].Chunk.StartLine < chunks[j].Chunk.StartLine
+	})
+	return chunks, nil
+}
+
+// GetFileHashes returns content hashes for all chunks in a file
+func (s *InMemoryVectorStore) GetFileHashes(_ context.Context, projectID, filePath string) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hashes := make(map[string]string)
+	for id, chunk := range s.projects[projectID] {
+		if chunk.Chunk.FilePath == filePath {
+			hashes[id] = chunk.Chunk.Hash
+		}
+	}
+	return hashes, nil
+}
+
+// Close is a no-op; it exists to match SQLiteVectorStore
+func (s *InMemoryVectorStore) Close() error {
+	return nil
+}
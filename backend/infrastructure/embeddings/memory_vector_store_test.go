@@ -0,0 +1,249 @@
+package embeddings
+
+import (
+	"context"
+	"math"
+	"shotgun_code/domain"
+	"testing"
+	"time"
+)
+
+// vectorStoreFactories lists the stores that must behave identically
+func vectorStoreFactories(t *testing.T) map[string]func() domain.VectorStore {
+	return map[string]func() domain.VectorStore{
+		"memory": func() domain.VectorStore { return NewInMemoryVectorStore() },
+		"sqlite": func() domain.VectorStore {
+			store, err := NewSQLiteVectorStore(t.TempDir(), &domain.NoopLogger{})
+			if err != nil {
+				t.Fatalf("failed to create SQLite store: %v", err)
+			}
+			t.Cleanup(func() { _ = store.Close() })
+			return store
+		},
+	}
+}
+
+func testChunk(id, filePath string, startLine int, embedding domain.EmbeddingVector) domain.EmbeddedChunk {
+	now := time.Now().UTC().Truncate(time.Second)
+	return domain.EmbeddedChunk{
+		Chunk: domain.CodeChunk{
+			ID: id, FilePath: filePath, Content: id, StartLine: startLine, EndLine: startLine + 1,
+			ChunkType: domain.ChunkTypeFunction, Language: "go", TokenCount: 10, Hash: "hash-" + id,
+		},
+		Embedding: embedding,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func TestVectorStores_Contract(t *testing.T) {
+	ctx := context.Background()
+	for name, newStore := range vectorStoreFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			err := store.StoreBatch(ctx, "p1", []domain.EmbeddedChunk{
+				testChunk("a1", "a.go", 10, domain.EmbeddingVector{1, 0, 0}),
+				testChunk("a2", "a.go", 1, domain.EmbeddingVector{0.7, 0.7, 0}),
+				testChunk("b1", "b.go", 1, domain.EmbeddingVector{0, 0, 1}),
+			})
+			if err != nil {
+				t.Fatalf("StoreBatch failed: %v", err)
+			}
+			if err := store.Store(ctx, "p2", testChunk("other", "a.go", 1, domain.EmbeddingVector{1, 0, 0})); err != nil {
+				t.Fatalf("Store failed: %v", err)
+			}
+
+			results, err := store.Search(ctx, "p1", domain.EmbeddingVector{1, 0, 0}, 2, 0.1, nil)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(results) != 2 || results[0].Chunk.ID != "a1" || results[1].Chunk.ID != "a2" {
+				t.Fatalf("unexpected search results: %+v", results)
+			}
+
+			stats, err := store.GetStats(ctx, "p1")
+			if err != nil {
+				t.Fatalf("GetStats failed: %v", err)
+			}
+			if stats.TotalChunks != 3 || stats.TotalFiles != 2 || stats.TotalTokens != 30 || stats.Dimensions != 3 {
+				t.Errorf("unexpected stats: %+v", stats)
+			}
+			if stats.LastUpdated.IsZero() {
+				t.Error("LastUpdated should be set")
+			}
+
+			chunks, err := store.ListChunks(ctx, "p1", "a.go")
+			if err != nil {
+				t.Fatalf("ListChunks failed: %v", err)
+			}
+			if len(chunks) != 2 || chunks[0].Chunk.ID != "a2" {
+				t.Errorf("expected chunks ordered by start line, got %+v", chunks)
+			}
+
+			chunk, err := store.GetChunkByID(ctx, "p1", "b1")
+			if err != nil || chunk == nil || chunk.Chunk.Hash != "hash-b1" {
+				t.Errorf("GetChunkByID = %+v, %v", chunk, err)
+			}
+			if chunk, err := store.GetChunkByID(ctx, "p1", "missing"); err != nil || chunk != nil {
+				t.Errorf("expected nil for missing chunk, got %+v, %v", chunk, err)
+			}
+
+			if err := store.Delete(ctx, "p1", "a.go"); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if stats, _ := store.GetStats(ctx, "p1"); stats.TotalChunks != 1 {
+				t.Errorf("expected 1 chunk after Delete, got %d", stats.TotalChunks)
+			}
+
+			if err := store.DeleteProject(ctx, "p1"); err != nil {
+				t.Fatalf("DeleteProject failed: %v", err)
+			}
+			if stats, _ := store.GetStats(ctx, "p1"); stats.TotalChunks != 0 {
+				t.Errorf("expected empty project after DeleteProject, got %d chunks", stats.TotalChunks)
+			}
+			if stats, _ := store.GetStats(ctx, "p2"); stats.TotalChunks != 1 {
+				t.Errorf("other project should be untouched, got %d chunks", stats.TotalChunks)
+			}
+		})
+	}
+}
+
+func TestInMemoryVectorStore_GetFileHashes(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryVectorStore()
+	_ = store.StoreBatch(ctx, "p", []domain.EmbeddedChunk{
+		testChunk("a1", "a.go", 1, domain.EmbeddingVector{1}),
+		testChunk("b1", "b.go", 1, domain.EmbeddingVector{1}),
+	})
+
+	hashes, err := store.GetFileHashes(ctx, "p", "a.go")
+	if err != nil {
+		t.Fatalf("GetFileHashes failed: %v", err)
+	}
+	if len(hashes) != 1 || hashes["a1"] != "hash-a1" {
+		t.Errorf("unexpected hashes: %v", hashes)
+	}
+}
+
+func TestVectorStores_SearchFilters(t *testing.T) {
+	ctx := context.Background()
+	pyChunk := testChunk("py", "scripts/tool.py", 1, domain.EmbeddingVector{0.9, 0.1, 0})
+	pyChunk.Chunk.Language = "python"
+	chunks := []domain.EmbeddedChunk{
+		testChunk("go1", "internal/a.go", 1, domain.EmbeddingVector{1, 0, 0}),
+		testChunk("go2", "vendor/lib/b.go", 1, domain.EmbeddingVector{1, 0.1, 0}),
+		testChunk("go3", "cmd/main.go", 1, domain.EmbeddingVector{0.8, 0.2, 0}),
+		pyChunk,
+	}
+
+	tests := []struct {
+		name     string
+		filters  *domain.SearchFilters
+		expected []string
+	}{
+		{"language", &domain.SearchFilters{Languages: []string{"python"}}, []string{"py"}},
+		{"file path prefix", &domain.SearchFilters{FilePaths: []string{"cmd/", "scripts/"}}, []string{"py", "go3"}},
+		{"exclude dirs", &domain.SearchFilters{Languages: []string{"go"}, ExcludeDirs: []string{"vendor"}}, []string{"go1", "go3"}},
+		{"chunk type", &domain.SearchFilters{ChunkTypes: []domain.ChunkType{domain.ChunkTypeClass}}, nil},
+	}
+
+	for name, newStore := range vectorStoreFactories(t) {
+		store := newStore()
+		if err := store.StoreBatch(ctx, "p", chunks); err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				results, err := store.Search(ctx, "p", domain.EmbeddingVector{1, 0, 0}, 2, 0, tt.filters)
+				if err != nil {
+					t.Fatalf("Search failed: %v", err)
+				}
+				var ids []string
+				for _, r := range results {
+					ids = append(ids, r.Chunk.ID)
+				}
+				if len(ids) != len(tt.expected) {
+					t.Fatalf("got %v, want %v", ids, tt.expected)
+				}
+				for i := range ids {
+					if ids[i] != tt.expected[i] {
+						t.Fatalf("got %v, want %v", ids, tt.expected)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestVectorStores_SimilarityMetric(t *testing.T) {
+	ctx := context.Background()
+	// "long" points the same way as the query but is 10x longer; "near" is
+	// closer in space but at an angle
+	query := domain.EmbeddingVector{1, 0}
+	chunks := []domain.EmbeddedChunk{
+		testChunk("long", "a.go", 1, domain.EmbeddingVector{10, 0}),
+		testChunk("near", "b.go", 1, domain.EmbeddingVector{1, 0.5}),
+	}
+	tests := []struct {
+		metric domain.SimilarityMetric
+		top    string
+		score  float32
+	}{
+		{domain.SimilarityCosine, "long", 1},
+		{domain.SimilarityDot, "long", 10},
+		{domain.SimilarityEuclidean, "near", 1 / 1.5},
+	}
+
+	for name, newStore := range vectorStoreFactories(t) {
+		for _, tt := range tests {
+			t.Run(name+"/"+string(tt.metric), func(t *testing.T) {
+				store := newStore()
+				store.(interface {
+					SetSimilarityMetric(domain.SimilarityMetric)
+				}).SetSimilarityMetric(tt.metric)
+				if err := store.StoreBatch(ctx, "p", chunks); err != nil {
+					t.Fatal(err)
+				}
+				results, err := store.Search(ctx, "p", query, 2, 0, nil)
+				if err != nil {
+					t.Fatalf("Search failed: %v", err)
+				}
+				if len(results) != 2 || results[0].Chunk.ID != tt.top {
+					t.Fatalf("expected %s first, got %+v", tt.top, results)
+				}
+				if d := results[0].Score - tt.score; d > 1e-5 || d < -1e-5 {
+					t.Errorf("expected score %v, got %v", tt.score, results[0].Score)
+				}
+			})
+		}
+	}
+}
+
+func TestVectorStores_EuclideanScalesMinScore(t *testing.T) {
+	ctx := context.Background()
+	// unit vectors at cosine 0.4 and 0.2 to the query; a cosine threshold of
+	// 0.3 keeps only the first under every metric
+	query := domain.EmbeddingVector{1, 0}
+	chunks := []domain.EmbeddedChunk{
+		testChunk("close", "a.go", 1, domain.EmbeddingVector{0.4, float32(math.Sqrt(0.84))}),
+		testChunk("far", "b.go", 1, domain.EmbeddingVector{0.2, float32(math.Sqrt(0.96))}),
+	}
+	for name, newStore := range vectorStoreFactories(t) {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			store.(interface {
+				SetSimilarityMetric(domain.SimilarityMetric)
+			}).SetSimilarityMetric(domain.SimilarityEuclidean)
+			if err := store.StoreBatch(ctx, "p", chunks); err != nil {
+				t.Fatal(err)
+			}
+			results, err := store.Search(ctx, "p", query, 2, 0.3, nil)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(results) != 1 || results[0].Chunk.ID != "close" {
+				t.Fatalf("expected only close, got %+v", results)
+			}
+		})
+	}
+}
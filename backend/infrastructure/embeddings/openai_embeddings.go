@@ -4,13 +4,22 @@ import (
 	"context"
 	"fmt"
 	"shotgun_code/domain"
+	"shotgun_code/infrastructure/ai/common"
+	"sync"
 
 	"github.com/sashabaranov/go-openai"
 )
 
+// OpenAI limits a single embeddings request to 2048 inputs and 300k tokens in total
+const (
+	openAIMaxBatchInputs = 2048
+	openAIMaxBatchTokens = 300000
+)
+
 // OpenAIEmbeddingProvider implements EmbeddingProvider using OpenAI API
 type OpenAIEmbeddingProvider struct {
 	client *openai.Client
+	mu     sync.RWMutex
 	model  domain.EmbeddingModel
 	log    domain.Logger
 }
@@ -21,7 +30,9 @@ func NewOpenAIEmbeddingProvider(apiKey string, model domain.EmbeddingModel, log
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	client := openai.NewClient(apiKey)
+	config := openai.DefaultConfig(apiKey)
+	config.HTTPClient = common.NewRetryAfterClient()
+	client := openai.NewClientWithConfig(config)
 
 	return &OpenAIEmbeddingProvider{
 		client: client,
@@ -38,7 +49,7 @@ func (p *OpenAIEmbeddingProvider) GenerateEmbeddings(ctx context.Context, req do
 
 	model := req.Model
 	if model == "" {
-		model = p.model
+		model = p.currentModel()
 	}
 
 	// Map domain model to OpenAI model
@@ -47,13 +58,14 @@ func (p *OpenAIEmbeddingProvider) GenerateEmbeddings(ctx context.Context, req do
 	p.log.Info(fmt.Sprintf("Generating embeddings for %d texts using model %s", len(req.Texts), openaiModel))
 
 	// OpenAI supports batch embedding
+	ctx, retryAfter := common.WithRetryAfter(ctx)
 	resp, err := p.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
 		Input: req.Texts,
 		Model: openaiModel,
 	})
 	if err != nil {
 		p.log.Error(fmt.Sprintf("Failed to generate embeddings: %v", err))
-		return nil, fmt.Errorf("failed to generate embeddings: %w", err)
+		return nil, fmt.Errorf("failed to generate embeddings: %w", common.HandleOpenAIError("OpenAI", err, *retryAfter))
 	}
 
 	// Convert response
@@ -71,13 +83,30 @@ func (p *OpenAIEmbeddingProvider) GenerateEmbeddings(ctx context.Context, req do
 	}, nil
 }
 
+// SetModel switches the model used for requests that don't name one
+func (p *OpenAIEmbeddingProvider) SetModel(model domain.EmbeddingModel) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.model = model
+}
+
+func (p *OpenAIEmbeddingProvider) currentModel() domain.EmbeddingModel {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.model
+}
+
 // GetModelInfo returns information about the embedding model
 func (p *OpenAIEmbeddingProvider) GetModelInfo() domain.EmbeddingModelInfo {
+	model := p.currentModel()
 	return domain.EmbeddingModelInfo{
-		Model:      p.model,
-		Dimensions: p.model.Dimensions(),
+		Model:      model,
+		Dimensions: model.Dimensions(),
 		MaxTokens:  8191, // OpenAI embedding models support up to 8191 tokens
 		Provider:   "openai",
+
+		MaxBatchInputs: openAIMaxBatchInputs,
+		MaxBatchTokens: openAIMaxBatchTokens,
 	}
 }
 
@@ -88,8 +117,8 @@ func (p *OpenAIEmbeddingProvider) ValidateRequest(req domain.EmbeddingRequest) e
 	}
 
 	// OpenAI has a limit on batch size
-	if len(req.Texts) > 2048 {
-		return fmt.Errorf("batch size exceeds maximum of 2048")
+	if len(req.Texts) > openAIMaxBatchInputs {
+		return fmt.Errorf("batch size exceeds maximum of %d", openAIMaxBatchInputs)
 	}
 
 	// Check for empty texts
@@ -0,0 +1,55 @@
+package embeddings
+
+import (
+	"math"
+	"shotgun_code/domain"
+)
+
+// similarity scores two vectors with the given metric, higher is more similar.
+// Vectors of different lengths score 0.
+func similarity(metric domain.SimilarityMetric, a, b domain.EmbeddingVector) float32 {
+	switch metric {
+	case domain.SimilarityDot:
+		return dot(a, b)
+	case domain.SimilarityEuclidean:
+		if len(a) != len(b) {
+			return 0
+		}
+		var sum float64
+		for i := range a {
+			d := float64(a[i]) - float64(b[i])
+			sum += d * d
+		}
+		return float32(1 / (1 + math.Sqrt(sum)))
+	default:
+		return cosineSimilarity(a, b)
+	}
+}
+
+// prepareVector returns the form in which a vector is compared under the
+// metric: unit length for cosine, unchanged otherwise
+func prepareVector(metric domain.SimilarityMetric, v domain.EmbeddingVector) domain.EmbeddingVector {
+	if normalizedMetric(metric) {
+		return normalizeVector(v)
+	}
+	return v
+}
+
+// normalizedMetric reports whether vectors are normalized under the metric,
+// so that similarity is a plain dot product of prepared vectors
+func normalizedMetric(metric domain.SimilarityMetric) bool {
+	return metric != domain.SimilarityDot && metric != domain.SimilarityEuclidean
+}
+
+// scoreThreshold converts a minimum score calibrated for cosine similarity to
+// the scale of the metric. For euclidean it is the score of two unit vectors
+// at that cosine, since 1/(1+L2) is 1/(1+sqrt(2-2cos)) for them. Dot products
+// have no fixed range, so the threshold is kept as is; it means the same as
+// for cosine only for models with normalized embeddings.
+func scoreThreshold(metric domain.SimilarityMetric, minScore float32) float32 {
+	if metric != domain.SimilarityEuclidean || minScore <= 0 {
+		return minScore
+	}
+	cos := math.Min(float64(minScore), 1)
+	return float32(1 / (1 + math.Sqrt(2-2*cos)))
+}
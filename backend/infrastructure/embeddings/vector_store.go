@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"shotgun_code/domain"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +24,12 @@ type SQLiteVectorStore struct {
 	mu     sync.RWMutex
 	dbPath string
 	log    domain.Logger
+	metric domain.SimilarityMetric
+	model  domain.EmbeddingModelInfo // active model; unset skips the model check
+
+	annConfig ANNConfig
+	annMu     sync.Mutex
+	ann       map[string]*projectANN // projectID -> index
 }
 
 // NewSQLiteVectorStore creates a new SQLite-based vector store
@@ -40,9 +47,12 @@ func NewSQLiteVectorStore(dataDir string, log domain.Logger) (*SQLiteVectorStore
 	}
 
 	store := &SQLiteVectorStore{
-		db:     db,
-		dbPath: dbPath,
-		log:    log,
+		db:        db,
+		dbPath:    dbPath,
+		log:       log,
+		metric:    domain.SimilarityCosine,
+		annConfig: DefaultANNConfig(),
+		ann:       make(map[string]*projectANN),
 	}
 
 	if err := store.initSchema(); err != nil {
@@ -88,10 +98,18 @@ func (s *SQLiteVectorStore) initSchema() error {
 		total_files INTEGER DEFAULT 0,
 		dimensions INTEGER DEFAULT 0
 	);
+	
+	CREATE TABLE IF NOT EXISTS ann_index (
+		project_id TEXT PRIMARY KEY,
+		chunk_count INTEGER NOT NULL,
+		data BLOB NOT NULL
+	);
 	`
 
-	_, err := s.db.Exec(schema)
-	return err
+	if _, err := s.db.Exec(schema); err != nil {
+		return err
+	}
+	return s.ensureColumn("projects", "embedding_model", "TEXT NOT NULL DEFAULT ''")
 }
 
 // Store stores an embedded chunk
@@ -104,6 +122,11 @@ func (s *SQLiteVectorStore) Store(ctx context.Context, projectID string, chunk d
 		return fmt.Errorf("failed to encode embedding: %w", err)
 	}
 
+	if err := s.checkEmbeddingModel(ctx, projectID, len(chunk.Embedding)); err != nil {
+		return err
+	}
+	s.prepareANN(ctx, projectID)
+
 	query := `
 	INSERT OR REPLACE INTO embeddings 
 	(id, project_id, file_path, content, start_line, end_line, chunk_type, 
@@ -128,8 +151,17 @@ func (s *SQLiteVectorStore) Store(ctx context.Context, projectID string, chunk d
 		chunk.CreatedAt,
 		chunk.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
+	if err := s.recordEmbeddingModel(ctx, projectID, len(chunk.Embedding)); err != nil {
+		return fmt.Errorf("failed to record embedding model: %w", err)
+	}
 
-	return err
+	s.updateANN(ctx, projectID, func(index *hnswIndex) {
+		index.Insert(chunk.Chunk.ID, chunk.Embedding)
+	})
+	return nil
 }
 
 // StoreBatch stores multiple embedded chunks efficiently
@@ -137,6 +169,20 @@ func (s *SQLiteVectorStore) StoreBatch(ctx context.Context, projectID string, ch
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if len(chunks) == 0 {
+		return nil
+	}
+	dimensions := len(chunks[0].Embedding)
+	for _, chunk := range chunks {
+		if len(chunk.Embedding) != dimensions {
+			return fmt.Errorf("chunks have embeddings of %d and %d dimensions", dimensions, len(chunk.Embedding))
+		}
+	}
+	if err := s.checkEmbeddingModel(ctx, projectID, dimensions); err != nil {
+		return err
+	}
+	s.prepareANN(ctx, projectID)
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -182,22 +228,48 @@ func (s *SQLiteVectorStore) StoreBatch(ctx context.Context, projectID string, ch
 		}
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if err := s.recordEmbeddingModel(ctx, projectID, dimensions); err != nil {
+		return fmt.Errorf("failed to record embedding model: %w", err)
+	}
+
+	s.updateANN(ctx, projectID, func(index *hnswIndex) {
+		for _, chunk := range chunks {
+			index.Insert(chunk.Chunk.ID, chunk.Embedding)
+		}
+	})
+	return nil
 }
 
-// Search performs vector similarity search using cosine similarity
-func (s *SQLiteVectorStore) Search(ctx context.Context, projectID string, query domain.EmbeddingVector, topK int, minScore float32) ([]domain.SemanticSearchResult, error) {
+// Search performs vector similarity search using the store's similarity metric.
+// minScore is calibrated for cosine similarity and scaled to the metric.
+func (s *SQLiteVectorStore) Search(ctx context.Context, projectID string, query domain.EmbeddingVector, topK int, minScore float32, filters *domain.SearchFilters) ([]domain.SemanticSearchResult, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	minScore = scoreThreshold(s.metric, minScore)
+
+	if err := s.checkEmbeddingModel(ctx, projectID, len(query)); err != nil {
+		return nil, err
+	}
 
-	// Load all embeddings for the project (for small-medium projects)
-	// For large projects, consider using approximate nearest neighbor algorithms
+	// Large projects use the HNSW index; small ones are searched exactly
+	if s.useANN(ctx, projectID) {
+		results, err := s.searchANN(ctx, projectID, query, topK, minScore, filters)
+		if err != nil || len(results) >= topK || filters.IsEmpty() {
+			return results, err
+		}
+		// Restrictive filters leave too few of the approximate candidates;
+		// scan the matching chunks exactly instead
+	}
+
+	filterSQL, filterArgs := searchFilterClause(filters)
 	rows, err := s.db.QueryContext(ctx, `
 	SELECT id, file_path, content, start_line, end_line, chunk_type, 
 	       symbol_name, symbol_kind, language, token_count, content_hash, embedding
 	FROM embeddings 
-	WHERE project_id = ?
-	`, projectID)
+	WHERE project_id = ?`+filterSQL, append([]any{projectID}, filterArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query embeddings: %w", err)
 	}
@@ -247,8 +319,7 @@ func (s *SQLiteVectorStore) Search(ctx context.Context, projectID string, query
 			continue
 		}
 
-		// Calculate cosine similarity
-		score := cosineSimilarity(query, embedding)
+		score := similarity(s.metric, query, embedding)
 
 		if score >= minScore {
 			results = append(results, scoredResult{chunk: chunk, score: score})
@@ -277,15 +348,72 @@ func (s *SQLiteVectorStore) Search(ctx context.Context, projectID string, query
 	return searchResults, nil
 }
 
+// searchFilterClause translates search filters into SQL conditions to append
+// to a WHERE clause; the semantics match domain.SearchFilters.Matches
+func searchFilterClause(filters *domain.SearchFilters) (string, []any) {
+	if filters.IsEmpty() {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	var args []any
+	in := func(column string, values []string) {
+		sb.WriteString(" AND " + column + " IN (" + strings.TrimSuffix(strings.Repeat("?,", len(values)), ",") + ")")
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+
+	if len(filters.Languages) > 0 {
+		in("language", filters.Languages)
+	}
+	if len(filters.ChunkTypes) > 0 {
+		chunkTypes := make([]string, len(filters.ChunkTypes))
+		for i, ct := range filters.ChunkTypes {
+			chunkTypes[i] = string(ct)
+		}
+		in("chunk_type", chunkTypes)
+	}
+	if len(filters.FilePaths) > 0 {
+		// instr() is case-sensitive and needs no escaping, unlike LIKE
+		conditions := make([]string, len(filters.FilePaths))
+		for i, prefix := range filters.FilePaths {
+			conditions[i] = "instr(file_path, ?) = 1"
+			args = append(args, prefix)
+		}
+		sb.WriteString(" AND (" + strings.Join(conditions, " OR ") + ")")
+	}
+	for _, dir := range filters.ExcludeDirs {
+		sb.WriteString(" AND instr(file_path, ?) = 0")
+		args = append(args, dir)
+	}
+	return sb.String(), args
+}
+
 // Delete removes embeddings for a file
 func (s *SQLiteVectorStore) Delete(ctx context.Context, projectID, filePath string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, err := s.db.ExecContext(ctx,
+	s.prepareANN(ctx, projectID)
+	ids, err := s.fileChunkIDs(ctx, projectID, filePath)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
 		"DELETE FROM embeddings WHERE project_id = ? AND file_path = ?",
 		projectID, filePath)
-	return err
+	if err != nil {
+		return err
+	}
+
+	s.updateANN(ctx, projectID, func(index *hnswIndex) {
+		for _, id := range ids {
+			index.Delete(id)
+		}
+	})
+	return nil
 }
 
 // DeleteProject removes all embeddings for a project
@@ -309,6 +437,10 @@ func (s *SQLiteVectorStore) DeleteProject(ctx context.Context, projectID string)
 		return err
 	}
 
+	if err := s.dropANN(ctx, tx, projectID); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -324,19 +456,22 @@ func (s *SQLiteVectorStore) GetStats(ctx context.Context, projectID string) (*do
 	SELECT 
 		COUNT(*) as total_chunks,
 		COUNT(DISTINCT file_path) as total_files,
-		COALESCE(SUM(token_count), 0) as total_tokens,
-		MAX(updated_at) as last_updated
+		COALESCE(SUM(token_count), 0) as total_tokens
 	FROM embeddings 
 	WHERE project_id = ?
 	`, projectID)
 
-	var lastUpdated sql.NullTime
-	err := row.Scan(&stats.TotalChunks, &stats.TotalFiles, &stats.TotalTokens, &lastUpdated)
+	err := row.Scan(&stats.TotalChunks, &stats.TotalFiles, &stats.TotalTokens)
 	if err != nil {
 		return nil, err
 	}
 
-	if lastUpdated.Valid {
+	// Selected as a plain column: MAX() drops the DATETIME type and the driver
+	// would return a string that can't be scanned into time.Time
+	var lastUpdated sql.NullTime
+	row = s.db.QueryRowContext(ctx,
+		"SELECT updated_at FROM embeddings WHERE project_id = ? ORDER BY updated_at DESC LIMIT 1", projectID)
+	if err := row.Scan(&lastUpdated); err == nil && lastUpdated.Valid {
 		stats.LastUpdated = lastUpdated.Time
 	}
 
@@ -516,8 +651,12 @@ func (s *SQLiteVectorStore) GetFileHashes(ctx context.Context, projectID, filePa
 	return hashes, nil
 }
 
-// Close closes the database connection
+// Close persists changed ANN indexes and closes the database connection
 func (s *SQLiteVectorStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.saveDirtyANN(context.Background())
 	return s.db.Close()
 }
 
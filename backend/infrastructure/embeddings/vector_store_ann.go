@@ -0,0 +1,454 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"shotgun_code/domain"
+	"sort"
+	"strings"
+)
+
+// ANNConfig controls the approximate nearest neighbor (HNSW) index of SQLiteVectorStore
+type ANNConfig struct {
+	Enabled        bool
+	MinChunks      int // projects with fewer chunks are searched exactly
+	M              int // graph degree: higher = better recall, more memory
+	EfConstruction int // build-time candidate list size: higher = better graph, slower inserts
+	EfSearch       int // query-time candidate list size: higher = more accurate, slower search
+}
+
+// DefaultANNConfig returns the default ANN configuration
+func DefaultANNConfig() ANNConfig {
+	return ANNConfig{
+		Enabled:        true,
+		MinChunks:      5000,
+		M:              16,
+		EfConstruction: 200,
+		EfSearch:       100,
+	}
+}
+
+// maxANNTombstoneRatio is the share of deleted nodes after which the graph is compacted
+const maxANNTombstoneRatio = 0.3
+
+// projectANN is the in-memory ANN index of a project
+type projectANN struct {
+	index     *hnswIndex
+	persisted bool // the stored copy in ann_index matches the in-memory index
+}
+
+// annSnapshot is the persisted form of an HNSW graph. Vectors of live nodes
+// are read back from the embeddings table; only tombstones keep theirs here.
+type annSnapshot struct {
+	M              int
+	EfConstruction int
+	Metric         domain.SimilarityMetric // empty in indexes saved before metrics were configurable: cosine
+	Entry          int32
+	MaxLevel       int
+	Nodes          []annSnapshotNode
+}
+
+type annSnapshotNode struct {
+	ID        string
+	Level     int
+	Neighbors [][]int32
+	Deleted   bool
+	Vector    []float32
+}
+
+// SetANNConfig changes the ANN configuration. Indexes built with other graph
+// parameters are rebuilt on the next search.
+func (s *SQLiteVectorStore) SetANNConfig(cfg ANNConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.annMu.Lock()
+	defer s.annMu.Unlock()
+	s.annConfig = cfg
+	s.ann = make(map[string]*projectANN)
+}
+
+// SetSimilarityMetric changes how embeddings are compared. Stored embeddings
+// are kept as generated, so switching metrics needs no re-indexing; ANN
+// indexes built for another metric are rebuilt on the next search.
+func (s *SQLiteVectorStore) SetSimilarityMetric(metric domain.SimilarityMetric) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.annMu.Lock()
+	defer s.annMu.Unlock()
+	s.metric = metric
+	s.ann = make(map[string]*projectANN)
+}
+
+// useANN reports whether the project is large enough for approximate search
+func (s *SQLiteVectorStore) useANN(ctx context.Context, projectID string) bool {
+	if !s.annConfig.Enabled {
+		return false
+	}
+	var count int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM embeddings WHERE project_id = ?", projectID).Scan(&count)
+	return err == nil && count >= s.annConfig.MinChunks
+}
+
+// annFilterOversampling is how many more candidates are retrieved when results are filtered
+const annFilterOversampling = 10
+
+// searchANN finds the topK most similar chunks using the project's HNSW index.
+// With filters, more candidates are retrieved and filtered when loading chunks,
+// so fewer than topK results may be returned.
+func (s *SQLiteVectorStore) searchANN(ctx context.Context, projectID string, query domain.EmbeddingVector, topK int, minScore float32, filters *domain.SearchFilters) ([]domain.SemanticSearchResult, error) {
+	index, err := s.annIndex(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	k := topK
+	if !filters.IsEmpty() {
+		k = topK * annFilterOversampling
+	}
+	candidates := index.Search(query, k, max(s.annConfig.EfSearch, k))
+	scores := make(map[string]float32, len(candidates))
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		if c.score < minScore {
+			continue
+		}
+		id := index.nodes[c.node].id
+		scores[id] = c.score
+		ids = append(ids, id)
+	}
+
+	chunks, err := s.chunksByID(ctx, projectID, ids, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.SemanticSearchResult, 0, len(chunks))
+	for _, chunk := range chunks {
+		results = append(results, domain.SemanticSearchResult{Chunk: chunk, Score: scores[chunk.ID]})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+// annIndex returns the project's index, loading it from disk or building it if needed
+func (s *SQLiteVectorStore) annIndex(ctx context.Context, projectID string) (*hnswIndex, error) {
+	s.annMu.Lock()
+	defer s.annMu.Unlock()
+
+	if p, ok := s.ann[projectID]; ok {
+		return p.index, nil
+	}
+
+	index, err := s.loadANN(ctx, projectID)
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to load ANN index, rebuilding: %v", err))
+	}
+	if index != nil {
+		s.ann[projectID] = &projectANN{index: index, persisted: true}
+		return index, nil
+	}
+
+	index, err = s.buildANN(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build ANN index: %w", err)
+	}
+	p := &projectANN{index: index}
+	s.ann[projectID] = p
+	if err := s.saveANN(ctx, projectID, index); err != nil {
+		s.log.Warning(fmt.Sprintf("Failed to persist ANN index: %v", err))
+	} else {
+		p.persisted = true
+	}
+	return index, nil
+}
+
+// prepareANN loads a persisted index before the project's embeddings change, so
+// that it can be updated incrementally instead of being rebuilt from scratch
+func (s *SQLiteVectorStore) prepareANN(ctx context.Context, projectID string) {
+	if !s.annConfig.Enabled {
+		return
+	}
+	s.annMu.Lock()
+	defer s.annMu.Unlock()
+
+	if _, ok := s.ann[projectID]; ok {
+		return
+	}
+	index, err := s.loadANN(ctx, projectID)
+	if err != nil {
+		s.log.Warning(fmt.Sprintf("Discarding stale ANN index: %v", err))
+		_, _ = s.db.ExecContext(ctx, "DELETE FROM ann_index WHERE project_id = ?", projectID)
+		return
+	}
+	if index != nil {
+		s.ann[projectID] = &projectANN{index: index, persisted: true}
+	}
+}
+
+// updateANN applies a change to the project's in-memory index, if there is one.
+// The persisted copy becomes stale and is removed until the index is saved again.
+func (s *SQLiteVectorStore) updateANN(ctx context.Context, projectID string, update func(index *hnswIndex)) {
+	s.annMu.Lock()
+	defer s.annMu.Unlock()
+
+	p, ok := s.ann[projectID]
+	if !ok {
+		return
+	}
+	update(p.index)
+	if p.index.tombstoneRatio() > maxANNTombstoneRatio {
+		p.index.Compact()
+	}
+	if p.persisted {
+		if _, err := s.db.ExecContext(ctx, "DELETE FROM ann_index WHERE project_id = ?", projectID); err != nil {
+			s.log.Warning(fmt.Sprintf("Failed to invalidate persisted ANN index: %v", err))
+			return
+		}
+		p.persisted = false
+	}
+}
+
+// dropANN forgets the project's index in memory and on disk
+func (s *SQLiteVectorStore) dropANN(ctx context.Context, tx *sql.Tx, projectID string) error {
+	s.annMu.Lock()
+	delete(s.ann, projectID)
+	s.annMu.Unlock()
+	_, err := tx.ExecContext(ctx, "DELETE FROM ann_index WHERE project_id = ?", projectID)
+	return err
+}
+
+// saveDirtyANN persists indexes that changed since they were last saved
+func (s *SQLiteVectorStore) saveDirtyANN(ctx context.Context) {
+	s.annMu.Lock()
+	defer s.annMu.Unlock()
+
+	for projectID, p := range s.ann {
+		if p.persisted {
+			continue
+		}
+		if err := s.saveANN(ctx, projectID, p.index); err != nil {
+			s.log.Warning(fmt.Sprintf("Failed to persist ANN index: %v", err))
+			continue
+		}
+		p.persisted = true
+	}
+}
+
+// buildANN builds an index over all embeddings of the project
+func (s *SQLiteVectorStore) buildANN(ctx context.Context, projectID string) (*hnswIndex, error) {
+	vectors, err := s.projectVectors(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Insert in a fixed order so the graph doesn't depend on map iteration
+	ids := make([]string, 0, len(vectors))
+	for id := range vectors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	index := newHNSWIndex(s.annConfig.M, s.annConfig.EfConstruction, s.metric)
+	for _, id := range ids {
+		index.Insert(id, vectors[id])
+	}
+	return index, nil
+}
+
+// loadANN restores a persisted index. It returns nil without error if there is
+// none, and an error if it doesn't match the current embeddings or config.
+func (s *SQLiteVectorStore) loadANN(ctx context.Context, projectID string) (*hnswIndex, error) {
+	var chunkCount int
+	var data []byte
+	err := s.db.QueryRowContext(ctx,
+		"SELECT chunk_count, data FROM ann_index WHERE project_id = ?", projectID).Scan(&chunkCount, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot annSnapshot
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode ANN index: %w", err)
+	}
+	if snapshot.Metric == "" {
+		snapshot.Metric = domain.SimilarityCosine
+	}
+	if snapshot.M != s.annConfig.M || snapshot.EfConstruction != s.annConfig.EfConstruction || snapshot.Metric != s.metric {
+		return nil, errors.New("ANN index was built with different parameters")
+	}
+
+	vectors, err := s.projectVectors(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != chunkCount {
+		return nil, fmt.Errorf("ANN index covers %d chunks, store has %d", chunkCount, len(vectors))
+	}
+
+	index := newHNSWIndex(snapshot.M, snapshot.EfConstruction, snapshot.Metric)
+	index.entry = snapshot.Entry
+	index.maxLevel = snapshot.MaxLevel
+	index.nodes = make([]*hnswNode, len(snapshot.Nodes))
+	for i, n := range snapshot.Nodes {
+		node := &hnswNode{id: n.ID, level: n.Level, neighbors: n.Neighbors, deleted: n.Deleted}
+		if n.Deleted {
+			node.vector = n.Vector
+			index.deleted++
+		} else {
+			vector, ok := vectors[n.ID]
+			if !ok {
+				return nil, fmt.Errorf("ANN index references missing chunk %s", n.ID)
+			}
+			node.vector = prepareVector(index.metric, vector)
+			index.ids[n.ID] = int32(i)
+		}
+		index.nodes[i] = node
+	}
+	if index.Len() != len(vectors) {
+		return nil, errors.New("ANN index doesn't cover all chunks")
+	}
+	return index, nil
+}
+
+// saveANN persists the graph of an index
+func (s *SQLiteVectorStore) saveANN(ctx context.Context, projectID string, index *hnswIndex) error {
+	snapshot := annSnapshot{
+		M:              index.m,
+		EfConstruction: index.efConstruction,
+		Metric:         index.metric,
+		Entry:          index.entry,
+		MaxLevel:       index.maxLevel,
+		Nodes:          make([]annSnapshotNode, len(index.nodes)),
+	}
+	for i, node := range index.nodes {
+		snapshot.Nodes[i] = annSnapshotNode{ID: node.id, Level: node.level, Neighbors: node.neighbors, Deleted: node.deleted}
+		if node.deleted {
+			snapshot.Nodes[i].Vector = node.vector
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to encode ANN index: %w", err)
+	}
+	_, err := s.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO ann_index (project_id, chunk_count, data) VALUES (?, ?, ?)",
+		projectID, index.Len(), buf.Bytes())
+	return err
+}
+
+// projectVectors reads all embeddings of a project keyed by chunk ID
+func (s *SQLiteVectorStore) projectVectors(ctx context.Context, projectID string) (map[string]domain.EmbeddingVector, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, embedding FROM embeddings WHERE project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vectors := make(map[string]domain.EmbeddingVector)
+	for rows.Next() {
+		var id string
+		var embeddingBytes []byte
+		if err := rows.Scan(&id, &embeddingBytes); err != nil {
+			return nil, err
+		}
+		embedding, err := decodeEmbedding(embeddingBytes)
+		if err != nil {
+			return nil, err
+		}
+		vectors[id] = embedding
+	}
+	return vectors, rows.Err()
+}
+
+// fileChunkIDs returns the IDs of all chunks of a file
+func (s *SQLiteVectorStore) fileChunkIDs(ctx context.Context, projectID, filePath string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id FROM embeddings WHERE project_id = ? AND file_path = ?", projectID, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// chunksByID loads chunks (without embeddings) by their IDs, skipping those that don't match the filters
+func (s *SQLiteVectorStore) chunksByID(ctx context.Context, projectID string, ids []string, filters *domain.SearchFilters) ([]domain.CodeChunk, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, projectID)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	filterSQL, filterArgs := searchFilterClause(filters)
+	args = append(args, filterArgs...)
+
+	rows, err := s.db.QueryContext(ctx, `
+	SELECT id, file_path, content, start_line, end_line, chunk_type,
+	       symbol_name, symbol_kind, language, token_count, content_hash
+	FROM embeddings
+	WHERE project_id = ? AND id IN (`+placeholders+`)`+filterSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []domain.CodeChunk
+	for rows.Next() {
+		var chunk domain.CodeChunk
+		var chunkType string
+		var symbolName, symbolKind sql.NullString
+
+		err := rows.Scan(
+			&chunk.ID,
+			&chunk.FilePath,
+			&chunk.Content,
+			&chunk.StartLine,
+			&chunk.EndLine,
+			&chunkType,
+			&symbolName,
+			&symbolKind,
+			&chunk.Language,
+			&chunk.TokenCount,
+			&chunk.Hash,
+		)
+		if err != nil {
+			continue
+		}
+
+		chunk.ChunkType = domain.ChunkType(chunkType)
+		if symbolName.Valid {
+			chunk.SymbolName = symbolName.String
+		}
+		if symbolKind.Valid {
+			chunk.SymbolKind = symbolKind.String
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, rows.Err()
+}
@@ -0,0 +1,109 @@
+package embeddings
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"shotgun_code/domain"
+)
+
+// SetEmbeddingModel sets the model that generates the embeddings stored and
+// searched from now on. Projects indexed with another model, or with vectors
+// of another dimension, can't be searched until they are re-indexed.
+func (s *SQLiteVectorStore) SetEmbeddingModel(info domain.EmbeddingModelInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.model = info
+}
+
+// CheckEmbeddingModel returns a domain.ErrCodeReindexRequired error if the
+// project was indexed with another embedding model than the active one
+func (s *SQLiteVectorStore) CheckEmbeddingModel(ctx context.Context, projectID string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.checkEmbeddingModel(ctx, projectID, s.model.Dimensions)
+}
+
+// checkEmbeddingModel compares the project's indexed model and dimensions with
+// the active model and the given vector dimensions (0 to skip that check)
+func (s *SQLiteVectorStore) checkEmbeddingModel(ctx context.Context, projectID string, dimensions int) error {
+	model, indexedDimensions, err := s.indexedEmbeddingModel(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to read indexed embedding model: %w", err)
+	}
+
+	active := string(s.model.Model)
+	if model != "" && active != "" && model != active {
+		return domain.NewReindexRequiredError(projectID, model, active)
+	}
+	if indexedDimensions > 0 && dimensions > 0 && indexedDimensions != dimensions {
+		return domain.NewReindexRequiredError(projectID, dimensionsLabel(indexedDimensions), dimensionsLabel(dimensions))
+	}
+	return nil
+}
+
+// indexedEmbeddingModel returns the model and vector dimensions a project was
+// indexed with. Projects indexed before models were recorded report no model
+// and the dimensions of a stored vector.
+func (s *SQLiteVectorStore) indexedEmbeddingModel(ctx context.Context, projectID string) (string, int, error) {
+	var model string
+	var dimensions int
+	err := s.db.QueryRowContext(ctx,
+		"SELECT embedding_model, dimensions FROM projects WHERE id = ?", projectID).Scan(&model, &dimensions)
+	if err == nil && dimensions > 0 {
+		return model, dimensions, nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", 0, err
+	}
+
+	var embeddingBytes []byte
+	err = s.db.QueryRowContext(ctx,
+		"SELECT embedding FROM embeddings WHERE project_id = ? LIMIT 1", projectID).Scan(&embeddingBytes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model, 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	embedding, err := decodeEmbedding(embeddingBytes)
+	if err != nil {
+		return "", 0, err
+	}
+	return model, len(embedding), nil
+}
+
+// recordEmbeddingModel records the active model and the dimensions of the
+// vectors stored for a project
+func (s *SQLiteVectorStore) recordEmbeddingModel(ctx context.Context, projectID string, dimensions int) error {
+	_, err := s.db.ExecContext(ctx, `
+	INSERT INTO projects (id, root_path, dimensions, embedding_model) VALUES (?, '', ?, ?)
+	ON CONFLICT(id) DO UPDATE SET dimensions = excluded.dimensions, embedding_model = excluded.embedding_model
+	`, projectID, dimensions, string(s.model.Model))
+	return err
+}
+
+// ensureColumn adds a column to tables created by older versions
+func (s *SQLiteVectorStore) ensureColumn(table, column, columnType string) error {
+	rows, err := s.db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err == nil && name == column {
+			return nil
+		}
+	}
+	_, err = s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, columnType))
+	return err
+}
+
+func dimensionsLabel(dimensions int) string {
+	return strconv.Itoa(dimensions) + "-dimensional vectors"
+}
@@ -0,0 +1,69 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"shotgun_code/domain"
+	"testing"
+)
+
+func isReindexRequired(err error) bool {
+	var domainErr *domain.DomainError
+	return errors.As(err, &domainErr) && domainErr.Code == domain.ErrCodeReindexRequired
+}
+
+func TestSQLiteVectorStore_EmbeddingModelChange(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := NewSQLiteVectorStore(dir, &domain.NoopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.SetEmbeddingModel(domain.EmbeddingModelInfo{Model: "model-a", Dimensions: 8})
+
+	vectors := randomVectors(10, 8, 1)
+	if err := store.StoreBatch(ctx, "p", annTestChunks(vectors)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Search(ctx, "p", vectors[0], 3, 0, nil); err != nil {
+		t.Fatalf("Search with the indexing model failed: %v", err)
+	}
+
+	// A query of another dimension is refused rather than scored as garbage
+	if _, err := store.Search(ctx, "p", randomVectors(1, 4, 2)[0], 3, 0, nil); !isReindexRequired(err) {
+		t.Errorf("expected a re-index error for a dimension mismatch, got %v", err)
+	}
+
+	// The model is recorded, so it is checked after reopening the store
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	store, err = NewSQLiteVectorStore(dir, &domain.NoopLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	store.SetEmbeddingModel(domain.EmbeddingModelInfo{Model: "model-b", Dimensions: 8})
+
+	if _, err := store.Search(ctx, "p", vectors[0], 3, 0, nil); !isReindexRequired(err) {
+		t.Errorf("expected a re-index error after the model changed, got %v", err)
+	}
+	if err := store.CheckEmbeddingModel(ctx, "p"); !isReindexRequired(err) {
+		t.Errorf("expected CheckEmbeddingModel to report the change, got %v", err)
+	}
+	if err := store.StoreBatch(ctx, "p", annTestChunks(vectors)); !isReindexRequired(err) {
+		t.Errorf("expected vectors of the new model to be refused, got %v", err)
+	}
+
+	// Clearing the project lets it be indexed with the new model
+	if err := store.DeleteProject(ctx, "p"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.StoreBatch(ctx, "p", annTestChunks(vectors)); err != nil {
+		t.Fatalf("StoreBatch after clearing failed: %v", err)
+	}
+	if err := store.CheckEmbeddingModel(ctx, "p"); err != nil {
+		t.Errorf("expected the re-indexed project to match, got %v", err)
+	}
+}
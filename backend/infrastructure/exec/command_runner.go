@@ -2,40 +2,169 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os/exec"
+	osexec "os/exec"
 	"path/filepath"
+	"runtime"
 	"shotgun_code/domain"
 	"shotgun_code/internal/executil"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // CommandRunnerImpl реализует интерфейс CommandRunner для выполнения команд
 type CommandRunnerImpl struct {
-	log domain.Logger
+	log       domain.Logger
+	executor  *executil.Executor
+	mu        sync.RWMutex
+	timeout   time.Duration
+	maxOutput int
+	allowed   map[string]bool // nil - разрешены любые команды
+}
+
+// DefaultAllowedCommands - исполняемые файлы, которые запускают сборка, тесты,
+// статический анализ, исправления и форматтеры; остальные отклоняются в
+// недоверенных режимах. node и java не входят: они выполняют любой переданный код
+var DefaultAllowedCommands = []string{
+	"black", "clang-format", "clang-tidy", "eslint", "git", "go", "gofmt", "goimports",
+	"gradle", "javac", "mvn", "npm", "npx", "prettier", "pyright", "python", "python3",
+	"ruff", "staticcheck", "tsc",
+}
+
+// launcherArgs ограничивает аргументы разрешенных команд, которые иначе
+// запускают произвольный код (go run, npx <любой пакет>, python -c)
+var launcherArgs = map[string]func(args []string) bool{
+	"go":      allowedGoArgs,
+	"npm":     firstArgIn("ci", "install", "run"),
+	"npx":     firstArgIn("eslint", "prettier", "tsc"),
+	"python":  allowedPythonArgs,
+	"python3": allowedPythonArgs,
 }
 
 // NewCommandRunnerImpl создает новый экземпляр CommandRunnerImpl
 func NewCommandRunnerImpl(log domain.Logger) *CommandRunnerImpl {
-	return &CommandRunnerImpl{
-		log: log,
+	c := &CommandRunnerImpl{
+		log:       log,
+		timeout:   executil.DefaultCommandTimeout,
+		maxOutput: executil.DefaultMaxOutputBytes,
+	}
+	c.executor = executil.NewExecutor(c.warnLimits)
+	c.executor.SetCommandCheck(c.checkAllowed)
+	return c
+}
+
+// Executor возвращает исполнитель с лимитами раннера; его используют сборка,
+// статический анализ и тесты, чтобы лимиты из настроек действовали и на них
+func (c *CommandRunnerImpl) Executor() *executil.Executor {
+	return c.executor
+}
+
+// SetTimeout задает лимит времени на одну команду; 0 отключает лимит.
+// Более ранний дедлайн переданного контекста по-прежнему действует
+func (c *CommandRunnerImpl) SetTimeout(timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timeout = timeout
+}
+
+// SetLimits применяет лимиты из настроек: таймаут и размер захваченного вывода
+// (0 оставляет значения по умолчанию), память дерева процессов команды и
+// процессорное время каждого процесса. Лимиты памяти и CPU действуют и на
+// команды, запущенные через Executor
+func (c *CommandRunnerImpl) SetLimits(limits domain.CommandLimits) {
+	timeout := executil.DefaultCommandTimeout
+	if limits.TimeoutSeconds > 0 {
+		timeout = time.Duration(limits.TimeoutSeconds) * time.Second
+	}
+	maxOutput := executil.DefaultMaxOutputBytes
+	if limits.MaxOutputKB > 0 {
+		maxOutput = limits.MaxOutputKB << 10
 	}
+	c.executor.SetLimits(executil.Limits{
+		MemoryBytes: uint64(max(limits.MemoryMB, 0)) << 20,
+		CPUSeconds:  uint64(max(limits.CPUSeconds, 0)),
+	})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timeout = timeout
+	c.maxOutput = maxOutput
+}
+
+// SetAllowedCommands ограничивает RunCommand, RunCommandInDir и команды Executor
+// исполняемыми файлами из names (по имени без пути и расширения .exe); nil
+// снимает ограничение. Включается в серверном и CLI режимах, где шаги и планы
+// приходят извне
+func (c *CommandRunnerImpl) SetAllowedCommands(names []string) {
+	var allowed map[string]bool
+	if names != nil {
+		allowed = make(map[string]bool, len(names))
+		for _, name := range names {
+			allowed[commandKey(name)] = true
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.allowed = allowed
+}
+
+// SetEnv задает переменные окружения, добавляемые к окружению приложения
+// для каждой команды раннера и Executor; nil их сбрасывает
+func (c *CommandRunnerImpl) SetEnv(env map[string]string) {
+	c.executor.SetEnv(env)
+}
+
+// LoadProjectEnv применяет commandEnv из .shotgun/config.json проекта.
+// Переменные, меняющие запускаемые программы и библиотеки (PATH, LD_PRELOAD и
+// т.п.), игнорируются: конфиг приходит вместе с недоверенным репозиторием
+func (c *CommandRunnerImpl) LoadProjectEnv(projectRoot string) error {
+	config, err := domain.LoadProjectConfig(projectRoot)
+	if err != nil {
+		c.SetEnv(nil)
+		return err
+	}
+	env, rejected := executil.FilterUnsafeEnv(config.CommandEnv)
+	if len(rejected) > 0 {
+		c.log.Warning(fmt.Sprintf("Ignoring unsafe commandEnv variables from %s: %s", projectRoot, strings.Join(rejected, ", ")))
+	}
+	if len(env) > 0 {
+		names := make([]string, 0, len(env))
+		for name := range env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		c.log.Info(fmt.Sprintf("Applying commandEnv from %s: %s", projectRoot, strings.Join(names, ", ")))
+	}
+	c.SetEnv(env)
+	return nil
 }
 
 // RunCommand выполняет команду с заданным контекстом и аргументами
 func (c *CommandRunnerImpl) RunCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
 	c.log.Debug(fmt.Sprintf("Executing command: %s %v", name, args))
 
-	cmd := exec.CommandContext(ctx, name, args...)
-	executil.HideWindow(cmd)
-	output, err := cmd.CombinedOutput()
+	if err := c.checkAllowed(name, args); err != nil {
+		return nil, err
+	}
+
+	timeout, limits := c.config()
+	ctx, cancel := executil.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := c.command(ctx, name, args...)
+	output, err := executil.RunStreaming(cmd, limits, c.warnLimits, domain.CommandOutputFunc(ctx))
 
 	if err != nil {
-		c.log.Warning(fmt.Sprintf("Command failed: %s %v - %v", name, args, err))
-		return output, fmt.Errorf("command %s failed: %w", name, err)
+		cmdErr := c.commandError(ctx, timeout, "", name, args, output, err)
+		c.log.Warning(fmt.Sprintf("Command failed: %s %v - %v", name, args, cmdErr.Err))
+		return output.Combined, cmdErr
 	}
 
 	c.log.Debug(fmt.Sprintf("Command succeeded: %s %v", name, args))
-	return output, nil
+	return output.Combined, nil
 }
 
 // RunCommandInDir выполняет команду в указанной директории
@@ -46,17 +175,146 @@ func (c *CommandRunnerImpl) RunCommandInDir(ctx context.Context, dir, name strin
 	if !filepath.IsAbs(dir) {
 		return nil, fmt.Errorf("directory path must be absolute: %s", dir)
 	}
+	if err := c.checkAllowed(name, args); err != nil {
+		return nil, err
+	}
+
+	timeout, limits := c.config()
+	ctx, cancel := executil.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	cmd := exec.CommandContext(ctx, name, args...)
-	executil.HideWindow(cmd)
+	cmd := c.command(ctx, name, args...)
 	cmd.Dir = dir
-	output, err := cmd.CombinedOutput()
+	output, err := executil.RunStreaming(cmd, limits, c.warnLimits, domain.CommandOutputFunc(ctx))
 
 	if err != nil {
-		c.log.Warning(fmt.Sprintf("Command failed in directory %s: %s %v - %v", dir, name, args, err))
-		return output, fmt.Errorf("command %s failed in directory %s: %w", name, dir, err)
+		cmdErr := c.commandError(ctx, timeout, dir, name, args, output, err)
+		c.log.Warning(fmt.Sprintf("Command failed in directory %s: %s %v - %v", dir, name, args, cmdErr.Err))
+		return output.Combined, cmdErr
 	}
 
 	c.log.Debug(fmt.Sprintf("Command succeeded in directory %s: %s %v", dir, name, args))
-	return output, nil
+	return output.Combined, nil
+}
+
+// checkAllowed отклоняет команду, которой нет в списке разрешенных, и запуск
+// разрешенной команды с аргументами вне launcherArgs. Путь принимается, только
+// если его же дает поиск имени в PATH: под разрешенным именем мог оказаться
+// любой файл
+func (c *CommandRunnerImpl) checkAllowed(name string, args []string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.allowed == nil {
+		return nil
+	}
+	key := commandKey(name)
+	if c.allowed[key] && (!strings.ContainsAny(name, `/\`) || resolvesFromPath(name)) {
+		allowedArgs, limited := launcherArgs[key]
+		if !limited || allowedArgs(args) {
+			return nil
+		}
+		name = strings.Join(append([]string{name}, args...), " ")
+	}
+	allowed := make([]string, 0, len(c.allowed))
+	for key := range c.allowed {
+		allowed = append(allowed, key)
+	}
+	sort.Strings(allowed)
+	c.log.Warning(fmt.Sprintf("Rejected command not on the allowlist: %s", name))
+	return domain.NewCommandNotAllowedError(name, allowed)
+}
+
+// resolvesFromPath сообщает, что name - тот же файл, который дает поиск его
+// имени в PATH (так передают найденные через LookPath python и pyright)
+func resolvesFromPath(name string) bool {
+	path, err := osexec.LookPath(filepath.Base(name))
+	return err == nil && path == name
+}
+
+// firstArgIn разрешает только команды, первый аргумент которых есть в values
+func firstArgIn(values ...string) func(args []string) bool {
+	return func(args []string) bool {
+		return len(args) > 0 && slices.Contains(values, args[0])
+	}
+}
+
+// allowedGoArgs разрешает подкоманды сборки, тестов и модулей без флагов,
+// подменяющих запускаемые компилятором и тестами программы
+func allowedGoArgs(args []string) bool {
+	if !firstArgIn("build", "list", "mod", "test", "version", "vet")(args) {
+		return false
+	}
+	for _, arg := range args[1:] {
+		flag, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && (flag == "exec" || flag == "toolexec" || flag == "vettool") {
+			return false
+		}
+	}
+	return true
+}
+
+// allowedPythonArgs разрешает только компиляцию модулей: python [-X опция]... -m compileall
+func allowedPythonArgs(args []string) bool {
+	for len(args) >= 2 && args[0] == "-X" {
+		args = args[2:]
+	}
+	return len(args) >= 2 && args[0] == "-m" && args[1] == "compileall"
+}
+
+// commandKey приводит имя исполняемого файла к виду для сравнения со списком;
+// в Windows имена файлов не зависят от регистра
+func commandKey(name string) string {
+	name = filepath.Base(name)
+	if runtime.GOOS == "windows" {
+		name = strings.ToLower(name)
+	}
+	return strings.TrimSuffix(name, ".exe")
+}
+
+// command создает команду с окружением приложения и переменными из SetEnv
+func (c *CommandRunnerImpl) command(ctx context.Context, name string, args ...string) *osexec.Cmd {
+	return c.executor.Command(ctx, name, args...)
+}
+
+// config возвращает текущие таймаут и лимиты
+func (c *CommandRunnerImpl) config() (time.Duration, executil.Limits) {
+	limits := c.executor.Limits()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	limits.MaxOutputBytes = c.maxOutput
+	return c.timeout, limits
+}
+
+// warnLimits сообщает, что лимиты не удалось применить; команда выполняется без них
+func (c *CommandRunnerImpl) warnLimits(err error) {
+	c.log.Warning(fmt.Sprintf("Running command without resource limits: %v", err))
+}
+
+// commandError собирает CommandError с выводом упавшей команды
+func (c *CommandRunnerImpl) commandError(ctx context.Context, timeout time.Duration, dir, name string, args []string, output executil.Output, err error) *domain.CommandError {
+	return &domain.CommandError{
+		Command:  name,
+		Args:     args,
+		Dir:      dir,
+		Stdout:   output.Stdout,
+		Stderr:   output.Stderr,
+		ExitCode: executil.ExitCode(err),
+		Err:      c.contextError(ctx, timeout, err),
+	}
+}
+
+// contextError заменяет "signal: killed" на причину отмены, чтобы таймаут
+// можно было распознать через errors.Is(err, context.DeadlineExceeded)
+func (c *CommandRunnerImpl) contextError(ctx context.Context, timeout time.Duration, err error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		if executil.KilledByCPULimit(err) {
+			return fmt.Errorf("exceeded CPU time limit: %w", err)
+		}
+		return err
+	}
+	if errors.Is(ctxErr, context.DeadlineExceeded) && timeout > 0 {
+		return fmt.Errorf("timed out (limit %s): %w", timeout, ctxErr)
+	}
+	return fmt.Errorf("%w: %v", ctxErr, err)
 }
@@ -0,0 +1,312 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"shotgun_code/domain"
+	"shotgun_code/internal/executil"
+)
+
+func TestCommandRunner_TimeoutKillsProcessTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	runner.SetTimeout(200 * time.Millisecond)
+
+	// The background sleep keeps stdout open; only killing the whole process
+	// group lets the command return before the wait delay
+	start := time.Now()
+	_, err := runner.RunCommandInDir(context.Background(), t.TempDir(), "sh", "-c", "sleep 30 & sleep 30")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("command took %s to stop after timeout", elapsed)
+	}
+}
+
+func TestCommandRunner_Cancellation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	_, err := runner.RunCommand(ctx, "sleep", "30")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+}
+
+func TestCommandRunner_CommandError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	ctx := context.Background()
+
+	output, err := runner.RunCommand(ctx, "sh", "-c", "echo out; echo 'main.go:3: undefined: x' >&2; exit 2")
+	var cmdErr *domain.CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("expected CommandError, got %v", err)
+	}
+	if cmdErr.ExitCode != 2 || string(cmdErr.Stdout) != "out\n" || string(cmdErr.Stderr) != "main.go:3: undefined: x\n" {
+		t.Errorf("unexpected command error %+v", cmdErr)
+	}
+	if !strings.Contains(err.Error(), "undefined: x") {
+		t.Errorf("stderr missing from error message: %v", err)
+	}
+	if !strings.Contains(string(output), "out") || !strings.Contains(string(output), "undefined: x") {
+		t.Errorf("expected combined output, got %q", output)
+	}
+
+	// Success keeps returning stdout and stderr together
+	output, err = runner.RunCommand(ctx, "sh", "-c", "echo out; echo warn >&2")
+	if err != nil || !strings.Contains(string(output), "out") || !strings.Contains(string(output), "warn") {
+		t.Errorf("unexpected success output %q, %v", output, err)
+	}
+}
+
+// recordingBus collects emitted command output lines
+type recordingBus struct {
+	mu    sync.Mutex
+	lines []domain.CommandOutputLine
+}
+
+func (b *recordingBus) Emit(eventName string, data ...interface{}) {
+	if eventName != domain.EventCommandOutput {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines = append(b.lines, data[0].(domain.CommandOutputLine))
+}
+
+func TestCommandRunner_StreamsOutputLines(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	bus := &recordingBus{}
+	ctx := domain.WithCommandStep(domain.WithCommandOutput(context.Background(), bus), "task_1", "compile")
+
+	if _, err := runner.RunCommand(ctx, "sh", "-c", "echo one; echo two; echo oops >&2; printf tail"); err != nil {
+		t.Fatalf("RunCommand failed: %v", err)
+	}
+
+	var stdout []string
+	var stderr []string
+	for _, line := range bus.lines {
+		if line.TaskID != "task_1" || line.Step != "compile" {
+			t.Errorf("line not routed to the step: %+v", line)
+		}
+		if line.Stream == domain.CommandStreamStderr {
+			stderr = append(stderr, line.Line)
+		} else {
+			stdout = append(stdout, line.Line)
+		}
+	}
+	if strings.Join(stdout, ",") != "one,two,tail" || strings.Join(stderr, ",") != "oops" {
+		t.Errorf("unexpected streamed lines stdout=%v stderr=%v", stdout, stderr)
+	}
+}
+
+func TestCommandRunner_CPULimitKillsRunawayProcess(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resource limits are only enforced on Linux")
+	}
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	runner.SetLimits(domain.CommandLimits{TimeoutSeconds: 30, CPUSeconds: 1})
+
+	_, err := runner.RunCommandInDir(context.Background(), t.TempDir(), "sh", "-c", "while :; do :; done")
+
+	if err == nil || !strings.Contains(err.Error(), "exceeded CPU time limit") {
+		t.Fatalf("expected CPU limit error, got %v", err)
+	}
+}
+
+func TestCommandRunner_MemoryLimitCapsProcessTree(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resource limits are only enforced on Linux")
+	}
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	runner.SetLimits(domain.CommandLimits{TimeoutSeconds: 30, MemoryMB: 64})
+
+	// Two subshells each hold 48MB: every process stays under the cap, the tree doesn't
+	hold := `x=$(head -c 50331648 /dev/zero | tr '\000' a); sleep 20`
+	script := "(" + hold + ") & (" + hold + ") & wait; echo done"
+	start := time.Now()
+	output, err := runner.RunCommandInDir(context.Background(), t.TempDir(), "sh", "-c", script)
+
+	if !errors.Is(err, executil.ErrMemoryLimitExceeded) {
+		t.Fatalf("expected memory limit error, got %v (output %q)", err, output)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("process tree killed only after %s", elapsed)
+	}
+}
+
+func TestCommandRunner_TruncatesLongOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	runner.SetLimits(domain.CommandLimits{MaxOutputKB: 1})
+	script := `echo first; i=0; while [ $i -lt 2000 ]; do echo "line $i"; i=$((i+1)); done; echo last`
+
+	output, err := runner.RunCommand(context.Background(), "sh", "-c", script)
+	if err != nil {
+		t.Fatalf("RunCommand failed: %v", err)
+	}
+
+	text := string(output)
+	if !strings.HasPrefix(text, "first\n") || !strings.HasSuffix(text, "last\n") {
+		t.Errorf("expected head and tail to be kept, got %q", text)
+	}
+	if !strings.Contains(text, "bytes truncated]") {
+		t.Errorf("expected a truncation marker, got %q", text)
+	}
+	if len(output) > 2<<10 {
+		t.Errorf("output not capped: %d bytes", len(output))
+	}
+}
+
+func TestCommandRunner_InjectsEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	t.Setenv("SHOTGUN_TEST_BASE", "inherited")
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	runner.SetEnv(map[string]string{
+		"CGO_ENABLED":       "0",
+		"SHOTGUN_TEST_BASE": "extra:$SHOTGUN_TEST_BASE",
+	})
+
+	output, err := runner.RunCommandInDir(context.Background(), t.TempDir(), "sh", "-c", `echo "$CGO_ENABLED $SHOTGUN_TEST_BASE"; command -v sh >/dev/null && echo path-ok`)
+	if err != nil {
+		t.Fatalf("RunCommandInDir failed: %v", err)
+	}
+	if got := string(output); got != "0 extra:inherited\npath-ok\n" {
+		t.Errorf("unexpected environment output %q", got)
+	}
+}
+
+func TestCommandRunner_LoadProjectEnvIgnoresUnsafeVariables(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	projectRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectRoot, ".shotgun"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"commandEnv": {"CGO_ENABLED": "0", "PATH": "/tmp/evil", "LD_PRELOAD": "/tmp/evil.so"}}`
+	if err := os.WriteFile(filepath.Join(projectRoot, ".shotgun", "config.json"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	if err := runner.LoadProjectEnv(projectRoot); err != nil {
+		t.Fatalf("LoadProjectEnv failed: %v", err)
+	}
+
+	output, err := runner.RunCommandInDir(context.Background(), projectRoot, "sh", "-c", `echo "$CGO_ENABLED|$PATH|$LD_PRELOAD"`)
+	if err != nil {
+		t.Fatalf("RunCommandInDir failed: %v", err)
+	}
+	want := "0|" + os.Getenv("PATH") + "|" + os.Getenv("LD_PRELOAD") + "\n"
+	if got := string(output); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if env := runner.Executor().Environ(); !slices.Contains(env, "CGO_ENABLED=0") {
+		t.Error("expected the executor to share the project env")
+	}
+}
+
+func TestCommandRunner_AllowedCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	runner.SetAllowedCommands([]string{"sh"})
+	ctx := context.Background()
+
+	if _, err := runner.RunCommand(ctx, "sh", "-c", "true"); err != nil {
+		t.Fatalf("allowed command failed: %v", err)
+	}
+	for _, name := range []string{"sleep", "/bin/sh", "./sh"} {
+		_, err := runner.RunCommandInDir(ctx, t.TempDir(), name, "0")
+		var domainErr *domain.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodePermissionDenied {
+			t.Errorf("expected %s to be rejected, got %v", name, err)
+		}
+	}
+
+	runner.SetAllowedCommands(nil)
+	if _, err := runner.RunCommand(ctx, "sleep", "0"); err != nil {
+		t.Errorf("expected nil allowlist to allow any command, got %v", err)
+	}
+}
+
+func TestCommandRunner_AllowedCommandsLimitLaunchers(t *testing.T) {
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	runner.SetAllowedCommands(DefaultAllowedCommands)
+
+	for _, args := range [][]string{
+		{"go", "run", "main.go"},
+		{"go", "test", "-exec=sh", "./..."},
+		{"go", "vet", "-vettool", "/tmp/tool", "./..."},
+		{"npx", "--yes", "some-package"},
+		{"python3", "-c", "print(1)"},
+		{"node", "-e", "1"},
+	} {
+		err := runner.checkAllowed(args[0], args[1:])
+		var domainErr *domain.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodePermissionDenied {
+			t.Errorf("expected %v to be rejected, got %v", args, err)
+		}
+	}
+	for _, args := range [][]string{
+		{"go", "test", "-v", "-timeout", "30s", "./..."},
+		{"npx", "tsc", "--noEmit"},
+		{"python3", "-X", "pycache_prefix=/tmp/cache", "-m", "compileall", "-q", "."},
+	} {
+		if err := runner.checkAllowed(args[0], args[1:]); err != nil {
+			t.Errorf("expected %v to be allowed, got %v", args, err)
+		}
+	}
+}
+
+func TestCommandRunner_AllowedCommandsApplyToExecutor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	runner := NewCommandRunnerImpl(&domain.NoopLogger{})
+	runner.SetAllowedCommands([]string{"go"})
+	executor := runner.Executor()
+
+	_, err := executor.CombinedOutput(executor.Command(context.Background(), "sh", "-c", "true"), nil)
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.ErrCodePermissionDenied {
+		t.Errorf("expected the executor to reject sh, got %v", err)
+	}
+	if err := executor.Command(context.Background(), "sh", "-c", "true").Run(); !errors.As(err, &domainErr) {
+		t.Errorf("expected commands started directly to be rejected too, got %v", err)
+	}
+
+	runner.SetAllowedCommands(nil)
+	if _, err := executor.CombinedOutput(executor.Command(context.Background(), "sh", "-c", "true"), nil); err != nil {
+		t.Errorf("expected nil allowlist to allow any command, got %v", err)
+	}
+}
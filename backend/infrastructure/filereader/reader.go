@@ -157,6 +157,23 @@ func (r *secureFileReader) ReadContents(
 	return r.readFileContents(ctx, validated, totalSize, progress)
 }
 
+// ExpandPaths lists the files ReadContents would read for filePaths: files as
+// given and the files of directories relative to rootDir, within the same limits.
+// Paths that cannot be read are left out.
+func (r *secureFileReader) ExpandPaths(filePaths []string, rootDir string) []string {
+	var files []string
+	for _, inputPath := range filePaths {
+		results, err := r.validateAndExpandPath(inputPath, rootDir)
+		if err != nil {
+			continue
+		}
+		for _, res := range results {
+			files = append(files, res.inputPath)
+		}
+	}
+	return files
+}
+
 // sanitizeAndAbs converts path to absolute, allowing files from any location
 func (r *secureFileReader) sanitizeAndAbs(rootDir, relPath string) (string, error) {
 	// If path is already absolute, use it directly
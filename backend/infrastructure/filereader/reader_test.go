@@ -173,3 +173,35 @@ func getKeys(m map[string]string) []string {
 	}
 	return keys
 }
+
+func TestExpandPaths_MatchesReadContents(t *testing.T) {
+	rootDir := t.TempDir()
+	for _, name := range []string{"main.go", "pkg/a.go", "pkg/.cache/b.go"} {
+		path := filepath.Join(rootDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reader := NewSecureFileReader(&MockLogger{})
+	paths := []string{"main.go", "pkg", "missing.go"}
+	files := reader.(interface {
+		ExpandPaths(filePaths []string, rootDir string) []string
+	}).ExpandPaths(paths, rootDir)
+
+	contents, err := reader.ReadContents(context.Background(), paths, rootDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(contents) {
+		t.Fatalf("expected %d files, got %v", len(contents), files)
+	}
+	for _, file := range files {
+		if _, ok := contents[file]; !ok {
+			t.Errorf("expanded %s was not read", file)
+		}
+	}
+}
@@ -139,6 +139,11 @@ func (b *fileTreeBuilder) processEntry(path string, d fs.DirEntry, err error, di
 
 // checkIgnored checks if path matches gitignore or custom ignore
 func (b *fileTreeBuilder) checkIgnored(relPath string, isDir bool, gi, ci *gitignore.GitIgnore) (isGitIgnored, isCustomIgnored bool) {
+	return matchIgnoreRules(relPath, isDir, gi, ci)
+}
+
+// matchIgnoreRules matches a relative path against gitignore and custom ignore rules
+func matchIgnoreRules(relPath string, isDir bool, gi, ci *gitignore.GitIgnore) (isGitIgnored, isCustomIgnored bool) {
 	matchPath := relPath
 	if isDir && !strings.HasSuffix(matchPath, string(filepath.Separator)) {
 		matchPath += string(filepath.Separator)
@@ -368,15 +373,7 @@ func (b *fileTreeBuilder) getGitignore(root string) *gitignore.GitIgnore {
 }
 
 func (b *fileTreeBuilder) getCustomIgnore() *gitignore.GitIgnore {
-	rules := strings.ReplaceAll(b.settingsRepo.GetCustomIgnoreRules(), "\r\n", "\n")
-	trimmed := []string{}
-	for _, line := range strings.Split(rules, "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		trimmed = append(trimmed, line)
-	}
+	trimmed := parseCustomIgnoreRules(b.settingsRepo.GetCustomIgnoreRules())
 	hash := strings.Join(trimmed, "\n")
 
 	b.mu.RLock()
@@ -398,3 +395,42 @@ func (b *fileTreeBuilder) getCustomIgnore() *gitignore.GitIgnore {
 	b.mu.Unlock()
 	return ci
 }
+
+// parseCustomIgnoreRules splits custom ignore rules into non-empty, non-comment lines
+func parseCustomIgnoreRules(rules string) []string {
+	rules = strings.ReplaceAll(rules, "\r\n", "\n")
+	trimmed := []string{}
+	for _, line := range strings.Split(rules, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		trimmed = append(trimmed, line)
+	}
+	return trimmed
+}
+
+// NewIgnoreMatcher returns a matcher that applies the same .gitignore and
+// custom ignore rules as BuildTree, so other components (e.g. file watchers)
+// can skip exactly the paths the tree omits. relPath is relative to rootDir.
+func NewIgnoreMatcher(settingsRepo domain.SettingsRepository, rootDir string) func(relPath string, isDir bool) bool {
+	var gi, ci *gitignore.GitIgnore
+	if settingsRepo.GetUseGitignore() {
+		if ig, err := gitignore.CompileIgnoreFile(filepath.Join(rootDir, ".gitignore")); err == nil {
+			gi = ig
+		}
+	}
+	if settingsRepo.GetUseCustomIgnore() {
+		if rules := parseCustomIgnoreRules(settingsRepo.GetCustomIgnoreRules()); len(rules) > 0 {
+			ci = gitignore.CompileIgnoreLines(rules...)
+		}
+	}
+
+	return func(relPath string, isDir bool) bool {
+		if isDir && filepath.Base(relPath) == ".git" {
+			return true
+		}
+		isGi, isCi := matchIgnoreRules(relPath, isDir, gi, ci)
+		return isGi || isCi
+	}
+}
@@ -41,6 +41,40 @@ func (f *fakeSettingsRepo) GetUseGitignore() bool           { return true }
 func (f *fakeSettingsRepo) SetUseGitignore(bool)            {}
 func (f *fakeSettingsRepo) GetUseCustomIgnore() bool        { return true }
 func (f *fakeSettingsRepo) SetUseCustomIgnore(bool)         {}
+func (f *fakeSettingsRepo) GetPreciseGoAnalysis() bool      { return false }
+func (f *fakeSettingsRepo) SetPreciseGoAnalysis(bool)       {}
+func (f *fakeSettingsRepo) GetEmbeddingProvider() string    { return "" }
+func (f *fakeSettingsRepo) SetEmbeddingProvider(string)     {}
+func (f *fakeSettingsRepo) GetEmbeddingModel() string       { return "" }
+func (f *fakeSettingsRepo) SetEmbeddingModel(string)        {}
+func (f *fakeSettingsRepo) GetVectorStore() string          { return "" }
+func (f *fakeSettingsRepo) SetVectorStore(string)           {}
+func (f *fakeSettingsRepo) GetSimilarityMetric() string     { return "" }
+func (f *fakeSettingsRepo) SetSimilarityMetric(string)      {}
+func (f *fakeSettingsRepo) GetBackgroundIndexing() bool     { return true }
+func (f *fakeSettingsRepo) SetBackgroundIndexing(bool)      {}
+func (f *fakeSettingsRepo) GetAIAuditIncludePrompts() bool  { return false }
+func (f *fakeSettingsRepo) SetAIAuditIncludePrompts(bool)   {}
+func (f *fakeSettingsRepo) GetSafeMode() bool               { return false }
+func (f *fakeSettingsRepo) SetSafeMode(bool)                {}
+func (f *fakeSettingsRepo) GetCommandLimits() domain.CommandLimits {
+	return domain.CommandLimits{}
+}
+func (f *fakeSettingsRepo) SetCommandLimits(domain.CommandLimits) {}
+func (f *fakeSettingsRepo) GetEnabledLanguages() []string         { return nil }
+func (f *fakeSettingsRepo) SetEnabledLanguages([]string)          {}
+func (f *fakeSettingsRepo) GetAIProviderFallback() []string       { return nil }
+func (f *fakeSettingsRepo) SetAIProviderFallback([]string)        {}
+func (f *fakeSettingsRepo) GetOptimizationProfile() string        { return "" }
+func (f *fakeSettingsRepo) SetOptimizationProfile(string)         {}
+func (f *fakeSettingsRepo) GetCustomOptimization() domain.ContentOptimizeOptions {
+	return domain.ContentOptimizeOptions{}
+}
+func (f *fakeSettingsRepo) SetCustomOptimization(domain.ContentOptimizeOptions) {}
+func (f *fakeSettingsRepo) GetVerificationGates() map[string]domain.VerificationGate {
+	return nil
+}
+func (f *fakeSettingsRepo) SetVerificationGates(map[string]domain.VerificationGate) {}
 func (f *fakeSettingsRepo) GetRecentProjects() []domain.RecentProjectInfo {
 	return nil
 }
@@ -104,3 +138,24 @@ func TestBuildTree_CustomIgnore(t *testing.T) {
 		t.Errorf("kept.txt should exist")
 	}
 }
+
+func TestNewIgnoreMatcher(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	matcher := NewIgnoreMatcher(&fakeSettingsRepo{custom: "node_modules/\n"}, dir)
+	if !matcher("debug.log", false) {
+		t.Errorf("debug.log should be ignored by .gitignore")
+	}
+	if !matcher("node_modules", true) {
+		t.Errorf("node_modules should be ignored by custom rules")
+	}
+	if !matcher(".git", true) {
+		t.Errorf(".git should always be ignored")
+	}
+	if matcher("main.go", false) {
+		t.Errorf("main.go should not be ignored")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"shotgun_code/domain"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +21,9 @@ const (
 	debounceDelay = 500 * time.Millisecond
 )
 
+// IgnoreFunc reports whether a path relative to the watched root should be ignored
+type IgnoreFunc func(relPath string, isDir bool) bool
+
 type Watcher struct {
 	log           domain.Logger
 	bus           domain.EventBus
@@ -29,19 +33,71 @@ type Watcher struct {
 	rootDir       string
 	appCtx        context.Context
 	debounceTimer *time.Timer
+	debounceDelay time.Duration
 	pendingFiles  map[string]struct{}
 	debounceMu    sync.Mutex
+	ignore        IgnoreFunc
+	onChanges     []func(rootDir string, files []string)
 }
 
 func New(ctx context.Context, bus domain.EventBus) (*Watcher, error) {
 	return &Watcher{
-		appCtx:       ctx,
-		log:          wailsLogger{ctx: ctx},
-		bus:          bus,
-		pendingFiles: make(map[string]struct{}),
+		appCtx:        ctx,
+		log:           wailsLogger{ctx: ctx},
+		bus:           bus,
+		debounceDelay: debounceDelay,
+		pendingFiles:  make(map[string]struct{}),
+	}, nil
+}
+
+// NewWithLogger creates a watcher that logs through the given logger instead
+// of the Wails runtime, for use outside the desktop app (e.g. the CLI).
+func NewWithLogger(log domain.Logger, bus domain.EventBus) (*Watcher, error) {
+	return &Watcher{
+		appCtx:        context.Background(),
+		log:           log,
+		bus:           bus,
+		debounceDelay: debounceDelay,
+		pendingFiles:  make(map[string]struct{}),
 	}, nil
 }
 
+// SetDebounceDelay sets how long to wait for more changes before emitting events.
+// Takes effect for changes scheduled after the call.
+func (w *Watcher) SetDebounceDelay(delay time.Duration) {
+	w.debounceMu.Lock()
+	defer w.debounceMu.Unlock()
+	w.debounceDelay = delay
+}
+
+// SetIgnoreFunc sets an additional filter for directories and change events,
+// applied on the next Start.
+func (w *Watcher) SetIgnoreFunc(ignore IgnoreFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.ignore = ignore
+}
+
+// OnFilesChanged registers a callback invoked with each debounced batch of
+// changed files (absolute paths), so a burst of events is handled once
+func (w *Watcher) OnFilesChanged(callback func(rootDir string, files []string)) {
+	w.debounceMu.Lock()
+	defer w.debounceMu.Unlock()
+	w.onChanges = append(w.onChanges, callback)
+}
+
+// isIgnored checks a path against the custom ignore filter
+func (w *Watcher) isIgnored(rootDir, path string, isDir bool, ignore IgnoreFunc) bool {
+	if ignore == nil {
+		return false
+	}
+	relPath, err := filepath.Rel(rootDir, path)
+	if err != nil || relPath == "." {
+		return false
+	}
+	return ignore(relPath, isDir)
+}
+
 func (w *Watcher) shouldSkipDir(name string) bool {
 	// Общий набор шумных директорий
 	switch name {
@@ -74,7 +130,7 @@ func (w *Watcher) Start(path string) error {
 			return err
 		}
 		if d.IsDir() {
-			if w.shouldSkipDir(d.Name()) {
+			if w.shouldSkipDir(d.Name()) || w.isIgnored(w.rootDir, p, true, w.ignore) {
 				w.log.Info("Watcher: skip dir " + p)
 				return filepath.SkipDir
 			}
@@ -86,7 +142,7 @@ func (w *Watcher) Start(path string) error {
 		return err
 	}
 
-	go w.run(ctx)
+	go w.run(ctx, w.rootDir, w.ignore)
 	w.log.Info("Наблюдатель запущен для: " + path)
 	return nil
 }
@@ -105,7 +161,7 @@ func (w *Watcher) Stop() {
 	}
 }
 
-func (w *Watcher) run(ctx context.Context) {
+func (w *Watcher) run(ctx context.Context, rootDir string, ignore IgnoreFunc) {
 	defer func() {
 		w.mu.Lock()
 		if w.fsWatcher != nil {
@@ -131,6 +187,9 @@ func (w *Watcher) run(ctx context.Context) {
 				// Пропускаем события из .git
 				continue
 			}
+			if w.isIgnored(rootDir, name, false, ignore) {
+				continue
+			}
 
 			// Debounce: collect events and emit after delay
 			w.scheduleDebounce(name)
@@ -156,7 +215,7 @@ func (w *Watcher) scheduleDebounce(filePath string) {
 		w.debounceTimer.Stop()
 	}
 
-	w.debounceTimer = time.AfterFunc(debounceDelay, func() {
+	w.debounceTimer = time.AfterFunc(w.debounceDelay, func() {
 		w.flushPendingEvents()
 	})
 }
@@ -169,15 +228,25 @@ func (w *Watcher) flushPendingEvents() {
 		files = append(files, f)
 	}
 	w.pendingFiles = make(map[string]struct{})
+	callbacks := slices.Clone(w.onChanges)
 	w.debounceMu.Unlock()
 
-	if len(files) > 0 {
-		// Emit single event with all changed files
-		w.bus.Emit("projectFilesChanged", w.rootDir)
-		// Also emit individual file events for fine-grained updates
-		for _, f := range files {
-			w.bus.Emit("fileChanged", f)
-		}
+	if len(files) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	rootDir := w.rootDir
+	w.mu.Unlock()
+
+	// Emit single event with all changed files
+	w.bus.Emit("projectFilesChanged", rootDir)
+	// Also emit individual file events for fine-grained updates
+	for _, f := range files {
+		w.bus.Emit("fileChanged", f)
+	}
+	for _, callback := range callbacks {
+		callback(rootDir, files)
 	}
 }
 
@@ -0,0 +1,143 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"shotgun_code/internal/executil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultCoChangeWindow is the number of recent commits used for co-change statistics
+	defaultCoChangeWindow = 500
+	// maxCoChangeCommitFiles skips commits touching more files (mass renames, reformatting),
+	// which say little about related files and would blow up the matrix
+	maxCoChangeCommitFiles = 100
+	// commitSeparator marks the start of a commit in git log output (%x1e in the format)
+	commitSeparator = "\x1e"
+)
+
+// coChangeMatrix counts how often pairs of files were changed in the same commit
+type coChangeMatrix struct {
+	head   string                    // HEAD revision the matrix was built for
+	counts map[string]map[string]int // file -> co-changed file -> commits
+}
+
+// SetCoChangeWindow sets how many recent commits GetCoChangedFiles looks at
+// and drops the cached co-change matrix
+func (b *ContextBuilder) SetCoChangeWindow(commits int) {
+	b.coChangeMu.Lock()
+	defer b.coChangeMu.Unlock()
+	if commits <= 0 {
+		commits = defaultCoChangeWindow
+	}
+	b.coChangeWindow = commits
+	b.coChange = nil
+}
+
+// GetCoChangedFiles returns files that are often changed together with the given file.
+// Counts come from a co-change matrix that is built once and rebuilt when HEAD moves.
+func (b *ContextBuilder) GetCoChangedFiles(filePath string, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	matrix, err := b.getCoChangeMatrix()
+	if err != nil {
+		return nil, err
+	}
+
+	related := matrix.counts[filepath.ToSlash(filePath)]
+	sorted := make([]string, 0, len(related))
+	for path := range related {
+		sorted = append(sorted, path)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if related[sorted[i]] != related[sorted[j]] {
+			return related[sorted[i]] > related[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted, nil
+}
+
+// getCoChangeMatrix returns the cached matrix, rebuilding it if HEAD changed
+func (b *ContextBuilder) getCoChangeMatrix() (*coChangeMatrix, error) {
+	head, err := b.headRevision()
+	if err != nil {
+		return nil, err
+	}
+
+	b.coChangeMu.Lock()
+	defer b.coChangeMu.Unlock()
+
+	if b.coChange != nil && b.coChange.head == head {
+		return b.coChange, nil
+	}
+
+	output, err := b.runGitCommand("log", "-n", strconv.Itoa(b.coChangeWindow), "--relative",
+		"--name-only", "--format=%x1e")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	b.coChange = &coChangeMatrix{head: head, counts: parseCoChangeMatrix(string(output))}
+	return b.coChange, nil
+}
+
+// parseCoChangeMatrix counts pairwise co-occurrence of files per commit
+func parseCoChangeMatrix(output string) map[string]map[string]int {
+	counts := make(map[string]map[string]int)
+	for _, commit := range strings.Split(output, commitSeparator) {
+		seen := make(map[string]bool)
+		var files []string
+		for _, line := range strings.Split(commit, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !seen[line] {
+				seen[line] = true
+				files = append(files, line)
+			}
+		}
+		if len(files) < 2 || len(files) > maxCoChangeCommitFiles {
+			continue
+		}
+
+		for _, a := range files {
+			row, ok := counts[a]
+			if !ok {
+				row = make(map[string]int)
+				counts[a] = row
+			}
+			for _, other := range files {
+				if other != a {
+					row[other]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// headRevision returns the commit HEAD points to
+func (b *ContextBuilder) headRevision() (string, error) {
+	output, err := b.runGitCommand("rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// runGitCommand runs git in the project root and returns its stdout
+func (b *ContextBuilder) runGitCommand(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	executil.HideWindow(cmd)
+	cmd.Dir = b.projectRoot
+	return cmd.Output()
+}
@@ -6,17 +6,22 @@ import (
 	"shotgun_code/internal/executil"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
 // ContextBuilder builds context from git history
 type ContextBuilder struct {
 	projectRoot string
+
+	coChangeMu     sync.Mutex
+	coChangeWindow int             // number of recent commits for co-change statistics
+	coChange       *coChangeMatrix // cached, rebuilt when HEAD changes
 }
 
 // NewContextBuilder creates a new git context builder
 func NewContextBuilder(projectRoot string) *ContextBuilder {
-	return &ContextBuilder{projectRoot: projectRoot}
+	return &ContextBuilder{projectRoot: projectRoot, coChangeWindow: defaultCoChangeWindow}
 }
 
 // RecentChange represents a recently changed file
@@ -27,13 +32,27 @@ type RecentChange struct {
 	Authors     []string
 }
 
+// RecentChangesOptions filters the history scanned by GetRecentChangesFiltered
+type RecentChangesOptions struct {
+	Since      string // passed to git log --since, defaults to "1 week ago"
+	Until      string // passed to git log --until, optional
+	Author     string // passed to git log --author (name or email pattern), optional
+	PathFilter string // limits the log to a path, optional
+}
+
 // GetRecentChanges returns files changed recently, sorted by relevance
 func (b *ContextBuilder) GetRecentChanges(since string, pathFilter string) ([]RecentChange, error) {
-	if since == "" {
-		since = "1 week ago"
+	return b.GetRecentChangesFiltered(RecentChangesOptions{Since: since, PathFilter: pathFilter})
+}
+
+// GetRecentChangesFiltered returns files changed in the given date range and
+// by the given author, sorted by relevance
+func (b *ContextBuilder) GetRecentChangesFiltered(opts RecentChangesOptions) ([]RecentChange, error) {
+	if opts.Since == "" {
+		opts.Since = "1 week ago"
 	}
 
-	output, err := b.runGitLog(since, pathFilter)
+	output, err := b.runGitLog(opts)
 	if err != nil {
 		return nil, err
 	}
@@ -43,10 +62,16 @@ func (b *ContextBuilder) GetRecentChanges(since string, pathFilter string) ([]Re
 }
 
 // runGitLog executes git log command
-func (b *ContextBuilder) runGitLog(since, pathFilter string) ([]byte, error) {
-	cmdArgs := []string{"log", "--since=" + since, "--name-only", "--format=%H|%an|%at"}
-	if pathFilter != "" {
-		cmdArgs = append(cmdArgs, "--", pathFilter)
+func (b *ContextBuilder) runGitLog(opts RecentChangesOptions) ([]byte, error) {
+	cmdArgs := []string{"log", "--since=" + opts.Since, "--name-only", "--format=%H|%an|%at"}
+	if opts.Until != "" {
+		cmdArgs = append(cmdArgs, "--until="+opts.Until)
+	}
+	if opts.Author != "" {
+		cmdArgs = append(cmdArgs, "--author="+opts.Author)
+	}
+	if opts.PathFilter != "" {
+		cmdArgs = append(cmdArgs, "--", opts.PathFilter)
 	}
 	cmd := exec.Command("git", cmdArgs...)
 	executil.HideWindow(cmd)
@@ -197,69 +222,6 @@ func (b *ContextBuilder) GetRelatedByAuthor(filePath string, limit int) ([]strin
 	return result, nil
 }
 
-// GetCoChangedFiles returns files that are often changed together with the given file
-func (b *ContextBuilder) GetCoChangedFiles(filePath string, limit int) ([]string, error) {
-	if limit <= 0 {
-		limit = 10
-	}
-
-	// Get commits that changed this file
-	cmd := exec.Command("git", "log", "--format=%H", "-n", "50", "--", filePath)
-	executil.HideWindow(cmd)
-	cmd.Dir = b.projectRoot
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, err
-	}
-
-	commits := strings.Split(strings.TrimSpace(string(output)), "\n")
-	fileCounts := make(map[string]int)
-
-	for _, commit := range commits {
-		if commit == "" {
-			continue
-		}
-		// Get files changed in this commit
-		cmd = exec.Command("git", "show", "--name-only", "--format=", commit)
-		executil.HideWindow(cmd)
-		cmd.Dir = b.projectRoot
-		output, err = cmd.Output()
-		if err != nil {
-			continue
-		}
-
-		for _, file := range strings.Split(string(output), "\n") {
-			file = strings.TrimSpace(file)
-			if file != "" && file != filePath {
-				fileCounts[file]++
-			}
-		}
-	}
-
-	// Sort by count
-	type fileCount struct {
-		path  string
-		count int
-	}
-	sorted := make([]fileCount, 0, len(fileCounts))
-	for path, count := range fileCounts {
-		sorted = append(sorted, fileCount{path, count})
-	}
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].count > sorted[j].count
-	})
-
-	result := make([]string, 0, limit)
-	for i, fc := range sorted {
-		if i >= limit {
-			break
-		}
-		result = append(result, fc.path)
-	}
-
-	return result, nil
-}
-
 // SuggestContextFiles suggests files to include in context based on git history
 func (b *ContextBuilder) SuggestContextFiles(taskDescription string, currentFiles []string, limit int) ([]string, error) {
 	if limit <= 0 {
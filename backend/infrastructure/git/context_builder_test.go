@@ -87,6 +87,36 @@ func TestContextBuilder_GetRecentChanges(t *testing.T) {
 	}
 }
 
+func TestContextBuilder_GetRecentChangesFiltered(t *testing.T) {
+	tmpDir := setupGitRepo(t)
+
+	writeFile(t, tmpDir, "alice.go", "package main")
+	runGit(t, tmpDir, "add", ".")
+	runGit(t, tmpDir, "-c", "user.name=Alice", "-c", "user.email=alice@test.com", "commit", "-m", "Alice change")
+
+	writeFile(t, tmpDir, "bob.go", "package main")
+	runGit(t, tmpDir, "add", ".")
+	runGit(t, tmpDir, "-c", "user.name=Bob", "-c", "user.email=bob@test.com", "commit", "-m", "Bob change")
+
+	cb := NewContextBuilder(tmpDir)
+
+	changes, err := cb.GetRecentChangesFiltered(RecentChangesOptions{Since: "10 years ago", Author: "Alice"})
+	if err != nil {
+		t.Fatalf("GetRecentChangesFiltered failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].FilePath != "alice.go" {
+		t.Errorf("expected only alice.go for author filter, got %+v", changes)
+	}
+
+	changes, err = cb.GetRecentChangesFiltered(RecentChangesOptions{Since: "10 years ago", Until: "1 day ago", Author: "Bob"})
+	if err != nil {
+		t.Fatalf("GetRecentChangesFiltered failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes before until, got %+v", changes)
+	}
+}
+
 func TestContextBuilder_GetCoChangedFiles(t *testing.T) {
 	tmpDir := setupGitRepo(t)
 
@@ -119,6 +149,47 @@ func TestContextBuilder_GetCoChangedFiles(t *testing.T) {
 	}
 }
 
+func TestContextBuilder_GetCoChangedFiles_CacheInvalidatedOnNewCommit(t *testing.T) {
+	tmpDir := setupGitRepo(t)
+
+	writeFile(t, tmpDir, "a.go", "package main")
+	writeFile(t, tmpDir, "b.go", "package main")
+	runGit(t, tmpDir, "add", ".")
+	runGit(t, tmpDir, "commit", "-m", "Add a and b")
+
+	cb := NewContextBuilder(tmpDir)
+	coChanged, err := cb.GetCoChangedFiles("a.go", 10)
+	if err != nil {
+		t.Fatalf("GetCoChangedFiles failed: %v", err)
+	}
+	if len(coChanged) != 1 || coChanged[0] != "b.go" {
+		t.Fatalf("expected [b.go], got %v", coChanged)
+	}
+
+	writeFile(t, tmpDir, "a.go", "package main\n// v2")
+	writeFile(t, tmpDir, "c.go", "package main")
+	runGit(t, tmpDir, "add", ".")
+	runGit(t, tmpDir, "commit", "-m", "Add c")
+
+	coChanged, err = cb.GetCoChangedFiles("a.go", 10)
+	if err != nil {
+		t.Fatalf("GetCoChangedFiles failed: %v", err)
+	}
+	if len(coChanged) != 2 {
+		t.Errorf("expected cache to be rebuilt after new commit, got %v", coChanged)
+	}
+
+	// A window of one commit only sees the latest change
+	cb.SetCoChangeWindow(1)
+	coChanged, err = cb.GetCoChangedFiles("a.go", 10)
+	if err != nil {
+		t.Fatalf("GetCoChangedFiles failed: %v", err)
+	}
+	if len(coChanged) != 1 || coChanged[0] != "c.go" {
+		t.Errorf("expected [c.go] with window of 1, got %v", coChanged)
+	}
+}
+
 func TestContextBuilder_GetRelatedByAuthor(t *testing.T) {
 	tmpDir := setupGitRepo(t)
 
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"shotgun_code/internal/executil"
+	"strings"
+)
+
+// CreateWorktree checks out a new branch created from baseRef into worktreePath
+func (r *Repository) CreateWorktree(projectPath, worktreePath, branch, baseRef string) error {
+	if _, err := r.runGit(projectPath, "worktree", "add", "-b", branch, worktreePath, baseRef); err != nil {
+		return fmt.Errorf("failed to create worktree for branch %s: %w", branch, err)
+	}
+	r.log.Info(fmt.Sprintf("Created worktree %s on branch %s", worktreePath, branch))
+	return nil
+}
+
+// RemoveWorktree removes a worktree and its files, discarding uncommitted changes.
+// The worktree's branch is kept.
+func (r *Repository) RemoveWorktree(projectPath, worktreePath string) error {
+	if _, err := r.runGit(projectPath, "worktree", "remove", "--force", worktreePath); err != nil {
+		return fmt.Errorf("failed to remove worktree %s: %w", worktreePath, err)
+	}
+	r.log.Info(fmt.Sprintf("Removed worktree %s", worktreePath))
+	return nil
+}
+
+// Commit commits the changes of files, or of the whole working tree if files
+// is empty, and returns the hash of the new commit. Other staged changes are
+// left out. Without changes to commit it returns an empty hash.
+func (r *Repository) Commit(projectPath, message string, files []string) (string, error) {
+	if _, err := r.runGit(projectPath, append([]string{"add", "-A", "--"}, files...)...); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+	if _, err := r.runGit(projectPath, append([]string{"diff", "--cached", "--quiet", "--"}, files...)...); err == nil {
+		return "", nil
+	}
+	if _, err := r.runGit(projectPath, append([]string{"commit", "-m", message, "--"}, files...)...); err != nil {
+		return "", fmt.Errorf("failed to commit changes: %w", err)
+	}
+	hash, err := r.runGit(projectPath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the new commit: %w", err)
+	}
+	return strings.TrimSpace(hash), nil
+}
+
+// GenerateDiffAgainst returns the diff between baseRef and the working tree
+func (r *Repository) GenerateDiffAgainst(projectPath, baseRef string) (string, error) {
+	output, err := r.runGit(projectPath, "diff", baseRef)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate diff against %s: %w", baseRef, err)
+	}
+	return output, nil
+}
+
+// MergeBranch merges branch into the checked out branch. It refuses to merge
+// into a checkout with uncommitted changes, and a merge that fails (e.g. on
+// conflicts) is aborted so the checkout is left as it was.
+func (r *Repository) MergeBranch(projectPath, branch string) error {
+	status, err := r.runGit(projectPath, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return fmt.Errorf("failed to check the working tree: %w", err)
+	}
+	if strings.TrimSpace(status) != "" {
+		return fmt.Errorf("cannot merge branch %s: %s has uncommitted changes", branch, projectPath)
+	}
+	if _, err := r.runGit(projectPath, "merge", "--no-edit", branch); err != nil {
+		if _, abortErr := r.runGit(projectPath, "merge", "--abort"); abortErr != nil {
+			r.log.Warning(fmt.Sprintf("Failed to abort merge of %s: %v", branch, abortErr))
+		}
+		return fmt.Errorf("failed to merge branch %s: %w", branch, err)
+	}
+	r.log.Info(fmt.Sprintf("Merged branch %s in %s", branch, projectPath))
+	return nil
+}
+
+// DeleteBranch force-deletes a local branch
+func (r *Repository) DeleteBranch(projectPath, branch string) error {
+	if _, err := r.runGit(projectPath, "branch", "-D", branch); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// runGit runs a git command in dir and returns its output; the error includes the output
+func (r *Repository) runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...) //nolint:gosec // Git command
+	executil.HideWindow(cmd)
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s - %w", strings.TrimSpace(string(output)), err)
+	}
+	return string(output), nil
+}
@@ -0,0 +1,117 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorktreeLifecycle(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := New(&testLogger{})
+	projectPath := setupTestGitRepo(t)
+	defer os.RemoveAll(projectPath)
+
+	commits, err := repo.GetCommitHistory(projectPath, 1)
+	if err != nil || len(commits) == 0 {
+		t.Fatalf("GetCommitHistory error: %v", err)
+	}
+	base := commits[0].Hash
+
+	worktreePath := filepath.Join(t.TempDir(), "task")
+	if err := repo.CreateWorktree(projectPath, worktreePath, "shotgun/task", base); err != nil {
+		t.Fatalf("CreateWorktree error: %v", err)
+	}
+	if hash, err := repo.Commit(worktreePath, "nothing", nil); err != nil || hash != "" {
+		t.Errorf("expected nothing to commit, got %q, %v", hash, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(worktreePath, "test.txt"), []byte("changed content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "unrelated.txt"), []byte("unrelated"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := repo.Commit(worktreePath, "Change test file", []string{"test.txt"})
+	if err != nil || len(hash) != 40 {
+		t.Fatalf("Commit = %q, %v", hash, err)
+	}
+	if status, _ := repo.GetUncommittedFiles(worktreePath); len(status) != 1 || status[0].Path != "unrelated.txt" {
+		t.Errorf("expected only the listed file committed, uncommitted: %+v", status)
+	}
+	if err := os.Remove(filepath.Join(worktreePath, "unrelated.txt")); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := repo.GenerateDiffAgainst(worktreePath, base)
+	if err != nil || !strings.Contains(diff, "+changed content") {
+		t.Errorf("expected the change in the diff against the base, got %q, %v", diff, err)
+	}
+
+	// The project's working tree is untouched until the branch is merged
+	if content, _ := os.ReadFile(filepath.Join(projectPath, "test.txt")); string(content) != "test content" {
+		t.Errorf("project changed before merge: %q", content)
+	}
+	if err := repo.RemoveWorktree(projectPath, worktreePath); err != nil {
+		t.Fatalf("RemoveWorktree error: %v", err)
+	}
+	if err := repo.MergeBranch(projectPath, "shotgun/task"); err != nil {
+		t.Fatalf("MergeBranch error: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(projectPath, "test.txt")); string(content) != "changed content" {
+		t.Errorf("expected the merged change, got %q", content)
+	}
+	if err := repo.DeleteBranch(projectPath, "shotgun/task"); err != nil {
+		t.Errorf("DeleteBranch error: %v", err)
+	}
+}
+
+func TestMergeBranch_LeavesCheckoutUntouchedOnFailure(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := New(&testLogger{})
+	projectPath := setupTestGitRepo(t)
+	defer os.RemoveAll(projectPath)
+
+	worktreePath := filepath.Join(t.TempDir(), "task")
+	if err := repo.CreateWorktree(projectPath, worktreePath, "shotgun/task", "HEAD"); err != nil {
+		t.Fatalf("CreateWorktree error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "test.txt"), []byte("task change"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Commit(worktreePath, "Task change", []string{"test.txt"}); err != nil {
+		t.Fatalf("Commit error: %v", err)
+	}
+
+	// A dirty checkout is refused
+	if err := os.WriteFile(filepath.Join(projectPath, "test.txt"), []byte("user edit"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MergeBranch(projectPath, "shotgun/task"); err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("expected the dirty checkout to be refused, got %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(projectPath, "test.txt")); string(content) != "user edit" {
+		t.Errorf("user edit lost: %q", content)
+	}
+
+	// A conflicting merge is aborted
+	if _, err := repo.Commit(projectPath, "User change", []string{"test.txt"}); err != nil {
+		t.Fatalf("Commit error: %v", err)
+	}
+	if err := repo.MergeBranch(projectPath, "shotgun/task"); err == nil {
+		t.Fatal("expected the conflicting merge to fail")
+	}
+	if status, _ := repo.GetUncommittedFiles(projectPath); len(status) != 0 {
+		t.Errorf("expected the merge to be aborted, uncommitted: %+v", status)
+	}
+	if content, _ := os.ReadFile(filepath.Join(projectPath, "test.txt")); string(content) != "user edit" {
+		t.Errorf("expected the checkout unchanged, got %q", content)
+	}
+}
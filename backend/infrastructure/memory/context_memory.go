@@ -1,11 +1,16 @@
 package memory
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"shotgun_code/domain"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,8 +20,31 @@ import (
 
 // ContextMemoryImpl implements domain.ContextMemory interface
 type ContextMemoryImpl struct {
-	db *sql.DB
-	mu sync.RWMutex
+	db       *sql.DB
+	mu       sync.RWMutex
+	embedder domain.EmbeddingProvider // optional, enables FindSimilarContexts ranking
+	policy   RetentionPolicy
+}
+
+// RetentionPolicy limits how many contexts are kept per project.
+// Zero values disable the corresponding limit.
+type RetentionPolicy struct {
+	MaxEntries int           // contexts kept per project, least recently used are evicted
+	MaxAge     time.Duration // contexts not accessed for longer are deleted
+}
+
+// Default retention limits
+const (
+	DefaultRetentionMaxEntries = 200
+	DefaultRetentionMaxAge     = 90 * 24 * time.Hour
+)
+
+// DefaultRetentionPolicy returns the retention limits used by NewContextMemory
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		MaxEntries: DefaultRetentionMaxEntries,
+		MaxAge:     DefaultRetentionMaxAge,
+	}
 }
 
 // Ensure ContextMemoryImpl implements domain.ContextMemory
@@ -45,8 +73,14 @@ func scanContextRows(rows *sql.Rows) ([]*domain.ConversationContext, error) {
 	return contexts, nil
 }
 
-// NewContextMemory creates a new context memory store
+// NewContextMemory creates a new context memory store with the default retention policy
 func NewContextMemory(cacheDir string) (*ContextMemoryImpl, error) {
+	return NewContextMemoryWithRetention(cacheDir, DefaultRetentionPolicy())
+}
+
+// NewContextMemoryWithRetention creates a context memory store that prunes
+// contexts according to policy on every save
+func NewContextMemoryWithRetention(cacheDir string, policy RetentionPolicy) (*ContextMemoryImpl, error) {
 	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
 		return nil, err
 	}
@@ -57,7 +91,7 @@ func NewContextMemory(cacheDir string) (*ContextMemoryImpl, error) {
 		return nil, err
 	}
 
-	cm := &ContextMemoryImpl{db: db}
+	cm := &ContextMemoryImpl{db: db, policy: policy}
 	if err := cm.initDB(); err != nil {
 		db.Close()
 		return nil, err
@@ -101,12 +135,47 @@ func (cm *ContextMemoryImpl) initDB() error {
 	CREATE INDEX IF NOT EXISTS idx_contexts_topic ON contexts(topic);
 	CREATE INDEX IF NOT EXISTS idx_task_context ON task_history(context_id);
 	`
-	_, err := cm.db.Exec(schema)
+	if _, err := cm.db.Exec(schema); err != nil {
+		return err
+	}
+	return cm.ensureColumn("contexts", "embedding", "BLOB")
+}
+
+// ensureColumn adds a column to tables created by older versions
+func (cm *ContextMemoryImpl) ensureColumn(table, column, columnType string) error {
+	rows, err := cm.db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err == nil && name == column {
+			return nil
+		}
+	}
+	_, err = cm.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, columnType))
 	return err
 }
 
-// SaveContext saves or updates a conversation context
+// SetEmbeddingProvider enables similarity search over context summaries
+func (cm *ContextMemoryImpl) SetEmbeddingProvider(provider domain.EmbeddingProvider) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.embedder = provider
+}
+
+// SaveContext saves or updates a conversation context.
+// With an embedding provider, the topic and summary are embedded as well;
+// if that fails the context is saved without an embedding.
 func (cm *ContextMemoryImpl) SaveContext(ctx *domain.ConversationContext) error {
+	// Embed outside the lock: providers may call a remote API
+	var embeddingJSON []byte
+	if vectors, err := cm.embed([]string{contextEmbeddingText(ctx)}); err == nil {
+		embeddingJSON, _ = json.Marshal(vectors[0])
+	}
+
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
@@ -115,12 +184,68 @@ func (cm *ContextMemoryImpl) SaveContext(ctx *domain.ConversationContext) error
 
 	_, err := cm.db.Exec(`
 		INSERT OR REPLACE INTO contexts 
-		(id, project_root, topic, files, symbols, summary, last_accessed, created_at, message_count)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		(id, project_root, topic, files, symbols, summary, last_accessed, created_at, message_count, embedding)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, ctx.ID, ctx.ProjectRoot, ctx.Topic, string(filesJSON), string(symbolsJSON),
-		ctx.Summary, ctx.LastAccessed.Unix(), ctx.CreatedAt.Unix(), ctx.MessageCount)
+		ctx.Summary, ctx.LastAccessed.Unix(), ctx.CreatedAt.Unix(), ctx.MessageCount, embeddingJSON)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Retention is best effort: the context itself was saved
+	_, _ = cm.pruneLocked(ctx.ProjectRoot)
+	return nil
+}
+
+// PruneContexts applies the retention policy to a project's contexts
+// and returns the number of removed entries
+func (cm *ContextMemoryImpl) PruneContexts(projectRoot string) (int, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.pruneLocked(projectRoot)
+}
+
+// contextAgeExpr is the most recent use of a context; contexts saved
+// without LastAccessed fall back to their creation time
+const contextAgeExpr = "MAX(COALESCE(last_accessed, 0), COALESCE(created_at, 0))"
+
+// pruneLocked deletes expired contexts and evicts the least recently used
+// ones beyond MaxEntries. Caller must hold cm.mu.
+func (cm *ContextMemoryImpl) pruneLocked(projectRoot string) (int, error) {
+	var removed int64
+
+	if cm.policy.MaxAge > 0 {
+		cutoff := time.Now().Add(-cm.policy.MaxAge).Unix()
+		res, err := cm.db.Exec(`
+			DELETE FROM contexts WHERE project_root = ? AND `+contextAgeExpr+` < ?
+		`, projectRoot, cutoff)
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete expired contexts: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+
+	if cm.policy.MaxEntries > 0 {
+		res, err := cm.db.Exec(`
+			DELETE FROM contexts WHERE id IN (
+				SELECT id FROM contexts
+				WHERE project_root = ?
+				ORDER BY `+contextAgeExpr+` DESC, id DESC
+				LIMIT -1 OFFSET ?
+			)
+		`, projectRoot, cm.policy.MaxEntries)
+		if err != nil {
+			return int(removed), fmt.Errorf("failed to evict contexts: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+
+	if removed > 0 {
+		_, _ = cm.db.Exec("DELETE FROM task_history WHERE context_id IS NOT NULL AND context_id NOT IN (SELECT id FROM contexts)")
+	}
+	return int(removed), nil
 }
 
 // GetContext retrieves a context by ID
@@ -174,6 +299,172 @@ func (cm *ContextMemoryImpl) FindContextByTopic(projectRoot, topic string) ([]*d
 	return scanContextRows(rows)
 }
 
+// FindSimilarContexts returns the contexts whose topic and summary are most
+// similar to the query. Without an embedding provider it falls back to
+// substring search like FindContextByTopic.
+func (cm *ContextMemoryImpl) FindSimilarContexts(projectRoot, query string, limit int) ([]*domain.ConversationContext, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	queryVectors, err := cm.embed([]string{query})
+	if errors.Is(err, errNoEmbeddingProvider) {
+		contexts, err := cm.FindContextByTopic(projectRoot, query)
+		if len(contexts) > limit {
+			contexts = contexts[:limit]
+		}
+		return contexts, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	contexts, embeddings, err := cm.contextsWithEmbeddings(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	type scoredContext struct {
+		ctx   *domain.ConversationContext
+		score float32
+	}
+	scored := make([]scoredContext, 0, len(contexts))
+	for i, ctx := range contexts {
+		if embeddings[i] != nil {
+			scored = append(scored, scoredContext{ctx: ctx, score: cosineSimilarity(queryVectors[0], embeddings[i])})
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+
+	result := make([]*domain.ConversationContext, len(scored))
+	for i, s := range scored {
+		result[i] = s.ctx
+	}
+	return result, nil
+}
+
+// contextsWithEmbeddings loads all contexts of a project with their embeddings,
+// embedding and storing those that were saved without one
+func (cm *ContextMemoryImpl) contextsWithEmbeddings(projectRoot string) ([]*domain.ConversationContext, []domain.EmbeddingVector, error) {
+	cm.mu.RLock()
+	rows, err := cm.db.Query(`
+		SELECT id, project_root, topic, files, symbols, summary, last_accessed, created_at, message_count, embedding
+		FROM contexts 
+		WHERE project_root = ?
+		ORDER BY last_accessed DESC
+	`, projectRoot)
+	if err != nil {
+		cm.mu.RUnlock()
+		return nil, nil, err
+	}
+
+	var contexts []*domain.ConversationContext
+	var embeddings []domain.EmbeddingVector
+	var missing []int
+	for rows.Next() {
+		var ctx domain.ConversationContext
+		var filesJSON, symbolsJSON string
+		var lastAccessed, createdAt int64
+		var embeddingJSON []byte
+
+		if err := rows.Scan(&ctx.ID, &ctx.ProjectRoot, &ctx.Topic, &filesJSON, &symbolsJSON,
+			&ctx.Summary, &lastAccessed, &createdAt, &ctx.MessageCount, &embeddingJSON); err != nil {
+			continue
+		}
+		_ = json.Unmarshal([]byte(filesJSON), &ctx.Files)
+		_ = json.Unmarshal([]byte(symbolsJSON), &ctx.Symbols)
+		ctx.LastAccessed = time.Unix(lastAccessed, 0)
+		ctx.CreatedAt = time.Unix(createdAt, 0)
+
+		var embedding domain.EmbeddingVector
+		if len(embeddingJSON) == 0 || json.Unmarshal(embeddingJSON, &embedding) != nil {
+			missing = append(missing, len(contexts))
+			embedding = nil
+		}
+		contexts = append(contexts, &ctx)
+		embeddings = append(embeddings, embedding)
+	}
+	rows.Close()
+	cm.mu.RUnlock()
+
+	if len(missing) == 0 {
+		return contexts, embeddings, nil
+	}
+
+	texts := make([]string, len(missing))
+	for i, idx := range missing {
+		texts[i] = contextEmbeddingText(contexts[idx])
+	}
+	vectors, err := cm.embed(texts)
+	if err != nil {
+		return contexts, embeddings, nil // rank only the contexts that have embeddings
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	for i, idx := range missing {
+		embeddings[idx] = vectors[i]
+		if embeddingJSON, err := json.Marshal(vectors[i]); err == nil {
+			_, _ = cm.db.Exec("UPDATE contexts SET embedding = ? WHERE id = ?", embeddingJSON, contexts[idx].ID)
+		}
+	}
+	return contexts, embeddings, nil
+}
+
+// errNoEmbeddingProvider is returned by embed when no provider is configured
+var errNoEmbeddingProvider = errors.New("no embedding provider configured")
+
+// embed generates embeddings for the texts with the configured provider
+func (cm *ContextMemoryImpl) embed(texts []string) ([]domain.EmbeddingVector, error) {
+	cm.mu.RLock()
+	embedder := cm.embedder
+	cm.mu.RUnlock()
+	if embedder == nil {
+		return nil, errNoEmbeddingProvider
+	}
+
+	for i, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			texts[i] = "(empty)"
+		}
+	}
+	resp, err := embedder.GenerateEmbeddings(context.Background(), domain.EmbeddingRequest{Texts: texts})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Embeddings))
+	}
+	return resp.Embeddings, nil
+}
+
+// contextEmbeddingText is the text embedded for a context
+func contextEmbeddingText(ctx *domain.ConversationContext) string {
+	return strings.TrimSpace(ctx.Topic + "\n" + ctx.Summary)
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, 0 if their lengths differ
+func cosineSimilarity(a, b domain.EmbeddingVector) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dotProduct, normA, normB float64
+	for i := range a {
+		dotProduct += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dotProduct / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
+
 // GetRecentContexts returns recent contexts for a project
 func (cm *ContextMemoryImpl) GetRecentContexts(projectRoot string, limit int) ([]*domain.ConversationContext, error) {
 	cm.mu.RLock()
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"shotgun_code/domain"
+	"shotgun_code/infrastructure/embeddings"
 	"testing"
 	"time"
 )
@@ -215,3 +216,104 @@ func TestContextMemory_Close(t *testing.T) {
 		t.Errorf("Close failed: %v", err)
 	}
 }
+
+func TestContextMemory_FindSimilarContexts(t *testing.T) {
+	cm, err := NewContextMemory(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewContextMemory failed: %v", err)
+	}
+	defer cm.Close()
+
+	// Saved before the provider is set: embedding is backfilled on search
+	_ = cm.SaveContext(&domain.ConversationContext{
+		ID: "ui", ProjectRoot: "/project", Topic: "sidebar layout",
+		Summary: "vue component styling for the sidebar", CreatedAt: time.Now(),
+	})
+	cm.SetEmbeddingProvider(embeddings.NewFakeEmbeddingProvider(0))
+	_ = cm.SaveContext(&domain.ConversationContext{
+		ID: "auth", ProjectRoot: "/project", Topic: "login flow",
+		Summary: "jwt token refresh and session expiry", CreatedAt: time.Now(),
+	})
+	_ = cm.SaveContext(&domain.ConversationContext{
+		ID: "other", ProjectRoot: "/other", Topic: "jwt token refresh", CreatedAt: time.Now(),
+	})
+
+	found, err := cm.FindSimilarContexts("/project", "refresh the jwt session token", 10)
+	if err != nil {
+		t.Fatalf("FindSimilarContexts failed: %v", err)
+	}
+	if len(found) != 2 || found[0].ID != "auth" {
+		t.Fatalf("expected auth context first among project contexts, got %+v", found)
+	}
+
+	found, _ = cm.FindSimilarContexts("/project", "sidebar styling", 1)
+	if len(found) != 1 || found[0].ID != "ui" {
+		t.Errorf("expected backfilled ui context, got %+v", found)
+	}
+}
+
+func TestContextMemory_FindSimilarContexts_FallsBackToTopic(t *testing.T) {
+	cm, err := NewContextMemory(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewContextMemory failed: %v", err)
+	}
+	defer cm.Close()
+
+	_ = cm.SaveContext(&domain.ConversationContext{ID: "a", ProjectRoot: "/p", Topic: "auth flow", CreatedAt: time.Now()})
+	_ = cm.SaveContext(&domain.ConversationContext{ID: "b", ProjectRoot: "/p", Topic: "ui layout", CreatedAt: time.Now()})
+
+	found, err := cm.FindSimilarContexts("/p", "auth", 5)
+	if err != nil {
+		t.Fatalf("FindSimilarContexts failed: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != "a" {
+		t.Errorf("expected topic match, got %+v", found)
+	}
+}
+
+func TestContextMemory_Retention(t *testing.T) {
+	cm, err := NewContextMemoryWithRetention(t.TempDir(), RetentionPolicy{MaxEntries: 2, MaxAge: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("NewContextMemoryWithRetention failed: %v", err)
+	}
+	defer cm.Close()
+
+	now := time.Now()
+	save := func(id, project string, at time.Time) {
+		t.Helper()
+		if err := cm.SaveContext(&domain.ConversationContext{ID: id, ProjectRoot: project, Topic: id, CreatedAt: at}); err != nil {
+			t.Fatalf("SaveContext failed: %v", err)
+		}
+	}
+	save("expired", "/p", now.Add(-48*time.Hour))
+	save("old", "/p", now.Add(-3*time.Hour))
+	save("mid", "/p", now.Add(-2*time.Hour))
+	save("new", "/p", now.Add(-time.Hour))
+	save("other", "/q", now.Add(-3*time.Hour))
+
+	contexts, _ := cm.GetRecentContexts("/p", 10)
+	if len(contexts) != 2 {
+		t.Fatalf("expected 2 contexts after eviction, got %d", len(contexts))
+	}
+	for _, ctx := range contexts {
+		if ctx.ID != "mid" && ctx.ID != "new" {
+			t.Errorf("unexpected surviving context %q", ctx.ID)
+		}
+	}
+	if other, _ := cm.GetRecentContexts("/q", 10); len(other) != 1 {
+		t.Errorf("other project should be untouched, got %d contexts", len(other))
+	}
+
+	// Tighten the policy and prune manually
+	cm.policy = RetentionPolicy{MaxEntries: 1}
+	removed, err := cm.PruneContexts("/p")
+	if err != nil {
+		t.Fatalf("PruneContexts failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 removed context, got %d", removed)
+	}
+	if _, err := cm.GetContext("new"); err != nil {
+		t.Errorf("most recent context should survive: %v", err)
+	}
+}
@@ -147,8 +147,8 @@ func (g *GofpdfGenerator) setupPDF(opts domain.PDFOptions) (*gofpdf.Fpdf, string
 	return pdf, font, nil
 }
 
-// Generate создаёт PDF и возвращает байты.
-func (g *GofpdfGenerator) Generate(text string, opts domain.PDFOptions) ([]byte, error) {
+// render выводит текст в новый PDF. Символ перевода формата (\f) начинает новую страницу.
+func (g *GofpdfGenerator) render(text string, opts domain.PDFOptions) (*gofpdf.Fpdf, error) {
 	text = replaceUnsupported(text)
 	pdf, _, err := g.setupPDF(opts)
 	if err != nil {
@@ -156,20 +156,39 @@ func (g *GofpdfGenerator) Generate(text string, opts domain.PDFOptions) ([]byte,
 	}
 
 	const maxCols = 160
-	var out strings.Builder
-	i := 1
-	for _, line := range strings.Split(text, "\n") {
-		if opts.LineNumbers {
-			out.WriteString(fmt.Sprintf("%6d  %s\n", i, line))
-		} else {
-			out.WriteString(line + "\n")
+	for n, page := range strings.Split(text, "\f") {
+		if n > 0 {
+			pdf.AddPage()
+			if opts.Dark {
+				pdf.SetFillColor(24, 26, 32)
+				pdf.Rect(0, 0, 210, 297, "F")
+			}
+		}
+
+		var out strings.Builder
+		i := 1
+		for _, line := range strings.Split(page, "\n") {
+			if opts.LineNumbers {
+				out.WriteString(fmt.Sprintf("%6d  %s\n", i, line))
+			} else {
+				out.WriteString(line + "\n")
+			}
+			i++
 		}
-		i++
+		wrapped := softWrapLongLines(out.String(), maxCols)
+
+		pdf.SetX(12)
+		pdf.MultiCell(0, 4.5, wrapped, "", "L", false)
 	}
-	wrapped := softWrapLongLines(out.String(), maxCols)
+	return pdf, nil
+}
 
-	pdf.SetX(12)
-	pdf.MultiCell(0, 4.5, wrapped, "", "L", false)
+// Generate создаёт PDF и возвращает байты.
+func (g *GofpdfGenerator) Generate(text string, opts domain.PDFOptions) ([]byte, error) {
+	pdf, err := g.render(text, opts)
+	if err != nil {
+		return nil, err
+	}
 
 	var buf bytes.Buffer
 	if err := pdf.Output(&buf); err != nil {
@@ -180,28 +199,11 @@ func (g *GofpdfGenerator) Generate(text string, opts domain.PDFOptions) ([]byte,
 
 // WriteAtomic создаёт PDF и атомарно записывает в файл.
 func (g *GofpdfGenerator) WriteAtomic(text string, opts domain.PDFOptions, outputPath string) error {
-	text = replaceUnsupported(text)
-	pdf, _, err := g.setupPDF(opts)
+	pdf, err := g.render(text, opts)
 	if err != nil {
 		return err
 	}
 
-	const maxCols = 160
-	var out strings.Builder
-	i := 1
-	for _, line := range strings.Split(text, "\n") {
-		if opts.LineNumbers {
-			out.WriteString(fmt.Sprintf("%6d  %s\n", i, line))
-		} else {
-			out.WriteString(line + "\n")
-		}
-		i++
-	}
-	wrapped := softWrapLongLines(out.String(), maxCols)
-
-	pdf.SetX(12)
-	pdf.MultiCell(0, 4.5, wrapped, "", "L", false)
-
 	dir := filepath.Dir(outputPath)
 	tmp, err := os.CreateTemp(dir, "pdf-*.tmp")
 	if err != nil {
@@ -5,12 +5,16 @@ import (
 	"os"
 	"path/filepath"
 	"shotgun_code/domain"
+	"sync"
 )
 
 // OPAService реализует сервис для работы с OPA политиками
 type OPAService struct {
 	log       domain.Logger
 	policyDir string
+
+	mu    sync.RWMutex
+	paths pathPolicy
 }
 
 // NewOPAService создает новый OPA сервис
@@ -18,11 +22,27 @@ func NewOPAService(log domain.Logger) *OPAService {
 	return &OPAService{
 		log:       log,
 		policyDir: "backend/infrastructure/policy",
+		paths:     pathPolicy{denied: domain.DefaultDeniedPaths},
 	}
 }
 
+// SetPathPolicy задает allowlist/denylist путей
+func (s *OPAService) SetPathPolicy(allowed, denied []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths = pathPolicy{allowed: append([]string(nil), allowed...), denied: append([]string(nil), denied...)}
+}
+
 // ValidatePath проверяет путь через OPA политики
 func (s *OPAService) ValidatePath(path string) (*domain.OPAValidationResult, error) {
+	// Allowlist/denylist проверяется до политик, чтобы блокировка не зависела от их загрузки
+	s.mu.RLock()
+	blocked := s.paths.evaluate(path)
+	s.mu.RUnlock()
+	if blocked != nil {
+		return blocked, nil
+	}
+
 	// Загружаем политики
 	policies, err := s.loadPolicies()
 	if err != nil {
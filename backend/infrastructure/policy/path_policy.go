@@ -0,0 +1,90 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"shotgun_code/domain"
+)
+
+// pathPolicy allowlist/denylist правок по glob-паттернам
+type pathPolicy struct {
+	allowed []string
+	denied  []string
+}
+
+// evaluate проверяет путь: сначала denylist, затем allowlist (если он задан).
+// Возвращает nil, если путь разрешен
+func (p pathPolicy) evaluate(filePath string) *domain.OPAValidationResult {
+	normalized := normalizePolicyPath(filePath)
+
+	for _, pattern := range p.denied {
+		if matchPathGlob(pattern, normalized) {
+			return &domain.OPAValidationResult{
+				Valid:       false,
+				MatchedRule: "deny:" + pattern,
+				Violations: []domain.OPAViolation{{
+					Type:    "denied_path",
+					Message: fmt.Sprintf("Path %s is denied by rule %s", filePath, pattern),
+					Rule:    pattern,
+				}},
+			}
+		}
+	}
+
+	if len(p.allowed) == 0 {
+		return nil
+	}
+	for _, pattern := range p.allowed {
+		if matchPathGlob(pattern, normalized) {
+			return nil
+		}
+	}
+	return &domain.OPAValidationResult{
+		Valid: false,
+		Violations: []domain.OPAViolation{{
+			Type:    "path_not_allowed",
+			Message: fmt.Sprintf("Path %s is outside the allowed paths (%s)", filePath, strings.Join(p.allowed, ", ")),
+		}},
+	}
+}
+
+// normalizePolicyPath приводит путь к виду "dir/file" со слешами
+func normalizePolicyPath(p string) string {
+	p = path.Clean(filepath.ToSlash(p))
+	return strings.TrimPrefix(p, "./")
+}
+
+// matchPathGlob сопоставляет путь с glob-паттерном; "**" соответствует любому
+// числу сегментов, включая ноль. Паттерн без "/" сравнивается с именем файла
+// в любом каталоге
+func matchPathGlob(pattern, p string) bool {
+	pattern = normalizePolicyPath(pattern)
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
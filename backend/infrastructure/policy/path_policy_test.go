@@ -0,0 +1,51 @@
+package policy
+
+import (
+	"testing"
+
+	"shotgun_code/domain"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"src/**", "src/app/main.go", true},
+		{"src/**", "src", true},
+		{"src/**", "lib/src/main.go", false},
+		{"vendor/**", "vendor/github.com/x/y.go", true},
+		{".git/**", ".git/config", true},
+		{"go.sum", "go.sum", true},
+		{"go.sum", "tools/go.sum", true},
+		{"go.sum", "go.sum.bak", false},
+		{"**/*_test.go", "pkg/a/b_test.go", true},
+		{"src/*.go", "src/a/b.go", false},
+	}
+	for _, tt := range tests {
+		if got := matchPathGlob(tt.pattern, normalizePolicyPath(tt.path)); got != tt.want {
+			t.Errorf("matchPathGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestOPAService_ValidatePathPolicy(t *testing.T) {
+	s := NewOPAService(&domain.NoopLogger{})
+	s.SetPathPolicy([]string{"src/**"}, []string{"src/generated/**"})
+
+	result, err := s.ValidatePath("./src/generated/api.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || result.MatchedRule != "deny:src/generated/**" || result.Violations[0].Rule != "src/generated/**" {
+		t.Errorf("expected deny rule to block, got %+v", result)
+	}
+
+	result, err = s.ValidatePath("docs/readme.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || result.Violations[0].Type != "path_not_allowed" {
+		t.Errorf("expected path outside allowlist to be blocked, got %+v", result)
+	}
+}
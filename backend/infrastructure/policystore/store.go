@@ -0,0 +1,108 @@
+// Package policystore persists user-defined repair rules and guardrail policies.
+package policystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"sync"
+)
+
+// RepairRuleStore implements domain.RepairRuleStore as a JSON file
+type RepairRuleStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewRepairRuleStore creates a store kept in the file at path
+func NewRepairRuleStore(path string) *RepairRuleStore {
+	return &RepairRuleStore{path: path}
+}
+
+// Load reads the rules; a missing file means no custom rules
+func (s *RepairRuleStore) Load() ([]domain.RepairRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rules []domain.RepairRule
+	if _, err := readJSON(s.path, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Save replaces the stored rules
+func (s *RepairRuleStore) Save(rules []domain.RepairRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return writeJSON(s.path, rules)
+}
+
+// GuardrailStore implements domain.GuardrailPolicyStore as a JSON file
+type GuardrailStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewGuardrailStore creates a store kept in the file at path
+func NewGuardrailStore(path string) *GuardrailStore {
+	return &GuardrailStore{path: path}
+}
+
+// Load reads the policies; it returns nil if they were never saved
+func (s *GuardrailStore) Load() (*domain.GuardrailPolicySet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var set domain.GuardrailPolicySet
+	found, err := readJSON(s.path, &set)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// Save replaces the stored policies
+func (s *GuardrailStore) Save(set domain.GuardrailPolicySet) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return writeJSON(s.path, set)
+}
+
+// readJSON decodes the file at path into v and reports whether it exists
+func readJSON(path string, v any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// writeJSON writes v through a temporary file so a crash never leaves a
+// truncated file behind
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
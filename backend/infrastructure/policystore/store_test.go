@@ -0,0 +1,44 @@
+package policystore
+
+import (
+	"path/filepath"
+	"shotgun_code/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairRuleStore_SaveAndLoad(t *testing.T) {
+	store := NewRepairRuleStore(filepath.Join(t.TempDir(), "config", "repair-rules.json"))
+
+	rules, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, rules, "missing file means no custom rules")
+
+	saved := []domain.RepairRule{{ID: "r1", Name: "Rule", Pattern: "error", Language: "go", Priority: 10}}
+	require.NoError(t, store.Save(saved))
+	rules, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, saved, rules)
+}
+
+func TestGuardrailStore_SaveAndLoad(t *testing.T) {
+	store := NewGuardrailStore(filepath.Join(t.TempDir(), "guardrails.json"))
+
+	set, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, set, "missing file means the policies were never saved")
+
+	saved := domain.GuardrailPolicySet{
+		Policies: []domain.GuardrailPolicy{{ID: "p1", Name: "Policy", Enabled: true,
+			Rules: []domain.GuardrailRule{{ID: "r1", Pattern: `\.env$`, Action: domain.GuardrailActionBlock}}}},
+		Budgets: []domain.BudgetPolicy{{ID: "b1", Name: "Budget", Limit: 5, TimeWindow: time.Hour}},
+	}
+	require.NoError(t, store.Save(saved))
+	set, err = store.Load()
+	require.NoError(t, err)
+	require.NotNil(t, set)
+	assert.Equal(t, saved, *set)
+}
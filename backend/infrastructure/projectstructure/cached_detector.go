@@ -159,6 +159,11 @@ func (cd *CachedDetector) DetectConventions(projectPath string) (*domain.Convent
 	return result, nil
 }
 
+// DetectLanguageInfo delegates to the shared language detector, which has its own cache
+func (cd *CachedDetector) DetectLanguageInfo(projectPath string) []domain.LanguageInfo {
+	return cd.detector.DetectLanguageInfo(projectPath)
+}
+
 // GetRelatedLayers delegates to underlying detector (no caching needed)
 func (cd *CachedDetector) GetRelatedLayers(projectPath, filePath string) ([]domain.LayerInfo, error) {
 	return cd.detector.GetRelatedLayers(projectPath, filePath)
@@ -177,6 +182,7 @@ func (cd *CachedDetector) Invalidate(projectPath string) {
 	delete(cd.architectureCache, projectPath)
 	delete(cd.frameworksCache, projectPath)
 	delete(cd.conventionsCache, projectPath)
+	cd.detector.languages.Invalidate(projectPath)
 }
 
 // InvalidateAll clears all caches
@@ -187,6 +193,7 @@ func (cd *CachedDetector) InvalidateAll() {
 	cd.architectureCache = make(map[string]*cachedArchitecture)
 	cd.frameworksCache = make(map[string]*cachedFrameworks)
 	cd.conventionsCache = make(map[string]*cachedConventions)
+	cd.detector.languages.InvalidateAll()
 }
 
 // Stats returns cache statistics
@@ -17,11 +17,12 @@ const (
 type Detector struct {
 	frameworkDetectors []frameworkDetector
 	archDetectors      []architectureDetector
+	languages          *LanguageDetector
 }
 
 // NewDetector creates a new project structure detector
 func NewDetector() *Detector {
-	d := &Detector{}
+	d := &Detector{languages: SharedLanguageDetector()}
 	d.initFrameworkDetectors()
 	d.initArchitectureDetectors()
 	return d
@@ -635,71 +636,14 @@ func extractNpmScripts(content string) []string {
 	return scripts
 }
 
-func (d *Detector) detectLanguages(projectPath string) []domain.LanguageInfo {
-	langCounts := make(map[string]int)
-	totalFiles := 0
-
-	extToLang := map[string]string{
-		".go":    "Go",
-		".ts":    "TypeScript",
-		".tsx":   "TypeScript",
-		".js":    "JavaScript",
-		".jsx":   "JavaScript",
-		".vue":   "Vue",
-		".py":    "Python",
-		".java":  "Java",
-		".kt":    "Kotlin",
-		".rs":    "Rust",
-		".cs":    "C#",
-		".cpp":   "C++",
-		".c":     "C",
-		".rb":    "Ruby",
-		".php":   "PHP",
-		".swift": "Swift",
-		".dart":  "Dart",
-	}
-
-	_ = filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return nil
-		}
-		if strings.Contains(path, "node_modules") || strings.Contains(path, ".git") || strings.Contains(path, "vendor") {
-			return filepath.SkipDir
-		}
-
-		ext := strings.ToLower(filepath.Ext(info.Name()))
-		if lang, ok := extToLang[ext]; ok {
-			langCounts[lang]++
-			totalFiles++
-		}
-		return nil
-	})
-
-	languages := make([]domain.LanguageInfo, 0, len(langCounts))
-	var maxCount int
-	var primaryLang string
-
-	for lang, count := range langCounts {
-		if count > maxCount {
-			maxCount = count
-			primaryLang = lang
-		}
-	}
-
-	for lang, count := range langCounts {
-		percentage := 0.0
-		if totalFiles > 0 {
-			percentage = float64(count) / float64(totalFiles) * 100
-		}
-		languages = append(languages, domain.LanguageInfo{
-			Name:       lang,
-			FileCount:  count,
-			Percentage: percentage,
-			Primary:    lang == primaryLang,
-		})
-	}
+// DetectLanguageInfo returns languages with file counts and percentages without
+// running the full structure detection
+func (d *Detector) DetectLanguageInfo(projectPath string) []domain.LanguageInfo {
+	return d.languages.Detect(projectPath)
+}
 
-	return languages
+func (d *Detector) detectLanguages(projectPath string) []domain.LanguageInfo {
+	return d.languages.Detect(projectPath)
 }
 
 func (d *Detector) detectProjectType(projectPath string, frameworks []domain.FrameworkInfo, buildSystems []domain.BuildSystemInfo) string {
@@ -0,0 +1,207 @@
+package projectstructure
+
+import (
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"shotgun_code/domain"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// extToLanguage maps source file extensions to language names
+var extToLanguage = map[string]string{
+	".go":    "Go",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".vue":   "Vue",
+	".py":    "Python",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".rs":    "Rust",
+	".cs":    "C#",
+	".cpp":   "C++",
+	".c":     "C",
+	".rb":    "Ruby",
+	".php":   "PHP",
+	".swift": "Swift",
+	".dart":  "Dart",
+}
+
+// languageSkipDirs are directories that never contain project sources
+var languageSkipDirs = map[string]bool{
+	"node_modules": true,
+	".git":         true,
+	"vendor":       true,
+}
+
+// LanguageDetector counts source files per language and caches the result per project.
+// The cache is dropped when the file watcher reports changes or after the TTL expires
+type LanguageDetector struct {
+	mu      sync.RWMutex
+	cache   map[string]*cachedLanguages
+	ttl     time.Duration
+	workers int
+}
+
+type cachedLanguages struct {
+	result    []domain.LanguageInfo
+	timestamp time.Time
+}
+
+// NewLanguageDetector creates a language detector with the default cache TTL
+func NewLanguageDetector() *LanguageDetector {
+	return &LanguageDetector{
+		cache:   make(map[string]*cachedLanguages),
+		ttl:     DefaultCacheTTL,
+		workers: runtime.NumCPU(),
+	}
+}
+
+// sharedLanguageDetector is shared by all detectors and the build service
+var sharedLanguageDetector = NewLanguageDetector()
+
+// SharedLanguageDetector returns the process-wide language detector
+func SharedLanguageDetector() *LanguageDetector {
+	return sharedLanguageDetector
+}
+
+// Detect returns languages sorted by file count (then name); the first one is primary.
+// Percentages are rounded to one decimal place
+func (ld *LanguageDetector) Detect(projectPath string) []domain.LanguageInfo {
+	key := filepath.Clean(projectPath)
+
+	ld.mu.RLock()
+	cached, ok := ld.cache[key]
+	ld.mu.RUnlock()
+	if ok && time.Since(cached.timestamp) < ld.ttl {
+		return append([]domain.LanguageInfo(nil), cached.result...)
+	}
+
+	result := languageInfos(ld.countFiles(key))
+
+	ld.mu.Lock()
+	ld.cache[key] = &cachedLanguages{result: result, timestamp: time.Now()}
+	ld.mu.Unlock()
+
+	return append([]domain.LanguageInfo(nil), result...)
+}
+
+// Invalidate drops the cached result for a project
+func (ld *LanguageDetector) Invalidate(projectPath string) {
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+	delete(ld.cache, filepath.Clean(projectPath))
+}
+
+// InvalidateAll drops all cached results
+func (ld *LanguageDetector) InvalidateAll() {
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+	ld.cache = make(map[string]*cachedLanguages)
+}
+
+// OnFilesChanged is a file watcher callback that invalidates the changed project
+func (ld *LanguageDetector) OnFilesChanged(rootDir string, _ []string) {
+	ld.Invalidate(rootDir)
+}
+
+// countFiles walks top-level directories in parallel and counts files per language
+func (ld *LanguageDetector) countFiles(projectPath string) map[string]int {
+	counts := make(map[string]int)
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return counts
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(ld.workers, 1))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			if lang, ok := languageOf(entry.Name()); ok {
+				counts[lang]++
+			}
+			continue
+		}
+		if languageSkipDirs[entry.Name()] {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(dir string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			local := countDirFiles(dir)
+			mu.Lock()
+			for lang, count := range local {
+				counts[lang] += count
+			}
+			mu.Unlock()
+		}(filepath.Join(projectPath, entry.Name()))
+	}
+	wg.Wait()
+	return counts
+}
+
+// countDirFiles counts files per language under a directory
+func countDirFiles(dir string) map[string]int {
+	counts := make(map[string]int)
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if languageSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if lang, ok := languageOf(d.Name()); ok {
+			counts[lang]++
+		}
+		return nil
+	})
+	return counts
+}
+
+// languageOf returns the language of a file by its extension
+func languageOf(name string) (string, bool) {
+	lang, ok := extToLanguage[strings.ToLower(filepath.Ext(name))]
+	return lang, ok
+}
+
+// languageInfos converts file counts into sorted language infos
+func languageInfos(counts map[string]int) []domain.LanguageInfo {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	languages := make([]domain.LanguageInfo, 0, len(counts))
+	for lang, count := range counts {
+		languages = append(languages, domain.LanguageInfo{
+			Name:       lang,
+			FileCount:  count,
+			Percentage: math.Round(float64(count)/float64(total)*1000) / 10,
+		})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].FileCount != languages[j].FileCount {
+			return languages[i].FileCount > languages[j].FileCount
+		}
+		return languages[i].Name < languages[j].Name
+	})
+	if len(languages) > 0 {
+		languages[0].Primary = true
+	}
+	return languages
+}
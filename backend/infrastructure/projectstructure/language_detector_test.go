@@ -0,0 +1,75 @@
+package projectstructure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeLanguageFiles(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, file := range files {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLanguageDetector_CountsAndPercentages(t *testing.T) {
+	root := t.TempDir()
+	writeLanguageFiles(t, root,
+		"main.go", "cmd/app/app.go", "internal/a.go",
+		"web/src/index.ts", "web/src/app.tsx",
+		"scripts/tool.py",
+		"node_modules/lib/index.js", "vendor/dep/dep.go", ".git/hooks/hook.py",
+		".github/workflows/check.py",
+	)
+
+	languages := NewLanguageDetector().Detect(root)
+
+	if len(languages) != 3 {
+		t.Fatalf("expected 3 languages, got %+v", languages)
+	}
+	expected := []struct {
+		name       string
+		files      int
+		percentage float64
+	}{
+		{"Go", 3, 42.9},
+		{"Python", 2, 28.6},
+		{"TypeScript", 2, 28.6},
+	}
+	for i, want := range expected {
+		got := languages[i]
+		if got.Name != want.name || got.FileCount != want.files || got.Percentage != want.percentage {
+			t.Errorf("language %d: expected %+v, got %+v", i, want, got)
+		}
+		if got.Primary != (i == 0) {
+			t.Errorf("language %s: unexpected primary flag %v", got.Name, got.Primary)
+		}
+	}
+}
+
+func TestLanguageDetector_CachesUntilFilesChange(t *testing.T) {
+	root := t.TempDir()
+	writeLanguageFiles(t, root, "main.go")
+	detector := NewLanguageDetector()
+
+	if languages := detector.Detect(root); len(languages) != 1 {
+		t.Fatalf("expected Go only, got %+v", languages)
+	}
+
+	writeLanguageFiles(t, root, "tool.py")
+	if languages := detector.Detect(root); len(languages) != 1 {
+		t.Errorf("expected cached result, got %+v", languages)
+	}
+
+	detector.OnFilesChanged(root, []string{"tool.py"})
+	if languages := detector.Detect(root); len(languages) != 2 {
+		t.Errorf("expected fresh result after invalidation, got %+v", languages)
+	}
+}
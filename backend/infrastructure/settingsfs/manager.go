@@ -2,6 +2,7 @@ package settingsfs
 
 import (
 	"fmt"
+	"maps"
 	"shotgun_code/domain"
 	"sync"
 	"time"
@@ -10,10 +11,18 @@ import (
 // appSettings stores settings that are safe to write to a JSON file.
 // API keys are handled separately via the system's keyring.
 type appSettings struct {
+	// Version is the schema version of the file, see currentSettingsVersion
+	Version int `json:"version"`
+
 	CustomIgnoreRules string                     `json:"customIgnoreRules"`
 	CustomPromptRules string                     `json:"customPromptRules"`
 	UseGitignore      bool                       `json:"useGitignore"`
 	UseCustomIgnore   bool                       `json:"useCustomIgnore"`
+	PreciseGoAnalysis bool                       `json:"preciseGoAnalysis,omitempty"`
+	EmbeddingProvider string                     `json:"embeddingProvider,omitempty"`
+	EmbeddingModel    string                     `json:"embeddingModel,omitempty"`
+	VectorStore       string                     `json:"vectorStore,omitempty"`
+	SimilarityMetric  string                     `json:"similarityMetric,omitempty"`
 	LocalAIHost       string                     `json:"localAIHost,omitempty"`
 	LocalAIModelName  string                     `json:"localAIModelName,omitempty"`
 	QwenHost          string                     `json:"qwenHost,omitempty"`
@@ -21,6 +30,21 @@ type appSettings struct {
 	SelectedModels    map[string]string          `json:"selectedModels"`
 	AvailableModels   map[string][]string        `json:"availableModels"`
 	RecentProjects    []domain.RecentProjectInfo `json:"recentProjects,omitempty"`
+
+	// Stored inverted so that files written before the setting existed keep it on
+	DisableBackgroundIndexing bool `json:"disableBackgroundIndexing,omitempty"`
+	AIAuditIncludePrompts     bool `json:"aiAuditIncludePrompts,omitempty"`
+	SafeMode                  bool `json:"safeMode,omitempty"`
+
+	CommandLimits    domain.CommandLimits `json:"commandLimits"`
+	EnabledLanguages []string             `json:"enabledLanguages,omitempty"`
+
+	AIProviderFallback []string `json:"aiProviderFallback,omitempty"`
+
+	OptimizationProfile string                        `json:"optimizationProfile,omitempty"`
+	CustomOptimization  domain.ContentOptimizeOptions `json:"customOptimization"`
+
+	VerificationGates map[string]domain.VerificationGate `json:"verificationGates,omitempty"`
 }
 
 // secureSettings holds secrets that are stored in the system's keyring.
@@ -65,13 +89,7 @@ func (m *Manager) load() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.loadDefaults()
-
-	if err := m.storage.loadFromFile(&m.settings); err != nil {
-		m.log.Error(fmt.Sprintf("Error reading settings file, using defaults: %v", err))
-	} else {
-		m.mergeWithDefaults()
-	}
+	m.loadSettingsFile()
 
 	if err := m.storage.loadKeysFromKeyring(&m.secure); err != nil {
 		// Log the error but don't fail, as keys might not be critical on startup
@@ -80,8 +98,31 @@ func (m *Manager) load() error {
 	return nil
 }
 
+// loadSettingsFile loads the settings file over the defaults and upgrades it
+// if it was written by an older version
+func (m *Manager) loadSettingsFile() {
+	m.loadDefaults()
+
+	found, err := m.storage.loadFromFile(&m.settings)
+	if err != nil {
+		m.log.Error(fmt.Sprintf("Error reading settings file, using defaults: %v", err))
+		return
+	}
+	if found {
+		m.upgrade()
+	} else {
+		m.settings.Version = currentSettingsVersion
+	}
+	m.mergeWithDefaults()
+}
+
 func (m *Manager) loadDefaults() {
-	m.settings = appSettings{
+	m.settings = defaultAppSettings()
+}
+
+// defaultAppSettings returns the settings used when the file doesn't set them
+func defaultAppSettings() appSettings {
+	return appSettings{
 		UseGitignore:     true,
 		UseCustomIgnore:  true,
 		SelectedProvider: "openai",
@@ -122,6 +163,7 @@ func (m *Manager) Save() error {
 		// Log the error but don't fail, as keyring is not always essential
 		m.log.Warning(fmt.Sprintf("Could not save API keys to keyring: %v", err))
 	}
+	m.settings.Version = currentSettingsVersion
 	return m.storage.saveToFile(&m.settings)
 }
 
@@ -182,6 +224,88 @@ func (m *Manager) GetUseCustomIgnore() bool {
 	defer m.mu.RUnlock()
 	return m.settings.UseCustomIgnore
 }
+func (m *Manager) GetPreciseGoAnalysis() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.settings.PreciseGoAnalysis
+}
+func (m *Manager) GetEmbeddingProvider() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.settings.EmbeddingProvider == "" {
+		return domain.EmbeddingProviderOpenAI
+	}
+	return m.settings.EmbeddingProvider
+}
+func (m *Manager) GetEmbeddingModel() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.settings.EmbeddingModel == "" {
+		return string(domain.DefaultEmbeddingModel)
+	}
+	return m.settings.EmbeddingModel
+}
+func (m *Manager) GetVectorStore() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.settings.VectorStore == "" {
+		return domain.VectorStoreSQLite
+	}
+	return m.settings.VectorStore
+}
+func (m *Manager) GetSimilarityMetric() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.settings.SimilarityMetric == "" {
+		return string(domain.SimilarityAuto)
+	}
+	return m.settings.SimilarityMetric
+}
+func (m *Manager) GetBackgroundIndexing() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return !m.settings.DisableBackgroundIndexing
+}
+func (m *Manager) GetAIAuditIncludePrompts() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.settings.AIAuditIncludePrompts
+}
+func (m *Manager) GetSafeMode() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.settings.SafeMode
+}
+func (m *Manager) GetCommandLimits() domain.CommandLimits {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.settings.CommandLimits
+}
+func (m *Manager) GetEnabledLanguages() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.settings.EnabledLanguages...)
+}
+func (m *Manager) GetAIProviderFallback() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.settings.AIProviderFallback...)
+}
+func (m *Manager) GetOptimizationProfile() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.settings.OptimizationProfile
+}
+func (m *Manager) GetCustomOptimization() domain.ContentOptimizeOptions {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.settings.CustomOptimization
+}
+func (m *Manager) GetVerificationGates() map[string]domain.VerificationGate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return maps.Clone(m.settings.VerificationGates)
+}
 func (m *Manager) GetLocalAIHost() string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -232,6 +356,76 @@ func (m *Manager) SetUseCustomIgnore(e bool) {
 	m.settings.UseCustomIgnore = e
 	m.mu.Unlock()
 }
+func (m *Manager) SetPreciseGoAnalysis(e bool) {
+	m.mu.Lock()
+	m.settings.PreciseGoAnalysis = e
+	m.mu.Unlock()
+}
+func (m *Manager) SetEmbeddingProvider(p string) {
+	m.mu.Lock()
+	m.settings.EmbeddingProvider = p
+	m.mu.Unlock()
+}
+func (m *Manager) SetEmbeddingModel(model string) {
+	m.mu.Lock()
+	m.settings.EmbeddingModel = model
+	m.mu.Unlock()
+}
+func (m *Manager) SetVectorStore(s string) {
+	m.mu.Lock()
+	m.settings.VectorStore = s
+	m.mu.Unlock()
+}
+func (m *Manager) SetSimilarityMetric(metric string) {
+	m.mu.Lock()
+	m.settings.SimilarityMetric = metric
+	m.mu.Unlock()
+}
+func (m *Manager) SetBackgroundIndexing(enabled bool) {
+	m.mu.Lock()
+	m.settings.DisableBackgroundIndexing = !enabled
+	m.mu.Unlock()
+}
+func (m *Manager) SetAIAuditIncludePrompts(include bool) {
+	m.mu.Lock()
+	m.settings.AIAuditIncludePrompts = include
+	m.mu.Unlock()
+}
+func (m *Manager) SetSafeMode(enabled bool) {
+	m.mu.Lock()
+	m.settings.SafeMode = enabled
+	m.mu.Unlock()
+}
+func (m *Manager) SetCommandLimits(limits domain.CommandLimits) {
+	m.mu.Lock()
+	m.settings.CommandLimits = limits
+	m.mu.Unlock()
+}
+func (m *Manager) SetEnabledLanguages(languages []string) {
+	m.mu.Lock()
+	m.settings.EnabledLanguages = append([]string(nil), languages...)
+	m.mu.Unlock()
+}
+func (m *Manager) SetAIProviderFallback(providers []string) {
+	m.mu.Lock()
+	m.settings.AIProviderFallback = append([]string(nil), providers...)
+	m.mu.Unlock()
+}
+func (m *Manager) SetOptimizationProfile(profile string) {
+	m.mu.Lock()
+	m.settings.OptimizationProfile = profile
+	m.mu.Unlock()
+}
+func (m *Manager) SetCustomOptimization(opts domain.ContentOptimizeOptions) {
+	m.mu.Lock()
+	m.settings.CustomOptimization = opts
+	m.mu.Unlock()
+}
+func (m *Manager) SetVerificationGates(gates map[string]domain.VerificationGate) {
+	m.mu.Lock()
+	m.settings.VerificationGates = maps.Clone(gates)
+	m.mu.Unlock()
+}
 func (m *Manager) SetLocalAIHost(h string) { m.mu.Lock(); m.settings.LocalAIHost = h; m.mu.Unlock() }
 func (m *Manager) SetLocalAIModelName(n string) {
 	m.mu.Lock()
@@ -280,6 +474,22 @@ func (m *Manager) GetSettingsDTO() (domain.SettingsDTO, error) {
 	if qwenHost == "" {
 		qwenHost = "https://dashscope.aliyuncs.com/compatible-mode/v1"
 	}
+	embeddingProvider := m.settings.EmbeddingProvider
+	if embeddingProvider == "" {
+		embeddingProvider = domain.EmbeddingProviderOpenAI
+	}
+	embeddingModel := m.settings.EmbeddingModel
+	if embeddingModel == "" {
+		embeddingModel = string(domain.DefaultEmbeddingModel)
+	}
+	vectorStore := m.settings.VectorStore
+	if vectorStore == "" {
+		vectorStore = domain.VectorStoreSQLite
+	}
+	similarityMetric := m.settings.SimilarityMetric
+	if similarityMetric == "" {
+		similarityMetric = string(domain.SimilarityAuto)
+	}
 
 	return domain.SettingsDTO{
 		CustomIgnoreRules: m.settings.CustomIgnoreRules,
@@ -297,7 +507,22 @@ func (m *Manager) GetSettingsDTO() (domain.SettingsDTO, error) {
 		AvailableModels:   availableModelsCopy,
 		UseGitignore:      m.settings.UseGitignore,
 		UseCustomIgnore:   m.settings.UseCustomIgnore,
+		PreciseGoAnalysis: m.settings.PreciseGoAnalysis,
+		EmbeddingProvider: embeddingProvider,
+		EmbeddingModel:    embeddingModel,
+		VectorStore:       vectorStore,
+		SimilarityMetric:  similarityMetric,
 		RecentProjects:    m.settings.RecentProjects,
+
+		BackgroundIndexing:    !m.settings.DisableBackgroundIndexing,
+		AIAuditIncludePrompts: m.settings.AIAuditIncludePrompts,
+		SafeMode:              m.settings.SafeMode,
+		CommandLimits:         m.settings.CommandLimits,
+		EnabledLanguages:      append([]string(nil), m.settings.EnabledLanguages...),
+		AIProviderFallback:    append([]string(nil), m.settings.AIProviderFallback...),
+		OptimizationProfile:   m.settings.OptimizationProfile,
+		CustomOptimization:    m.settings.CustomOptimization,
+		VerificationGates:     maps.Clone(m.settings.VerificationGates),
 	}, nil
 }
 
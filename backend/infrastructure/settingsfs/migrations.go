@@ -0,0 +1,87 @@
+package settingsfs
+
+import "fmt"
+
+// currentSettingsVersion is the schema version Save writes. Files without a
+// version were written before settings were versioned and are version 0.
+const currentSettingsVersion = 1
+
+// settingsMigrations upgrade settings one version at a time:
+// settingsMigrations[v] upgrades settings of version v to v+1. New fields that
+// need more than their zero value get a migration filling in their default.
+var settingsMigrations = []func(settings *appSettings, defaults appSettings){
+	migrateToV1,
+}
+
+// upgrade migrates settings loaded from an older file to the current version
+// and rewrites the file, keeping a backup of the old one. Files of a newer
+// version are loaded as far as they are understood and backed up, since
+// saving drops the settings this version doesn't know.
+func (m *Manager) upgrade() {
+	version := m.settings.Version
+	if version == currentSettingsVersion {
+		return
+	}
+
+	backupPath, err := m.storage.backupFile(version)
+	if err != nil {
+		m.log.Warning(fmt.Sprintf("Could not back up settings file: %v", err))
+	}
+
+	if version > currentSettingsVersion {
+		m.log.Warning(fmt.Sprintf("Settings file has version %d, newer than the supported version %d; settings this version doesn't know will be lost when saving (backup: %s)", version, currentSettingsVersion, backupPath))
+		return
+	}
+
+	m.settings.Version = migrateSettings(&m.settings, version)
+	if err != nil {
+		// Without a backup the old file is kept until settings are saved
+		return
+	}
+	if err := m.storage.saveToFile(&m.settings); err != nil {
+		m.log.Warning(fmt.Sprintf("Could not save migrated settings: %v", err))
+		return
+	}
+	m.log.Info(fmt.Sprintf("Migrated settings from version %d to %d (backup: %s)", version, currentSettingsVersion, backupPath))
+}
+
+// migrateSettings applies the migrations from version to the current version
+// and returns the version reached
+func migrateSettings(settings *appSettings, version int) int {
+	defaults := defaultAppSettings()
+	for ; version < len(settingsMigrations); version++ {
+		settingsMigrations[version](settings, defaults)
+	}
+	return version
+}
+
+// migrateToV1 upgrades files written before settings were versioned: providers
+// added since then get their default models, and empty provider settings
+// get their defaults.
+func migrateToV1(settings *appSettings, defaults appSettings) {
+	if settings.SelectedProvider == "" {
+		settings.SelectedProvider = defaults.SelectedProvider
+	}
+	if settings.SelectedModels == nil {
+		settings.SelectedModels = make(map[string]string, len(defaults.SelectedModels))
+	}
+	for provider, model := range defaults.SelectedModels {
+		if settings.SelectedModels[provider] == "" {
+			settings.SelectedModels[provider] = model
+		}
+	}
+	if settings.AvailableModels == nil {
+		settings.AvailableModels = make(map[string][]string, len(defaults.AvailableModels))
+	}
+	for provider, models := range defaults.AvailableModels {
+		if len(settings.AvailableModels[provider]) == 0 {
+			settings.AvailableModels[provider] = models
+		}
+	}
+	if settings.LocalAIHost == "" {
+		settings.LocalAIHost = defaults.LocalAIHost
+	}
+	if settings.QwenHost == "" {
+		settings.QwenHost = defaults.QwenHost
+	}
+}
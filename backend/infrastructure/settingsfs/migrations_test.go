@@ -0,0 +1,90 @@
+package settingsfs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"testing"
+)
+
+func newTestManager(t *testing.T, fileContent string) (*Manager, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(fileContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := &Manager{log: &domain.NoopLogger{}, storage: &storage{settingsFilePath: path}}
+	m.loadSettingsFile()
+	return m, path
+}
+
+func readVersion(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	return file.Version
+}
+
+func TestLoadSettingsFile_MigratesUnversionedFile(t *testing.T) {
+	old := `{"useGitignore": false, "selectedProvider": "", "selectedModels": {"openai": "gpt-4-turbo"}, "availableModels": null}`
+	m, path := newTestManager(t, old)
+
+	if m.settings.Version != currentSettingsVersion {
+		t.Errorf("Version = %d, want %d", m.settings.Version, currentSettingsVersion)
+	}
+	if m.settings.UseGitignore {
+		t.Error("stored settings should be kept")
+	}
+	if m.settings.SelectedProvider != "openai" || m.settings.SelectedModels["openai"] != "gpt-4-turbo" || m.settings.SelectedModels["qwen"] == "" {
+		t.Errorf("expected stored models kept and missing ones defaulted, got %q %v", m.settings.SelectedProvider, m.settings.SelectedModels)
+	}
+	if len(m.settings.AvailableModels["gemini"]) == 0 {
+		t.Errorf("expected default model lists, got %v", m.settings.AvailableModels)
+	}
+
+	backup, err := os.ReadFile(filepath.Join(filepath.Dir(path), "settings.v0.bak.json"))
+	if err != nil || string(backup) != old {
+		t.Errorf("expected the old file backed up, got %q, %v", backup, err)
+	}
+	if version := readVersion(t, path); version != currentSettingsVersion {
+		t.Errorf("migrated file has version %d, want %d", version, currentSettingsVersion)
+	}
+}
+
+func TestLoadSettingsFile_NewerVersion(t *testing.T) {
+	future := `{"version": 99, "useGitignore": false, "someFutureSetting": true}`
+	m, path := newTestManager(t, future)
+
+	if m.settings.Version != 99 || m.settings.UseGitignore {
+		t.Errorf("expected the newer file loaded as far as understood, got %+v", m.settings)
+	}
+	if data, _ := os.ReadFile(path); string(data) != future {
+		t.Error("a newer settings file should not be rewritten on load")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(path), "settings.v99.bak.json")); err != nil {
+		t.Errorf("expected a backup of the newer file: %v", err)
+	}
+}
+
+func TestLoadSettingsFile_CurrentVersionUnchanged(t *testing.T) {
+	m, path := newTestManager(t, `{"version": 1, "selectedProvider": "gemini"}`)
+	if m.settings.SelectedProvider != "gemini" {
+		t.Errorf("SelectedProvider = %q", m.settings.SelectedProvider)
+	}
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.bak.json"))
+	if len(matches) != 0 {
+		t.Errorf("current files should not be backed up, got %v", matches)
+	}
+	if len(settingsMigrations) != currentSettingsVersion {
+		t.Errorf("%d migrations for version %d", len(settingsMigrations), currentSettingsVersion)
+	}
+}
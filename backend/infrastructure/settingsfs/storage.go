@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/zalando/go-keyring"
 )
@@ -33,15 +34,30 @@ func newStorage() (*storage, error) {
 }
 
 // loadFromFile reads and unmarshals the settings from the JSON file.
-func (s *storage) loadFromFile(settings *appSettings) error {
+// It reports whether the file exists.
+func (s *storage) loadFromFile(settings *appSettings) (bool, error) {
 	data, err := os.ReadFile(s.settingsFilePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil // File not existing is not an error on first run
+			return false, nil // File not existing is not an error on first run
 		}
-		return fmt.Errorf("failed to read settings file: %w", err)
+		return false, fmt.Errorf("failed to read settings file: %w", err)
 	}
-	return json.Unmarshal(data, settings)
+	return true, json.Unmarshal(data, settings)
+}
+
+// backupFile copies the settings file next to it, named after its version,
+// and returns the path of the copy.
+func (s *storage) backupFile(version int) (string, error) {
+	data, err := os.ReadFile(s.settingsFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read settings file: %w", err)
+	}
+	backupPath := strings.TrimSuffix(s.settingsFilePath, ".json") + fmt.Sprintf(".v%d.bak.json", version)
+	if err := os.WriteFile(backupPath, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write settings backup: %w", err)
+	}
+	return backupPath, nil
 }
 
 // saveToFile marshals and writes the settings to the JSON file.
@@ -13,7 +13,8 @@ import (
 
 // ClangTidyAnalyzer реализует StaticAnalyzer для C/C++ с использованием ClangTidy
 type ClangTidyAnalyzer struct {
-	log domain.Logger
+	log      domain.Logger
+	executor *executil.Executor
 }
 
 // NewClangTidyAnalyzer создает новый анализатор для C/C++
@@ -23,6 +24,11 @@ func NewClangTidyAnalyzer(log domain.Logger) *ClangTidyAnalyzer {
 	}
 }
 
+// SetExecutor подключает исполнитель команд с лимитами ресурсов из настроек
+func (a *ClangTidyAnalyzer) SetExecutor(executor *executil.Executor) {
+	a.executor = executor
+}
+
 // Analyze выполняет статический анализ C/C++ кода
 func (a *ClangTidyAnalyzer) Analyze(ctx context.Context, config *domain.StaticAnalyzerConfig) (*domain.StaticAnalysisResult, error) {
 	a.log.Info(fmt.Sprintf("Running ClangTidy analysis for project: %s", config.ProjectPath))
@@ -67,8 +73,7 @@ func (a *ClangTidyAnalyzer) Analyze(ctx context.Context, config *domain.StaticAn
 	args = append(args, config.ProjectPath)
 
 	// Создаем команду
-	cmd := exec.CommandContext(ctx, "clang-tidy", args...)
-	executil.HideWindow(cmd)
+	cmd := a.executor.Command(ctx, "clang-tidy", args...)
 	cmd.Dir = config.ProjectPath
 
 	// Устанавливаем переменные окружения
@@ -79,7 +84,7 @@ func (a *ClangTidyAnalyzer) Analyze(ctx context.Context, config *domain.StaticAn
 	}
 
 	// Запускаем команду
-	output, err := cmd.CombinedOutput()
+	output, err := a.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	duration := time.Since(startTime).Seconds()
 
 	result := &domain.StaticAnalysisResult{
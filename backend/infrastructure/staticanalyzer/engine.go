@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"shotgun_code/domain"
+	"shotgun_code/internal/executil"
 	"strings"
 	"time"
 )
@@ -87,6 +88,15 @@ func (e *StaticAnalyzerEngineImpl) RegisterAnalyzer(analyzer domain.StaticAnalyz
 	e.analyzers[string(analyzerType)] = analyzer
 }
 
+// SetExecutor передает исполнитель команд с лимитами ресурсов зарегистрированным анализаторам
+func (e *StaticAnalyzerEngineImpl) SetExecutor(executor *executil.Executor) {
+	for _, analyzer := range e.analyzers {
+		if setter, ok := analyzer.(interface{ SetExecutor(*executil.Executor) }); ok {
+			setter.SetExecutor(executor)
+		}
+	}
+}
+
 // AnalyzeProject выполняет анализ проекта
 func (e *StaticAnalyzerEngineImpl) AnalyzeProject(ctx context.Context, projectPath string, languages []string) (map[string]*domain.StaticAnalysisResult, error) {
 	e.log.Info(fmt.Sprintf("Analyzing project: %s for languages: %v", projectPath, languages))
@@ -13,7 +13,8 @@ import (
 
 // ErrorProneAnalyzer реализует StaticAnalyzer для Java с использованием ErrorProne
 type ErrorProneAnalyzer struct {
-	log domain.Logger
+	log      domain.Logger
+	executor *executil.Executor
 }
 
 // NewErrorProneAnalyzer создает новый анализатор для Java
@@ -23,6 +24,11 @@ func NewErrorProneAnalyzer(log domain.Logger) *ErrorProneAnalyzer {
 	}
 }
 
+// SetExecutor подключает исполнитель команд с лимитами ресурсов из настроек
+func (a *ErrorProneAnalyzer) SetExecutor(executor *executil.Executor) {
+	a.executor = executor
+}
+
 // Analyze выполняет статический анализ Java кода
 func (a *ErrorProneAnalyzer) Analyze(ctx context.Context, config *domain.StaticAnalyzerConfig) (*domain.StaticAnalysisResult, error) {
 	a.log.Info(fmt.Sprintf("Running ErrorProne analysis for project: %s", config.ProjectPath))
@@ -67,8 +73,7 @@ func (a *ErrorProneAnalyzer) Analyze(ctx context.Context, config *domain.StaticA
 	args = append(args, config.ProjectPath)
 
 	// Создаем команду
-	cmd := exec.CommandContext(ctx, "javac", args...)
-	executil.HideWindow(cmd)
+	cmd := a.executor.Command(ctx, "javac", args...)
 	cmd.Dir = config.ProjectPath
 
 	// Устанавливаем переменные окружения
@@ -79,7 +84,7 @@ func (a *ErrorProneAnalyzer) Analyze(ctx context.Context, config *domain.StaticA
 	}
 
 	// Запускаем команду
-	output, err := cmd.CombinedOutput()
+	output, err := a.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	duration := time.Since(startTime).Seconds()
 
 	result := &domain.StaticAnalysisResult{
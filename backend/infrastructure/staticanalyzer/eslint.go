@@ -14,7 +14,8 @@ import (
 
 // ESLintAnalyzer реализует StaticAnalyzer для TypeScript/JavaScript с использованием ESLint
 type ESLintAnalyzer struct {
-	log domain.Logger
+	log      domain.Logger
+	executor *executil.Executor
 }
 
 // NewESLintAnalyzer создает новый анализатор для TypeScript/JavaScript
@@ -24,6 +25,11 @@ func NewESLintAnalyzer(log domain.Logger) *ESLintAnalyzer {
 	}
 }
 
+// SetExecutor подключает исполнитель команд с лимитами ресурсов из настроек
+func (a *ESLintAnalyzer) SetExecutor(executor *executil.Executor) {
+	a.executor = executor
+}
+
 // Analyze выполняет статический анализ TypeScript/JavaScript кода
 func (a *ESLintAnalyzer) Analyze(ctx context.Context, config *domain.StaticAnalyzerConfig) (*domain.StaticAnalysisResult, error) {
 	a.log.Info(fmt.Sprintf("Running ESLint analysis for project: %s", config.ProjectPath))
@@ -58,8 +64,7 @@ func (a *ESLintAnalyzer) Analyze(ctx context.Context, config *domain.StaticAnaly
 	args = append(args, ".")
 
 	// Создаем команду
-	cmd := exec.CommandContext(ctx, "npx", append([]string{"eslint"}, args...)...) //nolint:gosec // External tool command
-	executil.HideWindow(cmd)
+	cmd := a.executor.Command(ctx, "npx", append([]string{"eslint"}, args...)...) //nolint:gosec // External tool command
 	cmd.Dir = config.ProjectPath
 
 	// Устанавливаем переменные окружения
@@ -70,7 +75,7 @@ func (a *ESLintAnalyzer) Analyze(ctx context.Context, config *domain.StaticAnaly
 	}
 
 	// Запускаем команду
-	output, err := cmd.CombinedOutput()
+	output, err := a.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	duration := time.Since(startTime).Seconds()
 
 	result := &domain.StaticAnalysisResult{
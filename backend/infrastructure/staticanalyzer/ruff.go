@@ -13,7 +13,8 @@ import (
 
 // RuffAnalyzer реализует StaticAnalyzer для Python с использованием Ruff
 type RuffAnalyzer struct {
-	log domain.Logger
+	log      domain.Logger
+	executor *executil.Executor
 }
 
 // NewRuffAnalyzer создает новый анализатор для Python
@@ -23,6 +24,11 @@ func NewRuffAnalyzer(log domain.Logger) *RuffAnalyzer {
 	}
 }
 
+// SetExecutor подключает исполнитель команд с лимитами ресурсов из настроек
+func (a *RuffAnalyzer) SetExecutor(executor *executil.Executor) {
+	a.executor = executor
+}
+
 // Analyze выполняет статический анализ Python кода
 func (a *RuffAnalyzer) Analyze(ctx context.Context, config *domain.StaticAnalyzerConfig) (*domain.StaticAnalysisResult, error) {
 	a.log.Info(fmt.Sprintf("Running Ruff analysis for project: %s", config.ProjectPath))
@@ -67,8 +73,7 @@ func (a *RuffAnalyzer) Analyze(ctx context.Context, config *domain.StaticAnalyze
 	args = append(args, config.ProjectPath)
 
 	// Создаем команду
-	cmd := exec.CommandContext(ctx, "ruff", args...)
-	executil.HideWindow(cmd)
+	cmd := a.executor.Command(ctx, "ruff", args...)
 	cmd.Dir = config.ProjectPath
 
 	// Устанавливаем переменные окружения
@@ -79,7 +84,7 @@ func (a *RuffAnalyzer) Analyze(ctx context.Context, config *domain.StaticAnalyze
 	}
 
 	// Запускаем команду
-	output, err := cmd.CombinedOutput()
+	output, err := a.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	duration := time.Since(startTime).Seconds()
 
 	result := &domain.StaticAnalysisResult{
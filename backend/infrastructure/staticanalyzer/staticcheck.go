@@ -14,7 +14,8 @@ import (
 
 // StaticcheckAnalyzer реализует StaticAnalyzer для Go с использованием staticcheck
 type StaticcheckAnalyzer struct {
-	log domain.Logger
+	log      domain.Logger
+	executor *executil.Executor
 }
 
 // NewStaticcheckAnalyzer создает новый анализатор для Go
@@ -24,6 +25,11 @@ func NewStaticcheckAnalyzer(log domain.Logger) *StaticcheckAnalyzer {
 	}
 }
 
+// SetExecutor подключает исполнитель команд с лимитами ресурсов из настроек
+func (a *StaticcheckAnalyzer) SetExecutor(executor *executil.Executor) {
+	a.executor = executor
+}
+
 // Analyze выполняет статический анализ Go кода
 func (a *StaticcheckAnalyzer) Analyze(ctx context.Context, config *domain.StaticAnalyzerConfig) (*domain.StaticAnalysisResult, error) {
 	a.log.Info(fmt.Sprintf("Running staticcheck analysis for project: %s", config.ProjectPath))
@@ -57,8 +63,7 @@ func (a *StaticcheckAnalyzer) Analyze(ctx context.Context, config *domain.Static
 	args = append(args, "./...")
 
 	// Создаем команду
-	cmd := exec.CommandContext(ctx, "staticcheck", args...)
-	executil.HideWindow(cmd)
+	cmd := a.executor.Command(ctx, "staticcheck", args...)
 	cmd.Dir = config.ProjectPath
 
 	// Устанавливаем переменные окружения
@@ -69,7 +74,7 @@ func (a *StaticcheckAnalyzer) Analyze(ctx context.Context, config *domain.Static
 	}
 
 	// Запускаем команду
-	output, err := cmd.CombinedOutput()
+	output, err := a.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	duration := time.Since(startTime).Seconds()
 
 	result := &domain.StaticAnalysisResult{
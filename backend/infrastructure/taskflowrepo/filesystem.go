@@ -19,6 +19,11 @@ func NewFileSystemTaskflowRepository(statusPath string) *FileSystemTaskflowRepos
 	}
 }
 
+// SetStatusPath changes the status file location, e.g. when another project is selected
+func (r *FileSystemTaskflowRepository) SetStatusPath(statusPath string) {
+	r.statusPath = statusPath
+}
+
 // LoadStatuses loads task statuses from file
 func (r *FileSystemTaskflowRepository) LoadStatuses() (map[string]domain.TaskState, error) {
 	data, err := os.ReadFile(r.statusPath)
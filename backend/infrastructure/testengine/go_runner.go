@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"shotgun_code/domain"
@@ -15,7 +14,8 @@ import (
 
 // GoTestRunner реализует TestRunner для Go
 type GoTestRunner struct {
-	log domain.Logger
+	log      domain.Logger
+	executor *executil.Executor
 }
 
 // NewGoTestRunner создает новый runner для Go тестов
@@ -25,6 +25,11 @@ func NewGoTestRunner(log domain.Logger) *GoTestRunner {
 	}
 }
 
+// SetExecutor подключает исполнитель команд с лимитами ресурсов из настроек
+func (r *GoTestRunner) SetExecutor(executor *executil.Executor) {
+	r.executor = executor
+}
+
 // RunTest выполняет один Go тест
 func (r *GoTestRunner) RunTest(ctx context.Context, testPath string, config *domain.TestConfig) (*domain.TestResult, error) {
 	r.log.Info(fmt.Sprintf("Running Go test: %s", testPath))
@@ -51,8 +56,7 @@ func (r *GoTestRunner) RunTest(ctx context.Context, testPath string, config *dom
 	args = append(args, testPath)
 
 	// Создаем команду
-	cmd := exec.CommandContext(ctx, "go", args...)
-	executil.HideWindow(cmd)
+	cmd := r.executor.Command(ctx, "go", args...)
 	cmd.Dir = config.ProjectPath
 
 	// Устанавливаем переменные окружения
@@ -63,7 +67,7 @@ func (r *GoTestRunner) RunTest(ctx context.Context, testPath string, config *dom
 	}
 
 	// Запускаем команду
-	output, err := cmd.CombinedOutput()
+	output, err := r.executor.CombinedOutput(cmd, domain.CommandOutputFunc(ctx))
 	duration := time.Since(startTime).Seconds()
 
 	result := &domain.TestResult{
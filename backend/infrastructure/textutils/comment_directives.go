@@ -0,0 +1,107 @@
+package textutils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// directiveLines returns the indexes of lines that carry directives and must
+// survive comment stripping verbatim
+type directiveLines func(lines []string) map[int]bool
+
+// pythonEncodingPattern is the PEP 263 source encoding declaration
+var pythonEncodingPattern = regexp.MustCompile(`^[ \t\f]*#.*?coding[:=][ \t]*[-\w.]+`)
+
+// wholeLineComment returns the comment when the line holds nothing but a
+// // comment or a single-line /* */ comment
+func wholeLineComment(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "//") {
+		return trimmed, true
+	}
+	if strings.HasPrefix(trimmed, "/*") && strings.HasSuffix(trimmed, "*/") && strings.Count(trimmed, "*/") == 1 {
+		return trimmed, true
+	}
+	return "", false
+}
+
+// isGoBuildConstraint matches //go:build and legacy // +build lines
+func isGoBuildConstraint(comment string) bool {
+	return strings.HasPrefix(comment, "//go:build") ||
+		strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(comment, "//")), "+build")
+}
+
+// goDirectiveLines keeps build constraints (with the blank line that separates
+// them from the package clause), //go: and //line directives, cgo //export
+// and the cgo preamble preceding import "C"
+func goDirectiveLines(lines []string) map[int]bool {
+	keep := make(map[int]bool)
+	for i, line := range lines {
+		comment, ok := wholeLineComment(line)
+		if !ok {
+			if strings.TrimSpace(line) == `import "C"` {
+				markCgoPreamble(lines, i, keep)
+			}
+			continue
+		}
+		switch {
+		case isGoBuildConstraint(comment):
+			keep[i] = true
+			if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "" {
+				keep[i+1] = true
+			}
+		case strings.HasPrefix(comment, "//go:"), strings.HasPrefix(comment, "//line "), strings.HasPrefix(comment, "//export "):
+			keep[i] = true
+		}
+	}
+	return keep
+}
+
+// markCgoPreamble keeps the comment directly above import "C" on line
+// importLine: // lines or a /* */ block holding the C code
+func markCgoPreamble(lines []string, importLine int, keep map[int]bool) {
+	i := importLine - 1
+	if i >= 0 && strings.HasSuffix(strings.TrimSpace(lines[i]), "*/") {
+		for ; i >= 0; i-- {
+			keep[i] = true
+			if strings.Contains(lines[i], "/*") {
+				return
+			}
+		}
+		return
+	}
+	for ; i >= 0 && strings.HasPrefix(strings.TrimSpace(lines[i]), "//"); i-- {
+		keep[i] = true
+	}
+}
+
+// tsDirectiveLines keeps triple-slash directives (/// <reference ... />) and
+// compiler pragmas such as // @ts-ignore and /** @jsx h */
+func tsDirectiveLines(lines []string) map[int]bool {
+	keep := make(map[int]bool)
+	for i, line := range lines {
+		comment, ok := wholeLineComment(line)
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(comment, "///") {
+			keep[i] = strings.HasPrefix(strings.TrimSpace(comment[3:]), "<")
+			continue
+		}
+		text := strings.TrimLeft(comment, "/* \t")
+		keep[i] = strings.HasPrefix(text, "@ts-") || strings.HasPrefix(text, "@jsx")
+	}
+	return keep
+}
+
+// scriptDirectiveLines keeps the shebang and the Python source encoding
+// declaration, which is only honoured on the first two lines
+func scriptDirectiveLines(lines []string) map[int]bool {
+	keep := make(map[int]bool)
+	for i := 0; i < len(lines) && i < 2; i++ {
+		if (i == 0 && strings.HasPrefix(lines[i], "#!")) || pythonEncodingPattern.MatchString(lines[i]) {
+			keep[i] = true
+		}
+	}
+	return keep
+}
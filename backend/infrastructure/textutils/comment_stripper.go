@@ -15,14 +15,21 @@ func NewCommentStripper(log domain.Logger) domain.CommentStripper {
 	return &commentStripperImpl{log: log}
 }
 
-// Strip removes comments from code content based on file extension
+// Strip removes comments from code content based on file extension.
+// Comments that change how the file is built are kept: Go build constraints,
+// //go: directives and the cgo preamble, TypeScript triple-slash directives
+// and pragmas, shebangs and the Python encoding declaration.
 func (c *commentStripperImpl) Strip(content, filePath string) string {
 	ext := strings.ToLower(filepath.Ext(filePath))
 	switch ext {
-	case ".go", ".js", ".ts", ".java", ".c", ".cpp", ".cs":
+	case ".go":
+		return stripCStyleCommentsKeeping(content, goDirectiveLines)
+	case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts":
+		return stripCStyleCommentsKeeping(content, tsDirectiveLines)
+	case ".java", ".c", ".cpp", ".cs":
 		return stripCStyleComments(content)
 	case ".py", ".sh":
-		return stripHashComments(content)
+		return stripHashCommentsKeeping(content, scriptDirectiveLines)
 	case ".html", ".xml":
 		return stripXMLComments(content)
 	default:
@@ -32,11 +39,22 @@ func (c *commentStripperImpl) Strip(content, filePath string) string {
 
 // stripCStyleComments removes C-style comments (// and /* */)
 func stripCStyleComments(content string) string {
+	return stripCStyleCommentsKeeping(content, nil)
+}
+
+// stripCStyleCommentsKeeping removes C-style comments, keeping the lines
+// reported by directives verbatim
+func stripCStyleCommentsKeeping(content string, directives directiveLines) string {
 	lines := strings.Split(content, "\n")
+	keep := keptLines(lines, directives)
 	var result []string
 	inBlockComment := false
 
-	for _, line := range lines {
+	for i, line := range lines {
+		if keep[i] && !inBlockComment {
+			result = append(result, line)
+			continue
+		}
 		line, inBlockComment = processCommentLine(line, inBlockComment)
 		if trimmed := strings.TrimSpace(line); trimmed != "" {
 			result = append(result, line)
@@ -95,12 +113,31 @@ func stripInlineBlockComments(line string) string {
 	return line
 }
 
+// keptLines returns the directive lines, none when directives is nil
+func keptLines(lines []string, directives directiveLines) map[int]bool {
+	if directives == nil {
+		return nil
+	}
+	return directives(lines)
+}
+
 // stripHashComments removes hash-style comments (#)
 func stripHashComments(content string) string {
+	return stripHashCommentsKeeping(content, nil)
+}
+
+// stripHashCommentsKeeping removes hash-style comments, keeping the lines
+// reported by directives verbatim
+func stripHashCommentsKeeping(content string, directives directiveLines) string {
 	lines := strings.Split(content, "\n")
+	keep := keptLines(lines, directives)
 	var result []string
 
-	for _, line := range lines {
+	for i, line := range lines {
+		if keep[i] {
+			result = append(result, line)
+			continue
+		}
 		if idx := strings.Index(line, "#"); idx != -1 {
 			line = line[:idx]
 		}
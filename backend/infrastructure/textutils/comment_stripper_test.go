@@ -160,3 +160,83 @@ func TestStripCStyleComments_EmptyLines(t *testing.T) {
 		t.Error("should not have multiple empty lines")
 	}
 }
+
+func TestCommentStripper_Strip_GoDirectives(t *testing.T) {
+	cs := NewCommentStripper(&mockLogger{})
+
+	content := `//go:build linux && cgo
+// +build linux,cgo
+
+// Package sys does things.
+package sys
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+// #cgo LDFLAGS: -lm
+// #include <math.h>
+import "C"
+
+//go:generate stringer -type=Mode
+//go:embed static
+var files string
+
+// Free releases memory.
+//
+//export Free
+func Free() {} // trailing
+`
+	want := `//go:build linux && cgo
+// +build linux,cgo
+
+package sys
+/*
+#include <stdlib.h>
+*/
+import "C"
+// #cgo LDFLAGS: -lm
+// #include <math.h>
+import "C"
+//go:generate stringer -type=Mode
+//go:embed static
+var files string
+//export Free
+func Free() {} `
+	if result := cs.Strip(content, "sys_linux.go"); result != want {
+		t.Errorf("unexpected result:\n%s\nwant:\n%s", result, want)
+	}
+}
+
+func TestCommentStripper_Strip_TypeScriptDirectives(t *testing.T) {
+	cs := NewCommentStripper(&mockLogger{})
+
+	content := `/// <reference types="vite/client" />
+/// plain triple-slash comment
+/** @jsx h */
+// regular comment
+// @ts-expect-error legacy API
+legacy()
+`
+	result := cs.Strip(content, "main.tsx")
+
+	for _, kept := range []string{`/// <reference types="vite/client" />`, "/** @jsx h */", "// @ts-expect-error legacy API", "legacy()"} {
+		if !strings.Contains(result, kept) {
+			t.Errorf("should keep %q, got:\n%s", kept, result)
+		}
+	}
+	if strings.Contains(result, "plain triple-slash") || strings.Contains(result, "regular comment") {
+		t.Errorf("should strip ordinary comments, got:\n%s", result)
+	}
+}
+
+func TestCommentStripper_Strip_ScriptDirectives(t *testing.T) {
+	cs := NewCommentStripper(&mockLogger{})
+
+	content := "#!/usr/bin/env python3\n# -*- coding: latin-1 -*-\n# comment\nprint('x')\n"
+	want := "#!/usr/bin/env python3\n# -*- coding: latin-1 -*-\nprint('x')"
+	if result := cs.Strip(content, "tool.py"); result != want {
+		t.Errorf("unexpected result %q, want %q", result, want)
+	}
+}
@@ -3,6 +3,7 @@ package textutils
 import (
 	"context"
 	"path/filepath"
+	"shotgun_code/domain"
 	"strings"
 )
 
@@ -115,19 +116,41 @@ func (o *ContentOptimizer) Optimize(ctx context.Context, content, filePath strin
 		result = o.dataCompact.Compact(result, filePath)
 	}
 
-	// 5. Удаление trailing whitespace
+	// 5. Удаление trailing whitespace (кроме многострочных строковых литералов)
 	if opts.TrimWhitespace {
-		result = o.whitespace.TrimTrailingWhitespace(result)
+		result = o.whitespace.TrimTrailingWhitespaceForFile(result, filePath)
 	}
 
 	// 6. Схлопывание пустых строк (последним, после всех удалений)
 	if opts.CollapseEmptyLines {
-		result = o.whitespace.CollapseEmptyLines(result)
+		result = o.whitespace.CollapseEmptyLinesForFile(result, filePath)
 	}
 
 	return result
 }
 
+// domainContentOptimizer адаптирует ContentOptimizer к domain.ContentOptimizer
+type domainContentOptimizer struct {
+	*ContentOptimizer
+}
+
+// NewDomainContentOptimizer создает оптимизатор, реализующий domain.ContentOptimizer
+func NewDomainContentOptimizer(registry AnalyzerRegistry, commentStripper CommentStripperInterface) domain.ContentOptimizer {
+	return &domainContentOptimizer{ContentOptimizer: NewContentOptimizer(registry, commentStripper)}
+}
+
+// Optimize применяет оптимизации, заданные доменными опциями
+func (o *domainContentOptimizer) Optimize(ctx context.Context, content, filePath string, opts domain.ContentOptimizeOptions) string {
+	return o.ContentOptimizer.Optimize(ctx, content, filePath, OptimizeOptions{
+		CollapseEmptyLines: opts.CollapseEmptyLines,
+		StripLicense:       opts.StripLicense,
+		StripComments:      opts.StripComments,
+		CompactDataFiles:   opts.CompactDataFiles,
+		SkeletonMode:       opts.SkeletonMode,
+		TrimWhitespace:     opts.TrimWhitespace,
+	})
+}
+
 // OptimizeWithDefaults применяет оптимизации с настройками по умолчанию
 func (o *ContentOptimizer) OptimizeWithDefaults(ctx context.Context, content, filePath string) string {
 	return o.Optimize(ctx, content, filePath, DefaultOptimizeOptions())
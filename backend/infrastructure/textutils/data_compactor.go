@@ -3,10 +3,28 @@ package textutils
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"path/filepath"
+	"regexp"
 	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// maxDataArrayItems - массивы длиннее порога сокращаются
+	maxDataArrayItems = 20
+	// keptDataArrayItems - сколько элементов сохраняется в начале и в конце сокращенного массива
+	keptDataArrayItems = 5
 )
 
+// elidedItemsMarker заменяет пропущенные элементы массива
+func elidedItemsMarker(count int) string {
+	return fmt.Sprintf("…(%d more)…", count)
+}
+
 // DataCompactor сжимает JSON и YAML файлы для экономии токенов
 // Удаляет форматирование, сохраняя структуру данных
 type DataCompactor struct{}
@@ -30,25 +48,32 @@ func (c *DataCompactor) Compact(content, filePath string) string {
 	}
 }
 
-// CompactJSON минимизирует JSON, удаляя форматирование
-// Использует стандартный json пакет для надежности
+// CompactJSON минимизирует JSON, удаляя форматирование, и сокращает длинные
+// массивы до первых и последних keptDataArrayItems элементов
 func (c *DataCompactor) CompactJSON(content string) string {
 	if len(content) == 0 {
 		return content
 	}
 
-	// Быстрая проверка - если уже компактный (нет переносов), возвращаем как есть
-	if !strings.Contains(content, "\n") {
-		return content
-	}
-
-	// Парсим и сериализуем без форматирования
+	// Парсим с json.Number, чтобы не терять точность чисел
 	var data interface{}
-	if err := json.Unmarshal([]byte(content), &data); err != nil {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil || decoder.More() {
 		// Если не валидный JSON, возвращаем как есть
 		return content
 	}
 
+	data, truncated := truncateJSONArrays(data)
+	if !truncated {
+		// Без сокращений сохраняем исходный порядок ключей
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(content)); err != nil {
+			return content
+		}
+		return buf.String()
+	}
+
 	// Используем buffer для избежания аллокаций
 	var buf bytes.Buffer
 	encoder := json.NewEncoder(&buf)
@@ -63,6 +88,33 @@ func (c *DataCompactor) CompactJSON(content string) string {
 	return strings.TrimSuffix(result, "\n")
 }
 
+// truncateJSONArrays рекурсивно сокращает массивы длиннее maxDataArrayItems
+func truncateJSONArrays(value interface{}) (interface{}, bool) {
+	truncated := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			var changed bool
+			v[key], changed = truncateJSONArrays(item)
+			truncated = truncated || changed
+		}
+	case []interface{}:
+		for i, item := range v {
+			var changed bool
+			v[i], changed = truncateJSONArrays(item)
+			truncated = truncated || changed
+		}
+		if len(v) > maxDataArrayItems {
+			kept := make([]interface{}, 0, 2*keptDataArrayItems+1)
+			kept = append(kept, v[:keptDataArrayItems]...)
+			kept = append(kept, elidedItemsMarker(len(v)-2*keptDataArrayItems))
+			kept = append(kept, v[len(v)-keptDataArrayItems:]...)
+			return kept, true
+		}
+	}
+	return value, truncated
+}
+
 // CompactYAML минимизирует YAML
 // Упрощенная реализация без внешних зависимостей:
 // - Удаляет комментарии
@@ -73,11 +125,33 @@ func (c *DataCompactor) CompactYAML(content string) string {
 		return content
 	}
 
+	// Невалидный YAML возвращаем как есть
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return content
+		}
+	}
+
 	lines := strings.Split(content, "\n")
 	var result []string
 	prevEmpty := false
+	blockIndent := -1 // отступ строки, открывшей блочный скаляр
 
 	for _, line := range lines {
+		// Содержимое блочного скаляра (| или >) сохраняется без изменений
+		if blockIndent >= 0 {
+			if strings.TrimSpace(line) == "" || yamlIndent(line) > blockIndent {
+				result = append(result, line)
+				continue
+			}
+			blockIndent = -1
+		}
+
 		// Удаляем комментарии (но не в строках)
 		trimmed := strings.TrimSpace(line)
 
@@ -101,11 +175,104 @@ func (c *DataCompactor) CompactYAML(content string) string {
 
 		// Убираем trailing whitespace
 		line = strings.TrimRight(line, " \t")
+		if yamlBlockScalarStart(line) {
+			blockIndent = yamlIndent(line)
+		}
 
 		result = append(result, line)
 	}
 
-	return strings.Join(result, "\n")
+	return strings.Join(c.truncateYAMLSequences(result), "\n")
+}
+
+// truncateYAMLSequences сокращает блочные последовательности YAML длиннее
+// maxDataArrayItems. Элемент включает строку "- " и все строки с большим отступом
+func (c *DataCompactor) truncateYAMLSequences(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); {
+		indent, ok := yamlSequenceItem(lines[i])
+		if !ok {
+			result = append(result, lines[i])
+			i++
+			if yamlBlockScalarStart(lines[i-1]) {
+				end := yamlBlockEnd(lines, i-1)
+				result = append(result, lines[i:end]...)
+				i = end
+			}
+			continue
+		}
+
+		// Собираем соседние элементы с тем же отступом
+		starts := []int{i}
+		end := i + 1
+		for ; end < len(lines); end++ {
+			if strings.TrimSpace(lines[end]) == "" || yamlIndent(lines[end]) > indent {
+				continue
+			}
+			if itemIndent, ok := yamlSequenceItem(lines[end]); ok && itemIndent == indent {
+				starts = append(starts, end)
+				continue
+			}
+			break
+		}
+
+		items := make([][]string, len(starts))
+		for j, start := range starts {
+			stop := end
+			if j+1 < len(starts) {
+				stop = starts[j+1]
+			}
+			if yamlBlockScalarStart(lines[start]) {
+				items[j] = lines[start:stop]
+				continue
+			}
+			items[j] = append([]string{lines[start]}, c.truncateYAMLSequences(lines[start+1:stop])...)
+		}
+
+		if len(items) > maxDataArrayItems {
+			marker := []string{lines[i][:indent] + "- " + elidedItemsMarker(len(items)-2*keptDataArrayItems)}
+			kept := append(items[:keptDataArrayItems:keptDataArrayItems], marker)
+			items = append(kept, items[len(items)-keptDataArrayItems:]...)
+		}
+		for _, item := range items {
+			result = append(result, item...)
+		}
+		i = end
+	}
+	return result
+}
+
+// yamlSequenceItem возвращает отступ строки, если она начинает элемент последовательности
+func yamlSequenceItem(line string) (int, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if trimmed != "-" && !strings.HasPrefix(trimmed, "- ") {
+		return 0, false
+	}
+	return len(line) - len(trimmed), true
+}
+
+// yamlBlockScalarRe находит индикатор блочного скаляра (|, >, |-, >+, |2) в конце строки
+var yamlBlockScalarRe = regexp.MustCompile(`(^|:\s+|^\s*-\s+)[|>][-+1-9]*$`)
+
+// yamlBlockScalarStart проверяет, открывает ли строка блочный скаляр
+func yamlBlockScalarStart(line string) bool {
+	return yamlBlockScalarRe.MatchString(strings.TrimRight(line, " \t"))
+}
+
+// yamlBlockEnd возвращает индекс первой строки после содержимого блочного
+// скаляра, открытого строкой start
+func yamlBlockEnd(lines []string, start int) int {
+	indent := yamlIndent(lines[start])
+	end := start + 1
+	for end < len(lines) && (strings.TrimSpace(lines[end]) == "" || yamlIndent(lines[end]) > indent) {
+		end++
+	}
+	return end
+}
+
+// yamlIndent возвращает ширину отступа строки
+func yamlIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
 }
 
 // removeYAMLInlineComment удаляет inline комментарии из YAML строки
@@ -1,6 +1,7 @@
 package textutils
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -201,6 +202,80 @@ func TestDataCompactor_Compact(t *testing.T) {
 	}
 }
 
+func TestDataCompactor_CompactJSON_TruncatesLongArrays(t *testing.T) {
+	c := NewDataCompactor()
+
+	items := make([]string, 30)
+	for i := range items {
+		items[i] = fmt.Sprintf("%d", i)
+	}
+	input := "{\n  \"items\": [\n    " + strings.Join(items, ",\n    ") + "\n  ]\n}"
+
+	result := c.CompactJSON(input)
+	expected := `{"items":[0,1,2,3,4,"…(20 more)…",25,26,27,28,29]}`
+	if result != expected {
+		t.Errorf("CompactJSON() = %q, want %q", result, expected)
+	}
+}
+
+func TestDataCompactor_CompactYAML_TruncatesLongSequences(t *testing.T) {
+	c := NewDataCompactor()
+
+	var b strings.Builder
+	b.WriteString("items:\n")
+	for i := 0; i < 25; i++ {
+		fmt.Fprintf(&b, "  - name: item%d\n    tags:\n      - a\n", i)
+	}
+	b.WriteString("count: 25\n")
+
+	result := c.CompactYAML(b.String())
+	if !strings.Contains(result, "  - …(15 more)…") {
+		t.Errorf("expected elided marker, got:\n%s", result)
+	}
+	if !strings.Contains(result, "item4") || strings.Contains(result, "item5\n") || !strings.Contains(result, "item20") {
+		t.Errorf("expected first and last items to be kept, got:\n%s", result)
+	}
+	if !strings.Contains(result, "count: 25") {
+		t.Errorf("expected trailing keys to be kept, got:\n%s", result)
+	}
+}
+
+func TestDataCompactor_CompactYAML_KeepsBlockScalars(t *testing.T) {
+	c := NewDataCompactor()
+
+	var script strings.Builder
+	for i := 0; i < 25; i++ {
+		fmt.Fprintf(&script, "    - step%d # not a comment\n", i)
+	}
+	block := "  script: |\n" + script.String() + "\n\n    # also kept\n"
+	input := "job: # comment\n" + block + "  folded: >-\n    - one\n    - two\nnext: 1\n"
+
+	result := c.CompactYAML(input)
+	if !strings.Contains(result, block) {
+		t.Errorf("expected the literal block unchanged, got:\n%s", result)
+	}
+	if !strings.Contains(result, "  folded: >-\n    - one\n    - two\nnext: 1") {
+		t.Errorf("expected the folded block and the next key, got:\n%s", result)
+	}
+	if strings.Contains(result, "job: # comment") {
+		t.Errorf("expected comments outside blocks to be removed, got:\n%s", result)
+	}
+}
+
+func TestDataCompactor_MalformedUnchanged(t *testing.T) {
+	c := NewDataCompactor()
+
+	json := "{\n  \"name\": \"test\",\n"
+	if result := c.CompactJSON(json); result != json {
+		t.Errorf("CompactJSON() changed malformed JSON: %q", result)
+	}
+
+	yaml := "# comment\nkey: [unclosed\n"
+	if result := c.CompactYAML(yaml); result != yaml {
+		t.Errorf("CompactYAML() changed malformed YAML: %q", result)
+	}
+}
+
 func TestIsDataFile(t *testing.T) {
 	tests := []struct {
 		path     string
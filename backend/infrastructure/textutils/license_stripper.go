@@ -1,11 +1,17 @@
 package textutils
 
 import (
+	"regexp"
 	"strings"
 )
 
-// LicenseStripper удаляет блоки лицензий и copyright из начала файлов
-// Оптимизирован для минимального влияния на производительность
+// licenseNamePattern находит упоминание конкретной лицензии ("MIT License", "Лицензия Apache"),
+// а не любое слово license, чтобы не трогать документацию вроде "Package license ..."
+var licenseNamePattern = regexp.MustCompile(`(?i)\b(mit|apache|bsd|gnu|gpl|lgpl|agpl|mozilla|mpl|isc)\b[^\n]{0,30}(license|лицензи)|(license|лицензи[а-я]*)[^\n]{0,10}\b(mit|apache|bsd|gpl|lgpl|agpl|mpl|isc)\b`)
+
+// LicenseStripper удаляет блоки лицензий и copyright из начала файлов.
+// Комментарий считается лицензией, только если содержит идентификатор SPDX,
+// copyright или формулировку лицензии, поэтому обычные doc-комментарии сохраняются
 type LicenseStripper struct {
 	// Предкомпилированные паттерны для быстрого поиска (lowercase)
 	licenseKeywords []string
@@ -15,23 +21,15 @@ type LicenseStripper struct {
 func NewLicenseStripper() *LicenseStripper {
 	return &LicenseStripper{
 		licenseKeywords: []string{
+			"spdx-license-identifier",
 			"copyright",
-			"license",
-			"licensed",
-			"spdx-license",
-			"mit license",
-			"apache license",
-			"bsd license",
-			"gnu general public",
-			"gpl",
-			"lgpl",
-			"mozilla public",
+			"licensed under",
 			"all rights reserved",
 			"permission is hereby granted",
 			"redistribution and use",
-			"this file is part of",
-			"автор:",
-			"лицензия",
+			"this program is free software",
+			"авторское право",
+			"все права защищены",
 		},
 	}
 }
@@ -123,39 +121,33 @@ func (l *LicenseStripper) stripBlockComment(original, trimmed, start, end string
 	return afterComment
 }
 
-// stripLineComments удаляет последовательные строчные комментарии с лицензией
+// stripLineComments удаляет ведущие группы строчных комментариев с лицензией.
+// Группа заканчивается пустой строкой; первая группа без лицензии (например,
+// doc-комментарий пакета) и все, что за ней, сохраняются
 func (l *LicenseStripper) stripLineComments(original, trimmed, prefix string) string {
 	lines := strings.Split(trimmed, "\n")
-	commentLines := []string{}
-	lastCommentLine := 0
-
-	// Собираем последовательные строки комментариев в начале
-	for i, line := range lines {
-		trimmedLine := strings.TrimLeft(line, " \t")
-		if strings.HasPrefix(trimmedLine, prefix) || trimmedLine == "" {
-			commentLines = append(commentLines, line)
-			if strings.HasPrefix(trimmedLine, prefix) {
-				lastCommentLine = i
-			}
-		} else {
+	start := 0
+
+	for start < len(lines) {
+		end := start
+		for end < len(lines) && strings.HasPrefix(strings.TrimLeft(lines[end], " \t"), prefix) {
+			end++
+		}
+		if end == start || !l.containsLicenseKeyword(strings.Join(lines[start:end], "\n")) {
 			break
 		}
-	}
 
-	if len(commentLines) == 0 {
-		return original
+		// Пропускаем пустые строки после группы
+		for end < len(lines) && strings.TrimSpace(lines[end]) == "" {
+			end++
+		}
+		start = end
 	}
 
-	// Проверяем, содержит ли блок комментариев лицензию
-	commentBlock := strings.Join(commentLines[:lastCommentLine+1], "\n")
-	if !l.containsLicenseKeyword(commentBlock) {
+	if start == 0 {
 		return original
 	}
-
-	// Возвращаем контент после блока комментариев
-	remaining := lines[lastCommentLine+1:]
-	result := strings.Join(remaining, "\n")
-	return strings.TrimLeft(result, "\n")
+	return strings.Join(lines[start:], "\n")
 }
 
 // containsLicenseKeyword проверяет наличие лицензионных ключевых слов
@@ -167,7 +159,7 @@ func (l *LicenseStripper) containsLicenseKeyword(text string) bool {
 			return true
 		}
 	}
-	return false
+	return licenseNamePattern.MatchString(text)
 }
 
 // StripWithLanguageHint удаляет лицензию с подсказкой о языке для оптимизации
@@ -140,6 +140,30 @@ def main():
 	}
 }
 
+func TestLicenseStripper_PreservesDocComments(t *testing.T) {
+	l := NewLicenseStripper()
+
+	withLicense := `// Copyright 2024 Example Corp
+// SPDX-License-Identifier: Apache-2.0
+
+// Package license validates license files.
+package license`
+	result := l.StripWithLanguageHint(withLicense, ".go")
+	if strings.Contains(result, "Copyright") || strings.Contains(result, "SPDX") {
+		t.Errorf("license header should be stripped, got:\n%s", result)
+	}
+	if !strings.HasPrefix(result, "// Package license validates license files.") {
+		t.Errorf("package doc comment should be kept, got:\n%s", result)
+	}
+
+	docOnly := `// Package license validates license files
+// and reports which license applies.
+package license`
+	if result := l.StripWithLanguageHint(docOnly, ".go"); result != docOnly {
+		t.Errorf("doc comment without license notice should be unchanged, got:\n%s", result)
+	}
+}
+
 func TestLicenseStripper_Strip_NoLicense(t *testing.T) {
 	l := NewLicenseStripper()
 
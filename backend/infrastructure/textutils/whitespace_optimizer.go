@@ -1,6 +1,7 @@
 package textutils
 
 import (
+	"shotgun_code/internal/literals"
 	"strings"
 	"unicode"
 )
@@ -17,6 +18,18 @@ func NewWhitespaceOptimizer() *WhitespaceOptimizer {
 // CollapseEmptyLines схлопывает множественные пустые строки (2+) в одну
 // Алгоритм: однопроходный O(n), zero-allocation для небольших файлов
 func (w *WhitespaceOptimizer) CollapseEmptyLines(content string) string {
+	return collapseEmptyLines(content, nil)
+}
+
+// CollapseEmptyLinesForFile схлопывает пустые строки, оставляя без изменений
+// многострочные строковые литералы файла (raw-строки Go, template literals
+// JS/TS, тройные кавычки Python)
+func (w *WhitespaceOptimizer) CollapseEmptyLinesForFile(content, filePath string) string {
+	return collapseEmptyLines(content, literals.ProtectedLines(content, filePath))
+}
+
+// collapseEmptyLines схлопывает пустые строки; строки protected выводятся как есть
+func collapseEmptyLines(content string, protected []bool) string {
 	if len(content) == 0 {
 		return content
 	}
@@ -33,13 +46,19 @@ func (w *WhitespaceOptimizer) CollapseEmptyLines(content string) string {
 
 	emptyLineCount := 0
 	lineStart := 0
+	lineIdx := 0
 
 	for i := 0; i < len(content); i++ {
 		if content[i] == '\n' {
 			line := content[lineStart:i]
 			isEmptyLine := isWhitespaceOnly(line)
 
-			if isEmptyLine {
+			if lineIdx < len(protected) && protected[lineIdx] {
+				// Внутри строкового литерала пустые строки значимы
+				emptyLineCount = 0
+				b.WriteString(line)
+				b.WriteByte('\n')
+			} else if isEmptyLine {
 				emptyLineCount++
 				if emptyLineCount <= 2 {
 					b.WriteString(line)
@@ -52,6 +71,7 @@ func (w *WhitespaceOptimizer) CollapseEmptyLines(content string) string {
 				b.WriteByte('\n')
 			}
 			lineStart = i + 1
+			lineIdx++
 		}
 	}
 
@@ -80,6 +100,17 @@ func isWhitespaceOnly(s string) bool {
 // TrimTrailingWhitespace удаляет пробелы в конце каждой строки
 // Полезно для дополнительной экономии токенов
 func (w *WhitespaceOptimizer) TrimTrailingWhitespace(content string) string {
+	return trimTrailingWhitespace(content, nil)
+}
+
+// TrimTrailingWhitespaceForFile удаляет пробелы в конце строк, кроме строк
+// внутри многострочных строковых литералов файла
+func (w *WhitespaceOptimizer) TrimTrailingWhitespaceForFile(content, filePath string) string {
+	return trimTrailingWhitespace(content, literals.ProtectedLines(content, filePath))
+}
+
+// trimTrailingWhitespace удаляет пробелы в конце строк; строки protected не меняются
+func trimTrailingWhitespace(content string, protected []bool) string {
 	if len(content) == 0 {
 		return content
 	}
@@ -89,7 +120,11 @@ func (w *WhitespaceOptimizer) TrimTrailingWhitespace(content string) string {
 	b.Grow(len(content))
 
 	for i, line := range lines {
-		b.WriteString(strings.TrimRightFunc(line, unicode.IsSpace))
+		if i < len(protected) && protected[i] {
+			b.WriteString(line)
+		} else {
+			b.WriteString(strings.TrimRightFunc(line, unicode.IsSpace))
+		}
 		if i < len(lines)-1 {
 			b.WriteByte('\n')
 		}
@@ -104,3 +139,11 @@ func (w *WhitespaceOptimizer) OptimizeWhitespace(content string) string {
 	result = w.CollapseEmptyLines(result)
 	return result
 }
+
+// OptimizeWhitespaceForFile применяет все оптимизации пробелов, не трогая
+// многострочные строковые литералы файла
+func (w *WhitespaceOptimizer) OptimizeWhitespaceForFile(content, filePath string) string {
+	result := w.TrimTrailingWhitespaceForFile(content, filePath)
+	result = w.CollapseEmptyLinesForFile(result, filePath)
+	return result
+}
@@ -115,6 +115,51 @@ func TestWhitespaceOptimizer_TrimTrailingWhitespace(t *testing.T) {
 	}
 }
 
+func TestWhitespaceOptimizer_ForFileKeepsMultilineStrings(t *testing.T) {
+	w := NewWhitespaceOptimizer()
+
+	tests := []struct {
+		name     string
+		path     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "go raw string with yaml",
+			path:     "manifest.go",
+			input:    "package m   \n\n\n\nvar y = `\na: 1  \n\n\n\nb: 2\n`\n",
+			expected: "package m\n\n\nvar y = `\na: 1  \n\n\n\nb: 2\n`\n",
+		},
+		{
+			name:     "template literal",
+			path:     "query.ts",
+			input:    "const q = `\nSELECT *  \n\n\n\nFROM t`;  \n",
+			expected: "const q = `\nSELECT *  \n\n\n\nFROM t`;\n",
+		},
+		{
+			name:     "python docstring",
+			path:     "doc.py",
+			input:    "def f():\n    \"\"\"Usage:  \n\n\n\n    f()\n    \"\"\"\n",
+			expected: "def f():\n    \"\"\"Usage:  \n\n\n\n    f()\n    \"\"\"\n",
+		},
+		{
+			name:     "unknown extension behaves as before",
+			path:     "notes.txt",
+			input:    "a `  \n\n\n\nb`",
+			expected: "a `\n\n\nb`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := w.OptimizeWhitespaceForFile(tt.input, tt.path)
+			if result != tt.expected {
+				t.Errorf("OptimizeWhitespaceForFile() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestIsWhitespaceOnly(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -15,7 +15,8 @@ func NewContextBuilderAdapter(service *Service) *ContextBuilderAdapter {
 	return &ContextBuilderAdapter{service: service}
 }
 
-// BuildContext implements domain.ContextBuilder interface
+// BuildContext implements domain.ContextBuilder interface; options.Incremental
+// selects the incremental builder
 func (a *ContextBuilderAdapter) BuildContext(ctx context.Context, projectPath string, includedPaths []string, options *domain.ContextBuildOptions) (*domain.ContextSummary, error) {
 	return a.service.BuildContextSummary(ctx, projectPath, includedPaths, options)
 }
@@ -0,0 +1,103 @@
+package context
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+)
+
+// estimateSampleBytes is how much of each file is read to estimate its tokens
+const estimateSampleBytes = 16 * 1024
+
+// EstimateContextTokens estimates the token count of the context a build with
+// options would produce, without reading and formatting every file in full.
+// The head of each file is sampled, optimized and counted, and the count is
+// scaled by the file size. Skeleton mode is not applied to samples, so the
+// estimate is an upper bound for skeleton builds.
+func (s *Service) EstimateContextTokens(projectPath string, includedPaths []string, options *domain.ContextBuildOptions) (int, error) {
+	buildOpts, err := s.convertBuildOptions(options)
+	if err != nil {
+		return 0, err
+	}
+	if buildOpts == nil {
+		buildOpts = &BuildOptions{OutputFormat: FormatXML}
+	}
+
+	includedPaths, lineRanges := splitLineRanges(includedPaths)
+	files := s.expandPaths(projectPath, includedPaths)
+	if buildOpts.ExcludeTests {
+		files = s.filterTestFiles(files)
+	}
+
+	total := 0
+	if buildOpts.IncludeManifest {
+		total += s.tokenCounter.CountTokens(fmt.Sprintf("# Streaming Context\nProject Path: %s\n\n", projectPath))
+	}
+	total += s.tokenCounter.CountTokens(s.treeHeader(files, buildOpts))
+	for _, filePath := range files {
+		var tokens int
+		var ok bool
+		if spans := lineRanges[filePath]; len(spans) > 0 {
+			tokens, ok = s.estimateExcerptTokens(filepath.Join(projectPath, filePath), filePath, spans, buildOpts)
+		} else {
+			tokens, ok = s.estimateFileTokens(filepath.Join(projectPath, filePath), filePath, buildOpts)
+		}
+		if !ok {
+			continue
+		}
+		total += tokens + s.tokenCounter.CountTokens(s.formatFileHeader(filePath, buildOpts.OutputFormat)+s.formatFileFooter(buildOpts.OutputFormat))
+	}
+	return total, nil
+}
+
+// estimateFileTokens estimates the tokens of one file from a sample of its head.
+// Unreadable and binary files are reported as not included.
+func (s *Service) estimateFileTokens(fullPath, filePath string, options *BuildOptions) (int, bool) {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return 0, err == nil
+	}
+	sample := make([]byte, min(info.Size(), estimateSampleBytes))
+	if _, err := io.ReadFull(file, sample); err != nil {
+		return 0, false
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return 0, false
+	}
+
+	content := s.applyContentOptimizations(string(sample), filePath, options)
+	if options.IncludeLineNumbers {
+		content = addLineNumbers(content)
+	}
+	tokens := s.tokenCounter.CountTokens(content)
+	return int(int64(tokens) * info.Size() / int64(len(sample))), true
+}
+
+// estimateExcerptTokens counts the tokens of the requested line ranges of a
+// file. The file is read in full, since only the ranges are counted.
+func (s *Service) estimateExcerptTokens(fullPath, filePath string, spans []lineSpan, options *BuildOptions) (int, bool) {
+	data, err := os.ReadFile(fullPath)
+	if err != nil || bytes.IndexByte(data, 0) >= 0 {
+		return 0, false
+	}
+	content, _ := s.prepareExcerpt(filePath, string(data), spans, options)
+	return s.tokenCounter.CountTokens(content), true
+}
+
+// expandPaths lists the files the file reader would read for the included
+// paths; readers that cannot expand paths get them unchanged
+func (s *Service) expandPaths(projectPath string, includedPaths []string) []string {
+	if expander, ok := s.fileReader.(domain.PathExpander); ok {
+		return expander.ExpandPaths(includedPaths, projectPath)
+	}
+	return includedPaths
+}
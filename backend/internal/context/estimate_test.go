@@ -0,0 +1,54 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"shotgun_code/infrastructure/filereader"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_EstimateContextTokens(t *testing.T) {
+	projectPath := t.TempDir()
+	small := "package main\n\nfunc main() {}\n"
+	large := strings.Repeat("// a comment line that pads the file\nvar x = 1\n", 2000)
+	files := map[string]string{
+		"main.go":           small,
+		"pkg/large.go":      large,
+		"pkg/large_test.go": small,
+		"pkg/.cache/x.go":   large,
+		"image.bin":         "\x00\x01\x02",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(projectPath, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), 0o644))
+	}
+
+	service := &Service{
+		tokenCounter: &IntegrationMockTokenCounter{},
+		logger:       &domain.NoopLogger{},
+		fileReader:   filereader.NewSecureFileReader(&domain.NoopLogger{}),
+	}
+	overhead := func(path string) int {
+		return len(service.formatFileHeader(path, FormatXML)+service.formatFileFooter(FormatXML)) / 4
+	}
+
+	tokens, err := service.EstimateContextTokens(projectPath, []string{"main.go"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, len(small)/4+overhead("main.go"), tokens, "small files are counted exactly")
+
+	tokens, err = service.EstimateContextTokens(projectPath, []string{"main.go", "pkg", "image.bin", "missing.go"}, &domain.ContextBuildOptions{})
+	require.NoError(t, err)
+	exact := len(small)/4*2 + len(large)/4*2
+	assert.InDelta(t, exact, tokens-overhead("main.go")-overhead("pkg/large.go")-overhead("pkg/large_test.go")-overhead("pkg/.cache/x.go"), float64(exact)/100,
+		"large files are scaled from a sample, directories are expanded like the reader does and binary files are left out")
+
+	stripped, err := service.EstimateContextTokens(projectPath, []string{"pkg"}, &domain.ContextBuildOptions{StripComments: true, ExcludeTests: true})
+	require.NoError(t, err)
+	assert.Less(t, stripped, len(large)/2/4*2+overhead("pkg/large.go")+overhead("pkg/.cache/x.go")+50, "optimizations are applied to the samples")
+}
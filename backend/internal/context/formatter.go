@@ -44,6 +44,18 @@ func formatFileFooter(format OutputFormat) string {
 	}
 }
 
+// formatTreeSection wraps a rendered directory tree for the output format
+func formatTreeSection(tree string, format OutputFormat) string {
+	switch format {
+	case FormatXML:
+		return "<directory_tree>\n" + escapeForFormat(tree, format) + "</directory_tree>\n\n"
+	case FormatPlain:
+		return "--- Directory Tree ---\n" + tree + "\n"
+	default: // FormatMarkdown
+		return "## Directory Tree\n\n```\n" + tree + "```\n\n"
+	}
+}
+
 // escapeForFormat escapes content based on output format
 func escapeForFormat(content string, format OutputFormat) string {
 	switch format {
@@ -60,8 +72,13 @@ func escapeForFormat(content string, format OutputFormat) string {
 
 // addLineNumbers adds line numbers to content
 func addLineNumbers(content string) string {
+	return addLineNumbersFrom(content, 1)
+}
+
+// addLineNumbersFrom adds line numbers to content starting at first
+func addLineNumbersFrom(content string, first int) string {
 	lines := strings.Split(content, "\n")
-	width := len(fmt.Sprintf("%d", len(lines)))
+	width := len(fmt.Sprintf("%d", first+len(lines)-1))
 
 	var result strings.Builder
 	result.Grow(len(content) + len(lines)*(width+3)) // pre-allocate
@@ -70,7 +87,7 @@ func addLineNumbers(content string) string {
 		if i > 0 {
 			result.WriteByte('\n')
 		}
-		result.WriteString(fmt.Sprintf("%*d | %s", width, i+1, line))
+		result.WriteString(fmt.Sprintf("%*d | %s", width, first+i, line))
 	}
 	return result.String()
 }
@@ -0,0 +1,215 @@
+package context
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// incrementalSaveInterval is how often (in included paths) the summary is persisted while building
+	incrementalSaveInterval = 50
+
+	// EventContextBuildProgress reports progress of an incremental context build
+	EventContextBuildProgress = "contextBuildProgress"
+
+	skipReasonTokenBudget = "token budget exceeded"
+)
+
+// BuildContextIncremental builds a context by reading the included files one
+// at a time and appending each to the on-disk context file, so memory use
+// does not grow with the number of files. Once the token budget is reached the
+// remaining paths are recorded as skipped. The summary is persisted with status
+// "building" as the build progresses and "ready" when it completes.
+// Directories are expanded up front, so the directory tree lists the same files.
+func (s *Service) BuildContextIncremental(ctx context.Context, projectPath string, includedPaths []string, options *domain.ContextBuildOptions) (summary *domain.ContextSummary, err error) {
+	atomic.AddInt64(&s.activeOperations, 1)
+	defer atomic.AddInt64(&s.activeOperations, -1)
+	atomic.AddInt64(&s.totalOperations, 1)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	buildOpts, err := s.convertBuildOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if buildOpts == nil {
+		buildOpts = &BuildOptions{OutputFormat: FormatXML, EnableProgressEvents: true}
+	}
+	if buildOpts.MaxTokens <= 0 {
+		buildOpts.MaxTokens = s.defaultMaxTokens
+	}
+	if err := s.validateLimits(buildOpts); err != nil {
+		return nil, err
+	}
+
+	paths, lineRanges := splitLineRanges(includedPaths)
+	paths = s.expandPaths(projectPath, paths)
+	if buildOpts.ExcludeTests {
+		paths = s.filterTestFiles(paths)
+	}
+
+	contextID := fmt.Sprintf("stream_%s", uuid.New().String())
+	contextPath := filepath.Join(s.contextDir, contextID+".ctx")
+
+	file, err := os.Create(contextPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create context file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(contextPath)
+			_ = os.Remove(filepath.Join(s.contextDir, contextID+".summary.json"))
+		}
+	}()
+
+	now := time.Now()
+	summary = &domain.ContextSummary{
+		ID:          contextID,
+		ProjectPath: projectPath,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Status:      "building",
+		Metadata: domain.ContextMetadata{
+			SelectedFiles: includedPaths,
+			ProjectPath:   projectPath,
+			ContentPath:   contextPath,
+		},
+	}
+	if err := s.SaveContextSummary(summary); err != nil {
+		return nil, err
+	}
+
+	writer := bufio.NewWriter(file)
+	state := newStreamWriteState(buildOpts, 0)
+	state.lineRanges = lineRanges
+	if err := s.writeStreamHeader(writer, projectPath, buildOpts, state); err != nil {
+		return nil, err
+	}
+	if err := s.writeTreeHeader(writer, paths, buildOpts, state); err != nil {
+		return nil, err
+	}
+
+	for i, includedPath := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if state.tokenCount >= buildOpts.MaxTokens {
+			s.skipContextPath(summary, includedPath, skipReasonTokenBudget)
+		} else if err := s.appendIncludedPath(ctx, writer, projectPath, includedPath, buildOpts, state, summary); err != nil {
+			return nil, err
+		}
+
+		summary.FileCount = len(state.files)
+		summary.TokenCount = state.tokenCount
+		summary.LineCount = int(state.totalLines)
+		summary.TotalSize = state.totalChars
+		summary.UpdatedAt = time.Now()
+
+		if buildOpts.EnableProgressEvents {
+			s.emitEvent(EventContextBuildProgress, map[string]interface{}{
+				"contextId": contextID, "current": i + 1, "total": len(paths), "tokens": state.tokenCount,
+			})
+		}
+		if (i+1)%incrementalSaveInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return nil, fmt.Errorf("failed to flush context file: %w", err)
+			}
+			if err := s.SaveContextSummary(summary); err != nil {
+				s.logger.Warning(fmt.Sprintf("Failed to save context summary: %v", err))
+			}
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to flush context file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close context file: %w", err)
+	}
+
+	if skipped := len(summary.Metadata.SkippedFiles); skipped > 0 {
+		summary.Metadata.Warnings = append(summary.Metadata.Warnings, fmt.Sprintf("%d paths were skipped", skipped))
+	}
+	summary.Status = "ready"
+	summary.Metadata.Optimization = state.optimization
+	summary.Metadata.BuildDuration = time.Since(now).Milliseconds()
+	summary.Metadata.LastModified = time.Now()
+	if err := s.SaveContextSummary(summary); err != nil {
+		return nil, err
+	}
+
+	s.streamsMu.Lock()
+	s.streams[contextID] = &Stream{
+		ID: contextID, Name: s.generateContextName(projectPath, state.files),
+		Description: fmt.Sprintf("Incremental context with %d files from %s", len(state.files), filepath.Base(projectPath)),
+		Files:       state.files, ProjectPath: projectPath, TotalLines: state.totalLines, TotalChars: state.totalChars,
+		CreatedAt: now, UpdatedAt: summary.UpdatedAt, TokenCount: state.tokenCount, contextPath: contextPath,
+	}
+	s.streamsMu.Unlock()
+
+	s.logger.Info(fmt.Sprintf("Built incremental context %s with %d files, %d tokens", contextID, summary.FileCount, summary.TokenCount))
+	return summary, nil
+}
+
+// appendIncludedPath reads one included path (a file or a directory) and
+// appends its files to the context until the token budget is reached
+func (s *Service) appendIncludedPath(ctx context.Context, writer *bufio.Writer, projectPath, includedPath string, options *BuildOptions, state *streamWriteState, summary *domain.ContextSummary) error {
+	contents, err := s.fileReader.ReadContents(ctx, []string{includedPath}, projectPath, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.skipContextPath(summary, includedPath, err.Error())
+		return nil
+	}
+	if len(contents) == 0 {
+		s.skipContextPath(summary, includedPath, "not readable")
+		return nil
+	}
+
+	files := make([]string, 0, len(contents))
+	for filePath := range contents {
+		files = append(files, filePath)
+	}
+	sort.Strings(files)
+
+	for _, filePath := range files {
+		content, stats := s.prepareIncludedContent(filePath, contents[filePath], options, state)
+		tokens := s.tokenCounter.CountTokens(content)
+		if state.tokenCount+tokens > options.MaxTokens {
+			s.skipContextPath(summary, filePath, skipReasonTokenBudget)
+			continue
+		}
+
+		state.tokenCount += tokens
+		state.recordOptimization(stats)
+		state.files = append(state.files, filePath)
+		if err := s.writePreparedFile(writer, filePath, content, options, state); err != nil {
+			return err
+		}
+		atomic.AddInt64(&s.totalBytesRead, int64(len(contents[filePath])))
+	}
+	return nil
+}
+
+// skipContextPath records a path left out of the context
+func (s *Service) skipContextPath(summary *domain.ContextSummary, path, reason string) {
+	if summary.Metadata.SkippedReasons == nil {
+		summary.Metadata.SkippedReasons = make(map[string]string)
+	}
+	summary.Metadata.SkippedFiles = append(summary.Metadata.SkippedFiles, path)
+	summary.Metadata.SkippedReasons[path] = reason
+}
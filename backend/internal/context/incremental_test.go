@@ -0,0 +1,95 @@
+package context
+
+import (
+	"context"
+	"shotgun_code/domain"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_BuildContextIncremental(t *testing.T) {
+	mockFileReader := new(MockFileContentReader)
+	mockTokenCounter := new(MockTokenCounter)
+	mockBus := new(MockEventBus)
+
+	service := &Service{
+		fileReader:   mockFileReader,
+		tokenCounter: mockTokenCounter,
+		eventBus:     mockBus,
+		logger:       &domain.NoopLogger{},
+		contextDir:   t.TempDir(),
+		streams:      make(map[string]*Stream),
+	}
+
+	projectPath := testProjectPathService
+	for _, path := range []string{"a.go", "b.go", "c.go"} {
+		mockFileReader.On("ReadContents", mock.Anything, []string{path}, projectPath, mock.Anything).
+			Return(map[string]string{path: "package " + strings.TrimSuffix(path, ".go")}, nil)
+	}
+	mockTokenCounter.On("CountTokens", mock.AnythingOfType("string")).Return(40)
+	mockBus.On("Emit", EventContextBuildProgress, mock.Anything).Return()
+
+	summary, err := service.BuildContextSummary(context.Background(), projectPath, []string{"a.go", "b.go", "c.go"},
+		&domain.ContextBuildOptions{MaxTokens: 100, Incremental: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ready", summary.Status)
+	assert.Equal(t, 2, summary.FileCount)
+	assert.Equal(t, 80, summary.TokenCount)
+	assert.Equal(t, []string{"c.go"}, summary.Metadata.SkippedFiles)
+	assert.Equal(t, skipReasonTokenBudget, summary.Metadata.SkippedReasons["c.go"])
+	mockBus.AssertNumberOfCalls(t, "Emit", 3)
+
+	stored, err := service.GetContextSummary(context.Background(), summary.ID)
+	require.NoError(t, err)
+	assert.Equal(t, summary.TokenCount, stored.TokenCount)
+
+	chunk, err := service.ReadContextChunk(context.Background(), summary.ID, 1, 100)
+	require.NoError(t, err)
+	content := strings.Join(chunk.Lines, "\n")
+	assert.Contains(t, content, "package a")
+	assert.Contains(t, content, "package b")
+	assert.NotContains(t, content, "package c")
+}
+
+func TestService_BuildContextIncremental_ReportsOptimization(t *testing.T) {
+	mockFileReader := new(MockFileContentReader)
+	service := &Service{
+		fileReader:   mockFileReader,
+		tokenCounter: &IntegrationMockTokenCounter{},
+		logger:       &domain.NoopLogger{},
+		contextDir:   t.TempDir(),
+		streams:      make(map[string]*Stream),
+	}
+
+	projectPath := testProjectPathService
+	padded := "package a\n\n\n\n\n\n\n\nfunc A() {}\n" + strings.Repeat("\n", 40)
+	mockFileReader.On("ReadContents", mock.Anything, []string{"a.go"}, projectPath, mock.Anything).
+		Return(map[string]string{"a.go": padded}, nil)
+
+	summary, err := service.BuildContextSummary(context.Background(), projectPath, []string{"a.go"},
+		&domain.ContextBuildOptions{MaxTokens: 1000, Incremental: true, CollapseEmptyLines: true})
+	require.NoError(t, err)
+
+	stats := summary.Metadata.Optimization
+	require.NotNil(t, stats)
+	require.Len(t, stats.Files, 1)
+	assert.Equal(t, len(padded)/4, stats.OriginalTokens)
+	assert.Less(t, stats.OptimizedTokens, stats.OriginalTokens)
+	assert.Greater(t, stats.SavedPercent, 0.0)
+
+	estimate, err := service.EstimateOptimization(context.Background(), projectPath, []string{"a.go"},
+		&domain.ContextBuildOptions{CollapseEmptyLines: true})
+	require.NoError(t, err)
+	assert.Equal(t, stats.OriginalTokens, estimate.OriginalTokens)
+	assert.Equal(t, stats.OptimizedTokens, estimate.OptimizedTokens)
+
+	plain, err := service.BuildContextSummary(context.Background(), projectPath, []string{"a.go"},
+		&domain.ContextBuildOptions{MaxTokens: 1000, Incremental: true})
+	require.NoError(t, err)
+	assert.Nil(t, plain.Metadata.Optimization, "no stats without optimizations")
+}
@@ -0,0 +1,144 @@
+package context
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"shotgun_code/domain"
+)
+
+// lineRangeContext is how many lines around a requested line range are included
+const lineRangeContext = 3
+
+// lineSpan is a 1-based inclusive range of lines
+type lineSpan struct {
+	start, end int
+}
+
+// lineSegment is a part of a file included for its requested line ranges
+type lineSegment struct {
+	lineSpan
+	text string
+}
+
+// parsePathSpec splits an included path with a line range suffix, such as
+// "handlers/foo.go:120-180" or "main.go:42", into the path and the range
+func parsePathSpec(spec string) (string, lineSpan, bool) {
+	i := strings.LastIndexByte(spec, ':')
+	if i <= 0 {
+		return spec, lineSpan{}, false
+	}
+	startText, endText, isRange := strings.Cut(spec[i+1:], "-")
+	start, err := strconv.Atoi(startText)
+	if err != nil || start < 1 {
+		return spec, lineSpan{}, false
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(endText); err != nil || end < start {
+			return spec, lineSpan{}, false
+		}
+	}
+	return spec[:i], lineSpan{start: start, end: end}, true
+}
+
+// splitLineRanges strips line ranges from included paths. It returns the paths
+// without duplicates and the ranges requested per file; a file that is also
+// included without a range is included whole. Paths are returned unchanged
+// when none has a range.
+func splitLineRanges(includedPaths []string) ([]string, map[string][]lineSpan) {
+	var ranges map[string][]lineSpan
+	whole := make(map[string]bool, len(includedPaths))
+	paths := make([]string, 0, len(includedPaths))
+	seen := make(map[string]bool, len(includedPaths))
+	for _, spec := range includedPaths {
+		path, span, ok := parsePathSpec(spec)
+		if ok {
+			if ranges == nil {
+				ranges = make(map[string][]lineSpan)
+			}
+			ranges[path] = append(ranges[path], span)
+		} else {
+			whole[path] = true
+		}
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	if ranges == nil {
+		return includedPaths, nil
+	}
+	for path := range whole {
+		delete(ranges, path)
+	}
+	return paths, ranges
+}
+
+// excerptSegments returns the lines of content in spans, widened by
+// lineRangeContext and merged where they overlap or touch. Ranges past the
+// end of the file are dropped.
+func excerptSegments(content string, spans []lineSpan) []lineSegment {
+	lines := strings.Split(content, "\n")
+	widened := make([]lineSpan, 0, len(spans))
+	for _, span := range spans {
+		start := max(span.start-lineRangeContext, 1)
+		end := min(span.end+lineRangeContext, len(lines))
+		if start <= end {
+			widened = append(widened, lineSpan{start: start, end: end})
+		}
+	}
+	sort.Slice(widened, func(i, j int) bool { return widened[i].start < widened[j].start })
+
+	var merged []lineSpan
+	for _, span := range widened {
+		if n := len(merged); n > 0 && span.start <= merged[n-1].end+1 {
+			merged[n-1].end = max(merged[n-1].end, span.end)
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	segments := make([]lineSegment, len(merged))
+	for i, span := range merged {
+		segments[i] = lineSegment{lineSpan: span, text: strings.Join(lines[span.start-1:span.end], "\n")}
+	}
+	return segments
+}
+
+// prepareExcerpt prepares the requested line ranges of a file like
+// prepareFileContent. Each segment is introduced by its line range, and line
+// numbers follow the numbering of the file.
+func (s *Service) prepareExcerpt(filePath, content string, spans []lineSpan, options *BuildOptions) (string, domain.FileOptimizationStats) {
+	segments := excerptSegments(content, spans)
+	parts := make([]string, 0, len(segments))
+	originalTokens, optimizedTokens := 0, 0
+	for _, segment := range segments {
+		text := s.applyContentOptimizations(segment.text, filePath, options)
+		if options.optimizesContent() {
+			originalTokens += s.tokenCounter.CountTokens(segment.text)
+			optimizedTokens += s.tokenCounter.CountTokens(text)
+		}
+		if options.IncludeLineNumbers {
+			text = addLineNumbersFrom(text, segment.start)
+		}
+		parts = append(parts, fmt.Sprintf("@@ lines %d-%d @@\n%s", segment.start, segment.end, text))
+	}
+
+	var stats domain.FileOptimizationStats
+	if options.optimizesContent() {
+		stats = domain.NewFileOptimizationStats(filePath, originalTokens, optimizedTokens)
+	}
+	return strings.Join(parts, "\n"), stats
+}
+
+// prepareIncludedContent prepares a file for the context, limited to its
+// requested line ranges if it has any
+func (s *Service) prepareIncludedContent(filePath, content string, options *BuildOptions, state *streamWriteState) (string, domain.FileOptimizationStats) {
+	if spans := state.lineRanges[filePath]; len(spans) > 0 {
+		return s.prepareExcerpt(filePath, content, spans, options)
+	}
+	return s.prepareFileContent(filePath, content, options)
+}
@@ -0,0 +1,144 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParsePathSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		path string
+		span lineSpan
+		ok   bool
+	}{
+		{"handlers/foo.go:120-180", "handlers/foo.go", lineSpan{120, 180}, true},
+		{"main.go:42", "main.go", lineSpan{42, 42}, true},
+		{"main.go", "main.go", lineSpan{}, false},
+		{"main.go:180-120", "main.go:180-120", lineSpan{}, false},
+		{"main.go:0", "main.go:0", lineSpan{}, false},
+		{`C:\src\main.go`, `C:\src\main.go`, lineSpan{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			path, span, ok := parsePathSpec(tt.spec)
+			assert.Equal(t, tt.path, path)
+			assert.Equal(t, tt.span, span)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestSplitLineRanges(t *testing.T) {
+	plain := []string{"a.go", "b.go"}
+	paths, ranges := splitLineRanges(plain)
+	assert.Equal(t, plain, paths)
+	assert.Nil(t, ranges)
+
+	paths, ranges = splitLineRanges([]string{"a.go:10-20", "b.go", "a.go:50", "b.go:1-5"})
+	assert.Equal(t, []string{"a.go", "b.go"}, paths)
+	assert.Equal(t, map[string][]lineSpan{"a.go": {{10, 20}, {50, 50}}}, ranges)
+}
+
+func TestExcerptSegments(t *testing.T) {
+	lines := make([]string, 30)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	content := strings.Join(lines, "\n")
+
+	// Nearby ranges merge, ranges near the edges are clamped and ranges past the end are dropped
+	segments := excerptSegments(content, []lineSpan{{20, 21}, {2, 3}, {8, 9}, {40, 45}, {29, 30}})
+	spans := make([]lineSpan, len(segments))
+	for i, segment := range segments {
+		spans[i] = segment.lineSpan
+	}
+	assert.Equal(t, []lineSpan{{1, 12}, {17, 24}, {26, 30}}, spans)
+	assert.Equal(t, "line 17\nline 18\nline 19\nline 20\nline 21\nline 22\nline 23\nline 24", segments[1].text)
+}
+
+func TestService_CreateStream_LineRanges(t *testing.T) {
+	mockFileReader := new(MockFileContentReader)
+	mockLogger := new(MockLogger)
+
+	service := &Service{
+		fileReader:   mockFileReader,
+		tokenCounter: &IntegrationMockTokenCounter{},
+		logger:       mockLogger,
+		contextDir:   t.TempDir(),
+		streams:      make(map[string]*Stream),
+	}
+
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	fileContents := map[string]string{
+		"handlers/foo.go": strings.Join(lines, "\n"),
+		"main.go":         "package main",
+	}
+	mockFileReader.On("ReadContents", mock.Anything, []string{"handlers/foo.go", "main.go"}, testProjectPathService, mock.Anything).Return(fileContents, nil)
+	mockLogger.On("Info", mock.AnythingOfType("string")).Return()
+
+	stream, err := service.CreateStream(context.Background(), testProjectPathService, []string{"handlers/foo.go:50-52", "main.go"}, &BuildOptions{
+		OutputFormat:       FormatPlain,
+		IncludeLineNumbers: true,
+		MaxTokens:          1000,
+		MaxMemoryMB:        100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"handlers/foo.go", "main.go"}, stream.Files)
+
+	data, err := os.ReadFile(stream.contextPath)
+	assert.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "@@ lines 47-55 @@\n47 | line 47\n")
+	assert.Contains(t, content, "55 | line 55\n")
+	assert.NotContains(t, content, "line 46\n")
+	assert.NotContains(t, content, "line 56\n")
+	assert.Contains(t, content, "1 | package main")
+}
+
+func TestService_GenerateContext_LineRanges(t *testing.T) {
+	mockFileReader := new(MockFileContentReader)
+	mockLogger := new(MockLogger)
+	mockBus := new(MockEventBus)
+
+	service := &Service{
+		fileReader:   mockFileReader,
+		tokenCounter: &IntegrationMockTokenCounter{},
+		logger:       mockLogger,
+		eventBus:     mockBus,
+	}
+
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	fileContents := map[string]string{
+		"handlers/foo.go": strings.Join(lines, "\n"),
+		"main.go":         "package main",
+	}
+	mockFileReader.On("ReadContents", mock.Anything, []string{"handlers/foo.go", "main.go"}, testProjectPathService, mock.Anything).Return(fileContents, nil)
+	mockLogger.On("Info", mock.AnythingOfType("string")).Return()
+	mockBus.On("Emit", mock.Anything, mock.Anything).Return()
+
+	service.generateContextSafe(context.Background(), testProjectPathService, []string{"handlers/foo.go:50-52", "main.go"})
+
+	var generated string
+	for _, call := range mockBus.Calls {
+		if call.Arguments.String(0) == "shotgunContextGenerated" {
+			generated, _ = call.Arguments.Get(1).([]interface{})[0].(string)
+		}
+	}
+	assert.Contains(t, generated, "--- File: handlers/foo.go ---\n@@ lines 47-55 @@\nline 47\n")
+	assert.NotContains(t, generated, "line 46\n")
+	assert.NotContains(t, generated, "line 56\n")
+	assert.Contains(t, generated, "--- File: main.go ---\npackage main")
+}
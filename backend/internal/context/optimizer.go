@@ -1,18 +1,111 @@
 package context
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"shotgun_code/domain"
+	"shotgun_code/internal/literals"
+	"sort"
 	"strings"
 )
 
+const (
+	// defaultSkeletonThresholdKB is the file size above which skeleton mode applies
+	defaultSkeletonThresholdKB = 16
+
+	// dataCompactMinBytes is the file size above which data files are compacted
+	dataCompactMinBytes = 4 * 1024
+)
+
+// SetContentOptimizer sets the optimizer used to generate skeletons in skeleton mode
+func (s *Service) SetContentOptimizer(optimizer domain.ContentOptimizer) {
+	s.contentOptimizer = optimizer
+}
+
+// OptimizationProfileSource provides the options of the custom optimization
+// profile, usually from settings
+type OptimizationProfileSource interface {
+	GetCustomOptimization() domain.ContentOptimizeOptions
+}
+
+// SetOptimizationProfileSource sets where the custom optimization profile comes from
+func (s *Service) SetOptimizationProfileSource(source OptimizationProfileSource) {
+	s.profileSource = source
+}
+
+// applyOptimizationProfile replaces the optimization options with those of the
+// profile the build names. Without a profile the options are returned unchanged,
+// so the settings default applies only when the caller sends it.
+func (s *Service) applyOptimizationProfile(opts *domain.ContextBuildOptions) (*domain.ContextBuildOptions, error) {
+	profile := opts.OptimizationProfile
+	if profile == "" {
+		return opts, nil
+	}
+	var custom domain.ContentOptimizeOptions
+	if s.profileSource != nil {
+		custom = s.profileSource.GetCustomOptimization()
+	}
+
+	optimize, err := domain.ResolveOptimizationProfile(profile, custom)
+	if err != nil {
+		return nil, err
+	}
+	resolved := *opts
+	resolved.CollapseEmptyLines = optimize.CollapseEmptyLines
+	resolved.StripLicense = optimize.StripLicense
+	resolved.StripComments = optimize.StripComments
+	resolved.CompactDataFiles = optimize.CompactDataFiles
+	resolved.SkeletonMode = optimize.SkeletonMode
+	resolved.TrimWhitespace = optimize.TrimWhitespace
+	return &resolved, nil
+}
+
+// EstimateOptimization reports how many tokens the content optimizations of
+// options would save on the given files, without building a context
+func (s *Service) EstimateOptimization(ctx context.Context, projectPath string, filePaths []string, options *domain.ContextBuildOptions) (*domain.OptimizationStats, error) {
+	buildOpts, err := s.convertBuildOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if buildOpts == nil {
+		buildOpts = &BuildOptions{}
+	}
+	if buildOpts.ExcludeTests {
+		filePaths = s.filterTestFiles(filePaths)
+	}
+
+	contents, err := s.fileReader.ReadContents(ctx, filePaths, projectPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file contents: %w", err)
+	}
+	files := make([]string, 0, len(contents))
+	for filePath := range contents {
+		files = append(files, filePath)
+	}
+	sort.Strings(files)
+
+	stats := &domain.OptimizationStats{}
+	for _, filePath := range files {
+		content := contents[filePath]
+		optimized := s.applyContentOptimizations(content, filePath, buildOpts)
+		stats.Add(domain.NewFileOptimizationStats(filePath, s.tokenCounter.CountTokens(content), s.tokenCounter.CountTokens(optimized)))
+	}
+	return stats, nil
+}
+
 // applyContentOptimizations applies all content optimizations based on options
 func (s *Service) applyContentOptimizations(content, filePath string, options *BuildOptions) string {
 	if options == nil {
 		return content
 	}
 
+	// 0. Skeleton mode replaces large files with their signatures
+	if skeleton, ok := s.skeletonFor(content, filePath, options); ok {
+		return skeleton
+	}
+
 	// 1. Strip comments (existing functionality)
 	if options.StripComments {
 		content = s.stripComments(content, filePath)
@@ -23,24 +116,50 @@ func (s *Service) applyContentOptimizations(content, filePath string, options *B
 		content = s.stripLicenseHeader(content, filePath)
 	}
 
-	// 3. Compact data files (JSON/YAML)
-	if options.CompactDataFiles {
+	// 3. Compact large data files (JSON/YAML)
+	if options.CompactDataFiles && len(content) >= dataCompactMinBytes {
 		content = s.compactDataFile(content, filePath)
 	}
 
 	// 4. Trim trailing whitespace
 	if options.TrimWhitespace {
-		content = s.trimTrailingWhitespace(content)
+		content = s.trimTrailingWhitespace(content, filePath)
 	}
 
 	// 5. Collapse empty lines (last, after all removals)
 	if options.CollapseEmptyLines {
-		content = s.collapseEmptyLines(content)
+		content = s.collapseEmptyLines(content, filePath)
 	}
 
 	return content
 }
 
+// CanGenerateSkeleton reports whether skeleton mode can shorten the file
+func (s *Service) CanGenerateSkeleton(filePath string) bool {
+	return s.contentOptimizer != nil && s.contentOptimizer.CanGenerateSkeleton(filePath)
+}
+
+// skeletonFor returns the skeleton of a file when skeleton mode is on, the
+// file is over the size threshold and its language is supported
+func (s *Service) skeletonFor(content, filePath string, options *BuildOptions) (string, bool) {
+	if !options.SkeletonMode || s.contentOptimizer == nil {
+		return "", false
+	}
+	threshold := options.SkeletonThresholdKB
+	if threshold <= 0 {
+		threshold = defaultSkeletonThresholdKB
+	}
+	if len(content) < threshold*1024 || !s.CanGenerateSkeleton(filePath) {
+		return "", false
+	}
+
+	skeleton := s.contentOptimizer.Optimize(context.Background(), content, filePath, domain.ContentOptimizeOptions{SkeletonMode: true})
+	if skeleton == "" || skeleton == content {
+		return "", false
+	}
+	return skeleton, true
+}
+
 // stripComments removes comments from code based on file extension
 func (s *Service) stripComments(content, filePath string) string {
 	ext := strings.ToLower(filepath.Ext(filePath))
@@ -134,14 +253,20 @@ func (s *Service) stripXMLComments(content string) string {
 }
 
 // stripLicenseHeader removes license/copyright headers from file content
+// using the content optimizer's license stripper when one is set. Only comments
+// with SPDX identifiers, copyright notices or license wording are removed, so
+// leading doc comments are kept
 func (s *Service) stripLicenseHeader(content, filePath string) string {
 	if len(content) == 0 {
 		return content
 	}
 
+	if s.contentOptimizer != nil {
+		return s.contentOptimizer.Optimize(context.Background(), content, filePath, domain.ContentOptimizeOptions{StripLicense: true})
+	}
+
 	licenseKeywords := []string{
-		"copyright", "license", "licensed", "spdx-license",
-		"mit license", "apache license", "bsd license",
+		"copyright", "spdx-license-identifier", "licensed under",
 		"all rights reserved", "permission is hereby granted",
 	}
 
@@ -212,8 +337,13 @@ func (s *Service) stripLicenseHeader(content, filePath string) string {
 	return content
 }
 
-// compactDataFile compacts JSON/YAML files
+// compactDataFile compacts JSON/YAML files, using the content optimizer
+// (which also shortens very large arrays) when one is set
 func (s *Service) compactDataFile(content, filePath string) string {
+	if s.contentOptimizer != nil {
+		return s.contentOptimizer.Optimize(context.Background(), content, filePath, domain.ContentOptimizeOptions{CompactDataFiles: true})
+	}
+
 	ext := strings.ToLower(filepath.Ext(filePath))
 
 	switch ext {
@@ -277,33 +407,46 @@ func (s *Service) compactYAML(content string) string {
 	return strings.Join(result, "\n")
 }
 
-// trimTrailingWhitespace removes trailing whitespace from each line
-func (s *Service) trimTrailingWhitespace(content string) string {
+// trimTrailingWhitespace removes trailing whitespace from each line,
+// leaving lines inside multi-line string literals untouched
+func (s *Service) trimTrailingWhitespace(content, filePath string) string {
+	protected := literals.ProtectedLines(content, filePath)
 	lines := strings.Split(content, "\n")
 	for i, line := range lines {
+		if i < len(protected) && protected[i] {
+			continue
+		}
 		lines[i] = strings.TrimRight(line, " \t")
 	}
 	return strings.Join(lines, "\n")
 }
 
-// collapseEmptyLines collapses multiple empty lines into maximum two
-func (s *Service) collapseEmptyLines(content string) string {
+// collapseEmptyLines collapses multiple empty lines into maximum two.
+// Blank lines inside multi-line string literals are kept as they are.
+func (s *Service) collapseEmptyLines(content, filePath string) string {
 	if !strings.Contains(content, "\n\n\n") {
 		return content // Fast path: no triple newlines
 	}
 
+	protected := literals.ProtectedLines(content, filePath)
+
 	var result strings.Builder
 	result.Grow(len(content))
 
 	emptyCount := 0
 	lineStart := 0
+	lineIdx := 0
 
 	for i := 0; i < len(content); i++ {
 		if content[i] == '\n' {
 			line := content[lineStart:i]
 			isEmpty := strings.TrimSpace(line) == ""
 
-			if isEmpty {
+			if lineIdx < len(protected) && protected[lineIdx] {
+				emptyCount = 0
+				result.WriteString(line)
+				result.WriteByte('\n')
+			} else if isEmpty {
 				emptyCount++
 				if emptyCount <= 2 {
 					result.WriteString(line)
@@ -315,6 +458,7 @@ func (s *Service) collapseEmptyLines(content string) string {
 				result.WriteByte('\n')
 			}
 			lineStart = i + 1
+			lineIdx++
 		}
 	}
 
@@ -73,6 +73,10 @@ func (s *Service) DeleteContext(ctx context.Context, contextID string) error {
 
 // BuildContextSummary implements domain.ContextBuilder interface
 func (s *Service) BuildContextSummary(ctx context.Context, projectPath string, includedPaths []string, options *domain.ContextBuildOptions) (*domain.ContextSummary, error) {
+	if options != nil && options.Incremental {
+		return s.BuildContextIncremental(ctx, projectPath, includedPaths, options)
+	}
+
 	atomic.AddInt64(&s.activeOperations, 1)
 	defer atomic.AddInt64(&s.activeOperations, -1)
 	atomic.AddInt64(&s.totalOperations, 1)
@@ -84,7 +88,10 @@ func (s *Service) BuildContextSummary(ctx context.Context, projectPath string, i
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
-	buildOpts := s.convertBuildOptions(options)
+	buildOpts, err := s.convertBuildOptions(options)
+	if err != nil {
+		return nil, err
+	}
 	domainCtx, err := s.BuildContext(ctx, projectPath, includedPaths, buildOpts)
 	if err != nil {
 		return nil, err
@@ -105,6 +112,7 @@ func (s *Service) BuildContextSummary(ctx context.Context, projectPath string, i
 		Metadata: domain.ContextMetadata{
 			SelectedFiles: includedPaths,
 			ProjectPath:   domainCtx.ProjectPath,
+			Optimization:  domainCtx.Optimization,
 		},
 	}
 
@@ -115,9 +123,13 @@ func (s *Service) BuildContextSummary(ctx context.Context, projectPath string, i
 	return summary, nil
 }
 
-func (s *Service) convertBuildOptions(opts *domain.ContextBuildOptions) *BuildOptions {
+func (s *Service) convertBuildOptions(opts *domain.ContextBuildOptions) (*BuildOptions, error) {
 	if opts == nil {
-		return nil
+		return nil, nil
+	}
+	opts, err := s.applyOptimizationProfile(opts)
+	if err != nil {
+		return nil, err
 	}
 
 	outputFormat := OutputFormat(opts.OutputFormat)
@@ -133,6 +145,7 @@ func (s *Service) convertBuildOptions(opts *domain.ContextBuildOptions) *BuildOp
 		StripComments:        opts.StripComments,
 		IncludeManifest:      opts.IncludeManifest,
 		IncludeLineNumbers:   opts.IncludeLineNumbers,
+		IncludeTree:          opts.IncludeTree,
 		ForceStream:          true,
 		EnableProgressEvents: true,
 		OutputFormat:         outputFormat,
@@ -141,8 +154,9 @@ func (s *Service) convertBuildOptions(opts *domain.ContextBuildOptions) *BuildOp
 		StripLicense:         opts.StripLicense,
 		CompactDataFiles:     opts.CompactDataFiles,
 		SkeletonMode:         opts.SkeletonMode,
+		SkeletonThresholdKB:  opts.SkeletonThresholdKB,
 		TrimWhitespace:       opts.TrimWhitespace,
-	}
+	}, nil
 }
 
 // SaveContextSummary persists context metadata
@@ -25,6 +25,12 @@ type Service struct {
 	logger       domain.Logger
 	contextDir   string
 
+	// Optional AST-based optimizer used for skeleton mode
+	contentOptimizer domain.ContentOptimizer
+
+	// Optional source of the default and custom optimization profiles
+	profileSource OptimizationProfileSource
+
 	// Streaming support with RWMutex for concurrent reads
 	streams   map[string]*Stream
 	streamsMu sync.RWMutex
@@ -62,6 +68,7 @@ type BuildOptions struct {
 	StripComments        bool         `json:"stripComments,omitempty"`
 	IncludeManifest      bool         `json:"includeManifest,omitempty"`
 	IncludeLineNumbers   bool         `json:"includeLineNumbers,omitempty"`
+	IncludeTree          bool         `json:"includeTree,omitempty"`
 	ForceStream          bool         `json:"forceStream,omitempty"`
 	EnableProgressEvents bool         `json:"enableProgressEvents,omitempty"`
 	OutputFormat         OutputFormat `json:"outputFormat,omitempty"`
@@ -70,9 +77,15 @@ type BuildOptions struct {
 	StripLicense         bool         `json:"stripLicense,omitempty"`
 	CompactDataFiles     bool         `json:"compactDataFiles,omitempty"`
 	SkeletonMode         bool         `json:"skeletonMode,omitempty"`
+	SkeletonThresholdKB  int          `json:"skeletonThresholdKB,omitempty"`
 	TrimWhitespace       bool         `json:"trimWhitespace,omitempty"`
 }
 
+// optimizesContent reports whether any option rewrites file content
+func (o *BuildOptions) optimizesContent() bool {
+	return o.StripComments || o.CollapseEmptyLines || o.StripLicense || o.CompactDataFiles || o.SkeletonMode || o.TrimWhitespace
+}
+
 // Context is an alias for domain.Context used internally
 type Context = domain.Context
 
@@ -192,6 +205,8 @@ func (s *Service) buildStreamingContext(ctx context.Context, projectPath string,
 		TokenCount:  stream.TokenCount,
 		TotalLines:  stream.TotalLines,
 		TotalChars:  stream.TotalChars,
+
+		Optimization: stream.Optimization,
 	}, nil
 }
 
@@ -239,6 +254,7 @@ func (s *Service) generateContextSafe(ctx context.Context, rootDir string, inclu
 		}
 	}()
 
+	includedPaths, lineRanges := splitLineRanges(includedPaths)
 	s.emitEvent("shotgunContextGenerationStarted", map[string]interface{}{"fileCount": len(includedPaths), "rootDir": rootDir})
 
 	if ctx == nil {
@@ -273,6 +289,13 @@ func (s *Service) generateContextSafe(ctx context.Context, rootDir string, inclu
 		return
 	}
 
+	// Files requested with line ranges keep only those lines
+	for filePath, spans := range lineRanges {
+		if content, ok := contents[filePath]; ok {
+			contents[filePath], _ = s.prepareExcerpt(filePath, content, spans, &BuildOptions{})
+		}
+	}
+
 	finalContext := s.buildContextString(contents)
 	s.logger.Info(fmt.Sprintf("Async context generation completed. Length: %d characters", len(finalContext)))
 	s.emitEvent("shotgunContextGenerated", finalContext)
@@ -1,6 +1,7 @@
 package context
 
 import (
+	"shotgun_code/domain"
 	"strings"
 	"testing"
 
@@ -220,7 +221,7 @@ func TestService_trimTrailingWhitespace(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := service.trimTrailingWhitespace(tt.input)
+			result := service.trimTrailingWhitespace(tt.input, "file.txt")
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -259,12 +260,24 @@ func TestService_collapseEmptyLines(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := service.collapseEmptyLines(tt.input)
+			result := service.collapseEmptyLines(tt.input, "file.txt")
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
+// TestService_whitespaceKeepsMultilineStrings checks that string literals survive cleanup
+func TestService_whitespaceKeepsMultilineStrings(t *testing.T) {
+	service := &Service{}
+	input := "package cfg\n\n\n\nconst manifest = `\nkey: value  \n\n\n\nother: x\n`  \n"
+
+	trimmed := service.trimTrailingWhitespace(input, "cfg.go")
+	assert.Equal(t, "package cfg\n\n\n\nconst manifest = `\nkey: value  \n\n\n\nother: x\n`\n", trimmed)
+
+	collapsed := service.collapseEmptyLines(trimmed, "cfg.go")
+	assert.Equal(t, "package cfg\n\n\nconst manifest = `\nkey: value  \n\n\n\nother: x\n`\n", collapsed)
+}
+
 // TestService_applyContentOptimizations tests the full optimization pipeline
 func TestService_applyContentOptimizations(t *testing.T) {
 	service := &Service{}
@@ -314,6 +327,19 @@ func TestService_applyContentOptimizations_NilOptions(t *testing.T) {
 	assert.Equal(t, input, result)
 }
 
+// TestService_applyContentOptimizations_CompactThreshold tests that only large data files are compacted
+func TestService_applyContentOptimizations_CompactThreshold(t *testing.T) {
+	service := &Service{}
+	options := &BuildOptions{CompactDataFiles: true}
+
+	small := "{\n  \"name\": \"test\"\n}"
+	assert.Equal(t, small, service.applyContentOptimizations(small, "config.json", options))
+
+	large := "{\n  \"name\": \"" + strings.Repeat("x", dataCompactMinBytes) + "\"\n}"
+	result := service.applyContentOptimizations(large, "config.json", options)
+	assert.NotContains(t, result, "\n")
+}
+
 // TestService_compactJSON tests JSON compaction
 func TestService_compactJSON(t *testing.T) {
 	service := &Service{}
@@ -370,3 +396,47 @@ value: 123`
 	assert.Contains(t, result, "name: test")
 	assert.Contains(t, result, "value: 123")
 }
+
+// fakeProfileSource returns fixed custom profile options
+type fakeProfileSource struct {
+	custom domain.ContentOptimizeOptions
+}
+
+func (f fakeProfileSource) GetCustomOptimization() domain.ContentOptimizeOptions {
+	return f.custom
+}
+
+// TestService_applyOptimizationProfile tests that profiles replace the individual options
+func TestService_applyOptimizationProfile(t *testing.T) {
+	service := &Service{}
+	opts := &domain.ContextBuildOptions{StripComments: true, MaxTokens: 1000}
+
+	result, err := service.applyOptimizationProfile(opts)
+	assert.NoError(t, err)
+	assert.Same(t, opts, result, "options without a profile are kept")
+
+	result, err = service.applyOptimizationProfile(&domain.ContextBuildOptions{OptimizationProfile: "aggressive", MaxTokens: 1000})
+	assert.NoError(t, err)
+	assert.True(t, result.SkeletonMode && result.StripComments && result.CompactDataFiles)
+	assert.Equal(t, 1000, result.MaxTokens)
+
+	service.SetOptimizationProfileSource(fakeProfileSource{custom: domain.ContentOptimizeOptions{CompactDataFiles: true}})
+	result, err = service.applyOptimizationProfile(opts)
+	assert.NoError(t, err)
+	assert.Same(t, opts, result, "the settings default is not applied unless requested")
+
+	balanced := &domain.ContextBuildOptions{OptimizationProfile: "balanced", StripComments: true}
+	result, err = service.applyOptimizationProfile(balanced)
+	assert.NoError(t, err)
+	assert.True(t, result.StripLicense && result.CollapseEmptyLines)
+	assert.False(t, result.StripComments, "the profile replaces the individual options")
+	assert.True(t, balanced.StripComments, "the request options are not modified")
+
+	result, err = service.applyOptimizationProfile(&domain.ContextBuildOptions{OptimizationProfile: "custom"})
+	assert.NoError(t, err)
+	assert.True(t, result.CompactDataFiles)
+	assert.False(t, result.StripLicense)
+
+	_, err = service.applyOptimizationProfile(&domain.ContextBuildOptions{OptimizationProfile: "turbo"})
+	assert.Error(t, err)
+}
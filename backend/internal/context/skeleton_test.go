@@ -0,0 +1,39 @@
+package context
+
+import (
+	"context"
+	"shotgun_code/domain"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOptimizer turns every Go file into a fixed skeleton
+type fakeOptimizer struct{}
+
+func (fakeOptimizer) Optimize(_ context.Context, _ string, _ string, opts domain.ContentOptimizeOptions) string {
+	if opts.SkeletonMode {
+		return "func Big()"
+	}
+	return ""
+}
+
+func (fakeOptimizer) OptimizeWithDefaults(_ context.Context, s, _ string) string { return s }
+
+func (fakeOptimizer) CanGenerateSkeleton(path string) bool { return strings.HasSuffix(path, ".go") }
+
+func TestApplyContentOptimizations_SkeletonMode(t *testing.T) {
+	service := &Service{}
+	service.SetContentOptimizer(fakeOptimizer{})
+
+	large := strings.Repeat("x", 2*1024)
+	options := &BuildOptions{SkeletonMode: true, SkeletonThresholdKB: 1}
+
+	assert.Equal(t, "func Big()", service.applyContentOptimizations(large, "big.go", options))
+	assert.Equal(t, "small", service.applyContentOptimizations("small", "small.go", options), "files under the threshold are kept")
+	assert.Equal(t, large, service.applyContentOptimizations(large, "big.txt", options), "unsupported languages are kept")
+
+	options.SkeletonMode = false
+	assert.Equal(t, large, service.applyContentOptimizations(large, "big.go", options))
+}
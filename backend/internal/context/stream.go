@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"shotgun_code/domain"
 	"strings"
 	"time"
 
@@ -25,6 +26,8 @@ type Stream struct {
 	UpdatedAt   time.Time `json:"updatedAt"`
 	TokenCount  int       `json:"tokenCount"`
 	contextPath string    `json:"-"`
+
+	Optimization *domain.OptimizationStats `json:"optimization,omitempty"`
 }
 
 // LineRange represents a range of lines from a streaming context
@@ -40,6 +43,21 @@ type streamWriteState struct {
 	totalChars int64
 	tokenCount int
 	files      []string
+
+	// lineRanges limits files to the line ranges requested for them
+	lineRanges map[string][]lineSpan
+
+	// optimization is nil unless the build optimizes content
+	optimization *domain.OptimizationStats
+}
+
+// newStreamWriteState creates the write state of a build
+func newStreamWriteState(options *BuildOptions, fileCount int) *streamWriteState {
+	state := &streamWriteState{files: make([]string, 0, fileCount)}
+	if options.optimizesContent() {
+		state.optimization = &domain.OptimizationStats{}
+	}
+	return state
 }
 
 // createProgressCallback creates a progress callback for file reading
@@ -69,23 +87,73 @@ func (s *Service) writeStreamHeader(writer *bufio.Writer, projectPath string, op
 	return nil
 }
 
-// writeFileToStream writes a single file to the stream
-func (s *Service) writeFileToStream(writer *bufio.Writer, filePath, content string, options *BuildOptions, state *streamWriteState) error {
-	// Apply content optimizations
-	content = s.applyContentOptimizations(content, filePath, options)
+// treeHeader renders the directory tree of paths for the output format, or
+// returns "" unless the build includes the tree
+func (s *Service) treeHeader(paths []string, options *BuildOptions) string {
+	if !options.IncludeTree || len(paths) == 0 {
+		return ""
+	}
+	format := options.OutputFormat
+	if format == "" {
+		format = FormatXML
+	}
+	return formatTreeSection(s.buildSimpleTree(paths), format)
+}
 
-	// Add line numbers if requested
+// writeTreeHeader writes the directory tree of the included paths if requested
+// and counts its tokens towards the context
+func (s *Service) writeTreeHeader(writer *bufio.Writer, paths []string, options *BuildOptions, state *streamWriteState) error {
+	header := s.treeHeader(paths, options)
+	if header == "" {
+		return nil
+	}
+	if _, err := writer.WriteString(header); err != nil {
+		return fmt.Errorf("failed to write directory tree: %w", err)
+	}
+	state.tokenCount += s.tokenCounter.CountTokens(header)
+	state.totalLines += int64(strings.Count(header, "\n"))
+	state.totalChars += int64(len(header))
+	return nil
+}
+
+// prepareFileContent applies content optimizations and line numbers to a file.
+// When the build optimizes content it also measures the tokens saved.
+func (s *Service) prepareFileContent(filePath, content string, options *BuildOptions) (string, domain.FileOptimizationStats) {
+	optimized := s.applyContentOptimizations(content, filePath, options)
+	var stats domain.FileOptimizationStats
+	if options.optimizesContent() {
+		stats = domain.NewFileOptimizationStats(filePath, s.tokenCounter.CountTokens(content), s.tokenCounter.CountTokens(optimized))
+	}
 	if options.IncludeLineNumbers {
-		content = addLineNumbers(content)
+		optimized = addLineNumbers(optimized)
 	}
+	return optimized, stats
+}
+
+// recordOptimization adds the savings of a file written to the context
+func (state *streamWriteState) recordOptimization(stats domain.FileOptimizationStats) {
+	if state.optimization != nil {
+		state.optimization.Add(stats)
+	}
+}
+
+// writeFileToStream writes a single file to the stream
+func (s *Service) writeFileToStream(writer *bufio.Writer, filePath, content string, options *BuildOptions, state *streamWriteState) error {
+	content, stats := s.prepareIncludedContent(filePath, content, options, state)
 
 	fileTokens := s.tokenCounter.CountTokens(content)
 	state.tokenCount += fileTokens
+	state.recordOptimization(stats)
 
 	if options.MaxTokens > 0 && state.tokenCount > options.MaxTokens {
 		return fmt.Errorf("context would exceed token limit: %d > %d", state.tokenCount, options.MaxTokens)
 	}
 
+	return s.writePreparedFile(writer, filePath, content, options, state)
+}
+
+// writePreparedFile writes an already prepared file with its header and footer
+func (s *Service) writePreparedFile(writer *bufio.Writer, filePath, content string, options *BuildOptions, state *streamWriteState) error {
 	format := options.OutputFormat
 	if format == "" {
 		format = FormatXML // Default to XML - best for AI context
@@ -124,6 +192,9 @@ func (s *Service) CreateStream(ctx context.Context, projectPath string, included
 		return nil, err
 	}
 
+	includedPaths, lineRanges := splitLineRanges(includedPaths)
+	includedPaths = s.expandPaths(projectPath, includedPaths)
+
 	// Filter out test files if requested
 	if options.ExcludeTests {
 		includedPaths = s.filterTestFiles(includedPaths)
@@ -165,19 +236,26 @@ func (s *Service) CreateStream(ctx context.Context, projectPath string, included
 		}
 	}()
 
-	state := &streamWriteState{files: make([]string, 0, len(includedPaths))}
+	state := newStreamWriteState(options, len(includedPaths))
+	state.lineRanges = lineRanges
 
 	if err := s.writeStreamHeader(writer, projectPath, options, state); err != nil {
 		return nil, err
 	}
-
+	files := make([]string, 0, len(contents))
 	for _, filePath := range includedPaths {
-		content, exists := contents[filePath]
-		if !exists {
+		if _, exists := contents[filePath]; exists {
+			files = append(files, filePath)
+		} else {
 			s.logger.Warning(fmt.Sprintf("[CreateStream] File not found: %s", filePath))
-			continue
 		}
+	}
+	if err := s.writeTreeHeader(writer, files, options, state); err != nil {
+		return nil, err
+	}
 
+	for _, filePath := range files {
+		content := contents[filePath]
 		state.files = append(state.files, filePath)
 		if err := s.writeFileToStream(writer, filePath, content, options, state); err != nil {
 			_ = file.Close()
@@ -192,6 +270,7 @@ func (s *Service) CreateStream(ctx context.Context, projectPath string, included
 		Description: fmt.Sprintf("Streaming context with %d files from %s", len(state.files), filepath.Base(projectPath)),
 		Files:       state.files, ProjectPath: projectPath, TotalLines: state.totalLines, TotalChars: state.totalChars,
 		CreatedAt: now, UpdatedAt: now, TokenCount: state.tokenCount, contextPath: contextPath,
+		Optimization: state.optimization,
 	}
 
 	s.streamsMu.Lock()
@@ -3,11 +3,16 @@ package context
 import (
 	"context"
 	"os"
+	"path/filepath"
+	"shotgun_code/domain"
+	"shotgun_code/infrastructure/filereader"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestService_CreateStream(t *testing.T) {
@@ -84,6 +89,53 @@ func TestService_CreateStream(t *testing.T) {
 	// Skipping event bus assertions for now
 }
 
+func TestService_CreateStream_IncludeTree(t *testing.T) {
+	mockFileReader := new(MockFileContentReader)
+	mockLogger := new(MockLogger)
+
+	service := &Service{
+		fileReader:   mockFileReader,
+		tokenCounter: &IntegrationMockTokenCounter{},
+		logger:       mockLogger,
+		contextDir:   t.TempDir(),
+		streams:      make(map[string]*Stream),
+	}
+
+	includedPaths := []string{"src/main.go", "src/util/strings.go", "README.md"}
+	fileContents := map[string]string{
+		"src/main.go":         "package main",
+		"src/util/strings.go": "package util",
+		"README.md":           "# Demo",
+	}
+	mockFileReader.On("ReadContents", mock.Anything, includedPaths, testProjectPathService, mock.Anything).Return(fileContents, nil)
+	mockLogger.On("Info", mock.AnythingOfType("string")).Return()
+
+	build := func(includeTree bool) (*Stream, string) {
+		stream, err := service.CreateStream(context.Background(), testProjectPathService, includedPaths, &BuildOptions{
+			OutputFormat: FormatMarkdown,
+			IncludeTree:  includeTree,
+			MaxTokens:    1000,
+			MaxMemoryMB:  100,
+		})
+		assert.NoError(t, err)
+		content, err := os.ReadFile(stream.contextPath)
+		assert.NoError(t, err)
+		return stream, string(content)
+	}
+
+	plain, plainContent := build(false)
+	withTree, treeContent := build(true)
+
+	assert.NotContains(t, plainContent, "Directory Tree")
+	assert.True(t, strings.HasPrefix(treeContent, "## Directory Tree\n\n```\n"))
+	assert.Contains(t, treeContent, "├─ README.md\n└─ src\n   ├─ main.go\n   └─ util\n      └─ strings.go\n")
+	assert.Less(t, strings.Index(treeContent, "strings.go"), strings.Index(treeContent, "## File:"))
+
+	// The tree counts towards the tokens of the context
+	tree := formatTreeSection(service.buildSimpleTree(includedPaths), FormatMarkdown)
+	assert.Equal(t, plain.TokenCount+len(tree)/4, withTree.TokenCount)
+}
+
 func TestService_CreateStream_MemoryLimitExceeded(t *testing.T) {
 	// Skip this test since it requires filesystem access for size estimation
 	// The memory limit check happens in estimateTotalSize which uses os.Stat
@@ -285,3 +337,43 @@ func TestService_BuildContext_StreamingForced(t *testing.T) {
 	mockLogger.AssertExpectations(t)
 	// Skipping event bus assertions for now
 }
+
+func TestService_DirectoryTreeListsExpandedFiles(t *testing.T) {
+	projectPath := t.TempDir()
+	for _, name := range []string{"pkg/a.go", "pkg/.gen/b.go"} {
+		fullPath := filepath.Join(projectPath, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755))
+		require.NoError(t, os.WriteFile(fullPath, []byte("package pkg\n"), 0o644))
+	}
+	service := &Service{
+		fileReader:   filereader.NewSecureFileReader(&domain.NoopLogger{}),
+		tokenCounter: &IntegrationMockTokenCounter{},
+		logger:       &domain.NoopLogger{},
+		contextDir:   t.TempDir(),
+		streams:      make(map[string]*Stream),
+	}
+	tree := formatTreeSection(service.buildSimpleTree([]string{"pkg/.gen/b.go", "pkg/a.go"}), FormatMarkdown)
+
+	stream, err := service.CreateStream(context.Background(), projectPath, []string{"pkg"}, &BuildOptions{
+		OutputFormat: FormatMarkdown, IncludeTree: true, MaxTokens: 1000, MaxMemoryMB: 100,
+	})
+	require.NoError(t, err)
+	content, err := os.ReadFile(stream.contextPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), tree), "stream tree:\n%s", content)
+
+	options := &domain.ContextBuildOptions{OutputFormat: string(FormatMarkdown), IncludeTree: true, MaxTokens: 1000}
+	summary, err := service.BuildContextIncremental(context.Background(), projectPath, []string{"pkg"}, options)
+	require.NoError(t, err)
+	content, err = os.ReadFile(summary.Metadata.ContentPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), tree), "incremental tree:\n%s", content)
+	assert.Equal(t, 2, summary.FileCount)
+
+	withTree, err := service.EstimateContextTokens(projectPath, []string{"pkg"}, options)
+	require.NoError(t, err)
+	options.IncludeTree = false
+	withoutTree, err := service.EstimateContextTokens(projectPath, []string{"pkg"}, options)
+	require.NoError(t, err)
+	assert.Equal(t, len(tree)/4, withTree-withoutTree, "the estimate counts the same tree")
+}
@@ -0,0 +1,65 @@
+package executil
+
+import "fmt"
+
+// cappedBuffer keeps the first and the last limit/2 bytes written and drops the
+// middle, so a runaway command can't exhaust memory while the beginning and the
+// error-rich end of its output survive. A limit <= 0 keeps everything
+type cappedBuffer struct {
+	limit   int
+	head    []byte
+	tail    []byte
+	dropped int64
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if b.limit <= 0 {
+		b.head = append(b.head, p...)
+		return n, nil
+	}
+
+	headCap := b.limit / 2
+	if free := headCap - len(b.head); free > 0 {
+		take := min(free, len(p))
+		b.head = append(b.head, p[:take]...)
+		p = p[take:]
+	}
+	if len(p) == 0 {
+		return n, nil
+	}
+
+	// The tail grows up to twice its size before it is compacted,
+	// so copying stays proportional to the bytes written
+	tailCap := b.limit - headCap
+	if len(p) > tailCap {
+		b.dropped += int64(len(b.tail) + len(p) - tailCap)
+		b.tail = append(b.tail[:0], p[len(p)-tailCap:]...)
+		return n, nil
+	}
+	b.tail = append(b.tail, p...)
+	if len(b.tail) >= 2*tailCap {
+		over := len(b.tail) - tailCap
+		b.dropped += int64(over)
+		b.tail = append(b.tail[:0], b.tail[over:]...)
+	}
+	return n, nil
+}
+
+// Bytes returns the kept output with a marker in place of the dropped middle
+func (b *cappedBuffer) Bytes() []byte {
+	tail, dropped := b.tail, b.dropped
+	if tailCap := b.limit - b.limit/2; b.limit > 0 && len(tail) > tailCap {
+		dropped += int64(len(tail) - tailCap)
+		tail = tail[len(tail)-tailCap:]
+	}
+	if dropped == 0 {
+		return append(b.head[:len(b.head):len(b.head)], tail...)
+	}
+
+	marker := fmt.Sprintf("\n... [%d bytes truncated] ...\n", dropped)
+	out := make([]byte, 0, len(b.head)+len(marker)+len(tail))
+	out = append(out, b.head...)
+	out = append(out, marker...)
+	return append(out, tail...)
+}
@@ -0,0 +1,80 @@
+package executil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCappedBuffer_KeepsEverythingUnderLimit(t *testing.T) {
+	b := &cappedBuffer{limit: 16}
+	b.Write([]byte("hello "))
+	b.Write([]byte("world"))
+	if got := string(b.Bytes()); got != "hello world" {
+		t.Errorf("Bytes() = %q", got)
+	}
+}
+
+func TestCappedBuffer_KeepsHeadAndTail(t *testing.T) {
+	b := &cappedBuffer{limit: 8}
+	for _, chunk := range []string{"abcd", "efgh", "ijkl", "mnop", "qr"} {
+		b.Write([]byte(chunk))
+	}
+	// 18 bytes written: the first 4 and the last 4 are kept
+	want := "abcd\n... [10 bytes truncated] ...\nopqr"
+	if got := string(b.Bytes()); got != want {
+		t.Errorf("Bytes() = %q, want %q", got, want)
+	}
+}
+
+func TestCappedBuffer_LargeWrite(t *testing.T) {
+	b := &cappedBuffer{limit: 10}
+	b.Write([]byte(strings.Repeat("x", 5) + strings.Repeat("-", 100) + "12345"))
+	want := "xxxxx\n... [100 bytes truncated] ...\n12345"
+	if got := string(b.Bytes()); got != want {
+		t.Errorf("Bytes() = %q, want %q", got, want)
+	}
+}
+
+func TestCappedBuffer_ManySmallWritesStayBounded(t *testing.T) {
+	b := &cappedBuffer{limit: 100}
+	for i := 0; i < 10000; i++ {
+		b.Write([]byte("0123456789"))
+	}
+	if len(b.tail) > 2*50 {
+		t.Errorf("tail grew to %d bytes", len(b.tail))
+	}
+	out := b.Bytes()
+	if !bytes.HasPrefix(out, []byte(strings.Repeat("0123456789", 5))) || !bytes.HasSuffix(out, []byte(strings.Repeat("0123456789", 5))) {
+		t.Errorf("unexpected output %q", out)
+	}
+	if !bytes.Contains(out, []byte("[99900 bytes truncated]")) {
+		t.Errorf("expected the truncated byte count, got %q", out)
+	}
+}
+
+func TestCappedBuffer_NoLimit(t *testing.T) {
+	b := &cappedBuffer{}
+	b.Write(bytes.Repeat([]byte("a"), 1<<20))
+	if len(b.Bytes()) != 1<<20 {
+		t.Errorf("expected everything kept, got %d bytes", len(b.Bytes()))
+	}
+}
+
+func TestLineWriter_SplitsLongLines(t *testing.T) {
+	var lines []string
+	w := &lineWriter{stream: "stdout", onLine: func(_, line string) { lines = append(lines, line) }}
+
+	w.Write([]byte("short\r\n"))
+	w.Write(bytes.Repeat([]byte("x"), maxLineBytes+10))
+	if len(w.pending) >= maxLineBytes {
+		t.Errorf("pending not bounded: %d bytes", len(w.pending))
+	}
+	w.Write([]byte("end\npartial"))
+	w.flush()
+
+	if len(lines) != 4 || lines[0] != "short" || len(lines[1]) != maxLineBytes ||
+		lines[2] != strings.Repeat("x", 10)+"end" || lines[3] != "partial" {
+		t.Errorf("unexpected lines: %d lines, first %q, last %q", len(lines), lines[0], lines[len(lines)-1])
+	}
+}
@@ -0,0 +1,34 @@
+package executil
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// DefaultCommandTimeout limits external commands (linters, formatters, builds)
+// whose callers don't set a deadline
+const DefaultCommandTimeout = 10 * time.Minute
+
+// waitDelay bounds how long Wait waits for output pipes after the process was
+// killed, in case an orphaned grandchild still holds them open
+const waitDelay = 5 * time.Second
+
+// CommandContext creates a command bound to ctx with the console window hidden.
+// When ctx is cancelled or its deadline passes, the whole process tree is killed,
+// not only the direct child (e.g. npx and the node process it spawns).
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	HideWindow(cmd)
+	killTreeOnCancel(cmd)
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+// WithTimeout applies timeout to ctx; timeout <= 0 leaves ctx unchanged
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
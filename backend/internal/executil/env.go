@@ -0,0 +1,94 @@
+package executil
+
+import (
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// MergeEnv returns base ("KEY=value" entries, e.g. os.Environ()) with overrides
+// applied. $VAR and ${VAR} in an override expand to the value in base, so
+// "PATH": "/opt/go/bin:$PATH" prepends to the inherited PATH
+func MergeEnv(base []string, overrides map[string]string) []string {
+	if len(overrides) == 0 {
+		return base
+	}
+
+	lookup := make(map[string]string, len(base))
+	for _, entry := range base {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			lookup[envKey(key)] = value
+		}
+	}
+
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	overridden := make(map[string]bool, len(overrides))
+	for key := range overrides {
+		overridden[envKey(key)] = true
+	}
+
+	merged := make([]string, 0, len(base)+len(overrides))
+	for _, entry := range base {
+		key, _, _ := strings.Cut(entry, "=")
+		if !overridden[envKey(key)] {
+			merged = append(merged, entry)
+		}
+	}
+	for _, key := range keys {
+		value := os.Expand(overrides[key], func(name string) string {
+			return lookup[envKey(name)]
+		})
+		merged = append(merged, key+"="+value)
+	}
+	return merged
+}
+
+// unsafeEnvKeys and unsafeEnvPrefixes are variables that change which
+// programs, shell startup files or shared libraries a command runs
+var (
+	unsafeEnvKeys     = map[string]bool{"PATH": true, "PATHEXT": true, "BASH_ENV": true, "ENV": true}
+	unsafeEnvPrefixes = []string{"LD_", "DYLD_"}
+)
+
+// FilterUnsafeEnv splits env from an untrusted source (a project's config)
+// into the variables that may be passed to commands and the sorted names of
+// those that may not, such as PATH and LD_PRELOAD
+func FilterUnsafeEnv(env map[string]string) (map[string]string, []string) {
+	safe := make(map[string]string, len(env))
+	var rejected []string
+	for key, value := range env {
+		if isUnsafeEnvKey(envKey(key)) {
+			rejected = append(rejected, key)
+			continue
+		}
+		safe[key] = value
+	}
+	sort.Strings(rejected)
+	return safe, rejected
+}
+
+func isUnsafeEnvKey(key string) bool {
+	if unsafeEnvKeys[key] {
+		return true
+	}
+	for _, prefix := range unsafeEnvPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// envKey normalizes a variable name; environment keys are case-insensitive on Windows
+func envKey(key string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(key)
+	}
+	return key
+}
@@ -2,9 +2,24 @@
 
 package executil
 
-import "os/exec"
+import (
+	"os/exec"
+	"syscall"
+)
 
 // HideWindow is a no-op on non-Windows platforms
 func HideWindow(cmd *exec.Cmd) {
 	// No-op on Linux/macOS
 }
+
+// killTreeOnCancel starts the command in its own process group and kills the
+// group on cancellation
+func killTreeOnCancel(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -4,6 +4,7 @@ package executil
 
 import (
 	"os/exec"
+	"strconv"
 	"syscall"
 )
 
@@ -14,3 +15,16 @@ func HideWindow(cmd *exec.Cmd) {
 		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
 	}
 }
+
+// killTreeOnCancel kills the process and its descendants with taskkill on
+// cancellation; Process.Kill alone would leave children running
+func killTreeOnCancel(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+		HideWindow(kill)
+		if err := kill.Run(); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+}
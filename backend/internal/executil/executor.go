@@ -0,0 +1,106 @@
+package executil
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// Executor runs external tools (compilers, linters, test runners) with the
+// resource limits and extra environment variables configured for the app.
+// The command runner and the build, static analysis and test runners share
+// one Executor, so a settings change reaches all of them. A nil Executor runs
+// commands without limits in the app's environment.
+type Executor struct {
+	mu     sync.RWMutex
+	limits Limits
+	env    map[string]string
+	check  func(name string, args []string) error
+	warn   func(error)
+}
+
+// NewExecutor creates an Executor; warn is called when limits can't be applied
+func NewExecutor(warn func(error)) *Executor {
+	return &Executor{warn: warn}
+}
+
+// SetLimits sets the memory and CPU limits of the commands started afterwards
+func (e *Executor) SetLimits(limits Limits) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.limits = limits
+}
+
+// SetEnv sets the variables added to the app's environment for the commands
+// created afterwards (see MergeEnv); nil removes them
+func (e *Executor) SetEnv(env map[string]string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.env = env
+}
+
+// SetCommandCheck sets a check Command runs on every command it creates; a
+// command the check rejects fails to start with its error. nil removes it
+func (e *Executor) SetCommandCheck(check func(name string, args []string) error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.check = check
+}
+
+// Environ returns the environment commands run with
+func (e *Executor) Environ() []string {
+	if e == nil {
+		return os.Environ()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return MergeEnv(os.Environ(), e.env)
+}
+
+// Limits returns the current limits
+func (e *Executor) Limits() Limits {
+	if e == nil {
+		return Limits{}
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.limits
+}
+
+// Command creates a command like CommandContext, with the environment of SetEnv.
+// A command rejected by the check of SetCommandCheck gets the rejection as
+// cmd.Err, so starting it fails
+func (e *Executor) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := CommandContext(ctx, name, args...)
+	if e == nil {
+		return cmd
+	}
+	e.mu.RLock()
+	if len(e.env) > 0 {
+		cmd.Env = MergeEnv(os.Environ(), e.env)
+	}
+	check := e.check
+	e.mu.RUnlock()
+	if check != nil {
+		if err := check(name, args); err != nil {
+			cmd.Err = err
+		}
+	}
+	return cmd
+}
+
+// Run runs cmd under the limits, capturing its output and streaming lines
+// to onLine (see RunStreaming)
+func (e *Executor) Run(cmd *exec.Cmd, onLine func(stream, line string)) (Output, error) {
+	if e == nil {
+		return RunStreaming(cmd, Limits{}, nil, onLine)
+	}
+	return RunStreaming(cmd, e.Limits(), e.warn, onLine)
+}
+
+// CombinedOutput is Run returning stdout and stderr interleaved
+func (e *Executor) CombinedOutput(cmd *exec.Cmd, onLine func(stream, line string)) ([]byte, error) {
+	output, err := e.Run(cmd, onLine)
+	return output.Combined, err
+}
@@ -0,0 +1,34 @@
+package executil
+
+import "errors"
+
+// ErrLimitsUnsupported is returned when resource limits can't be applied on
+// this platform; the command runs without them
+var ErrLimitsUnsupported = errors.New("resource limits are not supported on this platform")
+
+// ErrMemoryLimitExceeded is returned for a command killed because its
+// processes together used more memory than allowed
+var ErrMemoryLimitExceeded = errors.New("exceeded memory limit")
+
+// DefaultMaxOutputBytes caps the output the command runner keeps of a
+// command when the settings set no cap
+const DefaultMaxOutputBytes = 4 << 20
+
+// Limits caps the resources of each process a command starts and the output
+// captured from it. Zero fields set no limit.
+type Limits struct {
+	// MemoryBytes caps the resident memory of the command and all the
+	// processes it starts together; the whole tree is killed when it is exceeded
+	MemoryBytes uint64
+	// CPUSeconds caps the CPU time of each process the command starts; the
+	// kernel kills a process that exceeds it
+	CPUSeconds uint64
+	// MaxOutputBytes caps what is kept of stdout, stderr and the combined
+	// output each; the middle of longer output is dropped (see cappedBuffer)
+	MaxOutputBytes int
+}
+
+// HasProcessLimits reports whether a memory or CPU limit has to be applied to the process
+func (l Limits) HasProcessLimits() bool {
+	return l.MemoryBytes != 0 || l.CPUSeconds != 0
+}
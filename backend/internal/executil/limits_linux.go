@@ -0,0 +1,127 @@
+//go:build linux
+
+package executil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// cpuKillGrace is how long after SIGXCPU at the soft CPU limit the kernel
+// waits before SIGKILL at the hard limit
+const cpuKillGrace = 5
+
+// memoryPollInterval is how often the memory of a command's process tree is measured
+const memoryPollInterval = 100 * time.Millisecond
+
+// limitChild prepares cmd so the limits hold from its first instruction.
+// The CPU limit is set by sh with ulimit before it execs the command, so
+// every process the command starts inherits it. The memory limit is enforced
+// by watchMemory on the command's process group, which must be its own.
+func limitChild(cmd *exec.Cmd, limits Limits) error {
+	if limits.MemoryBytes > 0 && (cmd.SysProcAttr == nil || !cmd.SysProcAttr.Setpgid) {
+		return errors.New("failed to limit memory: the command has no process group of its own")
+	}
+	if limits.CPUSeconds == 0 || cmd.Err != nil {
+		return nil
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		return fmt.Errorf("failed to limit CPU time: %w", err)
+	}
+	soft, hard := limits.CPUSeconds, limits.CPUSeconds+cpuKillGrace
+	var current unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_CPU, &current); err == nil && current.Max != unix.RLIM_INFINITY {
+		// ulimit can't raise the hard limit of an unprivileged process
+		hard = min(hard, current.Max)
+		soft = min(soft, hard)
+	}
+	script := fmt.Sprintf(`ulimit -S -t %d && ulimit -H -t %d || exit 126; exec "$@"`, soft, hard)
+	cmd.Args = append([]string{"sh", "-c", script, "sh", cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = sh
+	return nil
+}
+
+// watchMemory kills the command's process group once the resident memory of
+// all its processes exceeds limit. Unlike RLIMIT_DATA, which caps each
+// process separately, this bounds the whole tree (e.g. a build running many
+// compilers at once). The returned stop function ends the watch and reports
+// whether the limit was exceeded.
+func watchMemory(cmd *exec.Cmd, limit uint64) func() bool {
+	pgid := cmd.Process.Pid
+	done := make(chan struct{})
+	var exceeded atomic.Bool
+	go func() {
+		ticker := time.NewTicker(memoryPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if processGroupRSS(pgid) > limit {
+					exceeded.Store(true)
+					_ = syscall.Kill(-pgid, syscall.SIGKILL)
+					return
+				}
+			}
+		}
+	}()
+	return func() bool {
+		close(done)
+		return exceeded.Load()
+	}
+}
+
+// processGroupRSS sums the resident memory of the processes in a process group
+func processGroupRSS(pgid int) uint64 {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0
+	}
+	pageSize := uint64(os.Getpagesize())
+	var total uint64
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		stat, err := os.ReadFile("/proc/" + entry.Name() + "/stat")
+		if err != nil {
+			continue
+		}
+		// The command name may contain spaces; the fields follow its closing paren
+		end := bytes.LastIndexByte(stat, ')')
+		if end < 0 {
+			continue
+		}
+		fields := bytes.Fields(stat[end+1:])
+		// fields[2] is the process group, fields[21] the resident set in pages
+		if len(fields) < 22 || string(fields[2]) != strconv.Itoa(pgid) {
+			continue
+		}
+		if pages, err := strconv.ParseUint(string(fields[21]), 10, 64); err == nil {
+			total += pages * pageSize
+		}
+	}
+	return total
+}
+
+// KilledByCPULimit reports whether a command failed because it exceeded its
+// CPU time limit
+func KilledByCPULimit(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == syscall.SIGXCPU
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package executil
+
+import "os/exec"
+
+// limitChild is not supported outside Linux
+func limitChild(_ *exec.Cmd, _ Limits) error {
+	return ErrLimitsUnsupported
+}
+
+// watchMemory is not supported outside Linux
+func watchMemory(_ *exec.Cmd, _ uint64) func() bool {
+	return func() bool { return false }
+}
+
+// KilledByCPULimit is always false where limits aren't supported
+func KilledByCPULimit(error) bool {
+	return false
+}
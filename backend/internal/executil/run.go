@@ -0,0 +1,150 @@
+package executil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+)
+
+// Output is what a command wrote to stdout and stderr, separately and
+// interleaved in the order it was written
+type Output struct {
+	Stdout   []byte
+	Stderr   []byte
+	Combined []byte
+}
+
+// Run runs cmd capturing its output. cmd.Stdout and cmd.Stderr must be unset.
+func Run(cmd *exec.Cmd) (Output, error) {
+	return RunLimited(cmd, Limits{}, nil)
+}
+
+// RunLimited is Run with limits applied to the command and the processes it
+// starts. Limits are best-effort: if they can't be applied the command still
+// runs and warn is called with the reason.
+func RunLimited(cmd *exec.Cmd, limits Limits, warn func(error)) (Output, error) {
+	return RunStreaming(cmd, limits, warn, nil)
+}
+
+// RunStreaming is RunLimited that also passes every line of stdout and stderr
+// to onLine as soon as it is written; a nil onLine disables streaming.
+// onLine may be called from the stdout and stderr goroutines at once
+func RunStreaming(cmd *exec.Cmd, limits Limits, warn func(error), onLine func(stream, line string)) (Output, error) {
+	stdout := &cappedBuffer{limit: limits.MaxOutputBytes}
+	stderr := &cappedBuffer{limit: limits.MaxOutputBytes}
+	combined := &lockedBuffer{buf: cappedBuffer{limit: limits.MaxOutputBytes}}
+	stdoutWriters := []io.Writer{stdout, combined}
+	stderrWriters := []io.Writer{stderr, combined}
+	var stdoutLines, stderrLines *lineWriter
+	if onLine != nil {
+		stdoutLines = &lineWriter{stream: "stdout", onLine: onLine}
+		stderrLines = &lineWriter{stream: "stderr", onLine: onLine}
+		stdoutWriters = append(stdoutWriters, stdoutLines)
+		stderrWriters = append(stderrWriters, stderrLines)
+	}
+	cmd.Stdout = io.MultiWriter(stdoutWriters...)
+	cmd.Stderr = io.MultiWriter(stderrWriters...)
+
+	watchMem := limits.MemoryBytes > 0
+	if limits.HasProcessLimits() {
+		if limitErr := limitChild(cmd, limits); limitErr != nil {
+			watchMem = false
+			if warn != nil {
+				warn(limitErr)
+			}
+		}
+	}
+	err := cmd.Start()
+	if err == nil {
+		stopWatch := func() bool { return false }
+		if watchMem {
+			stopWatch = watchMemory(cmd, limits.MemoryBytes)
+		}
+		err = cmd.Wait()
+		if stopWatch() {
+			err = fmt.Errorf("%w (%d MB): %v", ErrMemoryLimitExceeded, limits.MemoryBytes>>20, err)
+		}
+	}
+	if onLine != nil {
+		stdoutLines.flush()
+		stderrLines.flush()
+	}
+	return Output{Stdout: stdout.Bytes(), Stderr: stderr.Bytes(), Combined: combined.buf.Bytes()}, err
+}
+
+// CombinedOutput replaces cmd.CombinedOutput, streaming lines to onLine while
+// the command runs (see RunStreaming). The output is not capped: callers parse
+// it (e.g. ESLint JSON), and a truncated report can't be parsed
+func CombinedOutput(cmd *exec.Cmd, onLine func(stream, line string)) ([]byte, error) {
+	output, err := RunStreaming(cmd, Limits{}, nil, onLine)
+	return output.Combined, err
+}
+
+// ExitCode returns the exit code of a failed command, or -1 if it didn't
+// exit by itself (failed to start or was killed)
+func ExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// lockedBuffer is written by the stdout and stderr copying goroutines at once
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf cappedBuffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// maxLineBytes bounds a streamed line; longer lines (e.g. minified JSON on
+// one line) are passed on in pieces of this size
+const maxLineBytes = 64 << 10
+
+// lineWriter splits a stream into lines; the last unterminated line is passed on flush
+type lineWriter struct {
+	stream  string
+	onLine  func(stream, line string)
+	pending []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(bytes.TrimSuffix(w.pending[:i], []byte("\r")))
+		w.pending = w.pending[i+1:]
+	}
+	for len(w.pending) >= maxLineBytes {
+		w.onLine(w.stream, string(w.pending[:maxLineBytes]))
+		w.pending = w.pending[maxLineBytes:]
+	}
+	return len(p), nil
+}
+
+// emit passes on a complete line, split into pieces of maxLineBytes
+func (w *lineWriter) emit(line []byte) {
+	for len(line) > maxLineBytes {
+		w.onLine(w.stream, string(line[:maxLineBytes]))
+		line = line[maxLineBytes:]
+	}
+	w.onLine(w.stream, string(line))
+}
+
+func (w *lineWriter) flush() {
+	if len(w.pending) > 0 {
+		w.onLine(w.stream, string(w.pending))
+		w.pending = nil
+	}
+}
@@ -2,6 +2,8 @@ package initmanager
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 )
@@ -12,6 +14,8 @@ type LazyService[T any] struct {
 	service      T
 	initialized  bool
 	initFunc     func(context.Context) (T, error)
+	cleanupFunc  func(T) error // releases resources held by the service, optional
+	inUseFunc    func(T) bool  // reports work that must not be interrupted, optional
 	lastAccessed time.Time
 	accessCount  int64
 	initTime     time.Time
@@ -24,24 +28,27 @@ func NewLazyService[T any](initFunc func(context.Context) (T, error)) *LazyServi
 	}
 }
 
-// Get returns the service instance, initializing it if necessary
-func (ls *LazyService[T]) Get(ctx context.Context) (T, error) {
-	ls.mu.RLock()
-	if ls.initialized {
-		service := ls.service
-		ls.mu.RUnlock()
-
-		// Update access tracking
-		ls.mu.Lock()
-		ls.lastAccessed = time.Now()
-		ls.accessCount++
-		ls.mu.Unlock()
+// WithCleanup sets a function called with the service when it is unloaded
+func (ls *LazyService[T]) WithCleanup(cleanup func(T) error) *LazyService[T] {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.cleanupFunc = cleanup
+	return ls
+}
 
-		return service, nil
-	}
-	ls.mu.RUnlock()
+// WithInUse sets a function reporting whether the service is busy;
+// busy services are never unloaded for being idle
+func (ls *LazyService[T]) WithInUse(inUse func(T) bool) *LazyService[T] {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.inUseFunc = inUse
+	return ls
+}
 
-	// Double-checked locking pattern
+// Get returns the service instance, initializing it if necessary
+func (ls *LazyService[T]) Get(ctx context.Context) (T, error) {
+	// A single critical section: an idle unload cannot slip in between
+	// handing out the instance and recording the access
 	ls.mu.Lock()
 	defer ls.mu.Unlock()
 
@@ -80,15 +87,44 @@ func (ls *LazyService[T]) IsInitialized() bool {
 	return ls.initialized
 }
 
-// Reset resets the service to uninitialized state
+// Reset resets the service to uninitialized state, running the cleanup function
 func (ls *LazyService[T]) Reset() {
+	_ = ls.Unload()
+}
+
+// Unload resets the service to uninitialized state and returns the cleanup error.
+// The next Get initializes a new instance.
+func (ls *LazyService[T]) Unload() error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.unloadLocked()
+}
+
+// UnloadIfIdle unloads the service if it was not accessed within idleThreshold
+// and is not in use. Checking and unloading happen atomically, so a concurrent
+// Get either keeps the service alive or receives a fresh instance.
+func (ls *LazyService[T]) UnloadIfIdle(idleThreshold time.Duration) (bool, error) {
 	ls.mu.Lock()
 	defer ls.mu.Unlock()
+
+	if !ls.shouldUnloadLocked(idleThreshold) {
+		return false, nil
+	}
+	return true, ls.unloadLocked()
+}
+
+func (ls *LazyService[T]) unloadLocked() error {
+	var err error
+	if ls.initialized && ls.cleanupFunc != nil {
+		err = ls.cleanupFunc(ls.service)
+	}
+
 	var zero T
 	ls.service = zero
 	ls.initialized = false
 	ls.lastAccessed = time.Time{}
 	ls.accessCount = 0
+	return err
 }
 
 // GetStats returns statistics about the lazy service
@@ -114,10 +150,16 @@ func (ls *LazyService[T]) GetStats() map[string]interface{} {
 func (ls *LazyService[T]) ShouldUnload(idleThreshold time.Duration) bool {
 	ls.mu.RLock()
 	defer ls.mu.RUnlock()
+	return ls.shouldUnloadLocked(idleThreshold)
+}
 
+func (ls *LazyService[T]) shouldUnloadLocked(idleThreshold time.Duration) bool {
 	if !ls.initialized {
 		return false
 	}
+	if ls.inUseFunc != nil && ls.inUseFunc(ls.service) {
+		return false
+	}
 
 	return time.Since(ls.lastAccessed) > idleThreshold
 }
@@ -165,6 +207,21 @@ func (m *LazyServiceManager) UnloadUnusedServices(idleTime time.Duration) int {
 
 	unloaded := 0
 	for name, svc := range m.services {
+		// Prefer the atomic check-and-unload when the service supports it
+		if unloader, ok := svc.(interface {
+			UnloadIfIdle(time.Duration) (bool, error)
+		}); ok {
+			didUnload, err := unloader.UnloadIfIdle(idleTime)
+			if didUnload {
+				unloaded++
+				println("Unloaded idle service:", name)
+			}
+			if err != nil {
+				println("Error unloading service:", name, err.Error())
+			}
+			continue
+		}
+
 		// Try to unload if the service supports it
 		if unloader, ok := svc.(interface{ ShouldUnload(time.Duration) bool }); ok {
 			if unloader.ShouldUnload(idleTime) {
@@ -179,3 +236,21 @@ func (m *LazyServiceManager) UnloadUnusedServices(idleTime time.Duration) int {
 
 	return unloaded
 }
+
+// Shutdown unloads all services, including busy ones, and returns cleanup errors
+func (m *LazyServiceManager) Shutdown() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+	for name, svc := range m.services {
+		if unloader, ok := svc.(interface{ Unload() error }); ok {
+			if err := unloader.Unload(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		} else if resetter, ok := svc.(interface{ Reset() }); ok {
+			resetter.Reset()
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+package initmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type closableService struct {
+	id     int
+	closed bool
+}
+
+func newCountingService() (*LazyService[*closableService], *int) {
+	created := 0
+	ls := NewLazyService(func(context.Context) (*closableService, error) {
+		created++
+		return &closableService{id: created}, nil
+	}).WithCleanup(func(s *closableService) error {
+		s.closed = true
+		return nil
+	})
+	return ls, &created
+}
+
+func TestLazyService_UnloadIfIdleRunsCleanup(t *testing.T) {
+	ls, created := newCountingService()
+	first, err := ls.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	if unloaded, _ := ls.UnloadIfIdle(time.Hour); unloaded {
+		t.Fatal("recently used service should not be unloaded")
+	}
+	if unloaded, err := ls.UnloadIfIdle(0); !unloaded || err != nil {
+		t.Fatalf("UnloadIfIdle(0) = %v, %v", unloaded, err)
+	}
+	if !first.closed {
+		t.Error("cleanup should run on unload")
+	}
+
+	second, _ := ls.Get(context.Background())
+	if second == first || *created != 2 {
+		t.Errorf("expected a fresh instance after unload, created %d", *created)
+	}
+}
+
+func TestLazyService_InUseBlocksIdleUnload(t *testing.T) {
+	busy := true
+	ls, _ := newCountingService()
+	ls.WithInUse(func(*closableService) bool { return busy })
+	svc, _ := ls.Get(context.Background())
+
+	m := NewLazyServiceManager()
+	m.Register("svc", ls)
+	if n := m.UnloadUnusedServices(0); n != 0 || svc.closed {
+		t.Fatalf("busy service was unloaded (%d)", n)
+	}
+
+	busy = false
+	if n := m.UnloadUnusedServices(0); n != 1 || !svc.closed {
+		t.Fatalf("idle service was not unloaded (%d)", n)
+	}
+}
+
+func TestLazyServiceManager_ShutdownUnloadsBusyServices(t *testing.T) {
+	ls, _ := newCountingService()
+	ls.WithInUse(func(*closableService) bool { return true })
+	svc, _ := ls.Get(context.Background())
+
+	failing := NewLazyService(func(context.Context) (int, error) { return 1, nil }).
+		WithCleanup(func(int) error { return errors.New("close failed") })
+	_, _ = failing.Get(context.Background())
+
+	m := NewLazyServiceManager()
+	m.Register("svc", ls)
+	m.Register("failing", failing)
+
+	err := m.Shutdown()
+	if !svc.closed || ls.IsInitialized() {
+		t.Error("Shutdown should unload busy services")
+	}
+	if err == nil {
+		t.Error("Shutdown should report cleanup errors")
+	}
+}
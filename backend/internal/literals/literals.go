@@ -0,0 +1,150 @@
+// Package literals finds multi-line string literals in source code, so text
+// transformations such as whitespace trimming can leave their content intact.
+package literals
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// delimiter opens and closes a string literal that may span lines
+type delimiter struct {
+	quote string
+	raw   bool // backslash escapes nothing, as in Go raw strings
+}
+
+// syntax describes the comments and string literals of a language
+type syntax struct {
+	lineComment  string
+	blockComment bool   // /* */ comments
+	quotes       string // quote characters of single-line strings
+	multiline    []delimiter
+}
+
+var (
+	goSyntax = syntax{lineComment: "//", blockComment: true, quotes: `"'`,
+		multiline: []delimiter{{quote: "`", raw: true}}}
+	jsSyntax = syntax{lineComment: "//", blockComment: true, quotes: `"'`,
+		multiline: []delimiter{{quote: "`"}}}
+	pythonSyntax = syntax{lineComment: "#", quotes: `"'`,
+		multiline: []delimiter{{quote: `"""`}, {quote: "'''"}}}
+)
+
+// syntaxByExt maps file extensions to languages with multi-line strings
+var syntaxByExt = map[string]syntax{
+	".go":  goSyntax,
+	".js":  jsSyntax,
+	".jsx": jsSyntax,
+	".mjs": jsSyntax,
+	".cjs": jsSyntax,
+	".ts":  jsSyntax,
+	".tsx": jsSyntax,
+	".mts": jsSyntax,
+	".cts": jsSyntax,
+	".py":  pythonSyntax,
+	".pyi": pythonSyntax,
+}
+
+// ProtectedLines reports for each line of content whether the newline ending
+// it lies inside a multi-line string literal: Go raw strings, JS/TS template
+// literals or Python triple-quoted strings. Such lines, including blank ones,
+// must be kept byte for byte. It returns nil when filePath is not one of these
+// languages or content has no multi-line literal.
+func ProtectedLines(content, filePath string) []bool {
+	lang, ok := syntaxByExt[strings.ToLower(filepath.Ext(filePath))]
+	if !ok || !lang.hasMultiline(content) {
+		return nil
+	}
+
+	protected := make([]bool, strings.Count(content, "\n")+1)
+	line := 0
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; {
+		case c == '\n':
+			line++
+		case strings.HasPrefix(content[i:], lang.lineComment):
+			// Stop before the newline, the loop counts it
+			i = indexFrom(content, i, "\n") - 1
+		case lang.blockComment && strings.HasPrefix(content[i:], "/*"):
+			end := min(indexFrom(content, i+2, "*/")+len("*/"), len(content))
+			line += strings.Count(content[i:end], "\n")
+			i = end - 1
+		default:
+			if d, ok := lang.multilineAt(content, i); ok {
+				i, line = scanMultiline(content, i+len(d.quote), line, d, protected)
+			} else if strings.IndexByte(lang.quotes, c) >= 0 {
+				i = scanQuoted(content, i+1, c)
+			}
+		}
+	}
+	return protected
+}
+
+// hasMultiline reports whether content contains any multi-line delimiter
+func (s syntax) hasMultiline(content string) bool {
+	for _, d := range s.multiline {
+		if strings.Contains(content, d.quote) {
+			return true
+		}
+	}
+	return false
+}
+
+// multilineAt returns the multi-line delimiter starting at i
+func (s syntax) multilineAt(content string, i int) (delimiter, bool) {
+	for _, d := range s.multiline {
+		if strings.HasPrefix(content[i:], d.quote) {
+			return d, true
+		}
+	}
+	return delimiter{}, false
+}
+
+// indexFrom returns the index of the next token at or after i, or
+// len(content) when there is none
+func indexFrom(content string, i int, token string) int {
+	if idx := strings.Index(content[i:], token); idx >= 0 {
+		return i + idx
+	}
+	return len(content)
+}
+
+// scanMultiline marks the lines whose newline is inside the literal starting
+// at i and returns the index of its last byte and the current line
+func scanMultiline(content string, i, line int, d delimiter, protected []bool) (int, int) {
+	for ; i < len(content); i++ {
+		switch {
+		case content[i] == '\\' && !d.raw:
+			if i+1 < len(content) && content[i+1] == '\n' {
+				protected[line] = true
+				line++
+			}
+			i++
+		case content[i] == '\n':
+			protected[line] = true
+			line++
+		case strings.HasPrefix(content[i:], d.quote):
+			return i + len(d.quote) - 1, line
+		}
+	}
+	return len(content), line
+}
+
+// scanQuoted skips a single-line string starting at i and returns the index
+// of its closing quote; an unterminated string ends before the newline
+func scanQuoted(content string, i int, quote byte) int {
+	for ; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			if i+1 < len(content) && content[i+1] == '\n' {
+				return i // line continuation: leave the newline to the caller
+			}
+			i++
+		case quote:
+			return i
+		case '\n':
+			return i - 1
+		}
+	}
+	return len(content)
+}
@@ -0,0 +1,63 @@
+package literals
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProtectedLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     []bool
+	}{
+		{
+			name:     "go raw string",
+			filePath: "config.go",
+			content:  "var cfg = `\nkey:  \n\n  nested: 1\n`\n",
+			want:     []bool{true, true, true, true, false, false},
+		},
+		{
+			name:     "go backticks in comments and runes are ignored",
+			filePath: "main.go",
+			content:  "// use `x\nr := '`'\n/* ` */\ns := \"`\"\n",
+			want:     nil,
+		},
+		{
+			name:     "template literal with escaped backtick",
+			filePath: "query.ts",
+			content:  "const q = `a \\` b\n\nc`\nconst d = 1\n",
+			want:     []bool{true, true, false, false, false},
+		},
+		{
+			name:     "python triple quotes",
+			filePath: "doc.py",
+			content:  "# it's \"\"\"\nx = '''\n  a\n'''\ny = \"\"\"b\"\"\"\n",
+			want:     []bool{false, true, true, false, false, false},
+		},
+		{
+			name:     "unsupported language",
+			filePath: "notes.md",
+			content:  "```\ncode\n```\n",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ProtectedLines(tt.content, tt.filePath)
+			if tt.want == nil {
+				for i, p := range got {
+					if p {
+						t.Fatalf("line %d unexpectedly protected: %v", i, got)
+					}
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProtectedLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
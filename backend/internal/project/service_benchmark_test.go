@@ -160,6 +160,30 @@ func (m *mockGitRepository) GetFileAtRef(projectPath, filePath, ref string) (str
 	return "file content at ref", nil
 }
 
+func (m *mockGitRepository) CreateWorktree(projectPath, worktreePath, branch, baseRef string) error {
+	return nil
+}
+
+func (m *mockGitRepository) RemoveWorktree(projectPath, worktreePath string) error {
+	return nil
+}
+
+func (m *mockGitRepository) Commit(projectPath, message string, files []string) (string, error) {
+	return "abc123", nil
+}
+
+func (m *mockGitRepository) GenerateDiffAgainst(projectPath, baseRef string) (string, error) {
+	return "", nil
+}
+
+func (m *mockGitRepository) MergeBranch(projectPath, branch string) error {
+	return nil
+}
+
+func (m *mockGitRepository) DeleteBranch(projectPath, branch string) error {
+	return nil
+}
+
 // Mock ContextService for benchmarking
 type mockContextService struct {
 	delayMs int
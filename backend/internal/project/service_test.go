@@ -165,6 +165,36 @@ func (m *MockGitRepository) GetFileAtRef(projectPath, filePath, ref string) (str
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockGitRepository) CreateWorktree(projectPath, worktreePath, branch, baseRef string) error {
+	args := m.Called(projectPath, worktreePath, branch, baseRef)
+	return args.Error(0)
+}
+
+func (m *MockGitRepository) RemoveWorktree(projectPath, worktreePath string) error {
+	args := m.Called(projectPath, worktreePath)
+	return args.Error(0)
+}
+
+func (m *MockGitRepository) Commit(projectPath, message string, files []string) (string, error) {
+	args := m.Called(projectPath, message, files)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGitRepository) GenerateDiffAgainst(projectPath, baseRef string) (string, error) {
+	args := m.Called(projectPath, baseRef)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGitRepository) MergeBranch(projectPath, branch string) error {
+	args := m.Called(projectPath, branch)
+	return args.Error(0)
+}
+
+func (m *MockGitRepository) DeleteBranch(projectPath, branch string) error {
+	args := m.Called(projectPath, branch)
+	return args.Error(0)
+}
+
 // Mock ContextService for testing
 type MockContextService struct {
 	mock.Mock
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"shotgun_code/domain"
 )
@@ -53,9 +54,29 @@ func (a *App) ReadFileContent(rootDir, relPath string) (string, error) {
 	return a.projectHandler.ReadFileContent(a.ctx, rootDir, relPath)
 }
 
-// StartFileWatcher starts watching a directory for file changes
+// StartFileWatcher starts watching a directory for file changes.
+// The watched directory is the selected project, so taskflow paths follow it
 func (a *App) StartFileWatcher(rootDirPath string) error {
-	return a.projectHandler.StartFileWatcher(rootDirPath)
+	if err := a.projectHandler.StartFileWatcher(rootDirPath); err != nil {
+		return err
+	}
+	if err := a.taskflowService.SetProjectRoot(rootDirPath); err != nil {
+		a.log.Warning(fmt.Sprintf("Failed to load taskflow for %s: %v", rootDirPath, err))
+	}
+	a.applyProjectCommandEnv(rootDirPath)
+	return nil
+}
+
+// applyProjectCommandEnv passes the commandEnv of the project's .shotgun/config.json
+// to the commands the app spawns
+func (a *App) applyProjectCommandEnv(projectRoot string) {
+	loader, ok := a.container.CommandRunner.(domain.ProjectEnvLoader)
+	if !ok {
+		return
+	}
+	if err := loader.LoadProjectEnv(projectRoot); err != nil {
+		a.log.Warning(fmt.Sprintf("Failed to load project config for %s: %v", projectRoot, err))
+	}
 }
 
 // StopFileWatcher stops the file watcher
@@ -25,6 +25,12 @@ func (a *App) RefreshAIModels(provider, apiKey string) error {
 	return a.settingsHandler.RefreshAIModels(provider, apiKey)
 }
 
+// SetEmbeddingModel selects the embedding model used for semantic search.
+// Projects indexed with another model must be re-indexed before searching.
+func (a *App) SetEmbeddingModel(model string) error {
+	return a.settingsService.SetEmbeddingModel(model)
+}
+
 // GetRecentProjects returns the list of recently opened projects
 func (a *App) GetRecentProjects() (string, error) {
 	projects := a.settingsService.GetRecentProjects()
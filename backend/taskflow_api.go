@@ -27,7 +27,7 @@ func (a *App) UpdateTaskStatus(taskID string, state domain.TaskState, message st
 
 // ExecuteTask executes a task
 func (a *App) ExecuteTask(taskID string) error {
-	return a.taskflowService.ExecuteTask(taskID)
+	return a.taskflowService.ExecuteTask(a.ctx, taskID)
 }
 
 // ExecuteTaskflow executes the entire taskflow
@@ -50,6 +50,12 @@ func (a *App) ValidateTaskflow() error {
 	return a.taskflowService.ValidateTaskflow()
 }
 
+// ValidateTaskflowPlan validates plan.yaml before execution: duplicate IDs,
+// unknown dependencies, missing step files and negative budgets
+func (a *App) ValidateTaskflowPlan(planPath string) (*domain.PlanValidationResult, error) {
+	return a.taskflowService.ValidatePlan(planPath)
+}
+
 // GetTaskflowProgress returns execution progress
 func (a *App) GetTaskflowProgress() (float64, error) {
 	return a.taskflowService.GetTaskflowProgress()
@@ -158,18 +158,18 @@ type MockBuildService struct {
 	mock.Mock
 }
 
-func (m *MockBuildService) Build(ctx context.Context, projectPath, language string) (*domain.BuildResult, error) {
-	args := m.Called(ctx, projectPath, language)
+func (m *MockBuildService) Build(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, error) {
+	args := m.Called(ctx, projectPath, language, target)
 	return args.Get(0).(*domain.BuildResult), args.Error(1)
 }
 
-func (m *MockBuildService) TypeCheck(ctx context.Context, projectPath, language string) (*domain.TypeCheckResult, error) {
-	args := m.Called(ctx, projectPath, language)
+func (m *MockBuildService) TypeCheck(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.TypeCheckResult, error) {
+	args := m.Called(ctx, projectPath, language, target)
 	return args.Get(0).(*domain.TypeCheckResult), args.Error(1)
 }
 
-func (m *MockBuildService) BuildAndTypeCheck(ctx context.Context, projectPath, language string) (*domain.BuildResult, *domain.TypeCheckResult, error) {
-	args := m.Called(ctx, projectPath, language)
+func (m *MockBuildService) BuildAndTypeCheck(ctx context.Context, projectPath, language string, target domain.BuildTarget) (*domain.BuildResult, *domain.TypeCheckResult, error) {
+	args := m.Called(ctx, projectPath, language, target)
 	return args.Get(0).(*domain.BuildResult), args.Get(1).(*domain.TypeCheckResult), args.Error(2)
 }
 
@@ -301,8 +301,8 @@ func (m *MockTaskflowService) UpdateTaskStatus(taskID string, state domain.TaskS
 	return args.Error(0)
 }
 
-func (m *MockTaskflowService) ExecuteTask(taskID string) error {
-	args := m.Called(taskID)
+func (m *MockTaskflowService) ExecuteTask(ctx context.Context, taskID string) error {
+	args := m.Called(ctx, taskID)
 	return args.Error(0)
 }
 
@@ -326,6 +326,16 @@ func (m *MockTaskflowService) ValidateTaskflow() error {
 	return args.Error(0)
 }
 
+func (m *MockTaskflowService) SetProjectRoot(projectRoot string) error {
+	args := m.Called(projectRoot)
+	return args.Error(0)
+}
+
+func (m *MockTaskflowService) ValidatePlan(planPath string) (*domain.PlanValidationResult, error) {
+	args := m.Called(planPath)
+	return args.Get(0).(*domain.PlanValidationResult), args.Error(1)
+}
+
 func (m *MockTaskflowService) GetTaskflowProgress() (float64, error) {
 	args := m.Called()
 	return args.Get(0).(float64), args.Error(1)
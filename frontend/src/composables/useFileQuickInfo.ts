@@ -10,6 +10,9 @@ export interface FileQuickInfo {
     symbolCount: number
     importCount: number
     dependentCount: number
+    avgComplexity: number
+    maxComplexity: number
+    complexityEstimated: boolean
     changeRisk: number
     riskLevel: 'low' | 'medium' | 'high'
 }
@@ -53,6 +56,9 @@ export function useFileQuickInfo() {
                 symbolCount: result.symbolCount || 0,
                 importCount: result.importCount || 0,
                 dependentCount: result.dependentCount || 0,
+                avgComplexity: result.avgComplexity || 0,
+                maxComplexity: result.maxComplexity || 0,
+                complexityEstimated: result.complexityEstimated || false,
                 changeRisk: result.changeRisk || 0,
                 riskLevel: (result.riskLevel as 'low' | 'medium' | 'high') || 'low'
             }
@@ -46,6 +46,8 @@ export const useChatStore = defineStore('chat', () => {
     const chatHistory = ref<ChatHistory[]>([])
     const currentChatId = ref<string | null>(null)
     const streamingContent = ref<string>('')
+    // Backend ID of the running stream, used to cancel it
+    const activeRequestId = ref<string | null>(null)
 
     // Computed
     const hasMessages = computed(() => messages.value.length > 0)
@@ -130,11 +132,13 @@ export const useChatStore = defineStore('chat', () => {
             }
             if (chunk.done) {
                 isStreaming.value = false
+                activeRequestId.value = null
                 window.removeEventListener('ai:stream:chunk', handleChunk as EventListener)
                 saveChat()
             }
             if (chunk.error) {
                 isStreaming.value = false
+                activeRequestId.value = null
                 uiStore.addToast(chunk.error, 'error')
                 window.removeEventListener('ai:stream:chunk', handleChunk as EventListener)
             }
@@ -144,7 +148,11 @@ export const useChatStore = defineStore('chat', () => {
 
         try {
             const systemPrompt = 'You are a helpful coding assistant.'
-            apiService.generateCodeStream(systemPrompt, content)
+            const requestId = await apiService.generateCodeStream(systemPrompt, content)
+            // A short reply may have finished before the ID arrived
+            if (isStreaming.value) {
+                activeRequestId.value = requestId
+            }
         } catch (error) {
             console.error('[ChatStore] streamMessage error:', error)
             isStreaming.value = false
@@ -227,6 +235,12 @@ export const useChatStore = defineStore('chat', () => {
     }
 
     function stopStreaming(): void {
+        if (activeRequestId.value) {
+            apiService.cancelAIRequest(activeRequestId.value).catch(error => {
+                console.warn('[ChatStore] Failed to cancel stream:', error)
+            })
+            activeRequestId.value = null
+        }
         isStreaming.value = false
         streamingContent.value = ''
     }
@@ -38,6 +38,14 @@ export class ContextApi {
         }
     }
 
+    async estimateContextTokens(
+        projectPath: string,
+        files: string[],
+        options: domain.ContextBuildOptions
+    ): Promise<number> {
+        return apiService.estimateContextTokens(projectPath, files, options)
+    }
+
     async getContextContent(contextId: string): Promise<string> {
         try {
             return await apiService.getFullContextContent(contextId)
@@ -42,6 +42,7 @@ export interface ContextSummary {
         skippedFiles?: string[]
         skippedReasons?: Record<string, string>
         projectPath?: string
+        optimization?: domain.OptimizationStats
     }
 }
 
@@ -73,6 +74,8 @@ export const useContextStore = defineStore('context', () => {
     const tokenCount = computed(() => summary.value?.tokenCount || Math.round(lineCount.value * 2.5))
     const totalTokens = computed(() => tokenCount.value) // Alias for ContextIndicator
     const estimatedCost = computed(() => (tokenCount.value / 1000) * 0.002)
+    // Share of tokens saved by content optimizations in the last build, if any were enabled
+    const optimizationSavings = computed(() => summary.value?.metadata?.optimization?.savedPercent)
 
     // Stats object for components
     const stats = computed(() => ({
@@ -144,7 +147,8 @@ export const useContextStore = defineStore('context', () => {
                 tokenCount: result.tokenCount,
                 createdAt: new Date().toISOString(),
                 files: filePaths,
-                isFavorite: false
+                isFavorite: false,
+                metadata: { optimization: result.metadata?.optimization }
             }
 
             validateBuildResult(summary.value, result)
@@ -177,6 +181,20 @@ export const useContextStore = defineStore('context', () => {
         }
     }
 
+    // Estimates the token count of a build without building it
+    async function estimateTokens(filePaths: string[], options?: Partial<domain.ContextBuildOptions>): Promise<number> {
+        const projectStore = useProjectStore()
+        if (!projectStore.currentPath) {
+            throw new Error('No project selected')
+        }
+        return contextApi.estimateContextTokens(projectStore.currentPath, filePaths, createBuildOptions(options))
+    }
+
+    // The custom profile is sent as the individual toggles, not as a named profile
+    function profileForBuild(profile?: string): string | undefined {
+        return profile && profile !== 'custom' ? profile : undefined
+    }
+
     function createBuildOptions(options?: Partial<domain.ContextBuildOptions>): domain.ContextBuildOptions {
         const settingsStore = useSettingsStore()
         const contextSettings = settingsStore.settings.context
@@ -187,16 +205,20 @@ export const useContextStore = defineStore('context', () => {
             stripComments: options?.stripComments ?? contextSettings.stripComments,
             includeManifest: options?.includeManifest ?? true,
             includeLineNumbers: options?.includeLineNumbers ?? false,
+            includeTree: options?.includeTree ?? contextSettings.includeTree,
             includeTests: options?.includeTests ?? true,
             splitStrategy: options?.splitStrategy || 'smart',
             forceStream: true,
             enableProgressEvents: true,
             outputFormat: options?.outputFormat || contextSettings.outputFormat,
+            incremental: options?.incremental ?? false,
             excludeTests: options?.excludeTests ?? contextSettings.excludeTests,
             collapseEmptyLines: options?.collapseEmptyLines ?? contextSettings.collapseEmptyLines,
             stripLicense: options?.stripLicense ?? contextSettings.stripLicense,
             compactDataFiles: options?.compactDataFiles ?? contextSettings.compactDataFiles,
-            trimWhitespace: options?.trimWhitespace ?? contextSettings.trimWhitespace
+            trimWhitespace: options?.trimWhitespace ?? contextSettings.trimWhitespace,
+            skeletonMode: options?.skeletonMode ?? contextSettings.skeletonMode,
+            optimizationProfile: profileForBuild(options?.optimizationProfile ?? contextSettings.optimizationProfile)
         } as domain.ContextBuildOptions
     }
 
@@ -501,6 +523,8 @@ export const useContextStore = defineStore('context', () => {
             stripLicense: settingsStore.settings.context.stripLicense,
             compactDataFiles: settingsStore.settings.context.compactDataFiles,
             trimWhitespace: settingsStore.settings.context.trimWhitespace,
+            skeletonMode: settingsStore.settings.context.skeletonMode,
+            optimizationProfile: settingsStore.settings.context.optimizationProfile,
             maxTokens: settingsStore.settings.context.maxTokens,
         }
 
@@ -513,9 +537,9 @@ export const useContextStore = defineStore('context', () => {
         buildProgress, error, contextList, warnings, skippedFiles,
         selectedListItem,
         // Computed
-        hasContext, totalSize, fileCount, lineCount, tokenCount, totalTokens, estimatedCost, stats,
+        hasContext, totalSize, fileCount, lineCount, tokenCount, totalTokens, estimatedCost, stats, optimizationSavings,
         // Actions
-        buildContext, rebuildContext, loadContextContent, deleteContext, exportContext,
+        buildContext, estimateTokens, rebuildContext, loadContextContent, deleteContext, exportContext,
         listProjectContexts, clearContext, setRawContext, getFullContextContent,
         getMemoryUsage, renameContext, toggleFavorite, duplicateContext,
         autoCleanup, loadContextMetadata, saveContextMetadata, generateSmartName,
@@ -58,10 +58,14 @@ export const apiService = {
   getProjectContexts: contextApi.getProjectContexts,
   exportContext: contextApi.exportContext,
   getFullContextContent: contextApi.getFullContextContent,
+  exportContextBundle: contextApi.exportContextBundle,
+  estimateContextTokens: contextApi.estimateContextTokens,
+  getSkeletonSupport: contextApi.getSkeletonSupport,
   suggestContextFiles: contextApi.suggestContextFiles,
   getSmartSuggestions: contextApi.getSmartSuggestions,
   getFileQuickInfo: contextApi.getFileQuickInfo,
   getImpactPreview: contextApi.getImpactPreview,
+  getChangeBlastRadius: contextApi.getChangeBlastRadius,
   analyzeTaskAndCollectContext: contextApi.analyzeTaskAndCollectContext,
   agenticChat: contextApi.agenticChat,
 
@@ -73,6 +77,10 @@ export const apiService = {
   generateIntelligentCode: aiApi.generateIntelligentCode,
   listAvailableModels: aiApi.listAvailableModels,
   getProviderInfo: aiApi.getProviderInfo,
+  queryAIAuditLog: aiApi.queryAuditLog,
+  getAICacheStats: aiApi.getCacheStats,
+  cancelAIRequest: aiApi.cancelRequest,
+  listActiveAIRequests: aiApi.listActiveRequests,
   qwenExecuteTask: aiApi.qwenExecuteTask,
   qwenPreviewContext: aiApi.qwenPreviewContext,
   qwenGetAvailableModels: aiApi.qwenGetAvailableModels,
@@ -82,8 +90,11 @@ export const apiService = {
   // ============================================
   analyzeProject: analysisApi.analyzeProject,
   analyzeFile: analysisApi.analyzeFile,
+  exportAnalysisReport: analysisApi.exportAnalysisReport,
   detectLanguages: analysisApi.detectLanguages,
   getSupportedAnalyzers: analysisApi.getSupportedAnalyzers,
+  getRepairHistory: analysisApi.getRepairHistory,
+  testRepairRule: analysisApi.testRepairRule,
 
   // ============================================
   // Git Operations
@@ -131,6 +142,7 @@ export const apiService = {
   // ============================================
   getSettings: settingsApi.getSettings,
   saveSettings: settingsApi.saveSettings,
+  setEmbeddingModel: settingsApi.setEmbeddingModel,
   getGitignoreContent: settingsApi.getGitignoreContent,
   getCustomIgnoreRules: settingsApi.getCustomIgnoreRules,
   updateCustomIgnoreRules: settingsApi.updateCustomIgnoreRules,
@@ -142,9 +154,11 @@ export const apiService = {
   // Build and Test
   // ============================================
   runTests: buildApi.runTests,
+  onCommandOutput: buildApi.onCommandOutput,
   discoverTests: buildApi.discoverTests,
   build: buildApi.build,
   typeCheck: buildApi.typeCheck,
+  validateProjectSummary: buildApi.validateProjectSummary,
   generateDiff: buildApi.generateDiff,
   applyEdits: buildApi.applyEdits,
   applySingleEdit: buildApi.applySingleEdit,
@@ -162,6 +176,8 @@ export const apiService = {
   // ============================================
   executeTaskProtocol: taskflowApi.executeTaskProtocol,
   getTaskProtocolConfiguration: taskflowApi.getTaskProtocolConfiguration,
+  validateTaskflowPlan: taskflowApi.validateTaskflowPlan,
+  onTaskflowEvent: taskflowApi.onTaskflowEvent,
   validatePath: taskflowApi.validatePath,
   getGuardrailPolicies: taskflowApi.getGuardrailPolicies,
   getBudgetPolicies: taskflowApi.getBudgetPolicies,
@@ -172,10 +188,12 @@ export const apiService = {
   isSemanticSearchAvailable: semanticApi.isAvailable,
   semanticSearch: semanticApi.search,
   semanticFindSimilar: semanticApi.findSimilar,
+  semanticSearchRelated: semanticApi.searchRelated,
   semanticIndexProject: semanticApi.indexProject,
   semanticIndexFile: semanticApi.indexFile,
   semanticGetStats: semanticApi.getStats,
   semanticIsIndexed: semanticApi.isIndexed,
+  semanticGetBackgroundIndexStatus: semanticApi.getBackgroundIndexStatus,
   semanticRetrieveContext: semanticApi.retrieveContext,
   semanticHybridSearch: semanticApi.hybridSearch,
 
@@ -184,6 +202,8 @@ export const apiService = {
   // ============================================
   getRecentContexts: memoryApi.getRecentContexts,
   findContextByTopic: memoryApi.findContextByTopic,
+  findSimilarContexts: memoryApi.findSimilarContexts,
+  pruneContexts: memoryApi.pruneContexts,
   saveContextMemory: memoryApi.saveContextMemory,
 }
 
@@ -5,6 +5,11 @@
 
 import * as wails from '#wailsjs/go/main/App'
 import type {
+    AIAuditEntry,
+    AIAuditQuery,
+    AICacheStats,
+    AIRateLimitState,
+    AIRequestInfo,
     QwenContextPreview,
     QwenModelInfo,
     QwenTaskRequest,
@@ -16,13 +21,9 @@ export const aiApi = {
     generateCode: (context: string, task: string): Promise<string> =>
         apiCall(() => wails.GenerateCode(context, task), 'Failed to generate code.', { logContext: 'ai' }),
 
-    generateCodeStream: (context: string, task: string): void => {
-        try {
-            wails.GenerateCodeStream(context, task)
-        } catch (error) {
-            console.error('[API:ai] Error starting code stream:', error)
-        }
-    },
+    /** Starts streaming via "ai:stream:chunk" events; resolves to the request ID to cancel it with */
+    generateCodeStream: (context: string, task: string): Promise<string> =>
+        apiCall(() => wails.GenerateCodeStream(context, task), 'Failed to start code stream.', { logContext: 'ai' }),
 
     generateIntelligentCode: (context: string, task: string, options: string): Promise<string> =>
         apiCall(
@@ -37,6 +38,39 @@ export const aiApi = {
     getProviderInfo: (): Promise<string> =>
         apiCall(() => wails.GetProviderInfo(), 'Failed to get provider information.', { logContext: 'ai' }),
 
+    queryAuditLog: async (query: AIAuditQuery = {}): Promise<AIAuditEntry[]> => {
+        const result = await apiCall(
+            () => wails.QueryAIAuditLog(JSON.stringify(query)),
+            'Failed to query AI audit log.',
+            { logContext: 'ai' }
+        )
+        return parseJsonResponse(result, 'Failed to parse AI audit log.')
+    },
+
+    getCacheStats: (): Promise<AICacheStats> =>
+        apiCall(
+            () => wails.GetAICacheStats() as Promise<AICacheStats>,
+            'Failed to get AI cache statistics.',
+            { logContext: 'ai' }
+        ),
+
+    getRateLimits: (): Promise<Record<string, AIRateLimitState>> =>
+        apiCall(
+            () => wails.GetAIRateLimits() as Promise<Record<string, AIRateLimitState>>,
+            'Failed to get AI rate limits.',
+            { logContext: 'ai' }
+        ),
+
+    cancelRequest: (requestId: string): Promise<void> =>
+        apiCall(() => wails.CancelAIRequest(requestId), 'Failed to cancel AI request.', { logContext: 'ai' }),
+
+    listActiveRequests: (): Promise<AIRequestInfo[]> =>
+        apiCall(
+            () => wails.ListActiveAIRequests() as Promise<AIRequestInfo[]>,
+            'Failed to list active AI requests.',
+            { logContext: 'ai' }
+        ),
+
     // Qwen Task Execution
     qwenExecuteTask: async (request: QwenTaskRequest): Promise<QwenTaskResponse> => {
         const result = await apiCall(
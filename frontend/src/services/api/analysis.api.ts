@@ -11,6 +11,13 @@ export const analysisApi = {
     analyzeProject: (path: string, analyzers: string[]): Promise<domain.StaticAnalysisReport> =>
         apiCall(() => wails.AnalyzeProject(path, analyzers), 'Failed to analyze project.', { logContext: 'analysis' }),
 
+    exportAnalysisReport: (projectPath: string, languages: string[], format: 'pdf' | 'html'): Promise<string> =>
+        apiCall(
+            () => wails.ExportAnalysisReport(projectPath, languages, format),
+            'Failed to export analysis report.',
+            { logContext: 'analysis' }
+        ),
+
     analyzeFile: (projectPath: string, filePath: string): Promise<domain.StaticAnalysisResult> =>
         apiCall(
             () => wails.AnalyzeFile(projectPath, filePath),
@@ -31,4 +38,25 @@ export const analysisApi = {
             'Failed to get supported analyzers.',
             { logContext: 'analysis' }
         ),
+
+    previewRepair: (projectPath: string, errorOutput: string, language: string): Promise<domain.RepairResult> =>
+        apiCall(
+            () => wails.PreviewRepair(projectPath, errorOutput, language),
+            'Failed to preview repair.',
+            { logContext: 'analysis' }
+        ),
+
+    getRepairHistory: (projectPath: string): Promise<domain.RepairReport[]> =>
+        apiCall(
+            () => wails.GetRepairHistory(projectPath),
+            'Failed to load repair history.',
+            { logContext: 'analysis' }
+        ),
+
+    testRepairRule: (rule: domain.RepairRule, sampleError: string, sampleFiles: Record<string, string>): Promise<domain.RepairResult> =>
+        apiCall(
+            () => wails.TestRepairRule(rule, sampleError, sampleFiles),
+            'Failed to test repair rule.',
+            { logContext: 'analysis' }
+        ),
 }
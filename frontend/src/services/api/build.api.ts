@@ -5,9 +5,15 @@
 
 import * as wails from '#wailsjs/go/main/App'
 import type { domain } from '#wailsjs/go/models'
+import { EventsOn } from '#wailsjs/runtime/runtime'
+import type { CommandOutputLine, ProjectHealth } from '../types'
 import { apiCall } from './base'
 
 export const buildApi = {
+    // Live console: subscribes to command output lines while commands run; returns unsubscribe
+    onCommandOutput: (handler: (line: CommandOutputLine) => void): (() => void) =>
+        EventsOn('command:output', handler),
+
     // Testing
     runTests: (config: domain.TestConfig): Promise<domain.TestResult[]> =>
         apiCall(() => wails.RunTests(config), 'Failed to run tests.', { logContext: 'build' }),
@@ -20,11 +26,36 @@ export const buildApi = {
         ),
 
     // Build
-    build: (projectPath: string, language: string): Promise<domain.BuildResult> =>
-        apiCall(() => wails.Build(projectPath, language), 'Failed to build project.', { logContext: 'build' }),
+    // target selects Go build tags, GOOS/GOARCH and clean builds; defaults to a cached host build
+    build: (
+        projectPath: string,
+        language: string,
+        target: domain.BuildTarget = domain.BuildTarget.createFrom({})
+    ): Promise<domain.BuildResult> =>
+        apiCall(() => wails.Build(projectPath, language, target), 'Failed to build project.', { logContext: 'build' }),
+
+    typeCheck: (
+        projectPath: string,
+        language: string,
+        target: domain.BuildTarget = domain.BuildTarget.createFrom({})
+    ): Promise<domain.TypeCheckResult> =>
+        apiCall(() => wails.TypeCheck(projectPath, language, target), 'Failed to type check.', { logContext: 'build' }),
 
-    typeCheck: (projectPath: string, language: string): Promise<domain.TypeCheckResult> =>
-        apiCall(() => wails.TypeCheck(projectPath, language), 'Failed to type check.', { logContext: 'build' }),
+    // Project health: build, type check, tests and static analysis in one call
+    validateProjectSummary: (config: domain.ValidationSummaryConfig): Promise<domain.ValidationSummary> =>
+        apiCall(
+            () => wails.ValidateProjectSummary(config),
+            'Failed to validate project.',
+            { logContext: 'build' }
+        ),
+
+    // Single 0-100 health score with a letter grade and per-category breakdown
+    computeProjectHealth: (projectPath: string): Promise<ProjectHealth> =>
+        apiCall(
+            () => wails.ComputeProjectHealth(projectPath) as Promise<ProjectHealth>,
+            'Failed to compute project health.',
+            { logContext: 'build' }
+        ),
 
     // Diff and Apply
     generateDiff: (original: string, modified: string, format: string): Promise<domain.DiffResult> =>
@@ -7,6 +7,9 @@ import * as wails from '#wailsjs/go/main/App'
 import type { domain } from '#wailsjs/go/models'
 import type {
     AgenticChatResponse,
+    BlastRadius,
+    CycleBreakSuggestion,
+    FileDependencyRank,
     FileQuickInfo,
     ImpactPreviewResult,
     SmartSuggestionsResult,
@@ -78,6 +81,42 @@ export const contextApi = {
             { logContext: 'context' }
         ),
 
+    exportContextBundle: (contextId: string): Promise<string> =>
+        apiCall(
+            () => wails.ExportContextBundle(contextId),
+            'Failed to export context bundle.',
+            { logContext: 'context' }
+        ),
+
+    estimateOptimization: (
+        projectPath: string,
+        filePaths: string[],
+        options: Partial<domain.ContextBuildOptions>
+    ): Promise<domain.OptimizationStats> =>
+        apiCall(
+            () => wails.EstimateContextOptimization(projectPath, filePaths, JSON.stringify(options)),
+            'Failed to estimate optimization savings.',
+            { logContext: 'context' }
+        ),
+
+    estimateContextTokens: (
+        projectPath: string,
+        filePaths: string[],
+        options: Partial<domain.ContextBuildOptions>
+    ): Promise<number> =>
+        apiCall(
+            () => wails.EstimateContextTokens(projectPath, filePaths, JSON.stringify(options)),
+            'Failed to estimate context tokens.',
+            { logContext: 'context' }
+        ),
+
+    getSkeletonSupport: (filePaths: string[]): Promise<Record<string, boolean>> =>
+        apiCall(
+            () => wails.GetSkeletonSupport(filePaths),
+            'Failed to check skeleton support.',
+            { logContext: 'context' }
+        ),
+
     suggestContextFiles: (taskDescription: string, files: domain.FileNode[]): Promise<string[]> =>
         apiCall(
             () => wails.SuggestContextFiles(taskDescription, files),
@@ -116,26 +155,35 @@ export const contextApi = {
                 symbolCount: result.symbolCount,
                 importCount: result.importCount,
                 dependentCount: result.dependentCount,
+                avgComplexity: result.avgComplexity,
+                maxComplexity: result.maxComplexity,
+                complexityEstimated: result.complexityEstimated,
                 changeRisk: result.changeRisk,
                 riskLevel: result.riskLevel as 'high' | 'medium' | 'low',
             }
         } catch {
-            return { symbolCount: 0, importCount: 0, dependentCount: 0, changeRisk: 0, riskLevel: 'low' }
+            return {
+                symbolCount: 0, importCount: 0, dependentCount: 0,
+                avgComplexity: 0, maxComplexity: 0, complexityEstimated: false,
+                changeRisk: 0, riskLevel: 'low',
+            }
         }
     },
 
-    getImpactPreview: async (projectPath: string, filePaths: string[]): Promise<ImpactPreviewResult> => {
+    getImpactPreview: async (projectPath: string, filePaths: string[], maxDepth = 0): Promise<ImpactPreviewResult> => {
         try {
             // @ts-ignore - method may not exist in wails bindings yet
-            const result = await wails.GetImpactPreview(projectPath, filePaths)
+            const result = await wails.GetImpactPreview(projectPath, filePaths, maxDepth)
             return {
                 totalDependents: result.totalDependents,
                 aggregateRisk: result.aggregateRisk,
                 riskLevel: result.riskLevel as 'high' | 'medium' | 'low',
-                affectedFiles: (result.affectedFiles || []).map((f: { path: string; type: string; dependents: number }) => ({
+                affectedFiles: (result.affectedFiles || []).map((f: { path: string; type: string; depth: number; dependents: number; risk: number }) => ({
                     path: f.path,
                     type: f.type as 'direct' | 'transitive',
+                    depth: f.depth,
                     dependents: f.dependents,
+                    risk: f.risk,
                 })),
                 relatedTests: result.relatedTests || [],
             }
@@ -144,6 +192,34 @@ export const contextApi = {
         }
     },
 
+    getChangeBlastRadius: (projectPath: string, language: string, functionId: string, maxDepth = 0): Promise<BlastRadius> =>
+        apiCall(
+            () => wails.GetChangeBlastRadius(projectPath, language, functionId, maxDepth) as Promise<BlastRadius>,
+            'Failed to compute change blast radius.',
+            { logContext: 'context' }
+        ),
+
+    getMostDepended: (projectPath: string, limit = 0): Promise<FileDependencyRank[]> =>
+        apiCall(
+            () => wails.GetMostDepended(projectPath, limit) as Promise<FileDependencyRank[]>,
+            'Failed to rank most depended-on files.',
+            { logContext: 'context' }
+        ),
+
+    suggestCycleBreak: (projectPath: string, cycle: string[]): Promise<CycleBreakSuggestion> =>
+        apiCall(
+            () => wails.SuggestCycleBreak(projectPath, cycle) as Promise<CycleBreakSuggestion>,
+            'Failed to suggest how to break the dependency cycle.',
+            { logContext: 'context' }
+        ),
+
+    getDependencyMermaid: (projectPath: string, includeExternal = false, maxNodes = 0): Promise<string> =>
+        apiCall(
+            () => wails.GetDependencyMermaid(projectPath, includeExternal, maxNodes),
+            'Failed to export the dependency graph.',
+            { logContext: 'context' }
+        ),
+
     analyzeTaskAndCollectContext: (task: string, allFilesJson: string, rootDir: string): Promise<string> =>
         apiCall(
             () => wails.AnalyzeTaskAndCollectContext(task, allFilesJson, rootDir),
@@ -24,6 +24,22 @@ export const memoryApi = {
             'memory.findContextByTopic'
         ),
 
+    findSimilarContexts: (projectPath: string, query: string, limit = 10): Promise<ContextMemoryEntry[]> =>
+        apiCallWithDefault(
+            // @ts-ignore
+            () => wails.FindSimilarContexts(projectPath, query, limit),
+            [],
+            'memory.findSimilarContexts'
+        ),
+
+    pruneContexts: (projectPath: string): Promise<number> =>
+        apiCall(
+            // @ts-ignore
+            () => wails.PruneContexts(projectPath),
+            'Failed to clean up saved contexts.',
+            { logContext: 'memory' }
+        ),
+
     saveContextMemory: (
         projectPath: string,
         topic: string,
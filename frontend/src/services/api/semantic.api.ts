@@ -5,9 +5,11 @@
 
 import * as wails from '#wailsjs/go/main/App'
 import type {
+    BackgroundIndexStatus,
     CodeChunk,
     FindSimilarRequest,
     RetrieveContextRequest,
+    SearchRelatedRequest,
     SemanticIndexStats,
     SemanticSearchRequest,
     SemanticSearchResponse,
@@ -43,6 +45,16 @@ export const semanticApi = {
         return parseJsonResponse(result, 'Failed to parse similar code response.')
     },
 
+    searchRelated: async (request: SearchRelatedRequest): Promise<SemanticSearchResponse> => {
+        const result = await apiCall(
+            // @ts-ignore
+            () => wails.SemanticSearchRelated(JSON.stringify(request)),
+            'Failed to find related code.',
+            { logContext: 'semantic' }
+        )
+        return parseJsonResponse(result, 'Failed to parse related code response.')
+    },
+
     indexProject: (projectRoot: string): Promise<void> =>
         apiCall(
             // @ts-ignore
@@ -69,6 +81,14 @@ export const semanticApi = {
         return parseJsonResponse(result, 'Failed to parse semantic stats.')
     },
 
+    getBackgroundIndexStatus: (): Promise<BackgroundIndexStatus | null> =>
+        apiCallWithDefault(
+            // @ts-ignore - method may not exist in wails bindings yet
+            () => wails.GetBackgroundIndexStatus(),
+            null,
+            'semantic'
+        ),
+
     isIndexed: (projectRoot: string): Promise<boolean> =>
         apiCallWithDefault(
             // @ts-ignore
@@ -27,6 +27,10 @@ export const settingsApi = {
     saveSettings: (settings: string): Promise<void> =>
         apiCall(() => wails.SaveSettings(settings), 'Failed to save settings.', { logContext: 'settings' }),
 
+    // Projects indexed with another model must be re-indexed before searching
+    setEmbeddingModel: (model: string): Promise<void> =>
+        apiCall(() => wails.SetEmbeddingModel(model), 'Failed to set embedding model.', { logContext: 'settings' }),
+
     // Ignore Rules
     getGitignoreContent: (projectPath: string): Promise<string> =>
         apiCall(
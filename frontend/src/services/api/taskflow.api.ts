@@ -5,9 +5,17 @@
 
 import * as wails from '#wailsjs/go/main/App'
 import type { domain } from '#wailsjs/go/models'
+import { EventsOn } from '#wailsjs/runtime/runtime'
+import type { TaskflowEventName, TaskflowProgressEvent } from '../types'
 import { apiCall } from './base'
 
 export const taskflowApi = {
+    // Task status events replace polling; returns unsubscribe
+    onTaskflowEvent: (
+        event: TaskflowEventName,
+        handler: (data: TaskflowProgressEvent) => void
+    ): (() => void) => EventsOn(event, handler),
+
     // Task Protocol
     executeTaskProtocol: (configPath: string): Promise<string> =>
         apiCall(
@@ -23,6 +31,14 @@ export const taskflowApi = {
             { logContext: 'taskflow' }
         ),
 
+    // Plan validation: duplicate IDs, unknown dependencies, missing step files, negative budgets
+    validateTaskflowPlan: (planPath: string): Promise<domain.PlanValidationResult> =>
+        apiCall(
+            () => wails.ValidateTaskflowPlan(planPath),
+            'Failed to validate taskflow plan.',
+            { logContext: 'taskflow' }
+        ),
+
     // Guardrails
     validatePath: (path: string): Promise<domain.GuardrailViolation[]> =>
         apiCall(() => wails.ValidatePath(path), 'Failed to validate path.', { logContext: 'taskflow' }),
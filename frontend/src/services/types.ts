@@ -89,10 +89,40 @@ export interface FileQuickInfo {
     symbolCount: number
     importCount: number
     dependentCount: number
+    avgComplexity: number
+    maxComplexity: number
+    complexityEstimated: boolean
     changeRisk: number
     riskLevel: 'low' | 'medium' | 'high'
 }
 
+/** File (or Go package) ranked by its dependents, reported by GetMostDepended */
+export interface FileDependencyRank {
+    path: string
+    type: 'file' | 'package'
+    dependents: number
+    dependencies: number
+}
+
+/** Import edge of a dependency cycle with the symbols used across it */
+export interface CycleEdgeUsage {
+    from: string
+    to: string
+    importPath: string
+    line: number
+    symbols: string[] | null
+    usages: number
+}
+
+/** Cheapest edge to cut in a dependency cycle, reported by SuggestCycleBreak */
+export interface CycleBreakSuggestion {
+    cycle: string[]
+    edge: CycleEdgeUsage
+    edges: CycleEdgeUsage[]
+    newPackage: string
+    explanation: string
+}
+
 // ============================================
 // Impact Preview types
 // ============================================
@@ -108,7 +138,37 @@ export interface ImpactPreviewResult {
 export interface AffectedFile {
     path: string
     type: 'direct' | 'transitive'
+    depth: number
     dependents: number
+    risk: number
+}
+
+export interface CallGraphNode {
+    id: string
+    name: string
+    filePath: string
+    line: number
+    package?: string
+}
+
+/** Why a file is in a change's blast radius */
+export interface BlastRadiusReason {
+    kind: 'changed' | 'caller' | 'dependent' | 'test'
+    /** Function or file responsible */
+    detail: string
+}
+
+export interface BlastRadiusFile {
+    path: string
+    reasons: BlastRadiusReason[]
+}
+
+/** Callers, files and tests affected by changing a function, reported by GetChangeBlastRadius */
+export interface BlastRadius {
+    function: CallGraphNode
+    callers: CallGraphNode[]
+    files: BlastRadiusFile[]
+    tests: string[]
 }
 
 // ============================================
@@ -132,14 +192,30 @@ export interface SemanticSearchRequest {
     projectRoot: string
     topK?: number
     minScore?: number
-    searchType?: 'semantic' | 'keyword' | 'hybrid'
+    searchType?: 'semantic' | 'keyword' | 'hybrid' | 'auto'
     languages?: string[]
     chunkTypes?: string[]
+    snippets?: boolean
+    highlight?: boolean
+    /** Hybrid search blend, non-negative; defaults to 0.7 / 0.3 */
+    semanticWeight?: number
+    keywordWeight?: number
+    /** Number of results to skip for paging */
+    offset?: number
+    /** Minimum final (hybrid-weighted) score; minScore filters raw similarity */
+    scoreThreshold?: number
+    /** Re-score the top candidates with the LLM reranker */
+    rerank?: boolean
+    /** Fill each result's explanation with its score breakdown */
+    explain?: boolean
 }
 
 export interface SemanticSearchResponse {
     results: SemanticSearchResult[]
     totalResults: number
+    /** Matches before paging, to compute the number of pages */
+    totalAvailable: number
+    offset?: number
     queryTime: number
     searchType: string
 }
@@ -149,8 +225,29 @@ export interface SemanticSearchResult {
     score: number
     highlights?: string[]
     reason?: string
+    snippet?: string
+    snippetStartLine?: number
+    snippetEndLine?: number
+    /** Set on re-ranked results: score before re-ranking and the rerank score */
+    vectorScore?: number
+    rerankScore?: number
+    /** Set when the request asks to explain scores */
+    explanation?: ScoreBreakdown
+}
+
+/** score = semanticScore * semanticWeight + keywordScore * keywordWeight + sum of boosts */
+export interface ScoreBreakdown {
+    semanticScore: number
+    keywordScore: number
+    semanticWeight: number
+    keywordWeight: number
+    boosts?: Record<string, number>
 }
 
+// Markers wrapped around query terms in SemanticSearchResult.snippet
+export const HIGHLIGHT_START = '\u27e6'
+export const HIGHLIGHT_END = '\u27e7'
+
 export interface CodeChunk {
     id: string
     filePath: string
@@ -165,6 +262,13 @@ export interface CodeChunk {
     hash: string
 }
 
+export interface SearchRelatedRequest {
+    projectRoot: string
+    filePath: string
+    startLine: number
+    topK?: number
+}
+
 export interface FindSimilarRequest {
     filePath: string
     startLine: number
@@ -189,6 +293,17 @@ export interface SemanticIndexStats {
     dimensions: number
 }
 
+export interface BackgroundIndexStatus {
+    enabled: boolean
+    running: boolean
+    pending: number
+    filesIndexed: number
+    errors: number
+    /** Changes ignored while disabled; a full re-index picks them up */
+    skipped: number
+    lastRun: string
+}
+
 // ============================================
 // Qwen types
 // ============================================
@@ -205,6 +320,8 @@ export interface QwenTaskRequest {
 }
 
 export interface QwenTaskResponse {
+    /** ID to cancel the task with while it runs */
+    requestId?: string
     content: string
     model: string
     tokensUsed: number
@@ -253,3 +370,141 @@ export interface QwenModelInfo {
     maxContext: number
     recommended: boolean
 }
+
+// ============================================
+// AI audit types
+// ============================================
+
+export type AIAuditOutcome = 'success' | 'error' | 'cache_hit'
+
+export interface AIAuditEntry {
+    id: string
+    timestamp: string
+    requestId: string
+    provider: string
+    model: string
+    streaming?: boolean
+    promptHash: string
+    /** Only present when prompt content is included in the audit log */
+    systemPrompt?: string
+    userPrompt?: string
+    promptTokens: number
+    completionTokens: number
+    totalTokens: number
+    cost: number
+    currency?: string
+    latencyMs: number
+    outcome: AIAuditOutcome
+    error?: string
+}
+
+/** AI response cache statistics, reported by GetAICacheStats */
+export interface AICacheStats {
+    entries: number
+    maxEntries: number
+    ttlSeconds: number
+    hits: number
+    misses: number
+    evictions: number
+    /** 0.0 - 1.0 */
+    hitRate: number
+}
+
+/** Adaptive rate limit of an AI provider, reported by GetAIRateLimits */
+export interface AIRateLimitState {
+    /** Current token rate, tightened after 429 responses */
+    tokensPerSecond: number
+    maxTokensPerSecond: number
+    availableTokens: number
+    /** Set while the provider is paused for its Retry-After delay */
+    pausedUntil?: string
+}
+
+export type AIRequestState = 'running' | 'finished' | 'cancelled'
+
+/** In-flight AI request, reported by ListActiveAIRequests and "ai:request" events */
+export interface AIRequestInfo {
+    requestId: string
+    kind: string
+    state: AIRequestState
+    startedAt: string
+}
+
+export interface AIAuditQuery {
+    provider?: string
+    model?: string
+    outcome?: AIAuditOutcome
+    /** RFC3339 timestamps */
+    since?: string
+    until?: string
+    limit?: number
+}
+
+// ============================================
+// Command output streaming
+// ============================================
+
+/** One line of build/test/static command output, emitted as "command:output" */
+export interface CommandOutputLine {
+    /** Taskflow task that ran the command, if any */
+    taskId?: string
+    /** Pipeline step ID or UI operation (build, typecheck, test, static, validate) */
+    step?: string
+    stream: 'stdout' | 'stderr'
+    line: string
+}
+
+// ============================================
+// Project health
+// ============================================
+
+export type HealthCategory = 'static' | 'coverage' | 'vulnerabilities' | 'cycles' | 'build'
+
+/** Relative category weights; defaults 0.25/0.20/0.25/0.10/0.20 */
+export interface HealthWeights {
+    staticAnalysis: number
+    coverage: number
+    vulnerabilities: number
+    cycles: number
+    build: number
+}
+
+export interface HealthCategoryScore {
+    category: HealthCategory
+    /** 0-100 */
+    score: number
+    weight: number
+    /** Issue density, coverage %, vulnerability or cycle count, or share of languages built */
+    value: number
+    /** Not measured, left out of the overall score */
+    skipped?: boolean
+    message?: string
+}
+
+/** Project health score reported by ComputeProjectHealth */
+export interface ProjectHealth {
+    projectPath: string
+    /** 0-100 weighted average of the measured categories */
+    score: number
+    grade: 'A' | 'B' | 'C' | 'D' | 'F'
+    categories: HealthCategoryScore[]
+    weights: HealthWeights
+    computedAt: string
+}
+
+// ============================================
+// Taskflow events
+// ============================================
+
+export type TaskflowEventName = 'taskflow:progress' | 'taskflow:completed' | 'taskflow:failed'
+
+/** Task status change, emitted as "taskflow:progress" and, when the task ends, "taskflow:completed"/"taskflow:failed" */
+export interface TaskflowProgressEvent {
+    taskId: string
+    state: 'todo' | 'running' | 'done' | 'blocked' | 'failed'
+    /** 0.0 - 1.0 */
+    progress: number
+    message: string
+    error?: string
+    attempts?: number
+}
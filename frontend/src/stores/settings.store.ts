@@ -3,6 +3,9 @@ import { ref, watch } from 'vue'
 
 export type OutputFormat = 'markdown' | 'xml' | 'plain'
 
+// Named optimization presets; 'custom' uses the individual toggles
+export type OptimizationProfile = 'none' | 'balanced' | 'aggressive' | 'custom'
+
 export interface ContextSettings {
     maxTokens: number
     stripComments: boolean
@@ -10,14 +13,17 @@ export interface ContextSettings {
     splitStrategy: 'smart' | 'file' | 'token'
     outputFormat: OutputFormat
     // Content optimization options
+    optimizationProfile: OptimizationProfile
     excludeTests: boolean
     collapseEmptyLines: boolean
     stripLicense: boolean
     compactDataFiles: boolean
     trimWhitespace: boolean
+    skeletonMode: boolean
     // Export options (previously in useExport)
     includeManifest: boolean
     includeLineNumbers: boolean
+    includeTree: boolean
     enableAutoSplit: boolean
     maxTokensPerChunk: number
     // Template options
@@ -69,14 +75,17 @@ const DEFAULT_SETTINGS: AppSettings = {
         splitStrategy: 'smart',
         outputFormat: 'xml', // Default format - XML is best for AI context
         // Content optimization - disabled by default for safety
+        optimizationProfile: 'custom',
         excludeTests: false,
         collapseEmptyLines: false,
         stripLicense: false,
         compactDataFiles: false,
         trimWhitespace: false,
+        skeletonMode: false,
         // Export options
         includeManifest: true,
         includeLineNumbers: false,
+        includeTree: false,
         enableAutoSplit: false,
         maxTokensPerChunk: 32000,
         // Template options
@@ -140,6 +140,10 @@ export interface AutonomousTaskRequest {
     enableStaticAnalysis?: boolean;
     enableTests?: boolean;
     enableSBOM?: boolean;
+    useWorktree?: boolean;
+    mergeOnSuccess?: boolean;
+    commitOnSuccess?: boolean;
+    commitAll?: boolean;
   };
 }
 
@@ -156,6 +160,7 @@ export interface AutonomousTaskStatus {
   progress: number; // 0-100
   estimatedTimeRemaining?: number; // seconds
   error?: string;
+  commitHash?: string;
   startedAt: string;
   updatedAt: string;
 }
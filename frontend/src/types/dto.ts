@@ -58,6 +58,20 @@ export interface SettingsDTO {
   availableModels: Record<string, string[]>;
   useGitignore: boolean;
   useCustomIgnore: boolean;
+  preciseGoAnalysis?: boolean;
+  embeddingProvider?: string;
+  embeddingModel?: 'text-embedding-3-small' | 'text-embedding-3-large' | 'text-embedding-ada-002';
+  vectorStore?: string;
+  similarityMetric?: 'auto' | 'cosine' | 'dot' | 'euclidean';
+  backgroundIndexing?: boolean;
+  aiAuditIncludePrompts?: boolean;
+  safeMode?: boolean;
+  enabledLanguages?: string[];
+  aiProviderFallback?: string[];
+  optimizationProfile?: OptimizationProfileName;
+  customOptimization?: ContentOptimizeOptions;
+  commandLimits?: CommandLimits;
+  verificationGates?: Partial<Record<'lite' | 'standard' | 'strict', VerificationGate>>;
   autonomousMode?: boolean;
   // AI Provider specific settings
   openaiModel?: string;
@@ -82,6 +96,33 @@ export interface SettingsDTO {
   validateSyntax?: boolean;
 }
 
+// Default optimization profile of context builds; empty uses the individual options
+export type OptimizationProfileName = '' | 'none' | 'balanced' | 'aggressive' | 'custom';
+
+// Options of the "custom" optimization profile
+export interface ContentOptimizeOptions {
+  collapseEmptyLines: boolean;
+  stripLicense: boolean;
+  stripComments: boolean;
+  compactDataFiles: boolean;
+  skeletonMode: boolean;
+  trimWhitespace: boolean;
+}
+
+// Caps for external tools (linters, compilers, test runners); 0 or unset means default / no limit
+export interface CommandLimits {
+  timeoutSeconds?: number;
+  memoryMB?: number;
+  cpuSeconds?: number;
+  maxOutputKB?: number;
+}
+
+// Checks an autonomous task must pass before it completes, overriding the SLA policy defaults
+export interface VerificationGate {
+  categories: string[];
+  minCoverage?: number; // percent; 0 or unset means not checked
+}
+
 export interface Hunk {
   header: string;
   lines: string[];
@@ -129,6 +170,10 @@ export interface AutonomousTaskRequest {
     enableStaticAnalysis?: boolean;
     enableTests?: boolean;
     enableSBOM?: boolean;
+    useWorktree?: boolean;
+    mergeOnSuccess?: boolean;
+    commitOnSuccess?: boolean;
+    commitAll?: boolean;
   };
 }
 
@@ -145,6 +190,7 @@ export interface AutonomousTaskStatus {
   progress: number; // 0-100
   estimatedTimeRemaining?: number; // seconds
   error?: string;
+  commitHash?: string;
   startedAt: string;
   updatedAt: string;
 }
@@ -198,9 +198,11 @@ export namespace domain {
 	    output: string;
 	    error?: string;
 	    duration: number;
+	    cacheHit: boolean;
 	    artifacts?: string[];
 	    warnings?: string[];
 	    metadata?: Record<string, any>;
+	    modules?: Record<string, BuildResult>;
 	
 	    static createFrom(source: any = {}) {
 	        return new BuildResult(source);
@@ -214,9 +216,47 @@ export namespace domain {
 	        this.output = source["output"];
 	        this.error = source["error"];
 	        this.duration = source["duration"];
+	        this.cacheHit = source["cacheHit"];
 	        this.artifacts = source["artifacts"];
 	        this.warnings = source["warnings"];
 	        this.metadata = source["metadata"];
+	        this.modules = this.convertValues(source["modules"], BuildResult, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BuildTarget {
+	    tags?: string[];
+	    goos?: string;
+	    goarch?: string;
+	    clean?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BuildTarget(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tags = source["tags"];
+	        this.goos = source["goos"];
+	        this.goarch = source["goarch"];
+	        this.clean = source["clean"];
 	    }
 	}
 	export class BuildSystemInfo {
@@ -683,6 +723,7 @@ export namespace domain {
 	    stripComments: boolean;
 	    includeManifest: boolean;
 	    includeLineNumbers: boolean;
+	    includeTree: boolean;
 	    maxTokens: number;
 	    maxMemoryMB: number;
 	    includeTests: boolean;
@@ -690,12 +731,15 @@ export namespace domain {
 	    forceStream: boolean;
 	    enableProgressEvents: boolean;
 	    outputFormat: string;
+	    incremental: boolean;
 	    excludeTests: boolean;
 	    collapseEmptyLines: boolean;
 	    stripLicense: boolean;
 	    compactDataFiles: boolean;
 	    skeletonMode: boolean;
 	    trimWhitespace: boolean;
+	    optimizationProfile?: string;
+	    skeletonThresholdKB: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new ContextBuildOptions(source);
@@ -706,6 +750,7 @@ export namespace domain {
 	        this.stripComments = source["stripComments"];
 	        this.includeManifest = source["includeManifest"];
 	        this.includeLineNumbers = source["includeLineNumbers"];
+	        this.includeTree = source["includeTree"];
 	        this.maxTokens = source["maxTokens"];
 	        this.maxMemoryMB = source["maxMemoryMB"];
 	        this.includeTests = source["includeTests"];
@@ -713,12 +758,15 @@ export namespace domain {
 	        this.forceStream = source["forceStream"];
 	        this.enableProgressEvents = source["enableProgressEvents"];
 	        this.outputFormat = source["outputFormat"];
+	        this.incremental = source["incremental"];
 	        this.excludeTests = source["excludeTests"];
 	        this.collapseEmptyLines = source["collapseEmptyLines"];
 	        this.stripLicense = source["stripLicense"];
 	        this.compactDataFiles = source["compactDataFiles"];
 	        this.skeletonMode = source["skeletonMode"];
 	        this.trimWhitespace = source["trimWhitespace"];
+	        this.optimizationProfile = source["optimizationProfile"];
+	        this.skeletonThresholdKB = source["skeletonThresholdKB"];
 	    }
 	}
 	export class ContextMetadata {
@@ -735,6 +783,7 @@ export namespace domain {
 	    projectPath?: string;
 	    chunksCount?: number;
 	    splitStrategy?: string;
+	    optimization?: OptimizationStats;
 	
 	    static createFrom(source: any = {}) {
 	        return new ContextMetadata(source);
@@ -754,6 +803,7 @@ export namespace domain {
 	        this.projectPath = source["projectPath"];
 	        this.chunksCount = source["chunksCount"];
 	        this.splitStrategy = source["splitStrategy"];
+	        this.optimization = this.convertValues(source["optimization"], OptimizationStats);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1380,6 +1430,24 @@ export namespace domain {
 		    return a;
 		}
 	}
+	export class FileOptimizationStats {
+	    filePath: string;
+	    originalTokens: number;
+	    optimizedTokens: number;
+	    savedPercent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileOptimizationStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.originalTokens = source["originalTokens"];
+	        this.optimizedTokens = source["optimizedTokens"];
+	        this.savedPercent = source["savedPercent"];
+	    }
+	}
 	export class FileReason {
 	    FilePath: string;
 	    Reason: string;
@@ -1625,6 +1693,7 @@ export namespace domain {
 	    duration: number;
 	    issues?: TypeIssue[];
 	    metadata?: Record<string, any>;
+	    modules?: Record<string, TypeCheckResult>;
 	
 	    static createFrom(source: any = {}) {
 	        return new TypeCheckResult(source);
@@ -1640,6 +1709,7 @@ export namespace domain {
 	        this.duration = source["duration"];
 	        this.issues = this.convertValues(source["issues"], TypeIssue);
 	        this.metadata = source["metadata"];
+	        this.modules = this.convertValues(source["modules"], TypeCheckResult, true);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1705,6 +1775,42 @@ export namespace domain {
 	
 	
 	
+	export class OptimizationStats {
+	    originalTokens: number;
+	    optimizedTokens: number;
+	    savedPercent: number;
+	    files?: FileOptimizationStats[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OptimizationStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.originalTokens = source["originalTokens"];
+	        this.optimizedTokens = source["optimizedTokens"];
+	        this.savedPercent = source["savedPercent"];
+	        this.files = this.convertValues(source["files"], FileOptimizationStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class PerformanceMetrics {
 	    TaskID: string;
 	    MemoryUsage: number;
@@ -1737,6 +1843,64 @@ export namespace domain {
 	        this.Values = source["Values"];
 	    }
 	}
+	export class PlanProblem {
+	    code: string;
+	    taskId?: string;
+	    field?: string;
+	    message: string;
+	    line?: number;
+	    context?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlanProblem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.taskId = source["taskId"];
+	        this.field = source["field"];
+	        this.message = source["message"];
+	        this.line = source["line"];
+	        this.context = source["context"];
+	    }
+	}
+	export class PlanValidationResult {
+	    planPath: string;
+	    valid: boolean;
+	    taskCount: number;
+	    problems: PlanProblem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PlanValidationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.planPath = source["planPath"];
+	        this.valid = source["valid"];
+	        this.taskCount = source["taskCount"];
+	        this.problems = this.convertValues(source["problems"], PlanProblem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class ProjectStructure {
 	    architecture?: ArchitectureInfo;
 	    conventions?: ConventionInfo;
@@ -1833,6 +1997,111 @@ export namespace domain {
 	        this.lastOpenedAt = source["lastOpenedAt"];
 	    }
 	}
+	export class RepairCorrection {
+	    ruleId: string;
+	    ruleName: string;
+	    category: string;
+	    files: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RepairCorrection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ruleId = source["ruleId"];
+	        this.ruleName = source["ruleName"];
+	        this.category = source["category"];
+	        this.files = source["files"];
+	    }
+	}
+	export class RepairAttemptReport {
+	    attempt: number;
+	    errorType: string;
+	    corrections: RepairCorrection[];
+	    buildBefore: string;
+	    buildAfter: string;
+	    errorsAfter?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepairAttemptReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.attempt = source["attempt"];
+	        this.errorType = source["errorType"];
+	        this.corrections = this.convertValues(source["corrections"], RepairCorrection);
+	        this.buildBefore = source["buildBefore"];
+	        this.buildAfter = source["buildAfter"];
+	        this.errorsAfter = source["errorsAfter"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepairReport {
+	    id: string;
+	    projectPath: string;
+	    language: string;
+	    // Go type: time
+	    startedAt: any;
+	    durationMs: number;
+	    success: boolean;
+	    error?: string;
+	    initialErrors: string;
+	    attempts: RepairAttemptReport[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RepairReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.projectPath = source["projectPath"];
+	        this.language = source["language"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.durationMs = source["durationMs"];
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.initialErrors = source["initialErrors"];
+	        this.attempts = this.convertValues(source["attempts"], RepairAttemptReport);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class RepairResult {
 	    Success: boolean;
 	    RuleID: string;
@@ -1840,6 +2109,8 @@ export namespace domain {
 	    Error: string;
 	    Duration: number;
 	    Attempts: number;
+	    DryRun: boolean;
+	    Diff: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new RepairResult(source);
@@ -1853,6 +2124,8 @@ export namespace domain {
 	        this.Error = source["Error"];
 	        this.Duration = source["Duration"];
 	        this.Attempts = source["Attempts"];
+	        this.DryRun = source["DryRun"];
+	        this.Diff = source["Diff"];
 	    }
 	}
 	export class RepairRule {
@@ -2344,6 +2617,7 @@ export namespace domain {
 	    Progress: number;
 	    Message: string;
 	    Error: string;
+	    Attempts: number;
 	    // Go type: time
 	    StartedAt?: any;
 	    // Go type: time
@@ -2363,6 +2637,7 @@ export namespace domain {
 	        this.Progress = source["Progress"];
 	        this.Message = source["Message"];
 	        this.Error = source["Error"];
+	        this.Attempts = source["Attempts"];
 	        this.StartedAt = this.convertValues(source["StartedAt"], null);
 	        this.CompletedAt = this.convertValues(source["CompletedAt"], null);
 	        this.UpdatedAt = this.convertValues(source["UpdatedAt"], null);
@@ -2671,6 +2946,86 @@ export namespace domain {
 	
 	
 	
+	export class ValidationCategoryResult {
+	    category: string;
+	    status: string;
+	    message?: string;
+	    duration: number;
+	    details?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidationCategoryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.category = source["category"];
+	        this.status = source["status"];
+	        this.message = source["message"];
+	        this.duration = source["duration"];
+	        this.details = source["details"];
+	    }
+	}
+	export class ValidationSummary {
+	    projectPath: string;
+	    languages: string[];
+	    success: boolean;
+	    status: string;
+	    categories: ValidationCategoryResult[];
+	    startedAt: string;
+	    completedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidationSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.projectPath = source["projectPath"];
+	        this.languages = source["languages"];
+	        this.success = source["success"];
+	        this.status = source["status"];
+	        this.categories = this.convertValues(source["categories"], ValidationCategoryResult);
+	        this.startedAt = source["startedAt"];
+	        this.completedAt = source["completedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ValidationSummaryConfig {
+	    projectPath: string;
+	    languages?: string[];
+	    categories?: string[];
+	    changedFiles?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidationSummaryConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.projectPath = source["projectPath"];
+	        this.languages = source["languages"];
+	        this.categories = source["categories"];
+	        this.changedFiles = source["changedFiles"];
+	    }
+	}
 	export class WhyViewReport {
 	    TaskID: string;
 	    Files: FileReason[];